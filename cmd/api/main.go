@@ -9,11 +9,14 @@ import (
 	"syscall"
 
 	"github.com/garnizeh/englog/internal/auth"
+	"github.com/garnizeh/englog/internal/blobstore"
 	"github.com/garnizeh/englog/internal/config"
 	"github.com/garnizeh/englog/internal/database"
 	"github.com/garnizeh/englog/internal/grpc"
 	"github.com/garnizeh/englog/internal/handlers"
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/mailer"
+	"github.com/garnizeh/englog/internal/reports"
 	"github.com/garnizeh/englog/internal/services"
 	"github.com/gin-gonic/gin"
 
@@ -120,17 +123,106 @@ func run() error {
 	}()
 
 	// Initialize all other services with logger
-	projectService := services.NewProjectService(db, logger)
-	logEntryService := services.NewLogEntryService(db, logger)
-	analyticsService := services.NewAnalyticsService(db, logger)
+	webhookService := services.NewWebhookService(db, logger).
+		WithWebhookSettings(cfg.Webhooks.PollInterval, cfg.Webhooks.Timeout, cfg.Webhooks.DeliveryRetention).
+		WithPrivateNetworks(cfg.Webhooks.AllowPrivateNetworks)
+	projectService := services.NewProjectService(db, logger).WithWebhooks(webhookService)
+	logEntryService := services.NewLogEntryService(db, logger).
+		WithTrashRetention(cfg.Logs.TrashRetentionDays).
+		WithWebhooks(webhookService)
+	logTemplateService := services.NewLogTemplateService(db, logger, logEntryService)
+	analyticsService := services.NewAnalyticsService(db, logger).WithSummaryRefresh(cfg.Analytics.SummaryRefreshInterval, cfg.Analytics.SummaryRefreshDebounce)
+	logEntryService.WithWriteNotifier(analyticsService.NotifyLogEntryWrite)
+	goalService := services.NewGoalService(db, logger, analyticsService)
+	teamService := services.NewTeamService(db, logger, projectService)
 	tagService := services.NewTagService(db, logger)
 	userService := services.NewUserService(db, logger)
+	exportService := services.NewExportService(db, logger)
+
+	blobs, err := blobstore.New(blobstore.Config{
+		Backend:     cfg.Storage.Backend,
+		LocalPath:   cfg.Storage.LocalPath,
+		S3Endpoint:  cfg.Storage.S3Endpoint,
+		S3Region:    cfg.Storage.S3Region,
+		S3Bucket:    cfg.Storage.S3Bucket,
+		S3AccessKey: cfg.Storage.S3AccessKey,
+		S3SecretKey: cfg.Storage.S3SecretKey,
+	})
+	if err != nil {
+		logger.LogError(ctx, err, "Failed to initialize blob storage",
+			logging.OperationField, "blob_storage_initialization")
+		return fmt.Errorf("blob storage initialization failed: %w", err)
+	}
+	attachmentService := services.NewAttachmentService(db, logger, blobs,
+		cfg.Storage.AttachmentMaxBytes, cfg.Storage.AttachmentQuotaBytes)
 
 	logger.LogInfo(ctx, "All services initialized successfully",
 		logging.OperationField, "services_initialization")
 
+	// Start recurring log template materializer
+	go func() {
+		logger.WithComponent("log_templates").LogInfo(ctx, "Starting log template materializer",
+			logging.OperationField, "start_template_materializer")
+		logTemplateService.StartMaterializer(cleanupCtx)
+	}()
+
+	// Start log entry trash purger
+	go func() {
+		logger.WithComponent("log_entries").LogInfo(ctx, "Starting log entry trash purger",
+			logging.OperationField, "start_trash_purger")
+		logEntryService.StartTrashPurger(cleanupCtx)
+	}()
+
+	// Start orphaned attachment sweeper
+	go func() {
+		logger.WithComponent("attachments").LogInfo(ctx, "Starting attachment orphan sweeper",
+			logging.OperationField, "start_attachment_sweeper")
+		attachmentService.StartOrphanSweeper(cleanupCtx)
+	}()
+
+	// Start activity summary refresher
+	go func() {
+		logger.WithComponent("analytics").LogInfo(ctx, "Starting activity summary refresher",
+			logging.OperationField, "start_summary_refresher")
+		analyticsService.StartSummaryRefresher(cleanupCtx)
+	}()
+
+	// Start webhook delivery processor
+	go func() {
+		logger.WithComponent("webhooks").LogInfo(ctx, "Starting webhook delivery processor",
+			logging.OperationField, "start_webhook_processor")
+		webhookService.StartDeliveryProcessor(cleanupCtx)
+	}()
+
 	// Initialize gRPC server for worker communication
 	grpcManager := grpc.NewManager(cfg, logger)
+	grpcManager.OnTaskResult(webhookService.PublishTaskOutcome)
+
+	// Email notifications are sent when an SMTP server is configured; weekly
+	// digests include the AI report of the week generated on workers
+	var sender mailer.Sender
+	if cfg.Notifications.SMTPHost != "" {
+		smtpSender, err := mailer.NewSMTP(mailer.Config{
+			Host:      cfg.Notifications.SMTPHost,
+			Port:      cfg.Notifications.SMTPPort,
+			Username:  cfg.Notifications.SMTPUsername,
+			Password:  cfg.Notifications.SMTPPassword,
+			FromName:  cfg.Notifications.FromName,
+			FromEmail: cfg.Notifications.FromEmail,
+			Timeout:   cfg.Notifications.SMTPTimeout,
+		})
+		if err != nil {
+			logger.LogError(ctx, err, "Failed to configure SMTP",
+				logging.OperationField, "services_initialization",
+				"smtp_host", cfg.Notifications.SMTPHost)
+			return fmt.Errorf("SMTP initialization failed: %w", err)
+		}
+		sender = smtpSender
+	}
+	notificationService := services.NewNotificationService(db, logger, sender, grpcManager).
+		WithNotificationSettings(cfg.Notifications.PollInterval, cfg.Notifications.ReportWait, cfg.Notifications.Retention)
+	grpcManager.OnTaskResult(notificationService.PublishTaskOutcome)
+
 	if err := grpcManager.Start(ctx); err != nil {
 		logger.LogError(ctx, err, "Failed to start gRPC server",
 			logging.OperationField, "grpc_startup")
@@ -147,6 +239,40 @@ func run() error {
 		logging.OperationField, "grpc_startup",
 		"port", cfg.GRPC.ServerPort)
 
+	// Entry suggestions run on workers when one is connected
+	suggestionService := services.NewSuggestionService(logger, tagService, projectService, grpcManager)
+
+	// Report files are rendered on workers and stored next to attachments
+	reportService := services.NewReportService(db, logger, analyticsService, blobs, grpcManager).
+		WithReportSettings(cfg.Reports.PollInterval, cfg.Reports.DownloadTTL).
+		WithWebhooks(webhookService)
+	bragDocTemplates, err := reports.LoadBragDocTemplates(cfg.Reports.TemplateDir)
+	if err != nil {
+		logger.LogError(ctx, err, "Failed to load brag document templates",
+			logging.OperationField, "services_initialization",
+			"template_dir", cfg.Reports.TemplateDir)
+		return fmt.Errorf("brag document templates initialization failed: %w", err)
+	}
+	bragDocService := services.NewBragDocService(db, logger, bragDocTemplates, grpcManager).
+		WithEntryURL(cfg.Reports.EntryURL)
+	accountService := services.NewAccountService(db, logger, userService, projectService, tagService, logEntryService)
+	gitImportService := services.NewGitImportService(db, logger)
+	calendarImportService := services.NewCalendarImportService(db, logger, logEntryService)
+	csvImportService := services.NewCSVImportService(db, logger, logEntryService)
+	calendarFeedService := services.NewCalendarFeedService(db, logger)
+	go func() {
+		logger.WithComponent("reports").LogInfo(ctx, "Starting report processor",
+			logging.OperationField, "start_report_processor")
+		reportService.StartReportProcessor(cleanupCtx)
+	}()
+
+	// Start email notification processor
+	go func() {
+		logger.WithComponent("notifications").LogInfo(ctx, "Starting notification processor",
+			logging.OperationField, "start_notification_processor")
+		notificationService.StartNotificationProcessor(cleanupCtx)
+	}()
+
 	// Create Gin router with structured logging
 	router := handlers.SetupRoutes(
 		cfg,
@@ -154,10 +280,25 @@ func run() error {
 		redisClient,
 		authService,
 		logEntryService,
+		logTemplateService,
+		attachmentService,
+		suggestionService,
 		projectService,
 		analyticsService,
+		goalService,
+		teamService,
 		tagService,
 		userService,
+		exportService,
+		reportService,
+		bragDocService,
+		accountService,
+		gitImportService,
+		calendarImportService,
+		csvImportService,
+		calendarFeedService,
+		webhookService,
+		notificationService,
 		grpcManager,
 	)
 
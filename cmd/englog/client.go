@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultAPIURL is the API address used when ENGLOG_API_URL is not set
+const defaultAPIURL = "http://localhost:8080"
+
+// client calls the EngLog API on behalf of the user who owns the token
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// newClient creates a client from ENGLOG_API_URL and ENGLOG_TOKEN
+func newClient() (*client, error) {
+	token := os.Getenv("ENGLOG_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("ENGLOG_TOKEN is not set: log in and export your access token")
+	}
+	baseURL := os.Getenv("ENGLOG_API_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// apiResponse is the envelope of API responses
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Message string          `json:"message"`
+}
+
+// formFile is a file field of a multipart request
+type formFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// postMultipart sends fields and file as a multipart form and decodes the data of
+// the response into out
+func (c *client) postMultipart(ctx context.Context, path string, fields map[string]string, file formFile, out any) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile(file.field, file.filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(file.content); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	return c.do(ctx, path, form.FormDataContentType(), &body, out)
+}
+
+// postJSON sends in as JSON and decodes the whole response body into out
+func (c *client) postJSON(ctx context.Context, path string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, path, "application/json", bytes.NewReader(body), out)
+}
+
+// do sends a POST request. Responses in the API envelope are unwrapped into out;
+// other JSON responses are decoded as they are.
+func (c *client) do(ctx context.Context, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the response of %s: %w", path, err)
+	}
+
+	var envelope apiResponse
+	_ = json.Unmarshal(data, &envelope)
+	if resp.StatusCode >= http.StatusBadRequest {
+		message := envelope.Error
+		if envelope.Message != "" {
+			message += ": " + envelope.Message
+		}
+		if message == "" {
+			message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("%s: %s (%s)", path, message, resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if envelope.Success && envelope.Data != nil {
+		data = envelope.Data
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode the response of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/garnizeh/englog/internal/gitlog"
+	"github.com/garnizeh/englog/internal/models"
+)
+
+// maxBulkEntries is the most entries POST /v1/logs/bulk accepts at once
+const maxBulkEntries = 100
+
+// bulkCreateRequest is the body of POST /v1/logs/bulk
+type bulkCreateRequest struct {
+	Entries []models.LogEntryRequest `json:"entries"`
+}
+
+// bulkCreateResponse is the response of POST /v1/logs/bulk
+type bulkCreateResponse struct {
+	Data    []json.RawMessage `json:"data"`
+	Summary struct {
+		Total   int `json:"total"`
+		Success int `json:"success"`
+		Errors  int `json:"errors"`
+	} `json:"summary"`
+}
+
+// importGit reads the history of a local repository, asks the API for the log
+// entries it suggests, shows them for review and creates the approved ones
+func importGit(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import-git", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: englog import-git [flags]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Groups your commits into work sessions, proposes one development log entry per")
+		fmt.Fprintln(flags.Output(), "session and creates the ones you approve. Sessions that are already logged are left out.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	repo := flags.String("repo", ".", "path of the git repository")
+	name := flags.String("name", "", "repository name, matched against your project names (default: the repository directory name)")
+	author := flags.String("author", "", "author email of the commits to import (default: git config user.email)")
+	start := flags.String("start", "", "first day to import, YYYY-MM-DD in your profile timezone")
+	end := flags.String("end", "", "last day to import, YYYY-MM-DD in your profile timezone")
+	gap := flags.Int("gap", 0, "longest pause in minutes between commits of one session (default 120)")
+	projectID := flags.String("project-id", "", "project of the entries, instead of the project named like the repository")
+	allBranches := flags.Bool("all", false, "read the commits of every branch, not only the checked out one")
+	output := flags.String("output", "", "write the proposed entries as a POST /v1/logs/bulk request body to this file instead of creating them")
+	yes := flags.Bool("yes", false, "create every proposed entry without asking")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if *name == "" {
+		if *name, err = gitlog.RepositoryName(ctx, *repo); err != nil {
+			return err
+		}
+	}
+	if *author == "" {
+		if *author, err = gitlog.UserEmail(ctx, *repo); err != nil || *author == "" {
+			return fmt.Errorf("no author email: pass -author or set git config user.email")
+		}
+	}
+
+	// git reads the dates in the local timezone; a day of margin on each side lets
+	// the API apply the exact period in the profile timezone
+	readOptions := gitlog.ReadOptions{Author: *author, All: *allBranches}
+	if *start != "" {
+		day, err := time.Parse(time.DateOnly, *start)
+		if err != nil {
+			return fmt.Errorf("invalid -start date, expected YYYY-MM-DD")
+		}
+		readOptions.Since = day.AddDate(0, 0, -1)
+	}
+	if *end != "" {
+		day, err := time.Parse(time.DateOnly, *end)
+		if err != nil {
+			return fmt.Errorf("invalid -end date, expected YYYY-MM-DD")
+		}
+		readOptions.Until = day.AddDate(0, 0, 2)
+	}
+	log, err := gitlog.Read(ctx, *repo, readOptions)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"repository":   *name,
+		"author_email": *author,
+		"start":        *start,
+		"end":          *end,
+		"project_id":   *projectID,
+	}
+	if *gap != 0 {
+		fields["gap_minutes"] = strconv.Itoa(*gap)
+	}
+	var proposal models.GitImportProposal
+	if err := api.postMultipart(ctx, "/v1/logs/import/git", fields, formFile{field: "log", filename: *name + ".log", content: log}, &proposal); err != nil {
+		return err
+	}
+
+	printProposal(os.Stdout, &proposal)
+
+	var candidates []int
+	for i, session := range proposal.Sessions {
+		if !session.Duplicate {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to import.")
+		return nil
+	}
+
+	selected := candidates
+	if !*yes {
+		selected, err = askSelection(os.Stdin, os.Stdout, candidates, *output != "")
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			fmt.Println("Nothing imported.")
+			return nil
+		}
+	}
+
+	entries := make([]models.LogEntryRequest, len(selected))
+	for i, index := range selected {
+		entries[i] = proposal.Sessions[index].Entry
+	}
+
+	if *output != "" {
+		data, err := json.MarshalIndent(bulkCreateRequest{Entries: entries}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*output, append(data, '\n'), 0o600); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d entries to %s; edit them and POST the file to /v1/logs/bulk.\n", len(entries), *output)
+		return nil
+	}
+
+	created := 0
+	for batchStart := 0; batchStart < len(entries); batchStart += maxBulkEntries {
+		batch := entries[batchStart:min(batchStart+maxBulkEntries, len(entries))]
+		var result bulkCreateResponse
+		if err := api.postJSON(ctx, "/v1/logs/bulk", bulkCreateRequest{Entries: batch}, &result); err != nil {
+			return fmt.Errorf("created %d of %d entries: %w", created, len(entries), err)
+		}
+		created += result.Summary.Success
+		if result.Summary.Errors > 0 {
+			for _, item := range result.Data {
+				var failure struct {
+					Error string `json:"error"`
+					Index int    `json:"index"`
+				}
+				if json.Unmarshal(item, &failure) == nil && failure.Error != "" {
+					fmt.Printf("⚠️  %s: %s\n", batch[failure.Index].Title, failure.Error)
+				}
+			}
+		}
+	}
+	fmt.Printf("✅ Created %d of %d log entries.\n", created, len(entries))
+	return nil
+}
+
+// printProposal lists the proposed sessions, numbered from 1
+func printProposal(w io.Writer, proposal *models.GitImportProposal) {
+	project := "no matching project"
+	if proposal.ProjectName != nil {
+		project = "project " + *proposal.ProjectName
+	}
+	fmt.Fprintf(w, "%s: %d commits in %d sessions (%s); %d commits skipped\n\n",
+		proposal.Repository, proposal.Commits, len(proposal.Sessions), project, proposal.SkippedCommits)
+	if len(proposal.Sessions) == 0 {
+		return
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "#\tDAY\tTIME\tHOURS\tCOMMITS\tTITLE")
+	for i, session := range proposal.Sessions {
+		entry := session.Entry
+		title := entry.Title
+		if session.Duplicate {
+			title += "  (already logged)"
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s-%s\t%.1f\t%d\t%s\n", i+1,
+			entry.StartTime.Local().Format(time.DateOnly),
+			entry.StartTime.Local().Format("15:04"), entry.EndTime.Local().Format("15:04"),
+			entry.EndTime.Sub(entry.StartTime).Hours(), len(session.Commits), title)
+	}
+	table.Flush()
+	fmt.Fprintln(w)
+}
+
+// askSelection asks which of the candidate sessions to keep. The answer is y for
+// all of them, or the numbers to leave out such as 2,5-7; anything else cancels.
+func askSelection(in io.Reader, out io.Writer, candidates []int, toFile bool) ([]int, error) {
+	action := "Create"
+	if toFile {
+		action = "Write"
+	}
+	fmt.Fprintf(out, "%s %d entries? Enter y for all, the numbers to leave out (e.g. 2,5-7), or nothing to cancel: ", action, len(candidates))
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	answer = strings.TrimSpace(answer)
+	switch strings.ToLower(answer) {
+	case "":
+		return nil, nil
+	case "y", "yes":
+		return candidates, nil
+	}
+
+	excluded, err := parseNumberList(answer)
+	if err != nil {
+		return nil, err
+	}
+	var selected []int
+	for _, index := range candidates {
+		if !excluded[index+1] {
+			selected = append(selected, index)
+		}
+	}
+	return selected, nil
+}
+
+// parseNumberList parses a list of numbers and ranges such as 2,5-7
+func parseNumberList(list string) (map[int]bool, error) {
+	numbers := map[int]bool{}
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry number %q", part)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(strings.TrimSpace(last)); err != nil || to < from {
+				return nil, fmt.Errorf("invalid entry range %q", part)
+			}
+		}
+		for n := from; n <= to; n++ {
+			numbers[n] = true
+		}
+	}
+	return numbers, nil
+}
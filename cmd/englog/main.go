@@ -0,0 +1,63 @@
+// Command englog is the command line client of the EngLog API. It reads the API
+// address from ENGLOG_API_URL (http://localhost:8080 by default) and the access
+// token from ENGLOG_TOKEN.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var (
+	// Version will be set during build
+	Version = "dev"
+)
+
+const usage = `Usage: englog <command> [flags]
+
+Commands:
+  import-git       Propose log entries from the commits of a local git repository
+  smtp-sink        Accept and print email notifications locally
+  webhook-listen   Receive, verify and print webhook deliveries locally
+  version          Print the version
+
+Run "englog <command> -h" for the flags of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("a command is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "import-git":
+		return importGit(ctx, args[1:])
+	case "smtp-sink":
+		return smtpSink(ctx, args[1:])
+	case "webhook-listen":
+		return webhookListen(ctx, args[1:])
+	case "version":
+		fmt.Println(Version)
+		return nil
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
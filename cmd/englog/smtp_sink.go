@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garnizeh/englog/internal/mailer"
+)
+
+// smtpSink runs a local SMTP server that accepts every email and prints it, to
+// try email notifications without a real SMTP server
+func smtpSink(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("smtp-sink", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: englog smtp-sink [flags]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Accepts every email sent to it and prints it. Point the API at it with")
+		fmt.Fprintln(flags.Output(), "SMTP_HOST=localhost and SMTP_PORT=1025. It has no TLS, so never expose it.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", "127.0.0.1:1025", "address to listen on")
+	html := flags.Bool("html", false, "print the HTML part of emails instead of the text part")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	sink, err := mailer.NewSink(*addr, printEmail(os.Stdout, *html))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📬 Accepting emails on smtp://%s (Ctrl+C to stop)\n", sink.Addr())
+	<-ctx.Done()
+	return sink.Close()
+}
+
+// printEmail returns a callback printing the emails a sink receives
+func printEmail(out io.Writer, html bool) func(mailer.SinkMessage) {
+	var mu sync.Mutex
+	return func(msg mailer.SinkMessage) {
+		body, err := msg.Text()
+		if html {
+			body, err = msg.HTML()
+		}
+		if err != nil {
+			body = string(msg.Data)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(out, "%s ✉ %s → %s\n", msg.ReceivedAt.Format(time.TimeOnly), msg.From, strings.Join(msg.To, ", "))
+		fmt.Fprintf(out, "  Subject: %s\n\n", msg.Subject())
+		for _, line := range strings.Split(strings.TrimRight(body, "\r\n"), "\n") {
+			fmt.Fprintf(out, "  %s\n", strings.TrimRight(line, "\r"))
+		}
+		fmt.Fprintln(out)
+	}
+}
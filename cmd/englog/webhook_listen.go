@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/garnizeh/englog/internal/webhook"
+)
+
+// maxWebhookBodyBytes bounds the deliveries webhook-listen accepts
+const maxWebhookBodyBytes = 1 << 20
+
+// webhookListen runs a local HTTP receiver that verifies the signature of webhook
+// deliveries and prints them, to test webhooks before pointing them at real automations
+func webhookListen(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("webhook-listen", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: englog webhook-listen [flags]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Receives webhook deliveries, checks their signature with the webhook secret and prints")
+		fmt.Fprintln(flags.Output(), "them. Deliveries with a missing or wrong signature are answered with 401.")
+		fmt.Fprintln(flags.Output(), "The API must run with WEBHOOK_ALLOW_PRIVATE_NETWORKS=true to deliver to it.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", "127.0.0.1:8787", "address to listen on")
+	secret := flags.String("secret", os.Getenv("ENGLOG_WEBHOOK_SECRET"), "signing secret of the webhook (default $ENGLOG_WEBHOOK_SECRET)")
+	failStatus := flags.Int("fail-status", 0, "answer every valid delivery with this status instead of 200, to watch retries")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	if *secret == "" {
+		return fmt.Errorf("no webhook secret: pass -secret or set ENGLOG_WEBHOOK_SECRET")
+	}
+	if *failStatus != 0 && (*failStatus < 100 || *failStatus > 599) {
+		return fmt.Errorf("invalid -fail-status: %d", *failStatus)
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           webhookReceiver(*secret, *failStatus, os.Stdout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("👂 Listening for webhook deliveries on http://%s/ (Ctrl+C to stop)\n", listener.Addr())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// webhookReceiver verifies and prints the deliveries it receives
+func webhookReceiver(secret string, failStatus int, out io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		event := r.Header.Get(webhook.EventHeader)
+		delivery := r.Header.Get(webhook.DeliveryHeader)
+		received := time.Now().Format(time.TimeOnly)
+
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, 0, time.Now()); err != nil {
+			fmt.Fprintf(out, "%s ✗ %s delivery %s rejected: %v\n", received, event, delivery, err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Fprintf(out, "%s ✓ %s delivery %s\n", received, event, delivery)
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "  ", "  ") == nil {
+			fmt.Fprintf(out, "  %s\n", pretty.String())
+		} else {
+			fmt.Fprintf(out, "  %s\n", body)
+		}
+
+		if failStatus != 0 {
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -69,6 +69,7 @@ func (r *InsightRequest) Prompt(ctx context.Context, logger *logging.Logger) str
 		promptBuilder.WriteString("\n- Focus on efficiency patterns, time utilization, and value delivery")
 		promptBuilder.WriteString("\n- Identify high-impact activities and optimization opportunities")
 		promptBuilder.WriteString("\n- Analyze work-life balance and sustainable productivity patterns")
+		promptBuilder.WriteString("\n- Use the focus metrics in the context, if any, to assess context switching and deep work")
 	case "skill_development":
 		promptBuilder.WriteString("\n- Identify learning opportunities and skill gaps")
 		promptBuilder.WriteString("\n- Track progress in technical and soft skills")
@@ -81,6 +82,10 @@ func (r *InsightRequest) Prompt(ctx context.Context, logger *logging.Logger) str
 		promptBuilder.WriteString("\n- Focus on collaboration patterns and team interactions")
 		promptBuilder.WriteString("\n- Identify communication effectiveness and team dynamics")
 		promptBuilder.WriteString("\n- Suggest improvements for team productivity")
+	case "goal_progress":
+		promptBuilder.WriteString("\n- Review each goal in the context against its target and the elapsed part of its period")
+		promptBuilder.WriteString("\n- Call out goals that are behind or missed and what the log entries say about why")
+		promptBuilder.WriteString("\n- Suggest concrete adjustments to reach the remaining targets")
 	default:
 		promptBuilder.WriteString("\n- Provide comprehensive analysis based on the available data")
 		promptBuilder.WriteString("\n- Focus on actionable insights and practical recommendations")
@@ -345,6 +350,8 @@ func (s *OllamaService) validateContextForInsightType(context any, insightType s
 			return s.validateSkillDevelopmentContext(contextData)
 		case "time_management":
 			return s.validateTimeManagementContext(contextData)
+		case "goal_progress":
+			return s.validateGoalProgressContext(contextData)
 		default:
 			// For unknown insight types, accept any valid JSON structure
 			return nil
@@ -412,6 +419,22 @@ func (s *OllamaService) validateTimeManagementContext(context map[string]any) er
 	return nil
 }
 
+// validateGoalProgressContext validates goal progress context
+func (s *OllamaService) validateGoalProgressContext(context map[string]any) error {
+	if goals, exists := context["goals"]; exists {
+		items, ok := goals.([]any)
+		if !ok {
+			return fmt.Errorf("goals must be an array")
+		}
+		for _, item := range items {
+			if _, ok := item.(map[string]any); !ok {
+				return fmt.Errorf("goals must contain objects")
+			}
+		}
+	}
+	return nil
+}
+
 // GenerateWeeklyReport generates a weekly report using langchaingo
 func (s *OllamaService) GenerateWeeklyReport(ctx context.Context, userID string, weekStart, weekEnd time.Time) (*WeeklyReport, error) {
 	if userID == "" {
@@ -198,6 +198,29 @@ func TestValidateInsightRequest(t *testing.T) {
 			expectError: true,
 			errorMsg:    "entry_ids cannot be empty",
 		},
+		{
+			name: "Valid goal progress context",
+			request: &InsightRequest{
+				UserID:      "user123",
+				EntryIDs:    []string{"entry1"},
+				InsightType: "goal_progress",
+				Context: map[string]any{
+					"goals": []any{map[string]any{"title": "8h/week of learning", "state": "behind"}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Invalid goal progress context",
+			request: &InsightRequest{
+				UserID:      "user123",
+				EntryIDs:    []string{"entry1"},
+				InsightType: "goal_progress",
+				Context:     map[string]any{"goals": "learning"},
+			},
+			expectError: true,
+			errorMsg:    "goals must be an array",
+		},
 	}
 
 	for _, tt := range tests {
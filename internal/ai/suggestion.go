@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// entrySuggestionTimeout bounds a single suggestion call; the API falls back to heuristics anyway
+const entrySuggestionTimeout = 20 * time.Second
+
+// EntrySuggestionRequest represents a request for log entry metadata suggestions
+type EntrySuggestionRequest struct {
+	UserID       string              `json:"user_id"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description,omitempty"`
+	Tags         []string            `json:"tags"` // The user's tag vocabulary, most used first
+	Projects     []SuggestionProject `json:"projects"`
+	Types        []string            `json:"types"`
+	ValueRatings []string            `json:"value_ratings"`
+	ImpactLevels []string            `json:"impact_levels"`
+}
+
+// SuggestionProject is a project the suggestion may point to
+type SuggestionProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// EntrySuggestion represents suggested metadata for a log entry
+type EntrySuggestion struct {
+	Tags        []string `json:"tags"`
+	Type        string   `json:"type"`
+	ProjectID   string   `json:"project_id,omitempty"`
+	ValueRating string   `json:"value_rating"`
+	ImpactLevel string   `json:"impact_level"`
+}
+
+// Prompt generates the AI prompt for an entry suggestion
+func (r *EntrySuggestionRequest) Prompt() string {
+	var b strings.Builder
+
+	b.WriteString("You classify entries of a software engineer's work log.\n")
+	fmt.Fprintf(&b, "Title: %s\n", r.Title)
+	if r.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", r.Description)
+	}
+	b.WriteString("\n")
+
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(&b, "Pick up to 5 tags, only from this list: %s\n", strings.Join(r.Tags, ", "))
+	} else {
+		b.WriteString("Return an empty tags list.\n")
+	}
+	fmt.Fprintf(&b, "Pick type from: %s\n", strings.Join(r.Types, ", "))
+	if len(r.Projects) > 0 {
+		projects := make([]string, len(r.Projects))
+		for i, p := range r.Projects {
+			projects[i] = fmt.Sprintf("%s (%s)", p.ID, p.Name)
+		}
+		fmt.Fprintf(&b, "Pick project_id from these ids, or leave it empty: %s\n", strings.Join(projects, ", "))
+	} else {
+		b.WriteString("Leave project_id empty.\n")
+	}
+	fmt.Fprintf(&b, "Pick value_rating from: %s\n", strings.Join(r.ValueRatings, ", "))
+	fmt.Fprintf(&b, "Pick impact_level from: %s\n", strings.Join(r.ImpactLevels, ", "))
+	b.WriteString("\nRespond with JSON only, in this shape: ")
+	b.WriteString(`{"tags": [], "type": "", "project_id": "", "value_rating": "", "impact_level": ""}`)
+
+	return b.String()
+}
+
+// SuggestEntry suggests tags, type, project and ratings for a log entry.
+// It makes a single attempt because callers wait for the answer synchronously.
+func (s *OllamaService) SuggestEntry(ctx context.Context, req *EntrySuggestionRequest) (*EntrySuggestion, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("title cannot be empty")
+	}
+
+	s.logger.LogDebug(ctx, "Starting entry suggestion with langchaingo",
+		logging.OperationField, "suggest_entry",
+		logging.UserIDField, req.UserID,
+		"vocabulary_size", len(req.Tags),
+		"project_count", len(req.Projects),
+		"model", s.modelName)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, entrySuggestionTimeout)
+	defer cancel()
+
+	response, err := llms.GenerateFromSinglePrompt(timeoutCtx, s.llm, req.Prompt())
+	if err != nil {
+		s.logger.LogWarn(ctx, "Entry suggestion failed",
+			logging.OperationField, "suggest_entry",
+			logging.ErrorField, err)
+		return nil, fmt.Errorf("entry suggestion failed: %w", err)
+	}
+
+	suggestion, err := ParseEntrySuggestion(response)
+	if err != nil {
+		s.logger.LogWarn(ctx, "Entry suggestion response could not be parsed",
+			logging.OperationField, "suggest_entry",
+			"response_length", len(response),
+			logging.ErrorField, err)
+		return nil, err
+	}
+
+	return suggestion, nil
+}
+
+// ParseEntrySuggestion extracts the JSON object from a model response.
+// Models often wrap JSON in prose or code fences, so everything outside the outer braces is ignored.
+func ParseEntrySuggestion(response string) (*EntrySuggestion, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in entry suggestion response")
+	}
+
+	var suggestion EntrySuggestion
+	if err := json.Unmarshal([]byte(response[start:end+1]), &suggestion); err != nil {
+		return nil, fmt.Errorf("invalid entry suggestion response: %w", err)
+	}
+
+	return &suggestion, nil
+}
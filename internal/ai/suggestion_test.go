@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEntrySuggestionRequest_Prompt tests that the prompt constrains the model to the given vocabulary
+func TestEntrySuggestionRequest_Prompt(t *testing.T) {
+	req := &EntrySuggestionRequest{
+		Title:        "Fix flaky login test",
+		Description:  "Retry on timeout",
+		Tags:         []string{"auth", "ci"},
+		Projects:     []SuggestionProject{{ID: "p-1", Name: "Platform"}},
+		Types:        []string{"testing", "debugging"},
+		ValueRatings: []string{"low", "high"},
+		ImpactLevels: []string{"team"},
+	}
+
+	prompt := req.Prompt()
+	assert.Contains(t, prompt, "Title: Fix flaky login test")
+	assert.Contains(t, prompt, "Description: Retry on timeout")
+	assert.Contains(t, prompt, "only from this list: auth, ci")
+	assert.Contains(t, prompt, "p-1 (Platform)")
+	assert.Contains(t, prompt, "testing, debugging")
+
+	req.Tags = nil
+	req.Projects = nil
+	prompt = req.Prompt()
+	assert.Contains(t, prompt, "Return an empty tags list.")
+	assert.Contains(t, prompt, "Leave project_id empty.")
+}
+
+// TestParseEntrySuggestion tests extraction of the JSON object from model responses
+func TestParseEntrySuggestion(t *testing.T) {
+	t.Run("plain JSON", func(t *testing.T) {
+		s, err := ParseEntrySuggestion(`{"tags": ["ci"], "type": "testing", "value_rating": "high", "impact_level": "team"}`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ci"}, s.Tags)
+		assert.Equal(t, "testing", s.Type)
+		assert.Equal(t, "high", s.ValueRating)
+		assert.Equal(t, "team", s.ImpactLevel)
+		assert.Empty(t, s.ProjectID)
+	})
+
+	t.Run("wrapped in a code fence", func(t *testing.T) {
+		s, err := ParseEntrySuggestion("Sure!\n```json\n{\"tags\": [], \"type\": \"meeting\", \"project_id\": \"p-1\"}\n```")
+		require.NoError(t, err)
+		assert.Equal(t, "meeting", s.Type)
+		assert.Equal(t, "p-1", s.ProjectID)
+	})
+
+	t.Run("no JSON", func(t *testing.T) {
+		_, err := ParseEntrySuggestion("I cannot help with that")
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, err := ParseEntrySuggestion(`{"tags": [}`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// accomplishmentSummaryTimeout bounds a single summary call; the API renders without summaries anyway
+	accomplishmentSummaryTimeout = 40 * time.Second
+	// maxSummaryItems bounds how many accomplishments of one group are put in the prompt
+	maxSummaryItems = 30
+	// maxSummaryDescription bounds the description length of one accomplishment in the prompt
+	maxSummaryDescription = 300
+)
+
+// AccomplishmentSummaryRequest represents a request to summarize groups of accomplishments
+type AccomplishmentSummaryRequest struct {
+	UserID string                `json:"user_id"`
+	Groups []AccomplishmentGroup `json:"groups"`
+}
+
+// AccomplishmentGroup is a group of accomplishments summarized together
+type AccomplishmentGroup struct {
+	Key         string               `json:"key"`
+	Project     string               `json:"project"`
+	ImpactLevel string               `json:"impact_level"`
+	Items       []AccomplishmentItem `json:"items"`
+}
+
+// AccomplishmentItem is one accomplishment of a group
+type AccomplishmentItem struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// AccomplishmentSummary holds one summary per group key
+type AccomplishmentSummary struct {
+	Summaries map[string]string `json:"summaries"`
+}
+
+// Prompt generates the AI prompt for an accomplishment summary
+func (r *AccomplishmentSummaryRequest) Prompt() string {
+	var b strings.Builder
+
+	b.WriteString("You help a software engineer write a brag document for their performance review.\n")
+	b.WriteString("For each group below, write one or two sentences in the first person that summarize the accomplishments ")
+	b.WriteString("and their impact. Do not invent facts that are not in the list.\n\n")
+
+	for _, group := range r.Groups {
+		fmt.Fprintf(&b, "Group %s (project: %s, impact: %s)\n", group.Key, group.Project, group.ImpactLevel)
+		for i, item := range group.Items {
+			if i == maxSummaryItems {
+				fmt.Fprintf(&b, "- and %d more\n", len(group.Items)-maxSummaryItems)
+				break
+			}
+			fmt.Fprintf(&b, "- %s", item.Title)
+			if description := strings.Join(strings.Fields(item.Description), " "); description != "" {
+				if runes := []rune(description); len(runes) > maxSummaryDescription {
+					description = string(runes[:maxSummaryDescription]) + "..."
+				}
+				fmt.Fprintf(&b, ": %s", description)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Respond with JSON only, in this shape: ")
+	b.WriteString(`{"summaries": {"<group>": "<summary>"}}`)
+
+	return b.String()
+}
+
+// SummarizeAccomplishments writes a short summary of each group of accomplishments.
+// It makes a single attempt because callers wait for the answer synchronously.
+func (s *OllamaService) SummarizeAccomplishments(ctx context.Context, req *AccomplishmentSummaryRequest) (*AccomplishmentSummary, error) {
+	if len(req.Groups) == 0 {
+		return &AccomplishmentSummary{Summaries: map[string]string{}}, nil
+	}
+
+	s.logger.LogDebug(ctx, "Starting accomplishment summary with langchaingo",
+		logging.OperationField, "summarize_accomplishments",
+		logging.UserIDField, req.UserID,
+		"group_count", len(req.Groups),
+		"model", s.modelName)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, accomplishmentSummaryTimeout)
+	defer cancel()
+
+	response, err := llms.GenerateFromSinglePrompt(timeoutCtx, s.llm, req.Prompt())
+	if err != nil {
+		s.logger.LogWarn(ctx, "Accomplishment summary failed",
+			logging.OperationField, "summarize_accomplishments",
+			logging.ErrorField, err)
+		return nil, fmt.Errorf("accomplishment summary failed: %w", err)
+	}
+
+	summary, err := ParseAccomplishmentSummary(response)
+	if err != nil {
+		s.logger.LogWarn(ctx, "Accomplishment summary response could not be parsed",
+			logging.OperationField, "summarize_accomplishments",
+			"response_length", len(response),
+			logging.ErrorField, err)
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// ParseAccomplishmentSummary extracts the JSON object from a model response.
+// Models often wrap JSON in prose or code fences, so everything outside the outer braces is ignored.
+func ParseAccomplishmentSummary(response string) (*AccomplishmentSummary, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in accomplishment summary response")
+	}
+
+	var summary AccomplishmentSummary
+	if err := json.Unmarshal([]byte(response[start:end+1]), &summary); err != nil {
+		return nil, fmt.Errorf("invalid accomplishment summary response: %w", err)
+	}
+	if summary.Summaries == nil {
+		summary.Summaries = map[string]string{}
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccomplishmentSummaryRequest_Prompt tests that every group and its accomplishments reach the prompt
+func TestAccomplishmentSummaryRequest_Prompt(t *testing.T) {
+	req := &AccomplishmentSummaryRequest{
+		Groups: []AccomplishmentGroup{
+			{
+				Key:         "g1",
+				Project:     "Platform",
+				ImpactLevel: "company",
+				Items: []AccomplishmentItem{
+					{Title: "Billing migration", Description: "Moved   invoices\nto the new ledger"},
+					{Title: "Incident review"},
+				},
+			},
+			{Key: "g2", Project: "No project", ImpactLevel: "team", Items: make([]AccomplishmentItem, maxSummaryItems+2)},
+		},
+	}
+
+	prompt := req.Prompt()
+	assert.Contains(t, prompt, "Group g1 (project: Platform, impact: company)")
+	assert.Contains(t, prompt, "- Billing migration: Moved invoices to the new ledger\n")
+	assert.Contains(t, prompt, "- Incident review\n")
+	assert.Contains(t, prompt, "Group g2")
+	assert.Contains(t, prompt, "- and 2 more")
+
+	req.Groups[0].Items[0].Description = strings.Repeat("é", maxSummaryDescription+10)
+	assert.Contains(t, req.Prompt(), strings.Repeat("é", maxSummaryDescription)+"...", "long descriptions are cut on rune boundaries")
+}
+
+// TestParseAccomplishmentSummary tests extraction of the summaries from model responses
+func TestParseAccomplishmentSummary(t *testing.T) {
+	s, err := ParseAccomplishmentSummary("Here you go:\n```json\n{\"summaries\": {\"g1\": \"I led the billing migration.\"}}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, "I led the billing migration.", s.Summaries["g1"])
+
+	s, err = ParseAccomplishmentSummary(`{}`)
+	require.NoError(t, err)
+	assert.NotNil(t, s.Summaries)
+
+	_, err = ParseAccomplishmentSummary("no summaries today")
+	assert.Error(t, err)
+}
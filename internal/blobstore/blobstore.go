@@ -0,0 +1,67 @@
+// Package blobstore stores binary objects such as log entry attachments on a
+// pluggable backend: the local filesystem or an S3-compatible object store.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// Store is a flat key/value object store
+type Store interface {
+	// Put writes size bytes from r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a backend
+type Config struct {
+	Backend   string // "local" or "s3"
+	LocalPath string // Root directory for the local backend
+
+	S3Endpoint  string // e.g. https://s3.us-east-1.amazonaws.com or http://minio:9000
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// New creates the Store described by cfg
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", "local":
+		return NewLocal(cfg.LocalPath)
+	case "s3":
+		return NewS3(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob storage backend: %s", cfg.Backend)
+	}
+}
+
+// validateKey rejects keys that could escape the store namespace
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key: %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "user/attachment", strings.NewReader("hello"), 5, "text/plain"))
+
+	r, err := store.Get(ctx, "user/attachment")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "hello", string(data))
+
+	err = store.Put(ctx, "user/short", strings.NewReader("abc"), 10, "text/plain")
+	assert.Error(t, err, "size mismatches are rejected")
+	_, err = store.Get(ctx, "user/short")
+	assert.ErrorIs(t, err, ErrNotFound, "failed uploads leave nothing behind")
+
+	require.NoError(t, store.Delete(ctx, "user/attachment"))
+	require.NoError(t, store.Delete(ctx, "user/attachment"), "deleting twice is fine")
+	_, err = store.Get(ctx, "user/attachment")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, validateKey("a/b/c.png"))
+	for _, key := range []string{"", "/abs", "../escape", "a/../b", "a//b", `a\b`, "a/./b"} {
+		assert.Error(t, validateKey(key), key)
+	}
+}
+
+func TestNew(t *testing.T) {
+	store, err := New(Config{Backend: "local", LocalPath: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &Local{}, store)
+
+	store, err = New(Config{Backend: "s3", S3Endpoint: "http://minio:9000", S3Bucket: "b", S3AccessKey: "k", S3SecretKey: "s"})
+	require.NoError(t, err)
+	assert.IsType(t, &S3{}, store)
+
+	_, err = New(Config{Backend: "ftp"})
+	assert.Error(t, err)
+	_, err = New(Config{Backend: "s3", S3Endpoint: "http://minio:9000"})
+	assert.Error(t, err)
+}
+
+// fakeS3 is a minimal in-memory S3 endpoint that checks requests are signed
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") ||
+		!strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date") ||
+		r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(body)
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3(S3Options{
+		Endpoint:  server.URL,
+		Region:    "eu-west-1",
+		Bucket:    "attachments",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "user/a b.txt", strings.NewReader("notes"), 5, "text/plain"))
+	assert.Contains(t, fake.objects, "/attachments/user/a b.txt")
+
+	r, err := store.Get(ctx, "user/a b.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "notes", string(data))
+
+	require.NoError(t, store.Delete(ctx, "user/a b.txt"))
+	_, err = store.Get(ctx, "user/a b.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3SignatureIsDeterministic(t *testing.T) {
+	store, err := NewS3(S3Options{Endpoint: "https://s3.amazonaws.com", Bucket: "b", AccessKey: "AKID", SecretKey: "secret"})
+	require.NoError(t, err)
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sign := func(key string) string {
+		req, err := store.newRequest(context.Background(), http.MethodGet, key, nil)
+		require.NoError(t, err)
+		store.sign(req, emptyPayloadHash, at)
+		assert.Equal(t, "20250102T030405Z", req.Header.Get("X-Amz-Date"))
+		return req.Header.Get("Authorization")
+	}
+
+	first := sign("k/v")
+	assert.Equal(t, first, sign("k/v"))
+	assert.NotEqual(t, first, sign("k/w"))
+	assert.Contains(t, first, "Credential=AKID/20250102/us-east-1/s3/aws4_request")
+}
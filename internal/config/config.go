@@ -14,13 +14,19 @@ type Config struct {
 	Port        int
 	Host        string
 
-	DB        DBConfig
-	Auth      AuthConfig
-	Server    ServerConfig
-	RateLimit RateLimitConfig
-	Security  SecurityConfig
-	Logging   LoggingConfig
-	Redis     RedisConfig
+	DB            DBConfig
+	Auth          AuthConfig
+	Server        ServerConfig
+	RateLimit     RateLimitConfig
+	Security      SecurityConfig
+	Logging       LoggingConfig
+	Redis         RedisConfig
+	Logs          LogsConfig
+	Storage       StorageConfig
+	Analytics     AnalyticsConfig
+	Reports       ReportsConfig
+	Webhooks      WebhooksConfig
+	Notifications NotificationsConfig
 
 	// gRPC configuration for worker communication
 	GRPC   GRPCConfig
@@ -100,6 +106,61 @@ type RedisConfig struct {
 	PoolSize int
 }
 
+// LogsConfig holds log entry retention configuration
+type LogsConfig struct {
+	TrashRetentionDays int // Days a deleted log entry stays in the trash before it is purged
+}
+
+// AnalyticsConfig holds analytics background refresh configuration
+type AnalyticsConfig struct {
+	SummaryRefreshInterval time.Duration // How often the activity summary view is refreshed
+	SummaryRefreshDebounce time.Duration // Quiet period after log entry writes before a refresh
+}
+
+// ReportsConfig holds configuration of generated reports and brag documents
+type ReportsConfig struct {
+	PollInterval time.Duration // How often report tasks are dispatched to workers and collected
+	DownloadTTL  time.Duration // How long a generated report can be downloaded
+	TemplateDir  string        // Directory of custom brag document templates (<name>.md.tmpl)
+	EntryURL     string        // Link to a log entry in brag documents; {id} is replaced by the entry ID
+}
+
+// WebhooksConfig holds configuration of outbound webhook deliveries
+type WebhooksConfig struct {
+	PollInterval         time.Duration // How often due deliveries are sent
+	Timeout              time.Duration // How long a receiver may take to answer
+	DeliveryRetention    time.Duration // How long finished deliveries stay in the delivery log
+	AllowPrivateNetworks bool          // Let webhooks point at loopback and private addresses; local development only
+}
+
+// NotificationsConfig holds configuration of email notifications; they are
+// disabled while SMTPHost is empty
+type NotificationsConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FromName     string
+	FromEmail    string
+	SMTPTimeout  time.Duration // How long an SMTP server may take to accept an email
+	PollInterval time.Duration // How often notifications are scheduled and sent
+	ReportWait   time.Duration // How long a weekly digest waits for its AI report
+	Retention    time.Duration // How long sent and failed notifications are kept
+}
+
+// StorageConfig holds blob storage configuration for log entry attachments
+type StorageConfig struct {
+	Backend              string // "local" or "s3"
+	LocalPath            string // Root directory of the local backend
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKey          string
+	S3SecretKey          string
+	AttachmentMaxBytes   int64 // Maximum size of a single attachment
+	AttachmentQuotaBytes int64 // Maximum total attachment size per user
+}
+
 // GRPCConfig holds gRPC configuration for worker communication
 type GRPCConfig struct {
 	ServerPort    int
@@ -192,6 +253,54 @@ func Load() *Config {
 			PoolSize: getIntEnv("REDIS_POOL_SIZE", 10),
 		},
 
+		Logs: LogsConfig{
+			TrashRetentionDays: getIntEnv("LOG_TRASH_RETENTION_DAYS", 30),
+		},
+
+		Analytics: AnalyticsConfig{
+			SummaryRefreshInterval: getDurationEnv("ANALYTICS_SUMMARY_REFRESH_INTERVAL", 15*time.Minute),
+			SummaryRefreshDebounce: getDurationEnv("ANALYTICS_SUMMARY_REFRESH_DEBOUNCE", 30*time.Second),
+		},
+
+		Reports: ReportsConfig{
+			PollInterval: getDurationEnv("REPORT_POLL_INTERVAL", 5*time.Second),
+			DownloadTTL:  getDurationEnv("REPORT_DOWNLOAD_TTL", 72*time.Hour),
+			TemplateDir:  getEnv("REPORT_TEMPLATE_DIR", ""),
+			EntryURL:     getEnv("REPORT_ENTRY_URL", "http://localhost:8080/v1/logs/{id}"),
+		},
+
+		Webhooks: WebhooksConfig{
+			PollInterval:         getDurationEnv("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			Timeout:              getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			DeliveryRetention:    getDurationEnv("WEBHOOK_DELIVERY_RETENTION", 720*time.Hour),
+			AllowPrivateNetworks: getBoolEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		},
+
+		Notifications: NotificationsConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getIntEnv("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FromName:     getEnv("SMTP_FROM_NAME", "EngLog"),
+			FromEmail:    getEnv("SMTP_FROM_EMAIL", "noreply@englog.dev"),
+			SMTPTimeout:  getDurationEnv("SMTP_TIMEOUT", 30*time.Second),
+			PollInterval: getDurationEnv("NOTIFICATION_POLL_INTERVAL", time.Minute),
+			ReportWait:   getDurationEnv("NOTIFICATION_REPORT_WAIT", 30*time.Minute),
+			Retention:    getDurationEnv("NOTIFICATION_RETENTION", 2160*time.Hour),
+		},
+
+		Storage: StorageConfig{
+			Backend:              getEnv("BLOB_STORAGE_BACKEND", "local"),
+			LocalPath:            getEnv("BLOB_STORAGE_PATH", "./data/attachments"),
+			S3Endpoint:           getEnv("S3_ENDPOINT", ""),
+			S3Region:             getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:             getEnv("S3_BUCKET", ""),
+			S3AccessKey:          getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:          getEnv("S3_SECRET_KEY", ""),
+			AttachmentMaxBytes:   int64(getIntEnv("ATTACHMENT_MAX_BYTES", 5*1024*1024)),
+			AttachmentQuotaBytes: int64(getIntEnv("ATTACHMENT_QUOTA_BYTES", 100*1024*1024)),
+		},
+
 		GRPC: GRPCConfig{
 			ServerPort:       getIntEnv("GRPC_SERVER_PORT", 9090),
 			WorkerAddress:    getEnv("WORKER_GRPC_ADDRESS", "worker-server:9091"),
@@ -0,0 +1,225 @@
+// Package gitlog reads the output of git log --numstat and groups commits into
+// work sessions.
+package gitlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLineBytes bounds the length of a single line of git log output
+const maxLineBytes = 1024 * 1024
+
+// dateLayouts are the --date formats Parse understands: git's default, iso,
+// iso-strict and rfc. Formats without a UTC offset are ambiguous and rejected.
+var dateLayouts = []string{
+	"Mon Jan 2 15:04:05 2006 -0700",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+}
+
+// numstatLine matches a --numstat line; binary files have - instead of counts
+var numstatLine = regexp.MustCompile(`^(\d+|-)\t(\d+|-)\t.+$`)
+
+// Commit is one commit of a git log
+type Commit struct {
+	Hash        string
+	AuthorName  string
+	AuthorEmail string
+	Time        time.Time // Author date
+	Subject     string
+	Merge       bool
+	Files       int
+	Insertions  int
+	Deletions   int
+}
+
+// ShortHash returns the first seven characters of the hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Parse reads the output of git log --numstat in its default layout, with any of
+// the --date formats that include a UTC offset. --pretty=fuller output is read too.
+func Parse(r io.Reader) ([]Commit, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	var commits []Commit
+	var current *Commit
+	lineNumber := 0
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if current.AuthorEmail == "" {
+			return fmt.Errorf("commit %s has no author", current.Hash)
+		}
+		if current.Time.IsZero() {
+			return fmt.Errorf("commit %s has no date", current.Hash)
+		}
+		commits = append(commits, *current)
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if rest, ok := strings.CutPrefix(line, "commit "); ok {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			hash, _, _ := strings.Cut(rest, " ") // Drop decorations such as (HEAD -> main)
+			current = &Commit{Hash: hash}
+			continue
+		}
+
+		if current == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: expected a commit line, got %q", lineNumber, line)
+		}
+
+		switch {
+		case strings.HasPrefix(line, "    "):
+			if current.Subject == "" {
+				current.Subject = strings.TrimSpace(line)
+			}
+		case strings.HasPrefix(line, "Merge:"):
+			current.Merge = true
+		case strings.HasPrefix(line, "Author:"):
+			name, email, ok := parseAuthor(strings.TrimPrefix(line, "Author:"))
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid author %q", lineNumber, line)
+			}
+			current.AuthorName = name
+			current.AuthorEmail = email
+		case strings.HasPrefix(line, "Date:"), strings.HasPrefix(line, "AuthorDate:"):
+			_, value, _ := strings.Cut(line, ":")
+			t, err := parseDate(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			current.Time = t
+		case numstatLine.MatchString(line):
+			fields := strings.SplitN(line, "\t", 3)
+			current.Files++
+			current.Insertions += atoiOrZero(fields[0])
+			current.Deletions += atoiOrZero(fields[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// parseAuthor splits "Name <email>". Git accepts almost anything as a name, so
+// this is more lenient than an RFC 5322 address parser.
+func parseAuthor(value string) (name, email string, ok bool) {
+	open := strings.LastIndex(value, "<")
+	if open < 0 || !strings.HasSuffix(strings.TrimSpace(value), ">") {
+		return "", "", false
+	}
+	email = strings.TrimSuffix(strings.TrimSpace(value[open+1:]), ">")
+	if email == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(value[:open]), email, true
+}
+
+// parseDate parses a commit date in one of dateLayouts
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date %q: use git's default, iso, iso-strict or rfc date format", value)
+}
+
+// atoiOrZero parses a numstat count, which is - for binary files
+func atoiOrZero(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// SessionOptions controls how commits are grouped into sessions
+type SessionOptions struct {
+	// Gap is the longest pause between two commits of the same session
+	Gap time.Duration
+	// Lead is the work assumed before the first commit of a session
+	Lead time.Duration
+	// MaxLength bounds the span of a session; longer runs of commits are split
+	MaxLength time.Duration
+}
+
+// Session is a run of commits without long pauses between them
+type Session struct {
+	Start   time.Time
+	End     time.Time
+	Commits []Commit // Oldest first
+}
+
+// Stats returns the files changed, insertions and deletions of the session
+func (s Session) Stats() (files, insertions, deletions int) {
+	for _, c := range s.Commits {
+		files += c.Files
+		insertions += c.Insertions
+		deletions += c.Deletions
+	}
+	return files, insertions, deletions
+}
+
+// Sessions groups commits into work sessions. A session starts Lead before its
+// first commit, but never before the previous session ends, and ends at its last
+// commit. A commit starts a new session when it comes more than Gap after the
+// previous one or when it would stretch the session beyond MaxLength. With a
+// positive Lead every session has a positive length.
+func Sessions(commits []Commit, opts SessionOptions) []Session {
+	sorted := make([]Commit, len(commits))
+	copy(sorted, commits)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var sessions []Session
+	for _, c := range sorted {
+		if n := len(sessions); n > 0 {
+			last := &sessions[n-1]
+			withinGap := c.Time.Sub(last.End) <= opts.Gap
+			withinLength := opts.MaxLength <= 0 || c.Time.Sub(last.Start) <= opts.MaxLength ||
+				!c.Time.After(last.End) // Commits at the same instant stay together
+			if withinGap && withinLength {
+				last.End = c.Time
+				last.Commits = append(last.Commits, c)
+				continue
+			}
+		}
+
+		start := c.Time.Add(-opts.Lead)
+		if n := len(sessions); n > 0 && start.Before(sessions[n-1].End) {
+			start = sessions[n-1].End
+		}
+		sessions = append(sessions, Session{Start: start, End: c.Time, Commits: []Commit{c}})
+	}
+	return sessions
+}
@@ -0,0 +1,181 @@
+package gitlog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const defaultDump = `commit 5f1c2a9d8e7b6a5c4d3e2f1a0b9c8d7e6f5a4b3c (HEAD -> main, origin/main)
+Author: Jane Doe <jane@example.com>
+Date:   Mon Mar 10 11:30:00 2025 -0300
+
+    Add invoice endpoint
+
+    Longer explanation that is not the subject.
+
+12	3	internal/handlers/invoices.go
+-	-	docs/diagram.png
+40	0	internal/handlers/invoices_test.go
+
+commit 0a1b2c3d4e5f60718293a4b5c6d7e8f901234567
+Merge: 1111111 2222222
+Author: Jane Doe <jane@example.com>
+Date:   Mon Mar 10 10:00:00 2025 -0300
+
+    Merge branch 'feature'
+
+commit 9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a291807
+Author: "Doe, John (contractor)" <john@example.com>
+Date:   Mon Mar 10 09:15:00 2025 -0300
+
+    Fix flaky test
+
+1	1	internal/services/tag_test.go
+`
+
+func TestParse(t *testing.T) {
+	commits, err := Parse(strings.NewReader(defaultDump))
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	first := commits[0]
+	assert.Equal(t, "5f1c2a9d8e7b6a5c4d3e2f1a0b9c8d7e6f5a4b3c", first.Hash, "decorations are dropped")
+	assert.Equal(t, "5f1c2a9", first.ShortHash())
+	assert.Equal(t, "Jane Doe", first.AuthorName)
+	assert.Equal(t, "jane@example.com", first.AuthorEmail)
+	assert.True(t, first.Time.Equal(time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "Add invoice endpoint", first.Subject)
+	assert.Equal(t, 3, first.Files)
+	assert.Equal(t, 52, first.Insertions, "binary files count as changed without lines")
+	assert.Equal(t, 3, first.Deletions)
+	assert.False(t, first.Merge)
+
+	assert.True(t, commits[1].Merge)
+	assert.Zero(t, commits[1].Files)
+
+	assert.Equal(t, `"Doe, John (contractor)"`, commits[2].AuthorName)
+	assert.Equal(t, "john@example.com", commits[2].AuthorEmail)
+}
+
+func TestParseDateFormats(t *testing.T) {
+	want := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, date := range []string{
+		"Mon Mar 10 09:00:00 2025 -0300",
+		"2025-03-10 09:00:00 -0300",
+		"2025-03-10T09:00:00-03:00",
+		"Mon, 10 Mar 2025 09:00:00 -0300",
+	} {
+		commits, err := Parse(strings.NewReader("commit abc\nAuthor: A <a@example.com>\nDate:   " + date + "\n\n    Subject\n"))
+		require.NoError(t, err, date)
+		require.Len(t, commits, 1)
+		assert.True(t, commits[0].Time.Equal(want), date)
+	}
+
+	fuller := "commit abc\nAuthor:     A <a@example.com>\nAuthorDate: 2025-03-10T09:00:00-03:00\nCommit:     B <b@example.com>\nCommitDate: 2025-03-11T09:00:00-03:00\n\n    Subject\n"
+	commits, err := Parse(strings.NewReader(fuller))
+	require.NoError(t, err)
+	assert.True(t, commits[0].Time.Equal(want), "the author date wins over the commit date")
+}
+
+func TestParseErrors(t *testing.T) {
+	commits, err := Parse(strings.NewReader("\n\n"))
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+
+	_, err = Parse(strings.NewReader("not a git log\n"))
+	assert.ErrorContains(t, err, "line 1: expected a commit line")
+
+	_, err = Parse(strings.NewReader("commit abc\nDate:   2025-03-10T09:00:00-03:00\n"))
+	assert.ErrorContains(t, err, "commit abc has no author")
+
+	_, err = Parse(strings.NewReader("commit abc\nAuthor: A <a@example.com>\n"))
+	assert.ErrorContains(t, err, "commit abc has no date")
+
+	_, err = Parse(strings.NewReader("commit abc\nAuthor: A <a@example.com>\nDate:   Mon Mar 10 09:00:00 2025\n"))
+	assert.ErrorContains(t, err, "unsupported date")
+
+	_, err = Parse(strings.NewReader("commit abc\nAuthor: nobody\n"))
+	assert.ErrorContains(t, err, "invalid author")
+}
+
+func TestSessions(t *testing.T) {
+	at := func(hour, minute int) Commit {
+		return Commit{Hash: time.Date(2025, 3, 10, hour, minute, 0, 0, time.UTC).Format("1504"), Time: time.Date(2025, 3, 10, hour, minute, 0, 0, time.UTC), Files: 1, Insertions: 2, Deletions: 1}
+	}
+	opts := SessionOptions{Gap: 2 * time.Hour, Lead: 30 * time.Minute, MaxLength: 4 * time.Hour}
+
+	sessions := Sessions([]Commit{at(11, 0), at(9, 0), at(10, 30), at(15, 0), at(15, 20)}, opts)
+	require.Len(t, sessions, 2)
+
+	assert.Equal(t, time.Date(2025, 3, 10, 8, 30, 0, 0, time.UTC), sessions[0].Start, "the lead comes before the first commit")
+	assert.Equal(t, time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC), sessions[0].End)
+	require.Len(t, sessions[0].Commits, 3)
+	assert.Equal(t, "0900", sessions[0].Commits[0].Hash, "commits are sorted oldest first")
+	files, insertions, deletions := sessions[0].Stats()
+	assert.Equal(t, []int{3, 6, 3}, []int{files, insertions, deletions})
+
+	assert.Equal(t, time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC), sessions[1].Start)
+	assert.Len(t, sessions[1].Commits, 2)
+
+	// A long run of commits is split, and the next session starts where the last one ended
+	opts.MaxLength = 2 * time.Hour
+	sessions = Sessions([]Commit{at(8, 0), at(9, 30), at(9, 45), at(9, 45)}, opts)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC), sessions[0].End)
+	assert.Equal(t, sessions[0].End, sessions[1].Start, "sessions never overlap")
+	assert.Len(t, sessions[1].Commits, 2, "commits at the same instant stay together")
+
+	assert.Empty(t, Sessions(nil, opts))
+}
+
+func TestRead(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := filepath.Join(t.TempDir(), "billing-service")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE=2025-03-10T09:00:00-03:00",
+			"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE=2025-03-10T09:00:00-03:00",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "--quiet")
+	git("config", "user.email", "jane@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+	git("add", "main.go")
+	git("commit", "--quiet", "-m", "Initial commit")
+
+	ctx := context.Background()
+	name, err := RepositoryName(ctx, filepath.Join(dir, "."))
+	require.NoError(t, err)
+	assert.Equal(t, "billing-service", name)
+
+	email, err := UserEmail(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", email)
+
+	out, err := Read(ctx, dir, ReadOptions{Author: email})
+	require.NoError(t, err)
+	commits, err := Parse(strings.NewReader(string(out)))
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "Initial commit", commits[0].Subject)
+	assert.Equal(t, 1, commits[0].Files)
+	assert.True(t, commits[0].Time.Equal(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)))
+
+	_, err = Read(ctx, t.TempDir(), ReadOptions{})
+	assert.ErrorContains(t, err, "git log")
+}
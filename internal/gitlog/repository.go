@@ -0,0 +1,77 @@
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReadOptions narrows the commits read from a repository
+type ReadOptions struct {
+	Author string    // Passed to --author; empty reads every author
+	Since  time.Time // Zero reads from the first commit
+	Until  time.Time // Zero reads up to the last commit
+	All    bool      // Read every branch instead of the checked out one
+}
+
+// Read runs git log --numstat in the repository at dir and returns its output,
+// in the layout Parse reads. Merge commits are left out.
+func Read(ctx context.Context, dir string, opts ReadOptions) ([]byte, error) {
+	args := []string{"log", "--numstat", "--no-merges", "--no-color", "--no-decorate", "--date=iso-strict"}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since="+opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		args = append(args, "--until="+opts.Until.Format(time.RFC3339))
+	}
+	if opts.All {
+		args = append(args, "--all")
+	}
+	return runGit(ctx, dir, args...)
+}
+
+// RepositoryName returns the name of the directory at the top of the repository
+// that contains dir
+func RepositoryName(ctx context.Context, dir string) (string, error) {
+	out, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(strings.TrimSpace(string(out))), nil
+}
+
+// UserEmail returns the user.email git uses for new commits in the repository at dir
+func UserEmail(ctx context.Context, dir string) (string, error) {
+	out, err := runGit(ctx, dir, "config", "user.email")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runGit runs git with args in dir and returns its standard output
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("git is not installed")
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -3,8 +3,10 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"sync"
 	"time"
 
@@ -14,9 +16,19 @@ import (
 
 	"github.com/garnizeh/englog/internal/config"
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
 	workerpb "github.com/garnizeh/englog/proto/worker"
 )
 
+// syncTaskPollInterval is how often the result of a task a caller waits for is checked
+const syncTaskPollInterval = 50 * time.Millisecond
+
+// reportRenderDeadline is how long a worker has to render a report
+const reportRenderDeadline = 10 * time.Minute
+
+// ErrNoCapableWorker is returned when no active worker can run the requested task type
+var ErrNoCapableWorker = errors.New("no active worker with the required capability")
+
 // Manager manages the gRPC server lifecycle
 type Manager struct {
 	server     *Server
@@ -158,6 +170,13 @@ func (m *Manager) GetServer() *Server {
 	return m.server
 }
 
+// OnTaskResult registers a callback told when a task queued through the manager
+// completes or fails, such as WebhookService.PublishTaskOutcome. Every registered
+// callback is told about every outcome.
+func (m *Manager) OnTaskResult(listener func(context.Context, models.WorkerTaskOutcome)) {
+	m.server.AddResultListener(listener)
+}
+
 // QueueInsightGenerationTask queues an insight generation task
 func (m *Manager) QueueInsightGenerationTask(ctx context.Context, userID string, entryIDs []string, insightType string, contextData any) (string, error) {
 	start := time.Now()
@@ -282,6 +301,199 @@ func (m *Manager) QueueWeeklyReportTask(ctx context.Context, userID string, week
 	return taskID, nil
 }
 
+// SuggestEntry runs an entry suggestion task on a worker and waits for its JSON result.
+// The wait is bounded by ctx; ErrNoCapableWorker is returned when no worker can run it.
+func (m *Manager) SuggestEntry(ctx context.Context, userID string, payload any) (string, error) {
+	return m.runTask(ctx, syncTask{
+		operation: "suggest_entry",
+		name:      "entry suggestion",
+		label:     "Entry suggestion",
+		taskType:  workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION,
+		idPrefix:  "suggest",
+		priority:  1, // Lowest priority, callers fall back when it is slow
+	}, userID, payload)
+}
+
+// SummarizeAccomplishments runs an accomplishment summary task on a worker and waits for its
+// JSON result. The wait is bounded by ctx; ErrNoCapableWorker is returned when no worker can run it.
+func (m *Manager) SummarizeAccomplishments(ctx context.Context, userID string, payload any) (string, error) {
+	return m.runTask(ctx, syncTask{
+		operation: "summarize_accomplishments",
+		name:      "accomplishment summary",
+		label:     "Accomplishment summary",
+		taskType:  workerpb.TaskType_TASK_TYPE_ACCOMPLISHMENT_SUMMARY,
+		idPrefix:  "summary",
+		priority:  1, // Callers render without summaries when it is slow
+	}, userID, payload)
+}
+
+// syncTask describes a task whose caller waits for the result
+type syncTask struct {
+	operation string
+	name      string // used in errors
+	label     string // used in log messages
+	taskType  workerpb.TaskType
+	idPrefix  string
+	priority  int32
+}
+
+// runTask queues a task on a worker and polls for its JSON result until ctx is done
+func (m *Manager) runTask(ctx context.Context, spec syncTask, userID string, payload any) (string, error) {
+	start := time.Now()
+
+	if !m.HasCapableWorker(ctx, spec.taskType) {
+		return "", ErrNoCapableWorker
+	}
+
+	taskID := fmt.Sprintf("%s_%s_%d", spec.idPrefix, userID, time.Now().UnixNano())
+
+	payloadJSON, err := jsonMarshal(payload)
+	if err != nil {
+		m.logger.LogError(ctx, err, "Failed to marshal "+spec.name+" task payload",
+			logging.OperationField, spec.operation,
+			"task_id", taskID,
+			"user_id", userID)
+		return "", fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+
+	task := &workerpb.TaskRequest{
+		TaskId:   taskID,
+		TaskType: spec.taskType,
+		Payload:  string(payloadJSON),
+		Priority: spec.priority,
+		Deadline: timestamppb.New(deadline),
+		Metadata: map[string]string{
+			"user_id": userID,
+		},
+	}
+
+	if err := m.server.QueueTask(ctx, task); err != nil {
+		return "", err
+	}
+
+	ticker := time.NewTicker(syncTaskPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.server.DiscardTaskResult(taskID)
+			m.logger.LogWarn(ctx, spec.label+" task did not complete in time",
+				logging.OperationField, spec.operation,
+				"task_id", taskID,
+				"user_id", userID,
+				"duration_ms", time.Since(start).Milliseconds())
+			return "", fmt.Errorf("%s task timed out: %w", spec.name, ctx.Err())
+		case <-ticker.C:
+			result, found := m.server.GetTaskResult(taskID)
+			if !found {
+				continue
+			}
+			m.server.DeleteTaskResult(taskID)
+
+			m.logger.LogDebug(ctx, spec.label+" task finished",
+				logging.OperationField, spec.operation,
+				"task_id", taskID,
+				"worker_id", result.WorkerID,
+				"status", result.Status,
+				"duration_ms", time.Since(start).Milliseconds())
+
+			if result.Status != workerpb.TaskStatus_TASK_STATUS_COMPLETED {
+				return "", fmt.Errorf("%s task failed: %s", spec.name, result.ErrorMsg)
+			}
+			return result.Result, nil
+		}
+	}
+}
+
+// CanRenderReports reports whether an active worker can render report files
+func (m *Manager) CanRenderReports(ctx context.Context) bool {
+	return m.HasCapableWorker(ctx, workerpb.TaskType_TASK_TYPE_DATA_EXPORT)
+}
+
+// QueueReportRender queues a report rendering task under the given task ID.
+// The result is collected later with TakeReportResult.
+func (m *Manager) QueueReportRender(ctx context.Context, taskID, userID string, render *models.ReportRenderTask) error {
+	payloadJSON, err := jsonMarshal(render)
+	if err != nil {
+		m.logger.LogError(ctx, err, "Failed to marshal report render task payload",
+			logging.OperationField, "queue_report_render",
+			"task_id", taskID,
+			"user_id", userID)
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := &workerpb.TaskRequest{
+		TaskId:   taskID,
+		TaskType: workerpb.TaskType_TASK_TYPE_DATA_EXPORT,
+		Payload:  string(payloadJSON),
+		Priority: 2, // Users wait for the download, but not interactively
+		Deadline: timestamppb.New(time.Now().Add(reportRenderDeadline)),
+		Metadata: map[string]string{
+			"user_id":     userID,
+			"report_type": string(render.ReportType),
+		},
+	}
+
+	if err := m.server.QueueTask(ctx, task); err != nil {
+		return err
+	}
+
+	m.logger.LogInfo(ctx, "Report render task queued",
+		logging.OperationField, "queue_report_render",
+		"task_id", taskID,
+		"user_id", userID,
+		"report_type", render.ReportType)
+	return nil
+}
+
+// TakeReportResult returns the rendered report of a finished task and forgets the result.
+// done is false while the task has not reported back yet.
+func (m *Manager) TakeReportResult(ctx context.Context, taskID string) (*models.ReportRenderResult, bool, error) {
+	result, found := m.server.GetTaskResult(taskID)
+	if !found {
+		return nil, false, nil
+	}
+	m.server.DeleteTaskResult(taskID)
+
+	m.logger.LogDebug(ctx, "Report render task finished",
+		logging.OperationField, "take_report_result",
+		"task_id", taskID,
+		"worker_id", result.WorkerID,
+		"status", result.Status)
+
+	if result.Status != workerpb.TaskStatus_TASK_STATUS_COMPLETED {
+		return nil, true, fmt.Errorf("report rendering failed: %s", result.ErrorMsg)
+	}
+
+	var rendered models.ReportRenderResult
+	if err := json.Unmarshal([]byte(result.Result), &rendered); err != nil {
+		return nil, true, fmt.Errorf("invalid report render result: %w", err)
+	}
+	return &rendered, true, nil
+}
+
+// DiscardReportResult drops the result of a report task nobody waits for anymore
+func (m *Manager) DiscardReportResult(taskID string) {
+	m.server.DiscardTaskResult(taskID)
+}
+
+// HasCapableWorker reports whether an active worker can run tasks of the given type
+func (m *Manager) HasCapableWorker(ctx context.Context, taskType workerpb.TaskType) bool {
+	required := m.server.getRequiredCapability(taskType)
+	for _, worker := range m.server.GetActiveWorkers(ctx) {
+		if required == workerpb.WorkerCapability_CAPABILITY_UNSPECIFIED || slices.Contains(worker.Capabilities, required) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetTaskResult retrieves the result of a completed task
 func (m *Manager) GetTaskResult(ctx context.Context, taskID string) (*TaskResult, bool) {
 	start := time.Now()
@@ -607,3 +607,98 @@ func findAvailablePort() int {
 	defer listener.Close()
 	return listener.Addr().(*net.TCPAddr).Port
 }
+
+// TestManager_SuggestEntry tests the synchronous entry suggestion round trip
+func TestManager_SuggestEntry(t *testing.T) {
+	ctx := context.Background()
+	cfg := createTestConfigForManager()
+	logger := createTestLoggerForManager()
+
+	manager := grpc.NewManager(cfg, logger)
+	server := manager.GetServer()
+
+	t.Run("no capable worker", func(t *testing.T) {
+		_, err := server.RegisterWorker(ctx, &workerpb.RegisterWorkerRequest{
+			WorkerId:   "insights-only",
+			WorkerName: "Insights Worker",
+			Capabilities: []workerpb.WorkerCapability{
+				workerpb.WorkerCapability_CAPABILITY_AI_INSIGHTS,
+			},
+			Version: "1.0.0",
+		})
+		require.NoError(t, err)
+
+		assert.False(t, manager.HasCapableWorker(ctx, workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION))
+
+		_, err = manager.SuggestEntry(ctx, "user-123", map[string]any{"title": "Fix login"})
+		assert.ErrorIs(t, err, grpc.ErrNoCapableWorker)
+	})
+
+	registerResp, err := server.RegisterWorker(ctx, &workerpb.RegisterWorkerRequest{
+		WorkerId:   "suggester",
+		WorkerName: "Suggestion Worker",
+		Capabilities: []workerpb.WorkerCapability{
+			workerpb.WorkerCapability_CAPABILITY_ENTRY_SUGGESTIONS,
+		},
+		Version: "1.0.0",
+	})
+	require.NoError(t, err)
+
+	t.Run("times out when the task is not picked up", func(t *testing.T) {
+		assert.True(t, manager.HasCapableWorker(ctx, workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err := manager.SuggestEntry(timeoutCtx, "user-123", map[string]any{"title": "Fix login"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("returns the worker result", func(t *testing.T) {
+		streamCtx, cancelStream := context.WithCancel(ctx)
+		defer cancelStream()
+
+		mockStream := NewMockStream(streamCtx)
+		go func() {
+			_ = server.StreamTasks(&workerpb.StreamTasksRequest{
+				WorkerId:     "suggester",
+				SessionToken: registerResp.SessionToken,
+			}, mockStream)
+		}()
+
+		// Answer suggestion tasks as they reach the worker, including the one left by the timeout case
+		go func() {
+			answered := make(map[string]bool)
+			for streamCtx.Err() == nil {
+				for _, task := range mockStream.GetSentTasks() {
+					if task.TaskType != workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION || answered[task.TaskId] {
+						continue
+					}
+					answered[task.TaskId] = true
+					_, _ = server.ReportTaskResult(ctx, &workerpb.TaskResultRequest{
+						TaskId:      task.TaskId,
+						WorkerId:    "suggester",
+						Status:      workerpb.TaskStatus_TASK_STATUS_COMPLETED,
+						Result:      `{"type": "debugging"}`,
+						StartedAt:   timestamppb.Now(),
+						CompletedAt: timestamppb.Now(),
+					})
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		result, err := manager.SuggestEntry(timeoutCtx, "user-123", map[string]any{"title": "Fix login"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type": "debugging"}`, result)
+
+		for _, task := range mockStream.GetSentTasks() {
+			_, found := manager.GetTaskResult(ctx, task.TaskId)
+			assert.False(t, found, "consumed suggestion results are removed")
+		}
+	})
+}
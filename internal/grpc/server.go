@@ -15,6 +15,7 @@ import (
 
 	"github.com/garnizeh/englog/internal/config"
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
 	workerpb "github.com/garnizeh/englog/proto/worker"
 )
 
@@ -28,8 +29,24 @@ type Server struct {
 	taskQueue    chan *workerpb.TaskRequest
 	taskResults  map[string]*TaskResult
 	resultsMutex sync.RWMutex
+	// discarded holds tasks nobody waits for anymore, by the time they were given up
+	discarded map[string]time.Time
+	// queued holds the owner and type of tasks waiting for a result
+	queued map[string]queuedTask
+	// resultListeners are told about every completed or failed task that was queued here
+	resultListeners []func(context.Context, models.WorkerTaskOutcome)
 }
 
+// queuedTask is what is remembered of a queued task until its result comes in
+type queuedTask struct {
+	userID   string
+	taskType workerpb.TaskType
+	queuedAt time.Time
+}
+
+// queuedTaskRetention is how long a queued task is remembered without a result
+const queuedTaskRetention = 24 * time.Hour
+
 // WorkerInfo holds information about a registered worker
 type WorkerInfo struct {
 	ID            string
@@ -70,9 +87,20 @@ func NewServer(cfg *config.Config, logger *logging.Logger) *Server {
 		workers:     make(map[string]*WorkerInfo),
 		taskQueue:   make(chan *workerpb.TaskRequest, 100), // Buffer for 100 tasks
 		taskResults: make(map[string]*TaskResult),
+		discarded:   make(map[string]time.Time),
+		queued:      make(map[string]queuedTask),
 	}
 }
 
+// AddResultListener registers a callback told about the outcome of every task queued
+// on this server once a worker reports it completed or failed. Each callback runs on
+// its own goroutine. Add them before workers connect.
+func (s *Server) AddResultListener(listener func(context.Context, models.WorkerTaskOutcome)) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	s.resultListeners = append(s.resultListeners, listener)
+}
+
 // RegisterWorker handles worker registration
 func (s *Server) RegisterWorker(ctx context.Context, req *workerpb.RegisterWorkerRequest) (*workerpb.RegisterWorkerResponse, error) {
 	start := time.Now()
@@ -313,8 +341,25 @@ func (s *Server) ReportTaskResult(ctx context.Context, req *workerpb.TaskResultR
 		taskDuration = req.CompletedAt.AsTime().Sub(req.StartedAt.AsTime())
 	}
 
-	// Store result
+	// Store result unless the requester already gave up on it
 	s.resultsMutex.Lock()
+	queued, wasQueued := s.queued[req.TaskId]
+	if req.Status == workerpb.TaskStatus_TASK_STATUS_COMPLETED || req.Status == workerpb.TaskStatus_TASK_STATUS_FAILED {
+		delete(s.queued, req.TaskId)
+	}
+	listeners := s.resultListeners
+	if _, ok := s.discarded[req.TaskId]; ok {
+		delete(s.discarded, req.TaskId)
+		s.resultsMutex.Unlock()
+		s.logger.WithContext(ctx).Info("Discarded late task result",
+			"task_id", req.TaskId,
+			"worker_id", req.WorkerId,
+			"status", req.Status)
+		return &workerpb.TaskResultResponse{
+			ResultReceived: true,
+			Message:        "Task result received successfully",
+		}, nil
+	}
 	s.taskResults[req.TaskId] = &TaskResult{
 		TaskID:      req.TaskId,
 		WorkerID:    req.WorkerId,
@@ -326,6 +371,14 @@ func (s *Server) ReportTaskResult(ctx context.Context, req *workerpb.TaskResultR
 	}
 	s.resultsMutex.Unlock()
 
+	if len(listeners) > 0 && wasQueued {
+		if outcome, ok := taskOutcome(req, queued); ok {
+			for _, listener := range listeners {
+				go listener(context.WithoutCancel(ctx), outcome)
+			}
+		}
+	}
+
 	duration := time.Since(start)
 
 	// Log based on task status
@@ -524,6 +577,12 @@ func (s *Server) getRequiredCapability(taskType workerpb.TaskType) workerpb.Work
 		return workerpb.WorkerCapability_CAPABILITY_DATA_ANALYSIS
 	case workerpb.TaskType_TASK_TYPE_NOTIFICATION:
 		return workerpb.WorkerCapability_CAPABILITY_NOTIFICATIONS
+	case workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION:
+		return workerpb.WorkerCapability_CAPABILITY_ENTRY_SUGGESTIONS
+	case workerpb.TaskType_TASK_TYPE_ACCOMPLISHMENT_SUMMARY:
+		return workerpb.WorkerCapability_CAPABILITY_AI_INSIGHTS
+	case workerpb.TaskType_TASK_TYPE_DATA_EXPORT:
+		return workerpb.WorkerCapability_CAPABILITY_DATA_EXPORTS
 	default:
 		return workerpb.WorkerCapability_CAPABILITY_UNSPECIFIED
 	}
@@ -543,6 +602,8 @@ func (s *Server) QueueTask(ctx context.Context, task *workerpb.TaskRequest) erro
 
 	select {
 	case s.taskQueue <- task:
+		s.rememberQueuedTask(task)
+
 		duration := time.Since(start)
 		queueSize := len(s.taskQueue)
 
@@ -564,6 +625,69 @@ func (s *Server) QueueTask(ctx context.Context, task *workerpb.TaskRequest) erro
 	}
 }
 
+// rememberQueuedTask keeps the owner and type of a queued task for its result listener.
+// Tasks without a result after queuedTaskRetention are forgotten.
+func (s *Server) rememberQueuedTask(task *workerpb.TaskRequest) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+
+	now := time.Now()
+	for id, queued := range s.queued {
+		if now.Sub(queued.queuedAt) > queuedTaskRetention {
+			delete(s.queued, id)
+		}
+	}
+
+	s.queued[task.TaskId] = queuedTask{
+		userID:   task.Metadata["user_id"],
+		taskType: task.TaskType,
+		queuedAt: now,
+	}
+}
+
+// taskOutcome describes the final result of a queued task; results that are not
+// final, such as a cancellation, are left out
+func taskOutcome(req *workerpb.TaskResultRequest, queued queuedTask) (models.WorkerTaskOutcome, bool) {
+	outcome := models.WorkerTaskOutcome{
+		TaskID:   req.TaskId,
+		UserID:   queued.userID,
+		TaskType: taskTypeName(queued.taskType),
+	}
+	switch req.Status {
+	case workerpb.TaskStatus_TASK_STATUS_COMPLETED:
+		outcome.Result = req.Result
+	case workerpb.TaskStatus_TASK_STATUS_FAILED:
+		outcome.Failed = true
+		outcome.Error = req.ErrorMessage
+	default:
+		return outcome, false
+	}
+	return outcome, true
+}
+
+// taskTypeName names a task type in outcomes
+func taskTypeName(taskType workerpb.TaskType) string {
+	switch taskType {
+	case workerpb.TaskType_TASK_TYPE_INSIGHT_GENERATION:
+		return "insight_generation"
+	case workerpb.TaskType_TASK_TYPE_WEEKLY_REPORT:
+		return "weekly_report"
+	case workerpb.TaskType_TASK_TYPE_DATA_ANALYSIS:
+		return "data_analysis"
+	case workerpb.TaskType_TASK_TYPE_CLEANUP:
+		return "cleanup"
+	case workerpb.TaskType_TASK_TYPE_NOTIFICATION:
+		return "notification"
+	case workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION:
+		return "entry_suggestion"
+	case workerpb.TaskType_TASK_TYPE_DATA_EXPORT:
+		return "data_export"
+	case workerpb.TaskType_TASK_TYPE_ACCOMPLISHMENT_SUMMARY:
+		return "accomplishment_summary"
+	}
+	return "unknown"
+}
+
 // GetTaskResult retrieves the result of a completed task
 func (s *Server) GetTaskResult(taskID string) (*TaskResult, bool) {
 	s.resultsMutex.RLock()
@@ -572,6 +696,33 @@ func (s *Server) GetTaskResult(taskID string) (*TaskResult, bool) {
 	return result, exists
 }
 
+// DeleteTaskResult removes a task result once it has been consumed
+func (s *Server) DeleteTaskResult(taskID string) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	delete(s.taskResults, taskID)
+}
+
+// DiscardTaskResult drops the result of a task nobody waits for, now or when it is reported later.
+// Marks older than an hour are pruned since their tasks are past any deadline.
+func (s *Server) DiscardTaskResult(taskID string) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+
+	now := time.Now()
+	for id, at := range s.discarded {
+		if now.Sub(at) > time.Hour {
+			delete(s.discarded, id)
+		}
+	}
+
+	if _, ok := s.taskResults[taskID]; ok {
+		delete(s.taskResults, taskID)
+		return
+	}
+	s.discarded[taskID] = now
+}
+
 // GetActiveWorkers returns information about all active workers
 func (s *Server) GetActiveWorkers(ctx context.Context) map[string]*WorkerInfo {
 	start := time.Now()
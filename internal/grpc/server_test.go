@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/garnizeh/englog/internal/grpc"
+	"github.com/garnizeh/englog/internal/models"
 	workerpb "github.com/garnizeh/englog/proto/worker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -452,6 +453,81 @@ func TestServer_ReportTaskResult(t *testing.T) {
 	}
 }
 
+// TestServer_ResultListener tests that outcomes of queued tasks reach the result listener
+func TestServer_ResultListener(t *testing.T) {
+	server := grpc.NewServer(createTestConfig(), createTestLoggerForManager())
+	ctx := context.Background()
+
+	outcomes := make(chan models.WorkerTaskOutcome, 4)
+	server.AddResultListener(func(_ context.Context, outcome models.WorkerTaskOutcome) {
+		outcomes <- outcome
+	})
+	// Every listener hears about every outcome
+	others := make(chan models.WorkerTaskOutcome, 4)
+	server.AddResultListener(func(_ context.Context, outcome models.WorkerTaskOutcome) {
+		others <- outcome
+	})
+
+	for _, taskID := range []string{"insight-1", "insight-2"} {
+		require.NoError(t, server.QueueTask(ctx, &workerpb.TaskRequest{
+			TaskId:   taskID,
+			TaskType: workerpb.TaskType_TASK_TYPE_INSIGHT_GENERATION,
+			Metadata: map[string]string{"user_id": "user-1"},
+		}))
+	}
+
+	report := func(taskID string, taskStatus workerpb.TaskStatus, result, errorMessage string) {
+		_, err := server.ReportTaskResult(ctx, &workerpb.TaskResultRequest{
+			TaskId:       taskID,
+			WorkerId:     "worker-001",
+			Status:       taskStatus,
+			Result:       result,
+			ErrorMessage: errorMessage,
+		})
+		require.NoError(t, err)
+	}
+	receive := func() models.WorkerTaskOutcome {
+		select {
+		case outcome := <-outcomes:
+			return outcome
+		case <-time.After(time.Second):
+			t.Fatal("no task outcome received")
+			return models.WorkerTaskOutcome{}
+		}
+	}
+
+	report("insight-1", workerpb.TaskStatus_TASK_STATUS_COMPLETED, `{"content":"ok"}`, "")
+	assert.Equal(t, models.WorkerTaskOutcome{
+		TaskID:   "insight-1",
+		UserID:   "user-1",
+		TaskType: "insight_generation",
+		Result:   `{"content":"ok"}`,
+	}, receive())
+
+	report("insight-2", workerpb.TaskStatus_TASK_STATUS_FAILED, "", "model unavailable")
+	failed := receive()
+	assert.True(t, failed.Failed)
+	assert.Equal(t, "model unavailable", failed.Error)
+
+	for _, taskID := range []string{"insight-1", "insight-2"} {
+		select {
+		case outcome := <-others:
+			assert.Contains(t, []string{"insight-1", "insight-2"}, outcome.TaskID, taskID)
+		case <-time.After(time.Second):
+			t.Fatal("second listener received no task outcome")
+		}
+	}
+
+	// Results of tasks not queued here, or reported twice, are not passed on
+	report("unknown", workerpb.TaskStatus_TASK_STATUS_COMPLETED, "{}", "")
+	report("insight-1", workerpb.TaskStatus_TASK_STATUS_COMPLETED, "{}", "")
+	select {
+	case outcome := <-outcomes:
+		t.Fatalf("unexpected task outcome: %+v", outcome)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestServer_UpdateTaskProgress tests task progress updates
 func TestServer_UpdateTaskProgress(t *testing.T) {
 	cfg := createTestConfig()
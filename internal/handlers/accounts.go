@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler handles HTTP requests for full account exports and imports
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler instance
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// ExportAccount handles POST /v1/users/export
+func (h *AccountHandler) ExportAccount(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	filename := "englog-account-" + time.Now().Format(time.DateOnly) + ".zip"
+	streamDownload(c, "account", filename, "application/zip", func(w io.Writer) error {
+		return h.accountService.ExportAccount(c.Request.Context(), userID, w)
+	})
+}
+
+// ImportAccount handles POST /v1/users/import (multipart form field "archive";
+// dry_run=true reports what the import would do without changing the account)
+func (h *AccountHandler) ImportAccount(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid dry_run parameter", "dry_run must be true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.accountService.MaxArchiveBytes()+multipartOverheadBytes)
+
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			RespondWithError(c, http.StatusRequestEntityTooLarge, "Archive too large")
+			return
+		}
+		RespondWithError(c, http.StatusBadRequest, "Missing archive", err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to read archive", err.Error())
+		return
+	}
+	defer file.Close()
+
+	report, err := h.accountService.ImportAccount(c.Request.Context(), userID, file, fileHeader.Size, dryRun)
+	if err != nil {
+		status := ErrorStatus(err)
+		if strings.Contains(err.Error(), "byte limit") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		RespondWithError(c, status, "Failed to import account", err.Error())
+		return
+	}
+
+	message := "Account imported"
+	if dryRun {
+		message = "Dry run completed; nothing was imported"
+	}
+	RespondWithSuccess(c, http.StatusOK, report, message)
+}
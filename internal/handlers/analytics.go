@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/garnizeh/englog/internal/models"
@@ -32,17 +33,12 @@ func (h *AnalyticsHandler) GetProductivityMetrics(c *gin.Context) {
 		return
 	}
 
-	// Parse date range parameters
-	startDate, endDate, err := h.parseDateRange(c)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid date range",
-			"details": err.Error(),
-		})
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
 		return
 	}
 
-	metrics, err := h.analyticsService.GetProductivityMetrics(c.Request.Context(), userID.(string), startDate, endDate)
+	metrics, err := h.analyticsService.GetProductivityMetrics(c.Request.Context(), userID.(string), period.Start, period.End)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get productivity metrics",
@@ -52,11 +48,8 @@ func (h *AnalyticsHandler) GetProductivityMetrics(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": metrics,
-		"period": gin.H{
-			"start_date": startDate.Format("2006-01-02"),
-			"end_date":   endDate.Format("2006-01-02"),
-		},
+		"data":   metrics,
+		"period": period.response(),
 	})
 }
 
@@ -70,59 +63,462 @@ func (h *AnalyticsHandler) GetActivitySummary(c *gin.Context) {
 		return
 	}
 
-	// Parse date range parameters
-	startDate, endDate, err := h.parseDateRange(c)
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	summary, err := h.analyticsService.GetActivitySummary(c.Request.Context(), userID.(string), period.Start, period.End)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get activity summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   summary,
+		"period": period.response(),
+	})
+}
+
+// GetWeeklySummary handles GET /v1/analytics/weekly
+func (h *AnalyticsHandler) GetWeeklySummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	summaries, err := h.analyticsService.GetWeeklyActivitySummary(c.Request.Context(), userID.(string), period.Start, period.End, period.Settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get weekly summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   summaries,
+		"period": period.response(),
+	})
+}
+
+// GetMonthlySummary handles GET /v1/analytics/monthly
+func (h *AnalyticsHandler) GetMonthlySummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	summaries, err := h.analyticsService.GetMonthlyActivitySummary(c.Request.Context(), userID.(string), period.Start, period.End, period.Settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get monthly summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   summaries,
+		"period": period.response(),
+	})
+}
+
+// GetHeatmap handles GET /v1/analytics/heatmap
+func (h *AnalyticsHandler) GetHeatmap(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	heatmap, err := h.analyticsService.GetActivityHeatmap(c.Request.Context(), userID.(string), period.Start, period.End, period.Settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get activity heatmap",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   heatmap,
+		"period": period.response(),
+	})
+}
+
+// GetTopProjects handles GET /v1/analytics/projects/top
+func (h *AnalyticsHandler) GetTopProjects(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	limit := defaultTopProjectsLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxTopProjectsLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit",
+				"details": fmt.Sprintf("limit must be between 1 and %d", maxTopProjectsLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	projects, err := h.analyticsService.GetTopProjectsByTime(c.Request.Context(), userID.(string), period.Start, period.End, int32(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get top projects",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   projects,
+		"period": period.response(),
+	})
+}
+
+// GetComparison handles GET /v1/analytics/compare
+func (h *AnalyticsHandler) GetComparison(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	previous, err := period.previous(c.DefaultQuery("compare_to", "previous_period"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid date range",
+			"error":   "Invalid comparison period",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	summary, err := h.analyticsService.GetActivitySummary(c.Request.Context(), userID.(string), startDate, endDate)
+	stats, err := h.analyticsService.GetComparisonStats(c.Request.Context(), userID.(string), period.Start, period.End, previous.Start, previous.End)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get activity summary",
+			"error":   "Failed to get comparison stats",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":            stats,
+		"period":          period.response(),
+		"previous_period": previous.response(),
+	})
+}
+
+// GetTrend handles GET /v1/analytics/trend
+func (h *AnalyticsHandler) GetTrend(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	trend, err := h.analyticsService.GetProductivityTrend(c.Request.Context(), userID.(string), period.Start, period.End, period.Settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get productivity trend",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   trend,
+		"period": period.response(),
+	})
+}
+
+// GetFocus handles GET /v1/analytics/focus
+func (h *AnalyticsHandler) GetFocus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	focus, err := h.analyticsService.GetFocusMetrics(c.Request.Context(), userID.(string), period.Start, period.End, period.Settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get focus metrics",
 			"details": err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": summary,
-		"period": gin.H{
-			"start_date": startDate.Format("2006-01-02"),
-			"end_date":   endDate.Format("2006-01-02"),
-		},
+		"data":   focus,
+		"period": period.response(),
 	})
 }
 
-// parseDateRange parses date range parameters from query string
-func (h *AnalyticsHandler) parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
-	// Default to last 30 days
-	endDate := time.Now()
-	startDate := endDate.AddDate(0, 0, -30)
+// GetProjectStats handles GET /v1/projects/:id/stats
+func (h *AnalyticsHandler) GetProjectStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
 
-	if startDateStr := c.Query("start_date"); startDateStr != "" {
+	location, err := parseTimezone(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	stats, err := h.analyticsService.GetProjectStats(c.Request.Context(), userID.(string), c.Param("id"), location)
+	if err != nil {
+		c.JSON(ErrorStatus(err), gin.H{
+			"error":   "Failed to get project stats",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
+
+// GetTeamAnalytics handles GET /v1/teams/:id/analytics
+func (h *AnalyticsHandler) GetTeamAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	period, ok := h.parseDateRange(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	analytics, err := h.analyticsService.GetTeamAnalytics(c.Request.Context(), userID.(string), c.Param("id"), period.Start, period.End)
+	if err != nil {
+		c.JSON(ErrorStatus(err), gin.H{
+			"error":   "Failed to get team analytics",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   analytics,
+		"period": period.response(),
+	})
+}
+
+const (
+	// maxAnalyticsRangeDays bounds every analytics query to a year, leap years included
+	maxAnalyticsRangeDays   = 366
+	defaultAnalyticsDays    = 30
+	defaultTopProjectsLimit = 10
+	maxTopProjectsLimit     = 50
+)
+
+// analyticsPeriod is an inclusive range of calendar days in the timezone of its settings.
+// Start is midnight of the first day and End is the last instant of the final day.
+type analyticsPeriod struct {
+	Start    time.Time
+	End      time.Time
+	Settings *models.AnalyticsSettings
+}
+
+// response renders the period for API responses
+func (p analyticsPeriod) response() gin.H {
+	return gin.H{
+		"start_date": p.Start.Format("2006-01-02"),
+		"end_date":   p.End.Format("2006-01-02"),
+		"timezone":   p.Settings.Location.String(),
+		"week_start": p.Settings.WeekStart,
+	}
+}
+
+// previous returns the period a comparison is made against: either the equally long
+// window right before this one, or the same dates one year earlier
+func (p analyticsPeriod) previous(compareTo string) (analyticsPeriod, error) {
+	switch compareTo {
+	case "previous_period":
+		days := calendarDays(p.Start, p.End)
+		start := p.Start.AddDate(0, 0, -days)
+		return analyticsPeriod{Start: start, End: p.Start.Add(-time.Nanosecond), Settings: p.Settings}, nil
+	case "previous_year":
+		start := p.Start.AddDate(-1, 0, 0)
+		end := endOfDay(startOfDay(p.End).AddDate(-1, 0, 0))
+		return analyticsPeriod{Start: start, End: end, Settings: p.Settings}, nil
+	}
+	return analyticsPeriod{}, fmt.Errorf("compare_to must be previous_period or previous_year")
+}
+
+// parseDateRange parses the start_date, end_date and tz query parameters shared by
+// all analytics endpoints and responds with an error when they are invalid. Dates are
+// calendar days in tz, or the user's own timezone when tz is omitted; the end date is
+// inclusive and the range defaults to the last 30 days.
+func (h *AnalyticsHandler) parseDateRange(c *gin.Context, userID string) (analyticsPeriod, bool) {
+	override, err := parseTimezone(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date range",
+			"details": err.Error(),
+		})
+		return analyticsPeriod{}, false
+	}
+
+	settings, err := h.analyticsService.ResolveSettings(c.Request.Context(), userID, override)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to resolve analytics settings",
+			"details": err.Error(),
+		})
+		return analyticsPeriod{}, false
+	}
+
+	period, err := parseAnalyticsPeriod(c.Query("start_date"), c.Query("end_date"), settings, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date range",
+			"details": err.Error(),
+		})
+		return analyticsPeriod{}, false
+	}
+
+	return period, true
+}
+
+// parseTimezone loads an optional IANA timezone, returning nil when tz is empty
+func parseTimezone(tz string) (*time.Location, error) {
+	if tz == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz: %q is not an IANA timezone", tz)
+	}
+	return loc, nil
+}
+
+// parseAnalyticsPeriod builds an analytics period from raw query values relative to now
+func parseAnalyticsPeriod(startDateStr, endDateStr string, settings *models.AnalyticsSettings, now time.Time) (analyticsPeriod, error) {
+	loc := settings.Location
+
+	endDay := startOfDay(now.In(loc))
+	startDay := endDay.AddDate(0, 0, -defaultAnalyticsDays)
+
+	if startDateStr != "" {
 		if err := models.ValidateDateFormat(startDateStr); err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date format: %v", err)
+			return analyticsPeriod{}, fmt.Errorf("invalid start_date format: %v", err)
 		}
-		parsed, _ := time.Parse("2006-01-02", startDateStr)
-		startDate = parsed
+		startDay, _ = time.ParseInLocation("2006-01-02", startDateStr, loc)
 	}
 
-	if endDateStr := c.Query("end_date"); endDateStr != "" {
+	if endDateStr != "" {
 		if err := models.ValidateDateFormat(endDateStr); err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date format: %v", err)
+			return analyticsPeriod{}, fmt.Errorf("invalid end_date format: %v", err)
 		}
-		parsed, _ := time.Parse("2006-01-02", endDateStr)
-		endDate = parsed
+		endDay, _ = time.ParseInLocation("2006-01-02", endDateStr, loc)
+	}
+
+	if endDay.Before(startDay) {
+		return analyticsPeriod{}, fmt.Errorf("end_date must be after start_date")
 	}
 
-	if endDate.Before(startDate) {
-		return time.Time{}, time.Time{}, fmt.Errorf("end_date must be after start_date")
+	if calendarDays(startDay, endDay) > maxAnalyticsRangeDays {
+		return analyticsPeriod{}, fmt.Errorf("date range cannot exceed %d days", maxAnalyticsRangeDays)
 	}
 
-	return startDate, endDate, nil
+	return analyticsPeriod{Start: startDay, End: endOfDay(endDay), Settings: settings}, nil
+}
+
+// calendarDays counts the days from start to end, both included
+func calendarDays(start, end time.Time) int {
+	startUTC := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endUTC := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(endUTC.Sub(startUTC).Hours()/24) + 1
+}
+
+// startOfDay returns midnight of t's calendar day in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns midnight of the first day of t's week when weeks start on weekStart
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+// endOfDay returns the last instant of t's calendar day in t's location
+func endOfDay(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/garnizeh/englog/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -175,6 +176,62 @@ func TestAnalyticsHandler_ErrorHandling(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "Invalid date range",
 		},
+		{
+			name:           "unknown timezone in weekly summary",
+			path:           "/v1/analytics/weekly?tz=Mars/Olympus_Mons",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid date range",
+		},
+		{
+			name:           "range longer than a year in heatmap",
+			path:           "/v1/analytics/heatmap?start_date=2023-01-01&end_date=2024-12-31",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid date range",
+		},
+		{
+			name:           "limit out of bounds in top projects",
+			path:           "/v1/analytics/projects/top?limit=500",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid limit",
+		},
+		{
+			name:           "unknown comparison period",
+			path:           "/v1/analytics/compare?compare_to=last_decade",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid comparison period",
+		},
+		{
+			name:           "unknown timezone in focus metrics",
+			path:           "/v1/analytics/focus?tz=Nowhere/Land",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid date range",
+		},
+		{
+			name:           "unknown timezone in project stats",
+			path:           "/v1/projects/6f1b7c9e-2d4a-4c1e-9b7a-3e5f8d2c1a00/stats?tz=Nowhere/Land",
+			token:          "valid-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid timezone",
+		},
+		{
+			name:           "stats of an unknown project",
+			path:           "/v1/projects/6f1b7c9e-2d4a-4c1e-9b7a-3e5f8d2c1a00/stats",
+			token:          "valid-token",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Failed to get project stats",
+		},
+		{
+			name:           "unauthorized trend request",
+			path:           "/v1/analytics/trend",
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Unauthorized",
+		},
 	}
 
 	for _, tt := range tests {
@@ -206,3 +263,103 @@ func TestAnalyticsHandler_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestParseAnalyticsPeriod tests the date range parsing shared by all analytics endpoints
+func TestParseAnalyticsPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 15, 23, 30, 0, 0, time.UTC)
+	utc := &models.AnalyticsSettings{Location: time.UTC, WeekStart: models.WeekStartMonday}
+
+	t.Run("defaults to the last 30 days", func(t *testing.T) {
+		period, err := parseAnalyticsPeriod("", "", utc, now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC), period.Start)
+		assert.Equal(t, time.Date(2024, 3, 15, 23, 59, 59, 999999999, time.UTC), period.End)
+	})
+
+	t.Run("end date is inclusive and dates follow the timezone", func(t *testing.T) {
+		saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+		require.NoError(t, err)
+		settings := &models.AnalyticsSettings{Location: saoPaulo, WeekStart: models.WeekStartSunday}
+
+		period, err := parseAnalyticsPeriod("2024-03-01", "2024-03-10", settings, now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC), period.Start.UTC())
+		assert.Equal(t, time.Date(2024, 3, 11, 2, 59, 59, 999999999, time.UTC), period.End.UTC())
+
+		response := period.response()
+		assert.Equal(t, "2024-03-10", response["end_date"])
+		assert.Equal(t, "America/Sao_Paulo", response["timezone"])
+		assert.Equal(t, models.WeekStartSunday, response["week_start"])
+	})
+
+	t.Run("today depends on the timezone", func(t *testing.T) {
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+
+		period, err := parseAnalyticsPeriod("", "", &models.AnalyticsSettings{Location: tokyo}, now)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-03-16", period.End.Format("2006-01-02"))
+	})
+
+	t.Run("a leap year fits", func(t *testing.T) {
+		_, err := parseAnalyticsPeriod("2024-01-01", "2024-12-31", utc, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := parseAnalyticsPeriod("2024-01-01", "2025-01-01", utc, now)
+		assert.ErrorContains(t, err, "cannot exceed 366 days")
+
+		_, err = parseAnalyticsPeriod("2024-03-10", "2024-03-01", utc, now)
+		assert.ErrorContains(t, err, "end_date must be after start_date")
+
+		_, err = parseAnalyticsPeriod("03/01/2024", "", utc, now)
+		assert.ErrorContains(t, err, "invalid start_date format")
+	})
+}
+
+// TestParseTimezone tests the optional tz override
+func TestParseTimezone(t *testing.T) {
+	loc, err := parseTimezone("")
+	require.NoError(t, err)
+	assert.Nil(t, loc, "no override keeps the user's timezone")
+
+	loc, err = parseTimezone("Europe/Lisbon")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Lisbon", loc.String())
+
+	_, err = parseTimezone("Not/AZone")
+	assert.ErrorContains(t, err, "invalid tz")
+}
+
+// TestStartOfWeek tests week boundaries for both week start preferences
+func TestStartOfWeek(t *testing.T) {
+	wednesday := time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), startOfWeek(wednesday, time.Monday))
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), startOfWeek(wednesday, time.Sunday))
+
+	sunday := time.Date(2024, 3, 17, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), startOfWeek(sunday, time.Monday))
+	assert.Equal(t, time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC), startOfWeek(sunday, time.Sunday))
+}
+
+// TestAnalyticsPeriod_Previous tests the periods used by /v1/analytics/compare
+func TestAnalyticsPeriod_Previous(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	period, err := parseAnalyticsPeriod("2024-03-01", "2024-03-10", &models.AnalyticsSettings{Location: time.UTC}, now)
+	require.NoError(t, err)
+
+	previous, err := period.previous("previous_period")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-20", previous.Start.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-29", previous.End.Format("2006-01-02"))
+	assert.Equal(t, period.Start, previous.End.Add(time.Nanosecond))
+
+	lastYear, err := period.previous("previous_year")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-03-01", lastYear.Start.Format("2006-01-02"))
+	assert.Equal(t, time.Date(2023, 3, 10, 23, 59, 59, 999999999, time.UTC), lastYear.End)
+
+	_, err = period.previous("yesterday")
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverheadBytes is the allowance for multipart headers on top of the file size
+const multipartOverheadBytes = 64 * 1024
+
+// AttachmentHandler handles HTTP requests for log entry attachments
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler instance
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment handles POST /v1/logs/:id/attachments (multipart form field "file")
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxBytes()+multipartOverheadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			RespondWithError(c, http.StatusRequestEntityTooLarge, "Attachment too large")
+			return
+		}
+		RespondWithError(c, http.StatusBadRequest, "Missing file", err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to read file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), userID, c.Param("id"),
+		fileHeader.Filename, fileHeader.Header.Get("Content-Type"), file)
+	if err != nil {
+		status := ErrorStatus(err)
+		if strings.Contains(err.Error(), "exceeds maximum size") || strings.Contains(err.Error(), "quota exceeded") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		RespondWithError(c, status, "Failed to upload attachment", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, attachment, "Attachment uploaded successfully")
+}
+
+// GetAttachments handles GET /v1/logs/:id/attachments
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get attachments", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, attachments)
+}
+
+// DownloadAttachment handles GET /v1/logs/:id/attachments/:attachment_id
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	attachment, content, err := h.attachmentService.Download(c.Request.Context(), userID, c.Param("id"), c.Param("attachment_id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to download attachment", err.Error())
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment handles DELETE /v1/logs/:id/attachments/:attachment_id
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), userID, c.Param("id"), c.Param("attachment_id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete attachment", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Attachment deleted successfully")
+}
+
+// GetAttachmentUsage handles GET /v1/users/attachments/usage
+func (h *AttachmentHandler) GetAttachmentUsage(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	usage, err := h.attachmentService.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get attachment usage", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, usage)
+}
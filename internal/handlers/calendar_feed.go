@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/ical"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CalendarFeedHandler handles HTTP requests for the iCalendar activity feeds and
+// the secret tokens they are subscribed with
+type CalendarFeedHandler struct {
+	calendarFeedService *services.CalendarFeedService
+}
+
+// NewCalendarFeedHandler creates a new CalendarFeedHandler instance
+func NewCalendarFeedHandler(calendarFeedService *services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		calendarFeedService: calendarFeedService,
+	}
+}
+
+// CreateToken handles POST /v1/users/profile/calendar-feeds. The response holds
+// the token and feed URL, which are not shown again.
+func (h *CalendarFeedHandler) CreateToken(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CalendarFeedTokenRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+			return
+		}
+	}
+
+	created, err := h.calendarFeedService.CreateToken(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create calendar feed token", err.Error())
+		return
+	}
+	created.FeedURL = "/v1/feeds/" + created.Token + "/activities.ics"
+
+	RespondWithSuccess(c, http.StatusCreated, created)
+}
+
+// GetTokens handles GET /v1/users/profile/calendar-feeds
+func (h *CalendarFeedHandler) GetTokens(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tokens, err := h.calendarFeedService.GetTokens(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get calendar feed tokens", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// RevokeToken handles DELETE /v1/users/profile/calendar-feeds/:id
+func (h *CalendarFeedHandler) RevokeToken(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.calendarFeedService.RevokeToken(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to revoke calendar feed token", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Calendar feed token revoked successfully")
+}
+
+// GetFeed handles GET /v1/feeds/:token/activities.ics, authenticated by the token
+// alone so calendar apps can subscribe. project_id and type may be repeated or
+// comma-separated; days sets how much history is served.
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	filters, err := parseCalendarFeedFilters(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid feed filters", err.Error())
+		return
+	}
+
+	calendar, err := h.calendarFeedService.Feed(c.Request.Context(), c.Param("token"), filters)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get calendar feed", err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, calendar); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to write calendar feed", err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// parseCalendarFeedFilters reads the feed filters from the query string
+func parseCalendarFeedFilters(c *gin.Context) (*models.CalendarFeedFilters, error) {
+	filters := &models.CalendarFeedFilters{}
+
+	for _, value := range splitQueryValues(c.QueryArray("project_id")) {
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project_id: %s", value)
+		}
+		filters.ProjectIDs = append(filters.ProjectIDs, projectID)
+	}
+
+	for _, value := range splitQueryValues(c.QueryArray("type")) {
+		if !models.ValidateActivityType(value) {
+			return nil, fmt.Errorf("invalid activity type: %s", value)
+		}
+		filters.Types = append(filters.Types, models.ActivityType(value))
+	}
+
+	if value := c.Query("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("invalid days: %s", value)
+		}
+		filters.Days = days
+	}
+
+	return filters, nil
+}
+
+// splitQueryValues splits comma-separated query values, dropping empty ones
+func splitQueryValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CalendarImportHandler handles HTTP requests for importing meetings from calendar files
+type CalendarImportHandler struct {
+	calendarImportService *services.CalendarImportService
+}
+
+// NewCalendarImportHandler creates a new CalendarImportHandler instance
+func NewCalendarImportHandler(calendarImportService *services.CalendarImportService) *CalendarImportHandler {
+	return &CalendarImportHandler{
+		calendarImportService: calendarImportService,
+	}
+}
+
+// ImportCalendar handles POST /v1/import/ics (multipart form: an iCalendar file in
+// the field "file", plus the optional period, calendar email and project)
+func (h *CalendarImportHandler) ImportCalendar(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.calendarImportService.MaxFileBytes()+multipartOverheadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			RespondWithError(c, http.StatusRequestEntityTooLarge, "Calendar file too large")
+			return
+		}
+		RespondWithError(c, http.StatusBadRequest, "Missing calendar file", err.Error())
+		return
+	}
+
+	req := &models.CalendarImportRequest{
+		StartDate: c.PostForm("start"),
+		EndDate:   c.PostForm("end"),
+		Email:     c.PostForm("email"),
+	}
+	if value := c.PostForm("project_id"); value != "" {
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid project ID", err.Error())
+			return
+		}
+		req.ProjectID = &projectID
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to read calendar file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, err := h.calendarImportService.ImportCalendar(c.Request.Context(), userID, file, req)
+	if err != nil {
+		status := ErrorStatus(err)
+		if strings.Contains(err.Error(), "byte limit") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		RespondWithError(c, status, "Failed to import calendar", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, result)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CSVImportHandler handles HTTP requests for importing time tracker CSV exports
+// and managing the column mapping profiles they are imported with
+type CSVImportHandler struct {
+	csvImportService *services.CSVImportService
+}
+
+// NewCSVImportHandler creates a new CSVImportHandler instance
+func NewCSVImportHandler(csvImportService *services.CSVImportService) *CSVImportHandler {
+	return &CSVImportHandler{
+		csvImportService: csvImportService,
+	}
+}
+
+// ImportCSV handles POST /v1/import/csv (multipart form: a CSV file in the field
+// "file", plus one of profile_id, preset or a JSON mapping, and preview=true to
+// check the rows without creating entries)
+func (h *CSVImportHandler) ImportCSV(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.csvImportService.MaxFileBytes()+multipartOverheadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			RespondWithError(c, http.StatusRequestEntityTooLarge, "CSV file too large")
+			return
+		}
+		RespondWithError(c, http.StatusBadRequest, "Missing CSV file", err.Error())
+		return
+	}
+
+	req := &models.CSVImportRequest{
+		Preset: c.PostForm("preset"),
+	}
+	if value := c.PostForm("profile_id"); value != "" {
+		profileID, err := uuid.Parse(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid profile ID", err.Error())
+			return
+		}
+		req.ProfileID = &profileID
+	}
+	if value := c.PostForm("mapping"); value != "" {
+		var mapping models.CSVColumnMapping
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid column mapping", err.Error())
+			return
+		}
+		req.Mapping = &mapping
+	}
+	if value := c.PostForm("preview"); value != "" {
+		preview, err := strconv.ParseBool(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid preview flag", err.Error())
+			return
+		}
+		req.Preview = preview
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to read CSV file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, err := h.csvImportService.ImportCSV(c.Request.Context(), userID, file, req)
+	if err != nil {
+		status := ErrorStatus(err)
+		if strings.Contains(err.Error(), "byte limit") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		RespondWithError(c, status, "Failed to import CSV file", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, result)
+}
+
+// GetPresets handles GET /v1/import/csv/presets
+func (h *CSVImportHandler) GetPresets(c *gin.Context) {
+	RespondWithSuccess(c, http.StatusOK, h.csvImportService.GetPresets())
+}
+
+// CreateProfile handles POST /v1/import/csv/profiles
+func (h *CSVImportHandler) CreateProfile(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CSVImportProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	profile, err := h.csvImportService.CreateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create CSV import profile", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, profile)
+}
+
+// GetProfiles handles GET /v1/import/csv/profiles
+func (h *CSVImportHandler) GetProfiles(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	profiles, err := h.csvImportService.GetProfiles(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get CSV import profiles", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, profiles)
+}
+
+// GetProfile handles GET /v1/import/csv/profiles/:id
+func (h *CSVImportHandler) GetProfile(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	profile, err := h.csvImportService.GetProfile(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get CSV import profile", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, profile)
+}
+
+// UpdateProfile handles PUT /v1/import/csv/profiles/:id
+func (h *CSVImportHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CSVImportProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	profile, err := h.csvImportService.UpdateProfile(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update CSV import profile", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, profile, "CSV import profile updated successfully")
+}
+
+// DeleteProfile handles DELETE /v1/import/csv/profiles/:id
+func (h *CSVImportHandler) DeleteProfile(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.csvImportService.DeleteProfile(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete CSV import profile", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "CSV import profile deleted successfully")
+}
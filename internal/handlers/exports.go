@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler handles HTTP requests for data exports
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler instance
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportLogEntries handles GET /v1/export/logs
+func (h *ExportHandler) ExportLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filter := &models.LogEntryExportFilter{
+		StartDate: c.Query("start"),
+		EndDate:   c.Query("end"),
+		ProjectID: c.Query("project_id"),
+	}
+
+	streamExport(c, "log entries", format, func(w io.Writer) error {
+		return h.exportService.ExportLogEntries(c.Request.Context(), userID, format, filter, w)
+	})
+}
+
+// ExportProjects handles GET /v1/export/projects
+func (h *ExportHandler) ExportProjects(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	streamExport(c, "projects", format, func(w io.Writer) error {
+		return h.exportService.ExportProjects(c.Request.Context(), userID, format, w)
+	})
+}
+
+// ExportTagUsage handles GET /v1/export/tags
+func (h *ExportHandler) ExportTagUsage(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	streamExport(c, "tag usage", format, func(w io.Writer) error {
+		return h.exportService.ExportTagUsage(c.Request.Context(), userID, format, w)
+	})
+}
+
+// exportFormat reads the format query parameter, csv by default, and responds
+// with 400 when it is not supported
+func exportFormat(c *gin.Context) (models.ExportFormat, bool) {
+	format := models.ExportFormat(c.DefaultQuery("format", string(models.ExportCSV)))
+	if !format.IsValid() {
+		RespondWithError(c, http.StatusBadRequest, "Invalid format", "format must be csv or jsonl")
+		return "", false
+	}
+	return format, true
+}
+
+// streamExport serves an export as a dated file download in the given format
+func streamExport(c *gin.Context, name string, format models.ExportFormat, export func(w io.Writer) error) {
+	filename := "englog-" + strings.ReplaceAll(name, " ", "-") + "-" + time.Now().Format(time.DateOnly) + "." + string(format)
+	streamDownload(c, name, filename, format.ContentType(), export)
+}
+
+// streamDownload serves a generated file as a download. Errors raised before the
+// first byte is written get a regular JSON error response; later ones can only
+// cut the download short, so they are logged and the response is aborted.
+func streamDownload(c *gin.Context, name, filename, contentType string, export func(w io.Writer) error) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	err := export(c.Writer)
+	if err == nil {
+		return
+	}
+
+	if c.Writer.Written() {
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	RespondWithError(c, ErrorStatus(err), "Failed to export "+name, err.Error())
+}
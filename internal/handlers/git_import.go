@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GitImportHandler handles HTTP requests for proposing log entries from git history
+type GitImportHandler struct {
+	gitImportService *services.GitImportService
+}
+
+// NewGitImportHandler creates a new GitImportHandler instance
+func NewGitImportHandler(gitImportService *services.GitImportService) *GitImportHandler {
+	return &GitImportHandler{
+		gitImportService: gitImportService,
+	}
+}
+
+// ProposeGitLogEntries handles POST /v1/logs/import/git (multipart form: the output
+// of git log --numstat in the field "log", plus the repository name and author email)
+func (h *GitImportHandler) ProposeGitLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.gitImportService.MaxLogBytes()+multipartOverheadBytes)
+
+	fileHeader, err := c.FormFile("log")
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			RespondWithError(c, http.StatusRequestEntityTooLarge, "Git log too large")
+			return
+		}
+		RespondWithError(c, http.StatusBadRequest, "Missing git log", err.Error())
+		return
+	}
+
+	req := &models.GitImportRequest{
+		Repository:  c.PostForm("repository"),
+		AuthorEmail: c.PostForm("author_email"),
+		StartDate:   c.PostForm("start"),
+		EndDate:     c.PostForm("end"),
+	}
+	if value := c.PostForm("gap_minutes"); value != "" {
+		req.GapMinutes, err = strconv.Atoi(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid gap_minutes parameter", "gap_minutes must be a number of minutes")
+			return
+		}
+	}
+	if value := c.PostForm("project_id"); value != "" {
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid project ID", err.Error())
+			return
+		}
+		req.ProjectID = &projectID
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to read git log", err.Error())
+		return
+	}
+	defer file.Close()
+
+	proposal, err := h.gitImportService.ProposeLogEntries(c.Request.Context(), userID, file, req)
+	if err != nil {
+		status := ErrorStatus(err)
+		if strings.Contains(err.Error(), "byte limit") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		RespondWithError(c, status, "Failed to propose log entries", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, proposal)
+}
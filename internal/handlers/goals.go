@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GoalHandler handles HTTP requests for personal goals and OKRs
+type GoalHandler struct {
+	goalService *services.GoalService
+}
+
+// NewGoalHandler creates a new GoalHandler instance
+func NewGoalHandler(goalService *services.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// CreateGoal handles POST /v1/goals
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	goal, err := h.goalService.CreateGoal(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create goal", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, goal)
+}
+
+// GetGoals handles GET /v1/goals
+func (h *GoalHandler) GetGoals(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	status := models.GoalStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		RespondWithError(c, http.StatusBadRequest, "Invalid status", "status must be active, completed or archived")
+		return
+	}
+
+	goals, err := h.goalService.GetGoals(c.Request.Context(), userID, status)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get goals", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, goals)
+}
+
+// GetGoal handles GET /v1/goals/:id
+func (h *GoalHandler) GetGoal(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	goal, err := h.goalService.GetGoal(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusNotFound, "Goal not found")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, goal)
+}
+
+// UpdateGoal handles PUT /v1/goals/:id
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	goal, err := h.goalService.UpdateGoal(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update goal", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, goal, "Goal updated successfully")
+}
+
+// DeleteGoal handles DELETE /v1/goals/:id
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.goalService.DeleteGoal(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete goal", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Goal deleted successfully")
+}
+
+// GetGoalProgress handles GET /v1/goals/:id/progress
+func (h *GoalHandler) GetGoalProgress(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	location, err := parseTimezone(c.Query("tz"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid timezone", err.Error())
+		return
+	}
+
+	progress, err := h.goalService.GetGoalProgress(c.Request.Context(), userID, c.Param("id"), c.Query("date"), location)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get goal progress", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, progress)
+}
+
+// GetGoalsProgress handles GET /v1/goals/progress
+func (h *GoalHandler) GetGoalsProgress(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	location, err := parseTimezone(c.Query("tz"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid timezone", err.Error())
+		return
+	}
+
+	progress, err := h.goalService.GetActiveGoalsProgress(c.Request.Context(), userID, c.Query("date"), location)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get goals progress", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, progress)
+}
@@ -140,8 +140,8 @@ func createTestLogEntry(t *testing.T, logEntryService *services.LogEntryService,
 	return logEntry
 }
 
-// createTestTag creates a test tag and returns it
-func createTestTag(t *testing.T, tagService *services.TagService) *models.Tag {
+// createTestTag creates a test tag owned by the user and returns it
+func createTestTag(t *testing.T, tagService *services.TagService, userID string) *models.Tag {
 	t.Helper()
 
 	ctx := context.Background()
@@ -151,12 +151,12 @@ func createTestTag(t *testing.T, tagService *services.TagService) *models.Tag {
 		Description: stringPtr("A test tag"),
 	}
 
-	tag, err := tagService.CreateTag(ctx, tagReq)
+	tag, err := tagService.CreateTag(ctx, userID, tagReq)
 	require.NoError(t, err)
 
 	// Cleanup on test end
 	t.Cleanup(func() {
-		_ = tagService.DeleteTag(ctx, tag.ID.String())
+		_ = tagService.DeleteTag(ctx, userID, tag.ID.String())
 	})
 
 	return tag
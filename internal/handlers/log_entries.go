@@ -276,6 +276,7 @@ func (h *LogEntryHandler) parseLogEntryFilters(c *gin.Context) (*services.LogEnt
 	if tagsStr := c.Query("tags"); tagsStr != "" {
 		filters.Tags = strings.Split(tagsStr, ",")
 	}
+	filters.IncludeTagDescendants = c.Query("include_descendants") == "true"
 
 	return filters, nil
 }
@@ -327,3 +328,103 @@ func (h *LogEntryHandler) paginate(entries []*models.LogEntry, page, limit int)
 
 	return paginatedEntries, pagination
 }
+
+// BulkUpdateLogEntries handles POST /v1/logs/bulk/update
+func (h *LogEntryHandler) BulkUpdateLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.BulkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.logEntryService.BulkUpdateLogEntries(c.Request.Context(), userID, &req)
+	respondWithBulkResult(c, result, err)
+}
+
+// BulkDeleteLogEntries handles POST /v1/logs/bulk/delete
+func (h *LogEntryHandler) BulkDeleteLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.logEntryService.BulkDeleteLogEntries(c.Request.Context(), userID, &req)
+	respondWithBulkResult(c, result, err)
+}
+
+// BulkRetagLogEntries handles POST /v1/logs/bulk/retag
+func (h *LogEntryHandler) BulkRetagLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.BulkRetagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.logEntryService.BulkRetagLogEntries(c.Request.Context(), userID, &req)
+	respondWithBulkResult(c, result, err)
+}
+
+// BulkMoveLogEntries handles POST /v1/logs/bulk/move
+func (h *LogEntryHandler) BulkMoveLogEntries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.logEntryService.BulkMoveLogEntries(c.Request.Context(), userID, &req)
+	respondWithBulkResult(c, result, err)
+}
+
+// respondWithBulkResult writes per-item results using the same status codes as
+// BulkCreateLogEntries: 200 when every item succeeded, 207 for a partial
+// best-effort run and 400 when nothing was applied
+func respondWithBulkResult(c *gin.Context, result *models.BulkResult, err error) {
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Bulk operation failed", err.Error())
+		return
+	}
+
+	statusCode := http.StatusOK
+	if !result.Committed {
+		statusCode = http.StatusBadRequest
+	} else if result.Failed > 0 {
+		statusCode = http.StatusMultiStatus
+	}
+
+	c.JSON(statusCode, gin.H{
+		"data": result.Results,
+		"summary": gin.H{
+			"mode":      result.Mode,
+			"committed": result.Committed,
+			"total":     result.Total,
+			"success":   result.Succeeded,
+			"errors":    result.Failed,
+		},
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLogEntryHistory handles GET /v1/logs/:id/history
+func (h *LogEntryHandler) GetLogEntryHistory(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	revisions, err := h.logEntryService.GetLogEntryHistory(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get log entry history", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, revisions)
+}
+
+// RestoreLogEntryRevision handles POST /v1/logs/:id/history/:revision/restore
+func (h *LogEntryHandler) RestoreLogEntryRevision(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		RespondWithError(c, http.StatusBadRequest, "Invalid revision number")
+		return
+	}
+
+	logEntry, err := h.logEntryService.RestoreLogEntryRevision(c.Request.Context(), userID, c.Param("id"), revision)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to restore revision", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, logEntry, "Log entry restored to revision "+strconv.Itoa(revision))
+}
+
+// GetTrash handles GET /v1/logs/trash
+func (h *LogEntryHandler) GetTrash(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	trashed, err := h.logEntryService.GetTrash(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get trash", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, trashed)
+}
+
+// RestoreFromTrash handles POST /v1/logs/trash/:id/restore
+func (h *LogEntryHandler) RestoreFromTrash(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	logEntry, err := h.logEntryService.RestoreFromTrash(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to restore log entry", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, logEntry, "Log entry restored from trash")
+}
+
+// PurgeFromTrash handles DELETE /v1/logs/trash/:id
+func (h *LogEntryHandler) PurgeFromTrash(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.logEntryService.PurgeFromTrash(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to purge log entry", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Log entry permanently deleted")
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LogTemplateHandler handles HTTP requests for recurring log templates
+type LogTemplateHandler struct {
+	logTemplateService *services.LogTemplateService
+}
+
+// NewLogTemplateHandler creates a new LogTemplateHandler instance
+func NewLogTemplateHandler(logTemplateService *services.LogTemplateService) *LogTemplateHandler {
+	return &LogTemplateHandler{
+		logTemplateService: logTemplateService,
+	}
+}
+
+// InstantiateTemplateRequest selects which occurrence to materialize
+type InstantiateTemplateRequest struct {
+	Date string `json:"date,omitempty"` // YYYY-MM-DD; empty materializes every due occurrence
+}
+
+// CreateTemplate handles POST /v1/logs/templates
+func (h *LogTemplateHandler) CreateTemplate(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.LogTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	template, err := h.logTemplateService.CreateTemplate(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Failed to create log template", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, template)
+}
+
+// GetTemplates handles GET /v1/logs/templates
+func (h *LogTemplateHandler) GetTemplates(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	templates, err := h.logTemplateService.GetTemplates(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get log templates", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, templates)
+}
+
+// GetTemplate handles GET /v1/logs/templates/:id
+func (h *LogTemplateHandler) GetTemplate(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	template, err := h.logTemplateService.GetTemplate(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusNotFound, "Log template not found")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, template)
+}
+
+// UpdateTemplate handles PUT /v1/logs/templates/:id
+func (h *LogTemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.LogTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	template, err := h.logTemplateService.UpdateTemplate(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update log template", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, template, "Log template updated successfully")
+}
+
+// DeleteTemplate handles DELETE /v1/logs/templates/:id
+func (h *LogTemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.logTemplateService.DeleteTemplate(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete log template", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Log template deleted successfully")
+}
+
+// GetOccurrences handles GET /v1/logs/templates/:id/occurrences
+func (h *LogTemplateHandler) GetOccurrences(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	startDate := c.DefaultQuery("start_date", today)
+	endDate := c.DefaultQuery("end_date", time.Now().AddDate(0, 0, 30).Format(time.DateOnly))
+
+	occurrences, err := h.logTemplateService.GetOccurrences(c.Request.Context(), userID, c.Param("id"), startDate, endDate)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get template occurrences", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, occurrences)
+}
+
+// InstantiateTemplate handles POST /v1/logs/templates/:id/instantiate
+func (h *LogTemplateHandler) InstantiateTemplate(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req InstantiateTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+			return
+		}
+	}
+
+	entries, err := h.logTemplateService.Instantiate(c.Request.Context(), userID, c.Param("id"), req.Date)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to instantiate log template", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, entries)
+}
+
+// SkipOccurrence handles POST /v1/logs/templates/:id/occurrences/:date/skip
+func (h *LogTemplateHandler) SkipOccurrence(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	occurrence, err := h.logTemplateService.SkipOccurrence(c.Request.Context(), userID, c.Param("id"), c.Param("date"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to skip occurrence", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, occurrence, "Occurrence skipped")
+}
+
+// EditOccurrence handles PUT /v1/logs/templates/:id/occurrences/:date
+func (h *LogTemplateHandler) EditOccurrence(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var overrides models.OccurrenceOverrides
+	if err := c.ShouldBindJSON(&overrides); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	occurrence, err := h.logTemplateService.EditOccurrence(c.Request.Context(), userID, c.Param("id"), c.Param("date"), &overrides)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to edit occurrence", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, occurrence, "Occurrence updated")
+}
+
+// ResetOccurrence handles DELETE /v1/logs/templates/:id/occurrences/:date
+func (h *LogTemplateHandler) ResetOccurrence(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.logTemplateService.ResetOccurrence(c.Request.Context(), userID, c.Param("id"), c.Param("date")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to reset occurrence", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Occurrence reset")
+}
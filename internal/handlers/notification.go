@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles HTTP requests for the email notifications of a user
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications handles GET /v1/users/profile/notifications, listing the
+// latest emails sent, pending or skipped
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get notifications", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, notifications)
+}
+
+// SendTestEmail handles POST /v1/users/profile/notifications/test. The email is
+// sent right away and the response tells whether the SMTP server accepted it.
+func (h *NotificationHandler) SendTestEmail(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	notification, err := h.notificationService.SendTestEmail(c.Request.Context(), userID)
+	if err != nil {
+		status := ErrorStatus(err)
+		if errors.Is(err, services.ErrNotificationsDisabled) {
+			status = http.StatusServiceUnavailable
+		}
+		RespondWithError(c, status, "Failed to send test email", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, notification)
+}
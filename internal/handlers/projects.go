@@ -72,7 +72,7 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 	RespondWithSuccess(c, 200, project, "Project retrieved successfully")
 }
 
-// GetProjects handles GET /v1/projects
+// GetProjects handles GET /v1/projects; archived projects are listed with ?include_archived=true
 func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -82,7 +82,9 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 		return
 	}
 
-	projects, err := h.projectService.GetUserProjects(c.Request.Context(), userID.(string))
+	includeArchived := c.Query("include_archived") == "true"
+
+	projects, err := h.projectService.GetUserProjects(c.Request.Context(), userID.(string), includeArchived)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get projects",
@@ -140,3 +142,37 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 
 	RespondWithSuccess(c, 200, nil, "Project deleted successfully")
 }
+
+// ArchiveProject handles POST /v1/projects/:id/archive
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		RespondWithError(c, 401, "Unauthorized")
+		return
+	}
+
+	project, err := h.projectService.ArchiveProject(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to archive project", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, 200, project, "Project archived successfully")
+}
+
+// UnarchiveProject handles POST /v1/projects/:id/unarchive
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		RespondWithError(c, 401, "Unauthorized")
+		return
+	}
+
+	project, err := h.projectService.UnarchiveProject(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to unarchive project", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, 200, project, "Project unarchived successfully")
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler handles HTTP requests for generated reports and brag documents
+type ReportHandler struct {
+	reportService  *services.ReportService
+	bragDocService *services.BragDocService
+}
+
+// NewReportHandler creates a new ReportHandler instance
+func NewReportHandler(reportService *services.ReportService, bragDocService *services.BragDocService) *ReportHandler {
+	return &ReportHandler{
+		reportService:  reportService,
+		bragDocService: bragDocService,
+	}
+}
+
+// RequestPerformanceReview handles POST /v1/reports/performance-review
+func (h *ReportHandler) RequestPerformanceReview(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.PerformanceReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	report, err := h.reportService.RequestPerformanceReview(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, reportErrorStatus(err), "Failed to request performance review", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusAccepted, report, "Performance review is being generated")
+}
+
+// GetReports handles GET /v1/reports
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	reports, err := h.reportService.ListReports(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get reports", err.Error())
+		return
+	}
+
+	for i := range reports {
+		withDownloadURL(&reports[i])
+	}
+	RespondWithSuccess(c, http.StatusOK, reports)
+}
+
+// GetReport handles GET /v1/reports/:id
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	report, err := h.reportService.GetReport(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get report", err.Error())
+		return
+	}
+
+	withDownloadURL(report)
+	RespondWithSuccess(c, http.StatusOK, report)
+}
+
+// DownloadReport handles GET /v1/reports/:id/download
+func (h *ReportHandler) DownloadReport(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	report, content, err := h.reportService.OpenReport(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, reportErrorStatus(err), "Failed to download report", err.Error())
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, report.SizeBytes, "application/pdf", content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": report.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// GetBragDoc handles GET /v1/reports/brag-doc
+func (h *ReportHandler) GetBragDoc(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req := &models.BragDocRequest{
+		Period:    models.BragDocPeriod(c.Query("period")),
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+		Template:  c.Query("template"),
+		Format:    models.BragDocFormat(c.Query("format")),
+	}
+	if summarize := c.Query("summarize"); summarize != "" {
+		value, err := strconv.ParseBool(summarize)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid summarize parameter", "summarize must be true or false")
+			return
+		}
+		req.Summarize = value
+	}
+
+	var buf bytes.Buffer
+	if err := h.bragDocService.RenderBragDoc(c.Request.Context(), userID, req, &buf); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to generate brag document", err.Error())
+		return
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, req.Format.ContentType(), buf.Bytes())
+}
+
+// GetBragDocTemplates handles GET /v1/reports/brag-doc/templates
+func (h *ReportHandler) GetBragDocTemplates(c *gin.Context) {
+	RespondWithSuccess(c, http.StatusOK, h.bragDocService.ListTemplates())
+}
+
+// withDownloadURL points a downloadable report at its download endpoint
+func withDownloadURL(report *models.ReportExport) {
+	if report.Downloadable() {
+		report.DownloadURL = "/v1/reports/" + report.ID.String() + "/download"
+	}
+}
+
+// reportErrorStatus maps report errors to HTTP status codes: 409 while a report is
+// still being generated, 410 once its download expired and 503 without workers
+func reportErrorStatus(err error) int {
+	switch msg := err.Error(); {
+	case strings.Contains(msg, "not ready"):
+		return http.StatusConflict
+	case strings.Contains(msg, "expired"):
+		return http.StatusGone
+	case strings.Contains(msg, "not available"):
+		return http.StatusServiceUnavailable
+	default:
+		return ErrorStatus(err)
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReportHandler_InvalidReportID tests that malformed report IDs are rejected by the router
+func TestReportHandler_InvalidReportID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, userService, _, _, _ := RouterWithServices(t)
+	user := createTestUser(t, userService)
+	token := loginUser(t, router, user.Email, "password123")
+
+	for _, path := range []string{"/v1/reports/invalid-uuid", "/v1/reports/invalid-uuid/download"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), "Invalid id format", path)
+	}
+}
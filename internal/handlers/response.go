@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -82,6 +83,18 @@ func GetUserIDFromContext(c *gin.Context) (string, bool) {
 	return userIDStr, ok
 }
 
+// ErrorStatus maps a service error to 404 when it reports a missing
+// resource, to 403 when a team role denies the action and to 400 otherwise
+func ErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	if strings.Contains(err.Error(), "permission denied") {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
 // TODO(rodrigo): plug it into the handlers (now is manually done in each handler)
 // RequireUserID middleware that ensures user ID exists in context
 func RequireUserID() gin.HandlerFunc {
@@ -20,10 +20,25 @@ func SetupRoutes(
 	redisClient *redis.Client,
 	authService *auth.AuthService,
 	logEntryService *services.LogEntryService,
+	logTemplateService *services.LogTemplateService,
+	attachmentService *services.AttachmentService,
+	suggestionService *services.SuggestionService,
 	projectService *services.ProjectService,
 	analyticsService *services.AnalyticsService,
+	goalService *services.GoalService,
+	teamService *services.TeamService,
 	tagService *services.TagService,
 	userService *services.UserService,
+	exportService *services.ExportService,
+	reportService *services.ReportService,
+	bragDocService *services.BragDocService,
+	accountService *services.AccountService,
+	gitImportService *services.GitImportService,
+	calendarImportService *services.CalendarImportService,
+	csvImportService *services.CSVImportService,
+	calendarFeedService *services.CalendarFeedService,
+	webhookService *services.WebhookService,
+	notificationService *services.NotificationService,
 	grpcManager *grpc.Manager,
 ) *gin.Engine {
 	r := gin.New() // Use gin.New() instead of gin.Default() for custom middleware
@@ -75,6 +90,10 @@ func SetupRoutes(
 		auth.GET("/me", authService.RequireAuth(), authService.MeHandler)
 	}
 
+	// Calendar feeds, authenticated by the secret token in the URL so calendar apps can subscribe
+	calendarFeedHandler := NewCalendarFeedHandler(calendarFeedService)
+	v1.GET("/feeds/:token/activities.ics", calendarFeedHandler.GetFeed)
+
 	// Protected endpoints
 	protected := v1.Group("/")
 	protected.Use(authService.RequireAuth())
@@ -89,10 +108,54 @@ func SetupRoutes(
 		logs.PUT("/:id", validator.ValidateUUIDParam("id"), logEntryHandler.UpdateLogEntry)
 		logs.DELETE("/:id", validator.ValidateUUIDParam("id"), logEntryHandler.DeleteLogEntry)
 		logs.POST("/bulk", logEntryHandler.BulkCreateLogEntries)
+		logs.POST("/bulk/update", logEntryHandler.BulkUpdateLogEntries)
+		logs.POST("/bulk/delete", logEntryHandler.BulkDeleteLogEntries)
+		logs.POST("/bulk/retag", logEntryHandler.BulkRetagLogEntries)
+		logs.POST("/bulk/move", logEntryHandler.BulkMoveLogEntries)
+		logs.GET("/:id/history", validator.ValidateUUIDParam("id"), logEntryHandler.GetLogEntryHistory)
+		logs.POST("/:id/history/:revision/restore", validator.ValidateUUIDParam("id"), logEntryHandler.RestoreLogEntryRevision)
+		logs.GET("/trash", logEntryHandler.GetTrash)
+		logs.POST("/trash/:id/restore", validator.ValidateUUIDParam("id"), logEntryHandler.RestoreFromTrash)
+		logs.DELETE("/trash/:id", validator.ValidateUUIDParam("id"), logEntryHandler.PurgeFromTrash)
+	}
+
+	// Log entry suggestions
+	suggestionHandler := NewSuggestionHandler(suggestionService)
+	logs.POST("/suggest", suggestionHandler.SuggestLogEntry)
+
+	// Log entry imports
+	gitImportHandler := NewGitImportHandler(gitImportService)
+	logs.POST("/import/git", gitImportHandler.ProposeGitLogEntries)
+
+	// Log entry attachments
+	attachmentHandler := NewAttachmentHandler(attachmentService)
+	attachments := logs.Group("/:id/attachments", validator.ValidateUUIDParam("id"))
+	{
+		attachments.POST("", attachmentHandler.UploadAttachment)
+		attachments.GET("", attachmentHandler.GetAttachments)
+		attachments.GET("/:attachment_id", validator.ValidateUUIDParam("attachment_id"), attachmentHandler.DownloadAttachment)
+		attachments.DELETE("/:attachment_id", validator.ValidateUUIDParam("attachment_id"), attachmentHandler.DeleteAttachment)
+	}
+
+	// Recurring log templates
+	logTemplateHandler := NewLogTemplateHandler(logTemplateService)
+	templates := logs.Group("/templates")
+	{
+		templates.POST("", logTemplateHandler.CreateTemplate)
+		templates.GET("", logTemplateHandler.GetTemplates)
+		templates.GET("/:id", validator.ValidateUUIDParam("id"), logTemplateHandler.GetTemplate)
+		templates.PUT("/:id", validator.ValidateUUIDParam("id"), logTemplateHandler.UpdateTemplate)
+		templates.DELETE("/:id", validator.ValidateUUIDParam("id"), logTemplateHandler.DeleteTemplate)
+		templates.POST("/:id/instantiate", validator.ValidateUUIDParam("id"), logTemplateHandler.InstantiateTemplate)
+		templates.GET("/:id/occurrences", validator.ValidateUUIDParam("id"), logTemplateHandler.GetOccurrences)
+		templates.PUT("/:id/occurrences/:date", validator.ValidateUUIDParam("id"), logTemplateHandler.EditOccurrence)
+		templates.DELETE("/:id/occurrences/:date", validator.ValidateUUIDParam("id"), logTemplateHandler.ResetOccurrence)
+		templates.POST("/:id/occurrences/:date/skip", validator.ValidateUUIDParam("id"), logTemplateHandler.SkipOccurrence)
 	}
 
-	// Projects
+	// Projects; stats are budget analytics served by the analytics handler
 	projectHandler := NewProjectHandler(projectService)
+	analyticsHandler := NewAnalyticsHandler(analyticsService)
 	projects := protected.Group("/projects")
 	{
 		projects.POST("", projectHandler.CreateProject)
@@ -100,18 +163,63 @@ func SetupRoutes(
 		projects.GET("/:id", validator.ValidateUUIDParam("id"), projectHandler.GetProject)
 		projects.PUT("/:id", validator.ValidateUUIDParam("id"), projectHandler.UpdateProject)
 		projects.DELETE("/:id", validator.ValidateUUIDParam("id"), projectHandler.DeleteProject)
+		projects.POST("/:id/archive", validator.ValidateUUIDParam("id"), projectHandler.ArchiveProject)
+		projects.POST("/:id/unarchive", validator.ValidateUUIDParam("id"), projectHandler.UnarchiveProject)
+		projects.GET("/:id/stats", validator.ValidateUUIDParam("id"), analyticsHandler.GetProjectStats)
 	}
 
 	// Analytics
-	analyticsHandler := NewAnalyticsHandler(analyticsService)
 	analytics := protected.Group("/analytics")
 	{
 		analytics.GET("/productivity", analyticsHandler.GetProductivityMetrics)
 		analytics.GET("/summary", analyticsHandler.GetActivitySummary)
+		analytics.GET("/weekly", analyticsHandler.GetWeeklySummary)
+		analytics.GET("/monthly", analyticsHandler.GetMonthlySummary)
+		analytics.GET("/heatmap", analyticsHandler.GetHeatmap)
+		analytics.GET("/projects/top", analyticsHandler.GetTopProjects)
+		analytics.GET("/compare", analyticsHandler.GetComparison)
+		analytics.GET("/trend", analyticsHandler.GetTrend)
+		analytics.GET("/focus", analyticsHandler.GetFocus)
 	}
 
-	// Tags
+	// Goals and OKRs
+	goalHandler := NewGoalHandler(goalService)
+	goals := protected.Group("/goals")
+	{
+		goals.POST("", goalHandler.CreateGoal)
+		goals.GET("", goalHandler.GetGoals)
+		goals.GET("/progress", goalHandler.GetGoalsProgress)
+		goals.GET("/:id", validator.ValidateUUIDParam("id"), goalHandler.GetGoal)
+		goals.PUT("/:id", validator.ValidateUUIDParam("id"), goalHandler.UpdateGoal)
+		goals.DELETE("/:id", validator.ValidateUUIDParam("id"), goalHandler.DeleteGoal)
+		goals.GET("/:id/progress", validator.ValidateUUIDParam("id"), goalHandler.GetGoalProgress)
+	}
+
+	// Teams; analytics are aggregates served by the analytics handler and tags by the tag handler
+	teamHandler := NewTeamHandler(teamService)
 	tagHandler := NewTagHandler(tagService)
+	teams := protected.Group("/teams")
+	{
+		teams.POST("", teamHandler.CreateTeam)
+		teams.GET("", teamHandler.GetTeams)
+		teams.GET("/:id", validator.ValidateUUIDParam("id"), teamHandler.GetTeam)
+		teams.PUT("/:id", validator.ValidateUUIDParam("id"), teamHandler.UpdateTeam)
+		teams.DELETE("/:id", validator.ValidateUUIDParam("id"), teamHandler.DeleteTeam)
+		teams.GET("/:id/members", validator.ValidateUUIDParam("id"), teamHandler.GetMembers)
+		teams.POST("/:id/members", validator.ValidateUUIDParam("id"), teamHandler.AddMember)
+		teams.PUT("/:id/members/:user_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("user_id"), teamHandler.UpdateMemberRole)
+		teams.DELETE("/:id/members/:user_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("user_id"), teamHandler.RemoveMember)
+		teams.GET("/:id/projects", validator.ValidateUUIDParam("id"), teamHandler.GetProjects)
+		teams.POST("/:id/projects", validator.ValidateUUIDParam("id"), teamHandler.ShareProject)
+		teams.DELETE("/:id/projects/:project_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("project_id"), teamHandler.UnshareProject)
+		teams.GET("/:id/analytics", validator.ValidateUUIDParam("id"), analyticsHandler.GetTeamAnalytics)
+		teams.GET("/:id/tags", validator.ValidateUUIDParam("id"), tagHandler.GetTeamTags)
+		teams.POST("/:id/tags", validator.ValidateUUIDParam("id"), tagHandler.CreateTeamTag)
+		teams.PUT("/:id/tags/:tag_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("tag_id"), tagHandler.UpdateTeamTag)
+		teams.DELETE("/:id/tags/:tag_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("tag_id"), tagHandler.DeleteTeamTag)
+	}
+
+	// Tags
 	tags := protected.Group("/tags")
 	{
 		tags.POST("", tagHandler.CreateTag)
@@ -123,21 +231,87 @@ func SetupRoutes(
 		tags.GET("/:id", tagHandler.GetTag)
 		tags.PUT("/:id", validator.ValidateUUIDParam("id"), tagHandler.UpdateTag)
 		tags.DELETE("/:id", tagHandler.DeleteTag)
+		tags.POST("/:id/merge", validator.ValidateUUIDParam("id"), tagHandler.MergeTag)
+		tags.GET("/:id/aliases", validator.ValidateUUIDParam("id"), tagHandler.GetTagAliases)
+		tags.POST("/:id/aliases", validator.ValidateUUIDParam("id"), tagHandler.AddTagAlias)
+		tags.DELETE("/:id/aliases/:alias", validator.ValidateUUIDParam("id"), tagHandler.RemoveTagAlias)
 	}
 
 	// Users (Profile Management)
 	userHandler := NewUserHandler(userService)
+	accountHandler := NewAccountHandler(accountService)
+	notificationHandler := NewNotificationHandler(notificationService)
 	users := protected.Group("/users")
 	{
 		users.GET("/profile", userHandler.GetProfile)
 		users.PUT("/profile", userHandler.UpdateProfile)
 		users.POST("/change-password", userHandler.ChangePassword)
 		users.DELETE("/account", userHandler.DeleteAccount)
+		users.GET("/attachments/usage", attachmentHandler.GetAttachmentUsage)
+		users.POST("/export", accountHandler.ExportAccount)
+		users.POST("/import", accountHandler.ImportAccount)
+		users.POST("/profile/calendar-feeds", calendarFeedHandler.CreateToken)
+		users.GET("/profile/calendar-feeds", calendarFeedHandler.GetTokens)
+		users.DELETE("/profile/calendar-feeds/:id", validator.ValidateUUIDParam("id"), calendarFeedHandler.RevokeToken)
+		users.GET("/profile/notifications", notificationHandler.GetNotifications)
+		users.POST("/profile/notifications/test", notificationHandler.SendTestEmail)
+	}
+
+	// Data exports, streamed as CSV or JSON Lines
+	exportHandler := NewExportHandler(exportService)
+	export := protected.Group("/export")
+	{
+		export.GET("/logs", exportHandler.ExportLogEntries)
+		export.GET("/projects", exportHandler.ExportProjects)
+		export.GET("/tags", exportHandler.ExportTagUsage)
+	}
+
+	// Data imports from other tools
+	calendarImportHandler := NewCalendarImportHandler(calendarImportService)
+	csvImportHandler := NewCSVImportHandler(csvImportService)
+	imports := protected.Group("/import")
+	{
+		imports.POST("/ics", calendarImportHandler.ImportCalendar)
+		imports.POST("/csv", csvImportHandler.ImportCSV)
+		imports.GET("/csv/presets", csvImportHandler.GetPresets)
+		imports.POST("/csv/profiles", csvImportHandler.CreateProfile)
+		imports.GET("/csv/profiles", csvImportHandler.GetProfiles)
+		imports.GET("/csv/profiles/:id", validator.ValidateUUIDParam("id"), csvImportHandler.GetProfile)
+		imports.PUT("/csv/profiles/:id", validator.ValidateUUIDParam("id"), csvImportHandler.UpdateProfile)
+		imports.DELETE("/csv/profiles/:id", validator.ValidateUUIDParam("id"), csvImportHandler.DeleteProfile)
+	}
+
+	// Brag documents, and report files generated asynchronously by workers
+	reportHandler := NewReportHandler(reportService, bragDocService)
+	reports := protected.Group("/reports")
+	{
+		reports.GET("", reportHandler.GetReports)
+		reports.GET("/brag-doc", reportHandler.GetBragDoc)
+		reports.GET("/brag-doc/templates", reportHandler.GetBragDocTemplates)
+		reports.POST("/performance-review", reportHandler.RequestPerformanceReview)
+		reports.GET("/:id", validator.ValidateUUIDParam("id"), reportHandler.GetReport)
+		reports.GET("/:id/download", validator.ValidateUUIDParam("id"), reportHandler.DownloadReport)
+	}
+
+	// Outbound webhooks and their delivery log
+	webhookHandler := NewWebhookHandler(webhookService)
+	webhooks := protected.Group("/webhooks")
+	{
+		webhooks.POST("", webhookHandler.CreateWebhook)
+		webhooks.GET("", webhookHandler.GetWebhooks)
+		webhooks.GET("/:id", validator.ValidateUUIDParam("id"), webhookHandler.GetWebhook)
+		webhooks.PUT("/:id", validator.ValidateUUIDParam("id"), webhookHandler.UpdateWebhook)
+		webhooks.DELETE("/:id", validator.ValidateUUIDParam("id"), webhookHandler.DeleteWebhook)
+		webhooks.POST("/:id/rotate-secret", validator.ValidateUUIDParam("id"), webhookHandler.RotateSecret)
+		webhooks.POST("/:id/ping", validator.ValidateUUIDParam("id"), webhookHandler.PingWebhook)
+		webhooks.GET("/:id/deliveries", validator.ValidateUUIDParam("id"), webhookHandler.GetDeliveries)
+		webhooks.GET("/:id/deliveries/:delivery_id", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("delivery_id"), webhookHandler.GetDelivery)
+		webhooks.POST("/:id/deliveries/:delivery_id/replay", validator.ValidateUUIDParam("id"), validator.ValidateUUIDParam("delivery_id"), webhookHandler.ReplayDelivery)
 	}
 
 	// Worker and task management routes (protected)
 	if grpcManager != nil {
-		SetupWorkerRoutes(protected, grpcManager)
+		SetupWorkerRoutes(protected, grpcManager, analyticsService, goalService)
 	} else {
 		logger.Warn("gRPC manager is nil, skipping worker routes setup")
 	}
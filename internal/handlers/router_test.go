@@ -34,10 +34,25 @@ func TestSetupRoutesWithRateLimit(t *testing.T) {
 		nil, // Redis client (nil for fallback)
 		nil, // authService
 		nil, // logEntryService
+		nil, // logTemplateService
+		nil, // attachmentService
+		nil, // suggestionService
 		nil, // projectService
 		nil, // analyticsService
+		nil, // goalService
+		nil, // teamService
 		nil, // tagService
 		nil, // userService
+		nil, // exportService
+		nil, // reportService
+		nil, // bragDocService
+		nil, // accountService
+		nil, // gitImportService
+		nil, // calendarImportService
+		nil, // csvImportService
+		nil, // calendarFeedService
+		nil, // webhookService
+		nil, // notificationService
 		nil, // grpcManager
 	)
 
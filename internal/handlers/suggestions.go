@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SuggestionHandler handles HTTP requests for log entry suggestions
+type SuggestionHandler struct {
+	suggestionService *services.SuggestionService
+}
+
+// NewSuggestionHandler creates a new SuggestionHandler instance
+func NewSuggestionHandler(suggestionService *services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+// SuggestLogEntry handles POST /v1/logs/suggest
+func (h *SuggestionHandler) SuggestLogEntry(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.LogEntrySuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	suggestion, err := h.suggestionService.SuggestLogEntry(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to suggest log entry details", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, suggestion)
+}
@@ -173,7 +173,7 @@ func TestTagHandler_Comprehensive_TagDuplication(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create first tag directly via service
-		firstTag := createTestTag(t, tagService)
+		firstTag := createTestTag(t, tagService, user.ID.String())
 
 		// Try to create second tag with same name via API
 		tagRequest := models.TagRequest{
@@ -209,7 +209,7 @@ func TestTagHandler_Comprehensive_TagDuplication(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create first tag
-		firstTag := createTestTag(t, tagService)
+		firstTag := createTestTag(t, tagService, user.ID.String())
 
 		// Try to create second tag with different case
 		tagRequest := models.TagRequest{
@@ -260,7 +260,7 @@ func TestTagHandler_Comprehensive_GetTags(t *testing.T) {
 		tagCount := 5
 		createdTags := make([]*models.Tag, tagCount)
 		for i := 0; i < tagCount; i++ {
-			createdTags[i] = createTestTag(t, tagService)
+			createdTags[i] = createTestTag(t, tagService, user.ID.String())
 		}
 
 		// Get all tags
@@ -529,7 +529,7 @@ func TestTagHandler_Comprehensive_UpdateTag(t *testing.T) {
 			token := loginUser(t, router, user.Email, "password123")
 
 			// Create initial tag
-			existingTag := createTestTag(t, tagService)
+			existingTag := createTestTag(t, tagService, user.ID.String())
 
 			// Prepare update request
 			body, err := json.Marshal(tc.updateReq)
@@ -582,7 +582,7 @@ func TestTagHandler_Comprehensive_DeleteTag(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create tag to delete
-		tag := createTestTag(t, tagService)
+		tag := createTestTag(t, tagService, user.ID.String())
 
 		// Delete tag
 		req := httptest.NewRequest(http.MethodDelete, "/v1/tags/"+tag.ID.String(), nil)
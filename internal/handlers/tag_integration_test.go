@@ -209,13 +209,13 @@ func TestTagHandler_Integration_CrossSystemBehavior(t *testing.T) {
 		require.NoError(t, err)
 
 		// Create tags
-		tag1, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag1, err := tagService.CreateTag(ctx, user.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("popular-tag-1-%d", time.Now().UnixNano()),
 			Color: "#FF0000",
 		})
 		require.NoError(t, err)
 
-		tag2, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag2, err := tagService.CreateTag(ctx, user.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("popular-tag-2-%d", time.Now().UnixNano()),
 			Color: "#00FF00",
 		})
@@ -289,7 +289,7 @@ func TestTagHandler_Integration_CrossSystemBehavior(t *testing.T) {
 		require.NoError(t, err)
 
 		// Create a tag
-		tag, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag, err := tagService.CreateTag(ctx, user.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("recent-tag-%d", time.Now().UnixNano()),
 			Color: "#FF0000",
 		})
@@ -379,10 +379,11 @@ func TestTagHandler_Integration_SecurityBehavior(t *testing.T) {
 
 	t.Run("unauthorized access prevention", func(t *testing.T) {
 		// Setup integration test environment
-		router, _, _, _, tagService := RouterWithServices(t)
+		router, userService, _, _, tagService := RouterWithServices(t)
+		owner := createTestUser(t, userService)
 
 		// Create a tag directly via service (without going through API)
-		tag, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag, err := tagService.CreateTag(ctx, owner.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("security-tag-%d", time.Now().UnixNano()),
 			Color: "#FF0000",
 		})
@@ -468,13 +469,13 @@ func TestTagHandler_Integration_SecurityBehavior(t *testing.T) {
 		require.NoError(t, err)
 
 		// Create tags
-		tag1, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag1, err := tagService.CreateTag(ctx, user1.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("user1-tag-%d", time.Now().UnixNano()),
 			Color: "#FF0000",
 		})
 		require.NoError(t, err)
 
-		tag2, err := tagService.CreateTag(ctx, &models.TagRequest{
+		tag2, err := tagService.CreateTag(ctx, user2.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("user2-tag-%d", time.Now().UnixNano()),
 			Color: "#00FF00",
 		})
@@ -726,7 +727,7 @@ func TestTagHandler_Integration_PerformanceAndReliability(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create a tag with searchable name
-		searchableTag, err := tagService.CreateTag(ctx, &models.TagRequest{
+		searchableTag, err := tagService.CreateTag(ctx, user.ID.String(), &models.TagRequest{
 			Name:  fmt.Sprintf("searchable-consistency-tag-%d", time.Now().UnixNano()),
 			Color: "#FF0000",
 		})
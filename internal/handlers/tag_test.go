@@ -102,7 +102,7 @@ func TestTagHandler_GetTag(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create a tag first
-		tag := createTestTag(t, tagService)
+		tag := createTestTag(t, tagService, user.ID.String())
 
 		// Get the tag
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/v1/tags/%s", tag.ID), nil)
@@ -159,8 +159,8 @@ func TestTagHandler_GetTags(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create multiple tags
-		tag1 := createTestTag(t, tagService)
-		tag2 := createTestTag(t, tagService)
+		tag1 := createTestTag(t, tagService, user.ID.String())
+		tag2 := createTestTag(t, tagService, user.ID.String())
 
 		// Get all tags
 		req, _ := http.NewRequest("GET", "/v1/tags", nil)
@@ -299,7 +299,7 @@ func TestTagHandler_SearchTags(t *testing.T) {
 
 		// Create a tag with searchable name
 		description := "Backend development tasks"
-		_, err := tagService.CreateTag(ctx, &models.TagRequest{
+		_, err := tagService.CreateTag(ctx, user.ID.String(), &models.TagRequest{
 			Name:        "backend-development",
 			Description: &description,
 			Color:       "#3B82F6",
@@ -369,7 +369,7 @@ func TestTagHandler_UpdateTag(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create a tag first
-		tag := createTestTag(t, tagService)
+		tag := createTestTag(t, tagService, user.ID.String())
 
 		// Prepare update request
 		updateDescription := "Updated backend development tasks"
@@ -450,7 +450,7 @@ func TestTagHandler_DeleteTag(t *testing.T) {
 		token := loginUser(t, router, user.Email, "password123")
 
 		// Create a tag first
-		tag := createTestTag(t, tagService)
+		tag := createTestTag(t, tagService, user.ID.String())
 
 		// Delete the tag
 		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/v1/tags/%s", tag.ID), nil)
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/garnizeh/englog/internal/models"
 	"github.com/garnizeh/englog/internal/services"
@@ -23,6 +24,14 @@ func NewTagHandler(tagService *services.TagService) *TagHandler {
 
 // CreateTag handles POST /v1/tags
 func (h *TagHandler) CreateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	var req models.TagRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -32,7 +41,7 @@ func (h *TagHandler) CreateTag(c *gin.Context) {
 		return
 	}
 
-	tag, err := h.tagService.CreateTag(c.Request.Context(), &req)
+	tag, err := h.tagService.CreateTag(c.Request.Context(), userID.(string), &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Failed to create tag",
@@ -48,9 +57,17 @@ func (h *TagHandler) CreateTag(c *gin.Context) {
 
 // GetTag handles GET /v1/tags/:id
 func (h *TagHandler) GetTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	tagID := c.Param("id")
 
-	tag, err := h.tagService.GetTag(c.Request.Context(), tagID)
+	tag, err := h.tagService.GetTag(c.Request.Context(), userID.(string), tagID)
 	if err != nil {
 		// Always return 404 "Tag not found" for any error (invalid format or not found)
 		RespondWithError(c, 404, "Tag not found")
@@ -58,9 +75,19 @@ func (h *TagHandler) GetTag(c *gin.Context) {
 	}
 
 	RespondWithSuccess(c, 200, tag, "Tag retrieved successfully")
-} // GetTags handles GET /v1/tags
+}
+
+// GetTags handles GET /v1/tags
 func (h *TagHandler) GetTags(c *gin.Context) {
-	tags, err := h.tagService.GetAllTags(c.Request.Context())
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
+	tags, err := h.tagService.GetAllTags(c.Request.Context(), userID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get tags",
@@ -77,6 +104,14 @@ func (h *TagHandler) GetTags(c *gin.Context) {
 
 // GetPopularTags handles GET /v1/tags/popular
 func (h *TagHandler) GetPopularTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	limit := int32(10) // Default limit
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
@@ -84,7 +119,7 @@ func (h *TagHandler) GetPopularTags(c *gin.Context) {
 		}
 	}
 
-	tags, err := h.tagService.GetPopularTags(c.Request.Context(), limit)
+	tags, err := h.tagService.GetPopularTags(c.Request.Context(), userID.(string), limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get popular tags",
@@ -133,6 +168,14 @@ func (h *TagHandler) GetRecentlyUsedTags(c *gin.Context) {
 
 // SearchTags handles GET /v1/tags/search
 func (h *TagHandler) SearchTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	query := c.Query("q")
 	if query == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -148,7 +191,7 @@ func (h *TagHandler) SearchTags(c *gin.Context) {
 		}
 	}
 
-	tags, err := h.tagService.SearchTags(c.Request.Context(), query, limit)
+	tags, err := h.tagService.SearchTags(c.Request.Context(), userID.(string), query, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to search tags",
@@ -166,6 +209,14 @@ func (h *TagHandler) SearchTags(c *gin.Context) {
 
 // UpdateTag handles PUT /v1/tags/:id
 func (h *TagHandler) UpdateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	tagID := c.Param("id")
 
 	var req models.TagRequest
@@ -174,8 +225,12 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 		return
 	}
 
-	tag, err := h.tagService.UpdateTag(c.Request.Context(), tagID, &req)
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), userID.(string), tagID, &req)
 	if err != nil {
+		if strings.Contains(err.Error(), "read-only") {
+			RespondWithError(c, http.StatusForbidden, "Shared tags are read-only")
+			return
+		}
 		RespondWithError(c, 400, "Failed to update tag")
 		return
 	}
@@ -185,10 +240,22 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 
 // DeleteTag handles DELETE /v1/tags/:id
 func (h *TagHandler) DeleteTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+		})
+		return
+	}
+
 	tagID := c.Param("id")
 
-	err := h.tagService.DeleteTag(c.Request.Context(), tagID)
+	err := h.tagService.DeleteTag(c.Request.Context(), userID.(string), tagID)
 	if err != nil {
+		if strings.Contains(err.Error(), "read-only") {
+			RespondWithError(c, http.StatusForbidden, "Shared tags are read-only")
+			return
+		}
 		// Always return 500 "Failed to delete tag" for any error (invalid format or not found)
 		RespondWithError(c, 500, "Failed to delete tag")
 		return
@@ -198,6 +265,7 @@ func (h *TagHandler) DeleteTag(c *gin.Context) {
 }
 
 // GetUserTagUsage handles GET /v1/tags/usage
+// Pass include_descendants=true to roll child tag usage up into each parent.
 func (h *TagHandler) GetUserTagUsage(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -207,7 +275,9 @@ func (h *TagHandler) GetUserTagUsage(c *gin.Context) {
 		return
 	}
 
-	usage, err := h.tagService.GetUserTagUsage(c.Request.Context(), userID.(string))
+	includeDescendants := c.Query("include_descendants") == "true"
+
+	usage, err := h.tagService.GetUserTagUsage(c.Request.Context(), userID.(string), includeDescendants)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get tag usage",
@@ -221,3 +291,169 @@ func (h *TagHandler) GetUserTagUsage(c *gin.Context) {
 		"total": len(usage),
 	})
 }
+
+// MergeTag handles POST /v1/tags/:id/merge
+func (h *TagHandler) MergeTag(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.tagService.MergeTags(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, tagErrorStatus(err), "Failed to merge tags", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, result, "Tags merged successfully")
+}
+
+// GetTagAliases handles GET /v1/tags/:id/aliases
+func (h *TagHandler) GetTagAliases(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	aliases, err := h.tagService.GetTagAliases(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, tagErrorStatus(err), "Failed to get tag aliases", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, aliases)
+}
+
+// AddTagAlias handles POST /v1/tags/:id/aliases
+func (h *TagHandler) AddTagAlias(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TagAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	alias, err := h.tagService.AddTagAlias(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, tagErrorStatus(err), "Failed to add tag alias", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, alias, "Tag alias added successfully")
+}
+
+// RemoveTagAlias handles DELETE /v1/tags/:id/aliases/:alias
+func (h *TagHandler) RemoveTagAlias(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.tagService.RemoveTagAlias(c.Request.Context(), userID, c.Param("id"), c.Param("alias")); err != nil {
+		RespondWithError(c, tagErrorStatus(err), "Failed to remove tag alias", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Tag alias removed successfully")
+}
+
+// GetTeamTags handles GET /v1/teams/:id/tags
+func (h *TagHandler) GetTeamTags(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tags, err := h.tagService.GetTeamTags(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get team tags", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, tags)
+}
+
+// CreateTeamTag handles POST /v1/teams/:id/tags
+func (h *TagHandler) CreateTeamTag(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.CreateTeamTag(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create team tag", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, tag, "Team tag created successfully")
+}
+
+// UpdateTeamTag handles PUT /v1/teams/:id/tags/:tag_id
+func (h *TagHandler) UpdateTeamTag(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.UpdateTeamTag(c.Request.Context(), userID, c.Param("id"), c.Param("tag_id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update team tag", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, tag, "Team tag updated successfully")
+}
+
+// DeleteTeamTag handles DELETE /v1/teams/:id/tags/:tag_id
+func (h *TagHandler) DeleteTeamTag(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.tagService.DeleteTeamTag(c.Request.Context(), userID, c.Param("id"), c.Param("tag_id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete team tag", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Team tag deleted successfully")
+}
+
+// tagErrorStatus maps tag service errors to HTTP status codes
+func tagErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "read-only") {
+		return http.StatusForbidden
+	}
+	return ErrorStatus(err)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TeamHandler handles HTTP requests for shared team workspaces
+type TeamHandler struct {
+	teamService *services.TeamService
+}
+
+// NewTeamHandler creates a new TeamHandler instance
+func NewTeamHandler(teamService *services.TeamService) *TeamHandler {
+	return &TeamHandler{
+		teamService: teamService,
+	}
+}
+
+// CreateTeam handles POST /v1/teams
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	team, err := h.teamService.CreateTeam(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create team", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, team)
+}
+
+// GetTeams handles GET /v1/teams
+func (h *TeamHandler) GetTeams(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	teams, err := h.teamService.GetUserTeams(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get teams", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, teams)
+}
+
+// GetTeam handles GET /v1/teams/:id
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	team, err := h.teamService.GetTeam(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get team", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, team)
+}
+
+// UpdateTeam handles PUT /v1/teams/:id
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	team, err := h.teamService.UpdateTeam(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update team", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, team)
+}
+
+// DeleteTeam handles DELETE /v1/teams/:id
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete team", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Team deleted successfully")
+}
+
+// GetMembers handles GET /v1/teams/:id/members
+func (h *TeamHandler) GetMembers(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	members, err := h.teamService.GetTeamMembers(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get team members", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, members)
+}
+
+// AddMember handles POST /v1/teams/:id/members
+func (h *TeamHandler) AddMember(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	member, err := h.teamService.AddTeamMember(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to add team member", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, member)
+}
+
+// UpdateMemberRole handles PUT /v1/teams/:id/members/:user_id
+func (h *TeamHandler) UpdateMemberRole(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TeamRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	member, err := h.teamService.UpdateTeamMemberRole(c.Request.Context(), userID, c.Param("id"), c.Param("user_id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update team role", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, member)
+}
+
+// RemoveMember handles DELETE /v1/teams/:id/members/:user_id; members may remove themselves to leave
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.teamService.RemoveTeamMember(c.Request.Context(), userID, c.Param("id"), c.Param("user_id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to remove team member", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Team member removed successfully")
+}
+
+// GetProjects handles GET /v1/teams/:id/projects
+func (h *TeamHandler) GetProjects(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projects, err := h.teamService.GetTeamProjects(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get team projects", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, projects)
+}
+
+// ShareProject handles POST /v1/teams/:id/projects
+func (h *TeamHandler) ShareProject(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TeamProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := h.teamService.ShareProject(c.Request.Context(), userID, c.Param("id"), &req); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to share project", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Project shared successfully")
+}
+
+// UnshareProject handles DELETE /v1/teams/:id/projects/:project_id
+func (h *TeamHandler) UnshareProject(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.teamService.UnshareProject(c.Request.Context(), userID, c.Param("id"), c.Param("project_id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to unshare project", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Project unshared successfully")
+}
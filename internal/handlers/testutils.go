@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/garnizeh/englog/internal/auth"
+	"github.com/garnizeh/englog/internal/blobstore"
 	"github.com/garnizeh/englog/internal/config"
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/reports"
 	"github.com/garnizeh/englog/internal/services"
 	"github.com/garnizeh/englog/internal/testutils"
 	"github.com/gin-gonic/gin"
@@ -19,11 +21,34 @@ func RouterWithServices(t *testing.T) (*gin.Engine, *services.UserService, *serv
 	db := testutils.DB(t)
 	testLogger := logging.NewTestLogger()
 	authService := auth.NewAuthService(db, testLogger, "test-secret")
-	projectService := services.NewProjectService(db, testLogger)
+	webhookService := services.NewWebhookService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger).WithWebhooks(webhookService)
 	userService := services.NewUserService(db, testLogger)
-	logEntryService := services.NewLogEntryService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger).WithWebhooks(webhookService)
+	logTemplateService := services.NewLogTemplateService(db, testLogger, logEntryService)
 	analyticsService := services.NewAnalyticsService(db, testLogger)
+	goalService := services.NewGoalService(db, testLogger, analyticsService)
+	teamService := services.NewTeamService(db, testLogger, projectService)
+	exportService := services.NewExportService(db, testLogger)
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create blob store: %v", err)
+	}
+	attachmentService := services.NewAttachmentService(db, testLogger, blobs, 5*1024*1024, 100*1024*1024)
+	reportService := services.NewReportService(db, testLogger, analyticsService, blobs, nil)
+	bragDocTemplates, err := reports.LoadBragDocTemplates("")
+	if err != nil {
+		t.Fatalf("failed to load brag document templates: %v", err)
+	}
+	bragDocService := services.NewBragDocService(db, testLogger, bragDocTemplates, nil)
 	tagService := services.NewTagService(db, testLogger)
+	suggestionService := services.NewSuggestionService(testLogger, tagService, projectService, nil)
+	accountService := services.NewAccountService(db, testLogger, userService, projectService, tagService, logEntryService)
+	gitImportService := services.NewGitImportService(db, testLogger)
+	calendarImportService := services.NewCalendarImportService(db, testLogger, logEntryService)
+	csvImportService := services.NewCSVImportService(db, testLogger, logEntryService)
+	calendarFeedService := services.NewCalendarFeedService(db, testLogger)
+	notificationService := services.NewNotificationService(db, testLogger, nil, nil)
 
 	// Create test configuration
 	cfg := &config.Config{
@@ -52,10 +77,25 @@ func RouterWithServices(t *testing.T) (*gin.Engine, *services.UserService, *serv
 		nil, // No Redis client in tests
 		authService,
 		logEntryService,
+		logTemplateService,
+		attachmentService,
+		suggestionService,
 		projectService,
 		analyticsService,
+		goalService,
+		teamService,
 		tagService,
 		userService,
+		exportService,
+		reportService,
+		bragDocService,
+		accountService,
+		gitImportService,
+		calendarImportService,
+		csvImportService,
+		calendarFeedService,
+		webhookService,
+		notificationService,
 		nil, // No gRPC manager in tests
 	)
 
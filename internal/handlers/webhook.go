@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles HTTP requests for outbound webhooks and their delivery log
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook handles POST /v1/webhooks. The response holds the signing secret,
+// which is not shown again.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	created, err := h.webhookService.CreateWebhook(c.Request.Context(), userID, &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to create webhook", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, created)
+}
+
+// GetWebhooks handles GET /v1/webhooks
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to get webhooks", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, webhooks)
+}
+
+// GetWebhook handles GET /v1/webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get webhook", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, webhook)
+}
+
+// UpdateWebhook handles PUT /v1/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to update webhook", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles DELETE /v1/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), userID, c.Param("id")); err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to delete webhook", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, nil, "Webhook deleted successfully")
+}
+
+// RotateSecret handles POST /v1/webhooks/:id/rotate-secret
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	rotated, err := h.webhookService.RotateSecret(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to rotate webhook secret", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, rotated)
+}
+
+// PingWebhook handles POST /v1/webhooks/:id/ping
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	delivery, err := h.webhookService.PingWebhook(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to ping webhook", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusAccepted, delivery)
+}
+
+// GetDeliveries handles GET /v1/webhooks/:id/deliveries, optionally filtered by status
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	status := models.WebhookDeliveryStatus(c.Query("status"))
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), userID, c.Param("id"), status)
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get webhook deliveries", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, deliveries)
+}
+
+// GetDelivery handles GET /v1/webhooks/:id/deliveries/:delivery_id
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), userID, c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to get webhook delivery", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, delivery)
+}
+
+// ReplayDelivery handles POST /v1/webhooks/:id/deliveries/:delivery_id/replay
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	replay, err := h.webhookService.ReplayDelivery(c.Request.Context(), userID, c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		RespondWithError(c, ErrorStatus(err), "Failed to replay webhook delivery", err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusAccepted, replay)
+}
@@ -1,22 +1,29 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/garnizeh/englog/internal/grpc"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // WorkerHandlers provides HTTP endpoints for worker management
 type WorkerHandlers struct {
-	grpcManager *grpc.Manager
+	grpcManager      *grpc.Manager
+	analyticsService *services.AnalyticsService
+	goalService      *services.GoalService
 }
 
 // NewWorkerHandlers creates a new WorkerHandlers instance
-func NewWorkerHandlers(grpcManager *grpc.Manager) *WorkerHandlers {
+func NewWorkerHandlers(grpcManager *grpc.Manager, analyticsService *services.AnalyticsService, goalService *services.GoalService) *WorkerHandlers {
 	return &WorkerHandlers{
-		grpcManager: grpcManager,
+		grpcManager:      grpcManager,
+		analyticsService: analyticsService,
+		goalService:      goalService,
 	}
 }
 
@@ -44,7 +51,12 @@ func (h *WorkerHandlers) GetActiveWorkers(c *gin.Context) {
 	})
 }
 
-// RequestInsightGeneration queues an insight generation task
+// insightFocusDays is how many recent days of focus metrics productivity insights get
+const insightFocusDays = 14
+
+// RequestInsightGeneration queues an insight generation task. Goal progress
+// insights get the user's active goals and their current progress in the context;
+// productivity insights get focus metrics for the last two weeks.
 func (h *WorkerHandlers) RequestInsightGeneration(c *gin.Context) {
 	var req struct {
 		UserID      string   `json:"user_id" binding:"required"`
@@ -59,6 +71,23 @@ func (h *WorkerHandlers) RequestInsightGeneration(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	switch req.InsightType {
+	case string(models.ReportGoalProgress):
+		progress, err := h.goalService.GetActiveGoalsProgress(ctx, req.UserID, "", nil)
+		if err != nil {
+			c.JSON(ErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		req.Context = withInsightContext(req.Context, "goals", progress)
+	case "productivity", string(models.ReportProductivityTrends):
+		focus, err := h.recentFocus(ctx, req.UserID)
+		if err != nil {
+			c.JSON(ErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		req.Context = withInsightContext(req.Context, "focus", focus)
+	}
+
 	taskID, err := h.grpcManager.QueueInsightGenerationTask(
 		ctx,
 		req.UserID,
@@ -77,12 +106,15 @@ func (h *WorkerHandlers) RequestInsightGeneration(c *gin.Context) {
 	})
 }
 
-// RequestWeeklyReport queues a weekly report generation task
+// RequestWeeklyReport queues a weekly report generation task.
+// The week is made of whole days in the user's timezone and defaults to the current
+// week, starting on the user's preferred week start day.
 func (h *WorkerHandlers) RequestWeeklyReport(c *gin.Context) {
 	var req struct {
 		UserID    string `json:"user_id" binding:"required"`
-		WeekStart string `json:"week_start" binding:"required"`
-		WeekEnd   string `json:"week_end" binding:"required"`
+		WeekStart string `json:"week_start"`
+		WeekEnd   string `json:"week_end"`
+		Timezone  string `json:"timezone"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -90,19 +122,43 @@ func (h *WorkerHandlers) RequestWeeklyReport(c *gin.Context) {
 		return
 	}
 
-	weekStart, err := time.Parse("2006-01-02", req.WeekStart)
+	override, err := parseTimezone(req.Timezone)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week_start format. Use YYYY-MM-DD"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	weekEnd, err := time.Parse("2006-01-02", req.WeekEnd)
+	ctx := c.Request.Context()
+	settings, err := h.analyticsService.ResolveSettings(ctx, req.UserID, override)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week_end format. Use YYYY-MM-DD"})
+		c.JSON(ErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	weekStart := startOfWeek(time.Now().In(settings.Location), settings.WeekStart.Weekday())
+	if req.WeekStart != "" {
+		weekStart, err = time.ParseInLocation("2006-01-02", req.WeekStart, settings.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week_start format. Use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	weekEnd := endOfDay(weekStart.AddDate(0, 0, 6))
+	if req.WeekEnd != "" {
+		lastDay, err := time.ParseInLocation("2006-01-02", req.WeekEnd, settings.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week_end format. Use YYYY-MM-DD"})
+			return
+		}
+		weekEnd = endOfDay(lastDay)
+	}
+
+	if weekEnd.Before(weekStart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "week_end must be after week_start"})
 		return
 	}
 
-	ctx := c.Request.Context()
 	taskID, err := h.grpcManager.QueueWeeklyReportTask(ctx, req.UserID, weekStart, weekEnd)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -159,9 +215,51 @@ func (h *WorkerHandlers) HealthCheck(c *gin.Context) {
 	})
 }
 
+// recentFocus returns the weekly and overall focus metrics of the user's last
+// insightFocusDays days, in the user's timezone
+func (h *WorkerHandlers) recentFocus(ctx context.Context, userID string) (map[string]any, error) {
+	settings, err := h.analyticsService.ResolveSettings(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	end := endOfDay(time.Now().In(settings.Location))
+	start := startOfDay(end.AddDate(0, 0, 1-insightFocusDays))
+	report, err := h.analyticsService.GetFocusMetrics(ctx, userID, start, end, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"summary": report.Summary,
+		"weeks":   report.Weeks,
+	}, nil
+}
+
+// withInsightContext adds value under key to an insight context.
+// A plain string context is kept under "notes".
+func withInsightContext(contextData any, key string, value any) map[string]any {
+	merged := map[string]any{}
+	switch data := contextData.(type) {
+	case map[string]any:
+		for key, value := range data {
+			merged[key] = value
+		}
+	case string:
+		if data != "" {
+			merged["notes"] = data
+		}
+	case nil:
+	default:
+		merged["notes"] = data
+	}
+	merged[key] = value
+	return merged
+}
+
 // SetupWorkerRoutes adds worker-related routes to the router
-func SetupWorkerRoutes(router *gin.RouterGroup, grpcManager *grpc.Manager) {
-	workerHandlers := NewWorkerHandlers(grpcManager)
+func SetupWorkerRoutes(router *gin.RouterGroup, grpcManager *grpc.Manager, analyticsService *services.AnalyticsService, goalService *services.GoalService) {
+	workerHandlers := NewWorkerHandlers(grpcManager, analyticsService, goalService)
 
 	// Worker management routes
 	workers := router.Group("/workers")
@@ -0,0 +1,104 @@
+package ical
+
+import (
+	"sort"
+	"time"
+)
+
+// Occurrence is one meeting of a calendar: a single event, an occurrence of a
+// recurring event or the override that replaced one
+type Occurrence struct {
+	// ID identifies the occurrence across exports of the same calendar: the UID,
+	// followed by "/" and the original start in UTC for recurring events
+	ID    string
+	Event *Event // The event, or the override of this occurrence
+	Start time.Time
+	End   time.Time
+}
+
+// Occurrences returns the occurrences of events that start in [from, to), in
+// chronological order. Recurring events are expanded in the location of their
+// start, leaving out EXDATEs and using the overrides (events with a RECURRENCE-ID)
+// in place of the occurrences they replace, even when they move them into or out
+// of the period. Repeated events with the same ID are returned once.
+func Occurrences(events []Event, from, to time.Time) []Occurrence {
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	overrides := map[string]map[int64]*Event{}
+	for i := range events {
+		event := &events[i]
+		if event.RecurrenceID == nil {
+			continue
+		}
+		if overrides[event.UID] == nil {
+			overrides[event.UID] = map[int64]*Event{}
+		}
+		overrides[event.UID][event.RecurrenceID.Unix()] = event
+	}
+
+	var occurrences []Occurrence
+	seen := map[string]bool{}
+	add := func(id string, event *Event, start, end time.Time) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		occurrences = append(occurrences, Occurrence{ID: id, Event: event, Start: start, End: end})
+	}
+
+	for i := range events {
+		event := &events[i]
+		if event.RecurrenceID != nil {
+			continue
+		}
+		if event.Rule == nil {
+			if inPeriod(event.Start) {
+				add(event.UID, event, event.Start, event.End)
+			}
+			continue
+		}
+
+		excluded := make(map[int64]bool, len(event.ExDates))
+		for _, exDate := range event.ExDates {
+			excluded[exDate.Unix()] = true
+		}
+		length := event.End.Sub(event.Start)
+		for _, start := range event.Rule.Between(event.Start, from, to) {
+			if excluded[start.Unix()] {
+				continue
+			}
+			id := occurrenceID(event.UID, start)
+			if override := overrides[event.UID][start.Unix()]; override != nil {
+				if inPeriod(override.Start) {
+					add(id, override, override.Start, override.End)
+				}
+				continue
+			}
+			add(id, event, start, start.Add(length))
+		}
+	}
+
+	// Overrides that moved an occurrence into the period, and overrides of events
+	// that are not in the file
+	for i := range events {
+		event := &events[i]
+		if event.RecurrenceID != nil && inPeriod(event.Start) {
+			add(occurrenceID(event.UID, *event.RecurrenceID), event, event.Start, event.End)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		if !occurrences[i].Start.Equal(occurrences[j].Start) {
+			return occurrences[i].Start.Before(occurrences[j].Start)
+		}
+		return occurrences[i].ID < occurrences[j].ID
+	})
+	return occurrences
+}
+
+// occurrenceID identifies an occurrence of a recurring event by its original start
+func occurrenceID(uid string, start time.Time) string {
+	return uid + "/" + start.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOccurrences(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	calendar, err := Parse(strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		// Daily at 9:00 New York time across the start of daylight saving time on March 9
+		"BEGIN:VEVENT",
+		"UID:daily",
+		"DTSTART;TZID=America/New_York:20250306T090000",
+		"DTEND;TZID=America/New_York:20250306T091500",
+		"RRULE:FREQ=DAILY;COUNT=6",
+		"EXDATE;TZID=America/New_York:20250308T090000,20250309T090000",
+		"SUMMARY:Standup",
+		"END:VEVENT",
+		// The occurrence of March 7 moved to the afternoon and the one of March 10 moved to April
+		"BEGIN:VEVENT",
+		"UID:daily",
+		"RECURRENCE-ID;TZID=America/New_York:20250307T090000",
+		"DTSTART;TZID=America/New_York:20250307T140000",
+		"DTEND;TZID=America/New_York:20250307T143000",
+		"SUMMARY:Standup (afternoon)",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:daily",
+		"RECURRENCE-ID;TZID=America/New_York:20250310T090000",
+		"DTSTART;TZID=America/New_York:20250401T090000",
+		"DTEND;TZID=America/New_York:20250401T091500",
+		"END:VEVENT",
+		// An override of a recurring event that is not in the file
+		"BEGIN:VEVENT",
+		"UID:elsewhere",
+		"RECURRENCE-ID:20250306T150000Z",
+		"DTSTART:20250306T160000Z",
+		"DTEND:20250306T170000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:single",
+		"DTSTART:20250306T120000Z",
+		"DTEND:20250306T130000Z",
+		"END:VEVENT",
+		// Some feeds repeat events
+		"BEGIN:VEVENT",
+		"UID:single",
+		"DTSTART:20250306T120000Z",
+		"DTEND:20250306T130000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")), time.UTC)
+	require.NoError(t, err)
+	require.Empty(t, calendar.Warnings)
+
+	from := time.Date(2025, 3, 6, 0, 0, 0, 0, newYork)
+	occurrences := Occurrences(calendar.Events, from, from.AddDate(0, 0, 14))
+
+	var ids []string
+	for _, occurrence := range occurrences {
+		ids = append(ids, occurrence.ID)
+	}
+	assert.Equal(t, []string{
+		"single",
+		"daily/20250306T140000Z",
+		"elsewhere/20250306T150000Z",
+		"daily/20250307T140000Z",
+		"daily/20250311T130000Z",
+	}, ids, "excluded dates and occurrences moved out of the period are left out")
+
+	first := occurrences[1]
+	assert.Equal(t, "Standup", first.Event.Summary)
+	assert.Equal(t, 15*time.Minute, first.End.Sub(first.Start))
+
+	moved := occurrences[3]
+	assert.Equal(t, "Standup (afternoon)", moved.Event.Summary, "overrides replace their occurrence")
+	assert.Equal(t, time.Date(2025, 3, 7, 14, 0, 0, 0, newYork), moved.Start)
+	assert.Equal(t, 30*time.Minute, moved.End.Sub(moved.Start))
+
+	afterDST := occurrences[4]
+	assert.Equal(t, time.Date(2025, 3, 11, 9, 0, 0, 0, newYork), afterDST.Start, "recurrences keep their local time across DST")
+
+	t.Run("MovedIntoPeriod", func(t *testing.T) {
+		april := time.Date(2025, 4, 1, 0, 0, 0, 0, newYork)
+		occurrences := Occurrences(calendar.Events, april, april.AddDate(0, 0, 1))
+		require.Len(t, occurrences, 1)
+		assert.Equal(t, "daily/20250310T130000Z", occurrences[0].ID, "moved occurrences keep the ID of their original start")
+	})
+
+	t.Run("UTCEventsRecurInTheGivenLocation", func(t *testing.T) {
+		calendar, err := Parse(strings.NewReader(strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:weekly",
+			"DTSTART:20250303T140000Z",
+			"DTEND:20250303T150000Z",
+			"RRULE:FREQ=WEEKLY",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\n")), newYork)
+		require.NoError(t, err)
+
+		occurrences := Occurrences(calendar.Events, from, from.AddDate(0, 0, 14))
+		require.Len(t, occurrences, 2)
+		assert.Equal(t, 9, occurrences[0].Start.In(newYork).Hour())
+		assert.Equal(t, 9, occurrences[1].Start.In(newYork).Hour(), "the local time of day is kept after DST starts")
+	})
+}
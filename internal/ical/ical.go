@@ -0,0 +1,538 @@
+// Package ical reads the events of iCalendar (RFC 5545) files, such as the exports
+// and subscription feeds of Google Calendar, Outlook and Apple Calendar, expands
+// recurring events into their occurrences and writes calendars for subscription.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/recurrence"
+)
+
+// maxLineBytes bounds the length of a single physical line of a calendar file
+const maxLineBytes = 1024 * 1024
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Attendee participation statuses (PARTSTAT)
+const (
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+)
+
+// Attendee roles (ROLE) and calendar user types (CUTYPE)
+const (
+	RoleChair          = "CHAIR"
+	RoleRequired       = "REQ-PARTICIPANT"
+	RoleOptional       = "OPT-PARTICIPANT"
+	RoleNonParticipant = "NON-PARTICIPANT"
+
+	KindIndividual = "INDIVIDUAL"
+	KindGroup      = "GROUP"
+	KindResource   = "RESOURCE"
+	KindRoom       = "ROOM"
+)
+
+// durationPattern matches an RFC 5545 duration such as PT1H30M, P1D or -PT15M
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Calendar is the content of an iCalendar file
+type Calendar struct {
+	Name            string        // X-WR-CALNAME, when the file names the calendar
+	RefreshInterval time.Duration // REFRESH-INTERVAL, how often subscribers should reload the calendar
+	Events          []Event
+	Warnings        []string // Events that could not be read, and values read with a fallback
+}
+
+// Attendee is an ATTENDEE of an event
+type Attendee struct {
+	Email  string // Lower case, without the mailto: prefix
+	Name   string
+	Role   string // REQ-PARTICIPANT when the file does not say
+	Status string // NEEDS-ACTION when the file does not say
+	Kind   string // INDIVIDUAL when the file does not say
+}
+
+// Event is a VEVENT. Times with a known TZID are read in that timezone; UTC and
+// floating times are read in the location given to Parse, so recurrences keep
+// their time of day there.
+type Event struct {
+	UID            string
+	Summary        string
+	Description    string
+	Location       string
+	Start          time.Time
+	End            time.Time
+	AllDay         bool   // DTSTART is a date
+	Status         string // Upper case; empty when the file does not say
+	OrganizerEmail string
+	Attendees      []Attendee
+	Rule           *recurrence.Rule
+	ExDates        []time.Time
+	RecurrenceID   *time.Time // Original start of the occurrence this event overrides
+	Categories     []string
+	Color          string    // RFC 7986 COLOR, a CSS3 color name
+	LastModified   time.Time // Zero when the file does not say
+}
+
+// Attendee returns the attendee with the given email, compared case-insensitively
+func (e *Event) Attendee(email string) *Attendee {
+	for i := range e.Attendees {
+		if strings.EqualFold(e.Attendees[i].Email, email) {
+			return &e.Attendees[i]
+		}
+	}
+	return nil
+}
+
+// property is a content line: NAME;PARAM=value:VALUE
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads a calendar file. Floating times, all-day dates and times whose TZID
+// is not a known timezone are read in loc. Events that cannot be read, like those
+// without a UID or a valid DTSTART, are left out and reported in the warnings.
+func Parse(r io.Reader, loc *time.Location) (*Calendar, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	calendar := &Calendar{}
+	var stack []string
+	var event []property
+	sawCalendar := false
+
+	handle := func(lineNumber int, line string) error {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 {
+				if component != "VCALENDAR" {
+					return fmt.Errorf("line %d: expected BEGIN:VCALENDAR", lineNumber)
+				}
+				sawCalendar = true
+			}
+			if component == "VEVENT" && len(stack) == 1 {
+				event = []property{}
+			}
+			stack = append(stack, component)
+			return nil
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return fmt.Errorf("line %d: unexpected END:%s", lineNumber, prop.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && len(stack) == 1 {
+				parsed, warnings, err := buildEvent(event, loc)
+				calendar.Warnings = append(calendar.Warnings, warnings...)
+				if err != nil {
+					calendar.Warnings = append(calendar.Warnings, err.Error())
+				} else {
+					calendar.Events = append(calendar.Events, parsed)
+				}
+				event = nil
+			}
+			return nil
+		}
+
+		if len(stack) == 0 {
+			return fmt.Errorf("line %d: expected BEGIN:VCALENDAR", lineNumber)
+		}
+		switch stack[len(stack)-1] {
+		case "VCALENDAR":
+			switch prop.name {
+			case "X-WR-CALNAME":
+				calendar.Name = unescapeText(prop.value)
+			case "REFRESH-INTERVAL":
+				if interval, err := parseDuration(prop.value); err == nil && interval > 0 {
+					calendar.RefreshInterval = interval
+				}
+			}
+		case "VEVENT":
+			event = append(event, prop)
+		}
+		return nil
+	}
+
+	// Long lines are folded: a line starting with a space or tab continues the previous one
+	var logical strings.Builder
+	logicalNumber, lineNumber := 0, 0
+	flush := func() error {
+		if logical.Len() == 0 {
+			return nil
+		}
+		line := logical.String()
+		logical.Reset()
+		return handle(logicalNumber, line)
+	}
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if logical.Len() > 0 {
+				if logical.Len()+len(line) > maxLineBytes {
+					return nil, fmt.Errorf("line %d: content line is too long", logicalNumber)
+				}
+				logical.WriteString(line[1:])
+			}
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		logical.WriteString(line)
+		logicalNumber = lineNumber
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if !sawCalendar {
+		return nil, fmt.Errorf("no VCALENDAR found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unexpected end of file inside %s", stack[len(stack)-1])
+	}
+	return calendar, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Parameter values may be quoted to contain ; : and , characters.
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return prop, fmt.Errorf("invalid content line %q", shortLine(line))
+	}
+	prop.name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return prop, fmt.Errorf("invalid parameter in %s", prop.name)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var values []string
+		for {
+			var value string
+			if strings.HasPrefix(rest, `"`) {
+				closing := strings.IndexByte(rest[1:], '"')
+				if closing < 0 {
+					return prop, fmt.Errorf("unterminated quoted parameter in %s", prop.name)
+				}
+				value, rest = rest[1:closing+1], rest[closing+2:]
+			} else {
+				stop := strings.IndexAny(rest, ",;:")
+				if stop < 0 {
+					return prop, fmt.Errorf("missing value in %s", prop.name)
+				}
+				value, rest = rest[:stop], rest[stop:]
+			}
+			values = append(values, value)
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			rest = rest[1:]
+		}
+		prop.params[name] = strings.Join(values, ",")
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return prop, fmt.Errorf("missing value in %s", prop.name)
+	}
+	prop.value = rest[1:]
+	return prop, nil
+}
+
+// buildEvent reads the properties of a VEVENT. Warnings report values read with a
+// fallback; an error means the event cannot be used.
+func buildEvent(props []property, loc *time.Location) (Event, []string, error) {
+	var event Event
+	var warnings []string
+	var start, end, recurrenceID *property
+	var duration string
+
+	for i := range props {
+		prop := &props[i]
+		switch prop.name {
+		case "UID":
+			event.UID = strings.TrimSpace(prop.value)
+		case "SUMMARY":
+			event.Summary = strings.TrimSpace(unescapeText(prop.value))
+		case "DESCRIPTION":
+			event.Description = strings.TrimSpace(unescapeText(prop.value))
+		case "LOCATION":
+			event.Location = strings.TrimSpace(unescapeText(prop.value))
+		case "STATUS":
+			event.Status = strings.ToUpper(strings.TrimSpace(prop.value))
+		case "DTSTART":
+			start = prop
+		case "DTEND":
+			end = prop
+		case "DURATION":
+			duration = prop.value
+		case "RECURRENCE-ID":
+			recurrenceID = prop
+		case "ORGANIZER":
+			event.OrganizerEmail = calendarAddress(prop.value)
+		case "ATTENDEE":
+			event.Attendees = append(event.Attendees, Attendee{
+				Email:  calendarAddress(prop.value),
+				Name:   prop.params["CN"],
+				Role:   upperOr(prop.params["ROLE"], RoleRequired),
+				Status: upperOr(prop.params["PARTSTAT"], PartStatNeedsAction),
+				Kind:   upperOr(prop.params["CUTYPE"], KindIndividual),
+			})
+		case "CATEGORIES":
+			for _, category := range splitText(prop.value) {
+				if category = strings.TrimSpace(category); category != "" {
+					event.Categories = append(event.Categories, category)
+				}
+			}
+		case "COLOR":
+			event.Color = strings.TrimSpace(prop.value)
+		case "LAST-MODIFIED":
+			if t, _, _, err := parseTime(strings.TrimSpace(prop.value), prop.params, loc); err == nil {
+				event.LastModified = t
+			}
+		}
+	}
+
+	if event.UID == "" {
+		return event, nil, fmt.Errorf("event %q has no UID", event.Summary)
+	}
+	if start == nil {
+		return event, nil, fmt.Errorf("event %s has no DTSTART", event.UID)
+	}
+
+	// times reads the values of a date or date-time property, reporting each unknown
+	// timezone once per event
+	unknownZones := map[string]bool{}
+	times := func(prop *property) ([]time.Time, bool, error) {
+		var values []time.Time
+		allDay := false
+		for value := range strings.SplitSeq(prop.value, ",") {
+			t, date, fallback, err := parseTime(strings.TrimSpace(value), prop.params, loc)
+			if err != nil {
+				return nil, false, fmt.Errorf("event %s has an invalid %s: %w", event.UID, prop.name, err)
+			}
+			if fallback && !unknownZones[prop.params["TZID"]] {
+				unknownZones[prop.params["TZID"]] = true
+				warnings = append(warnings, fmt.Sprintf("event %s: unknown timezone %q, read in %s", event.UID, prop.params["TZID"], loc))
+			}
+			values = append(values, t)
+			allDay = date
+		}
+		return values, allDay, nil
+	}
+
+	values, allDay, err := times(start)
+	if err != nil {
+		return event, warnings, err
+	}
+	event.Start, event.AllDay = values[0], allDay
+
+	switch {
+	case end != nil:
+		values, _, err := times(end)
+		if err != nil {
+			return event, warnings, err
+		}
+		event.End = values[0]
+	case duration != "":
+		length, err := parseDuration(duration)
+		if err != nil {
+			return event, warnings, fmt.Errorf("event %s has an invalid DURATION: %w", event.UID, err)
+		}
+		event.End = event.Start.Add(length)
+	case event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+	if event.End.Before(event.Start) {
+		return event, warnings, fmt.Errorf("event %s ends before it starts", event.UID)
+	}
+
+	if recurrenceID != nil {
+		values, _, err := times(recurrenceID)
+		if err != nil {
+			return event, warnings, err
+		}
+		event.RecurrenceID = &values[0]
+	}
+
+	for i := range props {
+		switch props[i].name {
+		case "RRULE":
+			if event.Rule != nil {
+				warnings = append(warnings, fmt.Sprintf("event %s: only the first RRULE is used", event.UID))
+				continue
+			}
+			rule, err := recurrence.Parse(props[i].value)
+			if err != nil {
+				return event, warnings, fmt.Errorf("event %s has an unsupported RRULE: %w", event.UID, err)
+			}
+			event.Rule = rule
+		case "EXDATE":
+			values, _, err := times(&props[i])
+			if err != nil {
+				return event, warnings, err
+			}
+			event.ExDates = append(event.ExDates, values...)
+		case "RDATE":
+			warnings = append(warnings, fmt.Sprintf("event %s: RDATE is not supported, extra dates are ignored", event.UID))
+		}
+	}
+
+	return event, warnings, nil
+}
+
+// parseTime reads a DATE or DATE-TIME value. fallback reports a TZID that is not a
+// known timezone, in which case the time is read in loc.
+func parseTime(value string, params map[string]string, loc *time.Location) (t time.Time, date, fallback bool, err error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, loc)
+		return t, true, false, err
+	}
+
+	if utc, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err = time.Parse("20060102T150405", utc)
+		return t.In(loc), false, false, err
+	}
+
+	in := loc
+	if tzid := params["TZID"]; tzid != "" {
+		zone, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			fallback = true
+		} else {
+			in = zone
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, in)
+	return t, false, fallback, err
+}
+
+// parseDuration reads an RFC 5545 duration. Days and weeks count 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil || strings.Join(match[2:], "") == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		total = -total
+	}
+	return total, nil
+}
+
+// unescapeText decodes the backslash escapes of a TEXT value
+func unescapeText(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// splitText splits a multi-valued TEXT value at its unescaped commas and decodes each value
+func splitText(value string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescapeText(value[start:]))
+}
+
+// calendarAddress returns the email of a CAL-ADDRESS value such as mailto:jane@example.com
+func calendarAddress(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		value = value[len("mailto:"):]
+	}
+	return strings.ToLower(value)
+}
+
+// upperOr returns value in upper case, or fallback when it is empty
+func upperOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return strings.ToUpper(value)
+}
+
+// shortLine cuts a line for error messages
+func shortLine(line string) string {
+	if len(line) > 40 {
+		return line[:40] + "..."
+	}
+	return line
+}
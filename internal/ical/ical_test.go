@@ -0,0 +1,193 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/recurrence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"X-WR-CALNAME:jane@example.com\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:America/Sao_Paulo\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19700101T000000\r\n" +
+	"TZOFFSETFROM:-0300\r\n" +
+	"TZOFFSETTO:-0300\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=America/Sao_Paulo:20250310T100000\r\n" +
+	"DTEND;TZID=America/Sao_Paulo:20250310T110000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO\r\n" +
+	"EXDATE;TZID=America/Sao_Paulo:20250317T100000\r\n" +
+	"UID:standup@example.com\r\n" +
+	"ORGANIZER;CN=John Roe:mailto:john@example.com\r\n" +
+	"ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;CN=\"Doe, Jane\r\n" +
+	" \":mailto:Jane@Example.com\r\n" +
+	"ATTENDEE;CUTYPE=ROOM;ROLE=NON-PARTICIPANT;CN=Room 1:mailto:room1@resource.example.com\r\n" +
+	"SUMMARY:Weekly planning\\, part 1\r\n" +
+	"DESCRIPTION:Agenda:\\n- review\\; plan\r\n" +
+	"LOCATION:Room 1\r\n" +
+	"STATUS:CONFIRMED\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250312\r\n" +
+	"DTEND;VALUE=DATE:20250313\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250311T170000Z\r\n" +
+	"DURATION:PT45M\r\n" +
+	"UID:review@example.com\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	calendar, err := Parse(strings.NewReader(feed), berlin)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", calendar.Name)
+	assert.Empty(t, calendar.Warnings)
+	require.Len(t, calendar.Events, 3)
+
+	standup := calendar.Events[0]
+	assert.Equal(t, "standup@example.com", standup.UID)
+	assert.Equal(t, "Weekly planning, part 1", standup.Summary)
+	assert.Equal(t, "Agenda:\n- review; plan", standup.Description, "the alarm description is not the event's")
+	assert.Equal(t, "Room 1", standup.Location)
+	assert.Equal(t, StatusConfirmed, standup.Status)
+	assert.Equal(t, time.Date(2025, 3, 10, 10, 0, 0, 0, saoPaulo), standup.Start)
+	assert.Equal(t, saoPaulo, standup.Start.Location(), "times with a TZID keep their timezone")
+	assert.Equal(t, time.Hour, standup.End.Sub(standup.Start))
+	assert.False(t, standup.AllDay)
+	require.NotNil(t, standup.Rule)
+	assert.Equal(t, recurrence.Weekly, standup.Rule.Freq)
+	require.Len(t, standup.ExDates, 1)
+	assert.True(t, standup.ExDates[0].Equal(time.Date(2025, 3, 17, 13, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "john@example.com", standup.OrganizerEmail)
+	assert.Equal(t, []Attendee{
+		{Email: "jane@example.com", Name: "Doe, Jane", Role: RoleRequired, Status: PartStatAccepted, Kind: KindIndividual},
+		{Email: "room1@resource.example.com", Name: "Room 1", Role: RoleNonParticipant, Status: PartStatNeedsAction, Kind: KindRoom},
+	}, standup.Attendees, "folded lines are joined, even inside quoted parameters")
+	assert.Equal(t, PartStatAccepted, standup.Attendee("JANE@example.com").Status)
+	assert.Nil(t, standup.Attendee("john@example.com"))
+
+	holiday := calendar.Events[1]
+	assert.True(t, holiday.AllDay)
+	assert.Equal(t, time.Date(2025, 3, 12, 0, 0, 0, 0, berlin), holiday.Start, "dates are read in the given location")
+	assert.Equal(t, time.Date(2025, 3, 13, 0, 0, 0, 0, berlin), holiday.End)
+
+	review := calendar.Events[2]
+	assert.True(t, review.Start.Equal(time.Date(2025, 3, 11, 17, 0, 0, 0, time.UTC)))
+	assert.Equal(t, berlin, review.Start.Location(), "UTC times are moved to the given location")
+	assert.Equal(t, 45*time.Minute, review.End.Sub(review.Start))
+}
+
+func TestParse_EventProblems(t *testing.T) {
+	calendar, err := Parse(strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:No UID",
+		"DTSTART:20250310T100000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:no-start",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-rule",
+		"DTSTART:20250310T100000Z",
+		"RRULE:FREQ=HOURLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:backwards",
+		"DTSTART:20250310T100000Z",
+		"DTEND:20250310T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:windows-zone",
+		"DTSTART;TZID=W. Europe Standard Time:20250310T100000",
+		"DTEND;TZID=W. Europe Standard Time:20250310T103000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:instant",
+		"DTSTART:20250310T100000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")), time.UTC)
+	require.NoError(t, err, "a bad event does not spoil the file")
+
+	require.Len(t, calendar.Events, 2)
+	assert.Equal(t, "windows-zone", calendar.Events[0].UID)
+	assert.Equal(t, time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC), calendar.Events[0].Start, "unknown timezones fall back to the given location")
+	assert.Equal(t, "instant", calendar.Events[1].UID)
+	assert.Equal(t, calendar.Events[1].Start, calendar.Events[1].End, "events without an end or duration take no time")
+
+	warnings := strings.Join(calendar.Warnings, "\n")
+	assert.Contains(t, warnings, `event "No UID" has no UID`)
+	assert.Contains(t, warnings, "event no-start has no DTSTART")
+	assert.Contains(t, warnings, "event bad-rule has an unsupported RRULE")
+	assert.Contains(t, warnings, "event backwards ends before it starts")
+	assert.Contains(t, warnings, `event windows-zone: unknown timezone "W. Europe Standard Time"`)
+	assert.Equal(t, 1, strings.Count(warnings, "unknown timezone"), "each unknown timezone is reported once per event")
+}
+
+func TestParse_InvalidFiles(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":          "",
+		"not a calendar": "BEGIN:VCARD\nFN:Jane\nEND:VCARD\n",
+		"unbalanced":     "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+		"truncated":      "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\n",
+		"no colon":       "BEGIN:VCALENDAR\nthis is not a content line\nEND:VCALENDAR\n",
+		"open quote":     "BEGIN:VCALENDAR\nX-TEST;CN=\"Jane:x\nEND:VCALENDAR\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(content), time.UTC)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"PT1H30M":  90 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P1W":      7 * 24 * time.Hour,
+		"P1DT2H":   26 * time.Hour,
+		"-PT15M":   -15 * time.Minute,
+		"PT0S":     0,
+		"+PT1M30S": 90 * time.Second,
+	} {
+		duration, err := parseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, duration, value)
+	}
+
+	for _, value := range []string{"", "P", "PT", "1H", "PT1.5H", "P1Y"} {
+		_, err := parseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	assert.Equal(t, "plain", unescapeText("plain"))
+	assert.Equal(t, "a, b; c\\d\ne\nf", unescapeText(`a\, b\; c\\d\ne\Nf`))
+	assert.Equal(t, `trailing\`, unescapeText(`trailing\`))
+}
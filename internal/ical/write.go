@@ -0,0 +1,150 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// productID identifies the application that wrote a calendar (PRODID)
+	productID = "-//EngLog//EngLog//EN"
+	// maxLineOctets is the longest content line before it is folded
+	maxLineOctets = 75
+	// utcLayout formats DATE-TIME values in UTC
+	utcLayout = "20060102T150405Z"
+)
+
+// Write writes the calendar as an iCalendar file for publishing. Times are written
+// in UTC and all-day events as dates. Recurrence rules, exceptions and attendees
+// are not written: each event stands for itself.
+func Write(w io.Writer, calendar *Calendar) error {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", productID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		cw.line("X-WR-CALNAME", escapeText(calendar.Name))
+		cw.line("NAME", escapeText(calendar.Name))
+	}
+	if calendar.RefreshInterval > 0 {
+		cw.line("REFRESH-INTERVAL;VALUE=DURATION", formatDuration(calendar.RefreshInterval))
+		cw.line("X-PUBLISHED-TTL", formatDuration(calendar.RefreshInterval))
+	}
+
+	now := time.Now()
+	for i := range calendar.Events {
+		event := &calendar.Events[i]
+		stamp := event.LastModified
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", event.UID)
+		cw.line("DTSTAMP", stamp.UTC().Format(utcLayout))
+		if event.AllDay {
+			cw.line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+			cw.line("DTEND;VALUE=DATE", event.End.Format("20060102"))
+		} else {
+			cw.line("DTSTART", event.Start.UTC().Format(utcLayout))
+			cw.line("DTEND", event.End.UTC().Format(utcLayout))
+		}
+		cw.line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			cw.line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			cw.line("LOCATION", escapeText(event.Location))
+		}
+		if event.Status != "" {
+			cw.line("STATUS", event.Status)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for j, category := range event.Categories {
+				categories[j] = escapeText(category)
+			}
+			cw.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		if event.Color != "" {
+			cw.line("COLOR", event.Color)
+		}
+		if !event.LastModified.IsZero() {
+			cw.line("LAST-MODIFIED", event.LastModified.UTC().Format(utcLayout))
+		}
+		cw.line("END", "VEVENT")
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// contentWriter writes folded content lines, keeping the first error
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes NAME:VALUE, folding it into lines of at most 75 octets that
+// continue with a space, without splitting characters
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(line[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	_, cw.err = cw.w.WriteString(line + "\r\n")
+}
+
+// escapeText encodes a TEXT value, the reverse of unescapeText
+func escapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// formatDuration writes a positive duration such as PT1H30M
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours, minutes, seconds := d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 {
+		b.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+	return b.String()
+}
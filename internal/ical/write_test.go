@@ -0,0 +1,110 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	modified := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	calendar := &Calendar{
+		Name:            "Work, logged",
+		RefreshInterval: 90 * time.Minute,
+		Events: []Event{
+			{
+				UID:          "entry-1@englog",
+				Summary:      "Review; refunds, PR",
+				Description:  "Checked the retries\n" + strings.Repeat("é", 60),
+				Start:        time.Date(2025, 3, 10, 9, 0, 0, 0, saoPaulo),
+				End:          time.Date(2025, 3, 10, 10, 30, 0, 0, saoPaulo),
+				Categories:   []string{"Payments, API", "code_review"},
+				Color:        "steelblue",
+				LastModified: modified,
+			},
+			{
+				UID:     "offsite@englog",
+				Summary: "Offsite",
+				Start:   time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, calendar))
+	output := buf.String()
+
+	assert.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:"))
+	assert.True(t, strings.HasSuffix(output, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, output, "X-WR-CALNAME:Work\\, logged\r\n")
+	assert.Contains(t, output, "REFRESH-INTERVAL;VALUE=DURATION:PT1H30M\r\n")
+	assert.Contains(t, output, "DTSTART:20250310T120000Z\r\n", "times are written in UTC")
+	assert.Contains(t, output, "SUMMARY:Review\\; refunds\\, PR\r\n")
+	assert.Contains(t, output, "CATEGORIES:Payments\\, API,code_review\r\n")
+	assert.Contains(t, output, "DTSTAMP:20250310T180000Z\r\n")
+	assert.Contains(t, output, "DTSTART;VALUE=DATE:20250312\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "long lines are folded")
+	}
+
+	parsed, err := Parse(strings.NewReader(output), time.UTC)
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Warnings)
+	assert.Equal(t, calendar.Name, parsed.Name)
+	assert.Equal(t, calendar.RefreshInterval, parsed.RefreshInterval)
+	require.Len(t, parsed.Events, 2)
+
+	event := parsed.Events[0]
+	assert.Equal(t, calendar.Events[0].Summary, event.Summary)
+	assert.Equal(t, calendar.Events[0].Description, event.Description, "folding does not split characters")
+	assert.True(t, event.Start.Equal(calendar.Events[0].Start))
+	assert.True(t, event.End.Equal(calendar.Events[0].End))
+	assert.Equal(t, calendar.Events[0].Categories, event.Categories)
+	assert.Equal(t, "steelblue", event.Color)
+	assert.True(t, event.LastModified.Equal(modified))
+
+	assert.True(t, parsed.Events[1].AllDay)
+	assert.Equal(t, calendar.Events[1].End, parsed.Events[1].End)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "PT1H", formatDuration(time.Hour))
+	assert.Equal(t, "PT15M30S", formatDuration(15*time.Minute+30*time.Second))
+	assert.Equal(t, "P1DT2H", formatDuration(26*time.Hour))
+	assert.Equal(t, "P7D", formatDuration(7*24*time.Hour))
+
+	for _, d := range []time.Duration{time.Hour, 90 * time.Minute, 26*time.Hour + time.Second} {
+		parsed, err := parseDuration(formatDuration(d))
+		require.NoError(t, err)
+		assert.Equal(t, d, parsed)
+	}
+}
+
+func TestColorName(t *testing.T) {
+	tests := map[string]string{
+		"#ff0000": "red",
+		"#3498db": "dodgerblue",
+		"#FFF":    "white",
+		"#2e8b57": "seagreen",
+	}
+	for hex, want := range tests {
+		name, ok := ColorName(hex)
+		assert.True(t, ok, hex)
+		assert.Equal(t, want, name, hex)
+	}
+
+	for _, hex := range []string{"", "#12345", "blue", "#zzzzzz"} {
+		_, ok := ColorName(hex)
+		assert.False(t, ok, hex)
+	}
+}
@@ -0,0 +1,169 @@
+// Package mailer sends email over SMTP and renders the notification emails EngLog
+// sends. Sink is a small in-memory SMTP server to test them against locally.
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultPort is the SMTP submission port used when none is configured
+	DefaultPort = 587
+	// implicitTLSPort is the port on which the connection is TLS from the start
+	implicitTLSPort = 465
+	// defaultTimeout bounds one conversation with the SMTP server
+	defaultTimeout = 30 * time.Second
+)
+
+// Config holds the SMTP server emails are sent through
+type Config struct {
+	Host      string
+	Port      int
+	Username  string // Credentials are only sent when set
+	Password  string
+	FromName  string
+	FromEmail string
+	Timeout   time.Duration
+}
+
+// Message is one email. Text is required; HTML, when set, is sent as an alternative
+// the recipient's mail client may prefer.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender sends email messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTP sends messages through an SMTP server. On port 465 the connection uses TLS
+// from the start; on other ports STARTTLS is used whenever the server offers it.
+// net/smtp only sends credentials over TLS or to a server on localhost.
+type SMTP struct {
+	cfg  Config
+	from mail.Address
+}
+
+// NewSMTP creates an SMTP sender
+func NewSMTP(cfg Config) (*SMTP, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	from, err := mail.ParseAddress(cfg.FromEmail)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.FromEmail, err)
+	}
+	from.Name = cfg.FromName
+	if cfg.Port <= 0 {
+		cfg.Port = DefaultPort
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &SMTP{cfg: cfg, from: *from}, nil
+}
+
+// Send delivers a message to its recipient
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return &textproto.Error{Code: 501, Msg: fmt.Sprintf("invalid recipient %q: %v", msg.To, err)}
+	}
+	data, err := buildMessage(s.from, *to, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	deadline := time.Now().Add(s.cfg.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}
+	if s.cfg.Port == implicitTLSPort {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("SMTP greeting failed: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Hello(helloName()); err != nil {
+		return fmt.Errorf("SMTP EHLO failed: %w", err)
+	}
+	if s.cfg.Port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+			}
+		}
+	}
+	if s.cfg.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP server does not offer authentication")
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP RCPT TO failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+	return client.Quit()
+}
+
+// Permanent reports whether sending failed for good, because the SMTP server
+// rejected the message with a 5xx reply. Other failures may pass when retried.
+func Permanent(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
+
+// helloName is the name this host introduces itself with
+func helloName() string {
+	if name, err := os.Hostname(); err == nil && name != "" {
+		return name
+	}
+	return "localhost"
+}
@@ -0,0 +1,195 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSink starts a sink on a free local port and a sender pointed at it
+func newTestSink(t *testing.T, cfg Config) (*Sink, *SMTP) {
+	t.Helper()
+	sink, err := NewSink("127.0.0.1:0", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sink.Close() })
+
+	host, port, err := net.SplitHostPort(sink.Addr().String())
+	require.NoError(t, err)
+	cfg.Host = host
+	cfg.Port, err = strconv.Atoi(port)
+	require.NoError(t, err)
+	if cfg.FromEmail == "" {
+		cfg.FromEmail = "noreply@englog.dev"
+	}
+
+	sender, err := NewSMTP(cfg)
+	require.NoError(t, err)
+	return sink, sender
+}
+
+func TestSMTP_SendToSink(t *testing.T) {
+	sink, sender := newTestSink(t, Config{FromName: "EngLog Ünïcode", Username: "user", Password: "secret"})
+
+	err := sender.Send(context.Background(), Message{
+		To:      "Ada <ada@example.com>",
+		Subject: "Your week: 3 entries – ok",
+		Text:    "Hi Ada,\n\n" + strings.Repeat("long line ", 20) + "\n.\nDone ✓",
+		HTML:    "<p>Hi Ada</p>",
+	})
+	require.NoError(t, err)
+
+	messages := sink.Messages()
+	require.Len(t, messages, 1)
+	msg := messages[0]
+	assert.Equal(t, "noreply@englog.dev", msg.From)
+	assert.Equal(t, []string{"ada@example.com"}, msg.To)
+	assert.Equal(t, "Your week: 3 entries – ok", msg.Subject())
+
+	text, err := msg.Text()
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Ada,\n\n"+strings.Repeat("long line ", 20)+"\n.\nDone ✓", text, "long lines and lone dots survive encoding")
+	html, err := msg.HTML()
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi Ada</p>", html)
+
+	data := string(msg.Data)
+	assert.Contains(t, data, "Auto-Submitted: auto-generated")
+	assert.Contains(t, data, "=?utf-8?q?EngLog_=C3=9Cn=C3=AFcode?= <noreply@englog.dev>")
+	assert.Contains(t, data, "Message-ID: <")
+}
+
+func TestSMTP_SendTextOnly(t *testing.T) {
+	sink, sender := newTestSink(t, Config{})
+	require.NoError(t, sender.Send(context.Background(), Message{To: "ada@example.com", Subject: "Hello", Text: "Plain"}))
+
+	messages := sink.Messages()
+	require.Len(t, messages, 1)
+	text, err := messages[0].Text()
+	require.NoError(t, err)
+	assert.Equal(t, "Plain", strings.TrimSpace(text))
+	_, err = messages[0].HTML()
+	assert.Error(t, err)
+}
+
+func TestSMTP_SendFailures(t *testing.T) {
+	_, sender := newTestSink(t, Config{})
+
+	err := sender.Send(context.Background(), Message{To: "not an address", Subject: "Hello", Text: "Hi"})
+	assert.Error(t, err)
+	assert.True(t, Permanent(err), "an invalid recipient is not retried")
+
+	err = sender.Send(context.Background(), Message{To: "ada@example.com", Subject: "Hello"})
+	assert.ErrorContains(t, err, "no text")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().(*net.TCPAddr)
+	require.NoError(t, listener.Close())
+	closed, err := NewSMTP(Config{Host: "127.0.0.1", Port: addr.Port, FromEmail: "noreply@englog.dev", Timeout: time.Second})
+	require.NoError(t, err)
+	err = closed.Send(context.Background(), Message{To: "ada@example.com", Subject: "Hello", Text: "Hi"})
+	assert.Error(t, err)
+	assert.False(t, Permanent(err), "an unreachable server is retried")
+}
+
+func TestNewSMTP_Validation(t *testing.T) {
+	_, err := NewSMTP(Config{FromEmail: "noreply@englog.dev"})
+	assert.ErrorContains(t, err, "host")
+
+	_, err = NewSMTP(Config{Host: "localhost", FromEmail: "not an address"})
+	assert.ErrorContains(t, err, "sender")
+
+	sender, err := NewSMTP(Config{Host: "localhost", FromEmail: "noreply@englog.dev"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPort, sender.cfg.Port)
+}
+
+func TestPermanent(t *testing.T) {
+	assert.True(t, Permanent(&textproto.Error{Code: 550, Msg: "mailbox unavailable"}))
+	assert.False(t, Permanent(&textproto.Error{Code: 451, Msg: "try again later"}))
+	assert.False(t, Permanent(context.DeadlineExceeded))
+}
+
+func TestRender(t *testing.T) {
+	periodStart := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WeeklyDigest", func(t *testing.T) {
+		msg, err := Render("weekly_digest", models.WeeklyDigest{
+			FirstName:   "Ada",
+			PeriodStart: periodStart,
+			PeriodEnd:   periodStart.AddDate(0, 0, 6),
+			Entries:     12,
+			Minutes:     570,
+			Report: &models.WeeklyDigestReport{
+				Summary:         "A focused week on the <billing> migration.",
+				KeyInsights:     []string{"Most time went to reviews"},
+				Recommendations: []string{"Block mornings for deep work"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Your week in EngLog: Mon, Mar 3 – Sun, Mar 9", msg.Subject)
+		assert.Contains(t, msg.Text, "**12 entries** logged over **9.5h**")
+		assert.Contains(t, msg.Text, "## Key insights\n\n- Most time went to reviews")
+		assert.Contains(t, msg.Text, "- Block mornings for deep work")
+		assert.Contains(t, msg.HTML, "<h2>Summary</h2>")
+		assert.Contains(t, msg.HTML, "&lt;billing&gt;", "report text is escaped")
+	})
+
+	t.Run("WeeklyDigestWithoutReport", func(t *testing.T) {
+		msg, err := Render("weekly_digest", models.WeeklyDigest{
+			FirstName:   "Ada",
+			PeriodStart: periodStart,
+			PeriodEnd:   periodStart.AddDate(0, 0, 6),
+			Entries:     1,
+			Minutes:     60,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, msg.Text, "**1 entry** logged over **1h**")
+		assert.Contains(t, msg.Text, "could not be generated")
+		assert.NotContains(t, msg.Text, "## Summary")
+	})
+
+	t.Run("DailyReminder", func(t *testing.T) {
+		msg, err := Render("daily_reminder", models.DailyReminder{FirstName: "Ada", Date: periodStart, Time: "18:00"})
+		require.NoError(t, err)
+		assert.Equal(t, "Nothing logged for Mon, Mar 3 yet", msg.Subject)
+		assert.Contains(t, msg.Text, "at 18:00")
+	})
+
+	t.Run("TaskFailure", func(t *testing.T) {
+		msg, err := Render("task_failure", models.TaskFailureNotice{
+			FirstName: "Ada",
+			TaskID:    "insight_1",
+			Error:     "model unavailable",
+			FailedAt:  periodStart.Add(14*time.Hour + 5*time.Minute),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "An insight could not be generated", msg.Subject)
+		assert.Contains(t, msg.Text, "at 14:05")
+		assert.Contains(t, msg.Text, "- Error: model unavailable")
+		assert.Contains(t, msg.HTML, "<code>insight_1</code>")
+	})
+
+	t.Run("Test", func(t *testing.T) {
+		msg, err := Render("test", models.TestEmail{
+			FirstName:   "Ada",
+			Preferences: models.NotificationPreferences{WeeklyDigest: true, ReminderTime: "18:00"},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, msg.Text, "- Weekly digest: on")
+		assert.Contains(t, msg.Text, "- Daily reminder: off (at 18:00)")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := Render("newsletter", nil)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
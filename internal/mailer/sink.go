@@ -0,0 +1,290 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sinkCommandTimeout bounds how long the sink waits for a client command
+	sinkCommandTimeout = 5 * time.Minute
+	// sinkMaxMessageBytes bounds the size of a message the sink accepts
+	sinkMaxMessageBytes = 10 << 20
+)
+
+// SinkMessage is a message received by a Sink
+type SinkMessage struct {
+	From       string
+	To         []string
+	Data       []byte // The message as sent, with headers
+	ReceivedAt time.Time
+}
+
+// Sink is a minimal SMTP server that accepts every message and keeps it in memory.
+// It has no TLS and accepts any credentials, so point SMTP_HOST at it to try email
+// notifications locally, and never expose it.
+type Sink struct {
+	listener  net.Listener
+	onMessage func(SinkMessage)
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	closed   bool
+	messages []SinkMessage
+}
+
+// NewSink starts a sink listening on addr, such as "127.0.0.1:1025". onMessage,
+// when set, is called with every message received.
+func NewSink(addr string, onMessage func(SinkMessage)) (*Sink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &Sink{listener: listener, onMessage: onMessage, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the sink listens on
+func (s *Sink) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Messages returns the messages received so far
+func (s *Sink) Messages() []SinkMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SinkMessage(nil), s.messages...)
+}
+
+// Close stops the sink, closes open connections and waits for them to end
+func (s *Sink) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (s *Sink) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+			}()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle speaks SMTP with one client
+func (s *Sink) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) bool {
+		return tp.PrintfLine(format, args...) == nil
+	}
+
+	var from string
+	var to []string
+	if !reply("220 englog-sink ESMTP ready") {
+		return
+	}
+	for {
+		_ = conn.SetDeadline(time.Now().Add(sinkCommandTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-englog-sink\r\n250-8BITMIME\r\n250-AUTH PLAIN\r\n250 SIZE %d", sinkMaxMessageBytes)
+		case "HELO":
+			ok = reply("250 englog-sink")
+		case "AUTH":
+			if _, response, _ := strings.Cut(arg, " "); response == "" {
+				// The client sends its credentials after an empty challenge
+				if !reply("334 ") {
+					return
+				}
+				if _, err := tp.ReadLine(); err != nil {
+					return
+				}
+			}
+			ok = reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			from, to = sinkAddress(arg), nil
+			ok = reply("250 2.1.0 OK")
+		case "RCPT":
+			if from == "" {
+				ok = reply("503 5.5.1 MAIL first")
+				break
+			}
+			to = append(to, sinkAddress(arg))
+			ok = reply("250 2.1.5 OK")
+		case "DATA":
+			if len(to) == 0 {
+				ok = reply("503 5.5.1 RCPT first")
+				break
+			}
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := io.ReadAll(io.LimitReader(tp.DotReader(), sinkMaxMessageBytes+1))
+			if err != nil {
+				return
+			}
+			if len(data) > sinkMaxMessageBytes {
+				// The rest of the message is still on the way, so end the session
+				reply("552 5.3.4 Message too big")
+				return
+			}
+			s.receive(SinkMessage{From: from, To: to, Data: data, ReceivedAt: time.Now()})
+			from, to = "", nil
+			ok = reply("250 2.0.0 OK: queued")
+		case "RSET":
+			from, to = "", nil
+			ok = reply("250 2.0.0 OK")
+		case "NOOP":
+			ok = reply("250 2.0.0 OK")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			ok = reply("502 5.5.2 Command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// receive stores a message and passes it to onMessage
+func (s *Sink) receive(msg SinkMessage) {
+	s.mu.Lock()
+	s.messages = append(s.messages, msg)
+	s.mu.Unlock()
+
+	if s.onMessage != nil {
+		s.onMessage(msg)
+	}
+}
+
+// sinkAddress extracts the address of a MAIL FROM:<...> or RCPT TO:<...> argument
+func sinkAddress(arg string) string {
+	start := strings.IndexByte(arg, '<')
+	end := strings.IndexByte(arg, '>')
+	if start < 0 || end < start {
+		return strings.TrimSpace(arg)
+	}
+	return arg[start+1 : end]
+}
+
+// Subject returns the decoded subject of the message
+func (m SinkMessage) Subject() string {
+	msg, err := mail.ReadMessage(bytes.NewReader(m.Data))
+	if err != nil {
+		return ""
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		return msg.Header.Get("Subject")
+	}
+	return subject
+}
+
+// Text returns the decoded plain text body of the message
+func (m SinkMessage) Text() (string, error) {
+	return m.body("text/plain")
+}
+
+// HTML returns the decoded HTML body of the message, if it has one
+func (m SinkMessage) HTML() (string, error) {
+	return m.body("text/html")
+}
+
+// body returns the decoded body part of the given media type
+func (m SinkMessage) body(mediaType string) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(m.Data))
+	if err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+	contentType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return "", fmt.Errorf("invalid content type: %w", err)
+	}
+
+	if !strings.HasPrefix(contentType, "multipart/") {
+		if contentType != mediaType {
+			return "", fmt.Errorf("message has no %s part", mediaType)
+		}
+		return readBody(msg.Body, msg.Header.Get("Content-Transfer-Encoding"))
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		// NextPart decodes quoted-printable parts itself
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("message has no %s part", mediaType)
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid multipart message: %w", err)
+		}
+		if partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type")); partType == mediaType {
+			return readBody(part, "")
+		}
+	}
+}
+
+// readBody reads a body in the given transfer encoding
+func readBody(r io.Reader, encoding string) (string, error) {
+	if strings.EqualFold(encoding, "quoted-printable") {
+		r = quotedprintable.NewReader(r)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(string(body), "\r\n", "\n"), nil
+}
@@ -0,0 +1,117 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/garnizeh/englog/internal/markdown"
+)
+
+// templateExt is the file extension of email templates
+const templateExt = ".md.tmpl"
+
+//go:embed templates/*.md.tmpl
+var templateFiles embed.FS
+
+// templateFuncs are the functions available to email templates
+var templateFuncs = template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("Mon, Jan 2") },
+	"hours": formatHours,
+	"plural": func(n int64, singular, plural string) string {
+		if n == 1 {
+			return singular
+		}
+		return plural
+	},
+	"onoff": func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	},
+}
+
+// templates holds the email templates by name. Each template is Markdown that
+// also defines a "subject" block; the Markdown is the text part of the email and
+// the HTML part is rendered from it.
+var templates = mustLoadTemplates()
+
+// emailPage wraps the HTML part of emails
+var emailPage = htmltemplate.Must(htmltemplate.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Subject }}</title>
+</head>
+<body style="font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; max-width: 40rem; margin: 0 auto; padding: 1rem; line-height: 1.5; color: #222;">
+{{ .Body }}</body>
+</html>
+`))
+
+// mustLoadTemplates parses the embedded templates
+func mustLoadTemplates() map[string]*template.Template {
+	entries, err := fs.ReadDir(templateFiles, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]*template.Template, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), templateExt)
+		loaded[name] = template.Must(template.New(name).Funcs(templateFuncs).Option("missingkey=error").
+			ParseFS(templateFiles, "templates/"+entry.Name()))
+	}
+	return loaded
+}
+
+// Render renders the named email template with data, returning a message
+// without recipient
+func Render(name string, data any) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("email template not found: %s", name)
+	}
+
+	var subject, text bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to execute subject of email template %s: %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&text, name+templateExt, data); err != nil {
+		return Message{}, fmt.Errorf("failed to execute email template %s: %w", name, err)
+	}
+
+	msg := Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}
+
+	var html bytes.Buffer
+	if err := emailPage.Execute(&html, struct {
+		Subject string
+		Body    htmltemplate.HTML
+	}{
+		Subject: msg.Subject,
+		// markdown.Render escapes all text and only links to safe URLs
+		Body: htmltemplate.HTML(markdown.Render(msg.Text)),
+	}); err != nil {
+		return Message{}, fmt.Errorf("failed to render HTML of email template %s: %w", name, err)
+	}
+	msg.HTML = html.String()
+
+	return msg, nil
+}
+
+// formatHours formats minutes as hours, such as "1.5h"
+func formatHours(minutes int64) string {
+	hours := float64(minutes) / 60
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%.0fh", hours)
+	}
+	return fmt.Sprintf("%.1fh", hours)
+}
@@ -0,0 +1,183 @@
+// Package markdown renders the small Markdown subset used in log entry
+// descriptions to safe HTML.
+//
+// Supported blocks: ATX headings, paragraphs, fenced code blocks, block
+// quotes, ordered and unordered lists. Supported inlines: code spans,
+// **strong**, *emphasis* / _emphasis_, [links](https://...) and bare
+// http(s) URLs. All text is HTML-escaped and link targets are limited to
+// http, https and mailto, so the output can be embedded without further
+// sanitizing.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe     = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	fenceRe       = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+-]*)\\s*$")
+	codeSpanRe    = regexp.MustCompile("`([^`]+)`")
+	linkRe        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongRe      = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emphasisRe    = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	bareURLRe     = regexp.MustCompile(`(^|[\s(])(https?://[^\s<]+[^\s<.,;:!?)])`)
+	placeholderRe = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// Render converts Markdown source to HTML
+func Render(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fenceRe.MatchString(line):
+			m := fenceRe.FindStringSubmatch(line)
+			fence, lang := m[1], m[2]
+			i++
+			var code []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != fence {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence (or end of input)
+			if lang != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			out.WriteString("</code></pre>\n")
+
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+			i++
+
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+				i++
+			}
+			out.WriteString("<blockquote>\n" + Render(strings.Join(quoted, "\n")) + "</blockquote>\n")
+
+		case unorderedRe.MatchString(line):
+			i = renderList(&out, lines, i, unorderedRe, "ul")
+
+		case orderedRe.MatchString(line):
+			i = renderList(&out, lines, i, orderedRe, "ol")
+
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines[i]) {
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			out.WriteString("<p>" + renderInline(strings.Join(para, "\n")) + "</p>\n")
+		}
+	}
+
+	return out.String()
+}
+
+// renderList writes consecutive list items matching re and returns the next line index
+func renderList(out *strings.Builder, lines []string, i int, re *regexp.Regexp, tag string) int {
+	out.WriteString("<" + tag + ">\n")
+	for i < len(lines) && re.MatchString(lines[i]) {
+		item := re.FindStringSubmatch(lines[i])[1]
+		i++
+		// Indented lines continue the previous item
+		for i < len(lines) && strings.HasPrefix(lines[i], "  ") && !re.MatchString(lines[i]) && strings.TrimSpace(lines[i]) != "" {
+			item += "\n" + strings.TrimSpace(lines[i])
+			i++
+		}
+		out.WriteString("<li>" + renderInline(item) + "</li>\n")
+	}
+	out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// startsBlock reports whether a line begins a block other than a paragraph
+func startsBlock(line string) bool {
+	return fenceRe.MatchString(line) || headingRe.MatchString(line) ||
+		strings.HasPrefix(strings.TrimSpace(line), ">") ||
+		unorderedRe.MatchString(line) || orderedRe.MatchString(line)
+}
+
+// renderInline renders inline markup. Code spans and links are replaced by
+// placeholders first so their content is not processed as emphasis.
+func renderInline(text string) string {
+	text = strings.ReplaceAll(text, "\x00", "")
+
+	var stash []string
+	hold := func(fragment string) string {
+		stash = append(stash, fragment)
+		return "\x00" + strconv.Itoa(len(stash)-1) + "\x00"
+	}
+
+	text = codeSpanRe.ReplaceAllStringFunc(text, func(m string) string {
+		return hold("<code>" + html.EscapeString(codeSpanRe.FindStringSubmatch(m)[1]) + "</code>")
+	})
+
+	text = linkRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := linkRe.FindStringSubmatch(m)
+		label, target := parts[1], parts[2]
+		if !SafeURL(target) {
+			return hold(html.EscapeString(label))
+		}
+		return hold(`<a href="` + html.EscapeString(target) + `" rel="nofollow noopener">` + html.EscapeString(label) + `</a>`)
+	})
+
+	text = bareURLRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := bareURLRe.FindStringSubmatch(m)
+		if !SafeURL(parts[2]) {
+			return m
+		}
+		return parts[1] + hold(`<a href="`+html.EscapeString(parts[2])+`" rel="nofollow noopener">`+html.EscapeString(parts[2])+`</a>`)
+	})
+
+	text = html.EscapeString(text)
+
+	text = strongRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := strongRe.FindStringSubmatch(m)
+		return "<strong>" + parts[1] + parts[2] + "</strong>"
+	})
+	text = emphasisRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := emphasisRe.FindStringSubmatch(m)
+		return "<em>" + parts[1] + parts[2] + "</em>"
+	})
+	text = strings.ReplaceAll(text, "\n", "<br>\n")
+
+	return placeholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		index, _ := strconv.Atoi(placeholderRe.FindStringSubmatch(m)[1])
+		return stash[index]
+	})
+}
+
+// SafeURL reports whether a link target may be rendered as a hyperlink
+func SafeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
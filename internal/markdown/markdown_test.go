@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "paragraph with inline markup",
+			src:  "Fixed **flaky** test in *ci* using `go test -race`",
+			want: "<p>Fixed <strong>flaky</strong> test in <em>ci</em> using <code>go test -race</code></p>\n",
+		},
+		{
+			name: "heading and list",
+			src:  "## Follow-ups\n- add metrics\n- update runbook",
+			want: "<h2>Follow-ups</h2>\n<ul>\n<li>add metrics</li>\n<li>update runbook</li>\n</ul>\n",
+		},
+		{
+			name: "ordered list",
+			src:  "1. reproduce\n2. fix",
+			want: "<ol>\n<li>reproduce</li>\n<li>fix</li>\n</ol>\n",
+		},
+		{
+			name: "fenced code is escaped verbatim",
+			src:  "```go\nif a < b && *p {\n}\n```",
+			want: "<pre><code class=\"language-go\">if a &lt; b &amp;&amp; *p {\n}</code></pre>\n",
+		},
+		{
+			name: "block quote",
+			src:  "> shipped to prod",
+			want: "<blockquote>\n<p>shipped to prod</p>\n</blockquote>\n",
+		},
+		{
+			name: "links",
+			src:  "See [PR 42](https://github.com/acme/api/pull/42) and https://jira.example.com/ABC-1.",
+			want: "<p>See <a href=\"https://github.com/acme/api/pull/42\" rel=\"nofollow noopener\">PR 42</a> and <a href=\"https://jira.example.com/ABC-1\" rel=\"nofollow noopener\">https://jira.example.com/ABC-1</a>.</p>\n",
+		},
+		{
+			name: "line breaks inside a paragraph",
+			src:  "first line\nsecond line\n\nnext paragraph",
+			want: "<p>first line<br>\nsecond line</p>\n<p>next paragraph</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.src))
+		})
+	}
+}
+
+func TestRenderIsSafe(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "raw html is escaped",
+			src:  "<script>alert('x')</script>",
+			want: "<p>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;</p>\n",
+		},
+		{
+			name: "javascript links are not rendered",
+			src:  "[click](javascript:void)",
+			want: "<p>click</p>\n",
+		},
+		{
+			name: "attributes cannot be injected through link targets",
+			src:  `[x](https://example.com/"onmouseover="alert(1))`,
+			want: "<p><a href=\"https://example.com/&#34;onmouseover=&#34;alert(1\" rel=\"nofollow noopener\">x</a>)</p>\n",
+		},
+		{
+			name: "placeholder bytes in input are ignored",
+			src:  "a\x0099\x00b",
+			want: "<p>a99b</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.src))
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	assert.True(t, SafeURL("https://example.com/doc"))
+	assert.True(t, SafeURL("http://localhost:8080"))
+	assert.True(t, SafeURL("mailto:team@example.com"))
+	assert.False(t, SafeURL("javascript:alert(1)"))
+	assert.False(t, SafeURL("data:text/html;base64,AAAA"))
+	assert.False(t, SafeURL("/relative/path"))
+	assert.False(t, SafeURL("https://"))
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountArchiveFormat identifies the manifest of an account archive
+const AccountArchiveFormat = "englog-account-archive"
+
+// AccountArchiveVersion is the archive layout written by this build. Imports accept
+// archives of this version and older; fields added later are optional.
+const AccountArchiveVersion = 1
+
+// AccountArchiveManifest describes an account archive: the zip holds manifest.json,
+// profile.json, projects.json, tags.json, log_entries.json and insights.json, plus
+// CSV copies of the projects, tags and log entries for spreadsheets
+type AccountArchiveManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	UserID     uuid.UUID      `json:"user_id"`
+	Counts     map[string]int `json:"counts"`
+}
+
+// AccountArchiveProfile is the profile and preferences of the exported account
+type AccountArchiveProfile struct {
+	Email       string         `json:"email"`
+	FirstName   string         `json:"first_name"`
+	LastName    string         `json:"last_name"`
+	Timezone    string         `json:"timezone"`
+	Preferences map[string]any `json:"preferences"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// AccountArchiveProject is a project of the exported account. IDs are those of the
+// source instance and only link records within the archive.
+type AccountArchiveProject struct {
+	ID              uuid.UUID               `json:"id"`
+	ParentID        *uuid.UUID              `json:"parent_id,omitempty"`
+	Name            string                  `json:"name"`
+	Description     *string                 `json:"description,omitempty"`
+	Color           string                  `json:"color"`
+	Status          ProjectStatus           `json:"status"`
+	StartDate       *string                 `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate         *string                 `json:"end_date,omitempty"`   // YYYY-MM-DD
+	IsDefault       bool                    `json:"is_default"`
+	EstimatedHours  *float64                `json:"estimated_hours,omitempty"`
+	ActivityBudgets []ProjectActivityBudget `json:"activity_budgets,omitempty"`
+	Defaults        *ProjectDefaults        `json:"defaults,omitempty"`
+	ArchivedAt      *time.Time              `json:"archived_at,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+// AccountArchiveTag is a tag of the exported account, or a shared tag used by its entries
+type AccountArchiveTag struct {
+	ID          uuid.UUID  `json:"id"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	Name        string     `json:"name"`
+	Color       string     `json:"color"`
+	Description *string    `json:"description,omitempty"`
+	Shared      bool       `json:"shared"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AccountArchiveLogEntry is a log entry of the exported account; tags are referenced by name
+type AccountArchiveLogEntry struct {
+	ID          uuid.UUID      `json:"id"`
+	ProjectID   *uuid.UUID     `json:"project_id,omitempty"`
+	Title       string         `json:"title"`
+	Description *string        `json:"description,omitempty"`
+	Type        ActivityType   `json:"type"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time"`
+	ValueRating ValueRating    `json:"value_rating"`
+	ImpactLevel ImpactLevel    `json:"impact_level"`
+	Tags        []string       `json:"tags"`
+	Links       []LogEntryLink `json:"links,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// AccountArchiveInsight is a generated insight of the exported account
+type AccountArchiveInsight struct {
+	ID              uuid.UUID       `json:"id"`
+	ReportType      ReportType      `json:"report_type"`
+	PeriodStart     string          `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd       string          `json:"period_end"`   // YYYY-MM-DD
+	Title           string          `json:"title"`
+	Content         string          `json:"content"`
+	Summary         *string         `json:"summary,omitempty"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	GenerationModel *string         `json:"generation_model,omitempty"`
+	QualityScore    *float64        `json:"quality_score,omitempty"`
+	Status          InsightStatus   `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// AccountImportCounts tallies what an import did with one kind of record
+type AccountImportCounts struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Matched int `json:"matched"` // Already in the account, so reused instead of created
+	Skipped int `json:"skipped"` // Invalid or conflicting; see the warnings
+}
+
+// AccountImportReport is the outcome of an account import. A dry run reports what
+// the import would do without keeping any change; its ID maps are omitted.
+type AccountImportReport struct {
+	DryRun         bool                    `json:"dry_run"`
+	Version        int                     `json:"version"`
+	ExportedAt     time.Time               `json:"exported_at"`
+	ProfileUpdated bool                    `json:"profile_updated"`
+	Projects       AccountImportCounts     `json:"projects"`
+	Tags           AccountImportCounts     `json:"tags"`
+	LogEntries     AccountImportCounts     `json:"log_entries"`
+	Insights       AccountImportCounts     `json:"insights"`
+	ProjectIDs     map[uuid.UUID]uuid.UUID `json:"project_ids,omitempty"` // Archive ID to account ID
+	TagIDs         map[uuid.UUID]uuid.UUID `json:"tag_ids,omitempty"`     // Archive ID to account ID
+	Warnings       []string                `json:"warnings"`
+}
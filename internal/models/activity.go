@@ -77,33 +77,37 @@ func (i ImpactLevel) IsValid() bool {
 
 // LogEntry represents a single activity log entry
 type LogEntry struct {
-	ID              uuid.UUID    `json:"id" db:"id"`
-	UserID          uuid.UUID    `json:"user_id" db:"user_id"`
-	Title           string       `json:"title" db:"title" validate:"required,max=500"`
-	Description     *string      `json:"description,omitempty" db:"description"`
-	Type            ActivityType `json:"type" db:"type" validate:"required"`
-	ProjectID       *uuid.UUID   `json:"project_id,omitempty" db:"project_id"`
-	StartTime       time.Time    `json:"start_time" db:"start_time" validate:"required"`
-	EndTime         time.Time    `json:"end_time" db:"end_time" validate:"required"`
-	DurationMinutes int          `json:"duration_minutes" db:"duration_minutes"`
-	ValueRating     ValueRating  `json:"value_rating" db:"value_rating" validate:"required"`
-	ImpactLevel     ImpactLevel  `json:"impact_level" db:"impact_level" validate:"required"`
-	Tags            []string     `json:"tags,omitempty"`
-	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID      `json:"id" db:"id"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id"`
+	Title           string         `json:"title" db:"title" validate:"required,max=500"`
+	Description     *string        `json:"description,omitempty" db:"description"` // Markdown
+	DescriptionHTML *string        `json:"description_html,omitempty"`             // Rendered description
+	Type            ActivityType   `json:"type" db:"type" validate:"required"`
+	ProjectID       *uuid.UUID     `json:"project_id,omitempty" db:"project_id"`
+	StartTime       time.Time      `json:"start_time" db:"start_time" validate:"required"`
+	EndTime         time.Time      `json:"end_time" db:"end_time" validate:"required"`
+	DurationMinutes int            `json:"duration_minutes" db:"duration_minutes"`
+	ValueRating     ValueRating    `json:"value_rating" db:"value_rating" validate:"required"`
+	ImpactLevel     ImpactLevel    `json:"impact_level" db:"impact_level" validate:"required"`
+	Tags            []string       `json:"tags,omitempty"`
+	Links           []LogEntryLink `json:"links,omitempty"`
+	Attachments     []Attachment   `json:"attachments,omitempty"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
 }
 
 // LogEntryRequest represents the data required to create or update a log entry
 type LogEntryRequest struct {
-	Title       string       `json:"title" validate:"required,max=500"`
-	Description *string      `json:"description,omitempty" validate:"omitempty,max=5000"`
-	Type        ActivityType `json:"type" validate:"required"`
-	ProjectID   *uuid.UUID   `json:"project_id,omitempty"`
-	StartTime   time.Time    `json:"start_time" validate:"required"`
-	EndTime     time.Time    `json:"end_time" validate:"required"`
-	ValueRating ValueRating  `json:"value_rating" validate:"required"`
-	ImpactLevel ImpactLevel  `json:"impact_level" validate:"required"`
-	Tags        []string     `json:"tags,omitempty" validate:"omitempty,dive,max=100"`
+	Title       string         `json:"title" validate:"required,max=500"`
+	Description *string        `json:"description,omitempty" validate:"omitempty,max=5000"`
+	Type        ActivityType   `json:"type" validate:"required"`
+	ProjectID   *uuid.UUID     `json:"project_id,omitempty"`
+	StartTime   time.Time      `json:"start_time" validate:"required"`
+	EndTime     time.Time      `json:"end_time" validate:"required"`
+	ValueRating ValueRating    `json:"value_rating" validate:"required"`
+	ImpactLevel ImpactLevel    `json:"impact_level" validate:"required"`
+	Tags        []string       `json:"tags,omitempty" validate:"omitempty,dive,max=100"`
+	Links       []LogEntryLink `json:"links,omitempty" validate:"omitempty,max=20,dive"`
 }
 
 // CalculateDuration calculates and sets the duration in minutes for a log entry
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// AnalyticsSettings controls how analytics are bucketed into days, hours and weeks
+type AnalyticsSettings struct {
+	Location  *time.Location
+	WeekStart WeekStart
+}
+
+// PeriodSummary aggregates a user's activity for one week or month
+type PeriodSummary struct {
+	PeriodStart   time.Time `json:"period_start"`
+	EntryCount    int       `json:"entry_count"`
+	TotalMinutes  int       `json:"total_minutes"`
+	AvgMinutes    float64   `json:"avg_minutes"`
+	ProjectsCount int       `json:"projects_count"`
+	ActiveDays    int       `json:"active_days"`
+}
+
+// ActivityHeatmap holds minutes and entries per day of week and hour of day.
+// Rows are indexed by day of week (0=Sunday) and columns by hour (0-23).
+type ActivityHeatmap struct {
+	Minutes      [7][24]int `json:"minutes"`
+	Entries      [7][24]int `json:"entries"`
+	DayTotals    [7]int     `json:"day_totals"`
+	HourTotals   [24]int    `json:"hour_totals"`
+	TotalMinutes int        `json:"total_minutes"`
+	TotalEntries int        `json:"total_entries"`
+	PeakDay      *int       `json:"peak_day,omitempty"`
+	PeakHour     *int       `json:"peak_hour,omitempty"`
+}
+
+// ProductivityTrendPoint is one day in a user's productivity trend
+type ProductivityTrendPoint struct {
+	Date              string  `json:"date"`
+	TotalMinutes      int     `json:"total_minutes"`
+	EntryCount        int     `json:"entry_count"`
+	AvgValueScore     float64 `json:"avg_value_score"`
+	ProductivityScore float64 `json:"productivity_score"`
+}
+
+// FocusStretch is a meeting-free stretch of a day, between the first and last logged activity
+type FocusStretch struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Minutes int       `json:"minutes"`
+}
+
+// FocusDay holds fragmentation metrics for one day in the user's timezone
+type FocusDay struct {
+	Date                      string         `json:"date"`
+	EntryCount                int            `json:"entry_count"`
+	TotalMinutes              int            `json:"total_minutes"`
+	Switches                  int            `json:"switches"`
+	LongestFocusMinutes       int            `json:"longest_focus_minutes"`
+	DeepWorkMinutes           int            `json:"deep_work_minutes"`
+	DeepWorkShare             float64        `json:"deep_work_share"`
+	MeetingMinutes            int            `json:"meeting_minutes"`
+	LongestMeetingFreeMinutes int            `json:"longest_meeting_free_minutes"`
+	MeetingFreeStretches      []FocusStretch `json:"meeting_free_stretches"`
+}
+
+// FocusSummary aggregates focus metrics over a week or a whole range
+type FocusSummary struct {
+	PeriodStart               string  `json:"period_start"`
+	ActiveDays                int     `json:"active_days"`
+	EntryCount                int     `json:"entry_count"`
+	TotalMinutes              int     `json:"total_minutes"`
+	Switches                  int     `json:"switches"`
+	AvgSwitchesPerDay         float64 `json:"avg_switches_per_day"`
+	LongestFocusMinutes       int     `json:"longest_focus_minutes"`
+	DeepWorkMinutes           int     `json:"deep_work_minutes"`
+	DeepWorkShare             float64 `json:"deep_work_share"`
+	MeetingMinutes            int     `json:"meeting_minutes"`
+	LongestMeetingFreeMinutes int     `json:"longest_meeting_free_minutes"`
+}
+
+// FocusReport holds deep-work and context-switching metrics per day and week.
+// Only days with logged activity are listed.
+type FocusReport struct {
+	Summary FocusSummary    `json:"summary"`
+	Weeks   []*FocusSummary `json:"weeks"`
+	Days    []*FocusDay     `json:"days"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// BulkMode controls how a bulk operation reacts to per-item failures
+type BulkMode string
+
+const (
+	// BulkAtomic applies every item in one transaction; any failure rolls back all of them
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort applies each item in its own transaction and keeps the successful ones
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// IsValid checks if the BulkMode is valid
+func (m BulkMode) IsValid() bool {
+	switch m {
+	case BulkAtomic, BulkBestEffort:
+		return true
+	}
+	return false
+}
+
+// BulkItemStatus represents the outcome of a single item in a bulk operation
+type BulkItemStatus string
+
+const (
+	BulkItemSucceeded  BulkItemStatus = "succeeded"
+	BulkItemFailed     BulkItemStatus = "failed"
+	BulkItemRolledBack BulkItemStatus = "rolled_back"
+)
+
+// BulkFilter selects log entries by the same criteria as GET /v1/logs.
+// A date range is required so a filter can never match an unbounded set.
+type BulkFilter struct {
+	StartDate   string        `json:"start_date" validate:"required"` // YYYY-MM-DD
+	EndDate     string        `json:"end_date" validate:"required"`   // YYYY-MM-DD, inclusive
+	Type        *ActivityType `json:"type,omitempty"`
+	ProjectID   *uuid.UUID    `json:"project_id,omitempty"`
+	ValueRating *ValueRating  `json:"value_rating,omitempty"`
+	ImpactLevel *ImpactLevel  `json:"impact_level,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	// IncludeDescendants also matches entries tagged with child tags of Tags
+	IncludeDescendants bool `json:"include_descendants,omitempty"`
+}
+
+// BulkSelection identifies the log entries a bulk operation applies to,
+// either by explicit IDs or by a filter
+type BulkSelection struct {
+	IDs    []uuid.UUID `json:"ids,omitempty"`
+	Filter *BulkFilter `json:"filter,omitempty"`
+}
+
+// BulkUpdateItem is a full replacement for one log entry
+type BulkUpdateItem struct {
+	ID    uuid.UUID       `json:"id" validate:"required"`
+	Entry LogEntryRequest `json:"entry" validate:"required"`
+}
+
+// BulkUpdateRequest represents a bulk update of log entries
+type BulkUpdateRequest struct {
+	Mode  BulkMode         `json:"mode,omitempty"`
+	Items []BulkUpdateItem `json:"items" validate:"required,min=1"`
+}
+
+// BulkDeleteRequest represents a bulk delete of log entries
+type BulkDeleteRequest struct {
+	Mode BulkMode `json:"mode,omitempty"`
+	BulkSelection
+}
+
+// BulkRetagRequest adds and/or removes tags on a set of log entries
+type BulkRetagRequest struct {
+	Mode       BulkMode `json:"mode,omitempty"`
+	AddTags    []string `json:"add_tags,omitempty"`
+	RemoveTags []string `json:"remove_tags,omitempty"`
+	BulkSelection
+}
+
+// BulkMoveRequest reassigns a set of log entries to a project.
+// A nil ProjectID removes the project from the entries.
+type BulkMoveRequest struct {
+	Mode      BulkMode   `json:"mode,omitempty"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	BulkSelection
+}
+
+// BulkItemResult is the outcome of one item of a bulk operation
+type BulkItemResult struct {
+	Index  int            `json:"index"`
+	ID     uuid.UUID      `json:"id"`
+	Status BulkItemStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Data   *LogEntry      `json:"data,omitempty"`
+}
+
+// BulkResult summarizes a bulk operation
+type BulkResult struct {
+	Mode      BulkMode         `json:"mode"`
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Committed bool             `json:"committed"` // false when an atomic operation was rolled back
+	Results   []BulkItemResult `json:"results"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedToken is a secret token a calendar app subscribes to the user's
+// activity feed with. The token itself is only shown when it is created.
+type CalendarFeedToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CalendarFeedTokenRequest represents the data used to create a calendar feed token
+type CalendarFeedTokenRequest struct {
+	Name string `json:"name" binding:"max=100"` // "Calendar feed" by default
+}
+
+// CalendarFeedTokenCreated is a new calendar feed token with its secret and the
+// URL of the feed, neither of which can be retrieved again
+type CalendarFeedTokenCreated struct {
+	CalendarFeedToken
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
+// CalendarFeedFilters narrows the log entries of a calendar feed. Empty lists
+// match everything.
+type CalendarFeedFilters struct {
+	ProjectIDs []uuid.UUID
+	Types      []ActivityType
+	Days       int // Days of history before today; 90 by default, at most 366
+}
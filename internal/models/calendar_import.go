@@ -0,0 +1,36 @@
+package models
+
+import "github.com/google/uuid"
+
+// CalendarImportRequest selects the events of an iCalendar file to import as meetings
+type CalendarImportRequest struct {
+	StartDate string     // YYYY-MM-DD in the user's timezone, inclusive; empty starts 30 days before the end
+	EndDate   string     // YYYY-MM-DD in the user's timezone, inclusive; empty ends today
+	Email     string     // The user's address in the calendar, when it is not the account email
+	ProjectID *uuid.UUID // Project of the imported entries
+}
+
+// CalendarImportResult reports the meetings an iCalendar import created and why
+// the other events of the period were skipped
+type CalendarImportResult struct {
+	Calendar    string              `json:"calendar,omitempty"` // Name of the calendar, when the file has one
+	StartDate   string              `json:"start_date"`
+	EndDate     string              `json:"end_date"`
+	Events      int                 `json:"events"`      // Events read from the file
+	Occurrences int                 `json:"occurrences"` // Meetings in the period, with recurring events expanded
+	Created     int                 `json:"created"`
+	Skipped     CalendarImportSkips `json:"skipped"`
+	Entries     []LogEntry          `json:"entries"`  // The created entries
+	Warnings    []string            `json:"warnings"` // Events that could not be read or imported
+}
+
+// CalendarImportSkips counts the meetings of the period that were not imported, by reason
+type CalendarImportSkips struct {
+	AlreadyImported int `json:"already_imported"` // Imported before, even if the entry was deleted since
+	Declined        int `json:"declined"`
+	AllDay          int `json:"all_day"`
+	Cancelled       int `json:"cancelled"`
+	NotOver         int `json:"not_over"` // Meetings that have not ended yet
+	Duration        int `json:"duration"` // Meetings without a duration or longer than a day
+	Invalid         int `json:"invalid"`  // Meetings that do not make a valid entry, see the warnings
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CSV date formats a column mapping can read dates with. Days and months may
+// have one or two digits.
+const (
+	CSVDateISO = "YYYY-MM-DD"
+	CSVDateUS  = "MM/DD/YYYY"
+	CSVDateEU  = "DD/MM/YYYY"
+	CSVDateDot = "DD.MM.YYYY"
+)
+
+// CSV duration units of plain numbers; values like 1:30 or 01:30:00 are always
+// read as hours, minutes and seconds
+const (
+	CSVDurationHours   = "hours"
+	CSVDurationMinutes = "minutes"
+)
+
+// CSVColumnMapping describes how the columns of a CSV file, named by their header,
+// become log entry fields. Dates and times without an offset are read in Timezone.
+type CSVColumnMapping struct {
+	Delimiter    string                  `json:"delimiter,omitempty"`     // Single character; "," by default
+	Title        []string                `json:"title"`                   // Columns tried in order; the first non-empty value is the title
+	Description  string                  `json:"description,omitempty"`   // Column of the description
+	Start        string                  `json:"start"`                   // Column of the start date, with the time unless StartTime is set
+	StartTime    string                  `json:"start_time,omitempty"`    // Column of the start time
+	End          string                  `json:"end,omitempty"`           // Column of the end date, with the time unless EndTime is set
+	EndTime      string                  `json:"end_time,omitempty"`      // Column of the end time; without End the end is on the start date, or the day after
+	Duration     string                  `json:"duration,omitempty"`      // Column of the duration, used when a row has no end
+	DurationUnit string                  `json:"duration_unit,omitempty"` // Unit of plain numbers: hours (default) or minutes
+	DateFormat   string                  `json:"date_format,omitempty"`   // One of the CSVDate formats; YYYY-MM-DD by default
+	Timezone     string                  `json:"timezone,omitempty"`      // IANA name; the user's timezone by default
+	DayStart     string                  `json:"day_start,omitempty"`     // HH:MM the first entry of a day without times starts at; 09:00 by default
+	Project      string                  `json:"project,omitempty"`       // Column of the project name
+	Tags         string                  `json:"tags,omitempty"`          // Column of the tags
+	TagSeparator string                  `json:"tag_separator,omitempty"` // "," by default
+	Type         string                  `json:"type,omitempty"`          // Column looked up in TypeMap
+	TypeMap      map[string]ActivityType `json:"type_map,omitempty"`      // Values of the type column, ignoring case, to activity types
+	DefaultType  ActivityType            `json:"default_type,omitempty"`  // Type of rows without a known type; "other" by default
+	ValueRating  ValueRating             `json:"value_rating,omitempty"`  // Value of every entry; the project default or medium otherwise
+	ImpactLevel  ImpactLevel             `json:"impact_level,omitempty"`  // Impact of every entry; the project default or team otherwise
+}
+
+// CSVImportPreset is a built-in column mapping for the export of a common time tracker
+type CSVImportPreset struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Mapping     CSVColumnMapping `json:"mapping"`
+}
+
+// CSVImportProfile is a column mapping the user saved for reuse
+type CSVImportProfile struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	Name      string           `json:"name" db:"name"`
+	Mapping   CSVColumnMapping `json:"mapping" db:"mapping"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// CSVImportProfileRequest represents the data required to create or update a CSV import profile
+type CSVImportProfileRequest struct {
+	Name    string           `json:"name" binding:"required,max=100"`
+	Mapping CSVColumnMapping `json:"mapping"`
+}
+
+// CSVImportRequest selects the column mapping of a CSV import: a saved profile,
+// a built-in preset or an inline mapping, exactly one of them
+type CSVImportRequest struct {
+	ProfileID *uuid.UUID
+	Preset    string
+	Mapping   *CSVColumnMapping
+	Preview   bool // Check the rows without creating entries
+}
+
+// CSVImportResult reports what a CSV import created, or would create in a preview,
+// and why the other rows were not imported
+type CSVImportResult struct {
+	Preview           bool             `json:"preview"`
+	Rows              int              `json:"rows"`       // Data rows read, without the header
+	Valid             int              `json:"valid"`      // Rows that make valid new entries
+	Created           int              `json:"created"`    // Always zero in a preview
+	Duplicates        int              `json:"duplicates"` // Rows with the title and times of an existing entry or an earlier row
+	Errors            []CSVImportError `json:"errors"`
+	MissingColumns    []string         `json:"missing_columns"`    // Mapped columns the file does not have; their fields are left empty
+	UnmatchedProjects []string         `json:"unmatched_projects"` // Project names the user has no project for; their entries have none
+	Entries           []CSVImportRow   `json:"entries,omitempty"`  // Preview only: the entries of the first valid rows
+}
+
+// CSVImportRow is the entry a row of a CSV file makes
+type CSVImportRow struct {
+	Row   int             `json:"row"` // Line of the row in the file, the header being line 1
+	Entry LogEntryRequest `json:"entry"`
+}
+
+// CSVImportError is a row of a CSV file that could not be imported
+type CSVImportError struct {
+	Row   int    `json:"row"` // Line of the row in the file, the header being line 1
+	Error string `json:"error"`
+}
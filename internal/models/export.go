@@ -0,0 +1,211 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is the file format of a data export
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"   // Comma-separated values with a header row
+	ExportJSONL ExportFormat = "jsonl" // JSON Lines: one JSON object per line
+)
+
+// IsValid checks if the ExportFormat is valid
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportCSV, ExportJSONL:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	if f == ExportJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportTagSeparator joins the tags of a log entry in a single CSV cell
+const ExportTagSeparator = ";"
+
+// LogEntryExportFilter narrows a log entry export; empty fields do not filter
+type LogEntryExportFilter struct {
+	StartDate string // YYYY-MM-DD in the user's timezone, inclusive
+	EndDate   string // YYYY-MM-DD in the user's timezone, inclusive
+	ProjectID string
+}
+
+// LogEntryExport is a log entry in a data export
+type LogEntryExport struct {
+	ID              uuid.UUID    `json:"id"`
+	Title           string       `json:"title"`
+	Description     *string      `json:"description,omitempty"`
+	Type            ActivityType `json:"type"`
+	ProjectID       *uuid.UUID   `json:"project_id,omitempty"`
+	ProjectName     *string      `json:"project_name,omitempty"`
+	StartTime       time.Time    `json:"start_time"`
+	EndTime         time.Time    `json:"end_time"`
+	DurationMinutes int          `json:"duration_minutes"`
+	DurationHours   float64      `json:"duration_hours"`
+	ValueRating     ValueRating  `json:"value_rating"`
+	ImpactLevel     ImpactLevel  `json:"impact_level"`
+	Tags            []string     `json:"tags"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// LogEntryExportColumns is the CSV header of a log entry export
+var LogEntryExportColumns = []string{
+	"id", "title", "description", "type", "project_id", "project_name", "start_time", "end_time",
+	"duration_minutes", "duration_hours", "value_rating", "impact_level", "tags", "created_at", "updated_at",
+}
+
+// CSVRecord returns the log entry as a CSV row in the order of LogEntryExportColumns
+func (e *LogEntryExport) CSVRecord() []string {
+	return []string{
+		e.ID.String(),
+		e.Title,
+		csvString(e.Description),
+		string(e.Type),
+		csvUUID(e.ProjectID),
+		csvString(e.ProjectName),
+		csvTime(&e.StartTime),
+		csvTime(&e.EndTime),
+		strconv.Itoa(e.DurationMinutes),
+		csvFloat(&e.DurationHours),
+		string(e.ValueRating),
+		string(e.ImpactLevel),
+		strings.Join(e.Tags, ExportTagSeparator),
+		csvTime(&e.CreatedAt),
+		csvTime(&e.UpdatedAt),
+	}
+}
+
+// ProjectExport is a project in a data export, with the time logged on it
+type ProjectExport struct {
+	ID             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	Description    *string       `json:"description,omitempty"`
+	Status         ProjectStatus `json:"status"`
+	Color          string        `json:"color"`
+	ParentID       *uuid.UUID    `json:"parent_id,omitempty"`
+	ParentName     *string       `json:"parent_name,omitempty"`
+	StartDate      *string       `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate        *string       `json:"end_date,omitempty"`   // YYYY-MM-DD
+	EstimatedHours *float64      `json:"estimated_hours,omitempty"`
+	IsDefault      bool          `json:"is_default"`
+	ArchivedAt     *time.Time    `json:"archived_at,omitempty"`
+	EntryCount     int           `json:"entry_count"`
+	TotalMinutes   int           `json:"total_minutes"`
+	TotalHours     float64       `json:"total_hours"`
+	LastActivityAt *time.Time    `json:"last_activity_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// ProjectExportColumns is the CSV header of a project export
+var ProjectExportColumns = []string{
+	"id", "name", "description", "status", "color", "parent_id", "parent_name", "start_date", "end_date",
+	"estimated_hours", "is_default", "archived_at", "entry_count", "total_minutes", "total_hours",
+	"last_activity_at", "created_at",
+}
+
+// CSVRecord returns the project as a CSV row in the order of ProjectExportColumns
+func (p *ProjectExport) CSVRecord() []string {
+	return []string{
+		p.ID.String(),
+		p.Name,
+		csvString(p.Description),
+		string(p.Status),
+		p.Color,
+		csvUUID(p.ParentID),
+		csvString(p.ParentName),
+		csvString(p.StartDate),
+		csvString(p.EndDate),
+		csvFloat(p.EstimatedHours),
+		strconv.FormatBool(p.IsDefault),
+		csvTime(p.ArchivedAt),
+		strconv.Itoa(p.EntryCount),
+		strconv.Itoa(p.TotalMinutes),
+		csvFloat(&p.TotalHours),
+		csvTime(p.LastActivityAt),
+		csvTime(&p.CreatedAt),
+	}
+}
+
+// TagUsageExport is a tag in a data export, with how often and how long it was used
+type TagUsageExport struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	ParentName   *string    `json:"parent_name,omitempty"`
+	Color        string     `json:"color"`
+	Description  *string    `json:"description,omitempty"`
+	EntryCount   int        `json:"entry_count"`
+	TotalMinutes int        `json:"total_minutes"`
+	TotalHours   float64    `json:"total_hours"`
+	FirstUsedAt  *time.Time `json:"first_used_at,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TagUsageExportColumns is the CSV header of a tag usage export
+var TagUsageExportColumns = []string{
+	"id", "name", "parent_id", "parent_name", "color", "description", "entry_count", "total_minutes",
+	"total_hours", "first_used_at", "last_used_at", "created_at",
+}
+
+// CSVRecord returns the tag as a CSV row in the order of TagUsageExportColumns
+func (t *TagUsageExport) CSVRecord() []string {
+	return []string{
+		t.ID.String(),
+		t.Name,
+		csvUUID(t.ParentID),
+		csvString(t.ParentName),
+		t.Color,
+		csvString(t.Description),
+		strconv.Itoa(t.EntryCount),
+		strconv.Itoa(t.TotalMinutes),
+		csvFloat(&t.TotalHours),
+		csvTime(t.FirstUsedAt),
+		csvTime(t.LastUsedAt),
+		csvTime(&t.CreatedAt),
+	}
+}
+
+// CSV cells are empty for missing values
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
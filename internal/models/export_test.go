@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportFormat(t *testing.T) {
+	assert.True(t, ExportCSV.IsValid())
+	assert.True(t, ExportJSONL.IsValid())
+	assert.False(t, ExportFormat("pdf").IsValid())
+	assert.False(t, ExportFormat("").IsValid())
+
+	assert.Equal(t, "text/csv; charset=utf-8", ExportCSV.ContentType())
+	assert.Equal(t, "application/x-ndjson", ExportJSONL.ContentType())
+}
+
+func TestExportCSVRecords(t *testing.T) {
+	sp := time.FixedZone("BRT", -3*60*60)
+	created := time.Date(2025, 3, 10, 8, 0, 0, 0, sp)
+
+	t.Run("LogEntry", func(t *testing.T) {
+		projectID := uuid.MustParse("6f1c1a52-7a0e-4f5e-9d61-5c1f0f3d2a10")
+		entry := &LogEntryExport{
+			ID:              uuid.MustParse("0b8c7f7e-2d3a-4c53-8a55-1f1f8e7f4a01"),
+			Title:           "Review, then merge",
+			Type:            ActivityCodeReview,
+			ProjectID:       &projectID,
+			ProjectName:     stringPointer("Platform"),
+			StartTime:       created,
+			EndTime:         created.Add(90 * time.Minute),
+			DurationMinutes: 90,
+			DurationHours:   1.5,
+			ValueRating:     ValueHigh,
+			ImpactLevel:     ImpactTeam,
+			Tags:            []string{"go", "review"},
+			CreatedAt:       created,
+			UpdatedAt:       created,
+		}
+
+		record := entry.CSVRecord()
+		assert.Len(t, record, len(LogEntryExportColumns))
+		assert.Equal(t, []string{
+			"0b8c7f7e-2d3a-4c53-8a55-1f1f8e7f4a01", "Review, then merge", "", "code_review",
+			"6f1c1a52-7a0e-4f5e-9d61-5c1f0f3d2a10", "Platform", "2025-03-10T08:00:00-03:00", "2025-03-10T09:30:00-03:00",
+			"90", "1.5", "high", "team", "go;review", "2025-03-10T08:00:00-03:00", "2025-03-10T08:00:00-03:00",
+		}, record)
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		project := &ProjectExport{
+			ID:           uuid.MustParse("6f1c1a52-7a0e-4f5e-9d61-5c1f0f3d2a10"),
+			Name:         "Platform",
+			Status:       ProjectActive,
+			Color:        "#3498db",
+			StartDate:    stringPointer("2025-01-01"),
+			IsDefault:    true,
+			EntryCount:   3,
+			TotalMinutes: 200,
+			TotalHours:   3.33,
+			CreatedAt:    created,
+		}
+
+		record := project.CSVRecord()
+		assert.Len(t, record, len(ProjectExportColumns))
+		assert.Equal(t, []string{
+			"6f1c1a52-7a0e-4f5e-9d61-5c1f0f3d2a10", "Platform", "", "active", "#3498db", "", "", "2025-01-01", "",
+			"", "true", "", "3", "200", "3.33", "", "2025-03-10T08:00:00-03:00",
+		}, record, "missing values are empty cells")
+	})
+
+	t.Run("TagUsage", func(t *testing.T) {
+		tag := &TagUsageExport{
+			ID:         uuid.MustParse("9a4e3b1c-5d6f-4a7b-8c9d-0e1f2a3b4c5d"),
+			Name:       "go",
+			Color:      "#6b7280",
+			EntryCount: 0,
+			CreatedAt:  created,
+		}
+
+		record := tag.CSVRecord()
+		assert.Len(t, record, len(TagUsageExportColumns))
+		assert.Equal(t, []string{
+			"9a4e3b1c-5d6f-4a7b-8c9d-0e1f2a3b4c5d", "go", "", "", "#6b7280", "", "0", "0", "0", "", "", "2025-03-10T08:00:00-03:00",
+		}, record)
+	})
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
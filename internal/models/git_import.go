@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GitImportRequest selects the commits of a git log to propose log entries for
+type GitImportRequest struct {
+	Repository  string     // Repository name, matched against the user's project names
+	AuthorEmail string     // Only commits by this author are proposed
+	StartDate   string     // YYYY-MM-DD in the user's timezone, inclusive; empty does not filter
+	EndDate     string     // YYYY-MM-DD in the user's timezone, inclusive; empty does not filter
+	GapMinutes  int        // Longest pause between commits of one session; zero uses the default
+	ProjectID   *uuid.UUID // Project of the proposed entries, instead of matching the repository name
+}
+
+// GitImportProposal is the set of log entries proposed for the commits of a git
+// log. Nothing is saved: the reviewed entries are created with POST /v1/logs/bulk.
+type GitImportProposal struct {
+	Repository     string             `json:"repository"`
+	ProjectID      *uuid.UUID         `json:"project_id,omitempty"`
+	ProjectName    *string            `json:"project_name,omitempty"`
+	Commits        int                `json:"commits"`         // Commits by the author in the period
+	SkippedCommits int                `json:"skipped_commits"` // Other authors, outside the period or merges
+	Sessions       []GitImportSession `json:"sessions"`
+}
+
+// GitImportSession is a work session and the log entry proposed for it
+type GitImportSession struct {
+	Entry         LogEntryRequest `json:"entry"`
+	Commits       []string        `json:"commits"`         // Short hashes, oldest first
+	FirstCommitAt time.Time       `json:"first_commit_at"` // The entry starts earlier, to cover the work before it
+	FilesChanged  int             `json:"files_changed"`
+	Insertions    int             `json:"insertions"`
+	Deletions     int             `json:"deletions"`
+	Duplicate     bool            `json:"duplicate"` // An entry with this title and time span already exists
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalMetric is what a goal measures over the log entries matching its filters
+type GoalMetric string
+
+const (
+	GoalMetricMinutes    GoalMetric = "minutes"     // Total minutes logged
+	GoalMetricEntries    GoalMetric = "entries"     // Number of log entries
+	GoalMetricShare      GoalMetric = "share"       // Percentage of all minutes logged in the period
+	GoalMetricKeyResults GoalMetric = "key_results" // Average progress of the child goals
+)
+
+// IsValid checks if the GoalMetric is valid
+func (m GoalMetric) IsValid() bool {
+	switch m {
+	case GoalMetricMinutes, GoalMetricEntries, GoalMetricShare, GoalMetricKeyResults:
+		return true
+	}
+	return false
+}
+
+// GoalComparison tells whether the target is a floor or a ceiling
+type GoalComparison string
+
+const (
+	GoalAtLeast GoalComparison = "at_least"
+	GoalAtMost  GoalComparison = "at_most"
+)
+
+// IsValid checks if the GoalComparison is valid
+func (c GoalComparison) IsValid() bool {
+	switch c {
+	case GoalAtLeast, GoalAtMost:
+		return true
+	}
+	return false
+}
+
+// GoalPeriod is the window a goal is evaluated over
+type GoalPeriod string
+
+const (
+	GoalPeriodWeek    GoalPeriod = "week"
+	GoalPeriodMonth   GoalPeriod = "month"
+	GoalPeriodQuarter GoalPeriod = "quarter"
+	GoalPeriodYear    GoalPeriod = "year"
+	GoalPeriodCustom  GoalPeriod = "custom"
+)
+
+// IsValid checks if the GoalPeriod is valid
+func (p GoalPeriod) IsValid() bool {
+	switch p {
+	case GoalPeriodWeek, GoalPeriodMonth, GoalPeriodQuarter, GoalPeriodYear, GoalPeriodCustom:
+		return true
+	}
+	return false
+}
+
+// GoalStatus is the lifecycle state of a goal
+type GoalStatus string
+
+const (
+	GoalActive    GoalStatus = "active"
+	GoalCompleted GoalStatus = "completed"
+	GoalArchived  GoalStatus = "archived"
+)
+
+// IsValid checks if the GoalStatus is valid
+func (s GoalStatus) IsValid() bool {
+	switch s {
+	case GoalActive, GoalCompleted, GoalArchived:
+		return true
+	}
+	return false
+}
+
+// ProgressState summarizes how a goal is doing in its current period
+type ProgressState string
+
+const (
+	ProgressAchieved ProgressState = "achieved"
+	ProgressOnTrack  ProgressState = "on_track"
+	ProgressBehind   ProgressState = "behind"
+	ProgressMissed   ProgressState = "missed"
+)
+
+// Goal represents a personal goal or OKR measured against log entries
+type Goal struct {
+	ID            uuid.UUID      `json:"id" db:"id"`
+	UserID        uuid.UUID      `json:"user_id" db:"user_id"`
+	ParentID      *uuid.UUID     `json:"parent_id,omitempty" db:"parent_id"`
+	Title         string         `json:"title" db:"title"`
+	Description   *string        `json:"description,omitempty" db:"description"`
+	Metric        GoalMetric     `json:"metric" db:"metric"`
+	Comparison    GoalComparison `json:"comparison" db:"comparison"`
+	TargetValue   float64        `json:"target_value" db:"target_value"`
+	Period        GoalPeriod     `json:"period" db:"period"`
+	StartDate     *string        `json:"start_date,omitempty" db:"start_date"` // YYYY-MM-DD, custom periods only
+	EndDate       *string        `json:"end_date,omitempty" db:"end_date"`     // YYYY-MM-DD, custom periods only
+	ActivityTypes []ActivityType `json:"activity_types" db:"activity_types"`
+	Tags          []string       `json:"tags" db:"tags"`
+	ProjectID     *uuid.UUID     `json:"project_id,omitempty" db:"project_id"`
+	ValueRatings  []ValueRating  `json:"value_ratings" db:"value_ratings"`
+	Status        GoalStatus     `json:"status" db:"status"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// GoalRequest represents the data required to create or update a goal
+type GoalRequest struct {
+	ParentID      *uuid.UUID     `json:"parent_id,omitempty"`
+	Title         string         `json:"title" validate:"required,max=200"`
+	Description   *string        `json:"description,omitempty"`
+	Metric        GoalMetric     `json:"metric" validate:"required"`
+	Comparison    GoalComparison `json:"comparison,omitempty"` // Defaults to at_least
+	TargetValue   float64        `json:"target_value"`
+	Period        GoalPeriod     `json:"period" validate:"required"`
+	StartDate     *string        `json:"start_date,omitempty"`
+	EndDate       *string        `json:"end_date,omitempty"`
+	ActivityTypes []ActivityType `json:"activity_types,omitempty"`
+	Tags          []string       `json:"tags,omitempty" validate:"omitempty,dive,max=100"`
+	ProjectID     *uuid.UUID     `json:"project_id,omitempty"`
+	ValueRatings  []ValueRating  `json:"value_ratings,omitempty"`
+	Status        GoalStatus     `json:"status,omitempty"` // Defaults to active
+}
+
+// GoalProgress is a goal evaluated over one period
+type GoalProgress struct {
+	GoalID         uuid.UUID       `json:"goal_id"`
+	Title          string          `json:"title"`
+	Metric         GoalMetric      `json:"metric"`
+	Comparison     GoalComparison  `json:"comparison"`
+	TargetValue    float64         `json:"target_value"`
+	CurrentValue   float64         `json:"current_value"`
+	Percent        float64         `json:"percent"` // Progress towards the target, 100 when met
+	State          ProgressState   `json:"state"`
+	PeriodStart    string          `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd      string          `json:"period_end"`   // YYYY-MM-DD, inclusive
+	ElapsedPercent float64         `json:"elapsed_percent"`
+	KeyResults     []*GoalProgress `json:"key_results,omitempty"`
+}
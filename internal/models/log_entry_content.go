@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkKind categorizes a structured link on a log entry
+type LinkKind string
+
+const (
+	LinkPullRequest LinkKind = "pull_request"
+	LinkIssue       LinkKind = "issue"
+	LinkTicket      LinkKind = "ticket"
+	LinkDesignDoc   LinkKind = "design_doc"
+	LinkDocument    LinkKind = "document"
+	LinkDashboard   LinkKind = "dashboard"
+	LinkOther       LinkKind = "other"
+)
+
+// IsValid checks if the LinkKind is valid
+func (k LinkKind) IsValid() bool {
+	switch k {
+	case LinkPullRequest, LinkIssue, LinkTicket, LinkDesignDoc, LinkDocument, LinkDashboard, LinkOther:
+		return true
+	}
+	return false
+}
+
+// LogEntryLink is a reference from a log entry to a PR, ticket, document...
+type LogEntryLink struct {
+	Kind  LinkKind `json:"kind" validate:"required"`
+	URL   string   `json:"url" validate:"required,url,max=2000"`
+	Title *string  `json:"title,omitempty" validate:"omitempty,max=200"`
+}
+
+// Attachment is a file stored alongside a log entry
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	LogEntryID  uuid.UUID `json:"log_entry_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentUsage reports a user's attachment storage consumption
+type AttachmentUsage struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionAction describes the change that produced a log entry revision
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionDeleted  RevisionAction = "deleted"
+	RevisionRestored RevisionAction = "restored"
+)
+
+// LogEntrySnapshot is the content of a log entry, including its tags and links, at a point in time
+type LogEntrySnapshot struct {
+	Title           string         `json:"title"`
+	Description     *string        `json:"description,omitempty"`
+	Type            ActivityType   `json:"type"`
+	ProjectID       *uuid.UUID     `json:"project_id,omitempty"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	DurationMinutes int            `json:"duration_minutes"`
+	ValueRating     ValueRating    `json:"value_rating"`
+	ImpactLevel     ImpactLevel    `json:"impact_level"`
+	Tags            []string       `json:"tags"`
+	Links           []LogEntryLink `json:"links"`
+}
+
+// LogEntryRevision is one immutable version in the history of a log entry
+type LogEntryRevision struct {
+	LogEntryID uuid.UUID      `json:"log_entry_id"`
+	Revision   int            `json:"revision"`
+	Action     RevisionAction `json:"action"`
+	LogEntrySnapshot
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TrashedLogEntry is a soft-deleted log entry waiting to be restored or purged
+type TrashedLogEntry struct {
+	ID uuid.UUID `json:"id"`
+	LogEntrySnapshot
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OccurrenceStatus represents the state of a single template occurrence
+type OccurrenceStatus string
+
+const (
+	OccurrencePending      OccurrenceStatus = "pending"
+	OccurrenceMaterialized OccurrenceStatus = "materialized"
+	OccurrenceSkipped      OccurrenceStatus = "skipped"
+)
+
+// IsValid checks if the OccurrenceStatus is valid
+func (o OccurrenceStatus) IsValid() bool {
+	switch o {
+	case OccurrencePending, OccurrenceMaterialized, OccurrenceSkipped:
+		return true
+	}
+	return false
+}
+
+// LogTemplate represents a recurring log entry template such as a daily standup
+type LogTemplate struct {
+	ID                uuid.UUID    `json:"id" db:"id"`
+	UserID            uuid.UUID    `json:"user_id" db:"user_id"`
+	ProjectID         *uuid.UUID   `json:"project_id,omitempty" db:"project_id"`
+	Title             string       `json:"title" db:"title"`
+	Description       *string      `json:"description,omitempty" db:"description"`
+	Type              ActivityType `json:"type" db:"type"`
+	ValueRating       ValueRating  `json:"value_rating" db:"value_rating"`
+	ImpactLevel       ImpactLevel  `json:"impact_level" db:"impact_level"`
+	Tags              []string     `json:"tags" db:"tags"`
+	RecurrenceRule    string       `json:"recurrence_rule" db:"recurrence_rule"`
+	StartDate         string       `json:"start_date" db:"start_date"`       // YYYY-MM-DD
+	EndDate           *string      `json:"end_date,omitempty" db:"end_date"` // YYYY-MM-DD
+	StartTime         string       `json:"start_time" db:"start_time"`       // HH:MM in Timezone
+	DurationMinutes   int          `json:"duration_minutes" db:"duration_minutes"`
+	Timezone          string       `json:"timezone" db:"timezone"`
+	IsActive          bool         `json:"is_active" db:"is_active"`
+	MaterializedUntil *string      `json:"materialized_until,omitempty" db:"materialized_until"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// LogTemplateRequest represents the data required to create or update a log template
+type LogTemplateRequest struct {
+	Title           string       `json:"title" validate:"required,max=500"`
+	Description     *string      `json:"description,omitempty"`
+	Type            ActivityType `json:"type" validate:"required"`
+	ProjectID       *uuid.UUID   `json:"project_id,omitempty"`
+	ValueRating     ValueRating  `json:"value_rating" validate:"required"`
+	ImpactLevel     ImpactLevel  `json:"impact_level" validate:"required"`
+	Tags            []string     `json:"tags,omitempty" validate:"omitempty,dive,max=100"`
+	RecurrenceRule  string       `json:"recurrence_rule" validate:"required"`
+	StartDate       string       `json:"start_date" validate:"required"`
+	EndDate         *string      `json:"end_date,omitempty"`
+	StartTime       string       `json:"start_time" validate:"required"`
+	DurationMinutes int          `json:"duration_minutes" validate:"required,min=1,max=1440"`
+	Timezone        string       `json:"timezone,omitempty"` // Defaults to the user's timezone
+	IsActive        *bool        `json:"is_active,omitempty"`
+}
+
+// OccurrenceOverrides holds per-occurrence edits applied on top of the template
+type OccurrenceOverrides struct {
+	Title           *string       `json:"title,omitempty"`
+	Description     *string       `json:"description,omitempty"`
+	Type            *ActivityType `json:"type,omitempty"`
+	ProjectID       *uuid.UUID    `json:"project_id,omitempty"`
+	ValueRating     *ValueRating  `json:"value_rating,omitempty"`
+	ImpactLevel     *ImpactLevel  `json:"impact_level,omitempty"`
+	Tags            []string      `json:"tags,omitempty"`
+	StartTime       *string       `json:"start_time,omitempty"` // HH:MM in the template timezone
+	DurationMinutes *int          `json:"duration_minutes,omitempty"`
+}
+
+// LogTemplateOccurrence represents one expanded occurrence of a template
+type LogTemplateOccurrence struct {
+	TemplateID uuid.UUID            `json:"template_id"`
+	Date       string               `json:"date"` // YYYY-MM-DD in the template timezone
+	StartTime  time.Time            `json:"start_time"`
+	EndTime    time.Time            `json:"end_time"`
+	Status     OccurrenceStatus     `json:"status"`
+	LogEntryID *uuid.UUID           `json:"log_entry_id,omitempty"`
+	Overrides  *OccurrenceOverrides `json:"overrides,omitempty"`
+}
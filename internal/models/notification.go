@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind is the type of an email notification
+type NotificationKind string
+
+const (
+	// NotificationWeeklyDigest summarizes the past week with its AI report
+	NotificationWeeklyDigest NotificationKind = "weekly_digest"
+	// NotificationDailyReminder is sent at the end of a day nothing was logged on
+	NotificationDailyReminder NotificationKind = "daily_reminder"
+	// NotificationTaskFailure tells that an insight could not be generated
+	NotificationTaskFailure NotificationKind = "task_failure"
+	// NotificationTest is sent on request to check the email settings
+	NotificationTest NotificationKind = "test"
+)
+
+// NotificationStatus is the state of an email notification
+type NotificationStatus string
+
+const (
+	// NotificationWaiting digests wait for their AI report before they are sent
+	NotificationWaiting NotificationStatus = "waiting"
+	// NotificationPending notifications are waiting for their next send attempt
+	NotificationPending NotificationStatus = "pending"
+	// NotificationSent notifications were accepted by the SMTP server
+	NotificationSent NotificationStatus = "sent"
+	// NotificationFailed notifications were rejected or ran out of attempts
+	NotificationFailed NotificationStatus = "failed"
+	// NotificationSkipped reminders were not needed because something was logged
+	NotificationSkipped NotificationStatus = "skipped"
+)
+
+// PreferenceNotifications is the preferences key holding the user's NotificationPreferences
+const PreferenceNotifications = "notifications"
+
+// DefaultReminderTime is when the daily reminder is sent when the user chose no time
+const DefaultReminderTime = "18:00"
+
+// reminderTimeLayout is the format of NotificationPreferences.ReminderTime
+const reminderTimeLayout = "15:04"
+
+// NotificationPreferences are the email notifications a user opted in to. Every
+// notification is off until turned on.
+type NotificationPreferences struct {
+	WeeklyDigest  bool   `json:"weekly_digest"`
+	DailyReminder bool   `json:"daily_reminder"`
+	TaskFailures  bool   `json:"task_failures"`
+	ReminderTime  string `json:"reminder_time"` // Local end of day as HH:MM, DefaultReminderTime when empty
+}
+
+// NotificationPreferencesFrom returns the notification preferences stored in the user's preferences
+func NotificationPreferencesFrom(preferences map[string]any) NotificationPreferences {
+	values, _ := preferences[PreferenceNotifications].(map[string]any)
+	prefs := NotificationPreferences{ReminderTime: DefaultReminderTime}
+	prefs.WeeklyDigest, _ = values["weekly_digest"].(bool)
+	prefs.DailyReminder, _ = values["daily_reminder"].(bool)
+	prefs.TaskFailures, _ = values["task_failures"].(bool)
+	if value, ok := values["reminder_time"].(string); ok && value != "" {
+		prefs.ReminderTime = value
+	}
+	return prefs
+}
+
+// ReminderClock returns the hour and minute of the daily reminder
+func (p NotificationPreferences) ReminderClock() (int, int) {
+	t, err := time.Parse(reminderTimeLayout, p.ReminderTime)
+	if err != nil {
+		t, _ = time.Parse(reminderTimeLayout, DefaultReminderTime)
+	}
+	return t.Hour(), t.Minute()
+}
+
+// validateNotificationPreferences validates the value of the notifications preference
+func validateNotificationPreferences(value any) error {
+	values, ok := value.(map[string]any)
+	if !ok {
+		return errors.New("notifications must be an object")
+	}
+	for _, key := range []string{"weekly_digest", "daily_reminder", "task_failures"} {
+		if setting, ok := values[key]; ok {
+			if _, isBool := setting.(bool); !isBool {
+				return errors.New("notifications." + key + " must be true or false")
+			}
+		}
+	}
+	if setting, ok := values["reminder_time"]; ok {
+		reminderTime, isString := setting.(string)
+		if _, err := time.Parse(reminderTimeLayout, reminderTime); !isString || err != nil {
+			return errors.New("notifications.reminder_time must be a time as HH:MM")
+		}
+	}
+	return nil
+}
+
+// EmailNotification is an email sent, or to be sent, to a user
+type EmailNotification struct {
+	ID            uuid.UUID          `json:"id"`
+	Kind          NotificationKind   `json:"kind"`
+	PeriodKey     string             `json:"period_key"` // The day, week or task the notification is about
+	Recipient     string             `json:"recipient"`
+	Subject       string             `json:"subject"`
+	Status        NotificationStatus `json:"status"`
+	Attempts      int                `json:"attempts"`
+	NextAttemptAt *time.Time         `json:"next_attempt_at,omitempty"`
+	SentAt        *time.Time         `json:"sent_at,omitempty"`
+	ErrorMessage  *string            `json:"error_message,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// WeeklyDigestReport is the AI report of a week, as generated by workers
+type WeeklyDigestReport struct {
+	Summary         string   `json:"summary"`
+	KeyInsights     []string `json:"key_insights"`
+	Recommendations []string `json:"recommendations"`
+}
+
+// WeeklyDigest is the content of a weekly digest email
+type WeeklyDigest struct {
+	FirstName   string
+	PeriodStart time.Time // First day of the week
+	PeriodEnd   time.Time // Last day of the week
+	Entries     int64
+	Minutes     int64
+	Report      *WeeklyDigestReport // Nil when the report could not be generated
+}
+
+// DailyReminder is the content of a daily reminder email
+type DailyReminder struct {
+	FirstName string
+	Date      time.Time
+	Time      string // The reminder time the user chose
+}
+
+// TaskFailureNotice is the content of an email telling that an insight failed
+type TaskFailureNotice struct {
+	FirstName string
+	TaskID    string
+	Error     string
+	FailedAt  time.Time
+}
+
+// TestEmail is the content of an email sent to check the email settings
+type TestEmail struct {
+	FirstName   string
+	Preferences NotificationPreferences
+}
@@ -39,6 +39,77 @@ type Project struct {
 	IsDefault   bool          `json:"is_default" db:"is_default"`
 	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+
+	// Effort budget; actual effort comes from the project's log entries
+	EstimatedHours  *float64                `json:"estimated_hours,omitempty" db:"estimated_hours"`
+	ActivityBudgets []ProjectActivityBudget `json:"activity_budgets,omitempty"`
+
+	ParentID   *uuid.UUID       `json:"parent_id,omitempty" db:"parent_id"`
+	ArchivedAt *time.Time       `json:"archived_at,omitempty" db:"archived_at"`
+	Defaults   *ProjectDefaults `json:"defaults,omitempty"`
+}
+
+// MaxProjectDepth is the number of project levels: epic, project and workstream
+const MaxProjectDepth = 3
+
+// ProjectDefaults are applied to log entries created on the project without these fields
+type ProjectDefaults struct {
+	Type        ActivityType `json:"type,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	ValueRating ValueRating  `json:"value_rating,omitempty"`
+	ImpactLevel ImpactLevel  `json:"impact_level,omitempty"`
+}
+
+// IsEmpty reports whether no default is set
+func (d *ProjectDefaults) IsEmpty() bool {
+	return d == nil || (d.Type == "" && len(d.Tags) == 0 && d.ValueRating == "" && d.ImpactLevel == "")
+}
+
+// Validate checks that every default that is set is a valid value
+func (d *ProjectDefaults) Validate() error {
+	if d == nil {
+		return nil
+	}
+	if d.Type != "" && !d.Type.IsValid() {
+		return errors.New("invalid default activity type")
+	}
+	if d.ValueRating != "" && !d.ValueRating.IsValid() {
+		return errors.New("invalid default value rating")
+	}
+	if d.ImpactLevel != "" && !d.ImpactLevel.IsValid() {
+		return errors.New("invalid default impact level")
+	}
+	for _, tag := range d.Tags {
+		if tag == "" || len(tag) > 100 {
+			return errors.New("default tags must be between 1 and 100 characters")
+		}
+	}
+	return nil
+}
+
+// Apply fills in the fields of a log entry request that were omitted
+func (d *ProjectDefaults) Apply(req *LogEntryRequest) {
+	if d == nil {
+		return
+	}
+	if req.Type == "" {
+		req.Type = d.Type
+	}
+	if len(req.Tags) == 0 && len(d.Tags) > 0 {
+		req.Tags = append([]string(nil), d.Tags...)
+	}
+	if req.ValueRating == "" {
+		req.ValueRating = d.ValueRating
+	}
+	if req.ImpactLevel == "" {
+		req.ImpactLevel = d.ImpactLevel
+	}
+}
+
+// ProjectActivityBudget is the part of a project's estimate set aside for one activity type
+type ProjectActivityBudget struct {
+	ActivityType   ActivityType `json:"activity_type"`
+	EstimatedHours float64      `json:"estimated_hours"`
 }
 
 // ProjectRequest represents the data required to create or update a project
@@ -50,6 +121,12 @@ type ProjectRequest struct {
 	StartDate   *time.Time    `json:"start_date,omitempty"`
 	EndDate     *time.Time    `json:"end_date,omitempty"`
 	IsDefault   bool          `json:"is_default"`
+
+	EstimatedHours  *float64                `json:"estimated_hours,omitempty"`
+	ActivityBudgets []ProjectActivityBudget `json:"activity_budgets,omitempty"`
+
+	ParentID *uuid.UUID       `json:"parent_id,omitempty"`
+	Defaults *ProjectDefaults `json:"defaults,omitempty"`
 }
 
 // Validate validates the project data
@@ -59,3 +136,75 @@ func (p *Project) Validate() error {
 	}
 	return nil
 }
+
+// ProjectStats reports the effort logged on a project and its sub-projects against
+// its budget. Hours are rounded to 2 decimals; budget fields are omitted when no estimate is set.
+type ProjectStats struct {
+	ProjectID      uuid.UUID     `json:"project_id"`
+	ProjectName    string        `json:"project_name"`
+	Status         ProjectStatus `json:"status"`
+	StartDate      *string       `json:"start_date,omitempty"`
+	EndDate        *string       `json:"end_date,omitempty"`
+	EstimatedHours *float64      `json:"estimated_hours,omitempty"`
+	ActualMinutes  int           `json:"actual_minutes"`
+	ActualHours    float64       `json:"actual_hours"`
+	RemainingHours *float64      `json:"remaining_hours,omitempty"` // Negative once over budget
+	PercentUsed    *float64      `json:"percent_used,omitempty"`
+	OverBudget     bool          `json:"over_budget"`
+
+	Activities  []*ProjectActivityStats `json:"activities"`
+	Burn        []*ProjectBurnPoint     `json:"burn"`
+	Projection  *ProjectProjection      `json:"projection,omitempty"`
+	Lifetime    *ProjectLifetimeMetrics `json:"lifetime,omitempty"`
+	Subprojects []*ProjectRollup        `json:"subprojects,omitempty"`
+}
+
+// ProjectRollup is the effort logged directly on one project of a hierarchy
+type ProjectRollup struct {
+	ProjectID     uuid.UUID  `json:"project_id"`
+	ProjectName   string     `json:"project_name"`
+	ParentID      *uuid.UUID `json:"parent_id,omitempty"`
+	Depth         int        `json:"depth"` // Levels below the project the stats are for
+	Archived      bool       `json:"archived"`
+	EntryCount    int        `json:"entry_count"`
+	ActualMinutes int        `json:"actual_minutes"`
+	ActualHours   float64    `json:"actual_hours"`
+}
+
+// ProjectActivityStats is the effort of one activity type, budgeted or not
+type ProjectActivityStats struct {
+	ActivityType   ActivityType `json:"activity_type"`
+	EstimatedHours *float64     `json:"estimated_hours,omitempty"`
+	ActualMinutes  int          `json:"actual_minutes"`
+	ActualHours    float64      `json:"actual_hours"`
+	PercentUsed    *float64     `json:"percent_used,omitempty"`
+	OverBudget     bool         `json:"over_budget"`
+}
+
+// ProjectBurnPoint is one day of the burn-up (cumulative hours) and
+// burn-down (remaining hours) series
+type ProjectBurnPoint struct {
+	Date                string   `json:"date"`
+	Hours               float64  `json:"hours"`
+	CumulativeHours     float64  `json:"cumulative_hours"`
+	RemainingHours      *float64 `json:"remaining_hours,omitempty"`
+	IdealRemainingHours *float64 `json:"ideal_remaining_hours,omitempty"` // Straight line from start_date to end_date
+}
+
+// ProjectProjection extrapolates the average daily burn rate since the project started
+type ProjectProjection struct {
+	DailyRateHours      float64  `json:"daily_rate_hours"`
+	ProjectedCompletion *string  `json:"projected_completion,omitempty"`  // Day the budget runs out at the current rate
+	ProjectedTotalHours *float64 `json:"projected_total_hours,omitempty"` // Hours logged by end_date at the current rate
+	WillExceedBudget    bool     `json:"will_exceed_budget"`
+}
+
+// ProjectLifetimeMetrics are a project's all-time activity metrics
+type ProjectLifetimeMetrics struct {
+	TotalEntries      int64      `json:"total_entries"`
+	ActiveDays        int64      `json:"active_days"`
+	ContributorsCount int64      `json:"contributors_count"`
+	FirstActivity     *time.Time `json:"first_activity,omitempty"`
+	LastActivity      *time.Time `json:"last_activity,omitempty"`
+	RecentEntries30d  int64      `json:"recent_entries_30d"`
+}
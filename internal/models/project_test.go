@@ -59,3 +59,85 @@ func TestProject_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectDefaults_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *ProjectDefaults
+		wantErr  bool
+	}{
+		{name: "nil", defaults: nil, wantErr: false},
+		{name: "empty", defaults: &ProjectDefaults{}, wantErr: false},
+		{
+			name: "all set",
+			defaults: &ProjectDefaults{
+				Type:        ActivityDevelopment,
+				Tags:        []string{"billing"},
+				ValueRating: ValueHigh,
+				ImpactLevel: ImpactTeam,
+			},
+			wantErr: false,
+		},
+		{name: "invalid type", defaults: &ProjectDefaults{Type: "napping"}, wantErr: true},
+		{name: "invalid value rating", defaults: &ProjectDefaults{ValueRating: "huge"}, wantErr: true},
+		{name: "invalid impact level", defaults: &ProjectDefaults{ImpactLevel: "world"}, wantErr: true},
+		{name: "empty tag", defaults: &ProjectDefaults{Tags: []string{""}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.defaults.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProjectDefaults_Apply(t *testing.T) {
+	defaults := &ProjectDefaults{
+		Type:        ActivityMeeting,
+		Tags:        []string{"billing", "q3"},
+		ValueRating: ValueHigh,
+		ImpactLevel: ImpactTeam,
+	}
+
+	t.Run("fills omitted fields", func(t *testing.T) {
+		req := &LogEntryRequest{Title: "Planning"}
+		defaults.Apply(req)
+
+		assert.Equal(t, ActivityMeeting, req.Type)
+		assert.Equal(t, []string{"billing", "q3"}, req.Tags)
+		assert.Equal(t, ValueHigh, req.ValueRating)
+		assert.Equal(t, ImpactTeam, req.ImpactLevel)
+
+		req.Tags[0] = "changed"
+		assert.Equal(t, "billing", defaults.Tags[0], "the defaults are not shared with the request")
+	})
+
+	t.Run("keeps provided fields", func(t *testing.T) {
+		req := &LogEntryRequest{
+			Type:        ActivityDevelopment,
+			Tags:        []string{"api"},
+			ValueRating: ValueLow,
+		}
+		defaults.Apply(req)
+
+		assert.Equal(t, ActivityDevelopment, req.Type)
+		assert.Equal(t, []string{"api"}, req.Tags)
+		assert.Equal(t, ValueLow, req.ValueRating)
+		assert.Equal(t, ImpactTeam, req.ImpactLevel)
+	})
+
+	t.Run("nil defaults", func(t *testing.T) {
+		req := &LogEntryRequest{}
+		var none *ProjectDefaults
+		none.Apply(req)
+
+		assert.Empty(t, req.Type)
+		assert.True(t, none.IsEmpty())
+		assert.False(t, defaults.IsEmpty())
+	})
+}
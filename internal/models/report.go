@@ -0,0 +1,216 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PerformanceReviewRequest represents a request for a performance review PDF.
+// Dates are inclusive calendar days in the user's timezone.
+type PerformanceReviewRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+// ReportExport is a report file generated asynchronously by a worker.
+// Once completed the file can be downloaded until ExpiresAt.
+type ReportExport struct {
+	ID          uuid.UUID  `json:"id"`
+	ReportType  ReportType `json:"report_type"`
+	Status      TaskStatus `json:"status"`
+	PeriodStart string     `json:"period_start"`
+	PeriodEnd   string     `json:"period_end"`
+	FileName    string     `json:"file_name,omitempty"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Expired     bool       `json:"expired"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Downloadable reports whether the report file can be downloaded
+func (r *ReportExport) Downloadable() bool {
+	return r.Status == TaskCompleted && !r.Expired
+}
+
+// PerformanceReview holds the data of a performance review report
+type PerformanceReview struct {
+	UserName     string            `json:"user_name"`
+	Timezone     string            `json:"timezone"`
+	PeriodStart  string            `json:"period_start"`
+	PeriodEnd    string            `json:"period_end"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+	TotalEntries int               `json:"total_entries"`
+	TotalMinutes int               `json:"total_minutes"`
+	Activities   []ReportBreakdown `json:"activities"`
+	ValueRatings []ReportBreakdown `json:"value_ratings"`
+	ImpactLevels []ReportBreakdown `json:"impact_levels"`
+	TopProjects  []ReportProject   `json:"top_projects"`
+	Highlights   []ReportHighlight `json:"highlights"`
+	Narrative    *ReportNarrative  `json:"narrative,omitempty"`
+}
+
+// ReportBreakdown is one bar of a report chart
+type ReportBreakdown struct {
+	Label   string `json:"label"`
+	Entries int    `json:"entries"`
+	Minutes int    `json:"minutes"`
+}
+
+// ReportProject is a project with the time logged on it during the report period
+type ReportProject struct {
+	Name       string  `json:"name"`
+	Color      string  `json:"color,omitempty"`
+	Entries    int     `json:"entries"`
+	Minutes    int     `json:"minutes"`
+	Percentage float64 `json:"percentage"`
+}
+
+// ReportHighlight is a high-value or wide-impact log entry
+type ReportHighlight struct {
+	Date        string       `json:"date"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Type        ActivityType `json:"type"`
+	ProjectName string       `json:"project_name,omitempty"`
+	ValueRating ValueRating  `json:"value_rating"`
+	ImpactLevel ImpactLevel  `json:"impact_level"`
+	Minutes     int          `json:"minutes"`
+}
+
+// ReportNarrative is the AI-written narrative of a report, taken from the generated insights
+type ReportNarrative struct {
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary,omitempty"`
+	Content     string    `json:"content"`
+	PeriodStart string    `json:"period_start"`
+	PeriodEnd   string    `json:"period_end"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// ReportRenderTask is the payload of a report rendering task sent to workers
+type ReportRenderTask struct {
+	ReportType        ReportType         `json:"report_type"`
+	FileName          string             `json:"file_name"`
+	PerformanceReview *PerformanceReview `json:"performance_review,omitempty"`
+}
+
+// ReportRenderResult is the result of a report rendering task
+type ReportRenderResult struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// BragDocFormat is the output format of a brag document
+type BragDocFormat string
+
+const (
+	BragDocMarkdown BragDocFormat = "markdown"
+	BragDocHTML     BragDocFormat = "html"
+)
+
+// IsValid checks if the BragDocFormat is valid
+func (f BragDocFormat) IsValid() bool {
+	return f == BragDocMarkdown || f == BragDocHTML
+}
+
+// ContentType returns the MIME type of documents in the format
+func (f BragDocFormat) ContentType() string {
+	if f == BragDocHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// BragDocPeriod names the calendar period a brag document covers, in the user's timezone
+type BragDocPeriod string
+
+const (
+	BragDocThisMonth   BragDocPeriod = "month"
+	BragDocThisQuarter BragDocPeriod = "quarter"
+	BragDocThisHalf    BragDocPeriod = "half"
+	BragDocThisYear    BragDocPeriod = "year"
+	BragDocLastMonth   BragDocPeriod = "last_month"
+	BragDocLastQuarter BragDocPeriod = "last_quarter"
+	BragDocLastHalf    BragDocPeriod = "last_half"
+	BragDocLastYear    BragDocPeriod = "last_year"
+	BragDocCustom      BragDocPeriod = "custom"
+)
+
+// IsValid checks if the BragDocPeriod is valid
+func (p BragDocPeriod) IsValid() bool {
+	switch p {
+	case BragDocThisMonth, BragDocThisQuarter, BragDocThisHalf, BragDocThisYear,
+		BragDocLastMonth, BragDocLastQuarter, BragDocLastHalf, BragDocLastYear, BragDocCustom:
+		return true
+	}
+	return false
+}
+
+// BragDocRequest represents a request for a brag document.
+// StartDate and EndDate (YYYY-MM-DD, inclusive) are required for the custom period.
+type BragDocRequest struct {
+	Period    BragDocPeriod
+	StartDate string
+	EndDate   string
+	Template  string
+	Format    BragDocFormat
+	Summarize bool // Whether to ask a worker for an AI summary of each group
+}
+
+// BragDoc holds the data a brag document template is executed with: the
+// accomplishments of a period grouped by project, then by impact level
+type BragDoc struct {
+	UserName     string           `json:"user_name"`
+	Timezone     string           `json:"timezone"`
+	Period       BragDocPeriod    `json:"period"`
+	PeriodStart  string           `json:"period_start"`
+	PeriodEnd    string           `json:"period_end"`
+	GeneratedAt  time.Time        `json:"generated_at"`
+	TotalEntries int              `json:"total_entries"`
+	TotalMinutes int              `json:"total_minutes"`
+	Summarized   bool             `json:"summarized"` // Whether the groups carry AI summaries
+	Truncated    bool             `json:"truncated"`  // Whether entries beyond the document limit were left out
+	Projects     []BragDocProject `json:"projects"`
+}
+
+// BragDocProject is the work done on one project; entries without a project share one group
+type BragDocProject struct {
+	ID      *uuid.UUID     `json:"id,omitempty"`
+	Name    string         `json:"name"`
+	Entries int            `json:"entries"`
+	Minutes int            `json:"minutes"`
+	Groups  []BragDocGroup `json:"groups"`
+}
+
+// BragDocGroup is the work on a project at one impact level
+type BragDocGroup struct {
+	ImpactLevel     ImpactLevel             `json:"impact_level"`
+	Summary         string                  `json:"summary,omitempty"`
+	Entries         int                     `json:"entries"`
+	Minutes         int                     `json:"minutes"`
+	Accomplishments []BragDocAccomplishment `json:"accomplishments"`
+}
+
+// BragDocAccomplishment is a log entry listed in a brag document, with a link back to it
+type BragDocAccomplishment struct {
+	ID          uuid.UUID    `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Date        string       `json:"date"`
+	Type        ActivityType `json:"type"`
+	ValueRating ValueRating  `json:"value_rating"`
+	ImpactLevel ImpactLevel  `json:"impact_level"`
+	Minutes     int          `json:"minutes"`
+	URL         string       `json:"url"`
+}
+
+// BragDocTemplate describes a template brag documents can be rendered with
+type BragDocTemplate struct {
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"built_in"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "github.com/google/uuid"
+
+// SuggestionSource tells how a log entry suggestion was produced
+type SuggestionSource string
+
+const (
+	SuggestionSourceWorker    SuggestionSource = "worker"
+	SuggestionSourceHeuristic SuggestionSource = "heuristic"
+)
+
+// LogEntrySuggestionRequest is the draft of a new log entry to suggest metadata for
+type LogEntrySuggestionRequest struct {
+	Title       string  `json:"title" validate:"required,max=500"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+}
+
+// LogEntrySuggestion holds suggested tags, type, project and ratings for a new log entry.
+// Tags are always taken from the user's existing vocabulary.
+type LogEntrySuggestion struct {
+	Tags        []string         `json:"tags"`
+	Type        ActivityType     `json:"type"`
+	ProjectID   *uuid.UUID       `json:"project_id,omitempty"`
+	ValueRating ValueRating      `json:"value_rating"`
+	ImpactLevel ImpactLevel      `json:"impact_level"`
+	Source      SuggestionSource `json:"source"`
+	Cached      bool             `json:"cached"`
+}
@@ -8,19 +8,48 @@ import (
 
 // Tag represents a tag that can be applied to log entries
 type Tag struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name" validate:"required,max=100"`
-	Color       string    `json:"color" db:"color" validate:"required,hexcolor"`
-	Description *string   `json:"description,omitempty" db:"description"`
-	UsageCount  int       `json:"usage_count" db:"usage_count"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" db:"user_id"` // nil for shared and team tags
+	TeamID      *uuid.UUID `json:"team_id,omitempty" db:"team_id"` // set for team tags
+	ParentID    *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	Name        string     `json:"name" db:"name" validate:"required,max=100"`
+	Color       string     `json:"color" db:"color" validate:"required,hexcolor"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	Shared      bool       `json:"shared"`
+	UsageCount  int        `json:"usage_count" db:"usage_count"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 }
 
 // TagRequest represents the data required to create or update a tag
 type TagRequest struct {
-	Name        string  `json:"name" validate:"required,max=100"`
-	Color       string  `json:"color" validate:"required,hexcolor"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Color       string     `json:"color" validate:"required,hexcolor"`
+	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// TagAlias is an alternative name that resolves to a tag when log entries are written
+type TagAlias struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	TagID     uuid.UUID `json:"tag_id" db:"tag_id"`
+	Alias     string    `json:"alias" db:"alias"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TagAliasRequest represents the data required to add an alias to a tag
+type TagAliasRequest struct {
+	Alias string `json:"alias" validate:"required,max=100"`
+}
+
+// TagMergeRequest identifies the tag that absorbs the merged tag
+type TagMergeRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+// TagMergeResult describes the outcome of merging one tag into another
+type TagMergeResult struct {
+	Tag          *Tag  `json:"tag"`
+	MovedEntries int64 `json:"moved_entries"`
 }
 
 // TagUsage represents tag usage statistics for a user
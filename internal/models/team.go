@@ -0,0 +1,201 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamRole is the role of a member within a team
+type TeamRole string
+
+const (
+	TeamRoleOwner   TeamRole = "owner"   // Manages the team, its members and roles
+	TeamRoleManager TeamRole = "manager" // Manages members and sees team analytics
+	TeamRoleMember  TeamRole = "member"  // Shares projects and logs time on shared projects
+	TeamRoleViewer  TeamRole = "viewer"  // Sees the team and its shared projects
+)
+
+// IsValid checks if the TeamRole is valid
+func (r TeamRole) IsValid() bool {
+	switch r {
+	case TeamRoleOwner, TeamRoleManager, TeamRoleMember, TeamRoleViewer:
+		return true
+	}
+	return false
+}
+
+// rank orders roles from viewer (1) to owner (4); unknown roles rank 0
+func (r TeamRole) rank() int {
+	switch r {
+	case TeamRoleOwner:
+		return 4
+	case TeamRoleManager:
+		return 3
+	case TeamRoleMember:
+		return 2
+	case TeamRoleViewer:
+		return 1
+	}
+	return 0
+}
+
+// Outranks reports whether r grants more than other
+func (r TeamRole) Outranks(other TeamRole) bool {
+	return r.rank() > other.rank()
+}
+
+// TeamPermission is an action guarded by the team role of the caller
+type TeamPermission string
+
+const (
+	TeamPermissionView          TeamPermission = "view"           // See the team, its members and shared projects
+	TeamPermissionLogTime       TeamPermission = "log_time"       // Log entries on shared projects
+	TeamPermissionShareProjects TeamPermission = "share_projects" // Share own projects with the team
+	TeamPermissionViewAnalytics TeamPermission = "view_analytics" // See aggregate team analytics
+	TeamPermissionManageMembers TeamPermission = "manage_members" // Add and remove members and viewers, unshare projects
+	TeamPermissionManageTags    TeamPermission = "manage_tags"    // Create, edit and delete the team's tags
+	TeamPermissionManageTeam    TeamPermission = "manage_team"    // Rename or delete the team, manage managers and owners
+)
+
+// Can reports whether the role grants permission
+func (r TeamRole) Can(permission TeamPermission) bool {
+	switch permission {
+	case TeamPermissionView:
+		return r.rank() >= TeamRoleViewer.rank()
+	case TeamPermissionLogTime, TeamPermissionShareProjects:
+		return r.rank() >= TeamRoleMember.rank()
+	case TeamPermissionViewAnalytics, TeamPermissionManageMembers, TeamPermissionManageTags:
+		return r.rank() >= TeamRoleManager.rank()
+	case TeamPermissionManageTeam:
+		return r == TeamRoleOwner
+	}
+	return false
+}
+
+// CanAssign reports whether the role may grant, change or revoke target: owners
+// manage every role, managers only members and viewers
+func (r TeamRole) CanAssign(target TeamRole) bool {
+	if r == TeamRoleOwner {
+		return true
+	}
+	return r.Can(TeamPermissionManageMembers) && r.Outranks(target)
+}
+
+// TeamSharing is how much of a member's activity shows up in team analytics
+type TeamSharing string
+
+const (
+	TeamSharingNamed     TeamSharing = "named"     // Counted in team totals and listed per member
+	TeamSharingAnonymous TeamSharing = "anonymous" // Counted in team totals only
+	TeamSharingPrivate   TeamSharing = "private"   // Left out of team analytics
+)
+
+// PreferenceTeamSharing is the preferences key holding the user's TeamSharing
+const PreferenceTeamSharing = "team_sharing"
+
+// IsValid checks if the TeamSharing is valid
+func (s TeamSharing) IsValid() bool {
+	switch s {
+	case TeamSharingNamed, TeamSharingAnonymous, TeamSharingPrivate:
+		return true
+	}
+	return false
+}
+
+// TeamSharingFromPreferences returns the team sharing stored in the user's preferences,
+// defaulting to anonymous: members are only listed by name once they opt in
+func TeamSharingFromPreferences(preferences map[string]any) TeamSharing {
+	if value, ok := preferences[PreferenceTeamSharing].(string); ok && TeamSharing(value).IsValid() {
+		return TeamSharing(value)
+	}
+	return TeamSharingAnonymous
+}
+
+// Team is a shared workspace; Role is the role of the user the team was loaded for
+type Team struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	Role        TeamRole   `json:"role,omitempty"`
+	MemberCount int        `json:"member_count,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TeamRequest represents the data required to create or update a team
+type TeamRequest struct {
+	Name        string  `json:"name" validate:"required,max=200"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+// TeamMember is a member of a team with the profile fields teams may see
+type TeamMember struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      TeamRole  `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// TeamMemberRequest adds an existing user to a team by email
+type TeamMemberRequest struct {
+	Email string   `json:"email" validate:"required,email"`
+	Role  TeamRole `json:"role" validate:"required"`
+}
+
+// TeamRoleRequest changes the role of a member
+type TeamRoleRequest struct {
+	Role TeamRole `json:"role" validate:"required"`
+}
+
+// TeamProjectRequest shares a project with a team
+type TeamProjectRequest struct {
+	ProjectID uuid.UUID `json:"project_id" validate:"required"`
+}
+
+// TeamAnalytics aggregates the activity of members on the projects shared with a
+// team. It never includes raw entries, and honors each member's TeamSharing.
+type TeamAnalytics struct {
+	TeamID        uuid.UUID `json:"team_id"`
+	TeamName      string    `json:"team_name"`
+	TotalEntries  int       `json:"total_entries"`
+	TotalMinutes  int       `json:"total_minutes"`
+	TotalHours    float64   `json:"total_hours"`
+	Contributors  int       `json:"contributors"`   // Members whose activity is counted
+	HiddenMembers int       `json:"hidden_members"` // Members whose activity is left out for privacy
+
+	Projects   []*TeamProjectActivity   `json:"projects"`
+	Activities []*TeamActivityBreakdown `json:"activities"`
+	Members    []*TeamMemberActivity    `json:"members"` // Named members only
+}
+
+// TeamProjectActivity is the activity counted on one shared project
+type TeamProjectActivity struct {
+	ProjectID    uuid.UUID `json:"project_id"`
+	ProjectName  string    `json:"project_name"`
+	EntryCount   int       `json:"entry_count"`
+	TotalMinutes int       `json:"total_minutes"`
+	TotalHours   float64   `json:"total_hours"`
+	Contributors int       `json:"contributors"`
+}
+
+// TeamActivityBreakdown is the time counted for one activity type
+type TeamActivityBreakdown struct {
+	ActivityType ActivityType `json:"activity_type"`
+	TotalMinutes int          `json:"total_minutes"`
+	Percentage   float64      `json:"percentage"`
+}
+
+// TeamMemberActivity is the aggregate activity of a member who shares it by name
+type TeamMemberActivity struct {
+	UserID       uuid.UUID `json:"user_id"`
+	Name         string    `json:"name"`
+	Role         TeamRole  `json:"role"`
+	EntryCount   int       `json:"entry_count"`
+	TotalMinutes int       `json:"total_minutes"`
+	TotalHours   float64   `json:"total_hours"`
+	Projects     int       `json:"projects"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamRole_IsValid(t *testing.T) {
+	assert.True(t, TeamRoleOwner.IsValid())
+	assert.True(t, TeamRoleManager.IsValid())
+	assert.True(t, TeamRoleMember.IsValid())
+	assert.True(t, TeamRoleViewer.IsValid())
+	assert.False(t, TeamRole("admin").IsValid())
+	assert.False(t, TeamRole("").IsValid())
+}
+
+func TestTeamRole_Can(t *testing.T) {
+	permissions := []TeamPermission{
+		TeamPermissionView,
+		TeamPermissionLogTime,
+		TeamPermissionShareProjects,
+		TeamPermissionViewAnalytics,
+		TeamPermissionManageMembers,
+		TeamPermissionManageTags,
+		TeamPermissionManageTeam,
+	}
+	tests := []struct {
+		role TeamRole
+		want []bool
+	}{
+		{TeamRoleOwner, []bool{true, true, true, true, true, true, true}},
+		{TeamRoleManager, []bool{true, true, true, true, true, true, false}},
+		{TeamRoleMember, []bool{true, true, true, false, false, false, false}},
+		{TeamRoleViewer, []bool{true, false, false, false, false, false, false}},
+		{TeamRole("admin"), []bool{false, false, false, false, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			for i, permission := range permissions {
+				assert.Equal(t, tt.want[i], tt.role.Can(permission), "permission %s", permission)
+			}
+			assert.False(t, tt.role.Can(TeamPermission("unknown")))
+		})
+	}
+}
+
+func TestTeamRole_CanAssign(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   TeamRole
+		target TeamRole
+		want   bool
+	}{
+		{"owner assigns owner", TeamRoleOwner, TeamRoleOwner, true},
+		{"owner assigns manager", TeamRoleOwner, TeamRoleManager, true},
+		{"manager assigns member", TeamRoleManager, TeamRoleMember, true},
+		{"manager assigns viewer", TeamRoleManager, TeamRoleViewer, true},
+		{"manager cannot assign manager", TeamRoleManager, TeamRoleManager, false},
+		{"manager cannot assign owner", TeamRoleManager, TeamRoleOwner, false},
+		{"member cannot assign viewer", TeamRoleMember, TeamRoleViewer, false},
+		{"viewer cannot assign", TeamRoleViewer, TeamRoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.CanAssign(tt.target))
+		})
+	}
+}
+
+func TestTeamSharingFromPreferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences map[string]any
+		want        TeamSharing
+	}{
+		{"nil preferences", nil, TeamSharingAnonymous},
+		{"not set", map[string]any{"theme": "dark"}, TeamSharingAnonymous},
+		{"named", map[string]any{"team_sharing": "named"}, TeamSharingNamed},
+		{"anonymous", map[string]any{"team_sharing": "anonymous"}, TeamSharingAnonymous},
+		{"private", map[string]any{"team_sharing": "private"}, TeamSharingPrivate},
+		{"unknown value", map[string]any{"team_sharing": "public"}, TeamSharingAnonymous},
+		{"not a string", map[string]any{"team_sharing": true}, TeamSharingAnonymous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TeamSharingFromPreferences(tt.preferences))
+		})
+	}
+}
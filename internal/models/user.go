@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -64,3 +65,61 @@ type UserPasswordChangeRequest struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
 	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"` // NewPassword does not accept passwords longer than 72 bytes
 }
+
+// WeekStart is the first day of the week used when grouping analytics by week
+type WeekStart string
+
+const (
+	WeekStartMonday WeekStart = "monday"
+	WeekStartSunday WeekStart = "sunday"
+)
+
+// PreferenceWeekStart is the preferences key holding the user's WeekStart
+const PreferenceWeekStart = "week_start"
+
+// IsValid checks if the WeekStart is valid
+func (w WeekStart) IsValid() bool {
+	switch w {
+	case WeekStartMonday, WeekStartSunday:
+		return true
+	}
+	return false
+}
+
+// Weekday returns the week start as a time.Weekday
+func (w WeekStart) Weekday() time.Weekday {
+	if w == WeekStartSunday {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// WeekStartFromPreferences returns the week start stored in the user's preferences, defaulting to Monday
+func WeekStartFromPreferences(preferences map[string]any) WeekStart {
+	if value, ok := preferences[PreferenceWeekStart].(string); ok && WeekStart(value).IsValid() {
+		return WeekStart(value)
+	}
+	return WeekStartMonday
+}
+
+// ValidatePreferences validates the preference values the API interprets
+func ValidatePreferences(preferences map[string]any) error {
+	if value, ok := preferences[PreferenceWeekStart]; ok {
+		weekStart, isString := value.(string)
+		if !isString || !WeekStart(weekStart).IsValid() {
+			return errors.New("week_start must be monday or sunday")
+		}
+	}
+	if value, ok := preferences[PreferenceTeamSharing]; ok {
+		sharing, isString := value.(string)
+		if !isString || !TeamSharing(sharing).IsValid() {
+			return errors.New("team_sharing must be named, anonymous or private")
+		}
+	}
+	if value, ok := preferences[PreferenceNotifications]; ok {
+		if err := validateNotificationPreferences(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
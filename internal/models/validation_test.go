@@ -34,6 +34,56 @@ func TestValidateTimezone(t *testing.T) {
 	}
 }
 
+func TestValidatePreferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences map[string]any
+		wantErr     bool
+		want        WeekStart
+	}{
+		{"nil preferences", nil, false, WeekStartMonday},
+		{"no week start", map[string]any{"theme": "dark"}, false, WeekStartMonday},
+		{"monday", map[string]any{"week_start": "monday"}, false, WeekStartMonday},
+		{"sunday", map[string]any{"week_start": "sunday"}, false, WeekStartSunday},
+		{"unknown day", map[string]any{"week_start": "friday"}, true, WeekStartMonday},
+		{"not a string", map[string]any{"week_start": 1}, true, WeekStartMonday},
+		{"team sharing", map[string]any{"team_sharing": "anonymous"}, false, WeekStartMonday},
+		{"unknown team sharing", map[string]any{"team_sharing": "public"}, true, WeekStartMonday},
+		{"notifications", map[string]any{"notifications": map[string]any{"weekly_digest": true, "reminder_time": "17:30"}}, false, WeekStartMonday},
+		{"notifications not an object", map[string]any{"notifications": true}, true, WeekStartMonday},
+		{"notification not a bool", map[string]any{"notifications": map[string]any{"daily_reminder": "yes"}}, true, WeekStartMonday},
+		{"invalid reminder time", map[string]any{"notifications": map[string]any{"reminder_time": "25:00"}}, true, WeekStartMonday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePreferences(tt.preferences)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, WeekStartFromPreferences(tt.preferences))
+		})
+	}
+}
+
+func TestNotificationPreferencesFrom(t *testing.T) {
+	prefs := NotificationPreferencesFrom(nil)
+	assert.Equal(t, NotificationPreferences{ReminderTime: DefaultReminderTime}, prefs, "every notification is off by default")
+	hour, minute := prefs.ReminderClock()
+	assert.Equal(t, []int{18, 0}, []int{hour, minute})
+
+	prefs = NotificationPreferencesFrom(map[string]any{
+		"notifications": map[string]any{"weekly_digest": true, "task_failures": true, "reminder_time": "17:45"},
+	})
+	assert.True(t, prefs.WeeklyDigest)
+	assert.False(t, prefs.DailyReminder)
+	assert.True(t, prefs.TaskFailures)
+	hour, minute = prefs.ReminderClock()
+	assert.Equal(t, []int{17, 45}, []int{hour, minute})
+}
+
 func TestValidateHexColor(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is the type of an event webhooks can subscribe to
+type WebhookEvent string
+
+const (
+	WebhookLogCreated       WebhookEvent = "log.created"
+	WebhookLogUpdated       WebhookEvent = "log.updated"
+	WebhookLogDeleted       WebhookEvent = "log.deleted"
+	WebhookProjectCreated   WebhookEvent = "project.created"
+	WebhookProjectUpdated   WebhookEvent = "project.updated"
+	WebhookProjectDeleted   WebhookEvent = "project.deleted"
+	WebhookInsightGenerated WebhookEvent = "insight.generated"
+	WebhookReportCompleted  WebhookEvent = "report.completed"
+	WebhookTaskFailed       WebhookEvent = "task.failed"
+	// WebhookPing is sent on request to test a webhook, whatever its events
+	WebhookPing WebhookEvent = "ping"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []WebhookEvent{
+	WebhookLogCreated, WebhookLogUpdated, WebhookLogDeleted,
+	WebhookProjectCreated, WebhookProjectUpdated, WebhookProjectDeleted,
+	WebhookInsightGenerated, WebhookReportCompleted, WebhookTaskFailed,
+}
+
+// IsValid checks if the WebhookEvent can be subscribed to
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending deliveries are waiting for their next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded deliveries were answered with a 2xx status
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed deliveries were rejected or ran out of attempts
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// IsValid checks if the WebhookDeliveryStatus is valid
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// Webhook is an HTTP endpoint the user's events are posted to. Its signing
+// secret is only shown when it is created or rotated.
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	Name      string         `json:"name" db:"name"`
+	URL       string         `json:"url" db:"url"`
+	Events    []WebhookEvent `json:"events" db:"events"`
+	Active    bool           `json:"active" db:"active"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookRequest represents the data used to create or update a webhook
+type WebhookRequest struct {
+	Name   string         `json:"name" binding:"required,max=100"`
+	URL    string         `json:"url" binding:"required,max=2000"`
+	Events []WebhookEvent `json:"events" binding:"required,min=1"`
+	Active *bool          `json:"active"` // true by default
+}
+
+// WebhookWithSecret is a webhook with the secret its deliveries are signed with
+type WebhookWithSecret struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is one event sent to a webhook and the outcome of its latest
+// attempt. Replays of a delivery share its event ID.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id"`
+	EventID        uuid.UUID             `json:"event_id"`
+	EventType      WebhookEvent          `json:"event_type"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	Error          *string               `json:"error,omitempty"`
+	DurationMs     *int                  `json:"duration_ms,omitempty"`
+	ReplayOf       *uuid.UUID            `json:"replay_of,omitempty"`
+	Payload        json.RawMessage       `json:"payload,omitempty"` // Only filled when a single delivery is retrieved
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook
+type WebhookPayload struct {
+	ID        uuid.UUID    `json:"id"` // The event ID
+	Type      WebhookEvent `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      any          `json:"data"`
+}
+
+// WorkerTaskOutcome is the result a worker reported for a task, as passed to
+// the listeners of task results
+type WorkerTaskOutcome struct {
+	TaskID   string
+	UserID   string
+	TaskType string // "insight_generation", "weekly_report", ...
+	Failed   bool
+	Result   string // The result JSON of completed tasks
+	Error    string
+}
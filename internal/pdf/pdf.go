@@ -0,0 +1,345 @@
+// Package pdf writes simple PDF documents: text in the standard Helvetica
+// fonts, filled rectangles and lines on A4 pages.
+//
+// It covers what generated reports need without an external dependency.
+// Coordinates are in points with the origin at the top-left corner of the
+// page, text is encoded with WinAnsiEncoding (Latin-1 plus typographic
+// quotes, dashes and the euro sign) and page content is Flate compressed.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PageWidth is the width of an A4 page in points
+	PageWidth = 595.28
+	// PageHeight is the height of an A4 page in points
+	PageHeight = 841.89
+)
+
+// Font selects one of the built-in fonts
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Color is an RGB color
+type Color struct {
+	R, G, B uint8
+}
+
+// Common colors
+var (
+	Black = Color{0, 0, 0}
+	White = Color{255, 255, 255}
+)
+
+// HexColor parses a #rrggbb color
+func HexColor(s string) (Color, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return Color{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, false
+	}
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+// Document is a PDF document being built page by page
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+}
+
+// New creates an empty document with the given title
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// AddPage starts a new page; later drawing goes to it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount returns the number of pages
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// page returns the current page, starting the first one when needed
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws s with its baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, c Color, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s rg %s %s Td (%s) Tj ET\n",
+		font+1, num(size), rgb(c), num(x), num(PageHeight-y), escape(encode(s)))
+}
+
+// Rect fills a rectangle whose top-left corner is at x, y
+func (d *Document) Rect(x, y, w, h float64, c Color) {
+	fmt.Fprintf(d.page(), "%s rg %s %s %s %s re f\n",
+		rgb(c), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Line strokes a line from x1, y1 to x2, y2
+func (d *Document) Line(x1, y1, x2, y2, width float64, c Color) {
+	fmt.Fprintf(d.page(), "%s RG %s w %s %s m %s %s l S\n",
+		rgb(c), num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	out := &countingWriter{w: w}
+	var offsets []int64
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-5 are fixed, then each page is followed by its content stream
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (EngLog) >>", escape(encode(d.title))))
+
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), 7+2*i))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return out.n, err
+		}
+		if err := zw.Close(); err != nil {
+			return out.n, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.n, out.err
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == Bold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, r := range s {
+		_, like := winAnsi(r)
+		total += widths[like-' ']
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap breaks s into lines no wider than width, breaking words only when a
+// single word does not fit on a line
+func Wrap(font Font, size, width float64, s string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if TextWidth(font, size, candidate) <= width {
+			line = candidate
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for TextWidth(font, size, word) > width {
+			cut := fit(font, size, width, word)
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Truncate shortens s with an ellipsis so that it fits in width
+func Truncate(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	const ellipsis = "…"
+	cut := fit(font, size, width-TextWidth(font, size, ellipsis), s)
+	return strings.TrimRight(s[:cut], " ") + ellipsis
+}
+
+// fit returns the byte length of the longest prefix of s, at least one
+// character, that fits in width
+func fit(font Font, size, width float64, s string) int {
+	end := 0
+	for i, r := range s {
+		next := i + len(string(r))
+		if end > 0 && TextWidth(font, size, s[:next]) > width {
+			break
+		}
+		end = next
+	}
+	return end
+}
+
+// encode converts s to WinAnsiEncoding; tabs and newlines become spaces and
+// characters without a WinAnsi code become question marks
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		code, _ := winAnsi(r)
+		out = append(out, code)
+	}
+	return out
+}
+
+// escape quotes encoded text for a PDF literal string
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// winAnsi returns the WinAnsiEncoding code of r and an ASCII character of
+// about the same width
+func winAnsi(r rune) (code, like byte) {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return ' ', ' '
+	case r >= ' ' && r <= '~':
+		return byte(r), byte(r)
+	case r >= 0xA0 && r <= 0xFF:
+		return byte(r), latin1Like[r-0xA0]
+	}
+	if extra, ok := winAnsiExtras[r]; ok {
+		return extra[0], extra[1]
+	}
+	return '?', '?'
+}
+
+// latin1Like holds, for each character from U+00A0 to U+00FF, an ASCII
+// character of about the same width
+const latin1Like = " !c$$$|$`Or$+-O`" +
+	"r+rr`u$.`rr$%%%?" +
+	"AAAAAAWCEEEEIIII" +
+	"DNOOOOO+OUUUUYPe" +
+	"aaaaaamceeeetttt" +
+	"onooooo+ouuuuypy"
+
+// winAnsiExtras maps characters outside Latin-1 to their WinAnsiEncoding code
+// and an ASCII character of about the same width
+var winAnsiExtras = map[rune][2]byte{
+	'€': {0x80, '$'},
+	'…': {0x85, 'W'},
+	'‘': {0x91, '\''},
+	'’': {0x92, '\''},
+	'“': {0x93, '-'},
+	'”': {0x94, '-'},
+	'•': {0x95, '*'},
+	'–': {0x96, '$'},
+	'—': {0x97, 'W'},
+	'™': {0x99, 'W'},
+}
+
+// helveticaWidths holds the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// helveticaBoldWidths holds the widths of the printable ASCII characters in
+// Helvetica-Bold, in thousandths of the font size
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 to ?
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P to _
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` to o
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p to ~
+}
+
+// num formats a coordinate with at most two decimals
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// rgb formats c as PDF color components
+func rgb(c Color) string {
+	return num(float64(c.R)/255) + " " + num(float64(c.G)/255) + " " + num(float64(c.B)/255)
+}
+
+// countingWriter counts written bytes and keeps the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) {
+	_, _ = c.Write([]byte(s))
+}
@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexColor(t *testing.T) {
+	c, ok := HexColor("#3498db")
+	require.True(t, ok)
+	assert.Equal(t, Color{0x34, 0x98, 0xdb}, c)
+
+	_, ok = HexColor("#fff")
+	assert.False(t, ok)
+	_, ok = HexColor("#zzzzzz")
+	assert.False(t, ok)
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 27.336, TextWidth(Regular, 12, "Hello"), 0.001)
+	assert.Greater(t, TextWidth(Bold, 12, "Hello"), TextWidth(Regular, 12, "Hello"))
+	assert.Equal(t, TextWidth(Regular, 10, "Sao"), TextWidth(Regular, 10, "São"), "accented letters are as wide as their base letter")
+}
+
+func TestWrap(t *testing.T) {
+	width := TextWidth(Regular, 10, "shipped the new")
+	assert.Equal(t, []string{"shipped the new", "release to", "production"}, Wrap(Regular, 10, width, "shipped the new release to\nproduction"))
+	assert.Nil(t, Wrap(Regular, 10, width, "  "))
+
+	lines := Wrap(Regular, 10, TextWidth(Regular, 10, "1234"), "1234567890")
+	assert.Equal(t, []string{"1234", "5678", "90"}, lines, "words longer than a line are broken")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Platform", Truncate(Regular, 10, 100, "Platform"))
+
+	truncated := Truncate(Regular, 10, TextWidth(Regular, 10, "Platform…"), "Platform migration")
+	assert.Equal(t, "Platform…", truncated)
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, []byte("S\xe3o Paulo \x96 caf\xe9 ?"), encode("São Paulo – café 日"))
+	assert.Equal(t, `a \(b\) \\`, escape(encode(`a (b) \`)))
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New("Performance review")
+	doc.Text(50, 60, Bold, 18, Black, "Review (Q1)")
+	doc.Rect(50, 80, 100, 10, Color{52, 152, 219})
+	doc.AddPage()
+	doc.Line(50, 100, 200, 100, 0.5, Black)
+	require.Equal(t, 2, doc.PageCount())
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, buf.String(), "/Count 2")
+	assert.Contains(t, buf.String(), "/Title (Performance review)")
+
+	// Every xref entry points at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 9, "5 fixed objects and 2 per page")
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// Page content is compressed
+	stream := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindSubmatch(out)
+	require.NotNil(t, stream)
+	zr, err := zlib.NewReader(bytes.NewReader(stream[1]))
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "BT /F2 18 Tf 0 0 0 rg 50 781.89 Td (Review \\(Q1\\)) Tj ET\n"))
+	assert.Contains(t, string(content), "0.2 0.6 0.86 rg 50 751.89 100 10 re f\n")
+}
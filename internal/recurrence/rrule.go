@@ -0,0 +1,449 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents the FREQ part of an RRULE
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the number of periods walked while expanding a rule so a
+// malformed or very sparse rule can never spin forever
+const maxPeriods = 100000
+
+var (
+	ErrEmptyRule        = errors.New("recurrence rule is empty")
+	ErrMissingFrequency = errors.New("recurrence rule must define FREQ")
+)
+
+// WeekdayNum is a BYDAY entry such as MO, -1FR or 2TU
+type WeekdayNum struct {
+	N   int // 0 means every such weekday in the period
+	Day time.Weekday
+}
+
+// Rule is a parsed subset of an RFC 5545 recurrence rule.
+// Supported parts: FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses an RRULE string such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO".
+// A leading "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return nil, ErrEmptyRule
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+
+	for part := range strings.SplitSeq(s, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(value)
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for item := range strings.SplitSeq(value, ",") {
+				wd, err := parseWeekdayNum(item)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for item := range strings.SplitSeq(value, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", item)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for item := range strings.SplitSeq(value, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", item)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			day, ok := weekdayCodes[value]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", value)
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, ErrMissingFrequency
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot be used together")
+	}
+
+	return rule, nil
+}
+
+// String renders the rule back into RRULE syntax (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+// String renders a BYDAY entry, e.g. "MO" or "-1FR"
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayCode(w.Day)
+	}
+	return strconv.Itoa(w.N) + weekdayCode(w.Day)
+}
+
+// Between returns the occurrences of the rule starting at dtstart that fall in
+// the half-open interval [from, to). Occurrences keep dtstart's wall clock time
+// and location, so DST transitions do not shift the local start time.
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.walk(dtstart, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// walk visits every occurrence in chronological order until fn returns false,
+// COUNT/UNTIL is reached or the period limit is exhausted
+func (r *Rule) walk(dtstart time.Time, fn func(time.Time) bool) {
+	interval := max(r.Interval, 1)
+	emitted := 0
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.candidates(dtstart, period*interval)
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && c.After(*r.Until) {
+				return
+			}
+			if !fn(c) {
+				return
+			}
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// candidates expands the period that is `offset` frequency units after dtstart
+func (r *Rule) candidates(dtstart time.Time, offset int) []time.Time {
+	loc := dtstart.Location()
+	hour, minute, second := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, loc)
+	}
+
+	var out []time.Time
+
+	switch r.Freq {
+	case Daily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+offset)
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			out = append(out, day)
+		}
+
+	case Weekly:
+		// Align to the configured week start so INTERVAL counts whole weeks
+		shift := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-shift+7*offset)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []WeekdayNum{{Day: dtstart.Weekday()}}
+		}
+		for _, wd := range days {
+			delta := (int(wd.Day) - int(r.WeekStart) + 7) % 7
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+delta)
+			if r.matchesMonth(day.Month()) {
+				out = append(out, day)
+			}
+		}
+
+	case Monthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(offset), 1)
+		if r.matchesMonth(first.Month()) {
+			out = append(out, r.monthCandidates(dtstart, first)...)
+		}
+
+	case Yearly:
+		year := dtstart.Year() + offset
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, m := range months {
+			out = append(out, r.monthCandidates(dtstart, at(year, m, 1))...)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return dedupe(out)
+}
+
+// monthCandidates expands BYMONTHDAY / BYDAY inside the month that starts at first
+func (r *Rule) monthCandidates(dtstart, first time.Time) []time.Time {
+	hour, minute, second := dtstart.Clock()
+	loc := dtstart.Location()
+	daysInMonth := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, loc).Day()
+	at := func(day int) time.Time {
+		return time.Date(first.Year(), first.Month(), day, hour, minute, second, 0, loc)
+	}
+
+	var out []time.Time
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			day := d
+			if d < 0 {
+				day = daysInMonth + d + 1
+			}
+			if day < 1 || day > daysInMonth {
+				continue
+			}
+			t := at(day)
+			if r.matchesWeekday(t) {
+				out = append(out, t)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			var matches []int
+			for day := 1; day <= daysInMonth; day++ {
+				if at(day).Weekday() == wd.Day {
+					matches = append(matches, day)
+				}
+			}
+			switch {
+			case wd.N == 0:
+				for _, day := range matches {
+					out = append(out, at(day))
+				}
+			case wd.N > 0 && wd.N <= len(matches):
+				out = append(out, at(matches[wd.N-1]))
+			case wd.N < 0 && -wd.N <= len(matches):
+				out = append(out, at(matches[len(matches)+wd.N]))
+			}
+		}
+	default:
+		// Months without the start day (e.g. the 31st) are skipped, per RFC 5545
+		if dtstart.Day() <= daysInMonth {
+			out = append(out, at(dtstart.Day()))
+		}
+	}
+
+	return out
+}
+
+func (r *Rule) matchesMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && daysInMonth+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(times []time.Time) []time.Time {
+	if len(times) < 2 {
+		return times
+	}
+	out := times[:1]
+	for _, t := range times[1:] {
+		if !t.Equal(out[len(out)-1]) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+
+	code := s[len(s)-2:]
+	day, ok := weekdayCodes[code]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+
+	n := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+		}
+	}
+
+	return WeekdayNum{N: n, Day: day}, nil
+}
+
+func parseUntil(s string) (time.Time, error) {
+	layouts := []string{"20060102T150405Z", "20060102T150405", "20060102"}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL is inclusive of the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", s)
+}
+
+func weekdayCode(d time.Weekday) string {
+	for code, day := range weekdayCodes {
+		if day == d {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("weekly with interval and days", func(t *testing.T) {
+		rule, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE")
+		require.NoError(t, err)
+		assert.Equal(t, Weekly, rule.Freq)
+		assert.Equal(t, 2, rule.Interval)
+		assert.Equal(t, []WeekdayNum{{Day: time.Monday}, {Day: time.Wednesday}}, rule.ByDay)
+		assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", rule.String())
+	})
+
+	t.Run("monthly with ordinal weekday", func(t *testing.T) {
+		rule, err := Parse("FREQ=MONTHLY;BYDAY=-1FR;COUNT=3")
+		require.NoError(t, err)
+		assert.Equal(t, []WeekdayNum{{N: -1, Day: time.Friday}}, rule.ByDay)
+		assert.Equal(t, 3, rule.Count)
+	})
+
+	t.Run("date-only until covers the whole day", func(t *testing.T) {
+		rule, err := Parse("FREQ=DAILY;UNTIL=20250110")
+		require.NoError(t, err)
+		require.NotNil(t, rule.Until)
+		assert.Equal(t, time.Date(2025, 1, 10, 23, 59, 59, 0, time.UTC), *rule.Until)
+	})
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ",
+	}
+	for _, s := range invalid {
+		t.Run("invalid "+s, func(t *testing.T) {
+			_, err := Parse(s)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	t.Run("daily", func(t *testing.T) {
+		rule, _ := Parse("FREQ=DAILY")
+		start := time.Date(2025, 1, 1, 9, 0, 0, 0, loc)
+		got := rule.Between(start, start, start.AddDate(0, 0, 3))
+		require.Len(t, got, 3)
+		assert.Equal(t, 3, got[2].Day())
+		assert.Equal(t, 9, got[2].Hour())
+	})
+
+	t.Run("every other monday", func(t *testing.T) {
+		rule, _ := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO")
+		start := time.Date(2025, 1, 6, 10, 0, 0, 0, loc) // Monday
+		got := rule.Between(start, start, time.Date(2025, 2, 10, 0, 0, 0, 0, loc))
+		require.Len(t, got, 3)
+		assert.Equal(t, []int{6, 20, 3}, []int{got[0].Day(), got[1].Day(), got[2].Day()})
+	})
+
+	t.Run("weekly skips days before dtstart", func(t *testing.T) {
+		rule, _ := Parse("FREQ=WEEKLY;BYDAY=MO,FR")
+		start := time.Date(2025, 1, 8, 10, 0, 0, 0, loc) // Wednesday
+		got := rule.Between(start, start, time.Date(2025, 1, 14, 0, 0, 0, 0, loc))
+		require.Len(t, got, 2)
+		assert.Equal(t, time.Friday, got[0].Weekday())
+		assert.Equal(t, time.Monday, got[1].Weekday())
+	})
+
+	t.Run("monthly last friday", func(t *testing.T) {
+		rule, _ := Parse("FREQ=MONTHLY;BYDAY=-1FR")
+		start := time.Date(2025, 1, 1, 16, 0, 0, 0, loc)
+		got := rule.Between(start, start, time.Date(2025, 4, 1, 0, 0, 0, 0, loc))
+		require.Len(t, got, 3)
+		assert.Equal(t, []int{31, 28, 28}, []int{got[0].Day(), got[1].Day(), got[2].Day()})
+	})
+
+	t.Run("monthly on the 31st skips short months", func(t *testing.T) {
+		rule, _ := Parse("FREQ=MONTHLY")
+		start := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+		got := rule.Between(start, start, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		require.Len(t, got, 3)
+		assert.Equal(t, []time.Month{time.January, time.March, time.May}, []time.Month{got[0].Month(), got[1].Month(), got[2].Month()})
+	})
+
+	t.Run("count limits occurrences across windows", func(t *testing.T) {
+		rule, _ := Parse("FREQ=DAILY;COUNT=5")
+		start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+		got := rule.Between(start, start.AddDate(0, 0, 3), start.AddDate(0, 1, 0))
+		require.Len(t, got, 2)
+		assert.Equal(t, 4, got[0].Day())
+		assert.Equal(t, 5, got[1].Day())
+	})
+
+	t.Run("until is inclusive", func(t *testing.T) {
+		rule, _ := Parse("FREQ=DAILY;UNTIL=20250103")
+		start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+		got := rule.Between(start, start, start.AddDate(1, 0, 0))
+		assert.Len(t, got, 3)
+	})
+
+	t.Run("keeps wall clock across DST", func(t *testing.T) {
+		ny, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		rule, _ := Parse("FREQ=WEEKLY;BYDAY=FR")
+		start := time.Date(2025, 3, 7, 9, 0, 0, 0, ny)
+		got := rule.Between(start, start, time.Date(2025, 3, 15, 0, 0, 0, 0, ny))
+		require.Len(t, got, 2)
+		assert.Equal(t, 9, got[1].Hour())
+		assert.NotEqual(t, 7*24*time.Hour, got[1].Sub(got[0]))
+	})
+
+	t.Run("yearly by month", func(t *testing.T) {
+		rule, _ := Parse("FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=1")
+		start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+		got := rule.Between(start, start, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC))
+		assert.Len(t, got, 4)
+	})
+}
@@ -0,0 +1,176 @@
+package reports
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/garnizeh/englog/internal/markdown"
+	"github.com/garnizeh/englog/internal/models"
+)
+
+// DefaultBragDocTemplate is the template used when none is chosen
+const DefaultBragDocTemplate = "default"
+
+// bragDocTemplateExt is the file extension of brag document templates
+const bragDocTemplateExt = ".md.tmpl"
+
+//go:embed templates/*.md.tmpl
+var builtinBragDocTemplates embed.FS
+
+// templateNameRe matches the names templates can be chosen by
+var templateNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// bragDocFuncs are the functions available to brag document templates
+var bragDocFuncs = template.FuncMap{
+	"date":     formatDate,
+	"hours":    formatHours,
+	"humanize": func(value any) string { return humanize(fmt.Sprint(value)) },
+	"plural":   plural,
+	"oneline":  func(s string) string { return strings.Join(strings.Fields(s), " ") },
+	"truncate": truncateRunes,
+}
+
+// bragDocPage wraps brag documents rendered as HTML
+var bragDocPage = htmltemplate.Must(htmltemplate.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+h1, h2, h3 { line-height: 1.25; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .25rem; margin-top: 2rem; }
+li { margin-bottom: .5rem; }
+a { color: #2c6fbb; }
+</style>
+</head>
+<body>
+{{ .Body }}</body>
+</html>
+`))
+
+// BragDocTemplates holds the templates brag documents are rendered with. Templates
+// are Go text/templates that produce Markdown from a models.BragDoc; HTML is
+// rendered from the same Markdown.
+type BragDocTemplates struct {
+	templates map[string]*template.Template
+	builtIn   map[string]bool
+}
+
+// LoadBragDocTemplates loads the built-in templates and, when dir is set, every
+// <name>.md.tmpl file in it. Templates in dir replace built-in ones of the same
+// name, so a company can make its own review format the default.
+func LoadBragDocTemplates(dir string) (*BragDocTemplates, error) {
+	t := &BragDocTemplates{
+		templates: make(map[string]*template.Template),
+		builtIn:   make(map[string]bool),
+	}
+
+	if err := t.load(builtinBragDocTemplates, "templates", true); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := t.load(os.DirFS(dir), ".", false); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// load parses the templates in one directory of fsys
+func (t *BragDocTemplates) load(fsys fs.FS, dir string, builtIn bool) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read brag document templates: %w", err)
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), bragDocTemplateExt)
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if !templateNameRe.MatchString(name) {
+			return fmt.Errorf("invalid brag document template name: %s", entry.Name())
+		}
+
+		src, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, entry.Name())))
+		if err != nil {
+			return fmt.Errorf("failed to read brag document template %s: %w", name, err)
+		}
+		tmpl, err := template.New(name).Funcs(bragDocFuncs).Option("missingkey=error").Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("invalid brag document template %s: %w", name, err)
+		}
+
+		t.templates[name] = tmpl
+		t.builtIn[name] = builtIn
+	}
+	return nil
+}
+
+// List returns the available templates by name
+func (t *BragDocTemplates) List() []models.BragDocTemplate {
+	list := make([]models.BragDocTemplate, 0, len(t.templates))
+	for name := range t.templates {
+		list = append(list, models.BragDocTemplate{Name: name, BuiltIn: t.builtIn[name]})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Has reports whether a template exists
+func (t *BragDocTemplates) Has(name string) bool {
+	_, ok := t.templates[name]
+	return ok
+}
+
+// Render writes a brag document in the given format using the named template
+func (t *BragDocTemplates) Render(w io.Writer, name string, format models.BragDocFormat, doc *models.BragDoc) error {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return fmt.Errorf("brag document template not found: %s", name)
+	}
+
+	var md bytes.Buffer
+	if err := tmpl.Execute(&md, doc); err != nil {
+		return fmt.Errorf("failed to execute brag document template %s: %w", name, err)
+	}
+
+	if format != models.BragDocHTML {
+		_, err := md.WriteTo(w)
+		return err
+	}
+
+	title := "Brag document"
+	if doc.UserName != "" {
+		title += ": " + doc.UserName
+	}
+	return bragDocPage.Execute(w, struct {
+		Title string
+		Body  htmltemplate.HTML
+	}{
+		Title: title,
+		// markdown.Render escapes all text and only links to safe URLs
+		Body: htmltemplate.HTML(markdown.Render(md.String())),
+	})
+}
+
+// truncateRunes shortens s to at most n characters, ending it with "…" when cut
+func truncateRunes(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
@@ -0,0 +1,139 @@
+package reports
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleBragDoc() *models.BragDoc {
+	entryID := uuid.MustParse("0b9d8a3e-5f41-4c1e-9f0a-7d2c6b1e4a90")
+	return &models.BragDoc{
+		UserName:     "Ana Souza",
+		PeriodStart:  "2025-01-01",
+		PeriodEnd:    "2025-03-31",
+		TotalEntries: 2,
+		TotalMinutes: 150,
+		Summarized:   true,
+		Projects: []models.BragDocProject{
+			{
+				Name:    "Platform",
+				Entries: 2,
+				Minutes: 150,
+				Groups: []models.BragDocGroup{
+					{
+						ImpactLevel: models.ImpactCompany,
+						Summary:     "I moved billing to the new ledger.",
+						Entries:     1,
+						Minutes:     90,
+						Accomplishments: []models.BragDocAccomplishment{{
+							ID:          entryID,
+							Title:       "Billing migration",
+							Description: "Moved invoices\n\nto the <new> ledger",
+							Date:        "2025-02-10",
+							Type:        models.ActivityDevelopment,
+							ValueRating: models.ValueCritical,
+							ImpactLevel: models.ImpactCompany,
+							Minutes:     90,
+							URL:         "https://englog.example.com/logs/" + entryID.String(),
+						}},
+					},
+					{
+						ImpactLevel: models.ImpactTeam,
+						Entries:     1,
+						Minutes:     60,
+						Accomplishments: []models.BragDocAccomplishment{{
+							ID:          uuid.New(),
+							Title:       "Code review guild",
+							Date:        "2025-03-01",
+							Type:        models.ActivityCodeReview,
+							ValueRating: models.ValueMedium,
+							ImpactLevel: models.ImpactTeam,
+							Minutes:     60,
+							URL:         "https://englog.example.com/logs/x",
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestBragDocTemplates_Markdown(t *testing.T) {
+	templates, err := LoadBragDocTemplates("")
+	require.NoError(t, err)
+	assert.Equal(t, []models.BragDocTemplate{{Name: "compact", BuiltIn: true}, {Name: "default", BuiltIn: true}}, templates.List())
+
+	var buf bytes.Buffer
+	require.NoError(t, templates.Render(&buf, DefaultBragDocTemplate, models.BragDocMarkdown, sampleBragDoc()))
+
+	want := "# Brag document: Ana Souza\n\n" +
+		"Jan 1, 2025 – Mar 31, 2025 · 2 entries · 2.5 h\n\n" +
+		"## Platform\n\n" +
+		"2.5 h across 2 entries\n\n" +
+		"### Company impact\n\n" +
+		"I moved billing to the new ledger.\n\n" +
+		"- [Billing migration](https://englog.example.com/logs/0b9d8a3e-5f41-4c1e-9f0a-7d2c6b1e4a90) — Feb 10, 2025 · Development · Critical value · 1.5 h\n" +
+		"  Moved invoices to the <new> ledger\n\n" +
+		"### Team impact\n\n" +
+		"- [Code review guild](https://englog.example.com/logs/x) — Mar 1, 2025 · Code review · Medium value · 1.0 h\n"
+	assert.Equal(t, want, buf.String())
+
+	buf.Reset()
+	require.NoError(t, templates.Render(&buf, "compact", models.BragDocMarkdown, &models.BragDoc{PeriodStart: "2025-01-01", PeriodEnd: "2025-03-31"}))
+	assert.Equal(t, "# Accomplishments, Jan 1, 2025 – Mar 31, 2025\n\nNo entries in this period.\n", buf.String())
+
+	assert.ErrorContains(t, templates.Render(&buf, "missing", models.BragDocMarkdown, sampleBragDoc()), "template not found")
+}
+
+func TestBragDocTemplates_HTML(t *testing.T) {
+	templates, err := LoadBragDocTemplates("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, templates.Render(&buf, DefaultBragDocTemplate, models.BragDocHTML, sampleBragDoc()))
+	out := buf.String()
+
+	assert.Contains(t, out, "<title>Brag document: Ana Souza</title>")
+	assert.Contains(t, out, "<h3>Company impact</h3>")
+	assert.Contains(t, out, `<a href="https://englog.example.com/logs/0b9d8a3e-5f41-4c1e-9f0a-7d2c6b1e4a90" rel="nofollow noopener">Billing migration</a>`)
+	assert.Contains(t, out, "to the &lt;new&gt; ledger", "entry text is escaped")
+}
+
+func TestLoadBragDocTemplates_Dir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "acme.md.tmpl"), []byte("{{ range .Projects }}{{ .Name }}: {{ hours .Minutes }}\n{{ end }}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "default.md.tmpl"), []byte("Acme review for {{ .UserName }}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600))
+
+	templates, err := LoadBragDocTemplates(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []models.BragDocTemplate{
+		{Name: "acme", BuiltIn: false},
+		{Name: "compact", BuiltIn: true},
+		{Name: "default", BuiltIn: false},
+	}, templates.List())
+
+	var buf bytes.Buffer
+	require.NoError(t, templates.Render(&buf, "acme", models.BragDocMarkdown, sampleBragDoc()))
+	assert.Equal(t, "Platform: 2.5 h\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, templates.Render(&buf, DefaultBragDocTemplate, models.BragDocMarkdown, sampleBragDoc()))
+	assert.Equal(t, "Acme review for Ana Souza\n", buf.String(), "directory templates replace built-in ones")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.md.tmpl"), []byte("{{ .Projects"), 0o600))
+	_, err = LoadBragDocTemplates(dir)
+	assert.ErrorContains(t, err, "invalid brag document template broken")
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "short", truncateRunes(10, "short"))
+	assert.Equal(t, "ação…", truncateRunes(5, "ação longa"))
+}
@@ -0,0 +1,382 @@
+// Package reports lays out generated reports: the performance review as a PDF
+// document and brag documents as Markdown or HTML rendered from templates.
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/pdf"
+)
+
+// ContentType is the content type of rendered PDF reports
+const ContentType = "application/pdf"
+
+const (
+	margin        = 50.0
+	bottomMargin  = 60.0
+	contentWidth  = pdf.PageWidth - 2*margin
+	barLabelWidth = 140.0
+	barCaptionGap = 110.0
+	barHeight     = 10.0
+	barRowHeight  = 18.0
+	// maxDescriptionLines bounds the description shown under a highlight
+	maxDescriptionLines = 3
+)
+
+var (
+	textColor   = pdf.Color{R: 33, G: 37, B: 41}
+	mutedColor  = pdf.Color{R: 108, G: 117, B: 125}
+	ruleColor   = pdf.Color{R: 222, G: 226, B: 230}
+	panelColor  = pdf.Color{R: 241, G: 243, B: 245}
+	accentColor = pdf.Color{R: 52, G: 152, B: 219}
+)
+
+// palette colors chart bars that have no color of their own
+var palette = []pdf.Color{
+	{R: 52, G: 152, B: 219}, {R: 46, G: 204, B: 113}, {R: 155, G: 89, B: 182},
+	{R: 241, G: 196, B: 15}, {R: 230, G: 126, B: 34}, {R: 231, G: 76, B: 60},
+	{R: 26, G: 188, B: 156}, {R: 52, G: 73, B: 94}, {R: 149, G: 165, B: 166},
+}
+
+var (
+	valueColors = map[string]pdf.Color{
+		string(models.ValueCritical): {R: 231, G: 76, B: 60},
+		string(models.ValueHigh):     {R: 230, G: 126, B: 34},
+		string(models.ValueMedium):   {R: 241, G: 196, B: 15},
+		string(models.ValueLow):      {R: 149, G: 165, B: 166},
+	}
+	impactColors = map[string]pdf.Color{
+		string(models.ImpactCompany):    {R: 142, G: 68, B: 173},
+		string(models.ImpactDepartment): {R: 41, G: 128, B: 185},
+		string(models.ImpactTeam):       {R: 26, G: 188, B: 156},
+		string(models.ImpactPersonal):   {R: 149, G: 165, B: 166},
+	}
+)
+
+// bar is one row of a horizontal bar chart
+type bar struct {
+	label   string
+	value   float64
+	caption string
+	color   pdf.Color
+}
+
+// RenderPerformanceReview writes a performance review as a PDF document: summary
+// figures, the AI-written narrative, activity, value and impact charts, the top
+// projects and the high-impact highlights of the period
+func RenderPerformanceReview(w io.Writer, review *models.PerformanceReview) error {
+	period := formatDate(review.PeriodStart) + " – " + formatDate(review.PeriodEnd)
+	doc := pdf.New("Performance review " + period)
+	l := &layout{doc: doc, footer: "EngLog performance review · " + period}
+	l.newPage()
+
+	l.doc.Text(margin, l.y+22, pdf.Bold, 24, textColor, "Performance review")
+	l.y += 36
+	subtitle := period
+	if review.UserName != "" {
+		subtitle = review.UserName + " · " + period
+	}
+	l.line(pdf.Regular, 12, textColor, subtitle)
+	l.line(pdf.Regular, 9, mutedColor, fmt.Sprintf("Generated %s (%s)",
+		review.GeneratedAt.Format("January 2, 2006 15:04"), review.Timezone))
+	l.y += 12
+
+	l.summary(review)
+	l.narrative(review.Narrative)
+
+	l.heading("Where the time went")
+	activities := make([]bar, len(review.Activities))
+	for i, activity := range review.Activities {
+		activities[i] = bar{
+			label:   humanize(activity.Label),
+			value:   float64(activity.Minutes),
+			caption: formatHours(activity.Minutes) + " · " + percent(activity.Minutes, review.TotalMinutes),
+			color:   palette[i%len(palette)],
+		}
+	}
+	l.barChart(activities)
+
+	l.heading("Value delivered")
+	l.barChart(ratingBars(review.ValueRatings, valueColors, review.TotalEntries))
+	l.heading("Impact reach")
+	l.barChart(ratingBars(review.ImpactLevels, impactColors, review.TotalEntries))
+
+	l.heading("Top projects")
+	projects := make([]bar, len(review.TopProjects))
+	for i, project := range review.TopProjects {
+		color, ok := pdf.HexColor(project.Color)
+		if !ok {
+			color = palette[i%len(palette)]
+		}
+		projects[i] = bar{
+			label: project.Name,
+			value: float64(project.Minutes),
+			caption: fmt.Sprintf("%s · %d %s · %s%%", formatHours(project.Minutes), project.Entries,
+				plural(project.Entries, "entry", "entries"), strconv.FormatFloat(project.Percentage, 'f', -1, 64)),
+			color: color,
+		}
+	}
+	l.barChart(projects)
+
+	l.heading("Highlights")
+	l.highlights(review.Highlights)
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// layout is a top-down cursor over a document that starts new pages as needed
+type layout struct {
+	doc    *pdf.Document
+	y      float64
+	pages  int
+	footer string
+}
+
+// newPage starts a page with its footer and moves the cursor to the top
+func (l *layout) newPage() {
+	l.doc.AddPage()
+	l.pages++
+	l.y = margin
+	l.doc.Text(margin, pdf.PageHeight-30, pdf.Regular, 8, mutedColor, fmt.Sprintf("%s · page %d", l.footer, l.pages))
+}
+
+// need starts a new page unless height points still fit on the current one
+func (l *layout) need(height float64) {
+	if l.y+height > pdf.PageHeight-bottomMargin {
+		l.newPage()
+	}
+}
+
+// line writes one line of text at the cursor
+func (l *layout) line(font pdf.Font, size float64, color pdf.Color, s string) {
+	l.lineAt(margin, font, size, color, s)
+}
+
+// lineAt writes one line of text at the cursor, starting at x
+func (l *layout) lineAt(x float64, font pdf.Font, size float64, color pdf.Color, s string) {
+	height := size * 1.4
+	l.need(height)
+	l.doc.Text(x, l.y+size, font, size, color, s)
+	l.y += height
+}
+
+// paragraph writes wrapped text at the cursor, indented by indent
+func (l *layout) paragraph(indent float64, font pdf.Font, size float64, color pdf.Color, s string) {
+	for _, text := range pdf.Wrap(font, size, contentWidth-indent, s) {
+		l.lineAt(margin+indent, font, size, color, text)
+	}
+}
+
+// heading starts a section, keeping room for its first rows on the same page
+func (l *layout) heading(title string) {
+	l.y += 14
+	l.need(60)
+	l.doc.Text(margin, l.y+14, pdf.Bold, 14, textColor, title)
+	l.y += 20
+	l.doc.Line(margin, l.y, margin+contentWidth, l.y, 0.5, ruleColor)
+	l.y += 10
+}
+
+// summary draws the headline figures of the review as a row of cards
+func (l *layout) summary(review *models.PerformanceReview) {
+	highValue := 0
+	for _, rating := range review.ValueRatings {
+		if rating.Label == string(models.ValueHigh) || rating.Label == string(models.ValueCritical) {
+			highValue += rating.Entries
+		}
+	}
+	wideImpact := 0
+	for _, level := range review.ImpactLevels {
+		if level.Label == string(models.ImpactDepartment) || level.Label == string(models.ImpactCompany) {
+			wideImpact += level.Entries
+		}
+	}
+
+	cards := []struct{ label, value string }{
+		{"Time logged", formatHours(review.TotalMinutes)},
+		{"Entries", strconv.Itoa(review.TotalEntries)},
+		{"High-value entries", strconv.Itoa(highValue)},
+		{"Department+ impact", strconv.Itoa(wideImpact)},
+	}
+
+	const gap, height = 10.0, 54.0
+	width := (contentWidth - gap*float64(len(cards)-1)) / float64(len(cards))
+	l.need(height)
+	for i, card := range cards {
+		x := margin + float64(i)*(width+gap)
+		l.doc.Rect(x, l.y, width, height, panelColor)
+		l.doc.Rect(x, l.y, 3, height, accentColor)
+		l.doc.Text(x+12, l.y+18, pdf.Regular, 8, mutedColor, strings.ToUpper(card.label))
+		l.doc.Text(x+12, l.y+42, pdf.Bold, 18, textColor, card.value)
+	}
+	l.y += height
+}
+
+// narrative writes the AI-written narrative. Markdown headings and list items
+// are kept as bold lines and bullets; other markup is dropped.
+func (l *layout) narrative(narrative *models.ReportNarrative) {
+	l.heading("Narrative")
+	if narrative == nil {
+		l.paragraph(0, pdf.Regular, 10, mutedColor,
+			"No AI narrative has been generated for this period yet. Generate a performance_review insight "+
+				"covering the period and request the report again to include it.")
+		return
+	}
+
+	if narrative.Title != "" {
+		l.paragraph(0, pdf.Bold, 11, textColor, stripMarkup(narrative.Title))
+	}
+	l.line(pdf.Regular, 8, mutedColor, fmt.Sprintf("AI-generated on %s for %s – %s",
+		narrative.GeneratedAt.Format("January 2, 2006"), formatDate(narrative.PeriodStart), formatDate(narrative.PeriodEnd)))
+	if narrative.Summary != "" {
+		l.y += 4
+		l.paragraph(0, pdf.Regular, 10, mutedColor, stripMarkup(narrative.Summary))
+	}
+
+	for _, text := range strings.Split(strings.ReplaceAll(narrative.Content, "\r\n", "\n"), "\n") {
+		text = strings.TrimSpace(text)
+		switch {
+		case text == "":
+			l.y += 5
+		case strings.HasPrefix(text, "#"):
+			l.y += 4
+			l.paragraph(0, pdf.Bold, 11, textColor, stripMarkup(strings.TrimLeft(text, "# ")))
+		case strings.HasPrefix(text, "- "), strings.HasPrefix(text, "* "):
+			l.need(14)
+			l.doc.Text(margin+4, l.y+10, pdf.Regular, 10, textColor, "•")
+			l.paragraph(14, pdf.Regular, 10, textColor, stripMarkup(text[2:]))
+		default:
+			l.paragraph(0, pdf.Regular, 10, textColor, stripMarkup(text))
+		}
+	}
+}
+
+// barChart draws one labelled horizontal bar per item, scaled to the largest value
+func (l *layout) barChart(bars []bar) {
+	if len(bars) == 0 {
+		l.line(pdf.Regular, 10, mutedColor, "No entries in this period.")
+		return
+	}
+
+	largest := 0.0
+	for _, b := range bars {
+		largest = max(largest, b.value)
+	}
+
+	trackX := margin + barLabelWidth
+	trackWidth := contentWidth - barLabelWidth - barCaptionGap
+	for _, b := range bars {
+		l.need(barRowHeight)
+		l.doc.Text(margin, l.y+11, pdf.Regular, 9, textColor, pdf.Truncate(pdf.Regular, 9, barLabelWidth-10, b.label))
+		l.doc.Rect(trackX, l.y+3, trackWidth, barHeight, panelColor)
+		if largest > 0 && b.value > 0 {
+			l.doc.Rect(trackX, l.y+3, max(trackWidth*b.value/largest, 1), barHeight, b.color)
+		}
+		l.doc.Text(trackX+trackWidth+8, l.y+11, pdf.Regular, 8, mutedColor, b.caption)
+		l.y += barRowHeight
+	}
+}
+
+// highlights lists high-value and wide-impact entries, each marked with the color of its impact
+func (l *layout) highlights(highlights []models.ReportHighlight) {
+	if len(highlights) == 0 {
+		l.line(pdf.Regular, 10, mutedColor, "No high-value or department-wide entries in this period.")
+		return
+	}
+
+	const indent = 12.0
+	for _, h := range highlights {
+		meta := []string{formatDate(h.Date), humanize(string(h.Type))}
+		if h.ProjectName != "" {
+			meta = append(meta, h.ProjectName)
+		}
+		meta = append(meta,
+			humanize(string(h.ValueRating))+" value",
+			humanize(string(h.ImpactLevel))+" impact",
+			formatHours(h.Minutes))
+
+		description := pdf.Wrap(pdf.Regular, 9, contentWidth-indent, stripMarkup(h.Description))
+		if len(description) > maxDescriptionLines {
+			description = description[:maxDescriptionLines]
+			last := description[maxDescriptionLines-1] + " …"
+			description[maxDescriptionLines-1] = pdf.Truncate(pdf.Regular, 9, contentWidth-indent, last)
+		}
+
+		height := 15 + 13 + float64(len(description))*12.6
+		l.need(height)
+		l.doc.Rect(margin, l.y+2, 3, height-6, impactColors[string(h.ImpactLevel)])
+		l.doc.Text(margin+indent, l.y+11, pdf.Bold, 10, textColor, pdf.Truncate(pdf.Bold, 10, contentWidth-indent, h.Title))
+		l.y += 15
+		l.doc.Text(margin+indent, l.y+9, pdf.Regular, 8, mutedColor,
+			pdf.Truncate(pdf.Regular, 8, contentWidth-indent, strings.Join(meta, " · ")))
+		l.y += 13
+		for _, text := range description {
+			l.doc.Text(margin+indent, l.y+9, pdf.Regular, 9, textColor, text)
+			l.y += 12.6
+		}
+		l.y += 6
+	}
+}
+
+// ratingBars turns a value rating or impact level breakdown into bars by entry count
+func ratingBars(items []models.ReportBreakdown, colors map[string]pdf.Color, totalEntries int) []bar {
+	bars := make([]bar, len(items))
+	for i, item := range items {
+		bars[i] = bar{
+			label:   humanize(item.Label),
+			value:   float64(item.Entries),
+			caption: fmt.Sprintf("%d %s · %s", item.Entries, plural(item.Entries, "entry", "entries"), percent(item.Entries, totalEntries)),
+			color:   colors[item.Label],
+		}
+	}
+	return bars
+}
+
+// humanize turns an enum value such as code_review into "Code review"
+func humanize(value string) string {
+	value = strings.ReplaceAll(value, "_", " ")
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+// stripMarkup drops the Markdown emphasis and code markers of AI-written text
+func stripMarkup(s string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
+}
+
+// formatDate formats a YYYY-MM-DD date as "Mar 10, 2025"
+func formatDate(date string) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// formatHours formats minutes as hours with one decimal
+func formatHours(minutes int) string {
+	return strconv.FormatFloat(float64(minutes)/60, 'f', 1, 64) + " h"
+}
+
+// percent formats part as a whole-number percentage of total
+func percent(part, total int) string {
+	if total <= 0 {
+		return "0%"
+	}
+	return strconv.Itoa((part*100+total/2)/total) + "%"
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
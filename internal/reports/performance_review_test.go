@@ -0,0 +1,126 @@
+package reports
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageContents returns the decompressed content streams of a rendered PDF
+func pageContents(t *testing.T, out []byte) []string {
+	t.Helper()
+	var contents []string
+	for _, stream := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(out, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(stream[1]))
+		require.NoError(t, err)
+		content, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	return contents
+}
+
+func TestRenderPerformanceReview(t *testing.T) {
+	review := &models.PerformanceReview{
+		UserName:     "Ana Souza",
+		Timezone:     "America/Sao_Paulo",
+		PeriodStart:  "2025-01-01",
+		PeriodEnd:    "2025-06-30",
+		GeneratedAt:  time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC),
+		TotalEntries: 40,
+		TotalMinutes: 6000,
+		Activities: []models.ReportBreakdown{
+			{Label: "development", Entries: 25, Minutes: 4500},
+			{Label: "code_review", Entries: 15, Minutes: 1500},
+		},
+		ValueRatings: []models.ReportBreakdown{
+			{Label: "critical", Entries: 4},
+			{Label: "high", Entries: 16},
+			{Label: "medium", Entries: 20},
+		},
+		ImpactLevels: []models.ReportBreakdown{
+			{Label: "company", Entries: 2},
+			{Label: "department", Entries: 8},
+			{Label: "team", Entries: 30},
+		},
+		TopProjects: []models.ReportProject{
+			{Name: "Platform", Color: "#3498db", Entries: 30, Minutes: 4800, Percentage: 80},
+		},
+		Narrative: &models.ReportNarrative{
+			Title:       "A strong half",
+			Content:     "## Delivery\n- Shipped the **billing** migration\n\nLed the incident response.",
+			PeriodStart: "2025-01-01",
+			PeriodEnd:   "2025-06-30",
+			GeneratedAt: time.Date(2025, 6, 30, 18, 0, 0, 0, time.UTC),
+		},
+	}
+	for i := range 30 {
+		review.Highlights = append(review.Highlights, models.ReportHighlight{
+			Date:        "2025-03-10",
+			Title:       fmt.Sprintf("Highlight %d", i+1),
+			Description: strings.Repeat("Reduced p99 latency of the checkout API. ", 12),
+			Type:        models.ActivityDevelopment,
+			ProjectName: "Platform",
+			ValueRating: models.ValueCritical,
+			ImpactLevel: models.ImpactCompany,
+			Minutes:     90,
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderPerformanceReview(&buf, review))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	contents := pageContents(t, buf.Bytes())
+	require.Greater(t, len(contents), 1, "long reports flow onto more pages")
+	all := strings.Join(contents, "")
+
+	for _, text := range []string{
+		"(Performance review)",
+		"(Ana Souza \xb7 Jan 1, 2025 \x96 Jun 30, 2025)",
+		"(100.0 h)", "(40)", "(20)", "(10)", // summary cards
+		"(Shipped the billing migration)",
+		"(Code review)",
+		"(25.0 h \xb7 25%)",
+		"(80.0 h \xb7 30 entries \xb7 80%)",
+		"(Highlight 30)",
+		"(Mar 10, 2025 \xb7 Development \xb7 Platform \xb7 Critical value \xb7 Company impact \xb7 1.5 h)",
+	} {
+		assert.Contains(t, all, text)
+	}
+	assert.Contains(t, contents[len(contents)-1], fmt.Sprintf("page %d)", len(contents)), "pages are numbered")
+	assert.NotContains(t, all, "**", "markdown markers are dropped")
+}
+
+func TestRenderPerformanceReview_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderPerformanceReview(&buf, &models.PerformanceReview{
+		PeriodStart: "2025-01-01",
+		PeriodEnd:   "2025-01-31",
+		Timezone:    "UTC",
+	}))
+
+	all := strings.Join(pageContents(t, buf.Bytes()), "")
+	assert.Contains(t, all, "(No AI narrative has been generated")
+	assert.Contains(t, all, "(No entries in this period.)")
+	assert.Contains(t, all, "(No high-value or department-wide entries in this period.)")
+}
+
+func TestFormatting(t *testing.T) {
+	assert.Equal(t, "Code review", humanize("code_review"))
+	assert.Equal(t, "", humanize(""))
+	assert.Equal(t, "33%", percent(1, 3))
+	assert.Equal(t, "0%", percent(1, 0))
+	assert.Equal(t, "1.5 h", formatHours(90))
+	assert.Equal(t, "Mar 10, 2025", formatDate("2025-03-10"))
+	assert.Equal(t, "soon", formatDate("soon"))
+}
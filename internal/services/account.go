@@ -0,0 +1,400 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// Files of an account archive
+const (
+	accountManifestFile   = "manifest.json"
+	accountProfileFile    = "profile.json"
+	accountProjectsFile   = "projects.json"
+	accountTagsFile       = "tags.json"
+	accountLogEntriesFile = "log_entries.json"
+	accountInsightsFile   = "insights.json"
+)
+
+// defaultMaxAccountArchiveBytes bounds the size of an uploaded account archive
+const defaultMaxAccountArchiveBytes = 100 * 1024 * 1024
+
+// AccountService exports a whole account as a versioned zip archive and restores such
+// archives into another account, on this instance or another one. It reuses the
+// conversions and validation of the user, project, tag and log entry services, so
+// imported records obey the same rules as ones created through the API.
+type AccountService struct {
+	db              *database.DB
+	logger          *logging.Logger
+	users           *UserService
+	projects        *ProjectService
+	tags            *TagService
+	logEntries      *LogEntryService
+	maxArchiveBytes int64
+}
+
+// NewAccountService creates a new AccountService instance
+func NewAccountService(db *database.DB, logger *logging.Logger, users *UserService, projects *ProjectService, tags *TagService, logEntries *LogEntryService) *AccountService {
+	return &AccountService{
+		db:              db,
+		logger:          logger.WithComponent("account_service"),
+		users:           users,
+		projects:        projects,
+		tags:            tags,
+		logEntries:      logEntries,
+		maxArchiveBytes: defaultMaxAccountArchiveBytes,
+	}
+}
+
+// WithMaxArchiveBytes sets the largest archive accepted by ImportAccount; non-positive
+// values keep the default
+func (s *AccountService) WithMaxArchiveBytes(maxBytes int64) *AccountService {
+	if maxBytes > 0 {
+		s.maxArchiveBytes = maxBytes
+	}
+	return s
+}
+
+// MaxArchiveBytes returns the largest archive accepted by ImportAccount
+func (s *AccountService) MaxArchiveBytes() int64 {
+	return s.maxArchiveBytes
+}
+
+// ExportAccount writes the user's whole account to w as a zip archive: the profile
+// and preferences, projects, tags, log entries and generated insights as JSON, plus
+// CSV copies of the projects, tags and log entries. Everything is read in a single
+// transaction, so the archive is a consistent snapshot. Attachments are not included.
+func (s *AccountService) ExportAccount(ctx context.Context, userID string, w io.Writer) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ExportAccount", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Exporting account", "user_id", userID)
+
+	manifest := models.AccountArchiveManifest{
+		Format:  models.AccountArchiveFormat,
+		Version: models.AccountArchiveVersion,
+		UserID:  userUUID,
+	}
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		manifest.ExportedAt = time.Now().UTC()
+		manifest.Counts = map[string]int{}
+		archive := zip.NewWriter(w)
+
+		profile := s.users.sqlcToUserProfile(user)
+		if err := writeArchiveJSON(archive, accountProfileFile, models.AccountArchiveProfile{
+			Email:       profile.Email,
+			FirstName:   profile.FirstName,
+			LastName:    profile.LastName,
+			Timezone:    profile.Timezone,
+			Preferences: profile.Preferences,
+			CreatedAt:   profile.CreatedAt,
+		}); err != nil {
+			return err
+		}
+
+		if manifest.Counts["projects"], err = s.exportProjects(ctx, qtx, userUUID, archive); err != nil {
+			return err
+		}
+		if manifest.Counts["tags"], err = s.exportTags(ctx, qtx, userUUID, archive); err != nil {
+			return err
+		}
+		if manifest.Counts["log_entries"], err = s.exportLogEntries(ctx, qtx, userUUID, archive); err != nil {
+			return err
+		}
+		if manifest.Counts["insights"], err = s.exportInsights(ctx, qtx, userUUID, archive); err != nil {
+			return err
+		}
+
+		location, err := time.LoadLocation(profile.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+		if err := s.exportCSVCopies(ctx, qtx, userUUID, location, archive); err != nil {
+			return err
+		}
+
+		if err := writeArchiveJSON(archive, accountManifestFile, manifest); err != nil {
+			return err
+		}
+		if err := archive.Close(); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to export account", "user_id", userID)
+		return err
+	}
+
+	s.logger.Info("Successfully exported account", "user_id", userID,
+		"projects", manifest.Counts["projects"],
+		"tags", manifest.Counts["tags"],
+		"log_entries", manifest.Counts["log_entries"],
+		"insights", manifest.Counts["insights"])
+	return nil
+}
+
+// exportProjects writes the projects the user owns, archived ones included
+func (s *AccountService) exportProjects(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, archive *zip.Writer) (int, error) {
+	rows, err := qtx.GetProjectsByUser(ctx, store.GetProjectsByUserParams{
+		CreatedBy:       userUUID,
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	budgetRows, err := qtx.GetProjectActivityBudgetsByUser(ctx, userUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project budgets: %w", err)
+	}
+	budgets := make(map[uuid.UUID][]store.ProjectActivityBudget)
+	for _, budget := range budgetRows {
+		budgets[budget.ProjectID] = append(budgets[budget.ProjectID], budget)
+	}
+
+	projects := make([]models.AccountArchiveProject, len(rows))
+	for i, row := range rows {
+		project := s.projects.sqlcToModel(row)
+		projects[i] = models.AccountArchiveProject{
+			ID:              project.ID,
+			ParentID:        project.ParentID,
+			Name:            project.Name,
+			Description:     project.Description,
+			Color:           project.Color,
+			Status:          project.Status,
+			StartDate:       pgDateToString(row.StartDate),
+			EndDate:         pgDateToString(row.EndDate),
+			IsDefault:       project.IsDefault,
+			EstimatedHours:  project.EstimatedHours,
+			ActivityBudgets: activityBudgetsToModel(budgets[row.ID]),
+			Defaults:        project.Defaults,
+			ArchivedAt:      project.ArchivedAt,
+			CreatedAt:       project.CreatedAt,
+		}
+	}
+
+	return len(projects), writeArchiveJSON(archive, accountProjectsFile, projects)
+}
+
+// exportTags writes the user's tags with their aliases, and the shared tags on the user's entries
+func (s *AccountService) exportTags(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, archive *zip.Writer) (int, error) {
+	rows, err := qtx.GetAccountTags(ctx, uuidToPgUUID(&userUUID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	aliasRows, err := qtx.GetAccountTagAliases(ctx, userUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tag aliases: %w", err)
+	}
+	aliases := make(map[uuid.UUID][]string)
+	for _, alias := range aliasRows {
+		aliases[alias.TagID] = append(aliases[alias.TagID], alias.Alias)
+	}
+
+	tags := make([]models.AccountArchiveTag, len(rows))
+	for i, row := range rows {
+		tag := s.tags.sqlcToModel(row)
+		tags[i] = models.AccountArchiveTag{
+			ID:          tag.ID,
+			ParentID:    tag.ParentID,
+			Name:        tag.Name,
+			Color:       tag.Color,
+			Description: tag.Description,
+			Shared:      tag.Shared,
+			Aliases:     aliases[tag.ID],
+			CreatedAt:   tag.CreatedAt,
+		}
+	}
+
+	return len(tags), writeArchiveJSON(archive, accountTagsFile, tags)
+}
+
+// exportLogEntries streams the user's log entries, oldest first, as a JSON array
+func (s *AccountService) exportLogEntries(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, archive *zip.Writer) (int, error) {
+	file, err := archive.Create(accountLogEntriesFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add %s to archive: %w", accountLogEntriesFile, err)
+	}
+
+	entries := &jsonArrayWriter{w: file}
+	if err := qtx.StreamExportAccountLogEntries(ctx, userUUID, func(row store.ExportAccountLogEntriesRow) error {
+		var links []models.LogEntryLink
+		if err := json.Unmarshal(row.Links, &links); err != nil {
+			return fmt.Errorf("failed to read links of log entry %s: %w", row.ID, err)
+		}
+
+		return entries.write(models.AccountArchiveLogEntry{
+			ID:          row.ID,
+			ProjectID:   pgUUIDToUUID(row.ProjectID),
+			Title:       row.Title,
+			Description: pgTextToString(row.Description),
+			Type:        models.ActivityType(row.Type),
+			StartTime:   pgTimestamptzToTime(row.StartTime),
+			EndTime:     pgTimestamptzToTime(row.EndTime),
+			ValueRating: models.ValueRating(row.ValueRating),
+			ImpactLevel: models.ImpactLevel(row.ImpactLevel),
+			Tags:        row.Tags,
+			Links:       links,
+			CreatedAt:   pgTimestamptzToTime(row.CreatedAt),
+			UpdatedAt:   pgTimestamptzToTime(row.UpdatedAt),
+		})
+	}); err != nil {
+		return 0, fmt.Errorf("failed to export log entries: %w", err)
+	}
+
+	return entries.count, entries.close()
+}
+
+// exportInsights writes every generated insight of the user, archived ones included
+func (s *AccountService) exportInsights(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, archive *zip.Writer) (int, error) {
+	rows, err := qtx.GetAccountInsights(ctx, userUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get insights: %w", err)
+	}
+
+	insights := make([]models.AccountArchiveInsight, len(rows))
+	for i, row := range rows {
+		insights[i] = models.AccountArchiveInsight{
+			ID:              row.ID,
+			ReportType:      models.ReportType(row.ReportType),
+			PeriodStart:     row.PeriodStart.Time.Format(time.DateOnly),
+			PeriodEnd:       row.PeriodEnd.Time.Format(time.DateOnly),
+			Title:           row.Title,
+			Content:         row.Content,
+			Summary:         pgTextToString(row.Summary),
+			Metadata:        json.RawMessage(row.Metadata),
+			GenerationModel: pgTextToString(row.GenerationModel),
+			QualityScore:    pgNumericToFloat64(row.QualityScore),
+			Status:          models.InsightStatus(pgTextToStringRequired(row.Status)),
+			CreatedAt:       pgTimestamptzToTime(row.CreatedAt),
+		}
+		if len(row.Metadata) == 0 {
+			insights[i].Metadata = nil
+		}
+	}
+
+	return len(insights), writeArchiveJSON(archive, accountInsightsFile, insights)
+}
+
+// exportCSVCopies adds the CSV exports of projects, tag usage and log entries, with
+// times in loc, for reading the archive in a spreadsheet
+func (s *AccountService) exportCSVCopies(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, loc *time.Location, archive *zip.Writer) error {
+	csvFile := func(name string, columns []string, stream func(export *exportWriter) error) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+		export, err := newExportWriter(file, models.ExportCSV, columns)
+		if err != nil {
+			return err
+		}
+		if err := stream(export); err != nil {
+			return fmt.Errorf("failed to export %s: %w", name, err)
+		}
+		return export.flush()
+	}
+
+	if err := csvFile("projects.csv", models.ProjectExportColumns, func(export *exportWriter) error {
+		return qtx.StreamExportProjects(ctx, userUUID, func(row store.ExportProjectsRow) error {
+			return export.write(projectExportToModel(row, loc))
+		})
+	}); err != nil {
+		return err
+	}
+
+	if err := csvFile("tags.csv", models.TagUsageExportColumns, func(export *exportWriter) error {
+		return qtx.StreamExportTagUsage(ctx, userUUID, func(row store.ExportTagUsageRow) error {
+			return export.write(tagUsageExportToModel(row, loc))
+		})
+	}); err != nil {
+		return err
+	}
+
+	return csvFile("log_entries.csv", models.LogEntryExportColumns, func(export *exportWriter) error {
+		return qtx.StreamExportLogEntries(ctx, store.ExportLogEntriesParams{UserID: userUUID}, func(row store.ExportLogEntriesRow) error {
+			return export.write(logEntryExportToModel(row, loc))
+		})
+	})
+}
+
+// writeArchiveJSON adds an indented JSON file to an archive
+func writeArchiveJSON(archive *zip.Writer, name string, v any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// jsonArrayWriter writes a JSON array one element per line, so long arrays never
+// have to be held in memory
+type jsonArrayWriter struct {
+	w     io.Writer
+	count int
+	buf   bytes.Buffer
+}
+
+// write appends one element
+func (a *jsonArrayWriter) write(v any) error {
+	a.buf.Reset()
+	if a.count == 0 {
+		a.buf.WriteString("[\n")
+	} else {
+		a.buf.WriteString(",\n")
+	}
+
+	encoder := json.NewEncoder(&a.buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode array element: %w", err)
+	}
+	a.buf.Truncate(a.buf.Len() - 1) // Encode ends every value with a newline
+
+	if _, err := a.w.Write(a.buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write array element: %w", err)
+	}
+	a.count++
+	return nil
+}
+
+// close ends the array
+func (a *jsonArrayWriter) close() error {
+	end := "\n]\n"
+	if a.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(a.w, end); err != nil {
+		return fmt.Errorf("failed to write array end: %w", err)
+	}
+	return nil
+}
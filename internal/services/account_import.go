@@ -0,0 +1,741 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// maxAccountArchiveFileBytes bounds the uncompressed size of each file in an archive
+	maxAccountArchiveFileBytes = 512 * 1024 * 1024
+	// maxAccountImportWarnings bounds the warnings listed in an import report
+	maxAccountImportWarnings = 100
+	// maxLogEntryDuration is the longest log entry the database accepts
+	maxLogEntryDuration = 24 * time.Hour
+)
+
+// errAccountImportDryRun rolls back the transaction of a dry-run import
+var errAccountImportDryRun = errors.New("account import dry run")
+
+// accountArchive is the decoded content of an account archive
+type accountArchive struct {
+	manifest   models.AccountArchiveManifest
+	profile    *models.AccountArchiveProfile
+	projects   []models.AccountArchiveProject
+	tags       []models.AccountArchiveTag
+	logEntries []models.AccountArchiveLogEntry
+	insights   []models.AccountArchiveInsight
+}
+
+// ImportAccount restores an account archive into the user's account. Records get new
+// IDs, and references between them are remapped; records already in the account are
+// matched instead of duplicated, so importing the same archive twice is harmless.
+// Invalid records are skipped with a warning. The import runs in one transaction; a
+// dry run reports what it would do and then rolls the transaction back.
+func (s *AccountService) ImportAccount(ctx context.Context, userID string, r io.ReaderAt, size int64, dryRun bool) (*models.AccountImportReport, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ImportAccount", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if size > s.maxArchiveBytes {
+		return nil, fmt.Errorf("archive is larger than the %d byte limit", s.maxArchiveBytes)
+	}
+
+	archive, err := readAccountArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Importing account", "user_id", userID, "dry_run", dryRun,
+		"version", archive.manifest.Version,
+		"projects", len(archive.projects),
+		"tags", len(archive.tags),
+		"log_entries", len(archive.logEntries),
+		"insights", len(archive.insights))
+
+	var report *models.AccountImportReport
+	err = s.db.Write(ctx, func(qtx *store.Queries) error {
+		// Rebuilt on every attempt: the transaction is retried on serialization errors
+		importer := &accountImporter{
+			service:  s,
+			qtx:      qtx,
+			userUUID: userUUID,
+			report: &models.AccountImportReport{
+				DryRun:     dryRun,
+				Version:    archive.manifest.Version,
+				ExportedAt: archive.manifest.ExportedAt,
+				ProjectIDs: map[uuid.UUID]uuid.UUID{},
+				TagIDs:     map[uuid.UUID]uuid.UUID{},
+				Warnings:   []string{},
+			},
+		}
+		report = importer.report
+
+		if err := importer.run(ctx, archive); err != nil {
+			return err
+		}
+		if dryRun {
+			return errAccountImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAccountImportDryRun) {
+		s.logger.LogError(ctx, err, "Failed to import account", "user_id", userID, "dry_run", dryRun)
+		return nil, fmt.Errorf("failed to import account: %w", err)
+	}
+
+	if dryRun {
+		// The IDs were assigned in the rolled back transaction
+		report.ProjectIDs = nil
+		report.TagIDs = nil
+	}
+
+	s.logger.Info("Successfully imported account", "user_id", userID, "dry_run", dryRun,
+		"projects_created", report.Projects.Created,
+		"tags_created", report.Tags.Created,
+		"log_entries_created", report.LogEntries.Created,
+		"insights_created", report.Insights.Created,
+		"warnings", len(report.Warnings))
+	return report, nil
+}
+
+// readAccountArchive reads and checks an account archive. Only the manifest is
+// required; a missing file imports nothing of its kind.
+func readAccountArchive(r io.ReaderAt, size int64) (*accountArchive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	archive := &accountArchive{}
+	manifest, ok := files[accountManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("invalid archive: %s is missing", accountManifestFile)
+	}
+	if err := readArchiveJSON(manifest, &archive.manifest); err != nil {
+		return nil, err
+	}
+	if archive.manifest.Format != models.AccountArchiveFormat {
+		return nil, fmt.Errorf("invalid archive: not an EngLog account archive")
+	}
+	if archive.manifest.Version < 1 || archive.manifest.Version > models.AccountArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d: this instance reads versions 1 to %d",
+			archive.manifest.Version, models.AccountArchiveVersion)
+	}
+
+	for name, target := range map[string]any{
+		accountProfileFile:    &archive.profile,
+		accountProjectsFile:   &archive.projects,
+		accountTagsFile:       &archive.tags,
+		accountLogEntriesFile: &archive.logEntries,
+		accountInsightsFile:   &archive.insights,
+	} {
+		if file, ok := files[name]; ok {
+			if err := readArchiveJSON(file, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return archive, nil
+}
+
+// readArchiveJSON decodes a JSON file of an archive into v
+func readArchiveJSON(file *zip.File, v any) error {
+	if file.UncompressedSize64 > maxAccountArchiveFileBytes {
+		return fmt.Errorf("invalid archive: %s is too large", file.Name)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid archive: failed to open %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	// The declared size cannot be trusted, so the limit also applies while reading
+	limited := &io.LimitedReader{R: rc, N: maxAccountArchiveFileBytes + 1}
+	if err := json.NewDecoder(limited).Decode(v); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("invalid archive: %s is too large", file.Name)
+		}
+		return fmt.Errorf("invalid archive: failed to read %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// accountImporter applies an account archive inside one transaction
+type accountImporter struct {
+	service  *AccountService
+	qtx      *store.Queries
+	userUUID uuid.UUID
+	report   *models.AccountImportReport
+}
+
+// run imports the archive: the profile first, then tags and projects, which the
+// log entries refer to, and finally insights
+func (im *accountImporter) run(ctx context.Context, archive *accountArchive) error {
+	if archive.profile != nil {
+		if err := im.importProfile(ctx, archive.profile); err != nil {
+			return err
+		}
+	}
+	if err := im.importTags(ctx, archive.tags); err != nil {
+		return err
+	}
+	if err := im.importProjects(ctx, archive.projects); err != nil {
+		return err
+	}
+	if err := im.importLogEntries(ctx, archive.logEntries); err != nil {
+		return err
+	}
+	return im.importInsights(ctx, archive.insights)
+}
+
+// warn adds a warning to the report, up to maxAccountImportWarnings
+func (im *accountImporter) warn(format string, args ...any) {
+	switch n := len(im.report.Warnings); {
+	case n < maxAccountImportWarnings:
+		im.report.Warnings = append(im.report.Warnings, fmt.Sprintf(format, args...))
+	case n == maxAccountImportWarnings:
+		im.report.Warnings = append(im.report.Warnings, "further warnings omitted")
+	}
+}
+
+// importProfile restores the name and timezone, and merges the archived preferences
+// over the current ones. The email address and password are never imported.
+func (im *accountImporter) importProfile(ctx context.Context, profile *models.AccountArchiveProfile) error {
+	user, err := im.qtx.GetUserByID(ctx, im.userUUID)
+	if err != nil {
+		if database.NoRows(err) {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	preferences, _ := im.service.users.bytesToPreferences(user.Preferences)
+	maps.Copy(preferences, profile.Preferences)
+
+	req := &models.UserProfileRequest{
+		FirstName:   profile.FirstName,
+		LastName:    profile.LastName,
+		Timezone:    profile.Timezone,
+		Preferences: preferences,
+	}
+	if err := im.service.users.validateProfileRequest(req); err != nil {
+		im.warn("profile skipped: %v", err)
+		return nil
+	}
+
+	preferencesBytes, err := im.service.users.preferencesToBytes(req.Preferences)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+	if _, err := im.qtx.UpdateUserProfile(ctx, store.UpdateUserProfileParams{
+		ID:          im.userUUID,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Timezone:    stringToPgTextRequired(req.Timezone),
+		Preferences: preferencesBytes,
+	}); err != nil {
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+
+	im.report.ProfileUpdated = true
+	return nil
+}
+
+// importTags matches archived tags to the user's tags by name or alias and creates
+// the others as the user's own tags, parents first. Shared tags of the source
+// instance become own tags too.
+func (im *accountImporter) importTags(ctx context.Context, tags []models.AccountArchiveTag) error {
+	im.report.Tags.Total = len(tags)
+	owner := uuidToPgUUID(&im.userUUID)
+
+	ordered := parentsFirst(tags,
+		func(t models.AccountArchiveTag) uuid.UUID { return t.ID },
+		func(t models.AccountArchiveTag) *uuid.UUID { return t.ParentID })
+	for _, tag := range ordered {
+		existing, err := optionalRow(im.qtx.GetTagByName(ctx, store.GetTagByNameParams{UserID: owner, Name: tag.Name}))
+		if err == nil && existing == nil {
+			existing, err = optionalRow(im.qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{UserID: im.userUUID, Lower: tag.Name}))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up tag %q: %w", tag.Name, err)
+		}
+		if existing != nil {
+			im.report.TagIDs[tag.ID] = existing.ID
+			im.report.Tags.Matched++
+			if err := im.importTagAliases(ctx, existing.ID, tag.Aliases); err != nil {
+				return err
+			}
+			continue
+		}
+
+		req := &models.TagRequest{Name: tag.Name, Color: tag.Color, Description: tag.Description}
+		if err := im.service.tags.validateTagRequest(req); err != nil {
+			im.report.Tags.Skipped++
+			im.warn("tag %q skipped: %v", tag.Name, err)
+			continue
+		}
+
+		parentID := im.mappedParent(tag.ParentID, im.report.TagIDs, "tag", tag.Name)
+		if err := validateTagParent(ctx, im.qtx, &im.userUUID, nil, parentID); err != nil {
+			im.warn("tag %q imported without its parent: %v", tag.Name, err)
+			parentID = nil
+		}
+
+		created, err := im.qtx.CreateTag(ctx, store.CreateTagParams{
+			Name:        req.Name,
+			Color:       stringToPgText(&req.Color),
+			Description: stringToPgText(req.Description),
+			UserID:      owner,
+			ParentID:    uuidToPgUUID(parentID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create tag %q: %w", tag.Name, err)
+		}
+		im.report.TagIDs[tag.ID] = created.ID
+		im.report.Tags.Created++
+
+		if err := im.importTagAliases(ctx, created.ID, tag.Aliases); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importTagAliases adds the archived aliases of a tag that do not clash with the
+// user's tag names or other aliases
+func (im *accountImporter) importTagAliases(ctx context.Context, tagID uuid.UUID, aliases []string) error {
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || len(alias) > maxTagAliasLength {
+			im.warn("tag alias %q skipped: invalid alias", alias)
+			continue
+		}
+
+		conflicts, err := im.qtx.GetTagsByNameInsensitive(ctx, store.GetTagsByNameInsensitiveParams{
+			UserID: uuidToPgUUID(&im.userUUID),
+			Lower:  alias,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check tag names: %w", err)
+		}
+		if len(conflicts) > 0 {
+			if conflicts[0].ID != tagID {
+				im.warn("tag alias %q skipped: it is the name of tag %q", alias, conflicts[0].Name)
+			}
+			continue
+		}
+
+		existing, err := optionalRow(im.qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{UserID: im.userUUID, Lower: alias}))
+		if err != nil {
+			return fmt.Errorf("failed to check tag aliases: %w", err)
+		}
+		if existing != nil {
+			if existing.ID != tagID {
+				im.warn("tag alias %q skipped: it is already an alias of tag %q", alias, existing.Name)
+			}
+			continue
+		}
+
+		if _, err := im.qtx.CreateTagAlias(ctx, store.CreateTagAliasParams{
+			TagID:  tagID,
+			UserID: im.userUUID,
+			Alias:  alias,
+		}); err != nil {
+			return fmt.Errorf("failed to create tag alias %q: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// importProjects matches archived projects to the user's projects with the same name
+// and parent, and creates the others, parents first. Projects are archived only once
+// all of them exist, because nothing can be created below an archived project.
+func (im *accountImporter) importProjects(ctx context.Context, projects []models.AccountArchiveProject) error {
+	im.report.Projects.Total = len(projects)
+
+	existing, err := im.qtx.GetProjectsByUser(ctx, store.GetProjectsByUserParams{
+		CreatedBy:       im.userUUID,
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+	byName := make(map[string]uuid.UUID, len(existing))
+	hasDefault := false
+	for _, project := range existing {
+		byName[projectMatchKey(project.Name, pgUUIDToUUID(project.ParentID))] = project.ID
+		hasDefault = hasDefault || pgBoolToBool(project.IsDefault)
+	}
+
+	var archived []models.AccountArchiveProject
+	ordered := parentsFirst(projects,
+		func(p models.AccountArchiveProject) uuid.UUID { return p.ID },
+		func(p models.AccountArchiveProject) *uuid.UUID { return p.ParentID })
+	for _, project := range ordered {
+		parentID := im.mappedParent(project.ParentID, im.report.ProjectIDs, "project", project.Name)
+
+		key := projectMatchKey(project.Name, parentID)
+		if id, ok := byName[key]; ok {
+			im.report.ProjectIDs[project.ID] = id
+			im.report.Projects.Matched++
+			continue
+		}
+
+		req := &models.ProjectRequest{
+			Name:            project.Name,
+			Description:     project.Description,
+			Color:           project.Color,
+			Status:          project.Status,
+			IsDefault:       project.IsDefault,
+			EstimatedHours:  project.EstimatedHours,
+			ActivityBudgets: project.ActivityBudgets,
+			ParentID:        parentID,
+			Defaults:        project.Defaults,
+		}
+		startDate, endDate, err := parseExportDates(valueOrEmpty(project.StartDate), valueOrEmpty(project.EndDate))
+		if err == nil {
+			req.StartDate, req.EndDate = startDate, endDate
+			err = im.service.projects.validateProjectRequest(req)
+		}
+		if err != nil {
+			im.report.Projects.Skipped++
+			im.warn("project %q skipped: %v", project.Name, err)
+			continue
+		}
+
+		if err := validateProjectParent(ctx, im.qtx, im.userUUID, nil, req.ParentID); err != nil {
+			im.warn("project %q imported without its parent: %v", project.Name, err)
+			req.ParentID = nil
+			key = projectMatchKey(project.Name, nil)
+		}
+		if req.IsDefault && hasDefault {
+			im.warn("project %q imported as a regular project: the account already has a default project", project.Name)
+			req.IsDefault = false
+		}
+
+		defaults := projectDefaultsParams(req.Defaults)
+		created, err := im.qtx.CreateProject(ctx, store.CreateProjectParams{
+			Name:               req.Name,
+			Description:        stringToPgText(req.Description),
+			Color:              stringToPgText(&req.Color),
+			Status:             stringToPgText((*string)(&req.Status)),
+			StartDate:          timeToPgDate(req.StartDate),
+			EndDate:            timeToPgDate(req.EndDate),
+			CreatedBy:          im.userUUID,
+			IsDefault:          boolToPgBool(req.IsDefault),
+			EstimatedHours:     float64ToPgFloat8(req.EstimatedHours),
+			ParentID:           uuidToPgUUID(req.ParentID),
+			DefaultType:        defaults.DefaultType,
+			DefaultTags:        defaults.DefaultTags,
+			DefaultValueRating: defaults.DefaultValueRating,
+			DefaultImpactLevel: defaults.DefaultImpactLevel,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create project %q: %w", project.Name, err)
+		}
+		if err := im.service.projects.saveActivityBudgets(ctx, im.qtx, created.ID, req.ActivityBudgets); err != nil {
+			return err
+		}
+
+		im.report.ProjectIDs[project.ID] = created.ID
+		im.report.Projects.Created++
+		byName[key] = created.ID
+		hasDefault = hasDefault || req.IsDefault
+		if project.ArchivedAt != nil {
+			archived = append(archived, project)
+		}
+	}
+
+	for _, project := range archived {
+		if _, err := im.qtx.SetProjectSubtreeArchived(ctx, store.SetProjectSubtreeArchivedParams{
+			ID:         im.report.ProjectIDs[project.ID],
+			CreatedBy:  im.userUUID,
+			ArchivedAt: timeToPgTimestamptz(*project.ArchivedAt),
+		}); err != nil {
+			return fmt.Errorf("failed to archive project %q: %w", project.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// importLogEntries creates the archived log entries that the user has not logged
+// already, on the remapped projects. Tags are referenced by name, so they resolve
+// to the tags imported before, and every entry gets a creation revision.
+func (im *accountImporter) importLogEntries(ctx context.Context, entries []models.AccountArchiveLogEntry) error {
+	im.report.LogEntries.Total = len(entries)
+
+	for _, entry := range entries {
+		req := &models.LogEntryRequest{
+			Title:       entry.Title,
+			Description: entry.Description,
+			Type:        entry.Type,
+			StartTime:   entry.StartTime,
+			EndTime:     entry.EndTime,
+			ValueRating: entry.ValueRating,
+			ImpactLevel: entry.ImpactLevel,
+			Links:       entry.Links,
+		}
+		for _, tag := range entry.Tags {
+			if tag = strings.TrimSpace(tag); tag != "" && len(tag) <= 100 {
+				req.Tags = append(req.Tags, tag)
+			} else {
+				im.warn("tag %q dropped from log entry %q: invalid tag name", tag, entry.Title)
+			}
+		}
+
+		if entry.ProjectID != nil {
+			if projectID, ok := im.report.ProjectIDs[*entry.ProjectID]; ok {
+				req.ProjectID = &projectID
+			} else {
+				im.warn("log entry %q imported without its project, which is not in the archive", entry.Title)
+			}
+		}
+
+		err := im.service.logEntries.validateLogEntryRequest(req)
+		if err == nil && req.EndTime.Sub(req.StartTime) > maxLogEntryDuration {
+			err = fmt.Errorf("log entries can last at most 24 hours")
+		}
+		if err != nil {
+			im.report.LogEntries.Skipped++
+			im.warn("log entry %q skipped: %v", entry.Title, err)
+			continue
+		}
+
+		exists, err := im.qtx.LogEntryExists(ctx, store.LogEntryExistsParams{
+			UserID:    im.userUUID,
+			Title:     req.Title,
+			StartTime: timeToPgTimestamptz(req.StartTime),
+			EndTime:   timeToPgTimestamptz(req.EndTime),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check log entry %q: %w", entry.Title, err)
+		}
+		if exists {
+			im.report.LogEntries.Matched++
+			continue
+		}
+
+		if _, err := im.service.logEntries.createLogEntryTx(ctx, im.qtx, im.userUUID, req); err != nil {
+			return err
+		}
+		im.report.LogEntries.Created++
+	}
+
+	return nil
+}
+
+// importInsights creates the archived insights the user does not have yet. Insights
+// that were archived or superseded in the source account are imported as archived.
+func (im *accountImporter) importInsights(ctx context.Context, insights []models.AccountArchiveInsight) error {
+	im.report.Insights.Total = len(insights)
+
+	existing, err := im.qtx.GetAccountInsights(ctx, im.userUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get insights: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, insight := range existing {
+		seen[insightMatchKey(insight.ReportType, insight.PeriodStart.Time.Format(time.DateOnly), insight.PeriodEnd.Time.Format(time.DateOnly), insight.Title)] = true
+	}
+
+	for _, insight := range insights {
+		key := insightMatchKey(string(insight.ReportType), insight.PeriodStart, insight.PeriodEnd, insight.Title)
+		if seen[key] {
+			im.report.Insights.Matched++
+			continue
+		}
+
+		params, err := insightParams(im.userUUID, insight)
+		if err != nil {
+			im.report.Insights.Skipped++
+			im.warn("insight %q skipped: %v", insight.Title, err)
+			continue
+		}
+
+		created, err := im.qtx.CreateInsight(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create insight %q: %w", insight.Title, err)
+		}
+		if insight.Status != "" && insight.Status != models.InsightActive {
+			if err := im.qtx.ArchiveInsight(ctx, store.ArchiveInsightParams{ID: created.ID, UserID: im.userUUID}); err != nil {
+				return fmt.Errorf("failed to archive insight %q: %w", insight.Title, err)
+			}
+		}
+		seen[key] = true
+		im.report.Insights.Created++
+	}
+
+	return nil
+}
+
+// mappedParent returns the account ID of an archived parent, warning when the parent
+// was not imported
+func (im *accountImporter) mappedParent(parentID *uuid.UUID, ids map[uuid.UUID]uuid.UUID, kind, name string) *uuid.UUID {
+	if parentID == nil {
+		return nil
+	}
+	if mapped, ok := ids[*parentID]; ok {
+		return &mapped
+	}
+	im.warn("%s %q imported without its parent, which was not imported", kind, name)
+	return nil
+}
+
+// insightParams validates an archived insight and converts it to insert parameters
+func insightParams(userUUID uuid.UUID, insight models.AccountArchiveInsight) (store.CreateInsightParams, error) {
+	if !insight.ReportType.IsValid() {
+		return store.CreateInsightParams{}, fmt.Errorf("invalid report type: %s", insight.ReportType)
+	}
+	if strings.TrimSpace(insight.Title) == "" || len(insight.Title) > 200 {
+		return store.CreateInsightParams{}, fmt.Errorf("title must be between 1 and 200 characters")
+	}
+	if insight.Content == "" {
+		return store.CreateInsightParams{}, fmt.Errorf("content is required")
+	}
+	start, end, err := parseExportDates(insight.PeriodStart, insight.PeriodEnd)
+	if err != nil {
+		return store.CreateInsightParams{}, err
+	}
+	if start == nil || end == nil {
+		return store.CreateInsightParams{}, fmt.Errorf("period start and end are required")
+	}
+	if insight.QualityScore != nil && (*insight.QualityScore < 0 || *insight.QualityScore > 1) {
+		return store.CreateInsightParams{}, fmt.Errorf("quality score must be between 0 and 1")
+	}
+
+	metadata := []byte(insight.Metadata)
+	if len(metadata) == 0 {
+		metadata = []byte("{}")
+	}
+	generationModel := insight.GenerationModel
+	if generationModel != nil && len(*generationModel) > 50 {
+		generationModel = nil
+	}
+
+	return store.CreateInsightParams{
+		UserID:          userUUID,
+		ReportType:      string(insight.ReportType),
+		PeriodStart:     timeToPgDate(start),
+		PeriodEnd:       timeToPgDate(end),
+		Title:           insight.Title,
+		Content:         insight.Content,
+		Summary:         stringToPgText(insight.Summary),
+		Metadata:        metadata,
+		GenerationModel: stringToPgText(generationModel),
+		QualityScore:    float64ToPgNumeric(insight.QualityScore),
+	}, nil
+}
+
+// parentsFirst orders records so that every record comes after its parent. Records
+// whose parent is not in the list come first; records in a cycle come last.
+func parentsFirst[T any](items []T, id func(T) uuid.UUID, parent func(T) *uuid.UUID) []T {
+	present := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		present[id(item)] = true
+	}
+
+	ordered := make([]T, 0, len(items))
+	placed := make(map[uuid.UUID]bool, len(items))
+	for len(ordered) < len(items) {
+		progress := false
+		for _, item := range items {
+			if placed[id(item)] {
+				continue
+			}
+			if p := parent(item); p != nil && present[*p] && !placed[*p] {
+				continue
+			}
+			ordered = append(ordered, item)
+			placed[id(item)] = true
+			progress = true
+		}
+		if !progress {
+			for _, item := range items {
+				if !placed[id(item)] {
+					ordered = append(ordered, item)
+					placed[id(item)] = true
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// optionalRow returns nil instead of a no rows error for lookups that may find nothing
+func optionalRow[T any](value T, err error) (*T, error) {
+	if database.NoRows(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// projectMatchKey identifies a project by name below a parent
+func projectMatchKey(name string, parentID *uuid.UUID) string {
+	if parentID == nil {
+		return name
+	}
+	return parentID.String() + "/" + name
+}
+
+// insightMatchKey identifies an insight by type, period and title
+func insightMatchKey(reportType, periodStart, periodEnd, title string) string {
+	return strings.Join([]string{reportType, periodStart, periodEnd, title}, "\x00")
+}
+
+// valueOrEmpty returns the string s points to, or an empty string
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// pgNumericToFloat64 converts pgtype.Numeric to *float64
+func pgNumericToFloat64(n pgtype.Numeric) *float64 {
+	value, err := n.Float64Value()
+	if err != nil || !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
+// float64ToPgNumeric converts *float64 to pgtype.Numeric
+func float64ToPgNumeric(f *float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	if f == nil {
+		return n
+	}
+	if err := n.Scan(strconv.FormatFloat(*f, 'f', -1, 64)); err != nil {
+		return pgtype.Numeric{}
+	}
+	return n
+}
@@ -0,0 +1,197 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountService_RoundTrip exports one account and imports it into another
+func TestAccountService_RoundTrip(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	userService := services.NewUserService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	tagService := services.NewTagService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	accountService := services.NewAccountService(db, testLogger, userService, projectService, tagService, logEntryService)
+
+	ctx := context.Background()
+
+	source, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "account-source@example.com",
+		Password:  "password123",
+		FirstName: "Source",
+		LastName:  "User",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	sourceID := source.ID.String()
+
+	target, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "account-target@example.com",
+		Password:  "password123",
+		FirstName: "Target",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	targetID := target.ID.String()
+
+	parent, err := projectService.CreateProject(ctx, sourceID, &models.ProjectRequest{
+		Name:   "Platform",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+	child, err := projectService.CreateProject(ctx, sourceID, &models.ProjectRequest{
+		Name:     "Billing",
+		Color:    "#2ecc71",
+		Status:   models.ProjectActive,
+		ParentID: &parent.ID,
+	})
+	require.NoError(t, err)
+	_, err = projectService.ArchiveProject(ctx, sourceID, child.ID.String())
+	require.NoError(t, err)
+
+	golang, err := tagService.CreateTag(ctx, sourceID, &models.TagRequest{Name: "golang", Color: "#00add8"})
+	require.NoError(t, err)
+	_, err = tagService.CreateTag(ctx, sourceID, &models.TagRequest{Name: "generics", Color: "#00add8", ParentID: &golang.ID})
+	require.NoError(t, err)
+	_, err = tagService.AddTagAlias(ctx, sourceID, golang.ID.String(), &models.TagAliasRequest{Alias: "go"})
+	require.NoError(t, err)
+
+	start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	_, err = logEntryService.CreateLogEntry(ctx, sourceID, &models.LogEntryRequest{
+		Title:       "Invoice service",
+		Type:        models.ActivityDevelopment,
+		ProjectID:   &child.ID,
+		StartTime:   start,
+		EndTime:     start.Add(90 * time.Minute),
+		ValueRating: models.ValueHigh,
+		ImpactLevel: models.ImpactTeam,
+		Tags:        []string{"generics", "golang"},
+	})
+	require.NoError(t, err)
+	_, err = logEntryService.CreateLogEntry(ctx, sourceID, &models.LogEntryRequest{
+		Title:       "Reading",
+		Type:        models.ActivityLearning,
+		StartTime:   start.Add(24 * time.Hour),
+		EndTime:     start.Add(25 * time.Hour),
+		ValueRating: models.ValueMedium,
+		ImpactLevel: models.ImpactPersonal,
+	})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	require.NoError(t, accountService.ExportAccount(ctx, sourceID, &archive))
+	data := archive.Bytes()
+
+	t.Run("ArchiveLayout", func(t *testing.T) {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+
+		files := map[string]*zip.File{}
+		for _, file := range reader.File {
+			files[file.Name] = file
+		}
+		for _, name := range []string{
+			"manifest.json", "profile.json", "projects.json", "tags.json", "log_entries.json", "insights.json",
+			"projects.csv", "tags.csv", "log_entries.csv",
+		} {
+			assert.Contains(t, files, name)
+		}
+
+		rc, err := files["manifest.json"].Open()
+		require.NoError(t, err)
+		defer rc.Close()
+		var manifest models.AccountArchiveManifest
+		require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+		assert.Equal(t, models.AccountArchiveFormat, manifest.Format)
+		assert.Equal(t, models.AccountArchiveVersion, manifest.Version)
+		assert.Equal(t, source.ID, manifest.UserID)
+		assert.Equal(t, 2, manifest.Counts["projects"])
+		assert.Equal(t, 2, manifest.Counts["tags"])
+		assert.Equal(t, 2, manifest.Counts["log_entries"])
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		report, err := accountService.ImportAccount(ctx, targetID, bytes.NewReader(data), int64(len(data)), true)
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Projects.Created)
+		assert.Equal(t, 2, report.Tags.Created)
+		assert.Equal(t, 2, report.LogEntries.Created)
+		assert.Nil(t, report.ProjectIDs, "IDs of a rolled back import are not reported")
+
+		projects, err := projectService.GetUserProjects(ctx, targetID, true)
+		require.NoError(t, err)
+		assert.Empty(t, projects, "a dry run changes nothing")
+		profile, err := userService.GetUserProfile(ctx, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", profile.Timezone)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		report, err := accountService.ImportAccount(ctx, targetID, bytes.NewReader(data), int64(len(data)), false)
+		require.NoError(t, err)
+		assert.False(t, report.DryRun)
+		assert.True(t, report.ProfileUpdated)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Created: 2}, report.Projects)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Created: 2}, report.Tags)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Created: 2}, report.LogEntries)
+		assert.Empty(t, report.Warnings)
+
+		profile, err := userService.GetUserProfile(ctx, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, "America/Sao_Paulo", profile.Timezone)
+		assert.Equal(t, "account-target@example.com", profile.Email, "the email is never imported")
+
+		newParentID := report.ProjectIDs[parent.ID]
+		newChildID := report.ProjectIDs[child.ID]
+		require.NotEqual(t, parent.ID, newParentID, "records get new IDs")
+		importedChild, err := projectService.GetProject(ctx, targetID, newChildID.String())
+		require.NoError(t, err)
+		require.NotNil(t, importedChild.ParentID)
+		assert.Equal(t, newParentID, *importedChild.ParentID, "parents are remapped")
+		assert.NotNil(t, importedChild.ArchivedAt, "archived projects stay archived")
+
+		aliases, err := tagService.GetTagAliases(ctx, targetID, report.TagIDs[golang.ID].String())
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+		assert.Equal(t, "go", aliases[0].Alias)
+
+		entries, err := tagService.GetLogEntriesForTag(ctx, targetID, report.TagIDs[golang.ID].String())
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Invoice service", entries[0].Title)
+		require.NotNil(t, entries[0].ProjectID)
+		assert.Equal(t, newChildID, *entries[0].ProjectID)
+	})
+
+	t.Run("ReimportMatches", func(t *testing.T) {
+		report, err := accountService.ImportAccount(ctx, targetID, bytes.NewReader(data), int64(len(data)), false)
+		require.NoError(t, err)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Matched: 2}, report.Projects)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Matched: 2}, report.Tags)
+		assert.Equal(t, models.AccountImportCounts{Total: 2, Matched: 2}, report.LogEntries)
+
+		projects, err := projectService.GetUserProjects(ctx, targetID, true)
+		require.NoError(t, err)
+		assert.Len(t, projects, 2, "importing twice does not duplicate")
+	})
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildArchive zips the given files, encoding each value as JSON
+func buildArchive(t *testing.T, files map[string]any) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, v := range files {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestParentsFirst(t *testing.T) {
+	type node struct {
+		id     uuid.UUID
+		parent *uuid.UUID
+	}
+	root, child, grandchild, orphan := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	missing := uuid.New()
+
+	nodes := []node{
+		{id: grandchild, parent: &child},
+		{id: child, parent: &root},
+		{id: orphan, parent: &missing},
+		{id: root},
+	}
+	ordered := parentsFirst(nodes,
+		func(n node) uuid.UUID { return n.id },
+		func(n node) *uuid.UUID { return n.parent })
+
+	position := map[uuid.UUID]int{}
+	for i, n := range ordered {
+		position[n.id] = i
+	}
+	require.Len(t, ordered, len(nodes))
+	assert.Less(t, position[root], position[child])
+	assert.Less(t, position[child], position[grandchild])
+	assert.Contains(t, position, orphan, "a missing parent does not hold a record back")
+
+	a, b := uuid.New(), uuid.New()
+	cycle := parentsFirst([]node{{id: a, parent: &b}, {id: b, parent: &a}},
+		func(n node) uuid.UUID { return n.id },
+		func(n node) *uuid.UUID { return n.parent })
+	assert.Len(t, cycle, 2, "records in a cycle are still returned")
+}
+
+func TestJSONArrayWriter(t *testing.T) {
+	var buf bytes.Buffer
+	array := &jsonArrayWriter{w: &buf}
+	require.NoError(t, array.close())
+	assert.Equal(t, "[]\n", buf.String())
+
+	buf.Reset()
+	array = &jsonArrayWriter{w: &buf}
+	require.NoError(t, array.write(map[string]string{"title": "<b>&"}))
+	require.NoError(t, array.write(map[string]int{"n": 2}))
+	require.NoError(t, array.close())
+	assert.Equal(t, "[\n{\"title\":\"<b>&\"},\n{\"n\":2}\n]\n", buf.String())
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded, 2)
+}
+
+func TestReadAccountArchive(t *testing.T) {
+	manifest := models.AccountArchiveManifest{
+		Format:     models.AccountArchiveFormat,
+		Version:    models.AccountArchiveVersion,
+		ExportedAt: time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC),
+	}
+
+	read := func(files map[string]any) (*accountArchive, error) {
+		data := buildArchive(t, files)
+		return readAccountArchive(bytes.NewReader(data), int64(len(data)))
+	}
+
+	_, err := readAccountArchive(bytes.NewReader([]byte("not a zip")), 9)
+	assert.ErrorContains(t, err, "invalid archive")
+
+	_, err = read(map[string]any{accountProjectsFile: []any{}})
+	assert.ErrorContains(t, err, "manifest.json is missing")
+
+	wrongFormat := manifest
+	wrongFormat.Format = "something-else"
+	_, err = read(map[string]any{accountManifestFile: wrongFormat})
+	assert.ErrorContains(t, err, "not an EngLog account archive")
+
+	newer := manifest
+	newer.Version = models.AccountArchiveVersion + 1
+	_, err = read(map[string]any{accountManifestFile: newer})
+	assert.ErrorContains(t, err, "unsupported archive version")
+
+	_, err = read(map[string]any{accountManifestFile: manifest, accountTagsFile: "not an array"})
+	assert.ErrorContains(t, err, "failed to read tags.json")
+
+	archive, err := read(map[string]any{
+		accountManifestFile: manifest,
+		accountTagsFile:     []models.AccountArchiveTag{{ID: uuid.New(), Name: "go", Color: "#00add8"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, manifest.ExportedAt, archive.manifest.ExportedAt)
+	assert.Nil(t, archive.profile, "missing files import nothing")
+	assert.Empty(t, archive.projects)
+	require.Len(t, archive.tags, 1)
+	assert.Equal(t, "go", archive.tags[0].Name)
+}
+
+func TestInsightParams(t *testing.T) {
+	userUUID := uuid.New()
+	valid := models.AccountArchiveInsight{
+		ReportType:  models.ReportWeeklySummary,
+		PeriodStart: "2025-03-03",
+		PeriodEnd:   "2025-03-09",
+		Title:       "Week 10",
+		Content:     "A productive week",
+	}
+
+	params, err := insightParams(userUUID, valid)
+	require.NoError(t, err)
+	assert.Equal(t, userUUID, params.UserID)
+	assert.Equal(t, []byte("{}"), params.Metadata, "missing metadata defaults to an empty object")
+	assert.False(t, params.QualityScore.Valid)
+
+	score := 0.8
+	valid.QualityScore = &score
+	params, err = insightParams(userUUID, valid)
+	require.NoError(t, err)
+	assert.Equal(t, &score, pgNumericToFloat64(params.QualityScore))
+
+	for name, mutate := range map[string]func(*models.AccountArchiveInsight){
+		"invalid report type": func(i *models.AccountArchiveInsight) { i.ReportType = "yearly" },
+		"title":               func(i *models.AccountArchiveInsight) { i.Title = " " },
+		"content is required": func(i *models.AccountArchiveInsight) { i.Content = "" },
+		"period start":        func(i *models.AccountArchiveInsight) { i.PeriodEnd = "" },
+		"on or after":         func(i *models.AccountArchiveInsight) { i.PeriodEnd = "2025-03-01" },
+		"quality score":       func(i *models.AccountArchiveInsight) { bad := 1.5; i.QualityScore = &bad },
+	} {
+		insight := valid
+		mutate(&insight)
+		_, err := insightParams(userUUID, insight)
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestAccountService_RejectsInvalidInput(t *testing.T) {
+	service := NewAccountService(nil, logging.NewTestLogger(), nil, nil, nil, nil).WithMaxArchiveBytes(1024)
+	ctx := context.Background()
+
+	assert.Equal(t, int64(1024), service.MaxArchiveBytes())
+	assert.Equal(t, int64(1024), service.WithMaxArchiveBytes(0).MaxArchiveBytes(), "non-positive values keep the limit")
+
+	err := service.ExportAccount(ctx, "not-a-uuid", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	_, err = service.ImportAccount(ctx, "not-a-uuid", bytes.NewReader(nil), 0, false)
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	_, err = service.ImportAccount(ctx, uuid.NewString(), bytes.NewReader(nil), 2048, false)
+	assert.ErrorContains(t, err, "byte limit")
+
+	_, err = service.ImportAccount(ctx, uuid.NewString(), bytes.NewReader([]byte("junk")), 4, true)
+	assert.ErrorContains(t, err, "invalid archive")
+}
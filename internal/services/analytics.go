@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/garnizeh/englog/internal/database"
@@ -10,19 +13,27 @@ import (
 	"github.com/garnizeh/englog/internal/models"
 	"github.com/garnizeh/englog/internal/store"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // AnalyticsService handles all business logic for analytics and productivity metrics
 type AnalyticsService struct {
 	db     *database.DB
 	logger *logging.Logger
+
+	refreshInterval time.Duration
+	refreshDebounce time.Duration
+	writes          chan struct{}
 }
 
 // NewAnalyticsService creates a new AnalyticsService instance
 func NewAnalyticsService(db *database.DB, logger *logging.Logger) *AnalyticsService {
 	return &AnalyticsService{
-		db:     db,
-		logger: logger.WithComponent("analytics_service"),
+		db:              db,
+		logger:          logger.WithComponent("analytics_service"),
+		refreshInterval: defaultSummaryRefreshInterval,
+		refreshDebounce: defaultSummaryRefreshDebounce,
+		writes:          make(chan struct{}, 1),
 	}
 }
 
@@ -41,40 +52,21 @@ func (s *AnalyticsService) GetProductivityMetrics(ctx context.Context, userID st
 	// Read operation to get productivity metrics
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
 		// Get activity type distribution
-		typeDistribution, err := qtx.GetActivityTypeDistribution(ctx, store.GetActivityTypeDistributionParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to get activity type distribution: %w", err)
-		}
-
-		// Get value rating distribution
-		valueDistribution, err := qtx.GetValueRatingDistribution(ctx, store.GetValueRatingDistributionParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		typeDistribution, err := s.activityTypeDistribution(ctx, qtx, userUUID, startDate, endDate)
 		if err != nil {
-			return fmt.Errorf("failed to get value rating distribution: %w", err)
+			return err
 		}
 
-		// Get impact level distribution
-		impactDistribution, err := qtx.GetImpactLevelDistribution(ctx, store.GetImpactLevelDistributionParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		// Get value rating and impact level distributions
+		valueRatingMap, impactLevelMap, err := s.ratingDistributions(ctx, qtx, userUUID, startDate, endDate)
 		if err != nil {
-			return fmt.Errorf("failed to get impact level distribution: %w", err)
+			return err
 		}
 
 		// Process distributions into model format
 		activityBreakdown := make(map[models.ActivityType]int)
 		totalActivities := 0
 		totalMinutes := 0
-		highValueActivities := 0
 
 		for _, dist := range typeDistribution {
 			activityType := models.ActivityType(dist.Type)
@@ -83,20 +75,7 @@ func (s *AnalyticsService) GetProductivityMetrics(ctx context.Context, userID st
 			totalMinutes += int(dist.TotalMinutes)
 		}
 
-		valueRatingMap := make(map[models.ValueRating]int)
-		for _, dist := range valueDistribution {
-			valueRating := models.ValueRating(dist.ValueRating)
-			valueRatingMap[valueRating] = int(dist.EntryCount)
-			if valueRating == models.ValueHigh || valueRating == models.ValueCritical {
-				highValueActivities += int(dist.EntryCount)
-			}
-		}
-
-		impactLevelMap := make(map[models.ImpactLevel]int)
-		for _, dist := range impactDistribution {
-			impactLevel := models.ImpactLevel(dist.ImpactLevel)
-			impactLevelMap[impactLevel] = int(dist.EntryCount)
-		}
+		highValueActivities := valueRatingMap[models.ValueHigh] + valueRatingMap[models.ValueCritical]
 
 		// Calculate daily averages
 		daysDiff := int(endDate.Sub(startDate).Hours()/24) + 1
@@ -154,13 +133,9 @@ func (s *AnalyticsService) GetActivitySummary(ctx context.Context, userID string
 
 	// Read operation to get activity summary
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		typeDistribution, err := qtx.GetActivityTypeDistribution(ctx, store.GetActivityTypeDistributionParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		typeDistribution, err := s.activityTypeDistribution(ctx, qtx, userUUID, startDate, endDate)
 		if err != nil {
-			return fmt.Errorf("failed to get activity type distribution: %w", err)
+			return err
 		}
 
 		summaries = make([]*models.ActivitySummary, len(typeDistribution))
@@ -184,8 +159,9 @@ func (s *AnalyticsService) GetActivitySummary(ctx context.Context, userID string
 	return summaries, nil
 }
 
-// GetWeeklyActivitySummary retrieves weekly activity summary for a user
-func (s *AnalyticsService) GetWeeklyActivitySummary(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.ActivitySummary, error) {
+// GetWeeklyActivitySummary retrieves weekly activity summary for a user.
+// Nil settings bucket in the user's timezone with their preferred week start.
+func (s *AnalyticsService) GetWeeklyActivitySummary(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) ([]*models.PeriodSummary, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format in GetWeeklyActivitySummary", "user_id", userID)
@@ -194,27 +170,29 @@ func (s *AnalyticsService) GetWeeklyActivitySummary(ctx context.Context, userID
 
 	s.logger.Info("Getting weekly activity summary", "user_id", userID, "start_date", startDate, "end_date", endDate)
 
-	var summaries []*models.ActivitySummary
+	var summaries []*models.PeriodSummary
 
 	// Read operation to get weekly activity summary
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		weeklyData, err := qtx.GetWeeklyActivitySummary(ctx, store.GetWeeklyActivitySummaryParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
 		if err != nil {
-			return fmt.Errorf("failed to get weekly activity summary: %w", err)
+			return err
 		}
 
-		summaries = make([]*models.ActivitySummary, len(weeklyData))
+		weeklyData, err := s.weeklySummary(ctx, qtx, userUUID, settings, startDate, endDate)
+		if err != nil {
+			return err
+		}
+
+		summaries = make([]*models.PeriodSummary, len(weeklyData))
 		for i, week := range weeklyData {
-			summaries[i] = &models.ActivitySummary{
-				Type:         models.ActivityDevelopment, // Default type for weekly summary
-				Count:        int(week.EntryCount),
-				TotalMinutes: int(week.TotalMinutes),
-				AvgMinutes:   week.AvgDuration,
-				Week:         startDate, // Using start date as reference since WeekStart is interval
+			summaries[i] = &models.PeriodSummary{
+				PeriodStart:   pgDateInLocation(week.WeekStart, settings.Location),
+				EntryCount:    int(week.EntryCount),
+				TotalMinutes:  int(week.TotalMinutes),
+				AvgMinutes:    week.AvgDuration,
+				ProjectsCount: int(week.ProjectsCount),
+				ActiveDays:    int(week.ActiveDays),
 			}
 		}
 
@@ -228,8 +206,9 @@ func (s *AnalyticsService) GetWeeklyActivitySummary(ctx context.Context, userID
 	return summaries, nil
 }
 
-// GetMonthlyActivitySummary retrieves monthly activity summary for a user
-func (s *AnalyticsService) GetMonthlyActivitySummary(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.ActivitySummary, error) {
+// GetMonthlyActivitySummary retrieves monthly activity summary for a user.
+// Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetMonthlyActivitySummary(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) ([]*models.PeriodSummary, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format in GetMonthlyActivitySummary", "user_id", userID)
@@ -238,27 +217,29 @@ func (s *AnalyticsService) GetMonthlyActivitySummary(ctx context.Context, userID
 
 	s.logger.Info("Getting monthly activity summary", "user_id", userID, "start_date", startDate, "end_date", endDate)
 
-	var summaries []*models.ActivitySummary
+	var summaries []*models.PeriodSummary
 
 	// Read operation to get monthly activity summary
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		monthlyData, err := qtx.GetMonthlyActivitySummary(ctx, store.GetMonthlyActivitySummaryParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
 		if err != nil {
-			return fmt.Errorf("failed to get monthly activity summary: %w", err)
+			return err
 		}
 
-		summaries = make([]*models.ActivitySummary, len(monthlyData))
+		monthlyData, err := s.monthlySummary(ctx, qtx, userUUID, settings, startDate, endDate)
+		if err != nil {
+			return err
+		}
+
+		summaries = make([]*models.PeriodSummary, len(monthlyData))
 		for i, month := range monthlyData {
-			summaries[i] = &models.ActivitySummary{
-				Type:         models.ActivityDevelopment, // Default type for monthly summary
-				Count:        int(month.EntryCount),
-				TotalMinutes: int(month.TotalMinutes),
-				AvgMinutes:   month.AvgDuration,
-				Week:         startDate, // Using start date as reference since MonthStart is interval
+			summaries[i] = &models.PeriodSummary{
+				PeriodStart:   pgDateInLocation(month.MonthStart, settings.Location),
+				EntryCount:    int(month.EntryCount),
+				TotalMinutes:  int(month.TotalMinutes),
+				AvgMinutes:    month.AvgDuration,
+				ProjectsCount: int(month.ProjectsCount),
+				ActiveDays:    int(month.ActiveDays),
 			}
 		}
 
@@ -272,8 +253,9 @@ func (s *AnalyticsService) GetMonthlyActivitySummary(ctx context.Context, userID
 	return summaries, nil
 }
 
-// GetProductivityByDayOfWeek retrieves productivity metrics by day of week
-func (s *AnalyticsService) GetProductivityByDayOfWeek(ctx context.Context, userID string, startDate, endDate time.Time) (map[string]float64, error) {
+// GetProductivityByDayOfWeek retrieves productivity metrics by day of week.
+// Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetProductivityByDayOfWeek(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) (map[string]float64, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format in GetProductivityByDayOfWeek", "user_id", userID)
@@ -286,18 +268,19 @@ func (s *AnalyticsService) GetProductivityByDayOfWeek(ctx context.Context, userI
 
 	// Read operation to get productivity by day of week
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		weekdayData, err := qtx.GetProductivityByDayOfWeek(ctx, store.GetProductivityByDayOfWeekParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
-		})
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
+		if err != nil {
+			return err
+		}
+
+		weekdayData, err := s.dayOfWeekSummary(ctx, qtx, userUUID, settings, startDate, endDate)
 		if err != nil {
-			return fmt.Errorf("failed to get productivity by day of week: %w", err)
+			return err
 		}
 
 		productivity = make(map[string]float64)
 		for _, day := range weekdayData {
-			productivity[day.DayName] = float64(day.TotalMinutes)
+			productivity[time.Weekday(day.DayOfWeek).String()] = float64(day.TotalMinutes)
 		}
 
 		return nil
@@ -310,8 +293,9 @@ func (s *AnalyticsService) GetProductivityByDayOfWeek(ctx context.Context, userI
 	return productivity, nil
 }
 
-// GetProductivityByHour retrieves productivity metrics by hour of day
-func (s *AnalyticsService) GetProductivityByHour(ctx context.Context, userID string, startDate, endDate time.Time) (map[string]float64, error) {
+// GetProductivityByHour retrieves productivity metrics by hour of day.
+// Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetProductivityByHour(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) (map[string]float64, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format in GetProductivityByHour", "user_id", userID)
@@ -324,10 +308,16 @@ func (s *AnalyticsService) GetProductivityByHour(ctx context.Context, userID str
 
 	// Read operation to get productivity by hour
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
+		if err != nil {
+			return err
+		}
+
 		hourlyData, err := qtx.GetProductivityByHour(ctx, store.GetProductivityByHourParams{
-			UserID:      userUUID,
-			StartTime:   timeToPgTimestamptz(startDate),
-			StartTime_2: timeToPgTimestamptz(endDate),
+			Tz:        settings.Location.String(),
+			UserID:    userUUID,
+			StartTime: timeToPgTimestamptz(startDate),
+			EndTime:   timeToPgTimestamptz(endDate),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to get productivity by hour: %w", err)
@@ -335,17 +325,7 @@ func (s *AnalyticsService) GetProductivityByHour(ctx context.Context, userID str
 
 		productivity = make(map[string]float64)
 		for _, hour := range hourlyData {
-			// Convert pgtype.Numeric to string for hour key
-			hourValue := "0"
-			if hour.HourOfDay.Valid {
-				// Convert numeric to int64 and then to string
-				if val, err := hour.HourOfDay.Value(); err == nil {
-					if intVal, ok := val.(int64); ok {
-						hourValue = fmt.Sprintf("%d", intVal)
-					}
-				}
-			}
-			hourKey := fmt.Sprintf("hour_%s", hourValue)
+			hourKey := fmt.Sprintf("hour_%d", hour.HourOfDay)
 			productivity[hourKey] = float64(hour.TotalMinutes)
 		}
 
@@ -387,15 +367,38 @@ func (s *AnalyticsService) GetTopProjectsByTime(ctx context.Context, userID stri
 			return fmt.Errorf("failed to get top projects by time: %w", err)
 		}
 
+		// Lifetime metrics are only available for projects the user owns
+		performance, err := qtx.GetProjectPerformanceMetrics(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project performance metrics: %w", err)
+		}
+		metricsByProject := make(map[uuid.UUID]store.GetProjectPerformanceMetricsRow, len(performance))
+		for _, metric := range performance {
+			metricsByProject[metric.ProjectID] = metric
+		}
+
 		projects = make([]map[string]any, len(projectData))
 		for i, project := range projectData {
+			percentage, _ := project.Percentage.Float64Value()
 			projects[i] = map[string]any{
 				"project_id":    project.ID,
 				"project_name":  project.Name,
 				"project_color": pgTextToStringRequired(project.Color),
 				"total_minutes": project.TotalMinutes,
 				"entry_count":   project.EntryCount,
-				"percentage":    project.Percentage,
+				"percentage":    percentage.Float64,
+			}
+
+			if metric, ok := metricsByProject[project.ID]; ok {
+				projects[i]["lifetime"] = map[string]any{
+					"total_entries":      metric.TotalEntries,
+					"total_minutes":      metric.TotalMinutes,
+					"active_days":        metric.ActiveDays,
+					"contributors_count": metric.ContributorsCount,
+					"first_activity":     pgTimestamptzToTimePtr(metric.FirstActivity),
+					"last_activity":      pgTimestamptzToTimePtr(metric.LastActivity),
+					"recent_entries_30d": metric.RecentEntries30d,
+				}
 			}
 		}
 
@@ -444,12 +447,14 @@ func (s *AnalyticsService) GetComparisonStats(ctx context.Context, userID string
 		minutesChange := comparisonData.CurrentMinutes - comparisonData.PreviousMinutes
 
 		stats = map[string]any{
-			"current_entries":  comparisonData.CurrentEntries,
-			"current_minutes":  comparisonData.CurrentMinutes,
-			"previous_entries": comparisonData.PreviousEntries,
-			"previous_minutes": comparisonData.PreviousMinutes,
-			"entries_change":   entriesChange,
-			"minutes_change":   minutesChange,
+			"current_entries":        comparisonData.CurrentEntries,
+			"current_minutes":        comparisonData.CurrentMinutes,
+			"previous_entries":       comparisonData.PreviousEntries,
+			"previous_minutes":       comparisonData.PreviousMinutes,
+			"entries_change":         entriesChange,
+			"minutes_change":         minutesChange,
+			"entries_change_percent": percentChange(comparisonData.CurrentEntries, comparisonData.PreviousEntries),
+			"minutes_change_percent": percentChange(comparisonData.CurrentMinutes, comparisonData.PreviousMinutes),
 		}
 
 		return nil
@@ -468,6 +473,153 @@ func (s *AnalyticsService) GetComparisonStats(ctx context.Context, userID string
 	return stats, nil
 }
 
+// GetActivityHeatmap retrieves minutes and entries per day of week and hour of day.
+// Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetActivityHeatmap(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) (*models.ActivityHeatmap, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetActivityHeatmap", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := s.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Getting activity heatmap", "user_id", userID, "start_date", startDate, "end_date", endDate)
+
+	var heatmap *models.ActivityHeatmap
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
+		if err != nil {
+			return err
+		}
+
+		patterns, err := qtx.GetDailyActivityPattern(ctx, store.GetDailyActivityPatternParams{
+			Tz:        settings.Location.String(),
+			UserID:    userUUID,
+			StartTime: timeToPgTimestamptz(startDate),
+			EndTime:   timeToPgTimestamptz(endDate),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get daily activity pattern: %w", err)
+		}
+
+		heatmap = buildActivityHeatmap(patterns)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get activity heatmap", "user_id", userID, "start_date", startDate, "end_date", endDate)
+		return nil, fmt.Errorf("failed to get activity heatmap: %w", err)
+	}
+
+	s.logger.Info("Successfully retrieved activity heatmap", "user_id", userID, "total_entries", heatmap.TotalEntries)
+	return heatmap, nil
+}
+
+// GetProductivityTrend retrieves a daily productivity series with a point for every day in the range.
+// Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetProductivityTrend(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) ([]*models.ProductivityTrendPoint, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetProductivityTrend", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := s.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Getting productivity trend", "user_id", userID, "start_date", startDate, "end_date", endDate)
+
+	var trend []*models.ProductivityTrendPoint
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
+		if err != nil {
+			return err
+		}
+
+		rows, err := s.productivityTrend(ctx, qtx, userUUID, settings, startDate, endDate)
+		if err != nil {
+			return err
+		}
+
+		startDay := truncateToDay(startDate.In(settings.Location))
+		endDay := truncateToDay(endDate.In(settings.Location))
+		trend = fillProductivityTrend(rows, startDay, endDay)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get productivity trend", "user_id", userID, "start_date", startDate, "end_date", endDate)
+		return nil, fmt.Errorf("failed to get productivity trend: %w", err)
+	}
+
+	s.logger.Info("Successfully retrieved productivity trend", "user_id", userID, "days_count", len(trend))
+	return trend, nil
+}
+
+// ResolveSettings returns how analytics are bucketed for a user: in the given location,
+// or the user's own timezone when it is nil, with weeks starting on the user's preferred day
+func (s *AnalyticsService) ResolveSettings(ctx context.Context, userID string, location *time.Location) (*models.AnalyticsSettings, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ResolveSettings", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var settings *models.AnalyticsSettings
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		settings, err = s.resolveSettings(ctx, qtx, userUUID, nil)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to resolve analytics settings", "user_id", userID)
+		return nil, err
+	}
+
+	if location != nil {
+		settings.Location = location
+	}
+
+	return settings, nil
+}
+
+// resolveSettings returns settings unchanged, or the user's own settings when nil
+func (s *AnalyticsService) resolveSettings(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, settings *models.AnalyticsSettings) (*models.AnalyticsSettings, error) {
+	if settings != nil && settings.Location != nil {
+		return settings, nil
+	}
+
+	user, err := qtx.GetUserByID(ctx, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "no rows") {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	location, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+	if err != nil {
+		s.logger.Warn("Stored timezone is invalid, bucketing analytics in UTC", "user_id", userUUID, "timezone", user.Timezone.String)
+		location = time.UTC
+	}
+
+	var preferences map[string]any
+	if len(user.Preferences) > 0 {
+		_ = json.Unmarshal(user.Preferences, &preferences)
+	}
+
+	resolved := &models.AnalyticsSettings{
+		Location:  location,
+		WeekStart: models.WeekStartFromPreferences(preferences),
+	}
+	if settings != nil && settings.WeekStart.IsValid() {
+		resolved.WeekStart = settings.WeekStart
+	}
+
+	return resolved, nil
+}
+
 // RefreshUserActivitySummary refreshes the materialized view for user activity summary
 func (s *AnalyticsService) RefreshUserActivitySummary(ctx context.Context) error {
 	s.logger.Info("Refreshing user activity summary materialized view")
@@ -509,3 +661,100 @@ func (s *AnalyticsService) validateUserID(ctx context.Context, userID string) er
 
 	return nil
 }
+
+// percentChange returns the relative change from previous to current in percent,
+// or nil when there is nothing to compare against
+func percentChange(current, previous int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round(float64(current-previous)*10000/float64(previous)) / 100
+	return &change
+}
+
+// buildActivityHeatmap folds daily activity patterns into a day of week by hour grid
+func buildActivityHeatmap(patterns []store.GetDailyActivityPatternRow) *models.ActivityHeatmap {
+	heatmap := &models.ActivityHeatmap{}
+
+	for _, pattern := range patterns {
+		day, hour := pattern.DayOfWeek, pattern.HourOfDay
+		if day < 0 || day > 6 || hour < 0 || hour > 23 {
+			continue
+		}
+
+		minutes := int(pattern.TotalMinutes)
+		entries := int(pattern.EntryCount)
+		heatmap.Minutes[day][hour] += minutes
+		heatmap.Entries[day][hour] += entries
+		heatmap.DayTotals[day] += minutes
+		heatmap.HourTotals[hour] += minutes
+		heatmap.TotalMinutes += minutes
+		heatmap.TotalEntries += entries
+	}
+
+	if heatmap.TotalMinutes == 0 {
+		return heatmap
+	}
+
+	peakDay, peakHour := 0, 0
+	for day, total := range heatmap.DayTotals {
+		if total > heatmap.DayTotals[peakDay] {
+			peakDay = day
+		}
+	}
+	for hour, total := range heatmap.HourTotals {
+		if total > heatmap.HourTotals[peakHour] {
+			peakHour = hour
+		}
+	}
+	heatmap.PeakDay = &peakDay
+	heatmap.PeakHour = &peakHour
+
+	return heatmap
+}
+
+// fillProductivityTrend returns one point per day from startDay to endDay, with zeros for days without entries
+func fillProductivityTrend(rows []store.GetUserProductivityTrendRow, startDay, endDay time.Time) []*models.ProductivityTrendPoint {
+	byDate := make(map[string]store.GetUserProductivityTrendRow, len(rows))
+	for _, row := range rows {
+		if row.ActivityDate.Valid {
+			byDate[row.ActivityDate.Time.Format("2006-01-02")] = row
+		}
+	}
+
+	trend := []*models.ProductivityTrendPoint{}
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		point := &models.ProductivityTrendPoint{Date: date}
+		if row, ok := byDate[date]; ok {
+			point.TotalMinutes = int(row.TotalMinutes)
+			point.EntryCount = int(row.EntryCount)
+			point.AvgValueScore = row.AvgValueScore
+			point.ProductivityScore = row.ProductivityScore
+		}
+		trend = append(trend, point)
+	}
+
+	return trend
+}
+
+// weekOffset is the number of days weeks are shifted back from ISO weeks, which start on Monday
+func weekOffset(weekStart models.WeekStart) int32 {
+	if weekStart == models.WeekStartSunday {
+		return 1
+	}
+	return 0
+}
+
+// pgDateInLocation returns midnight of a calendar date in loc
+func pgDateInLocation(date pgtype.Date, loc *time.Location) time.Time {
+	if !date.Valid {
+		return time.Time{}
+	}
+	return time.Date(date.Time.Year(), date.Time.Month(), date.Time.Day(), 0, 0, 0, 0, loc)
+}
+
+// truncateToDay returns midnight of the given time's calendar day in its location
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
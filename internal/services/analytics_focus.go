@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// focusBreakTolerance is the longest gap that does not interrupt a focus block
+	focusBreakTolerance = 5 * time.Minute
+	// deepWorkMinBlock is the shortest focus block that counts as deep work
+	deepWorkMinBlock = 90 * time.Minute
+)
+
+// focusEntry is a log entry reduced to what focus metrics need
+type focusEntry struct {
+	start    time.Time
+	end      time.Time
+	activity models.ActivityType
+	project  uuid.UUID // uuid.Nil when the entry has no project
+}
+
+// GetFocusMetrics measures fragmentation per day and week: type/project switches,
+// the longest uninterrupted focus block, meeting-free stretches and the share of
+// time spent in blocks of 90 minutes or more. Nil settings bucket in the user's timezone.
+func (s *AnalyticsService) GetFocusMetrics(ctx context.Context, userID string, startDate, endDate time.Time, settings *models.AnalyticsSettings) (*models.FocusReport, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetFocusMetrics", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := s.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Getting focus metrics", "user_id", userID, "start_date", startDate, "end_date", endDate)
+
+	var report *models.FocusReport
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, settings)
+		if err != nil {
+			return err
+		}
+
+		rows, err := qtx.GetFocusEntries(ctx, store.GetFocusEntriesParams{
+			UserID:    userUUID,
+			StartTime: timeToPgTimestamptz(startDate),
+			EndTime:   timeToPgTimestamptz(endDate),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get focus entries: %w", err)
+		}
+
+		entries := make([]focusEntry, len(rows))
+		for i, row := range rows {
+			entries[i] = focusEntry{
+				start:    row.StartTime.Time.In(settings.Location),
+				end:      row.EndTime.Time.In(settings.Location),
+				activity: models.ActivityType(row.Type),
+			}
+			if project := pgUUIDToUUID(row.ProjectID); project != nil {
+				entries[i].project = *project
+			}
+		}
+
+		report = buildFocusReport(entries, startDate.In(settings.Location), settings.WeekStart.Weekday())
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get focus metrics", "user_id", userID, "start_date", startDate, "end_date", endDate)
+		return nil, fmt.Errorf("failed to get focus metrics: %w", err)
+	}
+
+	s.logger.Info("Successfully retrieved focus metrics", "user_id", userID, "active_days", report.Summary.ActiveDays)
+	return report, nil
+}
+
+// buildFocusReport groups entries (ordered by start, already in the user's
+// location) by the local day they start on and aggregates days into weeks
+func buildFocusReport(entries []focusEntry, rangeStart time.Time, weekStart time.Weekday) *models.FocusReport {
+	report := &models.FocusReport{
+		Summary: models.FocusSummary{PeriodStart: rangeStart.Format(time.DateOnly)},
+		Weeks:   []*models.FocusSummary{},
+		Days:    []*models.FocusDay{},
+	}
+
+	for first := 0; first < len(entries); {
+		date := entries[first].start.Format(time.DateOnly)
+		last := first + 1
+		for last < len(entries) && entries[last].start.Format(time.DateOnly) == date {
+			last++
+		}
+		report.Days = append(report.Days, buildFocusDay(date, entries[first:last]))
+		first = last
+	}
+
+	for _, day := range report.Days {
+		dayStart, _ := time.Parse(time.DateOnly, day.Date)
+		offset := (int(dayStart.Weekday()) - int(weekStart) + 7) % 7
+		periodStart := dayStart.AddDate(0, 0, -offset).Format(time.DateOnly)
+
+		if len(report.Weeks) == 0 || report.Weeks[len(report.Weeks)-1].PeriodStart != periodStart {
+			report.Weeks = append(report.Weeks, &models.FocusSummary{PeriodStart: periodStart})
+		}
+		addFocusDay(report.Weeks[len(report.Weeks)-1], day)
+		addFocusDay(&report.Summary, day)
+	}
+
+	for _, week := range report.Weeks {
+		finishFocusSummary(week)
+	}
+	finishFocusSummary(&report.Summary)
+
+	return report
+}
+
+// buildFocusDay computes the metrics of one day's entries
+func buildFocusDay(date string, entries []focusEntry) *models.FocusDay {
+	day := &models.FocusDay{
+		Date:                 date,
+		EntryCount:           len(entries),
+		MeetingFreeStretches: []models.FocusStretch{},
+	}
+
+	var (
+		blockMinutes int
+		blockEnd     time.Time
+		inBlock      bool
+		dayEnd       = entries[0].end
+		cursor       = entries[0].start
+	)
+
+	closeBlock := func() {
+		if !inBlock {
+			return
+		}
+		day.LongestFocusMinutes = max(day.LongestFocusMinutes, blockMinutes)
+		if time.Duration(blockMinutes)*time.Minute >= deepWorkMinBlock {
+			day.DeepWorkMinutes += blockMinutes
+		}
+		inBlock = false
+	}
+
+	addStretch := func(start, end time.Time) {
+		if !end.After(start) {
+			return
+		}
+		minutes := int(end.Sub(start) / time.Minute)
+		day.MeetingFreeStretches = append(day.MeetingFreeStretches, models.FocusStretch{Start: start, End: end, Minutes: minutes})
+		day.LongestMeetingFreeMinutes = max(day.LongestMeetingFreeMinutes, minutes)
+	}
+
+	for i, entry := range entries {
+		minutes := int(entry.end.Sub(entry.start) / time.Minute)
+		day.TotalMinutes += minutes
+		if entry.end.After(dayEnd) {
+			dayEnd = entry.end
+		}
+
+		switched := i > 0 && (entry.activity != entries[i-1].activity || entry.project != entries[i-1].project)
+		if switched {
+			day.Switches++
+		}
+
+		if entry.activity == models.ActivityMeeting {
+			day.MeetingMinutes += minutes
+			closeBlock()
+			addStretch(cursor, entry.start)
+			if entry.end.After(cursor) {
+				cursor = entry.end
+			}
+			continue
+		}
+
+		if inBlock && !switched && entry.start.Sub(blockEnd) <= focusBreakTolerance {
+			blockMinutes += minutes
+			if entry.end.After(blockEnd) {
+				blockEnd = entry.end
+			}
+			continue
+		}
+
+		closeBlock()
+		inBlock = true
+		blockMinutes = minutes
+		blockEnd = entry.end
+	}
+	closeBlock()
+	addStretch(cursor, dayEnd)
+
+	day.DeepWorkShare = focusShare(day.DeepWorkMinutes, day.TotalMinutes)
+	return day
+}
+
+// addFocusDay adds one day to a weekly or range summary
+func addFocusDay(summary *models.FocusSummary, day *models.FocusDay) {
+	summary.ActiveDays++
+	summary.EntryCount += day.EntryCount
+	summary.TotalMinutes += day.TotalMinutes
+	summary.Switches += day.Switches
+	summary.DeepWorkMinutes += day.DeepWorkMinutes
+	summary.MeetingMinutes += day.MeetingMinutes
+	summary.LongestFocusMinutes = max(summary.LongestFocusMinutes, day.LongestFocusMinutes)
+	summary.LongestMeetingFreeMinutes = max(summary.LongestMeetingFreeMinutes, day.LongestMeetingFreeMinutes)
+}
+
+// finishFocusSummary computes the averages and shares of a summary
+func finishFocusSummary(summary *models.FocusSummary) {
+	summary.DeepWorkShare = focusShare(summary.DeepWorkMinutes, summary.TotalMinutes)
+	if summary.ActiveDays > 0 {
+		summary.AvgSwitchesPerDay = math.Round(float64(summary.Switches)*100/float64(summary.ActiveDays)) / 100
+	}
+}
+
+// focusShare returns part as a percentage of total, rounded to 2 decimals
+func focusShare(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
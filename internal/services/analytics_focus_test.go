@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFocusDay(t *testing.T) {
+	day := time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	api := uuid.New()
+	web := uuid.New()
+
+	entries := []focusEntry{
+		// 09:00-10:00 + 10:05-11:00 on the same project: one 115 minute block despite the short break
+		{start: at(9, 0), end: at(10, 0), activity: models.ActivityDevelopment, project: api},
+		{start: at(10, 5), end: at(11, 0), activity: models.ActivityDevelopment, project: api},
+		// Standup interrupts focus
+		{start: at(11, 0), end: at(11, 30), activity: models.ActivityMeeting},
+		// Switch to another project, then a review: two short blocks
+		{start: at(11, 30), end: at(12, 30), activity: models.ActivityDevelopment, project: web},
+		{start: at(12, 30), end: at(13, 0), activity: models.ActivityCodeReview, project: web},
+		// A long break splits blocks even without a switch
+		{start: at(14, 0), end: at(15, 0), activity: models.ActivityCodeReview, project: web},
+		{start: at(15, 0), end: at(16, 0), activity: models.ActivityMeeting},
+	}
+
+	focus := buildFocusDay("2025-05-12", entries)
+
+	assert.Equal(t, "2025-05-12", focus.Date)
+	assert.Equal(t, 7, focus.EntryCount)
+	assert.Equal(t, 355, focus.TotalMinutes)
+	assert.Equal(t, 4, focus.Switches, "meeting, web project, review, meeting")
+	assert.Equal(t, 115, focus.LongestFocusMinutes)
+	assert.Equal(t, 115, focus.DeepWorkMinutes)
+	assert.Equal(t, 32.39, focus.DeepWorkShare)
+	assert.Equal(t, 90, focus.MeetingMinutes)
+
+	require.Len(t, focus.MeetingFreeStretches, 2)
+	assert.Equal(t, models.FocusStretch{Start: at(9, 0), End: at(11, 0), Minutes: 120}, focus.MeetingFreeStretches[0])
+	assert.Equal(t, models.FocusStretch{Start: at(11, 30), End: at(15, 0), Minutes: 210}, focus.MeetingFreeStretches[1])
+	assert.Equal(t, 210, focus.LongestMeetingFreeMinutes)
+}
+
+func TestBuildFocusDay_Edges(t *testing.T) {
+	day := time.Date(2025, 5, 12, 9, 0, 0, 0, time.UTC)
+
+	t.Run("meetings only", func(t *testing.T) {
+		focus := buildFocusDay("2025-05-12", []focusEntry{
+			{start: day, end: day.Add(time.Hour), activity: models.ActivityMeeting},
+			{start: day.Add(time.Hour), end: day.Add(2 * time.Hour), activity: models.ActivityMeeting},
+		})
+		assert.Zero(t, focus.LongestFocusMinutes)
+		assert.Zero(t, focus.Switches, "back-to-back meetings are not a switch")
+		assert.Empty(t, focus.MeetingFreeStretches)
+	})
+
+	t.Run("overlapping entries extend the block", func(t *testing.T) {
+		focus := buildFocusDay("2025-05-12", []focusEntry{
+			{start: day, end: day.Add(2 * time.Hour), activity: models.ActivityResearch},
+			{start: day.Add(time.Hour), end: day.Add(90 * time.Minute), activity: models.ActivityResearch},
+			{start: day.Add(2 * time.Hour), end: day.Add(3 * time.Hour), activity: models.ActivityResearch},
+		})
+		assert.Equal(t, 210, focus.LongestFocusMinutes)
+		assert.Equal(t, float64(100), focus.DeepWorkShare)
+		require.Len(t, focus.MeetingFreeStretches, 1)
+		assert.Equal(t, 180, focus.LongestMeetingFreeMinutes)
+	})
+}
+
+func TestBuildFocusReport(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	entry := func(year int, month time.Month, day, hour, minutes int, activity models.ActivityType) focusEntry {
+		start := time.Date(year, month, day, hour, 0, 0, 0, saoPaulo)
+		return focusEntry{start: start, end: start.Add(time.Duration(minutes) * time.Minute), activity: activity}
+	}
+
+	entries := []focusEntry{
+		entry(2025, 5, 10, 22, 120, models.ActivityDevelopment), // Saturday
+		entry(2025, 5, 11, 10, 30, models.ActivityMeeting),      // Sunday
+		entry(2025, 5, 11, 11, 60, models.ActivityDevelopment),
+		entry(2025, 5, 12, 9, 45, models.ActivityLearning), // Monday
+	}
+	rangeStart := time.Date(2025, 5, 5, 0, 0, 0, 0, saoPaulo)
+
+	t.Run("weeks from monday", func(t *testing.T) {
+		report := buildFocusReport(entries, rangeStart, time.Monday)
+
+		require.Len(t, report.Days, 3)
+		assert.Equal(t, "2025-05-10", report.Days[0].Date, "late evening work stays on the local day")
+
+		require.Len(t, report.Weeks, 2)
+		assert.Equal(t, "2025-05-05", report.Weeks[0].PeriodStart)
+		assert.Equal(t, 2, report.Weeks[0].ActiveDays)
+		assert.Equal(t, 210, report.Weeks[0].TotalMinutes)
+		assert.Equal(t, 120, report.Weeks[0].DeepWorkMinutes)
+		assert.Equal(t, 0.5, report.Weeks[0].AvgSwitchesPerDay)
+		assert.Equal(t, "2025-05-12", report.Weeks[1].PeriodStart)
+
+		assert.Equal(t, "2025-05-05", report.Summary.PeriodStart)
+		assert.Equal(t, 3, report.Summary.ActiveDays)
+		assert.Equal(t, 255, report.Summary.TotalMinutes)
+		assert.Equal(t, 120, report.Summary.LongestFocusMinutes)
+		assert.Equal(t, 47.06, report.Summary.DeepWorkShare)
+	})
+
+	t.Run("weeks from sunday", func(t *testing.T) {
+		report := buildFocusReport(entries, rangeStart, time.Sunday)
+
+		require.Len(t, report.Weeks, 2)
+		assert.Equal(t, "2025-05-04", report.Weeks[0].PeriodStart)
+		assert.Equal(t, 1, report.Weeks[0].ActiveDays)
+		assert.Equal(t, "2025-05-11", report.Weeks[1].PeriodStart)
+		assert.Equal(t, 2, report.Weeks[1].ActiveDays)
+	})
+
+	t.Run("no entries", func(t *testing.T) {
+		report := buildFocusReport(nil, rangeStart, time.Monday)
+		assert.Empty(t, report.Days)
+		assert.Empty(t, report.Weeks)
+		assert.Zero(t, report.Summary.ActiveDays)
+		assert.Zero(t, report.Summary.DeepWorkShare)
+	})
+}
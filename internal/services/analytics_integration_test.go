@@ -166,7 +166,7 @@ func TestAnalyticsService_FullLifecycle(t *testing.T) {
 		startDate := baseTime.Add(-7 * 24 * time.Hour) // A week ago
 		endDate := baseTime.Add(48 * time.Hour)        // Two days from base
 
-		summary, err := analyticsService.GetWeeklyActivitySummary(ctx, testUser.ID.String(), startDate, endDate)
+		summary, err := analyticsService.GetWeeklyActivitySummary(ctx, testUser.ID.String(), startDate, endDate, nil)
 		// This might fail due to database schema issues, so we'll be more lenient
 		if err != nil {
 			t.Logf("GetWeeklyActivitySummary returned error (expected for some DB schemas): %v", err)
@@ -197,7 +197,7 @@ func TestAnalyticsService_FullLifecycle(t *testing.T) {
 		startDate := baseTime
 		endDate := baseTime.Add(48 * time.Hour) // Two days
 
-		productivity, err := analyticsService.GetProductivityByDayOfWeek(ctx, testUser.ID.String(), startDate, endDate)
+		productivity, err := analyticsService.GetProductivityByDayOfWeek(ctx, testUser.ID.String(), startDate, endDate, nil)
 		require.NoError(t, err)
 		assert.NotNil(t, productivity)
 		// May be empty or have limited data, which is acceptable
@@ -207,11 +207,62 @@ func TestAnalyticsService_FullLifecycle(t *testing.T) {
 		startDate := baseTime
 		endDate := baseTime.Add(48 * time.Hour) // Two days
 
-		productivity, err := analyticsService.GetProductivityByHour(ctx, testUser.ID.String(), startDate, endDate)
+		productivity, err := analyticsService.GetProductivityByHour(ctx, testUser.ID.String(), startDate, endDate, nil)
 		require.NoError(t, err)
 		assert.NotNil(t, productivity)
 		// May be empty or have limited data, which is acceptable
 	})
+
+	t.Run("GetMonthlyActivitySummary", func(t *testing.T) {
+		startDate := baseTime
+		endDate := baseTime.Add(48 * time.Hour)
+
+		summaries, err := analyticsService.GetMonthlyActivitySummary(ctx, testUser.ID.String(), startDate, endDate, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, summaries)
+
+		totalMinutes := 0
+		for _, summary := range summaries {
+			assert.Equal(t, 1, summary.PeriodStart.Day(), "months start on the first")
+			totalMinutes += summary.TotalMinutes
+		}
+		assert.Equal(t, 450, totalMinutes)
+	})
+
+	t.Run("GetActivityHeatmap", func(t *testing.T) {
+		startDate := baseTime
+		endDate := baseTime.Add(48 * time.Hour)
+
+		heatmap, err := analyticsService.GetActivityHeatmap(ctx, testUser.ID.String(), startDate, endDate, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 5, heatmap.TotalEntries)
+		assert.Equal(t, 450, heatmap.TotalMinutes)
+		assert.Equal(t, 120, heatmap.Minutes[baseTime.Weekday()][0])
+		require.NotNil(t, heatmap.PeakDay)
+		assert.Equal(t, int(baseTime.Add(24*time.Hour).Weekday()), *heatmap.PeakDay)
+	})
+
+	t.Run("GetProductivityTrend", func(t *testing.T) {
+		startDate := baseTime.Add(-24 * time.Hour)
+		endDate := baseTime.Add(24 * time.Hour)
+
+		trend, err := analyticsService.GetProductivityTrend(ctx, testUser.ID.String(), startDate, endDate, nil)
+		require.NoError(t, err)
+		require.Len(t, trend, 3)
+		assert.Equal(t, 0, trend[0].TotalMinutes, "days without entries are filled in")
+		assert.Equal(t, 210, trend[1].TotalMinutes)
+		assert.Equal(t, 3, trend[1].EntryCount)
+		assert.Equal(t, 240, trend[2].TotalMinutes)
+	})
+
+	t.Run("GetTopProjectsByTimeIncludesLifetimeMetrics", func(t *testing.T) {
+		projects, err := analyticsService.GetTopProjectsByTime(ctx, testUser.ID.String(), baseTime, baseTime.Add(48*time.Hour), 5)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		assert.Equal(t, testProject.ID, projects[0]["project_id"])
+		assert.Equal(t, 100.0, projects[0]["percentage"])
+		assert.Contains(t, projects[0], "lifetime")
+	})
 }
 
 func TestAnalyticsService_ValidationErrors(t *testing.T) {
@@ -356,3 +407,95 @@ func TestAnalyticsService_ComparisonStats(t *testing.T) {
 		assert.Contains(t, stats, "minutes_change")
 	})
 }
+
+func TestAnalyticsService_TimezoneBucketing(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	analyticsService := services.NewAnalyticsService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "timezone@example.com",
+		Password:  "password123",
+		FirstName: "Time",
+		LastName:  "Zone",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	// Sunday 22:00 in São Paulo is Monday 01:00 UTC
+	start := time.Date(2024, 3, 10, 22, 0, 0, 0, saoPaulo)
+	_, err = logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+		Title:       "Late deploy",
+		Type:        models.ActivityDevelopment,
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		ValueRating: models.ValueHigh,
+		ImpactLevel: models.ImpactTeam,
+	})
+	require.NoError(t, err)
+
+	rangeStart := time.Date(2024, 3, 4, 0, 0, 0, 0, saoPaulo)
+	rangeEnd := time.Date(2024, 3, 17, 0, 0, 0, 0, saoPaulo)
+
+	t.Run("UserTimezoneByDefault", func(t *testing.T) {
+		byDay, err := analyticsService.GetProductivityByDayOfWeek(ctx, userID, rangeStart, rangeEnd, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"Sunday": 60}, byDay)
+
+		byHour, err := analyticsService.GetProductivityByHour(ctx, userID, rangeStart, rangeEnd, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"hour_22": 60}, byHour)
+
+		trend, err := analyticsService.GetProductivityTrend(ctx, userID, rangeStart, rangeEnd, nil)
+		require.NoError(t, err)
+		for _, point := range trend {
+			if point.Date == "2024-03-10" {
+				assert.Equal(t, 60, point.TotalMinutes)
+			} else {
+				assert.Zero(t, point.TotalMinutes, point.Date)
+			}
+		}
+
+		weeks, err := analyticsService.GetWeeklyActivitySummary(ctx, userID, rangeStart, rangeEnd, nil)
+		require.NoError(t, err)
+		require.Len(t, weeks, 1)
+		assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, saoPaulo), weeks[0].PeriodStart, "weeks start on Monday by default")
+	})
+
+	t.Run("TimezoneOverride", func(t *testing.T) {
+		settings, err := analyticsService.ResolveSettings(ctx, userID, time.UTC)
+		require.NoError(t, err)
+
+		heatmap, err := analyticsService.GetActivityHeatmap(ctx, userID, rangeStart, rangeEnd, settings)
+		require.NoError(t, err)
+		assert.Equal(t, 60, heatmap.Minutes[time.Monday][1])
+	})
+
+	t.Run("SundayWeekStartPreference", func(t *testing.T) {
+		_, err := userService.UpdateUserProfile(ctx, userID, &models.UserProfileRequest{
+			FirstName:   "Time",
+			LastName:    "Zone",
+			Timezone:    "America/Sao_Paulo",
+			Preferences: map[string]any{models.PreferenceWeekStart: "sunday"},
+		})
+		require.NoError(t, err)
+
+		settings, err := analyticsService.ResolveSettings(ctx, userID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, models.WeekStartSunday, settings.WeekStart)
+		assert.Equal(t, "America/Sao_Paulo", settings.Location.String())
+
+		weeks, err := analyticsService.GetWeeklyActivitySummary(ctx, userID, rangeStart, rangeEnd, nil)
+		require.NoError(t, err)
+		require.Len(t, weeks, 1)
+		assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, saoPaulo), weeks[0].PeriodStart)
+	})
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// maxBurnDays bounds the burn series of long-running projects; minutes logged
+// before the first point are carried into its cumulative hours
+const maxBurnDays = 731
+
+// GetProjectStats reports the effort logged on a project and its sub-projects against its estimate:
+// totals per activity type, a daily burn-up/burn-down series and the projected
+// completion at the current burn rate, alongside the project's lifetime metrics.
+// Days are bucketed in location, or the user's timezone when nil.
+func (s *AnalyticsService) GetProjectStats(ctx context.Context, userID, projectID string, location *time.Location) (*models.ProjectStats, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetProjectStats", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	projectUUID, err := uuid.Parse(projectID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid project ID format in GetProjectStats", "user_id", userID, "project_id", projectID)
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	s.logger.Info("Getting project stats", "user_id", userID, "project_id", projectID)
+
+	var stats *models.ProjectStats
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		project, err := qtx.GetProjectByID(ctx, projectUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("project not found")
+			}
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		if project.CreatedBy != userUUID {
+			return fmt.Errorf("project not found")
+		}
+
+		settings, err := s.resolveSettings(ctx, qtx, userUUID, &models.AnalyticsSettings{Location: location})
+		if err != nil {
+			return err
+		}
+
+		budgets, err := qtx.GetProjectActivityBudgets(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project activity budgets: %w", err)
+		}
+
+		// Effort rolls up from the project's sub-projects
+		subtree, err := qtx.GetProjectSubtree(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get sub-projects: %w", err)
+		}
+		projectIDs := make([]uuid.UUID, 0, len(subtree))
+		for _, node := range subtree {
+			projectIDs = append(projectIDs, node.ID)
+		}
+
+		daily, err := qtx.GetProjectDailyMinutes(ctx, store.GetProjectDailyMinutesParams{
+			Tz:         settings.Location.String(),
+			ProjectIds: projectIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get project daily minutes: %w", err)
+		}
+
+		performance, err := qtx.GetProjectPerformanceMetrics(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project performance metrics: %w", err)
+		}
+
+		stats = buildProjectStats(project, budgets, daily, time.Now().In(settings.Location))
+
+		if len(subtree) > 1 {
+			totals, err := qtx.GetProjectTotals(ctx, projectIDs)
+			if err != nil {
+				return fmt.Errorf("failed to get sub-project totals: %w", err)
+			}
+			stats.Subprojects = projectRollups(subtree, totals)
+		}
+
+		for _, metric := range performance {
+			if metric.ProjectID == projectUUID {
+				stats.Lifetime = &models.ProjectLifetimeMetrics{
+					TotalEntries:      metric.TotalEntries,
+					ActiveDays:        metric.ActiveDays,
+					ContributorsCount: metric.ContributorsCount,
+					FirstActivity:     pgTimestamptzToTimePtr(metric.FirstActivity),
+					LastActivity:      pgTimestamptzToTimePtr(metric.LastActivity),
+					RecentEntries30d:  metric.RecentEntries30d,
+				}
+				break
+			}
+		}
+
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get project stats", "user_id", userID, "project_id", projectID)
+		return nil, fmt.Errorf("failed to get project stats: %w", err)
+	}
+
+	s.logger.Info("Successfully retrieved project stats", "user_id", userID, "project_id", projectID, "over_budget", stats.OverBudget)
+	return stats, nil
+}
+
+// buildProjectStats computes budget usage, the burn series and the projection.
+// now is the current time in the timezone the daily minutes were bucketed in.
+func buildProjectStats(project store.Project, budgets []store.ProjectActivityBudget, daily []store.GetProjectDailyMinutesRow, now time.Time) *models.ProjectStats {
+	today := civilDate(now)
+	estimated := pgFloat8ToFloat64(project.EstimatedHours)
+
+	stats := &models.ProjectStats{
+		ProjectID:      project.ID,
+		ProjectName:    project.Name,
+		Status:         models.ProjectStatus(pgTextToStringRequired(project.Status)),
+		EstimatedHours: estimated,
+		Activities:     []*models.ProjectActivityStats{},
+		Burn:           []*models.ProjectBurnPoint{},
+	}
+	if project.StartDate.Valid {
+		date := project.StartDate.Time.Format(time.DateOnly)
+		stats.StartDate = &date
+	}
+	if project.EndDate.Valid {
+		date := project.EndDate.Time.Format(time.DateOnly)
+		stats.EndDate = &date
+	}
+
+	minutesByDay := make(map[time.Time]int)
+	minutesByType := make(map[models.ActivityType]int)
+	for _, row := range daily {
+		minutesByDay[civilDate(row.ActivityDate.Time)] += int(row.TotalMinutes)
+		minutesByType[models.ActivityType(row.Type)] += int(row.TotalMinutes)
+		stats.ActualMinutes += int(row.TotalMinutes)
+	}
+	stats.ActualHours = minutesToHours(stats.ActualMinutes)
+
+	if estimated != nil {
+		remaining := roundHours(*estimated - float64(stats.ActualMinutes)/60)
+		percent := math.Round(float64(stats.ActualMinutes)*10000/(*estimated*60)) / 100
+		stats.RemainingHours = &remaining
+		stats.PercentUsed = &percent
+		stats.OverBudget = float64(stats.ActualMinutes) > *estimated*60
+	}
+
+	stats.Activities = projectActivityStats(budgets, minutesByType)
+
+	// The series starts on the project's start date, or its first logged day
+	var start time.Time
+	switch {
+	case project.StartDate.Valid:
+		start = civilDate(project.StartDate.Time)
+	case len(daily) > 0:
+		start = civilDate(daily[0].ActivityDate.Time)
+	default:
+		return stats
+	}
+
+	end := today
+	if project.EndDate.Valid && civilDate(project.EndDate.Time).Before(end) {
+		end = civilDate(project.EndDate.Time)
+	}
+	if len(daily) > 0 {
+		if last := civilDate(daily[len(daily)-1].ActivityDate.Time); last.After(end) {
+			end = last
+		}
+	}
+
+	stats.Burn = projectBurnSeries(project, estimated, minutesByDay, start, end)
+	if estimated != nil {
+		stats.Projection = projectProjection(project, *estimated, stats.ActualMinutes, start, today)
+	}
+
+	return stats
+}
+
+// projectRollups returns the effort of every sub-project in the subtree, in tree order.
+// Each row covers the sub-project's own entries; the root's stats hold the rollup.
+func projectRollups(subtree []store.GetProjectSubtreeRow, totals []store.GetProjectTotalsRow) []*models.ProjectRollup {
+	byProject := make(map[uuid.UUID]store.GetProjectTotalsRow, len(totals))
+	for _, total := range totals {
+		if total.ProjectID.Valid {
+			byProject[total.ProjectID.Bytes] = total
+		}
+	}
+
+	rollups := []*models.ProjectRollup{}
+	for _, node := range subtree {
+		if node.Depth == 0 {
+			continue
+		}
+		total := byProject[node.ID]
+		rollups = append(rollups, &models.ProjectRollup{
+			ProjectID:     node.ID,
+			ProjectName:   node.Name,
+			ParentID:      pgUUIDToUUID(node.ParentID),
+			Depth:         int(node.Depth),
+			Archived:      node.ArchivedAt.Valid,
+			EntryCount:    int(total.EntryCount),
+			ActualMinutes: int(total.TotalMinutes),
+			ActualHours:   minutesToHours(int(total.TotalMinutes)),
+		})
+	}
+
+	return rollups
+}
+
+// projectActivityStats lists every activity type with a budget or logged time
+func projectActivityStats(budgets []store.ProjectActivityBudget, minutesByType map[models.ActivityType]int) []*models.ProjectActivityStats {
+	byType := make(map[models.ActivityType]*models.ProjectActivityStats)
+	for _, budget := range budgets {
+		estimated := budget.EstimatedHours
+		byType[models.ActivityType(budget.ActivityType)] = &models.ProjectActivityStats{
+			ActivityType:   models.ActivityType(budget.ActivityType),
+			EstimatedHours: &estimated,
+		}
+	}
+	for activity := range minutesByType {
+		if _, ok := byType[activity]; !ok {
+			byType[activity] = &models.ProjectActivityStats{ActivityType: activity}
+		}
+	}
+
+	activities := make([]*models.ProjectActivityStats, 0, len(byType))
+	for activity, stats := range byType {
+		stats.ActualMinutes = minutesByType[activity]
+		stats.ActualHours = minutesToHours(stats.ActualMinutes)
+		if stats.EstimatedHours != nil {
+			percent := math.Round(float64(stats.ActualMinutes)*10000/(*stats.EstimatedHours*60)) / 100
+			stats.PercentUsed = &percent
+			stats.OverBudget = float64(stats.ActualMinutes) > *stats.EstimatedHours*60
+		}
+		activities = append(activities, stats)
+	}
+	slices.SortFunc(activities, func(a, b *models.ProjectActivityStats) int {
+		if a.ActualMinutes != b.ActualMinutes {
+			return b.ActualMinutes - a.ActualMinutes
+		}
+		if a.ActivityType < b.ActivityType {
+			return -1
+		}
+		return 1
+	})
+
+	return activities
+}
+
+// projectBurnSeries returns one point per day from start to end (both civil dates).
+// Ideal remaining hours need both project dates and fall linearly to zero by the end date.
+func projectBurnSeries(project store.Project, estimated *float64, minutesByDay map[time.Time]int, start, end time.Time) []*models.ProjectBurnPoint {
+	series := []*models.ProjectBurnPoint{}
+	if start.After(end) {
+		return series
+	}
+
+	first := start
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxBurnDays {
+		first = end.AddDate(0, 0, -(maxBurnDays - 1))
+	}
+
+	cumulative := 0
+	for day, minutes := range minutesByDay {
+		if day.Before(first) {
+			cumulative += minutes
+		}
+	}
+
+	var idealStart, idealEnd time.Time
+	ideal := estimated != nil && project.StartDate.Valid && project.EndDate.Valid
+	if ideal {
+		idealStart = civilDate(project.StartDate.Time)
+		idealEnd = civilDate(project.EndDate.Time)
+	}
+
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		minutes := minutesByDay[day]
+		cumulative += minutes
+
+		point := &models.ProjectBurnPoint{
+			Date:            day.Format(time.DateOnly),
+			Hours:           minutesToHours(minutes),
+			CumulativeHours: minutesToHours(cumulative),
+		}
+		if estimated != nil {
+			remaining := roundHours(*estimated - float64(cumulative)/60)
+			point.RemainingHours = &remaining
+		}
+		if ideal {
+			total := idealEnd.Sub(idealStart).Hours()/24 + 1
+			elapsed := min(max(day.Sub(idealStart).Hours()/24+1, 0), total)
+			remaining := roundHours(*estimated * (1 - elapsed/total))
+			point.IdealRemainingHours = &remaining
+		}
+		series = append(series, point)
+	}
+
+	return series
+}
+
+// projectProjection extrapolates the average hours per calendar day since start
+func projectProjection(project store.Project, estimated float64, actualMinutes int, start, today time.Time) *models.ProjectProjection {
+	projection := &models.ProjectProjection{
+		WillExceedBudget: float64(actualMinutes) > estimated*60,
+	}
+
+	days := today.Sub(start).Hours()/24 + 1
+	if days < 1 {
+		return projection
+	}
+	rate := float64(actualMinutes) / 60 / days
+	projection.DailyRateHours = roundHours(rate)
+	if rate == 0 {
+		return projection
+	}
+
+	if remaining := estimated - float64(actualMinutes)/60; remaining > 0 {
+		completion := today.AddDate(0, 0, int(math.Ceil(remaining/rate))).Format(time.DateOnly)
+		projection.ProjectedCompletion = &completion
+	}
+
+	if project.EndDate.Valid {
+		if end := civilDate(project.EndDate.Time); end.After(today) {
+			total := roundHours(float64(actualMinutes)/60 + rate*end.Sub(today).Hours()/24)
+			projection.ProjectedTotalHours = &total
+			projection.WillExceedBudget = projection.WillExceedBudget || total > estimated
+		}
+	}
+
+	return projection
+}
+
+// civilDate returns the calendar day of t as midnight UTC, so days compare and step safely
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// minutesToHours converts minutes to hours rounded to 2 decimals
+func minutesToHours(minutes int) float64 {
+	return roundHours(float64(minutes) / 60)
+}
+
+// roundHours rounds hours to 2 decimals
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProjectBudget(t *testing.T) {
+	hours := func(h float64) *float64 { return &h }
+	budget := func(activity models.ActivityType, h float64) models.ProjectActivityBudget {
+		return models.ProjectActivityBudget{ActivityType: activity, EstimatedHours: h}
+	}
+
+	tests := []struct {
+		name      string
+		estimated *float64
+		budgets   []models.ProjectActivityBudget
+		wantErr   string
+	}{
+		{"no budget", nil, nil, ""},
+		{"estimate only", hours(40), nil, ""},
+		{"split within estimate", hours(40), []models.ProjectActivityBudget{budget(models.ActivityDevelopment, 30), budget(models.ActivityMeeting, 10)}, ""},
+		{"split without estimate", nil, []models.ProjectActivityBudget{budget(models.ActivityMeeting, 10)}, ""},
+		{"zero estimate", hours(0), nil, "estimated hours must be greater than 0"},
+		{"invalid activity type", hours(40), []models.ProjectActivityBudget{budget("napping", 1)}, "invalid activity type in budget"},
+		{"duplicate activity type", hours(40), []models.ProjectActivityBudget{budget(models.ActivityTesting, 1), budget(models.ActivityTesting, 2)}, "duplicate budget"},
+		{"zero activity budget", hours(40), []models.ProjectActivityBudget{budget(models.ActivityTesting, 0)}, "estimated hours for testing must be greater than 0"},
+		{"split above estimate", hours(10), []models.ProjectActivityBudget{budget(models.ActivityDevelopment, 8), budget(models.ActivityMeeting, 4)}, "more than the 10h estimate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProjectBudget(tt.estimated, tt.budgets)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBuildProjectStats(t *testing.T) {
+	date := func(s string) pgtype.Date {
+		d, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return pgtype.Date{Time: d, Valid: true}
+	}
+	row := func(day string, activity models.ActivityType, minutes int32) store.GetProjectDailyMinutesRow {
+		return store.GetProjectDailyMinutesRow{ActivityDate: date(day), Type: string(activity), TotalMinutes: minutes}
+	}
+
+	project := store.Project{
+		ID:             uuid.New(),
+		Name:           "Billing revamp",
+		Status:         pgtype.Text{String: "active", Valid: true},
+		StartDate:      date("2025-05-01"),
+		EndDate:        date("2025-05-10"),
+		EstimatedHours: pgtype.Float8{Float64: 20, Valid: true},
+	}
+	budgets := []store.ProjectActivityBudget{
+		{ProjectID: project.ID, ActivityType: string(models.ActivityMeeting), EstimatedHours: 2},
+	}
+	daily := []store.GetProjectDailyMinutesRow{
+		row("2025-05-01", models.ActivityDevelopment, 240),
+		row("2025-05-01", models.ActivityMeeting, 60),
+		row("2025-05-03", models.ActivityMeeting, 90),
+		row("2025-05-04", models.ActivityDevelopment, 210),
+	}
+	// Evening of May 4th: 10 hours logged in 4 days
+	now := time.Date(2025, 5, 4, 21, 0, 0, 0, time.UTC)
+
+	t.Run("budget usage", func(t *testing.T) {
+		stats := buildProjectStats(project, budgets, daily, now)
+
+		assert.Equal(t, "2025-05-01", *stats.StartDate)
+		assert.Equal(t, 600, stats.ActualMinutes)
+		assert.Equal(t, float64(10), stats.ActualHours)
+		assert.Equal(t, float64(10), *stats.RemainingHours)
+		assert.Equal(t, float64(50), *stats.PercentUsed)
+		assert.False(t, stats.OverBudget)
+
+		require.Len(t, stats.Activities, 2)
+		assert.Equal(t, models.ActivityDevelopment, stats.Activities[0].ActivityType)
+		assert.Nil(t, stats.Activities[0].EstimatedHours, "development has no budget of its own")
+		assert.Equal(t, models.ActivityMeeting, stats.Activities[1].ActivityType)
+		assert.Equal(t, 2.5, stats.Activities[1].ActualHours)
+		assert.Equal(t, float64(125), *stats.Activities[1].PercentUsed)
+		assert.True(t, stats.Activities[1].OverBudget)
+	})
+
+	t.Run("burn series", func(t *testing.T) {
+		stats := buildProjectStats(project, budgets, daily, now)
+
+		require.Len(t, stats.Burn, 4, "from the start date through today")
+		assert.Equal(t, "2025-05-01", stats.Burn[0].Date)
+		assert.Equal(t, float64(5), stats.Burn[0].Hours)
+		assert.Equal(t, float64(15), *stats.Burn[0].RemainingHours)
+		assert.Equal(t, float64(18), *stats.Burn[0].IdealRemainingHours)
+		assert.Zero(t, stats.Burn[1].Hours, "days without entries are included")
+		assert.Equal(t, float64(5), stats.Burn[1].CumulativeHours)
+		assert.Equal(t, float64(10), stats.Burn[3].CumulativeHours)
+		assert.Equal(t, float64(12), *stats.Burn[3].IdealRemainingHours)
+	})
+
+	t.Run("projection", func(t *testing.T) {
+		stats := buildProjectStats(project, budgets, daily, now)
+
+		require.NotNil(t, stats.Projection)
+		assert.Equal(t, 2.5, stats.Projection.DailyRateHours)
+		assert.Equal(t, "2025-05-08", *stats.Projection.ProjectedCompletion)
+		assert.Equal(t, float64(25), *stats.Projection.ProjectedTotalHours)
+		assert.True(t, stats.Projection.WillExceedBudget)
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		small := project
+		small.EstimatedHours = pgtype.Float8{Float64: 8, Valid: true}
+		stats := buildProjectStats(small, nil, daily, now)
+
+		assert.True(t, stats.OverBudget)
+		assert.Equal(t, float64(-2), *stats.RemainingHours)
+		assert.Nil(t, stats.Projection.ProjectedCompletion)
+		assert.True(t, stats.Projection.WillExceedBudget)
+	})
+
+	t.Run("no estimate", func(t *testing.T) {
+		unbudgeted := project
+		unbudgeted.EstimatedHours = pgtype.Float8{}
+		unbudgeted.StartDate = pgtype.Date{}
+		stats := buildProjectStats(unbudgeted, nil, daily, now)
+
+		assert.Nil(t, stats.RemainingHours)
+		assert.Nil(t, stats.Projection)
+		assert.False(t, stats.OverBudget)
+		require.Len(t, stats.Burn, 4, "starts on the first logged day")
+		assert.Nil(t, stats.Burn[0].RemainingHours)
+		assert.Nil(t, stats.Burn[0].IdealRemainingHours)
+	})
+
+	t.Run("no activity", func(t *testing.T) {
+		unbudgeted := project
+		unbudgeted.StartDate = pgtype.Date{}
+		stats := buildProjectStats(unbudgeted, nil, nil, now)
+
+		assert.Empty(t, stats.Burn)
+		assert.Empty(t, stats.Activities)
+		assert.Nil(t, stats.Projection)
+	})
+
+	t.Run("long projects are capped", func(t *testing.T) {
+		old := project
+		old.StartDate = date("2020-01-01")
+		old.EndDate = pgtype.Date{}
+		stats := buildProjectStats(old, nil, append([]store.GetProjectDailyMinutesRow{row("2020-01-01", models.ActivityPlanning, 120)}, daily...), now)
+
+		require.Len(t, stats.Burn, maxBurnDays)
+		assert.Equal(t, "2025-05-04", stats.Burn[len(stats.Burn)-1].Date)
+		assert.Equal(t, float64(2), stats.Burn[0].CumulativeHours, "earlier hours are carried into the first point")
+	})
+}
+
+func TestProjectRollups(t *testing.T) {
+	epic := uuid.New()
+	api := uuid.New()
+	auth := uuid.New()
+	web := uuid.New()
+
+	subtree := []store.GetProjectSubtreeRow{
+		{ID: epic, Name: "Platform", Depth: 0},
+		{ID: api, ParentID: uuidToPgUUID(&epic), Name: "API", Depth: 1},
+		{ID: web, ParentID: uuidToPgUUID(&epic), Name: "Web", Depth: 1, ArchivedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+		{ID: auth, ParentID: uuidToPgUUID(&api), Name: "Auth", Depth: 2},
+	}
+	totals := []store.GetProjectTotalsRow{
+		{ProjectID: uuidToPgUUID(&epic), EntryCount: 1, TotalMinutes: 30},
+		{ProjectID: uuidToPgUUID(&api), EntryCount: 3, TotalMinutes: 150},
+		{ProjectID: uuidToPgUUID(&auth), EntryCount: 2, TotalMinutes: 45},
+	}
+
+	rollups := projectRollups(subtree, totals)
+
+	require.Len(t, rollups, 3, "the project itself is not a sub-project")
+	assert.Equal(t, api, rollups[0].ProjectID)
+	assert.Equal(t, epic, *rollups[0].ParentID)
+	assert.Equal(t, 150, rollups[0].ActualMinutes)
+	assert.Equal(t, 2.5, rollups[0].ActualHours)
+	assert.True(t, rollups[1].Archived)
+	assert.Zero(t, rollups[1].EntryCount, "sub-projects without entries are listed")
+	assert.Equal(t, 2, rollups[2].Depth)
+	assert.Equal(t, 45, rollups[2].ActualMinutes)
+}
+
+func TestProjectDefaultsConversion(t *testing.T) {
+	params := projectDefaultsParams(&models.ProjectDefaults{
+		Type:        models.ActivityMeeting,
+		Tags:        []string{" billing", "billing", "q3"},
+		ImpactLevel: models.ImpactTeam,
+	})
+
+	assert.Equal(t, pgtype.Text{String: "meeting", Valid: true}, params.DefaultType)
+	assert.False(t, params.DefaultValueRating.Valid, "unset defaults are stored as NULL")
+	assert.Equal(t, []string{"billing", "q3"}, params.DefaultTags)
+
+	defaults := projectDefaultsToModel(store.Project{
+		DefaultType:        params.DefaultType,
+		DefaultTags:        params.DefaultTags,
+		DefaultValueRating: params.DefaultValueRating,
+		DefaultImpactLevel: params.DefaultImpactLevel,
+	})
+	require.NotNil(t, defaults)
+	assert.Equal(t, models.ActivityMeeting, defaults.Type)
+	assert.Empty(t, defaults.ValueRating)
+	assert.Equal(t, models.ImpactTeam, defaults.ImpactLevel)
+
+	empty := projectDefaultsParams(nil)
+	assert.NotNil(t, empty.DefaultTags, "default_tags is NOT NULL")
+	assert.Nil(t, projectDefaultsToModel(store.Project{DefaultTags: empty.DefaultTags}))
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// defaultSummaryRefreshInterval is how often user_activity_summary is refreshed without writes
+	defaultSummaryRefreshInterval = 15 * time.Minute
+
+	// defaultSummaryRefreshDebounce is how long the refresher waits after a write, so a burst
+	// of writes leads to a single refresh
+	defaultSummaryRefreshDebounce = 30 * time.Second
+)
+
+// WithSummaryRefresh sets how often the summary refresher runs and how long it waits after
+// a write before refreshing; non-positive values keep the defaults
+func (s *AnalyticsService) WithSummaryRefresh(interval, debounce time.Duration) *AnalyticsService {
+	if interval > 0 {
+		s.refreshInterval = interval
+	}
+	if debounce > 0 {
+		s.refreshDebounce = debounce
+	}
+	return s
+}
+
+// NotifyLogEntryWrite tells the summary refresher that log entries changed. It never blocks:
+// writes arriving while a refresh is already pending are folded into it.
+func (s *AnalyticsService) NotifyLogEntryWrite() {
+	select {
+	case s.writes <- struct{}{}:
+	default:
+	}
+}
+
+// StartSummaryRefresher keeps the user_activity_summary materialized view fresh until ctx is
+// cancelled: it refreshes on a schedule, and once writes have been quiet for the debounce
+// period after a burst
+func (s *AnalyticsService) StartSummaryRefresher(ctx context.Context) {
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	// pending fires once the debounce period after the first write of a burst has passed
+	var pending *time.Timer
+	var pendingC <-chan time.Time
+	stopPending := func() {
+		if pending != nil {
+			pending.Stop()
+		}
+		pending, pendingC = nil, nil
+	}
+	defer stopPending()
+
+	s.logger.Info("Activity summary refresher started", "interval", s.refreshInterval.String(), "debounce", s.refreshDebounce.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Activity summary refresher stopped")
+			return
+		case <-s.writes:
+			if pending == nil {
+				pending = time.NewTimer(s.refreshDebounce)
+				pendingC = pending.C
+			}
+		case <-pendingC:
+			stopPending()
+			s.refreshSummary(ctx, "writes")
+			ticker.Reset(s.refreshInterval)
+		case <-ticker.C:
+			stopPending()
+			s.refreshSummary(ctx, "schedule")
+		}
+	}
+}
+
+// refreshSummary refreshes the view; RefreshUserActivitySummary logs failures, and the
+// refresher keeps running so the next write or tick retries
+func (s *AnalyticsService) refreshSummary(ctx context.Context, reason string) {
+	s.logger.Info("Activity summary refresh triggered", "reason", reason)
+	_ = s.RefreshUserActivitySummary(ctx)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The user_daily_rollups table is kept up to date by triggers on log entries and holds
+// per-day aggregates in the user's profile timezone. Analytics read it whenever a range
+// covers whole days in that timezone, and fall back to scanning log entries otherwise
+// (a tz override, or a range that starts or ends mid-day).
+
+// rollupDays returns the first and last day of the range when the rollups can answer it
+// exactly: location, when set, is the user's profile timezone and the range spans whole
+// days there
+func (s *AnalyticsService) rollupDays(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, location *time.Location, startDate, endDate time.Time) (pgtype.Date, pgtype.Date, bool) {
+	user, err := qtx.GetUserByID(ctx, userUUID)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, false
+	}
+
+	timezone := pgTextToStringRequired(user.Timezone)
+	if location != nil && location.String() != timezone {
+		return pgtype.Date{}, pgtype.Date{}, false
+	}
+	profileLocation, err := time.LoadLocation(timezone)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, false
+	}
+
+	firstDay, lastDay, ok := wholeDays(startDate, endDate, profileLocation)
+	if !ok {
+		return pgtype.Date{}, pgtype.Date{}, false
+	}
+
+	return dateToPgDate(firstDay), dateToPgDate(lastDay), true
+}
+
+// wholeDays returns the first and last calendar day of [startDate, endDate] in loc when
+// the range starts at midnight and ends on the last instant of a day
+func wholeDays(startDate, endDate time.Time, loc *time.Location) (time.Time, time.Time, bool) {
+	start := startDate.In(loc)
+	end := endDate.In(loc)
+	if !start.Equal(truncateToDay(start)) || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// Accept both nanosecond and database (microsecond) precision for the end of the day
+	nextDay := truncateToDay(end).AddDate(0, 0, 1)
+	if gap := nextDay.Sub(end); gap <= 0 || gap > time.Microsecond {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return truncateToDay(start), truncateToDay(end), true
+}
+
+// activityTypeDistribution returns entries and minutes per activity type
+func (s *AnalyticsService) activityTypeDistribution(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, startDate, endDate time.Time) ([]store.GetActivityTypeDistributionRow, error) {
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, nil, startDate, endDate); ok {
+		rows, err := qtx.GetRollupActivityTypeDistribution(ctx, store.GetRollupActivityTypeDistributionParams{
+			UserID:    userUUID,
+			StartDate: firstDay,
+			EndDate:   lastDay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get activity type distribution: %w", err)
+		}
+
+		distribution := make([]store.GetActivityTypeDistributionRow, len(rows))
+		for i, row := range rows {
+			distribution[i] = store.GetActivityTypeDistributionRow(row)
+		}
+		return distribution, nil
+	}
+
+	distribution, err := qtx.GetActivityTypeDistribution(ctx, store.GetActivityTypeDistributionParams{
+		UserID:      userUUID,
+		StartTime:   timeToPgTimestamptz(startDate),
+		StartTime_2: timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity type distribution: %w", err)
+	}
+	return distribution, nil
+}
+
+// ratingDistributions returns the entry count of every value rating and impact level in the range
+func (s *AnalyticsService) ratingDistributions(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, startDate, endDate time.Time) (map[models.ValueRating]int, map[models.ImpactLevel]int, error) {
+	valueRatings := make(map[models.ValueRating]int)
+	impactLevels := make(map[models.ImpactLevel]int)
+
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, nil, startDate, endDate); ok {
+		totals, err := qtx.GetRollupRatingTotals(ctx, store.GetRollupRatingTotalsParams{
+			UserID:    userUUID,
+			StartDate: firstDay,
+			EndDate:   lastDay,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get rating distributions: %w", err)
+		}
+
+		for rating, count := range map[models.ValueRating]int64{
+			models.ValueCritical: totals.CriticalEntries,
+			models.ValueHigh:     totals.HighEntries,
+			models.ValueMedium:   totals.MediumEntries,
+			models.ValueLow:      totals.LowEntries,
+		} {
+			if count > 0 {
+				valueRatings[rating] = int(count)
+			}
+		}
+		for level, count := range map[models.ImpactLevel]int64{
+			models.ImpactCompany:    totals.CompanyImpactEntries,
+			models.ImpactDepartment: totals.DepartmentImpactEntries,
+			models.ImpactTeam:       totals.TeamImpactEntries,
+			models.ImpactPersonal:   totals.PersonalImpactEntries,
+		} {
+			if count > 0 {
+				impactLevels[level] = int(count)
+			}
+		}
+		return valueRatings, impactLevels, nil
+	}
+
+	valueDistribution, err := qtx.GetValueRatingDistribution(ctx, store.GetValueRatingDistributionParams{
+		UserID:      userUUID,
+		StartTime:   timeToPgTimestamptz(startDate),
+		StartTime_2: timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get value rating distribution: %w", err)
+	}
+	for _, dist := range valueDistribution {
+		valueRatings[models.ValueRating(dist.ValueRating)] = int(dist.EntryCount)
+	}
+
+	impactDistribution, err := qtx.GetImpactLevelDistribution(ctx, store.GetImpactLevelDistributionParams{
+		UserID:      userUUID,
+		StartTime:   timeToPgTimestamptz(startDate),
+		StartTime_2: timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get impact level distribution: %w", err)
+	}
+	for _, dist := range impactDistribution {
+		impactLevels[models.ImpactLevel(dist.ImpactLevel)] = int(dist.EntryCount)
+	}
+
+	return valueRatings, impactLevels, nil
+}
+
+// weeklySummary returns one row per week, newest first
+func (s *AnalyticsService) weeklySummary(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, settings *models.AnalyticsSettings, startDate, endDate time.Time) ([]store.GetWeeklyActivitySummaryRow, error) {
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, settings.Location, startDate, endDate); ok {
+		rows, err := qtx.GetRollupWeeklySummary(ctx, store.GetRollupWeeklySummaryParams{
+			WeekOffset: weekOffset(settings.WeekStart),
+			UserID:     userUUID,
+			StartDate:  firstDay,
+			EndDate:    lastDay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get weekly activity summary: %w", err)
+		}
+
+		weeks := make([]store.GetWeeklyActivitySummaryRow, len(rows))
+		for i, row := range rows {
+			weeks[i] = store.GetWeeklyActivitySummaryRow(row)
+		}
+		return weeks, nil
+	}
+
+	weeks, err := qtx.GetWeeklyActivitySummary(ctx, store.GetWeeklyActivitySummaryParams{
+		Tz:         settings.Location.String(),
+		WeekOffset: weekOffset(settings.WeekStart),
+		UserID:     userUUID,
+		StartTime:  timeToPgTimestamptz(startDate),
+		EndTime:    timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly activity summary: %w", err)
+	}
+	return weeks, nil
+}
+
+// monthlySummary returns one row per month, newest first
+func (s *AnalyticsService) monthlySummary(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, settings *models.AnalyticsSettings, startDate, endDate time.Time) ([]store.GetMonthlyActivitySummaryRow, error) {
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, settings.Location, startDate, endDate); ok {
+		rows, err := qtx.GetRollupMonthlySummary(ctx, store.GetRollupMonthlySummaryParams{
+			UserID:    userUUID,
+			StartDate: firstDay,
+			EndDate:   lastDay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get monthly activity summary: %w", err)
+		}
+
+		months := make([]store.GetMonthlyActivitySummaryRow, len(rows))
+		for i, row := range rows {
+			months[i] = store.GetMonthlyActivitySummaryRow(row)
+		}
+		return months, nil
+	}
+
+	months, err := qtx.GetMonthlyActivitySummary(ctx, store.GetMonthlyActivitySummaryParams{
+		Tz:        settings.Location.String(),
+		UserID:    userUUID,
+		StartTime: timeToPgTimestamptz(startDate),
+		EndTime:   timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monthly activity summary: %w", err)
+	}
+	return months, nil
+}
+
+// dayOfWeekSummary returns one row per weekday with activity
+func (s *AnalyticsService) dayOfWeekSummary(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, settings *models.AnalyticsSettings, startDate, endDate time.Time) ([]store.GetProductivityByDayOfWeekRow, error) {
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, settings.Location, startDate, endDate); ok {
+		rows, err := qtx.GetRollupProductivityByDayOfWeek(ctx, store.GetRollupProductivityByDayOfWeekParams{
+			UserID:    userUUID,
+			StartDate: firstDay,
+			EndDate:   lastDay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get productivity by day of week: %w", err)
+		}
+
+		days := make([]store.GetProductivityByDayOfWeekRow, len(rows))
+		for i, row := range rows {
+			days[i] = store.GetProductivityByDayOfWeekRow(row)
+		}
+		return days, nil
+	}
+
+	days, err := qtx.GetProductivityByDayOfWeek(ctx, store.GetProductivityByDayOfWeekParams{
+		Tz:        settings.Location.String(),
+		UserID:    userUUID,
+		StartTime: timeToPgTimestamptz(startDate),
+		EndTime:   timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get productivity by day of week: %w", err)
+	}
+	return days, nil
+}
+
+// productivityTrend returns one row per day with activity, oldest first
+func (s *AnalyticsService) productivityTrend(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, settings *models.AnalyticsSettings, startDate, endDate time.Time) ([]store.GetUserProductivityTrendRow, error) {
+	if firstDay, lastDay, ok := s.rollupDays(ctx, qtx, userUUID, settings.Location, startDate, endDate); ok {
+		rows, err := qtx.GetRollupProductivityTrend(ctx, store.GetRollupProductivityTrendParams{
+			UserID:    userUUID,
+			StartDate: firstDay,
+			EndDate:   lastDay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user productivity trend: %w", err)
+		}
+
+		trend := make([]store.GetUserProductivityTrendRow, len(rows))
+		for i, row := range rows {
+			trend[i] = store.GetUserProductivityTrendRow(row)
+		}
+		return trend, nil
+	}
+
+	trend, err := qtx.GetUserProductivityTrend(ctx, store.GetUserProductivityTrendParams{
+		Tz:        settings.Location.String(),
+		UserID:    userUUID,
+		StartTime: timeToPgTimestamptz(startDate),
+		EndTime:   timeToPgTimestamptz(endDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user productivity trend: %w", err)
+	}
+	return trend, nil
+}
@@ -0,0 +1,216 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyticsService_DailyRollups tests that analytics read from the daily rollups match
+// the same analytics computed from log entries, as entries and the user's timezone change
+func TestAnalyticsService_DailyRollups(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	analyticsService := services.NewAnalyticsService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger).WithWriteNotifier(analyticsService.NotifyLogEntryWrite)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	// Same rules as America/Sao_Paulo under another name, so analytics scan log entries instead of rollups
+	alias, err := time.LoadLocation("Brazil/East")
+	require.NoError(t, err)
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "rollups@example.com",
+		Password:  "password123",
+		FirstName: "Roll",
+		LastName:  "Up",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Rollups",
+		Color:  "#123456",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	// Monday 2025-05-12 to Sunday 2025-05-18 in São Paulo
+	monday := time.Date(2025, 5, 12, 0, 0, 0, 0, saoPaulo)
+	start, end := monday, monday.AddDate(0, 0, 7).Add(-time.Nanosecond)
+
+	entries := []struct {
+		title   string
+		kind    models.ActivityType
+		at      time.Time
+		minutes int
+		rating  models.ValueRating
+		project bool
+	}{
+		{"API work", models.ActivityDevelopment, monday.Add(9 * time.Hour), 120, models.ValueHigh, true},
+		{"Standup", models.ActivityMeeting, monday.Add(11 * time.Hour), 15, models.ValueLow, false},
+		// 23:30 in São Paulo is already Wednesday in UTC
+		{"Late review", models.ActivityCodeReview, monday.AddDate(0, 0, 1).Add(23*time.Hour + 30*time.Minute), 20, models.ValueMedium, true},
+		{"Incident", models.ActivityDebugging, monday.AddDate(0, 0, 3).Add(14 * time.Hour), 90, models.ValueCritical, true},
+		{"Reading", models.ActivityLearning, monday.AddDate(0, 0, 6).Add(10 * time.Hour), 45, models.ValueMedium, false},
+	}
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		req := &models.LogEntryRequest{
+			Title:       e.title,
+			Type:        e.kind,
+			StartTime:   e.at,
+			EndTime:     e.at.Add(time.Duration(e.minutes) * time.Minute),
+			ValueRating: e.rating,
+			ImpactLevel: models.ImpactTeam,
+		}
+		if e.project {
+			req.ProjectID = &project.ID
+		}
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, req)
+		require.NoError(t, err)
+		ids[i] = entry.ID.String()
+	}
+
+	periods := func(summaries []*models.PeriodSummary) []string {
+		rows := make([]string, len(summaries))
+		for i, p := range summaries {
+			rows[i] = fmt.Sprintf("%s entries=%d minutes=%d avg=%.2f projects=%d days=%d",
+				p.PeriodStart.Format("2006-01-02"), p.EntryCount, p.TotalMinutes, p.AvgMinutes, p.ProjectsCount, p.ActiveDays)
+		}
+		return rows
+	}
+
+	// assertSamePaths compares analytics answered from rollups with the same analytics
+	// answered from log entries, and returns the rollup trend
+	assertSamePaths := func(t *testing.T, profile, live *time.Location) []*models.ProductivityTrendPoint {
+		rollupStart, rollupEnd := time.Date(2025, 5, 12, 0, 0, 0, 0, profile), time.Date(2025, 5, 19, 0, 0, 0, 0, profile).Add(-time.Nanosecond)
+		liveSettings := &models.AnalyticsSettings{Location: live, WeekStart: models.WeekStartMonday}
+
+		weekly, err := analyticsService.GetWeeklyActivitySummary(ctx, userID, rollupStart, rollupEnd, nil)
+		require.NoError(t, err)
+		weeklyLive, err := analyticsService.GetWeeklyActivitySummary(ctx, userID, rollupStart, rollupEnd, liveSettings)
+		require.NoError(t, err)
+		assert.Equal(t, periods(weeklyLive), periods(weekly))
+
+		monthly, err := analyticsService.GetMonthlyActivitySummary(ctx, userID, rollupStart, rollupEnd, nil)
+		require.NoError(t, err)
+		monthlyLive, err := analyticsService.GetMonthlyActivitySummary(ctx, userID, rollupStart, rollupEnd, liveSettings)
+		require.NoError(t, err)
+		assert.Equal(t, periods(monthlyLive), periods(monthly))
+
+		byDay, err := analyticsService.GetProductivityByDayOfWeek(ctx, userID, rollupStart, rollupEnd, nil)
+		require.NoError(t, err)
+		byDayLive, err := analyticsService.GetProductivityByDayOfWeek(ctx, userID, rollupStart, rollupEnd, liveSettings)
+		require.NoError(t, err)
+		assert.Equal(t, byDayLive, byDay)
+
+		trend, err := analyticsService.GetProductivityTrend(ctx, userID, rollupStart, rollupEnd, nil)
+		require.NoError(t, err)
+		trendLive, err := analyticsService.GetProductivityTrend(ctx, userID, rollupStart, rollupEnd, liveSettings)
+		require.NoError(t, err)
+		assert.Equal(t, trendLive, trend)
+
+		// Ending a microsecond early is no longer a whole day, so log entries are scanned
+		metrics, err := analyticsService.GetProductivityMetrics(ctx, userID, rollupStart, rollupEnd)
+		require.NoError(t, err)
+		metricsLive, err := analyticsService.GetProductivityMetrics(ctx, userID, rollupStart, rollupEnd.Add(-time.Microsecond))
+		require.NoError(t, err)
+		assert.Equal(t, metricsLive.TotalActivities, metrics.TotalActivities)
+		assert.Equal(t, metricsLive.TotalMinutes, metrics.TotalMinutes)
+		assert.Equal(t, metricsLive.HighValueActivities, metrics.HighValueActivities)
+		assert.Equal(t, metricsLive.ActivityBreakdown, metrics.ActivityBreakdown)
+		assert.Equal(t, metricsLive.ValueDistribution, metrics.ValueDistribution)
+		assert.Equal(t, metricsLive.ImpactDistribution, metrics.ImpactDistribution)
+
+		summary, err := analyticsService.GetActivitySummary(ctx, userID, rollupStart, rollupEnd)
+		require.NoError(t, err)
+		summaryLive, err := analyticsService.GetActivitySummary(ctx, userID, rollupStart, rollupEnd.Add(-time.Microsecond))
+		require.NoError(t, err)
+		require.Len(t, summary, len(summaryLive))
+		for i := range summary {
+			assert.Equal(t, summaryLive[i].Type, summary[i].Type)
+			assert.Equal(t, summaryLive[i].Count, summary[i].Count)
+			assert.Equal(t, summaryLive[i].TotalMinutes, summary[i].TotalMinutes)
+			assert.InDelta(t, summaryLive[i].AvgMinutes, summary[i].AvgMinutes, 0.001)
+		}
+
+		return trend
+	}
+
+	t.Run("AfterCreate", func(t *testing.T) {
+		trend := assertSamePaths(t, saoPaulo, alias)
+		require.Len(t, trend, 7)
+		assert.Equal(t, 135, trend[0].TotalMinutes)
+		assert.Equal(t, 20, trend[1].TotalMinutes, "the late review stays on Tuesday in São Paulo")
+		assert.Zero(t, trend[2].TotalMinutes)
+
+		metrics, err := analyticsService.GetProductivityMetrics(ctx, userID, start, end)
+		require.NoError(t, err)
+		assert.Equal(t, 5, metrics.TotalActivities)
+		assert.Equal(t, 290, metrics.TotalMinutes)
+		assert.Equal(t, 2, metrics.HighValueActivities)
+	})
+
+	t.Run("AfterUpdateAndDelete", func(t *testing.T) {
+		incident := entries[3]
+		_, err := logEntryService.UpdateLogEntry(ctx, userID, ids[3], &models.LogEntryRequest{
+			Title:       incident.title,
+			Type:        models.ActivityMaintenance,
+			ProjectID:   &project.ID,
+			StartTime:   incident.at.AddDate(0, 0, 1),
+			EndTime:     incident.at.AddDate(0, 0, 1).Add(60 * time.Minute),
+			ValueRating: models.ValueHigh,
+			ImpactLevel: models.ImpactCompany,
+		})
+		require.NoError(t, err)
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, ids[1]))
+
+		trend := assertSamePaths(t, saoPaulo, alias)
+		assert.Equal(t, 120, trend[0].TotalMinutes)
+		assert.Zero(t, trend[3].TotalMinutes)
+		assert.Equal(t, 60, trend[4].TotalMinutes)
+
+		_, err = logEntryService.RestoreFromTrash(ctx, userID, ids[1])
+		require.NoError(t, err)
+		trend = assertSamePaths(t, saoPaulo, alias)
+		assert.Equal(t, 135, trend[0].TotalMinutes)
+	})
+
+	t.Run("AfterTimezoneChange", func(t *testing.T) {
+		_, err := userService.UpdateUserProfile(ctx, userID, &models.UserProfileRequest{
+			FirstName: "Roll",
+			LastName:  "Up",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+
+		etcUTC, err := time.LoadLocation("Etc/UTC")
+		require.NoError(t, err)
+		trend := assertSamePaths(t, time.UTC, etcUTC)
+		assert.Zero(t, trend[1].TotalMinutes, "the late review moved to Wednesday in UTC")
+		assert.Equal(t, 20, trend[2].TotalMinutes)
+	})
+
+	t.Run("RefreshSummary", func(t *testing.T) {
+		require.NoError(t, analyticsService.RefreshUserActivitySummary(ctx))
+	})
+}
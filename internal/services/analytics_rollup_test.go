@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWholeDays(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	midnight := time.Date(2025, 5, 12, 0, 0, 0, 0, saoPaulo)
+	endOfDay := func(t time.Time) time.Time { return t.AddDate(0, 0, 1).Add(-time.Nanosecond) }
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		loc       *time.Location
+		ok        bool
+		wantFirst string
+		wantLast  string
+	}{
+		{"single day", midnight, endOfDay(midnight), saoPaulo, true, "2025-05-12", "2025-05-12"},
+		{"a week", midnight, endOfDay(midnight.AddDate(0, 0, 6)), saoPaulo, true, "2025-05-12", "2025-05-18"},
+		{"database precision", midnight, midnight.AddDate(0, 0, 1).Add(-time.Microsecond), saoPaulo, true, "2025-05-12", "2025-05-12"},
+		{"same instant in UTC", midnight.UTC(), endOfDay(midnight).UTC(), saoPaulo, true, "2025-05-12", "2025-05-12"},
+		{"starts mid-day", midnight.Add(9 * time.Hour), endOfDay(midnight), saoPaulo, false, "", ""},
+		{"ends at midnight", midnight, midnight.AddDate(0, 0, 1), saoPaulo, false, "", ""},
+		{"ends mid-day", midnight, midnight.Add(17 * time.Hour), saoPaulo, false, "", ""},
+		{"other timezone", midnight, endOfDay(midnight), time.UTC, false, "", ""},
+		{"end before start", midnight, endOfDay(midnight.AddDate(0, 0, -2)), saoPaulo, false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, ok := wholeDays(tt.start, tt.end, tt.loc)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.wantFirst, first.Format("2006-01-02"))
+				assert.Equal(t, tt.wantLast, last.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestAnalyticsService_SummaryRefreshSettings(t *testing.T) {
+	analyticsService := NewAnalyticsService(nil, logging.NewTestLogger())
+	assert.Equal(t, defaultSummaryRefreshInterval, analyticsService.refreshInterval)
+	assert.Equal(t, defaultSummaryRefreshDebounce, analyticsService.refreshDebounce)
+
+	analyticsService.WithSummaryRefresh(time.Hour, 0)
+	assert.Equal(t, time.Hour, analyticsService.refreshInterval)
+	assert.Equal(t, defaultSummaryRefreshDebounce, analyticsService.refreshDebounce)
+
+	// Notifications never block, and a burst folds into one pending refresh
+	for range 5 {
+		analyticsService.NotifyLogEntryWrite()
+	}
+	assert.Len(t, analyticsService.writes, 1)
+}
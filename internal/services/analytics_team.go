@@ -0,0 +1,248 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// minAnonymousContributors is the smallest group of anonymous members whose activity
+// is counted, in the team totals as in each project and activity type: a lone anonymous
+// member could be singled out by subtracting the named ones
+const minAnonymousContributors = 2
+
+// GetTeamAnalytics aggregates the activity logged between startDate and endDate on
+// the projects shared with a team. Only owners and managers may see it; members
+// are listed by name or counted anonymously according to their team sharing preference.
+func (s *AnalyticsService) GetTeamAnalytics(ctx context.Context, userID, teamID string, startDate, endDate time.Time) (*models.TeamAnalytics, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTeamAnalytics", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	teamUUID, err := uuid.Parse(teamID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid team ID format in GetTeamAnalytics", "user_id", userID, "team_id", teamID)
+		return nil, fmt.Errorf("invalid team ID: %w", err)
+	}
+
+	if err := s.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Getting team analytics", "user_id", userID, "team_id", teamID, "start_date", startDate, "end_date", endDate)
+
+	var analytics *models.TeamAnalytics
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionViewAnalytics); err != nil {
+			return err
+		}
+
+		team, err := qtx.GetTeamByID(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team: %w", err)
+		}
+
+		members, err := qtx.GetTeamMembers(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team members: %w", err)
+		}
+
+		projects, err := qtx.GetTeamProjects(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team projects: %w", err)
+		}
+
+		activity, err := qtx.GetTeamActivity(ctx, store.GetTeamActivityParams{
+			TeamID:    teamUUID,
+			StartTime: timeToPgTimestamptz(startDate),
+			EndTime:   timeToPgTimestamptz(endDate),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get team activity: %w", err)
+		}
+
+		analytics = buildTeamAnalytics(team, members, projects, activity)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get team analytics", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	s.logger.Info("Successfully retrieved team analytics", "user_id", userID, "team_id", teamID, "contributors", analytics.Contributors)
+	return analytics, nil
+}
+
+// buildTeamAnalytics aggregates the activity rows of the members whose sharing
+// preference allows it
+func buildTeamAnalytics(team store.Team, members []store.GetTeamMembersRow, projects []store.Project, activity []store.GetTeamActivityRow) *models.TeamAnalytics {
+	analytics := &models.TeamAnalytics{
+		TeamID:     team.ID,
+		TeamName:   team.Name,
+		Projects:   []*models.TeamProjectActivity{},
+		Activities: []*models.TeamActivityBreakdown{},
+		Members:    []*models.TeamMemberActivity{},
+	}
+
+	active := make(map[uuid.UUID]bool)
+	for _, row := range activity {
+		active[row.UserID] = true
+	}
+
+	sharing := make(map[uuid.UUID]models.TeamSharing, len(members))
+	anonymous := 0
+	for _, member := range members {
+		var preferences map[string]any
+		if len(member.Preferences) > 0 {
+			_ = json.Unmarshal(member.Preferences, &preferences)
+		}
+		sharing[member.UserID] = models.TeamSharingFromPreferences(preferences)
+		if sharing[member.UserID] == models.TeamSharingAnonymous && active[member.UserID] {
+			anonymous++
+		}
+	}
+
+	counted := func(userID uuid.UUID) bool {
+		switch sharing[userID] {
+		case models.TeamSharingNamed:
+			return true
+		case models.TeamSharingAnonymous:
+			return anonymous >= minAnonymousContributors
+		}
+		return false
+	}
+	for _, member := range members {
+		if !counted(member.UserID) {
+			analytics.HiddenMembers++
+		}
+	}
+
+	byProject := make(map[uuid.UUID]*models.TeamProjectActivity, len(projects))
+	for _, project := range projects {
+		byProject[project.ID] = &models.TeamProjectActivity{ProjectID: project.ID, ProjectName: project.Name}
+		analytics.Projects = append(analytics.Projects, byProject[project.ID])
+	}
+
+	byMember := make(map[uuid.UUID]*models.TeamMemberActivity)
+	for _, member := range members {
+		if sharing[member.UserID] == models.TeamSharingNamed {
+			byMember[member.UserID] = &models.TeamMemberActivity{
+				UserID: member.UserID,
+				Name:   strings.TrimSpace(member.FirstName + " " + member.LastName),
+				Role:   models.TeamRole(member.Role),
+			}
+			analytics.Members = append(analytics.Members, byMember[member.UserID])
+		}
+	}
+
+	// Anonymous activity only shows in a project or activity type that enough
+	// anonymous members contributed to
+	anonymousByProject := make(map[uuid.UUID]map[uuid.UUID]bool)
+	anonymousByType := make(map[models.ActivityType]map[uuid.UUID]bool)
+	for _, row := range activity {
+		if sharing[row.UserID] != models.TeamSharingAnonymous || !counted(row.UserID) || !row.ProjectID.Valid {
+			continue
+		}
+		projectID, activityType := uuid.UUID(row.ProjectID.Bytes), models.ActivityType(row.Type)
+		if anonymousByProject[projectID] == nil {
+			anonymousByProject[projectID] = make(map[uuid.UUID]bool)
+		}
+		anonymousByProject[projectID][row.UserID] = true
+		if anonymousByType[activityType] == nil {
+			anonymousByType[activityType] = make(map[uuid.UUID]bool)
+		}
+		anonymousByType[activityType][row.UserID] = true
+	}
+	shown := func(userID uuid.UUID, anonymous map[uuid.UUID]bool) bool {
+		return sharing[userID] == models.TeamSharingNamed || len(anonymous) >= minAnonymousContributors
+	}
+
+	minutesByType := make(map[models.ActivityType]int)
+	contributors := make(map[uuid.UUID]bool)
+	projectContributors := make(map[uuid.UUID]map[uuid.UUID]bool)
+	memberProjects := make(map[uuid.UUID]map[uuid.UUID]bool)
+
+	for _, row := range activity {
+		if !counted(row.UserID) || !row.ProjectID.Valid {
+			continue
+		}
+		projectID, activityType := uuid.UUID(row.ProjectID.Bytes), models.ActivityType(row.Type)
+		entries, minutes := int(row.EntryCount), int(row.TotalMinutes)
+
+		analytics.TotalEntries += entries
+		analytics.TotalMinutes += minutes
+		contributors[row.UserID] = true
+
+		if shown(row.UserID, anonymousByType[activityType]) {
+			minutesByType[activityType] += minutes
+		}
+
+		if project, ok := byProject[projectID]; ok && shown(row.UserID, anonymousByProject[projectID]) {
+			project.EntryCount += entries
+			project.TotalMinutes += minutes
+			if projectContributors[projectID] == nil {
+				projectContributors[projectID] = make(map[uuid.UUID]bool)
+			}
+			projectContributors[projectID][row.UserID] = true
+		}
+
+		if member, ok := byMember[row.UserID]; ok {
+			member.EntryCount += entries
+			member.TotalMinutes += minutes
+			if memberProjects[row.UserID] == nil {
+				memberProjects[row.UserID] = make(map[uuid.UUID]bool)
+			}
+			memberProjects[row.UserID][projectID] = true
+		}
+	}
+
+	analytics.Contributors = len(contributors)
+	analytics.TotalHours = minutesToHours(analytics.TotalMinutes)
+
+	for _, project := range analytics.Projects {
+		project.TotalHours = minutesToHours(project.TotalMinutes)
+		project.Contributors = len(projectContributors[project.ProjectID])
+	}
+	slices.SortStableFunc(analytics.Projects, func(a, b *models.TeamProjectActivity) int {
+		return b.TotalMinutes - a.TotalMinutes
+	})
+
+	for _, member := range analytics.Members {
+		member.TotalHours = minutesToHours(member.TotalMinutes)
+		member.Projects = len(memberProjects[member.UserID])
+	}
+	slices.SortStableFunc(analytics.Members, func(a, b *models.TeamMemberActivity) int {
+		return b.TotalMinutes - a.TotalMinutes
+	})
+
+	for activityType, minutes := range minutesByType {
+		percentage := 0.0
+		if analytics.TotalMinutes > 0 {
+			percentage = math.Round(float64(minutes)*10000/float64(analytics.TotalMinutes)) / 100
+		}
+		analytics.Activities = append(analytics.Activities, &models.TeamActivityBreakdown{
+			ActivityType: activityType,
+			TotalMinutes: minutes,
+			Percentage:   percentage,
+		})
+	}
+	slices.SortFunc(analytics.Activities, func(a, b *models.TeamActivityBreakdown) int {
+		if a.TotalMinutes != b.TotalMinutes {
+			return b.TotalMinutes - a.TotalMinutes
+		}
+		return cmp.Compare(a.ActivityType, b.ActivityType)
+	})
+
+	return analytics
+}
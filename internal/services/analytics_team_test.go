@@ -0,0 +1,143 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTeamAnalytics(t *testing.T) {
+	team := store.Team{ID: uuid.New(), Name: "Platform"}
+	api := store.Project{ID: uuid.New(), Name: "API"}
+	web := store.Project{ID: uuid.New(), Name: "Web"}
+	projects := []store.Project{api, web}
+
+	member := func(first string, role models.TeamRole, sharing string) store.GetTeamMembersRow {
+		row := store.GetTeamMembersRow{UserID: uuid.New(), Role: string(role), FirstName: first, LastName: "Doe"}
+		if sharing != "" {
+			row.Preferences = []byte(`{"team_sharing":"` + sharing + `"}`)
+		}
+		return row
+	}
+	row := func(user uuid.UUID, project store.Project, kind models.ActivityType, entries, minutes int32) store.GetTeamActivityRow {
+		return store.GetTeamActivityRow{
+			UserID:       user,
+			ProjectID:    pgtype.UUID{Bytes: project.ID, Valid: true},
+			Type:         string(kind),
+			EntryCount:   entries,
+			TotalMinutes: minutes,
+		}
+	}
+
+	owner := member("Olivia", models.TeamRoleOwner, "named")
+	dev := member("Dmitri", models.TeamRoleMember, "named")
+	quiet := member("Quinn", models.TeamRoleMember, "anonymous")
+	hidden := member("Priya", models.TeamRoleMember, "private")
+
+	t.Run("named, lone anonymous and private members", func(t *testing.T) {
+		members := []store.GetTeamMembersRow{owner, dev, quiet, hidden}
+		activity := []store.GetTeamActivityRow{
+			row(owner.UserID, api, models.ActivityMeeting, 2, 60),
+			row(dev.UserID, api, models.ActivityDevelopment, 3, 240),
+			row(dev.UserID, web, models.ActivityDevelopment, 1, 60),
+			row(quiet.UserID, web, models.ActivityDevelopment, 2, 120),
+			row(hidden.UserID, api, models.ActivityDevelopment, 5, 300),
+		}
+
+		analytics := buildTeamAnalytics(team, members, projects, activity)
+
+		assert.Equal(t, team.ID, analytics.TeamID)
+		assert.Equal(t, "Platform", analytics.TeamName)
+		assert.Equal(t, 6, analytics.TotalEntries)
+		assert.Equal(t, 360, analytics.TotalMinutes)
+		assert.Equal(t, 6.0, analytics.TotalHours)
+		assert.Equal(t, 2, analytics.Contributors)
+		assert.Equal(t, 2, analytics.HiddenMembers, "a lone anonymous member and the private one are left out")
+
+		require.Len(t, analytics.Projects, 2)
+		assert.Equal(t, "API", analytics.Projects[0].ProjectName)
+		assert.Equal(t, 300, analytics.Projects[0].TotalMinutes)
+		assert.Equal(t, 2, analytics.Projects[0].Contributors)
+		assert.Equal(t, "Web", analytics.Projects[1].ProjectName)
+		assert.Equal(t, 60, analytics.Projects[1].TotalMinutes)
+		assert.Equal(t, 1, analytics.Projects[1].Contributors)
+
+		require.Len(t, analytics.Activities, 2)
+		assert.Equal(t, models.ActivityDevelopment, analytics.Activities[0].ActivityType)
+		assert.Equal(t, 83.33, analytics.Activities[0].Percentage)
+		assert.Equal(t, models.ActivityMeeting, analytics.Activities[1].ActivityType)
+		assert.Equal(t, 16.67, analytics.Activities[1].Percentage)
+
+		require.Len(t, analytics.Members, 2)
+		assert.Equal(t, "Dmitri Doe", analytics.Members[0].Name)
+		assert.Equal(t, 300, analytics.Members[0].TotalMinutes)
+		assert.Equal(t, 2, analytics.Members[0].Projects)
+		assert.Equal(t, "Olivia Doe", analytics.Members[1].Name)
+		assert.Equal(t, models.TeamRoleOwner, analytics.Members[1].Role)
+	})
+
+	t.Run("anonymous members counted as a group", func(t *testing.T) {
+		other := member("Ana", models.TeamRoleViewer, "anonymous")
+		members := []store.GetTeamMembersRow{dev, quiet, other}
+		activity := []store.GetTeamActivityRow{
+			row(dev.UserID, api, models.ActivityDevelopment, 1, 60),
+			row(quiet.UserID, web, models.ActivityCodeReview, 1, 30),
+			row(other.UserID, web, models.ActivityCodeReview, 1, 30),
+		}
+
+		analytics := buildTeamAnalytics(team, members, projects, activity)
+
+		assert.Equal(t, 120, analytics.TotalMinutes)
+		assert.Equal(t, 3, analytics.Contributors)
+		assert.Zero(t, analytics.HiddenMembers)
+		require.Len(t, analytics.Members, 1, "anonymous members are not listed")
+		assert.Equal(t, dev.UserID, analytics.Members[0].UserID)
+	})
+
+	t.Run("lone anonymous member in a project or activity type", func(t *testing.T) {
+		other := member("Ana", models.TeamRoleViewer, "anonymous")
+		members := []store.GetTeamMembersRow{dev, quiet, other}
+		activity := []store.GetTeamActivityRow{
+			row(dev.UserID, api, models.ActivityDevelopment, 1, 60),
+			row(quiet.UserID, api, models.ActivityDevelopment, 1, 30),
+			row(quiet.UserID, web, models.ActivityCodeReview, 1, 30),
+			row(other.UserID, web, models.ActivityCodeReview, 1, 30),
+		}
+
+		analytics := buildTeamAnalytics(team, members, projects, activity)
+
+		assert.Equal(t, 150, analytics.TotalMinutes)
+		assert.Equal(t, 3, analytics.Contributors)
+
+		require.Len(t, analytics.Projects, 2)
+		assert.Equal(t, "API", analytics.Projects[0].ProjectName)
+		assert.Equal(t, 60, analytics.Projects[0].TotalMinutes, "the only anonymous member on the project is left out")
+		assert.Equal(t, 1, analytics.Projects[0].Contributors)
+		assert.Equal(t, "Web", analytics.Projects[1].ProjectName)
+		assert.Equal(t, 60, analytics.Projects[1].TotalMinutes)
+		assert.Equal(t, 2, analytics.Projects[1].Contributors)
+
+		minutes := map[models.ActivityType]int{}
+		for _, breakdown := range analytics.Activities {
+			minutes[breakdown.ActivityType] = breakdown.TotalMinutes
+		}
+		assert.Equal(t, 60, minutes[models.ActivityDevelopment], "the only anonymous member of the type is left out")
+		assert.Equal(t, 60, minutes[models.ActivityCodeReview])
+	})
+
+	t.Run("no activity", func(t *testing.T) {
+		analytics := buildTeamAnalytics(team, []store.GetTeamMembersRow{owner}, nil, nil)
+
+		assert.Zero(t, analytics.TotalMinutes)
+		assert.Zero(t, analytics.Contributors)
+		assert.Empty(t, analytics.Projects)
+		assert.Empty(t, analytics.Activities)
+		require.Len(t, analytics.Members, 1)
+		assert.Zero(t, analytics.Members[0].TotalMinutes)
+	})
+}
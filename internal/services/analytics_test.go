@@ -5,7 +5,11 @@ import (
 	"time"
 
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestAnalyticsService_ValidationMethods tests validation functions
@@ -216,6 +220,92 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 	})
 }
 
+// TestPercentChange tests the period over period change used by comparison stats
+func TestPercentChange(t *testing.T) {
+	change := percentChange(150, 100)
+	require.NotNil(t, change)
+	assert.Equal(t, 50.0, *change)
+
+	change = percentChange(1, 3)
+	require.NotNil(t, change)
+	assert.Equal(t, -66.67, *change)
+
+	assert.Nil(t, percentChange(10, 0), "growth from nothing has no percentage")
+}
+
+// TestBuildActivityHeatmap tests folding daily patterns into the day by hour grid
+func TestBuildActivityHeatmap(t *testing.T) {
+	t.Run("cells, totals and peaks", func(t *testing.T) {
+		heatmap := buildActivityHeatmap([]store.GetDailyActivityPatternRow{
+			{DayOfWeek: 1, HourOfDay: 9, EntryCount: 2, TotalMinutes: 90},
+			{DayOfWeek: 1, HourOfDay: 9, EntryCount: 1, TotalMinutes: 30},
+			{DayOfWeek: 3, HourOfDay: 14, EntryCount: 1, TotalMinutes: 60},
+			{DayOfWeek: 7, HourOfDay: 9, EntryCount: 1, TotalMinutes: 999},
+		})
+
+		assert.Equal(t, 120, heatmap.Minutes[1][9], "the same slot on different dates adds up")
+		assert.Equal(t, 3, heatmap.Entries[1][9])
+		assert.Equal(t, 60, heatmap.Minutes[3][14])
+		assert.Equal(t, 120, heatmap.DayTotals[1])
+		assert.Equal(t, 60, heatmap.HourTotals[14])
+		assert.Equal(t, 180, heatmap.TotalMinutes, "out of range slots are ignored")
+		assert.Equal(t, 4, heatmap.TotalEntries)
+		require.NotNil(t, heatmap.PeakDay)
+		require.NotNil(t, heatmap.PeakHour)
+		assert.Equal(t, 1, *heatmap.PeakDay)
+		assert.Equal(t, 9, *heatmap.PeakHour)
+	})
+
+	t.Run("no activity has no peak", func(t *testing.T) {
+		heatmap := buildActivityHeatmap(nil)
+		assert.Zero(t, heatmap.TotalMinutes)
+		assert.Nil(t, heatmap.PeakDay)
+		assert.Nil(t, heatmap.PeakHour)
+	})
+}
+
+// TestFillProductivityTrend tests that the trend has a point for every day in the range
+func TestFillProductivityTrend(t *testing.T) {
+	startDay := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	trend := fillProductivityTrend([]store.GetUserProductivityTrendRow{
+		{
+			ActivityDate:      pgtype.Date{Time: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), Valid: true},
+			TotalMinutes:      120,
+			EntryCount:        2,
+			AvgValueScore:     3,
+			ProductivityScore: 3.6,
+		},
+	}, startDay, endDay)
+
+	require.Len(t, trend, 4)
+	assert.Equal(t, "2024-02-28", trend[0].Date)
+	assert.Zero(t, trend[0].TotalMinutes)
+	assert.Equal(t, "2024-02-29", trend[1].Date)
+	assert.Equal(t, 120, trend[1].TotalMinutes)
+	assert.Equal(t, 3.6, trend[1].ProductivityScore)
+	assert.Equal(t, "2024-03-02", trend[3].Date)
+}
+
+// TestWeekOffset tests the shift applied to ISO weeks for each week start
+func TestWeekOffset(t *testing.T) {
+	assert.Equal(t, int32(0), weekOffset(models.WeekStartMonday))
+	assert.Equal(t, int32(1), weekOffset(models.WeekStartSunday))
+	assert.Equal(t, int32(0), weekOffset(""))
+}
+
+// TestPgDateInLocation tests that bucket dates become local midnights
+func TestPgDateInLocation(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	date := pgtype.Date{Time: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), Valid: true}
+	local := pgDateInLocation(date, saoPaulo)
+	assert.Equal(t, time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC), local.UTC())
+	assert.True(t, pgDateInLocation(pgtype.Date{}, saoPaulo).IsZero())
+}
+
 // Helper functions for testing (these might not exist in the actual service)
 func calculatePercentageChange(current, previous float64) float64 {
 	if previous == 0 {
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/garnizeh/englog/internal/blobstore"
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// attachmentSweepInterval is how often blobs of purged log entries are cleaned up
+	attachmentSweepInterval = time.Hour
+	// attachmentSweepBatch bounds how many orphaned attachments one sweep removes
+	attachmentSweepBatch = 100
+	// maxAttachmentFileNameLength mirrors the log_entry_attachments.file_name column size
+	maxAttachmentFileNameLength = 255
+)
+
+// allowedAttachmentTypes lists the content types accepted for attachments
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":        true,
+	"image/jpeg":       true,
+	"image/gif":        true,
+	"image/webp":       true,
+	"application/pdf":  true,
+	"application/json": true,
+	"text/plain":       true,
+	"text/markdown":    true,
+	"text/csv":         true,
+}
+
+// AttachmentService handles files attached to log entries
+type AttachmentService struct {
+	db         *database.DB
+	logger     *logging.Logger
+	blobs      blobstore.Store
+	maxBytes   int64
+	quotaBytes int64
+}
+
+// NewAttachmentService creates a new AttachmentService instance.
+// maxBytes limits a single file and quotaBytes the total storage of one user.
+func NewAttachmentService(db *database.DB, logger *logging.Logger, blobs blobstore.Store, maxBytes, quotaBytes int64) *AttachmentService {
+	return &AttachmentService{
+		db:         db,
+		logger:     logger.WithComponent("attachment_service"),
+		blobs:      blobs,
+		maxBytes:   maxBytes,
+		quotaBytes: quotaBytes,
+	}
+}
+
+// MaxBytes returns the size limit of a single attachment
+func (s *AttachmentService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload stores a file and attaches it to a log entry owned by the user
+func (s *AttachmentService) Upload(ctx context.Context, userID, logEntryID, fileName, contentType string, r io.Reader) (*models.Attachment, error) {
+	userUUID, entryUUID, err := s.parseIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName = sanitizeFileName(fileName)
+	if fileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	size := int64(len(data))
+	if size == 0 {
+		return nil, fmt.Errorf("attachment is empty")
+	}
+	if size > s.maxBytes {
+		return nil, fmt.Errorf("attachment exceeds maximum size of %d bytes", s.maxBytes)
+	}
+
+	contentType = normalizeContentType(contentType, data)
+	if !allowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("unsupported attachment content type: %s", contentType)
+	}
+
+	// Fail fast before writing the blob; the check is repeated inside the insert transaction
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		return s.checkUploadAllowed(ctx, qtx, userUUID, entryUUID, size)
+	}); err != nil {
+		return nil, err
+	}
+
+	attachmentID := uuid.New()
+	storageKey := userUUID.String() + "/" + attachmentID.String()
+
+	if err := s.blobs.Put(ctx, storageKey, bytes.NewReader(data), size, contentType); err != nil {
+		s.logger.LogError(ctx, err, "Failed to store attachment blob", "user_id", userID, "log_entry_id", logEntryID)
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	var attachment *models.Attachment
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		// Concurrent uploads of the user wait here, so each one sees the usage of the others
+		if err := qtx.LockUserAttachmentQuota(ctx, userUUID); err != nil {
+			return fmt.Errorf("failed to lock attachment quota: %w", err)
+		}
+		if err := s.checkUploadAllowed(ctx, qtx, userUUID, entryUUID, size); err != nil {
+			return err
+		}
+
+		row, err := qtx.CreateAttachment(ctx, store.CreateAttachmentParams{
+			ID:          attachmentID,
+			LogEntryID:  entryUUID,
+			UserID:      userUUID,
+			FileName:    fileName,
+			ContentType: contentType,
+			SizeBytes:   size,
+			StorageKey:  storageKey,
+		})
+		if err != nil {
+			return err
+		}
+		attachment = attachmentToModel(row)
+		return nil
+	}); err != nil {
+		if delErr := s.blobs.Delete(context.WithoutCancel(ctx), storageKey); delErr != nil {
+			s.logger.LogError(ctx, delErr, "Failed to remove blob of rejected attachment", "storage_key", storageKey)
+		}
+		s.logger.LogError(ctx, err, "Failed to create attachment", "user_id", userID, "log_entry_id", logEntryID)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	s.logger.Info("Attachment uploaded",
+		"user_id", userID,
+		"log_entry_id", logEntryID,
+		"attachment_id", attachment.ID,
+		"size_bytes", size)
+
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of a log entry owned by the user
+func (s *AttachmentService) ListAttachments(ctx context.Context, userID, logEntryID string) ([]*models.Attachment, error) {
+	userUUID, entryUUID, err := s.parseIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []*models.Attachment
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if err := ensureLogEntryOwned(ctx, qtx, userUUID, entryUUID); err != nil {
+			return err
+		}
+
+		rows, err := qtx.GetAttachmentsForLogEntry(ctx, store.GetAttachmentsForLogEntryParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		attachments = make([]*models.Attachment, len(rows))
+		for i, row := range rows {
+			attachments[i] = attachmentToModel(row)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to list attachments", "user_id", userID, "log_entry_id", logEntryID)
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Download opens the content of an attachment. The caller must close the reader.
+func (s *AttachmentService) Download(ctx context.Context, userID, logEntryID, attachmentID string) (*models.Attachment, io.ReadCloser, error) {
+	row, err := s.getAttachment(ctx, userID, logEntryID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, row.StorageKey)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to read attachment blob", "attachment_id", attachmentID, "storage_key", row.StorageKey)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, nil, fmt.Errorf("attachment content not found")
+		}
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	return attachmentToModel(row), content, nil
+}
+
+// DeleteAttachment removes an attachment and its stored content
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, logEntryID, attachmentID string) error {
+	row, err := s.getAttachment(ctx, userID, logEntryID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteAttachment(ctx, store.DeleteAttachmentParams{
+			ID:     row.ID,
+			UserID: row.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("attachment not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete attachment", "user_id", userID, "attachment_id", attachmentID)
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	// The row is gone, so a leftover blob is unreachable; log and move on
+	if err := s.blobs.Delete(ctx, row.StorageKey); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete attachment blob", "attachment_id", attachmentID, "storage_key", row.StorageKey)
+	}
+
+	s.logger.Info("Attachment deleted", "user_id", userID, "attachment_id", attachmentID)
+	return nil
+}
+
+// GetUsage reports the user's attachment storage consumption against the quota
+func (s *AttachmentService) GetUsage(ctx context.Context, userID string) (*models.AttachmentUsage, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetUsage", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var used int64
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		used, err = qtx.GetUserAttachmentUsage(ctx, userUUID)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get attachment usage", "user_id", userID)
+		return nil, fmt.Errorf("failed to get attachment usage: %w", err)
+	}
+
+	return &models.AttachmentUsage{UsedBytes: used, QuotaBytes: s.quotaBytes}, nil
+}
+
+// SweepOrphans deletes attachments whose log entry has been purged
+func (s *AttachmentService) SweepOrphans(ctx context.Context) (int, error) {
+	var orphans []store.LogEntryAttachment
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		orphans, err = qtx.GetOrphanedAttachments(ctx, attachmentSweepBatch)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to find orphaned attachments: %w", err)
+	}
+
+	removed := 0
+	for _, orphan := range orphans {
+		if err := s.blobs.Delete(ctx, orphan.StorageKey); err != nil {
+			s.logger.LogError(ctx, err, "Failed to delete orphaned attachment blob", "attachment_id", orphan.ID)
+			continue
+		}
+
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			_, err := qtx.DeleteAttachment(ctx, store.DeleteAttachmentParams{
+				ID:     orphan.ID,
+				UserID: orphan.UserID,
+			})
+			return err
+		}); err != nil {
+			return removed, fmt.Errorf("failed to delete orphaned attachment: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// StartOrphanSweeper periodically removes attachments of purged log entries until ctx is cancelled
+func (s *AttachmentService) StartOrphanSweeper(ctx context.Context) {
+	ticker := time.NewTicker(attachmentSweepInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Attachment orphan sweeper started", "interval", attachmentSweepInterval.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Attachment orphan sweeper stopped")
+			return
+		case <-ticker.C:
+			removed, err := s.SweepOrphans(ctx)
+			if err != nil {
+				s.logger.LogError(ctx, err, "Failed to sweep orphaned attachments")
+			} else if removed > 0 {
+				s.logger.Info("Orphaned attachments removed", "attachments_removed", removed)
+			}
+		}
+	}
+}
+
+// checkUploadAllowed verifies entry ownership and that size fits in the user's quota
+func (s *AttachmentService) checkUploadAllowed(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID, size int64) error {
+	if err := ensureLogEntryOwned(ctx, qtx, userUUID, entryUUID); err != nil {
+		return err
+	}
+
+	used, err := qtx.GetUserAttachmentUsage(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get attachment usage: %w", err)
+	}
+	if used+size > s.quotaBytes {
+		return fmt.Errorf("attachment quota exceeded: %d of %d bytes used", used, s.quotaBytes)
+	}
+	return nil
+}
+
+// getAttachment loads an attachment owned by the user that belongs to the given log entry
+func (s *AttachmentService) getAttachment(ctx context.Context, userID, logEntryID, attachmentID string) (store.LogEntryAttachment, error) {
+	userUUID, entryUUID, err := s.parseIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return store.LogEntryAttachment{}, err
+	}
+
+	attachmentUUID, err := uuid.Parse(attachmentID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid attachment ID format", "user_id", userID, "attachment_id", attachmentID)
+		return store.LogEntryAttachment{}, fmt.Errorf("invalid attachment ID: %w", err)
+	}
+
+	var row store.LogEntryAttachment
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		row, err = qtx.GetAttachmentByID(ctx, store.GetAttachmentByIDParams{
+			ID:     attachmentUUID,
+			UserID: userUUID,
+		})
+		if err != nil || row.LogEntryID != entryUUID {
+			return fmt.Errorf("attachment not found")
+		}
+		return nil
+	}); err != nil {
+		return store.LogEntryAttachment{}, err
+	}
+
+	return row, nil
+}
+
+// parseIDs parses the user and log entry IDs of a request
+func (s *AttachmentService) parseIDs(ctx context.Context, userID, logEntryID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	entryUUID, err := uuid.Parse(logEntryID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid log entry ID format", "user_id", userID, "log_entry_id", logEntryID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid log entry ID: %w", err)
+	}
+
+	return userUUID, entryUUID, nil
+}
+
+// ensureLogEntryOwned checks that a live (not trashed) log entry belongs to the user
+func ensureLogEntryOwned(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID) error {
+	entry, err := qtx.GetLogEntryByID(ctx, entryUUID)
+	if err != nil || entry.UserID != userUUID {
+		return fmt.Errorf("log entry not found")
+	}
+	return nil
+}
+
+// normalizeContentType strips parameters from the declared content type and
+// sniffs the content when the client did not declare a specific type
+func normalizeContentType(declared string, data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	return strings.ToLower(mediaType)
+}
+
+// sanitizeFileName keeps the base name of an uploaded file without control characters
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if len(name) > maxAttachmentFileNameLength {
+		ext := filepath.Ext(name)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:maxAttachmentFileNameLength-len(ext)], "") + ext
+	}
+	return name
+}
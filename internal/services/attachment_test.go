@@ -0,0 +1,45 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "report.pdf", want: "report.pdf"},
+		{name: "unix path", in: "../../etc/passwd", want: "passwd"},
+		{name: "windows path", in: `C:\Users\me\notes.txt`, want: "notes.txt"},
+		{name: "control characters and quotes", in: "bad\"\r\nname.txt", want: "badname.txt"},
+		{name: "only a separator", in: "/", want: ""},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeFileName(tt.in))
+		})
+	}
+
+	t.Run("long names keep their extension", func(t *testing.T) {
+		got := sanitizeFileName(strings.Repeat("a", 300) + ".png")
+		assert.Len(t, got, maxAttachmentFileNameLength)
+		assert.True(t, strings.HasSuffix(got, ".png"))
+	})
+}
+
+func TestNormalizeContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	assert.Equal(t, "text/plain", normalizeContentType("text/plain; charset=utf-8", []byte("hello")))
+	assert.Equal(t, "application/pdf", normalizeContentType("Application/PDF", []byte("%PDF-1.4")))
+	assert.Equal(t, "image/png", normalizeContentType("", png), "missing types are sniffed")
+	assert.Equal(t, "image/png", normalizeContentType("application/octet-stream", png), "generic types are sniffed")
+	assert.False(t, allowedAttachmentTypes[normalizeContentType("text/html", []byte("<html>"))])
+}
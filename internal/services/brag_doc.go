@@ -0,0 +1,395 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/reports"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// bragDocMaxEntries bounds how many entries one brag document lists
+	bragDocMaxEntries = 2000
+	// bragDocSummaryTimeout bounds how long a request waits for group summaries before rendering without them
+	bragDocSummaryTimeout = 45 * time.Second
+	// defaultBragDocEntryURL links accomplishments to their log entries; {id} is replaced by the entry ID
+	defaultBragDocEntryURL = "http://localhost:8080/v1/logs/{id}"
+	// bragDocNoProject names the group of entries without a project
+	bragDocNoProject = "No project"
+)
+
+// bragDocImpactOrder is the order of the impact level groups of a project
+var bragDocImpactOrder = []models.ImpactLevel{models.ImpactCompany, models.ImpactDepartment, models.ImpactTeam, models.ImpactPersonal}
+
+// AccomplishmentSummarizer runs an accomplishment summary task on a worker and returns its JSON result
+type AccomplishmentSummarizer interface {
+	SummarizeAccomplishments(ctx context.Context, userID string, payload any) (string, error)
+}
+
+// bragDocSummaryTask is the worker payload of an accomplishment summary
+type bragDocSummaryTask struct {
+	UserID string                `json:"user_id"`
+	Groups []bragDocSummaryGroup `json:"groups"`
+}
+
+// bragDocSummaryGroup is one group of accomplishments to summarize
+type bragDocSummaryGroup struct {
+	Key         string               `json:"key"`
+	Project     string               `json:"project"`
+	ImpactLevel models.ImpactLevel   `json:"impact_level"`
+	Items       []bragDocSummaryItem `json:"items"`
+}
+
+// bragDocSummaryItem is one accomplishment to summarize
+type bragDocSummaryItem struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// bragDocSummaryResult is the worker result of an accomplishment summary
+type bragDocSummaryResult struct {
+	Summaries map[string]string `json:"summaries"`
+}
+
+// BragDocService renders brag documents: the accomplishments of a period grouped
+// by project and impact level, laid out by a template
+type BragDocService struct {
+	db         *database.DB
+	logger     *logging.Logger
+	templates  *reports.BragDocTemplates
+	summarizer AccomplishmentSummarizer
+	entryURL   string
+}
+
+// NewBragDocService creates a new BragDocService instance.
+// summarizer may be nil, in which case documents are rendered without AI summaries.
+func NewBragDocService(db *database.DB, logger *logging.Logger, templates *reports.BragDocTemplates, summarizer AccomplishmentSummarizer) *BragDocService {
+	return &BragDocService{
+		db:         db,
+		logger:     logger.WithComponent("brag_doc_service"),
+		templates:  templates,
+		summarizer: summarizer,
+		entryURL:   defaultBragDocEntryURL,
+	}
+}
+
+// WithEntryURL sets the link of accomplishments to their log entries, where {id}
+// is replaced by the entry ID; an empty pattern keeps the default
+func (s *BragDocService) WithEntryURL(pattern string) *BragDocService {
+	if pattern != "" {
+		s.entryURL = pattern
+	}
+	return s
+}
+
+// ListTemplates returns the templates brag documents can be rendered with
+func (s *BragDocService) ListTemplates() []models.BragDocTemplate {
+	return s.templates.List()
+}
+
+// RenderBragDoc writes the user's brag document for the requested period, template and format
+func (s *BragDocService) RenderBragDoc(ctx context.Context, userID string, req *models.BragDocRequest, w io.Writer) error {
+	if err := s.normalizeRequest(req); err != nil {
+		return err
+	}
+
+	doc, err := s.GenerateBragDoc(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+
+	// Rendered in full first so template errors do not leave a partial response
+	var buf bytes.Buffer
+	if err := s.templates.Render(&buf, req.Template, req.Format, doc); err != nil {
+		s.logger.LogError(ctx, err, "Failed to render brag document", "user_id", userID, "template", req.Template)
+		return fmt.Errorf("failed to render brag document: %w", err)
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// GenerateBragDoc gathers the data of the user's brag document for the requested period
+func (s *BragDocService) GenerateBragDoc(ctx context.Context, userID string, req *models.BragDocRequest) (*models.BragDoc, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GenerateBragDoc", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := s.normalizeRequest(req); err != nil {
+		return nil, err
+	}
+
+	doc := &models.BragDoc{
+		Period:   req.Period,
+		Projects: []models.BragDocProject{},
+	}
+
+	err = s.db.Read(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		doc.UserName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+
+		loc, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+		if err != nil {
+			s.logger.Warn("Stored timezone is invalid, using UTC", "user_id", userID, "timezone", user.Timezone.String)
+			loc = time.UTC
+		}
+		doc.Timezone = loc.String()
+		doc.GeneratedAt = time.Now().In(loc)
+
+		first, last, err := bragDocWindow(req.Period, req.StartDate, req.EndDate, doc.GeneratedAt)
+		if err != nil {
+			return err
+		}
+		doc.PeriodStart = first.Format(time.DateOnly)
+		doc.PeriodEnd = last.Format(time.DateOnly)
+
+		rows, err := qtx.GetBragDocEntries(ctx, store.GetBragDocEntriesParams{
+			UserID:     userUUID,
+			StartTime:  timeToPgTimestamptz(first),
+			EndTime:    timeToPgTimestamptz(last.AddDate(0, 0, 1).Add(-time.Nanosecond)),
+			MaxEntries: bragDocMaxEntries + 1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get entries: %w", err)
+		}
+		if len(rows) > bragDocMaxEntries {
+			rows = rows[:bragDocMaxEntries]
+			doc.Truncated = true
+		}
+
+		s.groupBragDocEntries(doc, rows, loc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Summarize {
+		s.summarizeBragDoc(ctx, userID, doc)
+	}
+
+	return doc, nil
+}
+
+// normalizeRequest fills in the defaults of a brag document request and validates it
+func (s *BragDocService) normalizeRequest(req *models.BragDocRequest) error {
+	if req.Period == "" {
+		req.Period = models.BragDocThisQuarter
+		if req.StartDate != "" || req.EndDate != "" {
+			req.Period = models.BragDocCustom
+		}
+	}
+	if !req.Period.IsValid() {
+		return fmt.Errorf("invalid period: %s", req.Period)
+	}
+	if req.Period == models.BragDocCustom {
+		if req.StartDate == "" || req.EndDate == "" {
+			return fmt.Errorf("start_date and end_date are required for a custom period")
+		}
+		if err := validateReportPeriod(req.StartDate, req.EndDate); err != nil {
+			return err
+		}
+	}
+
+	if req.Format == "" {
+		req.Format = models.BragDocMarkdown
+	}
+	if !req.Format.IsValid() {
+		return fmt.Errorf("invalid format: %s", req.Format)
+	}
+
+	if req.Template == "" {
+		req.Template = reports.DefaultBragDocTemplate
+	}
+	if !s.templates.Has(req.Template) {
+		return fmt.Errorf("template not found: %s", req.Template)
+	}
+	return nil
+}
+
+// groupBragDocEntries groups entries by project, most time first with entries without
+// a project last, then by impact level, widest first
+func (s *BragDocService) groupBragDocEntries(doc *models.BragDoc, rows []store.GetBragDocEntriesRow, loc *time.Location) {
+	type projectGroups struct {
+		project models.BragDocProject
+		groups  map[models.ImpactLevel]*models.BragDocGroup
+	}
+
+	byProject := make(map[string]*projectGroups)
+	var order []string
+	for _, row := range rows {
+		key := ""
+		projectID := pgUUIDToUUID(row.ProjectID)
+		if projectID != nil {
+			key = projectID.String()
+		}
+
+		pg, ok := byProject[key]
+		if !ok {
+			name := pgTextToStringRequired(row.ProjectName)
+			if projectID == nil || name == "" {
+				name = bragDocNoProject
+			}
+			pg = &projectGroups{
+				project: models.BragDocProject{ID: projectID, Name: name},
+				groups:  make(map[models.ImpactLevel]*models.BragDocGroup),
+			}
+			byProject[key] = pg
+			order = append(order, key)
+		}
+
+		impact := models.ImpactLevel(row.ImpactLevel)
+		group, ok := pg.groups[impact]
+		if !ok {
+			group = &models.BragDocGroup{ImpactLevel: impact}
+			pg.groups[impact] = group
+		}
+
+		minutes := pgInt4ToInt(row.DurationMinutes)
+		group.Accomplishments = append(group.Accomplishments, models.BragDocAccomplishment{
+			ID:          row.ID,
+			Title:       row.Title,
+			Description: pgTextToStringRequired(row.Description),
+			Date:        pgTimestamptzToTime(row.StartTime).In(loc).Format(time.DateOnly),
+			Type:        models.ActivityType(row.Type),
+			ValueRating: models.ValueRating(row.ValueRating),
+			ImpactLevel: impact,
+			Minutes:     minutes,
+			URL:         strings.ReplaceAll(s.entryURL, "{id}", row.ID.String()),
+		})
+		group.Entries++
+		group.Minutes += minutes
+		pg.project.Entries++
+		pg.project.Minutes += minutes
+		doc.TotalEntries++
+		doc.TotalMinutes += minutes
+	}
+
+	for _, key := range order {
+		pg := byProject[key]
+		for _, impact := range bragDocImpactOrder {
+			if group, ok := pg.groups[impact]; ok {
+				pg.project.Groups = append(pg.project.Groups, *group)
+			}
+		}
+		doc.Projects = append(doc.Projects, pg.project)
+	}
+
+	sort.SliceStable(doc.Projects, func(i, j int) bool {
+		a, b := doc.Projects[i], doc.Projects[j]
+		if (a.ID == nil) != (b.ID == nil) {
+			return a.ID != nil
+		}
+		if a.Minutes != b.Minutes {
+			return a.Minutes > b.Minutes
+		}
+		return a.Name < b.Name
+	})
+}
+
+// summarizeBragDoc asks a worker for a summary of each group. Failures are logged and
+// the document is rendered without summaries.
+func (s *BragDocService) summarizeBragDoc(ctx context.Context, userID string, doc *models.BragDoc) {
+	if s.summarizer == nil || len(doc.Projects) == 0 {
+		return
+	}
+
+	task := bragDocSummaryTask{UserID: userID}
+	for i, project := range doc.Projects {
+		for j, group := range project.Groups {
+			items := make([]bragDocSummaryItem, len(group.Accomplishments))
+			for k, a := range group.Accomplishments {
+				items[k] = bragDocSummaryItem{Title: a.Title, Description: a.Description}
+			}
+			task.Groups = append(task.Groups, bragDocSummaryGroup{
+				Key:         bragDocGroupKey(i, j),
+				Project:     project.Name,
+				ImpactLevel: group.ImpactLevel,
+				Items:       items,
+			})
+		}
+	}
+
+	summaryCtx, cancel := context.WithTimeout(ctx, bragDocSummaryTimeout)
+	defer cancel()
+
+	raw, err := s.summarizer.SummarizeAccomplishments(summaryCtx, userID, task)
+	if err != nil {
+		s.logger.Warn("Accomplishment summary unavailable, rendering without it", "user_id", userID, "error", err)
+		return
+	}
+
+	var result bragDocSummaryResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		s.logger.Warn("Invalid accomplishment summary result", "user_id", userID, "error", err)
+		return
+	}
+
+	for i := range doc.Projects {
+		for j := range doc.Projects[i].Groups {
+			doc.Projects[i].Groups[j].Summary = strings.TrimSpace(result.Summaries[bragDocGroupKey(i, j)])
+		}
+	}
+	doc.Summarized = true
+}
+
+// bragDocGroupKey identifies a group of a brag document in summary tasks
+func bragDocGroupKey(project, group int) string {
+	return fmt.Sprintf("%d.%d", project+1, group+1)
+}
+
+// bragDocWindow returns the first and last day of a brag document period. Named periods
+// are calendar periods containing now, or the one before it for the last_ ones.
+func bragDocWindow(period models.BragDocPeriod, startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	loc := now.Location()
+	year, month, _ := now.Date()
+
+	var first time.Time
+	var months int
+	switch period {
+	case models.BragDocThisMonth, models.BragDocLastMonth:
+		first, months = time.Date(year, month, 1, 0, 0, 0, 0, loc), 1
+	case models.BragDocThisQuarter, models.BragDocLastQuarter:
+		first, months = time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc), 3
+	case models.BragDocThisHalf, models.BragDocLastHalf:
+		first, months = time.Date(year, month-(month-1)%6, 1, 0, 0, 0, 0, loc), 6
+	case models.BragDocThisYear, models.BragDocLastYear:
+		first, months = time.Date(year, time.January, 1, 0, 0, 0, 0, loc), 12
+	case models.BragDocCustom:
+		start, err := time.ParseInLocation(time.DateOnly, startDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+		}
+		end, err := time.ParseInLocation(time.DateOnly, endDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+		}
+		return start, end, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", period)
+	}
+
+	if strings.HasPrefix(string(period), "last_") {
+		first = first.AddDate(0, -months, 0)
+	}
+	return first, first.AddDate(0, months, -1), nil
+}
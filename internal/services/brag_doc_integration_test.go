@@ -0,0 +1,128 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/reports"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBragDocService_BragDoc tests gathering and rendering a brag document
+func TestBragDocService_BragDoc(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	templates, err := reports.LoadBragDocTemplates("")
+	require.NoError(t, err)
+	bragDocService := services.NewBragDocService(db, testLogger, templates, nil).
+		WithEntryURL("https://englog.example.com/logs/{id}")
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "bragdoc@example.com",
+		Password:  "password123",
+		FirstName: "Brag",
+		LastName:  "User",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	otherUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "bragdoc-other@example.com",
+		Password:  "password123",
+		FirstName: "Other",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Platform",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	entries := []struct {
+		title   string
+		start   time.Time
+		project bool
+		impact  models.ImpactLevel
+	}{
+		{"Billing migration", time.Date(2025, 2, 10, 9, 0, 0, 0, saoPaulo), true, models.ImpactCompany},
+		{"Code reviews", time.Date(2025, 2, 11, 9, 0, 0, 0, saoPaulo), true, models.ImpactTeam},
+		{"Conference talk", time.Date(2025, 3, 31, 22, 0, 0, 0, saoPaulo), false, models.ImpactDepartment},
+		{"Next quarter", time.Date(2025, 4, 1, 9, 0, 0, 0, saoPaulo), true, models.ImpactCompany},
+	}
+	var billingID string
+	for _, e := range entries {
+		req := &models.LogEntryRequest{
+			Title:       e.title,
+			Type:        models.ActivityDevelopment,
+			StartTime:   e.start,
+			EndTime:     e.start.Add(time.Hour),
+			ValueRating: models.ValueHigh,
+			ImpactLevel: e.impact,
+		}
+		if e.project {
+			req.ProjectID = &project.ID
+		}
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, req)
+		require.NoError(t, err)
+		if e.title == "Billing migration" {
+			billingID = entry.ID.String()
+		}
+	}
+
+	doc, err := bragDocService.GenerateBragDoc(ctx, userID, &models.BragDocRequest{
+		StartDate: "2025-01-01",
+		EndDate:   "2025-03-31",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Brag User", doc.UserName)
+	assert.Equal(t, models.BragDocCustom, doc.Period)
+	assert.Equal(t, 3, doc.TotalEntries, "entries are taken by day in the user's timezone")
+	require.Len(t, doc.Projects, 2)
+	assert.Equal(t, "Platform", doc.Projects[0].Name)
+	require.Len(t, doc.Projects[0].Groups, 2)
+	assert.Equal(t, models.ImpactCompany, doc.Projects[0].Groups[0].ImpactLevel)
+	assert.Equal(t, "https://englog.example.com/logs/"+billingID, doc.Projects[0].Groups[0].Accomplishments[0].URL)
+	assert.Equal(t, "No project", doc.Projects[1].Name)
+	assert.False(t, doc.Summarized)
+
+	var buf bytes.Buffer
+	require.NoError(t, bragDocService.RenderBragDoc(ctx, userID, &models.BragDocRequest{
+		StartDate: "2025-01-01",
+		EndDate:   "2025-03-31",
+		Format:    models.BragDocHTML,
+		Summarize: true,
+	}, &buf))
+	assert.Contains(t, buf.String(), "<h2>Platform</h2>")
+	assert.Contains(t, buf.String(), `href="https://englog.example.com/logs/`+billingID+`"`)
+
+	other, err := bragDocService.GenerateBragDoc(ctx, otherUser.ID.String(), &models.BragDocRequest{
+		StartDate: "2025-01-01",
+		EndDate:   "2025-03-31",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, other.Projects, "brag documents only list the user's own entries")
+}
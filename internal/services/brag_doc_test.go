@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/reports"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSummarizer answers accomplishment summary tasks with a fixed result
+type stubSummarizer struct {
+	result string
+	err    error
+	task   bragDocSummaryTask
+}
+
+func (s *stubSummarizer) SummarizeAccomplishments(ctx context.Context, userID string, payload any) (string, error) {
+	s.task = payload.(bragDocSummaryTask)
+	return s.result, s.err
+}
+
+func newTestBragDocService(t *testing.T, summarizer AccomplishmentSummarizer) *BragDocService {
+	t.Helper()
+	templates, err := reports.LoadBragDocTemplates("")
+	require.NoError(t, err)
+	return NewBragDocService(nil, logging.NewTestLogger(), templates, summarizer)
+}
+
+func TestBragDocWindow(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	now := time.Date(2025, 8, 14, 23, 30, 0, 0, saoPaulo)
+
+	tests := []struct {
+		period      models.BragDocPeriod
+		first, last string
+	}{
+		{models.BragDocThisMonth, "2025-08-01", "2025-08-31"},
+		{models.BragDocThisQuarter, "2025-07-01", "2025-09-30"},
+		{models.BragDocThisHalf, "2025-07-01", "2025-12-31"},
+		{models.BragDocThisYear, "2025-01-01", "2025-12-31"},
+		{models.BragDocLastMonth, "2025-07-01", "2025-07-31"},
+		{models.BragDocLastQuarter, "2025-04-01", "2025-06-30"},
+		{models.BragDocLastHalf, "2025-01-01", "2025-06-30"},
+		{models.BragDocLastYear, "2024-01-01", "2024-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			first, last, err := bragDocWindow(tt.period, "", "", now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.first, first.Format(time.DateOnly))
+			assert.Equal(t, tt.last, last.Format(time.DateOnly))
+			assert.Equal(t, saoPaulo, first.Location(), "periods are days in the user's timezone")
+		})
+	}
+
+	first, last, err := bragDocWindow(models.BragDocLastMonth, "", "", time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-12-01", first.Format(time.DateOnly), "last month crosses the year")
+	assert.Equal(t, "2024-12-31", last.Format(time.DateOnly))
+
+	first, last, err = bragDocWindow(models.BragDocCustom, "2025-02-03", "2025-02-09", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-03", first.Format(time.DateOnly))
+	assert.Equal(t, "2025-02-09", last.Format(time.DateOnly))
+}
+
+func TestBragDocService_NormalizeRequest(t *testing.T) {
+	service := newTestBragDocService(t, nil)
+
+	req := &models.BragDocRequest{}
+	require.NoError(t, service.normalizeRequest(req))
+	assert.Equal(t, models.BragDocThisQuarter, req.Period)
+	assert.Equal(t, models.BragDocMarkdown, req.Format)
+	assert.Equal(t, reports.DefaultBragDocTemplate, req.Template)
+
+	req = &models.BragDocRequest{StartDate: "2025-01-01", EndDate: "2025-03-31"}
+	require.NoError(t, service.normalizeRequest(req))
+	assert.Equal(t, models.BragDocCustom, req.Period, "dates imply a custom period")
+
+	tests := []struct {
+		name string
+		req  models.BragDocRequest
+		err  string
+	}{
+		{"unknown period", models.BragDocRequest{Period: "decade"}, "invalid period"},
+		{"custom without dates", models.BragDocRequest{Period: models.BragDocCustom, StartDate: "2025-01-01"}, "start_date and end_date are required"},
+		{"custom reversed", models.BragDocRequest{StartDate: "2025-03-01", EndDate: "2025-01-01"}, "on or after start date"},
+		{"unknown format", models.BragDocRequest{Format: "pdf"}, "invalid format"},
+		{"unknown template", models.BragDocRequest{Template: "acme"}, "template not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, service.normalizeRequest(&tt.req), tt.err)
+		})
+	}
+}
+
+func TestBragDocService_GroupEntries(t *testing.T) {
+	service := newTestBragDocService(t, nil).WithEntryURL("https://englog.example.com/logs/{id}")
+	platform := uuid.New()
+	billing := uuid.New()
+
+	row := func(project *uuid.UUID, projectName string, impact models.ImpactLevel, minutes int32, start time.Time) store.GetBragDocEntriesRow {
+		r := store.GetBragDocEntriesRow{
+			ID:              uuid.New(),
+			Title:           "Entry",
+			Type:            string(models.ActivityDevelopment),
+			StartTime:       pgtype.Timestamptz{Time: start, Valid: true},
+			DurationMinutes: pgtype.Int4{Int32: minutes, Valid: true},
+			ValueRating:     string(models.ValueHigh),
+			ImpactLevel:     string(impact),
+		}
+		if project != nil {
+			r.ProjectID = pgtype.UUID{Bytes: *project, Valid: true}
+			r.ProjectName = pgtype.Text{String: projectName, Valid: true}
+		}
+		return r
+	}
+
+	day := time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC) // Still March 9 in São Paulo
+	rows := []store.GetBragDocEntriesRow{
+		row(nil, "", models.ImpactPersonal, 300, day),
+		row(&billing, "Billing", models.ImpactTeam, 60, day),
+		row(&platform, "Platform", models.ImpactTeam, 30, day),
+		row(&platform, "Platform", models.ImpactCompany, 90, day),
+		row(&platform, "Platform", models.ImpactTeam, 15, day),
+	}
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	doc := &models.BragDoc{Projects: []models.BragDocProject{}}
+	service.groupBragDocEntries(doc, rows, saoPaulo)
+
+	assert.Equal(t, 5, doc.TotalEntries)
+	assert.Equal(t, 495, doc.TotalMinutes)
+	require.Len(t, doc.Projects, 3)
+	assert.Equal(t, "Platform", doc.Projects[0].Name, "projects with the most time come first")
+	assert.Equal(t, "Billing", doc.Projects[1].Name)
+	assert.Equal(t, bragDocNoProject, doc.Projects[2].Name, "entries without a project come last")
+	assert.Nil(t, doc.Projects[2].ID)
+
+	groups := doc.Projects[0].Groups
+	require.Len(t, groups, 2)
+	assert.Equal(t, models.ImpactCompany, groups[0].ImpactLevel, "wider impact comes first")
+	assert.Equal(t, models.ImpactTeam, groups[1].ImpactLevel)
+	assert.Equal(t, 2, groups[1].Entries)
+	assert.Equal(t, 45, groups[1].Minutes)
+
+	accomplishment := groups[0].Accomplishments[0]
+	assert.Equal(t, "2025-03-09", accomplishment.Date)
+	assert.Equal(t, "https://englog.example.com/logs/"+accomplishment.ID.String(), accomplishment.URL)
+}
+
+func TestBragDocService_Summarize(t *testing.T) {
+	doc := func() *models.BragDoc {
+		return &models.BragDoc{Projects: []models.BragDocProject{{
+			Name: "Platform",
+			Groups: []models.BragDocGroup{
+				{ImpactLevel: models.ImpactCompany, Accomplishments: []models.BragDocAccomplishment{{Title: "Billing migration", Description: "Ledger"}}},
+				{ImpactLevel: models.ImpactTeam, Accomplishments: []models.BragDocAccomplishment{{Title: "Reviews"}}},
+			},
+		}}}
+	}
+
+	result, err := json.Marshal(bragDocSummaryResult{Summaries: map[string]string{"1.1": " I migrated billing. "}})
+	require.NoError(t, err)
+	summarizer := &stubSummarizer{result: string(result)}
+
+	summarized := doc()
+	newTestBragDocService(t, summarizer).summarizeBragDoc(context.Background(), "user", summarized)
+	assert.True(t, summarized.Summarized)
+	assert.Equal(t, "I migrated billing.", summarized.Projects[0].Groups[0].Summary)
+	assert.Empty(t, summarized.Projects[0].Groups[1].Summary, "groups the worker skipped have no summary")
+
+	require.Len(t, summarizer.task.Groups, 2)
+	assert.Equal(t, "1.1", summarizer.task.Groups[0].Key)
+	assert.Equal(t, "Platform", summarizer.task.Groups[0].Project)
+	assert.Equal(t, []bragDocSummaryItem{{Title: "Billing migration", Description: "Ledger"}}, summarizer.task.Groups[0].Items)
+
+	failed := doc()
+	newTestBragDocService(t, &stubSummarizer{err: errors.New("no active worker")}).summarizeBragDoc(context.Background(), "user", failed)
+	assert.False(t, failed.Summarized, "documents are rendered without summaries when the worker fails")
+	assert.Empty(t, failed.Projects[0].Groups[0].Summary)
+
+	unsummarized := doc()
+	newTestBragDocService(t, nil).summarizeBragDoc(context.Background(), "user", unsummarized)
+	assert.False(t, unsummarized.Summarized)
+}
+
+func TestBragDocService_RejectsInvalidInput(t *testing.T) {
+	service := newTestBragDocService(t, nil)
+
+	_, err := service.GenerateBragDoc(context.Background(), "not-a-uuid", &models.BragDocRequest{})
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	_, err = service.GenerateBragDoc(context.Background(), uuid.NewString(), &models.BragDocRequest{Period: "decade"})
+	assert.ErrorContains(t, err, "invalid period")
+}
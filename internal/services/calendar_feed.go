@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/ical"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxCalendarFeedTokens is the number of feed tokens a user may have at once
+	maxCalendarFeedTokens = 10
+	// calendarFeedTokenBytes is the number of random bytes of a feed token
+	calendarFeedTokenBytes = 32
+	// defaultCalendarFeedName names tokens created without a name
+	defaultCalendarFeedName = "Calendar feed"
+	// defaultCalendarFeedDays is the history a feed serves unless asked otherwise
+	defaultCalendarFeedDays = 90
+	// maxCalendarFeedDays is the longest history a feed serves
+	maxCalendarFeedDays = 366
+	// maxCalendarFeedEntries is the largest number of events in a feed
+	maxCalendarFeedEntries = 5000
+	// calendarFeedRefreshInterval is how often calendar apps are asked to refresh
+	calendarFeedRefreshInterval = time.Hour
+	// calendarFeedTouchInterval is how stale a token's last use gets before it is updated
+	calendarFeedTouchInterval = time.Hour
+)
+
+// CalendarFeedService manages the secret tokens of the users' iCalendar activity
+// feeds and builds the feeds from their log entries
+type CalendarFeedService struct {
+	db     *database.DB
+	logger *logging.Logger
+}
+
+// NewCalendarFeedService creates a new CalendarFeedService instance
+func NewCalendarFeedService(db *database.DB, logger *logging.Logger) *CalendarFeedService {
+	return &CalendarFeedService{
+		db:     db,
+		logger: logger.WithComponent("calendar_feed_service"),
+	}
+}
+
+// CreateToken creates a feed token. Only its hash is stored, so the token is
+// returned this once.
+func (s *CalendarFeedService) CreateToken(ctx context.Context, userID string, req *models.CalendarFeedTokenRequest) (*models.CalendarFeedTokenCreated, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateToken", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultCalendarFeedName
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return nil, fmt.Errorf("name must be at most 100 characters")
+	}
+
+	token, err := generateCalendarFeedToken()
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to generate calendar feed token", "user_id", userID)
+		return nil, fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+
+	s.logger.Info("Creating calendar feed token", "user_id", userID, "name", name)
+
+	var created *models.CalendarFeedTokenCreated
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		count, err := qtx.CountCalendarFeedTokens(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to count calendar feed tokens: %w", err)
+		}
+		if count >= maxCalendarFeedTokens {
+			return fmt.Errorf("at most %d calendar feed tokens are allowed; revoke one first", maxCalendarFeedTokens)
+		}
+
+		sqlcToken, err := qtx.CreateCalendarFeedToken(ctx, store.CreateCalendarFeedTokenParams{
+			UserID:    userUUID,
+			Name:      name,
+			TokenHash: hashCalendarFeedToken(token),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create calendar feed token: %w", err)
+		}
+
+		created = &models.CalendarFeedTokenCreated{
+			CalendarFeedToken: *calendarFeedTokenToModel(sqlcToken),
+			Token:             token,
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create calendar feed token", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Calendar feed token created successfully", "user_id", userID, "token_id", created.ID)
+	return created, nil
+}
+
+// GetTokens retrieves the feed tokens of a user, oldest first, without their secrets
+func (s *CalendarFeedService) GetTokens(ctx context.Context, userID string) ([]*models.CalendarFeedToken, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTokens", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var tokens []*models.CalendarFeedToken
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcTokens, err := qtx.GetCalendarFeedTokensByUser(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		tokens = make([]*models.CalendarFeedToken, len(sqlcTokens))
+		for i, sqlcToken := range sqlcTokens {
+			tokens[i] = calendarFeedTokenToModel(sqlcToken)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get calendar feed tokens", "user_id", userID)
+		return nil, fmt.Errorf("failed to get calendar feed tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeToken deletes a feed token; calendars subscribed with it stop updating
+func (s *CalendarFeedService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in RevokeToken", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	tokenUUID, err := uuid.Parse(tokenID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid calendar feed token ID format", "user_id", userID, "token_id", tokenID)
+		return fmt.Errorf("invalid calendar feed token ID: %w", err)
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteCalendarFeedToken(ctx, store.DeleteCalendarFeedTokenParams{
+			ID:     tokenUUID,
+			UserID: userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("calendar feed token not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to revoke calendar feed token", "user_id", userID, "token_id", tokenID)
+		return err
+	}
+
+	s.logger.Info("Calendar feed token revoked successfully", "user_id", userID, "token_id", tokenID)
+	return nil
+}
+
+// Feed builds the calendar of the feed token: one event per log entry started in
+// the requested days of history or the next 24 hours, categorized by project
+// name and activity type and colored like the project
+func (s *CalendarFeedService) Feed(ctx context.Context, token string, filters *models.CalendarFeedFilters) (*ical.Calendar, error) {
+	if filters == nil {
+		filters = &models.CalendarFeedFilters{}
+	}
+
+	days := filters.Days
+	if days == 0 {
+		days = defaultCalendarFeedDays
+	}
+	if days < 1 || days > maxCalendarFeedDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxCalendarFeedDays)
+	}
+
+	types := make([]string, len(filters.Types))
+	for i, activityType := range filters.Types {
+		if !activityType.IsValid() {
+			return nil, fmt.Errorf("invalid activity type: %s", activityType)
+		}
+		types[i] = string(activityType)
+	}
+	projectIDs := filters.ProjectIDs
+	if projectIDs == nil {
+		projectIDs = []uuid.UUID{}
+	}
+
+	now := time.Now()
+	var feedToken store.CalendarFeedToken
+	var entries []store.GetCalendarFeedEntriesRow
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		feedToken, err = qtx.GetCalendarFeedTokenByHash(ctx, hashCalendarFeedToken(token))
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("calendar feed not found")
+			}
+			return fmt.Errorf("failed to get calendar feed token: %w", err)
+		}
+
+		entries, err = qtx.GetCalendarFeedEntries(ctx, store.GetCalendarFeedEntriesParams{
+			UserID:     feedToken.UserID,
+			StartTime:  timeToPgTimestamptz(now.AddDate(0, 0, -days)),
+			EndTime:    timeToPgTimestamptz(now.Add(24 * time.Hour)),
+			ProjectIds: projectIDs,
+			Types:      types,
+			MaxEntries: maxCalendarFeedEntries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get calendar feed entries: %w", err)
+		}
+		return nil
+	}); err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			s.logger.LogError(ctx, err, "Failed to build calendar feed")
+		}
+		return nil, err
+	}
+
+	s.touchToken(ctx, feedToken, now)
+
+	calendar := &ical.Calendar{
+		Name:            "EngLog activities",
+		RefreshInterval: calendarFeedRefreshInterval,
+		Events:          make([]ical.Event, len(entries)),
+	}
+	for i, entry := range entries {
+		calendar.Events[i] = calendarFeedEvent(entry)
+	}
+
+	return calendar, nil
+}
+
+// touchToken records that a feed token was used. Calendar apps poll feeds often,
+// so the time is only written once it is stale, and failures only logged.
+func (s *CalendarFeedService) touchToken(ctx context.Context, feedToken store.CalendarFeedToken, now time.Time) {
+	if feedToken.LastUsedAt.Valid && now.Sub(feedToken.LastUsedAt.Time) < calendarFeedTouchInterval {
+		return
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		return qtx.TouchCalendarFeedToken(ctx, feedToken.ID)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update calendar feed token last use", "token_id", feedToken.ID)
+	}
+}
+
+// calendarFeedEvent converts a log entry to a calendar event
+func calendarFeedEvent(entry store.GetCalendarFeedEntriesRow) ical.Event {
+	details := fmt.Sprintf("Type: %s · Value: %s · Impact: %s", entry.Type, entry.ValueRating, entry.ImpactLevel)
+	description := details
+	if entry.Description.Valid && strings.TrimSpace(entry.Description.String) != "" {
+		description = entry.Description.String + "\n\n" + details
+	}
+
+	event := ical.Event{
+		UID:          entry.ID.String() + "@englog",
+		Summary:      entry.Title,
+		Description:  description,
+		Start:        pgTimestamptzToTime(entry.StartTime),
+		End:          pgTimestamptzToTime(entry.EndTime),
+		LastModified: pgTimestamptzToTime(entry.UpdatedAt),
+	}
+	if entry.ProjectName.Valid {
+		event.Categories = append(event.Categories, entry.ProjectName.String)
+	}
+	event.Categories = append(event.Categories, entry.Type)
+	if entry.ProjectColor.Valid {
+		if color, ok := ical.ColorName(entry.ProjectColor.String); ok {
+			event.Color = color
+		}
+	}
+	return event
+}
+
+// generateCalendarFeedToken returns a random URL-safe token
+func generateCalendarFeedToken() (string, error) {
+	bytes := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// hashCalendarFeedToken returns the hex SHA-256 a feed token is stored as
+func hashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// calendarFeedTokenToModel converts a stored feed token to the API model
+func calendarFeedTokenToModel(sqlcToken store.CalendarFeedToken) *models.CalendarFeedToken {
+	return &models.CalendarFeedToken{
+		ID:         sqlcToken.ID,
+		Name:       sqlcToken.Name,
+		LastUsedAt: pgTimestamptzToTimePtr(sqlcToken.LastUsedAt),
+		CreatedAt:  pgTimestamptzToTime(sqlcToken.CreatedAt),
+	}
+}
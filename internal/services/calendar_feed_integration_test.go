@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalendarFeedService tests feed tokens, their filters and revocation
+func TestCalendarFeedService(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	calendarFeedService := services.NewCalendarFeedService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "calendar-feed@example.com",
+		Password:  "password123",
+		FirstName: "Calendar",
+		LastName:  "Feed",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Payments",
+		Color:  "#ff0000",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	start := time.Now().Add(-48 * time.Hour).Truncate(time.Minute)
+	for _, req := range []models.LogEntryRequest{
+		{Title: "Review refunds PR", Type: models.ActivityCodeReview, ProjectID: &project.ID, StartTime: start, EndTime: start.Add(time.Hour)},
+		{Title: "Standup", Type: models.ActivityMeeting, StartTime: start.Add(2 * time.Hour), EndTime: start.Add(150 * time.Minute)},
+		{Title: "Old work", Type: models.ActivityDevelopment, StartTime: start.AddDate(0, 0, -200), EndTime: start.AddDate(0, 0, -200).Add(time.Hour)},
+	} {
+		req.ValueRating = models.ValueMedium
+		req.ImpactLevel = models.ImpactTeam
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &req)
+		require.NoError(t, err)
+	}
+
+	created, err := calendarFeedService.CreateToken(ctx, userID, &models.CalendarFeedTokenRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "Calendar feed", created.Name)
+	assert.NotEmpty(t, created.Token)
+	assert.Nil(t, created.LastUsedAt)
+
+	t.Run("Feed", func(t *testing.T) {
+		calendar, err := calendarFeedService.Feed(ctx, created.Token, nil)
+		require.NoError(t, err)
+		require.Len(t, calendar.Events, 2, "entries older than 90 days are left out")
+		assert.Equal(t, "Standup", calendar.Events[0].Summary, "newest first")
+
+		review := calendar.Events[1]
+		assert.Equal(t, "Review refunds PR", review.Summary)
+		assert.Equal(t, []string{"Payments", "code_review"}, review.Categories)
+		assert.Equal(t, "red", review.Color)
+		assert.True(t, review.Start.Equal(start))
+
+		tokens, err := calendarFeedService.GetTokens(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.NotNil(t, tokens[0].LastUsedAt, "using a feed records it")
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		calendar, err := calendarFeedService.Feed(ctx, created.Token, &models.CalendarFeedFilters{Types: []models.ActivityType{models.ActivityMeeting}})
+		require.NoError(t, err)
+		require.Len(t, calendar.Events, 1)
+		assert.Equal(t, "Standup", calendar.Events[0].Summary)
+
+		calendar, err = calendarFeedService.Feed(ctx, created.Token, &models.CalendarFeedFilters{ProjectIDs: []uuid.UUID{project.ID}})
+		require.NoError(t, err)
+		require.Len(t, calendar.Events, 1)
+		assert.Equal(t, "Review refunds PR", calendar.Events[0].Summary)
+
+		calendar, err = calendarFeedService.Feed(ctx, created.Token, &models.CalendarFeedFilters{Days: 366})
+		require.NoError(t, err)
+		assert.Len(t, calendar.Events, 3)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		_, err := calendarFeedService.Feed(ctx, "not-a-token", nil)
+		assert.ErrorContains(t, err, "not found")
+
+		require.NoError(t, calendarFeedService.RevokeToken(ctx, userID, created.ID.String()))
+		_, err = calendarFeedService.Feed(ctx, created.Token, nil)
+		assert.ErrorContains(t, err, "not found", "revoked tokens stop working")
+
+		err = calendarFeedService.RevokeToken(ctx, userID, created.ID.String())
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("TokenLimit", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			_, err := calendarFeedService.CreateToken(ctx, userID, &models.CalendarFeedTokenRequest{Name: "Phone"})
+			require.NoError(t, err)
+		}
+		_, err := calendarFeedService.CreateToken(ctx, userID, &models.CalendarFeedTokenRequest{Name: "One too many"})
+		assert.ErrorContains(t, err, "at most 10")
+	})
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeedEvent(t *testing.T) {
+	start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	entry := store.GetCalendarFeedEntriesRow{
+		ID:           uuid.New(),
+		Title:        "Review refunds PR",
+		Description:  pgtype.Text{String: "Checked the retries", Valid: true},
+		Type:         string(models.ActivityCodeReview),
+		StartTime:    pgtype.Timestamptz{Time: start, Valid: true},
+		EndTime:      pgtype.Timestamptz{Time: start.Add(90 * time.Minute), Valid: true},
+		ValueRating:  string(models.ValueHigh),
+		ImpactLevel:  string(models.ImpactTeam),
+		UpdatedAt:    pgtype.Timestamptz{Time: start.Add(2 * time.Hour), Valid: true},
+		ProjectName:  pgtype.Text{String: "Payments", Valid: true},
+		ProjectColor: pgtype.Text{String: "#ff0000", Valid: true},
+	}
+
+	event := calendarFeedEvent(entry)
+	assert.Equal(t, entry.ID.String()+"@englog", event.UID)
+	assert.Equal(t, "Review refunds PR", event.Summary)
+	assert.Equal(t, "Checked the retries\n\nType: code_review · Value: high · Impact: team", event.Description)
+	assert.True(t, event.Start.Equal(start))
+	assert.True(t, event.End.Equal(start.Add(90*time.Minute)))
+	assert.Equal(t, []string{"Payments", "code_review"}, event.Categories)
+	assert.Equal(t, "red", event.Color)
+	assert.True(t, event.LastModified.Equal(start.Add(2*time.Hour)))
+
+	entry.Description = pgtype.Text{}
+	entry.ProjectName = pgtype.Text{}
+	entry.ProjectColor = pgtype.Text{}
+	event = calendarFeedEvent(entry)
+	assert.Equal(t, "Type: code_review · Value: high · Impact: team", event.Description)
+	assert.Equal(t, []string{"code_review"}, event.Categories, "entries without a project are categorized by type")
+	assert.Empty(t, event.Color)
+}
+
+func TestCalendarFeedTokens(t *testing.T) {
+	token, err := generateCalendarFeedToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 43, "32 bytes in unpadded base64")
+	assert.NotContains(t, token, "/")
+
+	other, err := generateCalendarFeedToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	hash := hashCalendarFeedToken(token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, hashCalendarFeedToken(token))
+	assert.NotEqual(t, hash, hashCalendarFeedToken(other))
+}
+
+func TestCalendarFeedService_FeedValidation(t *testing.T) {
+	service := NewCalendarFeedService(nil, logging.NewTestLogger())
+	ctx := context.Background()
+
+	_, err := service.Feed(ctx, "token", &models.CalendarFeedFilters{Days: 400})
+	assert.ErrorContains(t, err, "days must be between 1 and 366")
+
+	_, err = service.Feed(ctx, "token", &models.CalendarFeedFilters{Types: []models.ActivityType{"napping"}})
+	assert.ErrorContains(t, err, "invalid activity type")
+}
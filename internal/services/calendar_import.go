@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/ical"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// calendarImportSource identifies calendar events in log_entry_imports
+	calendarImportSource = "ics"
+	// defaultMaxCalendarBytes is the largest calendar file accepted for an import
+	defaultMaxCalendarBytes = 10 * 1024 * 1024
+	// defaultCalendarImportDays is the period imported when no start date is given
+	defaultCalendarImportDays = 30
+	// maxCalendarImportDays is the longest period of one import
+	maxCalendarImportDays = 366
+)
+
+// CalendarImportService imports the meetings of iCalendar files as log entries.
+// Each imported event occurrence is recorded by its UID, so importing the same
+// calendar again only adds the meetings that are new.
+type CalendarImportService struct {
+	db           *database.DB
+	logger       *logging.Logger
+	logEntries   *LogEntryService
+	maxFileBytes int64
+}
+
+// NewCalendarImportService creates a new CalendarImportService instance
+func NewCalendarImportService(db *database.DB, logger *logging.Logger, logEntries *LogEntryService) *CalendarImportService {
+	return &CalendarImportService{
+		db:           db,
+		logger:       logger.WithComponent("calendar_import_service"),
+		logEntries:   logEntries,
+		maxFileBytes: defaultMaxCalendarBytes,
+	}
+}
+
+// WithMaxFileBytes sets the largest calendar file accepted for an import;
+// non-positive values keep the default
+func (s *CalendarImportService) WithMaxFileBytes(maxBytes int64) *CalendarImportService {
+	if maxBytes > 0 {
+		s.maxFileBytes = maxBytes
+	}
+	return s
+}
+
+// MaxFileBytes returns the largest calendar file accepted by ImportCalendar
+func (s *CalendarImportService) MaxFileBytes() int64 {
+	return s.maxFileBytes
+}
+
+// ImportCalendar creates a meeting entry for every event of the calendar that took
+// place in the period, with recurring events expanded in the user's timezone.
+// All-day, cancelled and declined events are skipped, as are meetings that have not
+// ended yet and those imported before.
+func (s *CalendarImportService) ImportCalendar(ctx context.Context, userID string, file io.Reader, req *models.CalendarImportRequest) (*models.CalendarImportResult, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ImportCalendar", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	startDay, endDay, err := parseExportDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	calendarEmail := strings.TrimSpace(req.Email)
+	if calendarEmail != "" && !strings.Contains(calendarEmail, "@") {
+		return nil, fmt.Errorf("a valid calendar email is required")
+	}
+
+	var user store.User
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		user, err = qtx.GetUserByID(ctx, userUUID)
+		return err
+	}); err != nil {
+		if database.NoRows(err) {
+			return nil, fmt.Errorf("user not found")
+		}
+		s.logger.LogError(ctx, err, "Failed to get user for calendar import", "user_id", userID)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	location, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+	if err != nil {
+		s.logger.Warn("Stored timezone is invalid, importing the calendar in UTC", "user_id", userID, "timezone", user.Timezone.String)
+		location = time.UTC
+	}
+
+	// The period covers whole days in the user's timezone
+	now := time.Now()
+	last := inLocation(now.In(location), time.UTC)
+	if endDay != nil {
+		last = *endDay
+	}
+	first := last.AddDate(0, 0, 1-defaultCalendarImportDays)
+	if startDay != nil {
+		first = *startDay
+	}
+	if first.After(last) {
+		return nil, fmt.Errorf("end date must be on or after start date")
+	}
+	if days := int(last.Sub(first).Hours()/24) + 1; days > maxCalendarImportDays {
+		return nil, fmt.Errorf("the period must be at most %d days", maxCalendarImportDays)
+	}
+	from, until := inLocation(first, location), inLocation(last, location).AddDate(0, 0, 1)
+
+	limited := &io.LimitedReader{R: file, N: s.maxFileBytes + 1}
+	calendar, err := ical.Parse(limited, location)
+	if limited.N <= 0 {
+		return nil, fmt.Errorf("calendar file is larger than the %d byte limit", s.maxFileBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar file: %w", err)
+	}
+
+	emails := []string{user.Email}
+	if calendarEmail != "" {
+		emails = append(emails, calendarEmail)
+	}
+
+	s.logger.Info("Importing calendar", "user_id", userID, "events", len(calendar.Events),
+		"start_date", first.Format(time.DateOnly), "end_date", last.Format(time.DateOnly))
+
+	occurrences := ical.Occurrences(calendar.Events, from, until)
+	template := models.CalendarImportResult{
+		Calendar:    calendar.Name,
+		StartDate:   first.Format(time.DateOnly),
+		EndDate:     last.Format(time.DateOnly),
+		Events:      len(calendar.Events),
+		Occurrences: len(occurrences),
+		Warnings:    append([]string{}, calendar.Warnings...),
+	}
+
+	// Events that are not meetings the user attended are skipped before touching the database
+	var candidates []ical.Occurrence
+	var candidateIDs []string
+	for _, occurrence := range occurrences {
+		event := occurrence.Event
+		attendee := calendarAttendee(event, emails)
+		switch {
+		case event.Status == ical.StatusCancelled:
+			template.Skipped.Cancelled++
+		case event.AllDay:
+			template.Skipped.AllDay++
+		case attendee != nil && attendee.Status == ical.PartStatDeclined:
+			template.Skipped.Declined++
+		case occurrence.End.After(now):
+			template.Skipped.NotOver++
+		case !occurrence.End.After(occurrence.Start) || occurrence.End.Sub(occurrence.Start) > maxLogEntryDuration:
+			template.Skipped.Duration++
+		default:
+			candidates = append(candidates, occurrence)
+			candidateIDs = append(candidateIDs, occurrence.ID)
+		}
+	}
+
+	var result models.CalendarImportResult
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		result = template
+		result.Entries = []models.LogEntry{}
+		result.Warnings = append([]string{}, template.Warnings...)
+
+		var project *store.Project
+		if req.ProjectID != nil {
+			found, err := authorizeProject(ctx, qtx, *req.ProjectID, userUUID, models.TeamPermissionLogTime)
+			if err != nil {
+				return err
+			}
+			if found.ArchivedAt.Valid {
+				return fmt.Errorf("project is archived")
+			}
+			project = &found
+		}
+
+		imported := map[string]bool{}
+		if len(candidateIDs) > 0 {
+			ids, err := qtx.GetImportedExternalIDs(ctx, store.GetImportedExternalIDsParams{
+				UserID:      userUUID,
+				Source:      calendarImportSource,
+				ExternalIds: candidateIDs,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get imported events: %w", err)
+			}
+			for _, id := range ids {
+				imported[id] = true
+			}
+		}
+
+		for _, occurrence := range candidates {
+			if imported[occurrence.ID] {
+				result.Skipped.AlreadyImported++
+				continue
+			}
+
+			entry := meetingEntry(occurrence, calendarAttendee(occurrence.Event, emails), project)
+			if err := s.logEntries.validateLogEntryRequest(&entry); err != nil {
+				result.Skipped.Invalid++
+				result.Warnings = append(result.Warnings, fmt.Sprintf("event %s: %v", occurrence.ID, err))
+				continue
+			}
+			created, err := s.logEntries.createLogEntryTx(ctx, qtx, userUUID, &entry)
+			if err != nil {
+				return err
+			}
+			if err := qtx.CreateLogEntryImport(ctx, store.CreateLogEntryImportParams{
+				UserID:     userUUID,
+				Source:     calendarImportSource,
+				ExternalID: occurrence.ID,
+				LogEntryID: created.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to record imported event: %w", err)
+			}
+			result.Entries = append(result.Entries, *created)
+		}
+		result.Created = len(result.Entries)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to import calendar", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Successfully imported calendar", "user_id", userID, "occurrences", result.Occurrences,
+		"created", result.Created, "already_imported", result.Skipped.AlreadyImported, "warnings", len(result.Warnings))
+	return &result, nil
+}
+
+// calendarAttendee returns the attendee of the event that is the user, if any
+func calendarAttendee(event *ical.Event, emails []string) *ical.Attendee {
+	for _, email := range emails {
+		if attendee := event.Attendee(email); attendee != nil {
+			return attendee
+		}
+	}
+	return nil
+}
+
+// meetingImpact estimates the reach of a meeting from the number of people in it:
+// one-on-ones are personal, small meetings team-wide, larger ones department-wide
+// and all-hands company-wide. Rooms, resources and people who declined do not count;
+// the organizer does even when not listed as an attendee.
+func meetingImpact(event *ical.Event) models.ImpactLevel {
+	people := 0
+	organizerListed := event.OrganizerEmail == ""
+	for _, attendee := range event.Attendees {
+		if attendee.Kind == ical.KindRoom || attendee.Kind == ical.KindResource ||
+			attendee.Role == ical.RoleNonParticipant || attendee.Status == ical.PartStatDeclined {
+			continue
+		}
+		if strings.EqualFold(attendee.Email, event.OrganizerEmail) {
+			organizerListed = true
+		}
+		people++
+	}
+	if !organizerListed {
+		people++
+	}
+
+	switch {
+	case people <= 2:
+		return models.ImpactPersonal
+	case people <= 10:
+		return models.ImpactTeam
+	case people <= 50:
+		return models.ImpactDepartment
+	default:
+		return models.ImpactCompany
+	}
+}
+
+// meetingEntry builds the meeting entry of an event occurrence. The project's
+// defaults fill the value rating and tags; meetings the user was optional in are
+// of low value unless the project says otherwise.
+func meetingEntry(occurrence ical.Occurrence, attendee *ical.Attendee, project *store.Project) models.LogEntryRequest {
+	event := occurrence.Event
+
+	title := strings.Join(strings.Fields(event.Summary), " ")
+	if title == "" {
+		title = "Meeting"
+	}
+
+	var details []string
+	if len(event.Attendees) > 0 {
+		details = append(details, fmt.Sprintf("%d invited", len(event.Attendees)))
+	}
+	if event.Location != "" {
+		details = append(details, event.Location)
+	}
+	text := strings.Join(details, " · ")
+	if event.Description != "" {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += event.Description
+	}
+
+	entry := models.LogEntryRequest{
+		Title:       shortenText(title, maxProposedTitleBytes),
+		Type:        models.ActivityMeeting,
+		StartTime:   occurrence.Start,
+		EndTime:     occurrence.End,
+		ImpactLevel: meetingImpact(event),
+	}
+	if text != "" {
+		text = shortenText(text, maxProposedDescriptionBytes)
+		entry.Description = &text
+	}
+	if project != nil {
+		entry.ProjectID = &project.ID
+		projectDefaultsToModel(*project).Apply(&entry)
+	}
+	if entry.ValueRating == "" {
+		entry.ValueRating = models.ValueMedium
+		if attendee != nil && attendee.Role == ical.RoleOptional {
+			entry.ValueRating = models.ValueLow
+		}
+	}
+	return entry
+}
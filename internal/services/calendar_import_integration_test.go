@@ -0,0 +1,166 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var calendarImportFeed = strings.Join([]string{
+	"BEGIN:VCALENDAR",
+	"X-WR-CALNAME:Work",
+	// Weekly on Mondays at 10:00 Sao Paulo time, the second week moved to Tuesday
+	"BEGIN:VEVENT",
+	"UID:planning@example.com",
+	"DTSTART;TZID=America/Sao_Paulo:20250310T100000",
+	"DTEND;TZID=America/Sao_Paulo:20250310T110000",
+	"RRULE:FREQ=WEEKLY;COUNT=3",
+	"SUMMARY:Sprint planning",
+	"ORGANIZER:mailto:lead@example.com",
+	"ATTENDEE;PARTSTAT=ACCEPTED:mailto:lead@example.com",
+	"ATTENDEE;PARTSTAT=ACCEPTED:mailto:Calendar-Import@example.com",
+	"ATTENDEE;PARTSTAT=ACCEPTED:mailto:dev@example.com",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:planning@example.com",
+	"RECURRENCE-ID;TZID=America/Sao_Paulo:20250317T100000",
+	"DTSTART;TZID=America/Sao_Paulo:20250318T140000",
+	"DTEND;TZID=America/Sao_Paulo:20250318T150000",
+	"SUMMARY:Sprint planning (moved)",
+	"END:VEVENT",
+	// A floating time, read in the profile timezone
+	"BEGIN:VEVENT",
+	"UID:one-on-one@example.com",
+	"DTSTART:20250311T160000",
+	"DURATION:PT30M",
+	"SUMMARY:1:1",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:offsite@example.com",
+	"DTSTART;VALUE=DATE:20250312",
+	"SUMMARY:Offsite",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:declined@example.com",
+	"DTSTART:20250312T170000Z",
+	"DTEND:20250312T180000Z",
+	"SUMMARY:Vendor demo",
+	"ATTENDEE;PARTSTAT=DECLINED:mailto:me@personal.example.com",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:cancelled@example.com",
+	"DTSTART:20250313T170000Z",
+	"DTEND:20250313T180000Z",
+	"STATUS:CANCELLED",
+	"SUMMARY:Retro",
+	"END:VEVENT",
+	"END:VCALENDAR",
+}, "\r\n")
+
+// TestCalendarImportService_ImportCalendar tests recurrence expansion, skipped events and UID deduplication
+func TestCalendarImportService_ImportCalendar(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	calendarImportService := services.NewCalendarImportService(db, testLogger, logEntryService)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "calendar-import@example.com",
+		Password:  "password123",
+		FirstName: "Calendar",
+		LastName:  "Import",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	req := &models.CalendarImportRequest{
+		StartDate: "2025-03-10",
+		EndDate:   "2025-03-23",
+		Email:     "me@personal.example.com",
+	}
+	result, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader(calendarImportFeed), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Work", result.Calendar)
+	assert.Equal(t, 6, result.Events)
+	assert.Equal(t, 6, result.Occurrences, "two planning meetings in the period, the third is on March 24")
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, models.CalendarImportSkips{AllDay: 1, Declined: 1, Cancelled: 1}, result.Skipped)
+	assert.Empty(t, result.Warnings)
+	require.Len(t, result.Entries, 3)
+
+	planning := result.Entries[0]
+	assert.Equal(t, "Sprint planning", planning.Title)
+	assert.Equal(t, models.ActivityMeeting, planning.Type)
+	assert.Equal(t, models.ImpactTeam, planning.ImpactLevel, "three people attend")
+	assert.True(t, planning.StartTime.Equal(time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)))
+
+	oneOnOne := result.Entries[1]
+	assert.Equal(t, "1:1", oneOnOne.Title)
+	assert.Equal(t, models.ImpactPersonal, oneOnOne.ImpactLevel)
+	assert.True(t, oneOnOne.StartTime.Equal(time.Date(2025, 3, 11, 19, 0, 0, 0, time.UTC)), "floating times are in the profile timezone")
+
+	moved := result.Entries[2]
+	assert.Equal(t, "Sprint planning (moved)", moved.Title)
+	assert.True(t, moved.StartTime.Equal(time.Date(2025, 3, 18, 17, 0, 0, 0, time.UTC)))
+
+	t.Run("ReimportIsIdempotent", func(t *testing.T) {
+		result, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader(calendarImportFeed), req)
+		require.NoError(t, err)
+		assert.Zero(t, result.Created)
+		assert.Empty(t, result.Entries)
+		assert.Equal(t, 3, result.Skipped.AlreadyImported)
+	})
+
+	t.Run("DeletedEntriesStayDeleted", func(t *testing.T) {
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, oneOnOne.ID.String()))
+
+		result, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader(calendarImportFeed), req)
+		require.NoError(t, err)
+		assert.Zero(t, result.Created)
+		assert.Equal(t, 3, result.Skipped.AlreadyImported)
+	})
+
+	t.Run("LongerPeriodAddsNewOccurrences", func(t *testing.T) {
+		result, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader(calendarImportFeed), &models.CalendarImportRequest{
+			StartDate: "2025-03-10",
+			EndDate:   "2025-03-31",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Skipped.AlreadyImported)
+		assert.Zero(t, result.Skipped.Declined, "without the calendar email the user is not among the attendees")
+		require.Len(t, result.Entries, 2)
+		assert.Equal(t, "Vendor demo", result.Entries[0].Title)
+		assert.Equal(t, "Sprint planning", result.Entries[1].Title)
+		assert.True(t, result.Entries[1].StartTime.Equal(time.Date(2025, 3, 24, 13, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("PeriodTooLong", func(t *testing.T) {
+		_, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader(calendarImportFeed), &models.CalendarImportRequest{
+			StartDate: "2024-01-01",
+			EndDate:   "2025-03-31",
+		})
+		assert.ErrorContains(t, err, "at most 366 days")
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		_, err := calendarImportService.ImportCalendar(ctx, userID, strings.NewReader("not a calendar"), req)
+		assert.ErrorContains(t, err, "invalid calendar file")
+	})
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/ical"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetingImpact(t *testing.T) {
+	people := func(n int) []ical.Attendee {
+		attendees := make([]ical.Attendee, n)
+		for i := range attendees {
+			attendees[i] = ical.Attendee{
+				Email:  fmt.Sprintf("person%d@example.com", i),
+				Role:   ical.RoleRequired,
+				Status: ical.PartStatAccepted,
+				Kind:   ical.KindIndividual,
+			}
+		}
+		return attendees
+	}
+
+	assert.Equal(t, models.ImpactPersonal, meetingImpact(&ical.Event{}), "events without attendees are the user's own")
+	assert.Equal(t, models.ImpactPersonal, meetingImpact(&ical.Event{Attendees: people(2)}))
+	assert.Equal(t, models.ImpactTeam, meetingImpact(&ical.Event{Attendees: people(3)}))
+	assert.Equal(t, models.ImpactTeam, meetingImpact(&ical.Event{Attendees: people(10)}))
+	assert.Equal(t, models.ImpactDepartment, meetingImpact(&ical.Event{Attendees: people(11)}))
+	assert.Equal(t, models.ImpactCompany, meetingImpact(&ical.Event{Attendees: people(51)}))
+
+	oneOnOne := people(2)
+	assert.Equal(t, models.ImpactTeam, meetingImpact(&ical.Event{Attendees: oneOnOne, OrganizerEmail: "boss@example.com"}),
+		"an organizer who is not listed counts")
+	assert.Equal(t, models.ImpactPersonal, meetingImpact(&ical.Event{Attendees: oneOnOne, OrganizerEmail: "PERSON0@example.com"}))
+
+	withRoom := people(2)
+	withRoom = append(withRoom,
+		ical.Attendee{Email: "room@example.com", Kind: ical.KindRoom, Role: ical.RoleRequired},
+		ical.Attendee{Email: "projector@example.com", Kind: ical.KindResource, Role: ical.RoleRequired},
+		ical.Attendee{Email: "fyi@example.com", Kind: ical.KindIndividual, Role: ical.RoleNonParticipant},
+		ical.Attendee{Email: "no@example.com", Kind: ical.KindIndividual, Role: ical.RoleRequired, Status: ical.PartStatDeclined},
+	)
+	assert.Equal(t, models.ImpactPersonal, meetingImpact(&ical.Event{Attendees: withRoom}),
+		"rooms, resources, non-participants and people who declined do not count")
+}
+
+func TestMeetingEntry(t *testing.T) {
+	start := time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)
+	event := &ical.Event{
+		UID:         "planning@example.com",
+		Summary:     "  Sprint\n planning ",
+		Description: "Agenda: review the backlog",
+		Location:    "Room 1",
+		Attendees: []ical.Attendee{
+			{Email: "jane@example.com", Role: ical.RoleOptional, Status: ical.PartStatAccepted, Kind: ical.KindIndividual},
+			{Email: "john@example.com", Role: ical.RoleRequired, Status: ical.PartStatAccepted, Kind: ical.KindIndividual},
+			{Email: "joe@example.com", Role: ical.RoleRequired, Status: ical.PartStatTentative, Kind: ical.KindIndividual},
+		},
+	}
+	occurrence := ical.Occurrence{ID: event.UID, Event: event, Start: start, End: start.Add(time.Hour)}
+
+	entry := meetingEntry(occurrence, nil, nil)
+	assert.Equal(t, "Sprint planning", entry.Title)
+	require.NotNil(t, entry.Description)
+	assert.Equal(t, "3 invited · Room 1\n\nAgenda: review the backlog", *entry.Description)
+	assert.Equal(t, models.ActivityMeeting, entry.Type)
+	assert.Equal(t, models.ImpactTeam, entry.ImpactLevel)
+	assert.Equal(t, models.ValueMedium, entry.ValueRating)
+	assert.Equal(t, start, entry.StartTime)
+	assert.Equal(t, start.Add(time.Hour), entry.EndTime)
+	assert.Nil(t, entry.ProjectID)
+
+	entry = meetingEntry(occurrence, &event.Attendees[0], nil)
+	assert.Equal(t, models.ValueLow, entry.ValueRating, "optional meetings are of low value")
+
+	project := &store.Project{
+		ID:                 uuid.New(),
+		DefaultType:        pgtype.Text{String: string(models.ActivityDevelopment), Valid: true},
+		DefaultValueRating: pgtype.Text{String: string(models.ValueHigh), Valid: true},
+		DefaultImpactLevel: pgtype.Text{String: string(models.ImpactCompany), Valid: true},
+		DefaultTags:        []string{"payments"},
+	}
+	entry = meetingEntry(occurrence, &event.Attendees[0], project)
+	assert.Equal(t, &project.ID, entry.ProjectID)
+	assert.Equal(t, models.ActivityMeeting, entry.Type, "calendar events are always meetings")
+	assert.Equal(t, models.ImpactTeam, entry.ImpactLevel, "the attendees decide the impact")
+	assert.Equal(t, models.ValueHigh, entry.ValueRating, "project defaults fill the value rating")
+	assert.Equal(t, []string{"payments"}, entry.Tags)
+
+	untitled := meetingEntry(ical.Occurrence{ID: "x", Event: &ical.Event{UID: "x", Description: strings.Repeat("d", 2000)}, Start: start, End: start.Add(time.Hour)}, nil, nil)
+	assert.Equal(t, "Meeting", untitled.Title)
+	assert.LessOrEqual(t, len(*untitled.Description), maxProposedDescriptionBytes)
+	assert.Equal(t, models.ImpactPersonal, untitled.ImpactLevel)
+}
+
+func TestCalendarImportService_RejectsInvalidInput(t *testing.T) {
+	service := NewCalendarImportService(nil, logging.NewTestLogger(), nil).WithMaxFileBytes(64)
+	ctx := context.Background()
+
+	assert.Equal(t, int64(64), service.MaxFileBytes())
+	assert.Equal(t, int64(64), service.WithMaxFileBytes(0).MaxFileBytes(), "non-positive limits keep the current one")
+
+	_, err := service.ImportCalendar(ctx, "not-a-uuid", strings.NewReader(""), &models.CalendarImportRequest{})
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	userID := uuid.NewString()
+	for message, req := range map[string]*models.CalendarImportRequest{
+		"invalid start date":           {StartDate: "10/03/2025"},
+		"invalid end date":             {EndDate: "2025-13-01"},
+		"end date must be on or after": {StartDate: "2025-03-10", EndDate: "2025-03-01"},
+		"a valid calendar email":       {Email: "jane"},
+	} {
+		_, err := service.ImportCalendar(ctx, userID, strings.NewReader(""), req)
+		assert.ErrorContains(t, err, message)
+	}
+}
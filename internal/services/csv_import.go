@@ -0,0 +1,1004 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultMaxCSVBytes is the largest CSV file accepted for an import
+	defaultMaxCSVBytes = 20 * 1024 * 1024
+	// maxCSVImportRows is the largest number of data rows of one CSV import
+	maxCSVImportRows = 50000
+	// maxCSVPreviewEntries is the number of entries a preview shows
+	maxCSVPreviewEntries = 100
+	// defaultCSVDayStart is when the first entry of a day without times starts
+	defaultCSVDayStart = "09:00"
+)
+
+// csvDateLayouts are the Go layouts of the supported CSV date formats
+var csvDateLayouts = map[string]string{
+	models.CSVDateISO: "2006-1-2",
+	models.CSVDateUS:  "1/2/2006",
+	models.CSVDateEU:  "2/1/2006",
+	models.CSVDateDot: "2.1.2006",
+}
+
+// csvClockLayouts are the times of day CSV files are read with, after upper-casing
+var csvClockLayouts = []string{"15:04:05", "15:04", "3:04:05 PM", "3:04 PM", "3:04:05PM", "3:04PM"}
+
+// trackerTaskTypes maps task names common in time trackers to activity types.
+// Task names that are activity types themselves need no entry.
+var trackerTaskTypes = map[string]models.ActivityType{
+	"programming":        models.ActivityDevelopment,
+	"coding":             models.ActivityDevelopment,
+	"meetings":           models.ActivityMeeting,
+	"code review":        models.ActivityCodeReview,
+	"review":             models.ActivityCodeReview,
+	"bug fixing":         models.ActivityDebugging,
+	"bugfixing":          models.ActivityDebugging,
+	"qa":                 models.ActivityTesting,
+	"release":            models.ActivityDeployment,
+	"project management": models.ActivityPlanning,
+	"training":           models.ActivityLearning,
+}
+
+// csvImportPresets are the built-in mappings of the CSV exports of common time trackers
+var csvImportPresets = []models.CSVImportPreset{
+	{
+		Name:        "toggl",
+		Description: "Toggl Track detailed report",
+		Mapping: models.CSVColumnMapping{
+			Title:      []string{"Description", "Task", "Project"},
+			Start:      "Start date",
+			StartTime:  "Start time",
+			End:        "End date",
+			EndTime:    "End time",
+			Duration:   "Duration",
+			DateFormat: models.CSVDateISO,
+			Project:    "Project",
+			Tags:       "Tags",
+			Type:       "Task",
+			TypeMap:    trackerTaskTypes,
+		},
+	},
+	{
+		Name:        "clockify",
+		Description: "Clockify detailed report",
+		Mapping: models.CSVColumnMapping{
+			Title:      []string{"Description", "Task", "Project"},
+			Start:      "Start Date",
+			StartTime:  "Start Time",
+			End:        "End Date",
+			EndTime:    "End Time",
+			Duration:   "Duration (h)",
+			DateFormat: models.CSVDateUS,
+			Project:    "Project",
+			Tags:       "Tags",
+			Type:       "Task",
+			TypeMap:    trackerTaskTypes,
+		},
+	},
+	{
+		Name:        "harvest",
+		Description: "Harvest detailed time report, with entries of a day placed one after another",
+		Mapping: models.CSVColumnMapping{
+			Title:        []string{"Notes", "Task", "Project"},
+			Start:        "Date",
+			Duration:     "Hours",
+			DurationUnit: models.CSVDurationHours,
+			DateFormat:   models.CSVDateISO,
+			Project:      "Project",
+			Type:         "Task",
+			TypeMap:      trackerTaskTypes,
+		},
+	},
+}
+
+// CSVImportService imports the CSV exports of other time trackers as log entries,
+// with the columns mapped to entry fields by a built-in preset, a profile the user
+// saved or a mapping given with the file
+type CSVImportService struct {
+	db           *database.DB
+	logger       *logging.Logger
+	logEntries   *LogEntryService
+	maxFileBytes int64
+}
+
+// NewCSVImportService creates a new CSVImportService instance
+func NewCSVImportService(db *database.DB, logger *logging.Logger, logEntries *LogEntryService) *CSVImportService {
+	return &CSVImportService{
+		db:           db,
+		logger:       logger.WithComponent("csv_import_service"),
+		logEntries:   logEntries,
+		maxFileBytes: defaultMaxCSVBytes,
+	}
+}
+
+// WithMaxFileBytes sets the largest CSV file accepted for an import;
+// non-positive values keep the default
+func (s *CSVImportService) WithMaxFileBytes(maxBytes int64) *CSVImportService {
+	if maxBytes > 0 {
+		s.maxFileBytes = maxBytes
+	}
+	return s
+}
+
+// MaxFileBytes returns the largest CSV file accepted by ImportCSV
+func (s *CSVImportService) MaxFileBytes() int64 {
+	return s.maxFileBytes
+}
+
+// GetPresets returns the built-in column mappings
+func (s *CSVImportService) GetPresets() []models.CSVImportPreset {
+	return csvImportPresets
+}
+
+// CreateProfile saves a column mapping under a name unique to the user
+func (s *CSVImportService) CreateProfile(ctx context.Context, userID string, req *models.CSVImportProfileRequest) (*models.CSVImportProfile, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateProfile", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	name, mapping, err := csvProfileParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Creating CSV import profile", "user_id", userID, "name", name)
+
+	var profile *models.CSVImportProfile
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := checkCSVProfileName(ctx, qtx, userUUID, uuid.Nil, name); err != nil {
+			return err
+		}
+
+		sqlcProfile, err := qtx.CreateCsvImportProfile(ctx, store.CreateCsvImportProfileParams{
+			UserID:  userUUID,
+			Name:    name,
+			Mapping: mapping,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create CSV import profile: %w", err)
+		}
+
+		profile, err = csvProfileToModel(sqlcProfile)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create CSV import profile", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("CSV import profile created successfully", "user_id", userID, "profile_id", profile.ID)
+	return profile, nil
+}
+
+// GetProfile retrieves a single CSV import profile owned by the user
+func (s *CSVImportService) GetProfile(ctx context.Context, userID, profileID string) (*models.CSVImportProfile, error) {
+	userUUID, profileUUID, err := s.parseIDs(ctx, userID, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile *models.CSVImportProfile
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		profile, err = getCSVProfile(ctx, qtx, userUUID, profileUUID)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get CSV import profile", "user_id", userID, "profile_id", profileID)
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// GetProfiles retrieves all CSV import profiles of a user, by name
+func (s *CSVImportService) GetProfiles(ctx context.Context, userID string) ([]*models.CSVImportProfile, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetProfiles", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var profiles []*models.CSVImportProfile
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcProfiles, err := qtx.GetCsvImportProfilesByUser(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		profiles = make([]*models.CSVImportProfile, len(sqlcProfiles))
+		for i, sqlcProfile := range sqlcProfiles {
+			if profiles[i], err = csvProfileToModel(sqlcProfile); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get CSV import profiles", "user_id", userID)
+		return nil, fmt.Errorf("failed to get CSV import profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// UpdateProfile replaces the name and mapping of a CSV import profile
+func (s *CSVImportService) UpdateProfile(ctx context.Context, userID, profileID string, req *models.CSVImportProfileRequest) (*models.CSVImportProfile, error) {
+	userUUID, profileUUID, err := s.parseIDs(ctx, userID, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, mapping, err := csvProfileParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Updating CSV import profile", "user_id", userID, "profile_id", profileID)
+
+	var profile *models.CSVImportProfile
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := checkCSVProfileName(ctx, qtx, userUUID, profileUUID, name); err != nil {
+			return err
+		}
+
+		sqlcProfile, err := qtx.UpdateCsvImportProfile(ctx, store.UpdateCsvImportProfileParams{
+			ID:      profileUUID,
+			UserID:  userUUID,
+			Name:    name,
+			Mapping: mapping,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("CSV import profile not found")
+			}
+			return fmt.Errorf("failed to update CSV import profile: %w", err)
+		}
+
+		profile, err = csvProfileToModel(sqlcProfile)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update CSV import profile", "user_id", userID, "profile_id", profileID)
+		return nil, err
+	}
+
+	s.logger.Info("CSV import profile updated successfully", "user_id", userID, "profile_id", profileID)
+	return profile, nil
+}
+
+// DeleteProfile deletes a CSV import profile. Entries imported with it are kept.
+func (s *CSVImportService) DeleteProfile(ctx context.Context, userID, profileID string) error {
+	userUUID, profileUUID, err := s.parseIDs(ctx, userID, profileID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteCsvImportProfile(ctx, store.DeleteCsvImportProfileParams{
+			ID:     profileUUID,
+			UserID: userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("CSV import profile not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete CSV import profile", "user_id", userID, "profile_id", profileID)
+		return err
+	}
+
+	s.logger.Info("CSV import profile deleted successfully", "user_id", userID, "profile_id", profileID)
+	return nil
+}
+
+// ImportCSV creates a log entry for every row of a CSV file, with the columns
+// mapped by the selected profile, preset or mapping. Rows that do not make a valid
+// entry are reported with their line and left out, as are rows with the title and
+// times of an existing entry. A preview checks every row without creating anything.
+func (s *CSVImportService) ImportCSV(ctx context.Context, userID string, file io.Reader, req *models.CSVImportRequest) (*models.CSVImportResult, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ImportCSV", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	selected := 0
+	for _, set := range []bool{req.ProfileID != nil, req.Preset != "", req.Mapping != nil} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return nil, fmt.Errorf("exactly one of a profile, a preset or a mapping is required")
+	}
+
+	var mapping models.CSVColumnMapping
+	switch {
+	case req.Preset != "":
+		preset, ok := csvImportPreset(req.Preset)
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV import preset: %s", req.Preset)
+		}
+		mapping = preset.Mapping
+	case req.Mapping != nil:
+		if err := validateCSVMapping(req.Mapping); err != nil {
+			return nil, err
+		}
+		mapping = *req.Mapping
+	}
+
+	var user store.User
+	var projects []store.Project
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		user, err = qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if req.ProfileID != nil {
+			profile, err := getCSVProfile(ctx, qtx, userUUID, *req.ProfileID)
+			if err != nil {
+				return err
+			}
+			mapping = profile.Mapping
+		}
+
+		projects, err = qtx.GetProjectsByUser(ctx, store.GetProjectsByUserParams{CreatedBy: userUUID})
+		if err != nil {
+			return fmt.Errorf("failed to get projects: %w", err)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to prepare CSV import", "user_id", userID)
+		return nil, err
+	}
+
+	timezone := mapping.Timezone
+	if timezone == "" {
+		timezone = pgTextToStringRequired(user.Timezone)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		s.logger.Warn("Stored timezone is invalid, importing the CSV file in UTC", "user_id", userID, "timezone", timezone)
+		location = time.UTC
+	}
+
+	limited := &io.LimitedReader{R: file, N: s.maxFileBytes + 1}
+	header, rows, err := readCSV(limited, mapping.Delimiter)
+	if limited.N <= 0 {
+		return nil, fmt.Errorf("CSV file is larger than the %d byte limit", s.maxFileBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV file: %w", err)
+	}
+
+	mapper, err := newCSVMapper(mapping, header, location, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	template := models.CSVImportResult{
+		Preview:           req.Preview,
+		Rows:              len(rows),
+		Errors:            []models.CSVImportError{},
+		MissingColumns:    mapper.missing,
+		UnmatchedProjects: []string{},
+	}
+
+	// Rows are mapped and validated before touching the database
+	var candidates []models.CSVImportRow
+	unmatched := map[string]bool{}
+	for _, row := range rows {
+		entry, project, err := mapper.entry(row.fields)
+		if err == nil {
+			err = s.logEntries.validateLogEntryRequest(&entry)
+		}
+		if err != nil {
+			template.Errors = append(template.Errors, models.CSVImportError{Row: row.line, Error: err.Error()})
+			continue
+		}
+		if project != "" && !unmatched[strings.ToLower(project)] {
+			unmatched[strings.ToLower(project)] = true
+			template.UnmatchedProjects = append(template.UnmatchedProjects, project)
+		}
+		candidates = append(candidates, models.CSVImportRow{Row: row.line, Entry: entry})
+	}
+
+	s.logger.Info("Importing CSV file", "user_id", userID, "rows", len(rows), "errors", len(template.Errors), "preview", req.Preview)
+
+	transaction := s.db.Write
+	if req.Preview {
+		transaction = s.db.Read
+	}
+
+	var result models.CSVImportResult
+	if err := transaction(ctx, func(qtx *store.Queries) error {
+		result = template
+
+		seen := map[string]bool{}
+		for _, candidate := range candidates {
+			entry := candidate.Entry
+			key := fmt.Sprintf("%s|%d|%d", entry.Title, entry.StartTime.Unix(), entry.EndTime.Unix())
+			if seen[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = true
+
+			exists, err := qtx.LogEntryExists(ctx, store.LogEntryExistsParams{
+				UserID:    userUUID,
+				Title:     entry.Title,
+				StartTime: timeToPgTimestamptz(entry.StartTime),
+				EndTime:   timeToPgTimestamptz(entry.EndTime),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to check row %d for duplicates: %w", candidate.Row, err)
+			}
+			if exists {
+				result.Duplicates++
+				continue
+			}
+
+			result.Valid++
+			if req.Preview {
+				if len(result.Entries) < maxCSVPreviewEntries {
+					result.Entries = append(result.Entries, candidate)
+				}
+				continue
+			}
+			if _, err := s.logEntries.createLogEntryTx(ctx, qtx, userUUID, &entry); err != nil {
+				return fmt.Errorf("failed to import row %d: %w", candidate.Row, err)
+			}
+			result.Created++
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to import CSV file", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Successfully imported CSV file", "user_id", userID, "rows", result.Rows,
+		"created", result.Created, "duplicates", result.Duplicates, "errors", len(result.Errors), "preview", result.Preview)
+	return &result, nil
+}
+
+func (s *CSVImportService) parseIDs(ctx context.Context, userID, profileID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	profileUUID, err := uuid.Parse(profileID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid CSV import profile ID format", "user_id", userID, "profile_id", profileID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid CSV import profile ID: %w", err)
+	}
+
+	return userUUID, profileUUID, nil
+}
+
+// csvImportPreset returns the built-in preset with the name, ignoring case
+func csvImportPreset(name string) (models.CSVImportPreset, bool) {
+	for _, preset := range csvImportPresets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return models.CSVImportPreset{}, false
+}
+
+// csvProfileParams validates a profile request and returns its name and encoded mapping
+func csvProfileParams(req *models.CSVImportProfileRequest) (string, []byte, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", nil, fmt.Errorf("name must be at most 100 characters")
+	}
+	if err := validateCSVMapping(&req.Mapping); err != nil {
+		return "", nil, err
+	}
+
+	mapping, err := json.Marshal(req.Mapping)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode mapping: %w", err)
+	}
+	return name, mapping, nil
+}
+
+// checkCSVProfileName rejects a name another profile of the user has, ignoring case
+func checkCSVProfileName(ctx context.Context, qtx *store.Queries, userUUID, profileUUID uuid.UUID, name string) error {
+	profiles, err := qtx.GetCsvImportProfilesByUser(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get CSV import profiles: %w", err)
+	}
+	for _, profile := range profiles {
+		if profile.ID != profileUUID && strings.EqualFold(profile.Name, name) {
+			return fmt.Errorf("a CSV import profile named %q already exists", profile.Name)
+		}
+	}
+	return nil
+}
+
+// getCSVProfile loads a CSV import profile owned by the user
+func getCSVProfile(ctx context.Context, qtx *store.Queries, userUUID, profileUUID uuid.UUID) (*models.CSVImportProfile, error) {
+	sqlcProfile, err := qtx.GetCsvImportProfileByID(ctx, store.GetCsvImportProfileByIDParams{
+		ID:     profileUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return nil, fmt.Errorf("CSV import profile not found")
+		}
+		return nil, fmt.Errorf("failed to get CSV import profile: %w", err)
+	}
+	return csvProfileToModel(sqlcProfile)
+}
+
+// csvProfileToModel converts a stored CSV import profile to its model
+func csvProfileToModel(sqlcProfile store.CsvImportProfile) (*models.CSVImportProfile, error) {
+	profile := &models.CSVImportProfile{
+		ID:        sqlcProfile.ID,
+		Name:      sqlcProfile.Name,
+		CreatedAt: pgTimestamptzToTime(sqlcProfile.CreatedAt),
+		UpdatedAt: pgTimestamptzToTime(sqlcProfile.UpdatedAt),
+	}
+	if err := json.Unmarshal(sqlcProfile.Mapping, &profile.Mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode CSV import profile %s: %w", sqlcProfile.ID, err)
+	}
+	return profile, nil
+}
+
+// validateCSVMapping checks that a column mapping names the columns an entry needs
+// and that its formats and values are supported
+func validateCSVMapping(mapping *models.CSVColumnMapping) error {
+	if mapping.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+		if size != len(mapping.Delimiter) || delimiter == utf8.RuneError || strings.ContainsRune("\"\r\n", delimiter) {
+			return fmt.Errorf("the delimiter must be a single character other than a quote or a line break")
+		}
+	}
+
+	hasTitle := false
+	for _, column := range mapping.Title {
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("title columns cannot be empty")
+		}
+		hasTitle = true
+	}
+	if !hasTitle {
+		return fmt.Errorf("a title column is required")
+	}
+	if strings.TrimSpace(mapping.Start) == "" {
+		return fmt.Errorf("a start column is required")
+	}
+	if mapping.End == "" && mapping.EndTime == "" && mapping.Duration == "" {
+		return fmt.Errorf("an end or duration column is required")
+	}
+
+	if mapping.DateFormat != "" {
+		if _, ok := csvDateLayouts[mapping.DateFormat]; !ok {
+			return fmt.Errorf("unsupported date format: %s", mapping.DateFormat)
+		}
+	}
+	switch mapping.DurationUnit {
+	case "", models.CSVDurationHours, models.CSVDurationMinutes:
+	default:
+		return fmt.Errorf("unsupported duration unit: %s", mapping.DurationUnit)
+	}
+	if mapping.Timezone != "" {
+		if _, err := time.LoadLocation(mapping.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %s", mapping.Timezone)
+		}
+	}
+	if mapping.DayStart != "" {
+		if _, err := time.Parse("15:04", mapping.DayStart); err != nil {
+			return fmt.Errorf("day start must be HH:MM")
+		}
+	}
+
+	for value, activityType := range mapping.TypeMap {
+		if !activityType.IsValid() {
+			return fmt.Errorf("invalid activity type for %q: %s", value, activityType)
+		}
+	}
+	if mapping.DefaultType != "" && !mapping.DefaultType.IsValid() {
+		return fmt.Errorf("invalid default type: %s", mapping.DefaultType)
+	}
+	if mapping.ValueRating != "" && !mapping.ValueRating.IsValid() {
+		return fmt.Errorf("invalid value rating: %s", mapping.ValueRating)
+	}
+	if mapping.ImpactLevel != "" && !mapping.ImpactLevel.IsValid() {
+		return fmt.Errorf("invalid impact level: %s", mapping.ImpactLevel)
+	}
+	return nil
+}
+
+// csvRow is a data row of a CSV file and the line it starts on
+type csvRow struct {
+	line   int
+	fields []string
+}
+
+// readCSV reads the header and the data rows of a CSV file, skipping blank rows
+func readCSV(r io.Reader, delimiter string) ([]string, []csvRow, error) {
+	reader := csv.NewReader(r)
+	if delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(delimiter)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := []csvRow{}
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return header, rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.TrimSpace(strings.Join(fields, "")) == "" {
+			continue
+		}
+		if len(rows) == maxCSVImportRows {
+			return nil, nil, fmt.Errorf("the file has more than %d rows", maxCSVImportRows)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, csvRow{line: line, fields: fields})
+	}
+}
+
+// csvMapper turns the rows of a CSV file into log entry requests following a column mapping
+type csvMapper struct {
+	mapping    models.CSVColumnMapping
+	columns    map[string]int // Lowercase header names to their index
+	location   *time.Location
+	dateLayout string
+	dayStart   time.Time
+	typeMap    map[string]models.ActivityType // Lowercase values of the type column
+	projects   map[string]*store.Project      // Lowercase project names
+	dayEnds    map[string]time.Time           // End of the last entry placed on each day without times
+	missing    []string                       // Mapped columns the file does not have
+}
+
+// newCSVMapper prepares the mapping of a file with the header. The start column and
+// one of the title columns must be in the file, as must an end or a duration column;
+// other missing columns leave their fields empty.
+func newCSVMapper(mapping models.CSVColumnMapping, header []string, location *time.Location, projects []store.Project) (*csvMapper, error) {
+	m := &csvMapper{
+		mapping:    mapping,
+		columns:    map[string]int{},
+		location:   location,
+		dateLayout: csvDateLayouts[models.CSVDateISO],
+		typeMap:    map[string]models.ActivityType{},
+		projects:   map[string]*store.Project{},
+		dayEnds:    map[string]time.Time{},
+		missing:    []string{},
+	}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := m.columns[key]; !ok {
+			m.columns[key] = i
+		}
+	}
+	if layout, ok := csvDateLayouts[mapping.DateFormat]; ok {
+		m.dateLayout = layout
+	}
+	dayStart := mapping.DayStart
+	if dayStart == "" {
+		dayStart = defaultCSVDayStart
+	}
+	m.dayStart, _ = time.Parse("15:04", dayStart)
+	for value, activityType := range mapping.TypeMap {
+		m.typeMap[strings.ToLower(strings.TrimSpace(value))] = activityType
+	}
+	for i := range projects {
+		m.projects[strings.ToLower(projects[i].Name)] = &projects[i]
+	}
+
+	for _, column := range []string{mapping.Start, mapping.StartTime} {
+		if column != "" && !m.has(column) {
+			return nil, fmt.Errorf("the file has no %q column", column)
+		}
+	}
+	hasTitle := false
+	for _, column := range mapping.Title {
+		hasTitle = hasTitle || m.has(column)
+	}
+	if !hasTitle {
+		return nil, fmt.Errorf("the file has none of the title columns: %s", strings.Join(mapping.Title, ", "))
+	}
+	if !m.has(mapping.End) && !m.has(mapping.EndTime) && !m.has(mapping.Duration) {
+		return nil, fmt.Errorf("the file has neither the end nor the duration columns")
+	}
+
+	optional := append(append([]string{}, mapping.Title...), mapping.End, mapping.EndTime, mapping.Duration,
+		mapping.Description, mapping.Project, mapping.Tags, mapping.Type)
+	for _, column := range optional {
+		if column != "" && !m.has(column) {
+			m.missing = append(m.missing, column)
+		}
+	}
+	return m, nil
+}
+
+// has reports whether the file has the column
+func (m *csvMapper) has(column string) bool {
+	if column == "" {
+		return false
+	}
+	_, ok := m.columns[strings.ToLower(strings.TrimSpace(column))]
+	return ok
+}
+
+// value returns the trimmed value of a column in the row, or "" when the
+// column is not mapped or not in the file
+func (m *csvMapper) value(fields []string, column string) string {
+	if column == "" {
+		return ""
+	}
+	i, ok := m.columns[strings.ToLower(strings.TrimSpace(column))]
+	if !ok || i >= len(fields) {
+		return ""
+	}
+	return strings.TrimSpace(fields[i])
+}
+
+// entry maps a row to a log entry request. The project name is returned when the
+// user has no project with it. Rows with dates but no times are placed one after
+// another from the day start, in the order of the file.
+func (m *csvMapper) entry(fields []string) (models.LogEntryRequest, string, error) {
+	var title string
+	for _, column := range m.mapping.Title {
+		if title = strings.Join(strings.Fields(m.value(fields, column)), " "); title != "" {
+			break
+		}
+	}
+	if title == "" {
+		return models.LogEntryRequest{}, "", fmt.Errorf("the title is empty")
+	}
+
+	start, startHasTime, err := m.dateTime(m.value(fields, m.mapping.Start), m.value(fields, m.mapping.StartTime))
+	if err != nil {
+		return models.LogEntryRequest{}, "", fmt.Errorf("invalid start: %w", err)
+	}
+
+	var duration time.Duration
+	if value := m.value(fields, m.mapping.Duration); value != "" {
+		if duration, err = parseCSVDuration(value, m.mapping.DurationUnit); err != nil {
+			return models.LogEntryRequest{}, "", fmt.Errorf("invalid duration: %w", err)
+		}
+	}
+
+	var end time.Time
+	endDate, endClock := m.value(fields, m.mapping.End), m.value(fields, m.mapping.EndTime)
+	if startHasTime && (endDate != "" || endClock != "") {
+		if endDate == "" {
+			endDate = start.Format(m.dateLayout)
+		}
+		parsed, endHasTime, err := m.dateTime(endDate, endClock)
+		if err != nil {
+			return models.LogEntryRequest{}, "", fmt.Errorf("invalid end: %w", err)
+		}
+		if endHasTime {
+			end = parsed
+			// An end time without a date that is not after the start is on the next day
+			if m.value(fields, m.mapping.End) == "" && !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+		}
+	}
+
+	switch {
+	case !end.IsZero():
+	case duration > 0 && startHasTime:
+		end = start.Add(duration)
+	case duration > 0:
+		day := start.Format(time.DateOnly)
+		if last, ok := m.dayEnds[day]; ok {
+			start = last
+		} else {
+			start = time.Date(start.Year(), start.Month(), start.Day(), m.dayStart.Hour(), m.dayStart.Minute(), 0, 0, m.location)
+		}
+		end = start.Add(duration)
+		m.dayEnds[day] = end
+	default:
+		return models.LogEntryRequest{}, "", fmt.Errorf("the row has neither an end time nor a duration")
+	}
+	if end.Sub(start) > maxLogEntryDuration {
+		return models.LogEntryRequest{}, "", fmt.Errorf("entries can be at most 24 hours long")
+	}
+
+	entry := models.LogEntryRequest{
+		Title:       shortenText(title, maxProposedTitleBytes),
+		Type:        m.activityType(m.value(fields, m.mapping.Type)),
+		StartTime:   start,
+		EndTime:     end,
+		ValueRating: m.mapping.ValueRating,
+		ImpactLevel: m.mapping.ImpactLevel,
+		Tags:        m.tags(m.value(fields, m.mapping.Tags)),
+	}
+	if description := m.value(fields, m.mapping.Description); description != "" {
+		description = shortenText(description, maxProposedDescriptionBytes)
+		entry.Description = &description
+	}
+
+	var unmatched string
+	if name := m.value(fields, m.mapping.Project); name != "" {
+		if project, ok := m.projects[strings.ToLower(name)]; ok {
+			entry.ProjectID = &project.ID
+			projectDefaultsToModel(*project).Apply(&entry)
+		} else {
+			unmatched = name
+		}
+	}
+
+	if entry.Type == "" {
+		entry.Type = m.mapping.DefaultType
+		if entry.Type == "" {
+			entry.Type = models.ActivityOther
+		}
+	}
+	if entry.ValueRating == "" {
+		entry.ValueRating = models.ValueMedium
+	}
+	if entry.ImpactLevel == "" {
+		entry.ImpactLevel = models.ImpactTeam
+	}
+	return entry, unmatched, nil
+}
+
+// dateTime reads a date, with the time of day from the separate clock value or
+// from the rest of the date value. Times are in the mapper's location unless they
+// carry an offset; it reports whether there was a time.
+func (m *csvMapper) dateTime(dateValue, clockValue string) (time.Time, bool, error) {
+	if dateValue == "" {
+		return time.Time{}, false, fmt.Errorf("the date is empty")
+	}
+	if clockValue == "" {
+		if t, err := time.Parse(time.RFC3339, dateValue); err == nil {
+			return t.In(m.location), true, nil
+		}
+		if i := strings.IndexAny(dateValue, " T"); i > 0 {
+			dateValue, clockValue = dateValue[:i], strings.TrimSpace(dateValue[i+1:])
+		}
+	}
+
+	day, err := time.Parse(m.dateLayout, dateValue)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q does not match the date format", dateValue)
+	}
+	if clockValue == "" {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, m.location), false, nil
+	}
+
+	clock, err := parseCSVClock(clockValue)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, m.location), true, nil
+}
+
+// activityType looks a value of the type column up in the type map, falling back
+// to the activity type of the same name; unknown values return ""
+func (m *csvMapper) activityType(value string) models.ActivityType {
+	key := strings.ToLower(strings.TrimSpace(value))
+	if key == "" {
+		return ""
+	}
+	if activityType, ok := m.typeMap[key]; ok {
+		return activityType
+	}
+	if activityType := models.ActivityType(strings.NewReplacer(" ", "_", "-", "_").Replace(key)); activityType.IsValid() {
+		return activityType
+	}
+	return ""
+}
+
+// tags splits a value of the tags column, dropping empty and repeated tags
+func (m *csvMapper) tags(value string) []string {
+	separator := m.mapping.TagSeparator
+	if separator == "" {
+		separator = ","
+	}
+
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(value, separator) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// parseCSVClock reads a time of day in 24-hour or 12-hour notation
+func parseCSVClock(value string) (time.Time, error) {
+	value = strings.ToUpper(value)
+	for _, layout := range csvClockLayouts {
+		if clock, err := time.Parse(layout, value); err == nil {
+			return clock, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time of day", value)
+}
+
+// parseCSVDuration reads a duration as hours, minutes and optional seconds
+// separated by colons, or as a plain number in the unit (hours by default).
+// Decimal commas are accepted.
+func parseCSVDuration(value, unit string) (time.Duration, error) {
+	var duration time.Duration
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("%q is not a duration", value)
+		}
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || (i > 0 && n > 59) {
+				return 0, fmt.Errorf("%q is not a duration", value)
+			}
+			duration += time.Duration(n) * units[i]
+		}
+	} else {
+		n, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		// The bounds also reject NaN and durations too long to represent
+		if err != nil || !(n >= 0 && n <= 1e6) {
+			return 0, fmt.Errorf("%q is not a duration", value)
+		}
+		scale := time.Hour
+		if unit == models.CSVDurationMinutes {
+			scale = time.Minute
+		}
+		duration = time.Duration(n * float64(scale)).Round(time.Second)
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("the duration must be positive")
+	}
+	return duration, nil
+}
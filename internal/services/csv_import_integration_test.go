@@ -0,0 +1,159 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var csvImportTogglFile = strings.Join([]string{
+	"User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()",
+	"Jane,jane@example.com,Acme,Payments,Code review,Review refunds PR,No,2025-03-10,09:00:00,2025-03-10,10:30:00,01:30:00,\"backend, review\",",
+	"Jane,jane@example.com,Acme,Legacy,,Fix flaky test,No,2025-03-10,11:00:00,2025-03-10,11:45:00,00:45:00,,",
+	"Jane,jane@example.com,Acme,Payments,,Backwards,No,2025-03-10,15:00:00,2025-03-10,14:00:00,01:00:00,,",
+	"Jane,jane@example.com,Acme,Payments,Code review,Review refunds PR,No,2025-03-10,09:00:00,2025-03-10,10:30:00,01:30:00,,",
+}, "\n")
+
+// TestCSVImportService_ImportCSV tests previews, imports with a preset, per-row errors and duplicates
+func TestCSVImportService_ImportCSV(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	csvImportService := services.NewCSVImportService(db, testLogger, logEntryService)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "csv-import@example.com",
+		Password:  "password123",
+		FirstName: "CSV",
+		LastName:  "Import",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "payments",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	t.Run("Preview", func(t *testing.T) {
+		result, err := csvImportService.ImportCSV(ctx, userID, strings.NewReader(csvImportTogglFile), &models.CSVImportRequest{
+			Preset:  "toggl",
+			Preview: true,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, result.Preview)
+		assert.Equal(t, 4, result.Rows)
+		assert.Equal(t, 2, result.Valid)
+		assert.Zero(t, result.Created)
+		assert.Equal(t, 1, result.Duplicates, "the last row repeats the first")
+		assert.Equal(t, []models.CSVImportError{{Row: 4, Error: "end time must be after start time"}}, result.Errors)
+		assert.Equal(t, []string{"Legacy"}, result.UnmatchedProjects)
+		require.Len(t, result.Entries, 2)
+		assert.Equal(t, 2, result.Entries[0].Row)
+		assert.Equal(t, &project.ID, result.Entries[0].Entry.ProjectID)
+
+		entries, err := logEntryService.GetLogEntries(ctx, userID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "previews create nothing")
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		result, err := csvImportService.ImportCSV(ctx, userID, strings.NewReader(csvImportTogglFile), &models.CSVImportRequest{Preset: "toggl"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Len(t, result.Errors, 1)
+		assert.Empty(t, result.Entries)
+
+		entries, err := logEntryService.GetLogEntries(ctx, userID, nil)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		var review *models.LogEntry
+		for _, entry := range entries {
+			if entry.Title == "Review refunds PR" {
+				review = entry
+			}
+		}
+		require.NotNil(t, review)
+		assert.Equal(t, models.ActivityCodeReview, review.Type)
+		assert.Equal(t, &project.ID, review.ProjectID)
+		assert.True(t, review.StartTime.Equal(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)), "times are in the profile timezone")
+		assert.ElementsMatch(t, []string{"backend", "review"}, review.Tags)
+	})
+
+	t.Run("ReimportSkipsDuplicates", func(t *testing.T) {
+		result, err := csvImportService.ImportCSV(ctx, userID, strings.NewReader(csvImportTogglFile), &models.CSVImportRequest{Preset: "toggl"})
+		require.NoError(t, err)
+		assert.Zero(t, result.Created)
+		assert.Equal(t, 3, result.Duplicates)
+	})
+
+	t.Run("Profiles", func(t *testing.T) {
+		harvest, ok := func() (models.CSVColumnMapping, bool) {
+			for _, preset := range csvImportService.GetPresets() {
+				if preset.Name == "harvest" {
+					return preset.Mapping, true
+				}
+			}
+			return models.CSVColumnMapping{}, false
+		}()
+		require.True(t, ok)
+		harvest.DayStart = "08:00"
+
+		profile, err := csvImportService.CreateProfile(ctx, userID, &models.CSVImportProfileRequest{Name: "Harvest 2024", Mapping: harvest})
+		require.NoError(t, err)
+		assert.Equal(t, "08:00", profile.Mapping.DayStart)
+
+		_, err = csvImportService.CreateProfile(ctx, userID, &models.CSVImportProfileRequest{Name: "harvest 2024", Mapping: harvest})
+		assert.ErrorContains(t, err, "already exists")
+
+		harvest.Timezone = "UTC"
+		updated, err := csvImportService.UpdateProfile(ctx, userID, profile.ID.String(), &models.CSVImportProfileRequest{Name: "Harvest", Mapping: harvest})
+		require.NoError(t, err)
+		assert.Equal(t, "Harvest", updated.Name)
+
+		result, err := csvImportService.ImportCSV(ctx, userID, strings.NewReader("Date,Project,Task,Notes,Hours\n2025-03-12,Payments,Meetings,,1.5\n"), &models.CSVImportRequest{
+			ProfileID: &profile.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+
+		meeting := models.ActivityMeeting
+		entries, err := logEntryService.GetLogEntries(ctx, userID, &services.LogEntryFilters{Type: &meeting})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, entries[0].StartTime.Equal(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC)))
+
+		profiles, err := csvImportService.GetProfiles(ctx, userID)
+		require.NoError(t, err)
+		assert.Len(t, profiles, 1)
+
+		require.NoError(t, csvImportService.DeleteProfile(ctx, userID, profile.ID.String()))
+		_, err = csvImportService.GetProfile(ctx, userID, profile.ID.String())
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("MissingColumns", func(t *testing.T) {
+		_, err := csvImportService.ImportCSV(ctx, userID, strings.NewReader("Title,Hours\nx,1\n"), &models.CSVImportRequest{Preset: "harvest"})
+		assert.ErrorContains(t, err, `no "Date" column`)
+	})
+}
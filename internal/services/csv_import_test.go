@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCSV reads a CSV file with a preset and maps its rows, failing on mapping errors
+func mapCSV(t *testing.T, preset, file string, location *time.Location, projects ...store.Project) ([]models.LogEntryRequest, []string) {
+	t.Helper()
+	found, ok := csvImportPreset(preset)
+	require.True(t, ok)
+	require.NoError(t, validateCSVMapping(&found.Mapping))
+
+	header, rows, err := readCSV(strings.NewReader(file), found.Mapping.Delimiter)
+	require.NoError(t, err)
+	mapper, err := newCSVMapper(found.Mapping, header, location, projects)
+	require.NoError(t, err)
+
+	var entries []models.LogEntryRequest
+	var unmatched []string
+	for _, row := range rows {
+		entry, project, err := mapper.entry(row.fields)
+		require.NoError(t, err, "row %d", row.line)
+		entries = append(entries, entry)
+		unmatched = append(unmatched, project)
+	}
+	return entries, unmatched
+}
+
+func TestCSVImportPresets(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	api := store.Project{
+		ID:                 uuid.New(),
+		Name:               "API",
+		DefaultValueRating: pgtype.Text{String: string(models.ValueHigh), Valid: true},
+	}
+
+	t.Run("Toggl", func(t *testing.T) {
+		file := "\ufeffUser,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+			"Jane,jane@example.com,Acme,api,Code review,Review payments PR,No,2025-03-10,09:00:00,2025-03-10,10:30:00,01:30:00,\"backend, payments\",\n" +
+			"Jane,jane@example.com,,Website,,,No,2025-03-10,23:30:00,2025-03-11,00:15:00,00:45:00,,\n"
+		entries, unmatched := mapCSV(t, "toggl", file, saoPaulo, api)
+		require.Len(t, entries, 2)
+
+		review := entries[0]
+		assert.Equal(t, "Review payments PR", review.Title)
+		assert.Equal(t, models.ActivityCodeReview, review.Type)
+		assert.True(t, review.StartTime.Equal(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)), "times are in the given timezone")
+		assert.Equal(t, 90*time.Minute, review.EndTime.Sub(review.StartTime))
+		assert.Equal(t, []string{"backend", "payments"}, review.Tags)
+		assert.Equal(t, &api.ID, review.ProjectID, "projects match by name ignoring case")
+		assert.Equal(t, models.ValueHigh, review.ValueRating, "project defaults fill the entry")
+		assert.Equal(t, models.ImpactTeam, review.ImpactLevel)
+		assert.Empty(t, unmatched[0])
+
+		website := entries[1]
+		assert.Equal(t, "Website", website.Title, "the project names entries without a description")
+		assert.Equal(t, models.ActivityOther, website.Type)
+		assert.Equal(t, 45*time.Minute, website.EndTime.Sub(website.StartTime))
+		assert.Nil(t, website.ProjectID)
+		assert.Equal(t, "Website", unmatched[1])
+	})
+
+	t.Run("Clockify", func(t *testing.T) {
+		file := "Project,Client,Description,Task,User,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+			"API,Acme,Deploy v2,Deployment,Jane,,Yes,03/10/2025,02:15:00 PM,03/10/2025,03:00:00 PM,00:45:00,0.75\n"
+		entries, _ := mapCSV(t, "clockify", file, time.UTC, api)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Deploy v2", entries[0].Title)
+		assert.Equal(t, models.ActivityDeployment, entries[0].Type, "task names that are activity types map to them")
+		assert.Equal(t, time.Date(2025, 3, 10, 14, 15, 0, 0, time.UTC), entries[0].StartTime)
+		assert.Equal(t, time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), entries[0].EndTime)
+	})
+
+	t.Run("Harvest", func(t *testing.T) {
+		file := "Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded\n" +
+			"2025-03-10,Acme,API,,Programming,Payments webhooks,2.5,2.5\n" +
+			"2025-03-10,Acme,API,,Meetings,,\"0,5\",0.5\n" +
+			"2025-03-11,Acme,API,,Design,Mockups,1,1\n"
+		entries, _ := mapCSV(t, "harvest", file, saoPaulo, api)
+		require.Len(t, entries, 3)
+
+		assert.Equal(t, "Payments webhooks", entries[0].Title)
+		assert.Equal(t, models.ActivityDevelopment, entries[0].Type)
+		assert.Equal(t, time.Date(2025, 3, 10, 9, 0, 0, 0, saoPaulo), entries[0].StartTime, "days start at 09:00")
+		assert.Equal(t, time.Date(2025, 3, 10, 11, 30, 0, 0, saoPaulo), entries[0].EndTime)
+
+		assert.Equal(t, "Meetings", entries[1].Title)
+		assert.Equal(t, models.ActivityMeeting, entries[1].Type)
+		assert.Equal(t, entries[0].EndTime, entries[1].StartTime, "entries of a day follow each other")
+		assert.Equal(t, 30*time.Minute, entries[1].EndTime.Sub(entries[1].StartTime))
+
+		assert.Equal(t, time.Date(2025, 3, 11, 9, 0, 0, 0, saoPaulo), entries[2].StartTime)
+		assert.Equal(t, models.ActivityOther, entries[2].Type)
+	})
+}
+
+func TestCSVMapper_Entry(t *testing.T) {
+	mapping := models.CSVColumnMapping{
+		Delimiter:    ";",
+		Title:        []string{"what"},
+		Description:  "notes",
+		Start:        "from",
+		End:          "to",
+		Duration:     "minutes",
+		DurationUnit: models.CSVDurationMinutes,
+		DateFormat:   models.CSVDateDot,
+		Tags:         "labels",
+		TagSeparator: "|",
+		Type:         "kind",
+		TypeMap:      map[string]models.ActivityType{"Ops": models.ActivityMaintenance},
+		DefaultType:  models.ActivityDevelopment,
+		ValueRating:  models.ValueLow,
+		DayStart:     "08:30",
+	}
+	require.NoError(t, validateCSVMapping(&mapping))
+
+	header, rows, err := readCSV(strings.NewReader(
+		"What;Notes;From;To;Minutes;Labels;Kind;Other\n"+
+			"Patch servers;;10.3.2025 14:00;10.3.2025 15:00;;ops|Ops|infra;OPS\n"+
+			"\"Write\n docs\";  long notes  ;10.3.2025;;20;;research\n"+
+			";;;;\n"+
+			"Pair programming;;2025-03-10T16:00:00Z;;45;;unknown\n"+
+			"Broken;;10.3.2025 14:00;;;;\n"+
+			"Broken;;13.13.2025;;30;;\n"+
+			";;10.3.2025;;30;;\n"+
+			"Too long;;10.3.2025;;1500;;\n"),
+		mapping.Delimiter)
+	require.NoError(t, err)
+	require.Len(t, rows, 7, "blank rows are skipped")
+	assert.Equal(t, []int{2, 3, 6, 7, 8, 9, 10}, []int{rows[0].line, rows[1].line, rows[2].line, rows[3].line, rows[4].line, rows[5].line, rows[6].line},
+		"rows keep the line they start on")
+
+	mapper, err := newCSVMapper(mapping, header, time.UTC, nil)
+	require.NoError(t, err)
+	assert.Empty(t, mapper.missing)
+
+	entry, _, err := mapper.entry(rows[0].fields)
+	require.NoError(t, err)
+	assert.Equal(t, "Patch servers", entry.Title)
+	assert.Equal(t, models.ActivityMaintenance, entry.Type, "type map keys ignore case")
+	assert.Equal(t, []string{"ops", "infra"}, entry.Tags)
+	assert.Equal(t, models.ValueLow, entry.ValueRating)
+	assert.Nil(t, entry.Description)
+	assert.Equal(t, time.Hour, entry.EndTime.Sub(entry.StartTime))
+
+	entry, _, err = mapper.entry(rows[1].fields)
+	require.NoError(t, err)
+	assert.Equal(t, "Write docs", entry.Title)
+	require.NotNil(t, entry.Description)
+	assert.Equal(t, "long notes", *entry.Description)
+	assert.Equal(t, models.ActivityResearch, entry.Type)
+	assert.Equal(t, time.Date(2025, 3, 10, 8, 30, 0, 0, time.UTC), entry.StartTime)
+	assert.Equal(t, 20*time.Minute, entry.EndTime.Sub(entry.StartTime))
+
+	entry, _, err = mapper.entry(rows[2].fields)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 10, 16, 0, 0, 0, time.UTC), entry.StartTime, "RFC 3339 times are accepted in any date format")
+	assert.Equal(t, 45*time.Minute, entry.EndTime.Sub(entry.StartTime))
+	assert.Equal(t, models.ActivityDevelopment, entry.Type, "unknown types get the default type")
+
+	for i, message := range map[int]string{
+		3: "neither an end time nor a duration",
+		4: "invalid start",
+		5: "the title is empty",
+		6: "at most 24 hours",
+	} {
+		_, _, err := mapper.entry(rows[i].fields)
+		assert.ErrorContains(t, err, message, "row %d", rows[i].line)
+	}
+}
+
+func TestNewCSVMapper_Columns(t *testing.T) {
+	mapping := models.CSVColumnMapping{
+		Title:       []string{"Title", "Notes"},
+		Start:       "Start",
+		End:         "End",
+		Duration:    "Duration",
+		Project:     "Project",
+		Description: "Description",
+	}
+
+	mapper, err := newCSVMapper(mapping, []string{" title ", "START", "Duration"}, time.UTC, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Notes", "End", "Description", "Project"}, mapper.missing)
+
+	_, err = newCSVMapper(mapping, []string{"Title", "Duration"}, time.UTC, nil)
+	assert.ErrorContains(t, err, `no "Start" column`)
+
+	_, err = newCSVMapper(mapping, []string{"Name", "Start", "End"}, time.UTC, nil)
+	assert.ErrorContains(t, err, "none of the title columns")
+
+	_, err = newCSVMapper(mapping, []string{"Title", "Start"}, time.UTC, nil)
+	assert.ErrorContains(t, err, "neither the end nor the duration")
+}
+
+func TestParseCSVDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"1:30":     90 * time.Minute,
+		"01:30:15": 90*time.Minute + 15*time.Second,
+		"25:00:00": 25 * time.Hour,
+		"1.25":     75 * time.Minute,
+		"0,5":      30 * time.Minute,
+		"2":        2 * time.Hour,
+	} {
+		duration, err := parseCSVDuration(value, "")
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, duration, value)
+	}
+
+	duration, err := parseCSVDuration("90", models.CSVDurationMinutes)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, duration)
+
+	for _, value := range []string{"abc", "1:60", "1:2:3:4", "-1", "NaN", "1e9", "0", "0:00"} {
+		_, err := parseCSVDuration(value, "")
+		assert.Error(t, err, value)
+	}
+}
+
+func TestValidateCSVMapping(t *testing.T) {
+	valid := func() models.CSVColumnMapping {
+		return models.CSVColumnMapping{Title: []string{"Title"}, Start: "Start", Duration: "Hours"}
+	}
+	mapping := valid()
+	assert.NoError(t, validateCSVMapping(&mapping))
+
+	for message, change := range map[string]func(*models.CSVColumnMapping){
+		"delimiter must be a single character": func(m *models.CSVColumnMapping) { m.Delimiter = ";;" },
+		"a title column is required":           func(m *models.CSVColumnMapping) { m.Title = nil },
+		"title columns cannot be empty":        func(m *models.CSVColumnMapping) { m.Title = []string{" "} },
+		"a start column is required":           func(m *models.CSVColumnMapping) { m.Start = "" },
+		"an end or duration column":            func(m *models.CSVColumnMapping) { m.Duration = "" },
+		"unsupported date format":              func(m *models.CSVColumnMapping) { m.DateFormat = "YY-MM-DD" },
+		"unsupported duration unit":            func(m *models.CSVColumnMapping) { m.DurationUnit = "days" },
+		"invalid timezone":                     func(m *models.CSVColumnMapping) { m.Timezone = "Mars/Olympus" },
+		"day start must be HH:MM":              func(m *models.CSVColumnMapping) { m.DayStart = "9am" },
+		"invalid activity type":                func(m *models.CSVColumnMapping) { m.TypeMap = map[string]models.ActivityType{"x": "coffee"} },
+		"invalid default type":                 func(m *models.CSVColumnMapping) { m.DefaultType = "coffee" },
+		"invalid value rating":                 func(m *models.CSVColumnMapping) { m.ValueRating = "huge" },
+		"invalid impact level":                 func(m *models.CSVColumnMapping) { m.ImpactLevel = "galaxy" },
+	} {
+		mapping := valid()
+		change(&mapping)
+		assert.ErrorContains(t, validateCSVMapping(&mapping), message)
+	}
+}
+
+func TestCSVImportService_RejectsInvalidInput(t *testing.T) {
+	service := NewCSVImportService(nil, logging.NewTestLogger(), nil).WithMaxFileBytes(64)
+	ctx := context.Background()
+
+	assert.Equal(t, int64(64), service.MaxFileBytes())
+	assert.Equal(t, int64(64), service.WithMaxFileBytes(0).MaxFileBytes(), "non-positive limits keep the current one")
+	assert.Len(t, service.GetPresets(), 3)
+
+	_, err := service.ImportCSV(ctx, "not-a-uuid", strings.NewReader(""), &models.CSVImportRequest{Preset: "toggl"})
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	userID := uuid.NewString()
+	profileID := uuid.New()
+	for message, req := range map[string]*models.CSVImportRequest{
+		"exactly one of":            {},
+		"exactly one of a profile":  {Preset: "toggl", ProfileID: &profileID},
+		"unknown CSV import preset": {Preset: "timesheet"},
+		"a start column":            {Mapping: &models.CSVColumnMapping{Title: []string{"Title"}}},
+	} {
+		_, err := service.ImportCSV(ctx, userID, strings.NewReader(""), req)
+		assert.ErrorContains(t, err, message)
+	}
+
+	_, err = service.CreateProfile(ctx, userID, &models.CSVImportProfileRequest{Name: "  "})
+	assert.ErrorContains(t, err, "name is required")
+	_, err = service.CreateProfile(ctx, userID, &models.CSVImportProfileRequest{Name: "Toggl"})
+	assert.ErrorContains(t, err, "a title column is required")
+	_, err = service.UpdateProfile(ctx, userID, "not-a-uuid", &models.CSVImportProfileRequest{Name: "Toggl"})
+	assert.ErrorContains(t, err, "invalid CSV import profile ID")
+}
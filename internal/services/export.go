@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ExportService streams a user's data as CSV or JSON Lines. Rows are read through
+// database cursors and written as they arrive, so exports of long histories never
+// hold more than one batch in memory.
+type ExportService struct {
+	db     *database.DB
+	logger *logging.Logger
+}
+
+// NewExportService creates a new ExportService instance
+func NewExportService(db *database.DB, logger *logging.Logger) *ExportService {
+	return &ExportService{
+		db:     db,
+		logger: logger.WithComponent("export_service"),
+	}
+}
+
+// ExportLogEntries writes the user's log entries to w, oldest first. Times are in
+// the user's profile timezone, which is also the timezone of the filter dates.
+// Nothing is written to w when an error is returned before the first row.
+func (s *ExportService) ExportLogEntries(ctx context.Context, userID string, format models.ExportFormat, filter *models.LogEntryExportFilter, w io.Writer) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ExportLogEntries", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if !format.IsValid() {
+		return fmt.Errorf("invalid export format: %s", format)
+	}
+
+	if filter == nil {
+		filter = &models.LogEntryExportFilter{}
+	}
+
+	var projectUUID uuid.UUID
+	if filter.ProjectID != "" {
+		projectUUID, err = uuid.Parse(filter.ProjectID)
+		if err != nil {
+			return fmt.Errorf("invalid project ID: %w", err)
+		}
+	}
+
+	startDay, endDay, err := parseExportDates(filter.StartDate, filter.EndDate)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Exporting log entries", "user_id", userID, "format", format, "start_date", filter.StartDate, "end_date", filter.EndDate, "project_id", filter.ProjectID)
+
+	rows := 0
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		location, err := s.userLocation(ctx, qtx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		params := store.ExportLogEntriesParams{UserID: userUUID}
+		if startDay != nil {
+			params.StartTime = timeToPgTimestamptz(inLocation(*startDay, location))
+		}
+		if endDay != nil {
+			params.EndTime = timeToPgTimestamptz(inLocation(*endDay, location).AddDate(0, 0, 1).Add(-time.Nanosecond))
+		}
+		if filter.ProjectID != "" {
+			if _, err := authorizeProject(ctx, qtx, projectUUID, userUUID, models.TeamPermissionView); err != nil {
+				return err
+			}
+			params.ProjectID = uuidToPgUUID(&projectUUID)
+		}
+
+		export, err := newExportWriter(w, format, models.LogEntryExportColumns)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.StreamExportLogEntries(ctx, params, func(row store.ExportLogEntriesRow) error {
+			rows++
+			return export.write(logEntryExportToModel(row, location))
+		}); err != nil {
+			return fmt.Errorf("failed to export log entries: %w", err)
+		}
+
+		return export.flush()
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to export log entries", "user_id", userID, "rows", rows)
+		return err
+	}
+
+	s.logger.Info("Successfully exported log entries", "user_id", userID, "format", format, "rows", rows)
+	return nil
+}
+
+// ExportProjects writes the projects the user owns to w, by name, with the time
+// the user logged on each
+func (s *ExportService) ExportProjects(ctx context.Context, userID string, format models.ExportFormat, w io.Writer) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ExportProjects", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if !format.IsValid() {
+		return fmt.Errorf("invalid export format: %s", format)
+	}
+
+	s.logger.Info("Exporting projects", "user_id", userID, "format", format)
+
+	rows := 0
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		location, err := s.userLocation(ctx, qtx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		export, err := newExportWriter(w, format, models.ProjectExportColumns)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.StreamExportProjects(ctx, userUUID, func(row store.ExportProjectsRow) error {
+			rows++
+			return export.write(projectExportToModel(row, location))
+		}); err != nil {
+			return fmt.Errorf("failed to export projects: %w", err)
+		}
+
+		return export.flush()
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to export projects", "user_id", userID, "rows", rows)
+		return err
+	}
+
+	s.logger.Info("Successfully exported projects", "user_id", userID, "format", format, "rows", rows)
+	return nil
+}
+
+// ExportTagUsage writes the user's tags to w, most used first, with how often and
+// how long each was used
+func (s *ExportService) ExportTagUsage(ctx context.Context, userID string, format models.ExportFormat, w io.Writer) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ExportTagUsage", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if !format.IsValid() {
+		return fmt.Errorf("invalid export format: %s", format)
+	}
+
+	s.logger.Info("Exporting tag usage", "user_id", userID, "format", format)
+
+	rows := 0
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		location, err := s.userLocation(ctx, qtx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		export, err := newExportWriter(w, format, models.TagUsageExportColumns)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.StreamExportTagUsage(ctx, userUUID, func(row store.ExportTagUsageRow) error {
+			rows++
+			return export.write(tagUsageExportToModel(row, location))
+		}); err != nil {
+			return fmt.Errorf("failed to export tag usage: %w", err)
+		}
+
+		return export.flush()
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to export tag usage", "user_id", userID, "rows", rows)
+		return err
+	}
+
+	s.logger.Info("Successfully exported tag usage", "user_id", userID, "format", format, "rows", rows)
+	return nil
+}
+
+// userLocation returns the user's profile timezone, or UTC when the stored one is invalid
+func (s *ExportService) userLocation(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID) (*time.Location, error) {
+	user, err := qtx.GetUserByID(ctx, userUUID)
+	if err != nil {
+		if database.NoRows(err) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	location, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+	if err != nil {
+		s.logger.Warn("Stored timezone is invalid, exporting times in UTC", "user_id", userUUID, "timezone", user.Timezone.String)
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+// parseExportDates parses the optional first and last day (YYYY-MM-DD) of an export
+func parseExportDates(startDate, endDate string) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+
+	if startDate != "" {
+		day, err := time.Parse(time.DateOnly, startDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+		}
+		start = &day
+	}
+
+	if endDate != "" {
+		day, err := time.Parse(time.DateOnly, endDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+		}
+		end = &day
+	}
+
+	if start != nil && end != nil && end.Before(*start) {
+		return nil, nil, fmt.Errorf("end date must be on or after start date")
+	}
+
+	return start, end, nil
+}
+
+// inLocation returns midnight of day's calendar date in loc
+func inLocation(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
+
+// exportRecord is a row of a data export
+type exportRecord interface {
+	CSVRecord() []string
+}
+
+// exportWriter encodes export rows as CSV, with a header row, or as JSON Lines
+type exportWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+// newExportWriter creates an exportWriter; the CSV header is buffered with the first rows
+func newExportWriter(w io.Writer, format models.ExportFormat, columns []string) (*exportWriter, error) {
+	if format == models.ExportJSONL {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &exportWriter{json: encoder}, nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to write export header: %w", err)
+	}
+	return &exportWriter{csv: writer}, nil
+}
+
+// write encodes one row
+func (e *exportWriter) write(record exportRecord) error {
+	if e.json != nil {
+		if err := e.json.Encode(record); err != nil {
+			return fmt.Errorf("failed to write export row: %w", err)
+		}
+		return nil
+	}
+
+	if err := e.csv.Write(record.CSVRecord()); err != nil {
+		return fmt.Errorf("failed to write export row: %w", err)
+	}
+	return nil
+}
+
+// flush writes any buffered rows
+func (e *exportWriter) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// logEntryExportToModel converts an export row, with times in loc
+func logEntryExportToModel(row store.ExportLogEntriesRow, loc *time.Location) *models.LogEntryExport {
+	minutes := pgInt4ToInt(row.DurationMinutes)
+
+	return &models.LogEntryExport{
+		ID:              row.ID,
+		Title:           row.Title,
+		Description:     pgTextToString(row.Description),
+		Type:            models.ActivityType(row.Type),
+		ProjectID:       pgUUIDToUUID(row.ProjectID),
+		ProjectName:     pgTextToString(row.ProjectName),
+		StartTime:       pgTimestamptzToTime(row.StartTime).In(loc),
+		EndTime:         pgTimestamptzToTime(row.EndTime).In(loc),
+		DurationMinutes: minutes,
+		DurationHours:   minutesToHours(minutes),
+		ValueRating:     models.ValueRating(row.ValueRating),
+		ImpactLevel:     models.ImpactLevel(row.ImpactLevel),
+		Tags:            row.Tags,
+		CreatedAt:       pgTimestamptzToTime(row.CreatedAt).In(loc),
+		UpdatedAt:       pgTimestamptzToTime(row.UpdatedAt).In(loc),
+	}
+}
+
+// projectExportToModel converts an export row, with times in loc
+func projectExportToModel(row store.ExportProjectsRow, loc *time.Location) *models.ProjectExport {
+	minutes := int(row.TotalMinutes)
+
+	return &models.ProjectExport{
+		ID:             row.ID,
+		Name:           row.Name,
+		Description:    pgTextToString(row.Description),
+		Status:         models.ProjectStatus(pgTextToStringRequired(row.Status)),
+		Color:          pgTextToStringRequired(row.Color),
+		ParentID:       pgUUIDToUUID(row.ParentID),
+		ParentName:     pgTextToString(row.ParentName),
+		StartDate:      pgDateToString(row.StartDate),
+		EndDate:        pgDateToString(row.EndDate),
+		EstimatedHours: pgFloat8ToFloat64(row.EstimatedHours),
+		IsDefault:      pgBoolToBool(row.IsDefault),
+		ArchivedAt:     timeInLocation(pgTimestamptzToTimePtr(row.ArchivedAt), loc),
+		EntryCount:     int(row.EntryCount),
+		TotalMinutes:   minutes,
+		TotalHours:     minutesToHours(minutes),
+		LastActivityAt: timeInLocation(pgTimestamptzToTimePtr(row.LastActivityAt), loc),
+		CreatedAt:      pgTimestamptzToTime(row.CreatedAt).In(loc),
+	}
+}
+
+// tagUsageExportToModel converts an export row, with times in loc
+func tagUsageExportToModel(row store.ExportTagUsageRow, loc *time.Location) *models.TagUsageExport {
+	minutes := int(row.TotalMinutes)
+
+	return &models.TagUsageExport{
+		ID:           row.ID,
+		Name:         row.Name,
+		ParentID:     pgUUIDToUUID(row.ParentID),
+		ParentName:   pgTextToString(row.ParentName),
+		Color:        pgTextToStringRequired(row.Color),
+		Description:  pgTextToString(row.Description),
+		EntryCount:   int(row.EntryCount),
+		TotalMinutes: minutes,
+		TotalHours:   minutesToHours(minutes),
+		FirstUsedAt:  timeInLocation(pgTimestamptzToTimePtr(row.FirstUsedAt), loc),
+		LastUsedAt:   timeInLocation(pgTimestamptzToTimePtr(row.LastUsedAt), loc),
+		CreatedAt:    pgTimestamptzToTime(row.CreatedAt).In(loc),
+	}
+}
+
+// pgDateToString formats a date as YYYY-MM-DD, or returns nil when it is not set
+func pgDateToString(date pgtype.Date) *string {
+	if !date.Valid {
+		return nil
+	}
+	formatted := date.Time.Format(time.DateOnly)
+	return &formatted
+}
+
+// timeInLocation converts t to loc, keeping nil
+func timeInLocation(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.In(loc)
+	return &converted
+}
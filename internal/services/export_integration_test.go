@@ -0,0 +1,193 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportService_Exports tests the log entry, project and tag usage exports
+func TestExportService_Exports(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	exportService := services.NewExportService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "exports@example.com",
+		Password:  "password123",
+		FirstName: "Export",
+		LastName:  "User",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	otherUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "exports-other@example.com",
+		Password:  "password123",
+		FirstName: "Other",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Platform",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	foreignProject, err := projectService.CreateProject(ctx, otherUser.ID.String(), &models.ProjectRequest{
+		Name:   "Secret",
+		Color:  "#000000",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	// 2025-03-10 22:00 in São Paulo is already 2025-03-11 in UTC
+	entries := []struct {
+		title   string
+		start   time.Time
+		minutes int
+		project bool
+		tags    []string
+	}{
+		{"Design review", time.Date(2025, 3, 10, 9, 0, 0, 0, saoPaulo), 60, true, []string{"review", "design"}},
+		{"Late deploy", time.Date(2025, 3, 10, 22, 0, 0, 0, saoPaulo), 30, true, []string{"deploy"}},
+		{"Reading", time.Date(2025, 3, 11, 8, 0, 0, 0, saoPaulo), 45, false, nil},
+	}
+	for _, e := range entries {
+		req := &models.LogEntryRequest{
+			Title:       e.title,
+			Type:        models.ActivityDevelopment,
+			StartTime:   e.start,
+			EndTime:     e.start.Add(time.Duration(e.minutes) * time.Minute),
+			ValueRating: models.ValueHigh,
+			ImpactLevel: models.ImpactTeam,
+			Tags:        e.tags,
+		}
+		if e.project {
+			req.ProjectID = &project.ID
+		}
+		_, err := logEntryService.CreateLogEntry(ctx, userID, req)
+		require.NoError(t, err)
+	}
+
+	t.Run("LogEntriesCSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportLogEntries(ctx, userID, models.ExportCSV, nil, &buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, models.LogEntryExportColumns, records[0])
+
+		row := make(map[string]string)
+		for i, column := range records[0] {
+			row[column] = records[1][i]
+		}
+		assert.Equal(t, "Design review", row["title"], "oldest first")
+		assert.Equal(t, "Platform", row["project_name"])
+		assert.Equal(t, "2025-03-10T09:00:00-03:00", row["start_time"])
+		assert.Equal(t, "60", row["duration_minutes"])
+		assert.Equal(t, "1", row["duration_hours"])
+		assert.Equal(t, "design;review", row["tags"])
+		assert.Equal(t, "Reading", records[3][1])
+	})
+
+	t.Run("LogEntriesJSONLWithFilters", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportLogEntries(ctx, userID, models.ExportJSONL, &models.LogEntryExportFilter{
+			StartDate: "2025-03-10",
+			EndDate:   "2025-03-10",
+			ProjectID: project.ID.String(),
+		}, &buf))
+
+		var exported []models.LogEntryExport
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var entry models.LogEntryExport
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			exported = append(exported, entry)
+		}
+		require.Len(t, exported, 2, "days are in the user's timezone")
+		assert.Equal(t, "Late deploy", exported[1].Title)
+		assert.Equal(t, []string{"deploy"}, exported[1].Tags)
+	})
+
+	t.Run("LogEntriesUnknownProject", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := exportService.ExportLogEntries(ctx, userID, models.ExportCSV, &models.LogEntryExportFilter{
+			ProjectID: foreignProject.ID.String(),
+		}, &buf)
+		assert.ErrorContains(t, err, "project not found")
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("Projects", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportProjects(ctx, userID, models.ExportJSONL, &buf))
+
+		var exported []models.ProjectExport
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var p models.ProjectExport
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &p))
+			exported = append(exported, p)
+		}
+
+		var platform *models.ProjectExport
+		for i := range exported {
+			assert.NotEqual(t, foreignProject.ID, exported[i].ID, "only the user's projects")
+			if exported[i].ID == project.ID {
+				platform = &exported[i]
+			}
+		}
+		require.NotNil(t, platform)
+		assert.Equal(t, 2, platform.EntryCount)
+		assert.Equal(t, 90, platform.TotalMinutes)
+		assert.Equal(t, 1.5, platform.TotalHours)
+		require.NotNil(t, platform.LastActivityAt)
+		assert.True(t, platform.LastActivityAt.Equal(entries[1].start))
+	})
+
+	t.Run("TagUsage", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportTagUsage(ctx, userID, models.ExportCSV, &buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, models.TagUsageExportColumns, records[0])
+
+		minutes := make(map[string]string)
+		for _, record := range records[1:] {
+			minutes[record[1]] = record[7]
+		}
+		assert.Equal(t, map[string]string{"deploy": "30", "design": "60", "review": "60"}, minutes)
+	})
+}
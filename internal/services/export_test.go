@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExportDates(t *testing.T) {
+	start, end, err := parseExportDates("", "")
+	require.NoError(t, err)
+	assert.Nil(t, start)
+	assert.Nil(t, end)
+
+	start, end, err = parseExportDates("2025-03-01", "2025-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01", start.Format(time.DateOnly))
+	assert.Equal(t, "2025-03-01", end.Format(time.DateOnly), "a single day is a valid range")
+
+	start, end, err = parseExportDates("", "2025-03-31")
+	require.NoError(t, err)
+	assert.Nil(t, start, "either bound may be open")
+	assert.NotNil(t, end)
+
+	_, _, err = parseExportDates("03/01/2025", "")
+	assert.ErrorContains(t, err, "invalid start date")
+
+	_, _, err = parseExportDates("", "2025-02-30")
+	assert.ErrorContains(t, err, "invalid end date")
+
+	_, _, err = parseExportDates("2025-03-02", "2025-03-01")
+	assert.ErrorContains(t, err, "on or after")
+}
+
+func TestExportWriter(t *testing.T) {
+	tag := &models.TagUsageExport{
+		ID:        uuid.MustParse("9a4e3b1c-5d6f-4a7b-8c9d-0e1f2a3b4c5d"),
+		Name:      `"quoted", <b>`,
+		Color:     "#6b7280",
+		CreatedAt: time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		export, err := newExportWriter(&buf, models.ExportCSV, models.TagUsageExportColumns)
+		require.NoError(t, err)
+		require.NoError(t, export.write(tag))
+		assert.Zero(t, buf.Len(), "rows are buffered until flushed")
+
+		require.NoError(t, export.flush())
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, strings.Join(models.TagUsageExportColumns, ","), lines[0])
+		assert.Equal(t, `9a4e3b1c-5d6f-4a7b-8c9d-0e1f2a3b4c5d,"""quoted"", <b>",,,#6b7280,,0,0,0,,,2025-03-10T08:00:00Z`, lines[1])
+	})
+
+	t.Run("CSVHeaderOnly", func(t *testing.T) {
+		var buf bytes.Buffer
+		export, err := newExportWriter(&buf, models.ExportCSV, models.TagUsageExportColumns)
+		require.NoError(t, err)
+		require.NoError(t, export.flush())
+		assert.Equal(t, strings.Join(models.TagUsageExportColumns, ",")+"\n", buf.String())
+	})
+
+	t.Run("JSONL", func(t *testing.T) {
+		var buf bytes.Buffer
+		export, err := newExportWriter(&buf, models.ExportJSONL, models.TagUsageExportColumns)
+		require.NoError(t, err)
+		require.NoError(t, export.write(tag))
+		require.NoError(t, export.write(tag))
+		require.NoError(t, export.flush())
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 2, "one object per line and no header")
+		assert.Equal(t, `{"id":"9a4e3b1c-5d6f-4a7b-8c9d-0e1f2a3b4c5d","name":"\"quoted\", <b>","color":"#6b7280","entry_count":0,"total_minutes":0,"total_hours":0,"created_at":"2025-03-10T08:00:00Z"}`, lines[0])
+	})
+}
+
+func TestLogEntryExportToModel(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+
+	entry := logEntryExportToModel(store.ExportLogEntriesRow{
+		ID:              uuid.New(),
+		Title:           "Incident review",
+		Type:            string(models.ActivityMeeting),
+		ProjectID:       pgtype.UUID{Bytes: projectID, Valid: true},
+		ProjectName:     pgtype.Text{String: "Platform", Valid: true},
+		StartTime:       timeToPgTimestamptz(start),
+		EndTime:         timeToPgTimestamptz(start.Add(50 * time.Minute)),
+		DurationMinutes: pgtype.Int4{Int32: 50, Valid: true},
+		ValueRating:     string(models.ValueHigh),
+		ImpactLevel:     string(models.ImpactTeam),
+		Tags:            []string{"incident"},
+		CreatedAt:       timeToPgTimestamptz(start),
+		UpdatedAt:       timeToPgTimestamptz(start),
+	}, saoPaulo)
+
+	assert.Equal(t, "2025-03-10T09:00:00-03:00", entry.StartTime.Format(time.RFC3339), "times are in the user's timezone")
+	assert.Equal(t, 50, entry.DurationMinutes)
+	assert.Equal(t, 0.83, entry.DurationHours)
+	assert.Equal(t, &projectID, entry.ProjectID)
+	assert.Equal(t, "Platform", *entry.ProjectName)
+	assert.Nil(t, entry.Description)
+	assert.Equal(t, []string{"incident"}, entry.Tags)
+}
+
+func TestExportService_RejectsInvalidInput(t *testing.T) {
+	service := NewExportService(nil, logging.NewTestLogger())
+	ctx := context.Background()
+	userID := uuid.NewString()
+	var buf bytes.Buffer
+
+	err := service.ExportLogEntries(ctx, "not-a-uuid", models.ExportCSV, nil, &buf)
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	err = service.ExportLogEntries(ctx, userID, models.ExportFormat("xlsx"), nil, &buf)
+	assert.ErrorContains(t, err, "invalid export format")
+
+	err = service.ExportLogEntries(ctx, userID, models.ExportCSV, &models.LogEntryExportFilter{ProjectID: "nope"}, &buf)
+	assert.ErrorContains(t, err, "invalid project ID")
+
+	err = service.ExportLogEntries(ctx, userID, models.ExportCSV, &models.LogEntryExportFilter{StartDate: "2025-03-02", EndDate: "2025-03-01"}, &buf)
+	assert.ErrorContains(t, err, "end date must be on or after start date")
+
+	err = service.ExportProjects(ctx, userID, models.ExportFormat("xml"), &buf)
+	assert.ErrorContains(t, err, "invalid export format")
+
+	err = service.ExportTagUsage(ctx, "", models.ExportJSONL, &buf)
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	assert.Zero(t, buf.Len(), "nothing is written when the input is rejected")
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/gitlog"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultGitSessionGapMinutes is the longest pause between commits of one session
+	defaultGitSessionGapMinutes = 120
+	// minGitSessionGapMinutes and maxGitSessionGapMinutes bound a requested session gap
+	minGitSessionGapMinutes = 15
+	maxGitSessionGapMinutes = 12 * 60
+	// gitSessionLead is the work assumed before the first commit of a session
+	gitSessionLead = 30 * time.Minute
+	// defaultMaxGitLogBytes is the largest git log accepted for an import
+	defaultMaxGitLogBytes = 20 * 1024 * 1024
+	// maxProposedTitleBytes and maxProposedDescriptionBytes are the log entry limits
+	maxProposedTitleBytes       = 200
+	maxProposedDescriptionBytes = 1000
+)
+
+// GitImportService proposes log entries for the commits of a git repository.
+// Commits are grouped into work sessions by the pauses between them, and each
+// session becomes one development entry on the project named after the repository.
+// Proposals are not saved; the user reviews them and creates the entries in bulk.
+type GitImportService struct {
+	db          *database.DB
+	logger      *logging.Logger
+	maxLogBytes int64
+}
+
+// NewGitImportService creates a new GitImportService instance
+func NewGitImportService(db *database.DB, logger *logging.Logger) *GitImportService {
+	return &GitImportService{
+		db:          db,
+		logger:      logger.WithComponent("git_import_service"),
+		maxLogBytes: defaultMaxGitLogBytes,
+	}
+}
+
+// WithMaxLogBytes sets the largest git log accepted for an import; non-positive
+// values keep the default
+func (s *GitImportService) WithMaxLogBytes(maxBytes int64) *GitImportService {
+	if maxBytes > 0 {
+		s.maxLogBytes = maxBytes
+	}
+	return s
+}
+
+// MaxLogBytes returns the largest git log accepted by ProposeLogEntries
+func (s *GitImportService) MaxLogBytes() int64 {
+	return s.maxLogBytes
+}
+
+// ProposeLogEntries reads the output of git log --numstat and proposes one log
+// entry per work session of the author in the period. Sessions that were already
+// logged, with the same title and time span, are flagged as duplicates.
+func (s *GitImportService) ProposeLogEntries(ctx context.Context, userID string, log io.Reader, req *models.GitImportRequest) (*models.GitImportProposal, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ProposeLogEntries", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	repository := strings.TrimSpace(req.Repository)
+	if repository == "" {
+		return nil, fmt.Errorf("repository name is required")
+	}
+	if len(repository) > 100 {
+		return nil, fmt.Errorf("repository name must be at most 100 characters")
+	}
+	authorEmail := strings.TrimSpace(req.AuthorEmail)
+	if !strings.Contains(authorEmail, "@") {
+		return nil, fmt.Errorf("a valid author email is required")
+	}
+	gapMinutes := req.GapMinutes
+	if gapMinutes == 0 {
+		gapMinutes = defaultGitSessionGapMinutes
+	}
+	if gapMinutes < minGitSessionGapMinutes || gapMinutes > maxGitSessionGapMinutes {
+		return nil, fmt.Errorf("gap must be between %d and %d minutes", minGitSessionGapMinutes, maxGitSessionGapMinutes)
+	}
+	startDay, endDay, err := parseExportDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := &io.LimitedReader{R: log, N: s.maxLogBytes + 1}
+	commits, err := gitlog.Parse(limited)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git log: %w", err)
+	}
+	if limited.N <= 0 {
+		return nil, fmt.Errorf("git log is larger than the %d byte limit", s.maxLogBytes)
+	}
+
+	s.logger.Info("Proposing log entries from git log", "user_id", userID, "repository", repository,
+		"commits", len(commits), "start_date", req.StartDate, "end_date", req.EndDate, "gap_minutes", gapMinutes)
+
+	proposal := &models.GitImportProposal{
+		Repository: repository,
+		Sessions:   []models.GitImportSession{},
+	}
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		location, err := s.userLocation(ctx, qtx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		project, err := s.resolveProject(ctx, qtx, userUUID, repository, req.ProjectID)
+		if err != nil {
+			return err
+		}
+		if project != nil {
+			proposal.ProjectID = &project.ID
+			proposal.ProjectName = &project.Name
+		}
+
+		var from, until time.Time
+		if startDay != nil {
+			from = inLocation(*startDay, location)
+		}
+		if endDay != nil {
+			until = inLocation(*endDay, location).AddDate(0, 0, 1)
+		}
+
+		selected := make([]gitlog.Commit, 0, len(commits))
+		seen := make(map[string]bool, len(commits))
+		for _, commit := range commits {
+			inPeriod := (from.IsZero() || !commit.Time.Before(from)) && (until.IsZero() || commit.Time.Before(until))
+			if commit.Merge || seen[commit.Hash] || !inPeriod || !strings.EqualFold(commit.AuthorEmail, authorEmail) {
+				proposal.SkippedCommits++
+				continue
+			}
+			seen[commit.Hash] = true
+			selected = append(selected, commit)
+		}
+		proposal.Commits = len(selected)
+
+		sessions := gitlog.Sessions(selected, gitlog.SessionOptions{
+			Gap:       time.Duration(gapMinutes) * time.Minute,
+			Lead:      gitSessionLead,
+			MaxLength: maxLogEntryDuration,
+		})
+		for _, session := range sessions {
+			proposed := gitSessionProposal(repository, session, project)
+
+			duplicate, err := qtx.LogEntryExists(ctx, store.LogEntryExistsParams{
+				UserID:    userUUID,
+				Title:     proposed.Entry.Title,
+				StartTime: timeToPgTimestamptz(proposed.Entry.StartTime),
+				EndTime:   timeToPgTimestamptz(proposed.Entry.EndTime),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to check for existing log entries: %w", err)
+			}
+			proposed.Duplicate = duplicate
+			proposal.Sessions = append(proposal.Sessions, proposed)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to propose log entries from git log", "user_id", userID, "repository", repository)
+		return nil, err
+	}
+
+	s.logger.Info("Successfully proposed log entries from git log", "user_id", userID, "repository", repository,
+		"commits", proposal.Commits, "skipped_commits", proposal.SkippedCommits, "sessions", len(proposal.Sessions))
+	return proposal, nil
+}
+
+// userLocation returns the user's profile timezone, or UTC when the stored one is invalid
+func (s *GitImportService) userLocation(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID) (*time.Location, error) {
+	user, err := qtx.GetUserByID(ctx, userUUID)
+	if err != nil {
+		if database.NoRows(err) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	location, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+	if err != nil {
+		s.logger.Warn("Stored timezone is invalid, using UTC for the import period", "user_id", userUUID, "timezone", user.Timezone.String)
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+// resolveProject returns the requested project, which the user must be able to log
+// time on, or else the user's active project named like the repository, if any
+func (s *GitImportService) resolveProject(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, repository string, projectID *uuid.UUID) (*store.Project, error) {
+	if projectID != nil {
+		project, err := authorizeProject(ctx, qtx, *projectID, userUUID, models.TeamPermissionLogTime)
+		if err != nil {
+			return nil, err
+		}
+		if project.ArchivedAt.Valid {
+			return nil, fmt.Errorf("project is archived")
+		}
+		return &project, nil
+	}
+
+	projects, err := qtx.GetProjectsByUser(ctx, store.GetProjectsByUserParams{CreatedBy: userUUID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	for _, project := range projects {
+		if strings.EqualFold(strings.TrimSpace(project.Name), repository) {
+			return &project, nil
+		}
+	}
+	return nil, nil
+}
+
+// gitSessionProposal builds the development entry proposed for a session: the first
+// commit subject as the title and every commit subject in the description. The
+// project's defaults fill the value rating, impact level and tags.
+func gitSessionProposal(repository string, session gitlog.Session, project *store.Project) models.GitImportSession {
+	files, insertions, deletions := session.Stats()
+
+	title := repository + ": " + session.Commits[0].Subject
+	if more := len(session.Commits) - 1; more > 0 {
+		suffix := fmt.Sprintf(" (+%d more)", more)
+		title = shortenText(title, maxProposedTitleBytes-len(suffix)) + suffix
+	}
+	title = shortenText(title, maxProposedTitleBytes)
+
+	var description strings.Builder
+	noun := "commits"
+	if len(session.Commits) == 1 {
+		noun = "commit"
+	}
+	fmt.Fprintf(&description, "%d %s, %d files changed, +%d -%d\n", len(session.Commits), noun, files, insertions, deletions)
+	hashes := make([]string, len(session.Commits))
+	for i, commit := range session.Commits {
+		hashes[i] = commit.ShortHash()
+		line := fmt.Sprintf("\n- %s %s", commit.ShortHash(), commit.Subject)
+		omitted := fmt.Sprintf("\n- and %d more", len(session.Commits)-i)
+		if description.Len()+len(line)+len(omitted) > maxProposedDescriptionBytes && i < len(session.Commits)-1 {
+			description.WriteString(omitted)
+			break
+		}
+		description.WriteString(shortenText(line, maxProposedDescriptionBytes-description.Len()))
+	}
+	text := description.String()
+
+	entry := models.LogEntryRequest{
+		Title:       title,
+		Description: &text,
+		Type:        models.ActivityDevelopment,
+		StartTime:   session.Start,
+		EndTime:     session.End,
+	}
+	if project != nil {
+		entry.ProjectID = &project.ID
+		projectDefaultsToModel(*project).Apply(&entry)
+		entry.Type = models.ActivityDevelopment
+	}
+	if entry.ValueRating == "" {
+		entry.ValueRating = models.ValueMedium
+	}
+	if entry.ImpactLevel == "" {
+		entry.ImpactLevel = models.ImpactTeam
+	}
+
+	return models.GitImportSession{
+		Entry:         entry,
+		Commits:       hashes,
+		FirstCommitAt: session.Commits[0].Time,
+		FilesChanged:  files,
+		Insertions:    insertions,
+		Deletions:     deletions,
+	}
+}
+
+// shortenText cuts s to at most maxBytes bytes without splitting a character,
+// ending it with "…" when cut
+func shortenText(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	const ellipsis = "…"
+	cut := maxBytes - len(ellipsis)
+	if cut <= 0 {
+		return ""
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
@@ -0,0 +1,136 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitImportLog = `commit 3333333333333333333333333333333333333333
+Author: Jane Doe <jane@example.com>
+Date:   2025-03-10T15:00:00-03:00
+
+    Release invoices
+
+2	0	CHANGELOG.md
+
+commit 2222222222222222222222222222222222222222
+Author: John Roe <john@example.com>
+Date:   2025-03-10T10:30:00-03:00
+
+    Someone else's work
+
+commit 1111111111111111111111111111111111111111
+Author: Jane Doe <JANE@example.com>
+Date:   2025-03-10T10:00:00-03:00
+
+    Add invoice endpoint
+
+40	2	invoices.go
+
+commit 0000000000000000000000000000000000000000
+Author: Jane Doe <jane@example.com>
+Date:   2025-03-10T09:00:00-03:00
+
+    Start invoices
+
+10	0	invoices.go
+`
+
+// TestGitImportService_ProposeLogEntries tests session grouping, project matching and duplicate detection
+func TestGitImportService_ProposeLogEntries(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	gitImportService := services.NewGitImportService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "git-import@example.com",
+		Password:  "password123",
+		FirstName: "Git",
+		LastName:  "Import",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Billing",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	req := &models.GitImportRequest{
+		Repository:  "billing",
+		AuthorEmail: "jane@example.com",
+		StartDate:   "2025-03-10",
+		EndDate:     "2025-03-10",
+	}
+	proposal, err := gitImportService.ProposeLogEntries(ctx, userID, strings.NewReader(gitImportLog), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, proposal.Commits, "author emails match regardless of case")
+	assert.Equal(t, 1, proposal.SkippedCommits)
+	require.NotNil(t, proposal.ProjectID, "the repository matches the project name")
+	assert.Equal(t, project.ID, *proposal.ProjectID)
+	require.Len(t, proposal.Sessions, 2, "the afternoon commit is more than two hours after the morning ones")
+
+	morning := proposal.Sessions[0]
+	assert.Equal(t, "billing: Start invoices (+1 more)", morning.Entry.Title)
+	assert.True(t, morning.Entry.StartTime.Equal(time.Date(2025, 3, 10, 11, 30, 0, 0, time.UTC)))
+	assert.True(t, morning.Entry.EndTime.Equal(time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"0000000", "1111111"}, morning.Commits)
+	assert.Equal(t, &project.ID, morning.Entry.ProjectID)
+	assert.False(t, morning.Duplicate)
+
+	// Accept the morning session, then propose again
+	_, err = logEntryService.CreateLogEntry(ctx, userID, &morning.Entry)
+	require.NoError(t, err)
+
+	proposal, err = gitImportService.ProposeLogEntries(ctx, userID, strings.NewReader(gitImportLog), req)
+	require.NoError(t, err)
+	require.Len(t, proposal.Sessions, 2)
+	assert.True(t, proposal.Sessions[0].Duplicate, "logged sessions are flagged")
+	assert.False(t, proposal.Sessions[1].Duplicate)
+
+	t.Run("PeriodInProfileTimezone", func(t *testing.T) {
+		proposal, err := gitImportService.ProposeLogEntries(ctx, userID, strings.NewReader(gitImportLog), &models.GitImportRequest{
+			Repository:  "billing",
+			AuthorEmail: "jane@example.com",
+			StartDate:   "2025-03-11",
+		})
+		require.NoError(t, err)
+		assert.Zero(t, proposal.Commits)
+		assert.Equal(t, 4, proposal.SkippedCommits)
+		assert.Empty(t, proposal.Sessions)
+	})
+
+	t.Run("UnknownRepository", func(t *testing.T) {
+		proposal, err := gitImportService.ProposeLogEntries(ctx, userID, strings.NewReader(gitImportLog), &models.GitImportRequest{
+			Repository:  "website",
+			AuthorEmail: "jane@example.com",
+			GapMinutes:  360,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, proposal.ProjectID)
+		require.Len(t, proposal.Sessions, 1, "a longer gap joins the sessions")
+		assert.Nil(t, proposal.Sessions[0].Entry.ProjectID)
+	})
+}
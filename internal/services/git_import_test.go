@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garnizeh/englog/internal/gitlog"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortenText(t *testing.T) {
+	assert.Equal(t, "short", shortenText("short", 10))
+	assert.Equal(t, "abcdefg…", shortenText("abcdefghijklmnop", 10))
+	assert.Equal(t, "", shortenText("abcdef", 2))
+
+	cut := shortenText("ação ação ação", 9)
+	assert.True(t, utf8.ValidString(cut), "characters are never split")
+	assert.LessOrEqual(t, len(cut), 9)
+}
+
+func TestGitSessionProposal(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	session := gitlog.Session{
+		Start: start.Add(-30 * time.Minute),
+		End:   start.Add(time.Hour),
+		Commits: []gitlog.Commit{
+			{Hash: "5f1c2a9d8e7b", Time: start, Subject: "Add invoice endpoint", Files: 2, Insertions: 50, Deletions: 3},
+			{Hash: "9e8d7c6b5a4f", Time: start.Add(time.Hour), Subject: "Fix rounding", Files: 1, Insertions: 1, Deletions: 1},
+		},
+	}
+
+	proposed := gitSessionProposal("billing", session, nil)
+	entry := proposed.Entry
+	assert.Equal(t, "billing: Add invoice endpoint (+1 more)", entry.Title)
+	require.NotNil(t, entry.Description)
+	assert.Equal(t, "2 commits, 3 files changed, +51 -4\n\n- 5f1c2a9 Add invoice endpoint\n- 9e8d7c6 Fix rounding", *entry.Description)
+	assert.Equal(t, models.ActivityDevelopment, entry.Type)
+	assert.Equal(t, models.ValueMedium, entry.ValueRating)
+	assert.Equal(t, models.ImpactTeam, entry.ImpactLevel)
+	assert.Equal(t, session.Start, entry.StartTime)
+	assert.Equal(t, session.End, entry.EndTime)
+	assert.Nil(t, entry.ProjectID)
+	assert.Equal(t, []string{"5f1c2a9", "9e8d7c6"}, proposed.Commits)
+	assert.Equal(t, start, proposed.FirstCommitAt)
+	assert.Equal(t, []int{3, 51, 4}, []int{proposed.FilesChanged, proposed.Insertions, proposed.Deletions})
+
+	project := &store.Project{
+		ID:                 uuid.New(),
+		Name:               "billing",
+		DefaultType:        pgtype.Text{String: string(models.ActivityMeeting), Valid: true},
+		DefaultImpactLevel: pgtype.Text{String: string(models.ImpactCompany), Valid: true},
+		DefaultTags:        []string{"payments"},
+	}
+	entry = gitSessionProposal("billing", session, project).Entry
+	assert.Equal(t, &project.ID, entry.ProjectID)
+	assert.Equal(t, models.ActivityDevelopment, entry.Type, "commits are always development work")
+	assert.Equal(t, models.ImpactCompany, entry.ImpactLevel, "project defaults fill the rest")
+	assert.Equal(t, models.ValueMedium, entry.ValueRating)
+	assert.Equal(t, []string{"payments"}, entry.Tags)
+}
+
+func TestGitSessionProposal_Limits(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	session := gitlog.Session{Start: start, End: start.Add(time.Hour)}
+	for i := 0; i < 80; i++ {
+		session.Commits = append(session.Commits, gitlog.Commit{
+			Hash:    uuid.NewString(),
+			Time:    start,
+			Subject: strings.Repeat("long subject ", 20),
+		})
+	}
+
+	entry := gitSessionProposal(strings.Repeat("repo", 25), session, nil).Entry
+	assert.LessOrEqual(t, len(entry.Title), maxProposedTitleBytes)
+	assert.True(t, strings.HasSuffix(entry.Title, " (+79 more)"))
+	assert.LessOrEqual(t, len(*entry.Description), maxProposedDescriptionBytes)
+	assert.Regexp(t, `- and \d+ more$`, *entry.Description)
+}
+
+func TestGitImportService_RejectsInvalidInput(t *testing.T) {
+	service := NewGitImportService(nil, logging.NewTestLogger()).WithMaxLogBytes(64)
+	ctx := context.Background()
+	valid := func() *models.GitImportRequest {
+		return &models.GitImportRequest{Repository: "billing", AuthorEmail: "jane@example.com"}
+	}
+
+	assert.Equal(t, int64(64), service.MaxLogBytes())
+
+	_, err := service.ProposeLogEntries(ctx, "not-a-uuid", strings.NewReader(""), valid())
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	userID := uuid.NewString()
+	for message, mutate := range map[string]func(*models.GitImportRequest){
+		"repository name is required":  func(r *models.GitImportRequest) { r.Repository = " " },
+		"at most 100 characters":       func(r *models.GitImportRequest) { r.Repository = strings.Repeat("r", 101) },
+		"a valid author email":         func(r *models.GitImportRequest) { r.AuthorEmail = "jane" },
+		"gap must be between":          func(r *models.GitImportRequest) { r.GapMinutes = 5 },
+		"invalid start date":           func(r *models.GitImportRequest) { r.StartDate = "10/03/2025" },
+		"end date must be on or after": func(r *models.GitImportRequest) { r.StartDate, r.EndDate = "2025-03-10", "2025-03-01" },
+	} {
+		req := valid()
+		mutate(req)
+		_, err := service.ProposeLogEntries(ctx, userID, strings.NewReader(""), req)
+		assert.ErrorContains(t, err, message)
+	}
+
+	_, err = service.ProposeLogEntries(ctx, userID, strings.NewReader("not a git log"), valid())
+	assert.ErrorContains(t, err, "invalid git log")
+
+	_, err = service.ProposeLogEntries(ctx, userID, strings.NewReader("\n"+strings.Repeat(" ", 100)), valid())
+	assert.ErrorContains(t, err, "byte limit")
+}
@@ -0,0 +1,756 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GoalService handles personal goals, OKR key results and their progress
+type GoalService struct {
+	db        *database.DB
+	logger    *logging.Logger
+	analytics *AnalyticsService
+}
+
+// NewGoalService creates a new GoalService instance
+func NewGoalService(db *database.DB, logger *logging.Logger, analyticsService *AnalyticsService) *GoalService {
+	return &GoalService{
+		db:        db,
+		logger:    logger.WithComponent("goal_service"),
+		analytics: analyticsService,
+	}
+}
+
+// CreateGoal creates a new goal or key result
+func (s *GoalService) CreateGoal(ctx context.Context, userID string, req *models.GoalRequest) (*models.Goal, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateGoal", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Creating goal", "user_id", userID, "title", req.Title, "metric", req.Metric, "period", req.Period)
+
+	var goal *models.Goal
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		params, err := s.goalParams(ctx, qtx, userUUID, uuid.Nil, req)
+		if err != nil {
+			return err
+		}
+
+		sqlcGoal, err := qtx.CreateGoal(ctx, store.CreateGoalParams{
+			UserID:        userUUID,
+			ParentID:      params.ParentID,
+			Title:         params.Title,
+			Description:   params.Description,
+			Metric:        params.Metric,
+			Comparison:    params.Comparison,
+			TargetValue:   params.TargetValue,
+			Period:        params.Period,
+			StartDate:     params.StartDate,
+			EndDate:       params.EndDate,
+			ActivityTypes: params.ActivityTypes,
+			Tags:          params.Tags,
+			ProjectID:     params.ProjectID,
+			ValueRatings:  params.ValueRatings,
+			Status:        params.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create goal: %w", err)
+		}
+
+		goal = goalToModel(sqlcGoal)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create goal", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Goal created successfully", "user_id", userID, "goal_id", goal.ID)
+	return goal, nil
+}
+
+// GetGoal retrieves a single goal owned by the user
+func (s *GoalService) GetGoal(ctx context.Context, userID, goalID string) (*models.Goal, error) {
+	userUUID, goalUUID, err := s.parseIDs(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var goal *models.Goal
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcGoal, err := s.getOwnedGoal(ctx, qtx, userUUID, goalUUID)
+		if err != nil {
+			return err
+		}
+		goal = goalToModel(sqlcGoal)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get goal", "user_id", userID, "goal_id", goalID)
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+// GetGoals retrieves the user's goals, optionally only those with the given status.
+// Objectives are listed before their key results.
+func (s *GoalService) GetGoals(ctx context.Context, userID string, status models.GoalStatus) ([]*models.Goal, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetGoals", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("invalid goal status: %s", status)
+	}
+
+	var goals []*models.Goal
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcGoals, err := qtx.GetGoalsByUser(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		goals = []*models.Goal{}
+		for _, sqlcGoal := range sqlcGoals {
+			if status != "" && models.GoalStatus(sqlcGoal.Status) != status {
+				continue
+			}
+			goals = append(goals, goalToModel(sqlcGoal))
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get goals", "user_id", userID)
+		return nil, fmt.Errorf("failed to get goals: %w", err)
+	}
+
+	return goals, nil
+}
+
+// UpdateGoal replaces the settings of an existing goal
+func (s *GoalService) UpdateGoal(ctx context.Context, userID, goalID string, req *models.GoalRequest) (*models.Goal, error) {
+	userUUID, goalUUID, err := s.parseIDs(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Updating goal", "user_id", userID, "goal_id", goalID)
+
+	var goal *models.Goal
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := s.getOwnedGoal(ctx, qtx, userUUID, goalUUID); err != nil {
+			return err
+		}
+
+		params, err := s.goalParams(ctx, qtx, userUUID, goalUUID, req)
+		if err != nil {
+			return err
+		}
+
+		if params.Metric != string(models.GoalMetricKeyResults) {
+			children, err := qtx.GetGoalChildren(ctx, store.GetGoalChildrenParams{
+				ParentID: uuidToPgUUID(&goalUUID),
+				UserID:   userUUID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get key results: %w", err)
+			}
+			if len(children) > 0 {
+				return fmt.Errorf("goal has key results, its metric must stay key_results")
+			}
+		}
+
+		params.ID = goalUUID
+		params.UserID = userUUID
+		sqlcGoal, err := qtx.UpdateGoal(ctx, params)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("goal not found")
+			}
+			return fmt.Errorf("failed to update goal: %w", err)
+		}
+
+		goal = goalToModel(sqlcGoal)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update goal", "user_id", userID, "goal_id", goalID)
+		return nil, err
+	}
+
+	s.logger.Info("Goal updated successfully", "user_id", userID, "goal_id", goalID)
+	return goal, nil
+}
+
+// DeleteGoal deletes a goal together with its key results
+func (s *GoalService) DeleteGoal(ctx context.Context, userID, goalID string) error {
+	userUUID, goalUUID, err := s.parseIDs(ctx, userID, goalID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteGoal(ctx, store.DeleteGoalParams{
+			ID:     goalUUID,
+			UserID: userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("goal not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete goal", "user_id", userID, "goal_id", goalID)
+		return err
+	}
+
+	s.logger.Info("Goal deleted successfully", "user_id", userID, "goal_id", goalID)
+	return nil
+}
+
+// GetGoalProgress evaluates a goal over the period containing date (YYYY-MM-DD,
+// empty for today). Periods are computed in the user's timezone unless location
+// overrides it.
+func (s *GoalService) GetGoalProgress(ctx context.Context, userID, goalID, date string, location *time.Location) (*models.GoalProgress, error) {
+	userUUID, goalUUID, err := s.parseIDs(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.analytics.ResolveSettings(ctx, userID, location)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := referenceTime(date, settings.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress *models.GoalProgress
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcGoal, err := s.getOwnedGoal(ctx, qtx, userUUID, goalUUID)
+		if err != nil {
+			return err
+		}
+
+		progress, err = s.evaluate(ctx, qtx, userUUID, goalToModel(sqlcGoal), at, settings)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get goal progress", "user_id", userID, "goal_id", goalID)
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// GetActiveGoalsProgress evaluates every active top-level goal over the period
+// containing date (YYYY-MM-DD, empty for today). Key results are nested under
+// their objectives.
+func (s *GoalService) GetActiveGoalsProgress(ctx context.Context, userID, date string, location *time.Location) ([]*models.GoalProgress, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetActiveGoalsProgress", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	settings, err := s.analytics.ResolveSettings(ctx, userID, location)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := referenceTime(date, settings.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress []*models.GoalProgress
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcGoals, err := qtx.GetGoalsByUser(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get goals: %w", err)
+		}
+
+		progress = []*models.GoalProgress{}
+		for _, sqlcGoal := range sqlcGoals {
+			if sqlcGoal.ParentID.Valid || models.GoalStatus(sqlcGoal.Status) != models.GoalActive {
+				continue
+			}
+
+			goalProgress, err := s.evaluate(ctx, qtx, userUUID, goalToModel(sqlcGoal), at, settings)
+			if err != nil {
+				return err
+			}
+			progress = append(progress, goalProgress)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get goals progress", "user_id", userID)
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// evaluate computes the progress of a goal, recursing into key results
+func (s *GoalService) evaluate(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, goal *models.Goal, at time.Time, settings *models.AnalyticsSettings) (*models.GoalProgress, error) {
+	start, end, err := goalWindow(goal, at, settings.WeekStart.Weekday())
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := elapsedFraction(start, end, time.Now())
+	progress := &models.GoalProgress{
+		GoalID:         goal.ID,
+		Title:          goal.Title,
+		Metric:         goal.Metric,
+		Comparison:     goal.Comparison,
+		TargetValue:    goal.TargetValue,
+		PeriodStart:    start.Format(time.DateOnly),
+		PeriodEnd:      end.AddDate(0, 0, -1).Format(time.DateOnly),
+		ElapsedPercent: math.Round(elapsed*10000) / 100,
+	}
+
+	if goal.Metric == models.GoalMetricKeyResults {
+		children, err := qtx.GetGoalChildren(ctx, store.GetGoalChildrenParams{
+			ParentID: uuidToPgUUID(&goal.ID),
+			UserID:   userUUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get key results: %w", err)
+		}
+
+		progress.KeyResults = []*models.GoalProgress{}
+		for _, child := range children {
+			if models.GoalStatus(child.Status) == models.GoalArchived {
+				continue
+			}
+
+			childProgress, err := s.evaluate(ctx, qtx, userUUID, goalToModel(child), at, settings)
+			if err != nil {
+				return nil, err
+			}
+			progress.KeyResults = append(progress.KeyResults, childProgress)
+		}
+
+		progress.Percent, progress.State = combineKeyResults(progress.KeyResults)
+		progress.CurrentValue = progress.Percent
+		return progress, nil
+	}
+
+	tags := goal.Tags
+	if len(tags) > 0 {
+		tags, err = expandTagNames(ctx, qtx, userUUID, tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	activityTypes := make([]string, len(goal.ActivityTypes))
+	for i, activityType := range goal.ActivityTypes {
+		activityTypes[i] = string(activityType)
+	}
+	valueRatings := make([]string, len(goal.ValueRatings))
+	for i, valueRating := range goal.ValueRatings {
+		valueRatings[i] = string(valueRating)
+	}
+
+	metric, err := qtx.GetGoalMetric(ctx, store.GetGoalMetricParams{
+		ActivityTypes: activityTypes,
+		ProjectID:     uuidToPgUUID(goal.ProjectID),
+		ValueRatings:  valueRatings,
+		Tags:          tags,
+		UserID:        userUUID,
+		StartTime:     timeToPgTimestamptz(start),
+		EndTime:       timeToPgTimestamptz(end),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure goal: %w", err)
+	}
+
+	progress.CurrentValue = goalMetricValue(goal.Metric, metric)
+	progress.Percent, progress.State = measureProgress(goal.Comparison, progress.CurrentValue, goal.TargetValue, elapsed)
+	return progress, nil
+}
+
+// getOwnedGoal fetches a goal and hides goals of other users
+func (s *GoalService) getOwnedGoal(ctx context.Context, qtx *store.Queries, userUUID, goalUUID uuid.UUID) (store.Goal, error) {
+	sqlcGoal, err := qtx.GetGoalByID(ctx, store.GetGoalByIDParams{
+		ID:     goalUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return store.Goal{}, fmt.Errorf("goal not found")
+		}
+		return store.Goal{}, fmt.Errorf("failed to get goal: %w", err)
+	}
+	return sqlcGoal, nil
+}
+
+func (s *GoalService) parseIDs(ctx context.Context, userID, goalID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	goalUUID, err := uuid.Parse(goalID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid goal ID format", "user_id", userID, "goal_id", goalID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid goal ID: %w", err)
+	}
+
+	return userUUID, goalUUID, nil
+}
+
+// goalParams validates a request, checks its parent and converts it to column
+// values. goalUUID is uuid.Nil for new goals.
+func (s *GoalService) goalParams(ctx context.Context, qtx *store.Queries, userUUID, goalUUID uuid.UUID, req *models.GoalRequest) (store.UpdateGoalParams, error) {
+	if err := validateGoalRequest(req); err != nil {
+		return store.UpdateGoalParams{}, err
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == goalUUID {
+			return store.UpdateGoalParams{}, fmt.Errorf("goal cannot be its own parent")
+		}
+
+		parent, err := s.getOwnedGoal(ctx, qtx, userUUID, *req.ParentID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return store.UpdateGoalParams{}, fmt.Errorf("parent goal not found")
+			}
+			return store.UpdateGoalParams{}, err
+		}
+		if models.GoalMetric(parent.Metric) != models.GoalMetricKeyResults || parent.ParentID.Valid {
+			return store.UpdateGoalParams{}, fmt.Errorf("parent goal must be a top-level goal with metric key_results")
+		}
+	}
+
+	activityTypes := make([]string, len(req.ActivityTypes))
+	for i, activityType := range req.ActivityTypes {
+		activityTypes[i] = string(activityType)
+	}
+	valueRatings := make([]string, len(req.ValueRatings))
+	for i, valueRating := range req.ValueRatings {
+		valueRatings[i] = string(valueRating)
+	}
+
+	tags := []string{}
+	for _, tag := range req.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	var startDate, endDate pgtype.Date
+	if req.Period == models.GoalPeriodCustom {
+		start, end, _ := parseDateRange(*req.StartDate, *req.EndDate)
+		startDate = dateToPgDate(start)
+		endDate = dateToPgDate(end)
+	}
+
+	return store.UpdateGoalParams{
+		ParentID:      uuidToPgUUID(req.ParentID),
+		Title:         strings.TrimSpace(req.Title),
+		Description:   stringToPgText(req.Description),
+		Metric:        string(req.Metric),
+		Comparison:    string(req.Comparison),
+		TargetValue:   req.TargetValue,
+		Period:        string(req.Period),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		ActivityTypes: activityTypes,
+		Tags:          tags,
+		ProjectID:     uuidToPgUUID(req.ProjectID),
+		ValueRatings:  valueRatings,
+		Status:        string(req.Status),
+	}, nil
+}
+
+// validateGoalRequest validates a goal request and fills in defaults
+func validateGoalRequest(req *models.GoalRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(title) > 200 {
+		return fmt.Errorf("title must be at most 200 characters")
+	}
+
+	if !req.Metric.IsValid() {
+		return fmt.Errorf("invalid metric: %s", req.Metric)
+	}
+
+	if req.Comparison == "" {
+		req.Comparison = models.GoalAtLeast
+	}
+	if !req.Comparison.IsValid() {
+		return fmt.Errorf("invalid comparison: %s", req.Comparison)
+	}
+
+	if req.Status == "" {
+		req.Status = models.GoalActive
+	}
+	if !req.Status.IsValid() {
+		return fmt.Errorf("invalid goal status: %s", req.Status)
+	}
+
+	if !req.Period.IsValid() {
+		return fmt.Errorf("invalid period: %s", req.Period)
+	}
+
+	if req.Period == models.GoalPeriodCustom {
+		if req.StartDate == nil || req.EndDate == nil {
+			return fmt.Errorf("start_date and end_date are required for custom periods")
+		}
+		if _, _, err := parseDateRange(*req.StartDate, *req.EndDate); err != nil {
+			return err
+		}
+	} else if req.StartDate != nil || req.EndDate != nil {
+		return fmt.Errorf("start_date and end_date are only allowed for custom periods")
+	}
+
+	if req.Metric == models.GoalMetricKeyResults {
+		if req.ParentID != nil {
+			return fmt.Errorf("key_results goals cannot have a parent")
+		}
+		if len(req.ActivityTypes) > 0 || len(req.Tags) > 0 || req.ProjectID != nil || len(req.ValueRatings) > 0 {
+			return fmt.Errorf("key_results goals are measured by their key results and cannot have entry filters")
+		}
+		req.Comparison = models.GoalAtLeast
+		req.TargetValue = 100
+		return nil
+	}
+
+	if req.TargetValue < 0 || (req.Comparison == models.GoalAtLeast && req.TargetValue == 0) {
+		return fmt.Errorf("target_value must be greater than 0")
+	}
+	if req.Metric == models.GoalMetricShare && req.TargetValue > 100 {
+		return fmt.Errorf("target_value of a share goal must be at most 100")
+	}
+
+	for _, activityType := range req.ActivityTypes {
+		if !activityType.IsValid() {
+			return fmt.Errorf("invalid activity type: %s", activityType)
+		}
+	}
+	for _, valueRating := range req.ValueRatings {
+		if !valueRating.IsValid() {
+			return fmt.Errorf("invalid value rating: %s", valueRating)
+		}
+	}
+	for _, tag := range req.Tags {
+		if len(strings.TrimSpace(tag)) > 100 {
+			return fmt.Errorf("tag names must be at most 100 characters")
+		}
+	}
+
+	return nil
+}
+
+// goalWindow returns the [start, end) window of the goal period containing at,
+// in at's location
+func goalWindow(goal *models.Goal, at time.Time, weekStart time.Weekday) (time.Time, time.Time, error) {
+	loc := at.Location()
+	year, month, day := at.Date()
+
+	switch goal.Period {
+	case models.GoalPeriodWeek:
+		offset := (int(at.Weekday()) - int(weekStart) + 7) % 7
+		start := time.Date(year, month, day-offset, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 7), nil
+	case models.GoalPeriodMonth:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0), nil
+	case models.GoalPeriodQuarter:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 3, 0), nil
+	case models.GoalPeriodYear:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0), nil
+	case models.GoalPeriodCustom:
+		if goal.StartDate == nil || goal.EndDate == nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("custom goal period has no dates")
+		}
+		start, err := time.ParseInLocation(time.DateOnly, *goal.StartDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid goal start_date: %w", err)
+		}
+		end, err := time.ParseInLocation(time.DateOnly, *goal.EndDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid goal end_date: %w", err)
+		}
+		return start, end.AddDate(0, 0, 1), nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", goal.Period)
+}
+
+// referenceTime parses a YYYY-MM-DD date in loc, defaulting to now
+func referenceTime(date string, loc *time.Location) (time.Time, error) {
+	if date == "" {
+		return time.Now().In(loc), nil
+	}
+
+	at, err := time.ParseInLocation(time.DateOnly, date, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+	}
+	return at, nil
+}
+
+// elapsedFraction returns how much of [start, end) has passed at now, between 0 and 1
+func elapsedFraction(start, end, now time.Time) float64 {
+	switch {
+	case !now.After(start):
+		return 0
+	case !now.Before(end):
+		return 1
+	}
+	return float64(now.Sub(start)) / float64(end.Sub(start))
+}
+
+// goalMetricValue extracts the value a goal is compared on from the measured entries
+func goalMetricValue(metric models.GoalMetric, row store.GetGoalMetricRow) float64 {
+	switch metric {
+	case models.GoalMetricMinutes:
+		return float64(row.MatchedMinutes)
+	case models.GoalMetricEntries:
+		return float64(row.MatchedEntries)
+	case models.GoalMetricShare:
+		if row.TotalMinutes == 0 {
+			return 0
+		}
+		return math.Round(float64(row.MatchedMinutes)*10000/float64(row.TotalMinutes)) / 100
+	}
+	return 0
+}
+
+// measureProgress compares a measured value with its target. At-least goals are
+// on track while the value keeps pace with the elapsed part of the period;
+// at-most goals are on track while the value stays within the limit.
+func measureProgress(comparison models.GoalComparison, current, target, elapsed float64) (float64, models.ProgressState) {
+	if comparison == models.GoalAtMost {
+		percent := 100.0
+		if current > target {
+			percent = math.Round(target*10000/current) / 100
+		}
+
+		switch {
+		case current > target && elapsed >= 1:
+			return percent, models.ProgressMissed
+		case current > target:
+			return percent, models.ProgressBehind
+		case elapsed >= 1:
+			return percent, models.ProgressAchieved
+		}
+		return percent, models.ProgressOnTrack
+	}
+
+	percent := math.Round(current*10000/target) / 100
+	switch {
+	case current >= target:
+		return percent, models.ProgressAchieved
+	case elapsed >= 1:
+		return percent, models.ProgressMissed
+	case current >= target*elapsed:
+		return percent, models.ProgressOnTrack
+	}
+	return percent, models.ProgressBehind
+}
+
+// combineKeyResults averages key result progress (each capped at 100%) and
+// reports the weakest key result state
+func combineKeyResults(keyResults []*models.GoalProgress) (float64, models.ProgressState) {
+	if len(keyResults) == 0 {
+		return 0, models.ProgressOnTrack
+	}
+
+	total := 0.0
+	achieved := 0
+	state := models.ProgressOnTrack
+	for _, keyResult := range keyResults {
+		total += math.Min(keyResult.Percent, 100)
+		switch keyResult.State {
+		case models.ProgressAchieved:
+			achieved++
+		case models.ProgressMissed:
+			state = models.ProgressMissed
+		case models.ProgressBehind:
+			if state != models.ProgressMissed {
+				state = models.ProgressBehind
+			}
+		}
+	}
+
+	if achieved == len(keyResults) {
+		state = models.ProgressAchieved
+	}
+	return math.Round(total*100/float64(len(keyResults))) / 100, state
+}
+
+// goalToModel converts SQLC Goal to models.Goal
+func goalToModel(sqlcGoal store.Goal) *models.Goal {
+	goal := &models.Goal{
+		ID:            sqlcGoal.ID,
+		UserID:        sqlcGoal.UserID,
+		ParentID:      pgUUIDToUUID(sqlcGoal.ParentID),
+		Title:         sqlcGoal.Title,
+		Description:   pgTextToString(sqlcGoal.Description),
+		Metric:        models.GoalMetric(sqlcGoal.Metric),
+		Comparison:    models.GoalComparison(sqlcGoal.Comparison),
+		TargetValue:   sqlcGoal.TargetValue,
+		Period:        models.GoalPeriod(sqlcGoal.Period),
+		ActivityTypes: make([]models.ActivityType, len(sqlcGoal.ActivityTypes)),
+		Tags:          sqlcGoal.Tags,
+		ProjectID:     pgUUIDToUUID(sqlcGoal.ProjectID),
+		ValueRatings:  make([]models.ValueRating, len(sqlcGoal.ValueRatings)),
+		Status:        models.GoalStatus(sqlcGoal.Status),
+		CreatedAt:     pgTimestamptzToTime(sqlcGoal.CreatedAt),
+		UpdatedAt:     pgTimestamptzToTime(sqlcGoal.UpdatedAt),
+	}
+
+	for i, activityType := range sqlcGoal.ActivityTypes {
+		goal.ActivityTypes[i] = models.ActivityType(activityType)
+	}
+	for i, valueRating := range sqlcGoal.ValueRatings {
+		goal.ValueRatings[i] = models.ValueRating(valueRating)
+	}
+	if goal.Tags == nil {
+		goal.Tags = []string{}
+	}
+
+	if sqlcGoal.StartDate.Valid {
+		startDate := sqlcGoal.StartDate.Time.Format(time.DateOnly)
+		goal.StartDate = &startDate
+	}
+	if sqlcGoal.EndDate.Valid {
+		endDate := sqlcGoal.EndDate.Time.Format(time.DateOnly)
+		goal.EndDate = &endDate
+	}
+
+	return goal
+}
@@ -0,0 +1,194 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGoalService_Lifecycle tests goal CRUD, key results and progress against log entries
+func TestGoalService_Lifecycle(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	analyticsService := services.NewAnalyticsService(db, testLogger)
+	goalService := services.NewGoalService(db, testLogger, analyticsService)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "goals@example.com",
+		Password:  "password123",
+		FirstName: "Goal",
+		LastName:  "Setter",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	// Monday 2025-05-12: 3h learning, 1h design doc, 1h support
+	monday := time.Date(2025, 5, 12, 9, 0, 0, 0, time.UTC)
+	entries := []struct {
+		title    string
+		kind     models.ActivityType
+		minutes  int
+		tags     []string
+		startsAt time.Time
+	}{
+		{"Go course", models.ActivityLearning, 180, nil, monday},
+		{"Storage design doc", models.ActivityDocumentation, 60, []string{"design-doc"}, monday.Add(4 * time.Hour)},
+		{"On-call tickets", models.ActivitySupport, 60, nil, monday.Add(6 * time.Hour)},
+	}
+	for _, e := range entries {
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       e.title,
+			Type:        e.kind,
+			StartTime:   e.startsAt,
+			EndTime:     e.startsAt.Add(time.Duration(e.minutes) * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactPersonal,
+			Tags:        e.tags,
+		})
+		require.NoError(t, err)
+	}
+
+	learning, err := goalService.CreateGoal(ctx, userID, &models.GoalRequest{
+		Title:         "8h/week of learning",
+		Metric:        models.GoalMetricMinutes,
+		TargetValue:   480,
+		Period:        models.GoalPeriodWeek,
+		ActivityTypes: []models.ActivityType{models.ActivityLearning},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.GoalAtLeast, learning.Comparison)
+	assert.Equal(t, models.GoalActive, learning.Status)
+
+	t.Run("MinutesProgress", func(t *testing.T) {
+		progress, err := goalService.GetGoalProgress(ctx, userID, learning.ID.String(), "2025-05-14", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-05-12", progress.PeriodStart)
+		assert.Equal(t, "2025-05-18", progress.PeriodEnd)
+		assert.Equal(t, float64(180), progress.CurrentValue)
+		assert.Equal(t, 37.5, progress.Percent)
+		assert.Equal(t, models.ProgressMissed, progress.State, "the week is over")
+
+		progress, err = goalService.GetGoalProgress(ctx, userID, learning.ID.String(), "2025-05-19", nil)
+		require.NoError(t, err)
+		assert.Zero(t, progress.CurrentValue, "next week starts from zero")
+	})
+
+	t.Run("EntriesByTag", func(t *testing.T) {
+		designDocs, err := goalService.CreateGoal(ctx, userID, &models.GoalRequest{
+			Title:       "3 design docs this quarter",
+			Metric:      models.GoalMetricEntries,
+			TargetValue: 3,
+			Period:      models.GoalPeriodQuarter,
+			Tags:        []string{"design-doc"},
+		})
+		require.NoError(t, err)
+
+		progress, err := goalService.GetGoalProgress(ctx, userID, designDocs.ID.String(), "2025-06-30", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-04-01", progress.PeriodStart)
+		assert.Equal(t, float64(1), progress.CurrentValue)
+	})
+
+	t.Run("ShareCeiling", func(t *testing.T) {
+		support, err := goalService.CreateGoal(ctx, userID, &models.GoalRequest{
+			Title:         "Support below 20% of my time",
+			Metric:        models.GoalMetricShare,
+			Comparison:    models.GoalAtMost,
+			TargetValue:   20,
+			Period:        models.GoalPeriodMonth,
+			ActivityTypes: []models.ActivityType{models.ActivitySupport},
+		})
+		require.NoError(t, err)
+
+		progress, err := goalService.GetGoalProgress(ctx, userID, support.ID.String(), "2025-05-01", nil)
+		require.NoError(t, err)
+		assert.Equal(t, float64(20), progress.CurrentValue)
+		assert.Equal(t, models.ProgressAchieved, progress.State)
+	})
+
+	t.Run("KeyResults", func(t *testing.T) {
+		objective, err := goalService.CreateGoal(ctx, userID, &models.GoalRequest{
+			Title:  "Grow as an engineer",
+			Metric: models.GoalMetricKeyResults,
+			Period: models.GoalPeriodWeek,
+		})
+		require.NoError(t, err)
+
+		_, err = goalService.UpdateGoal(ctx, userID, learning.ID.String(), &models.GoalRequest{
+			ParentID:      &objective.ID,
+			Title:         learning.Title,
+			Metric:        models.GoalMetricMinutes,
+			TargetValue:   120,
+			Period:        models.GoalPeriodWeek,
+			ActivityTypes: []models.ActivityType{models.ActivityLearning},
+		})
+		require.NoError(t, err)
+
+		_, err = goalService.CreateGoal(ctx, userID, &models.GoalRequest{
+			ParentID:    &learning.ID,
+			Title:       "Key results cannot nest",
+			Metric:      models.GoalMetricEntries,
+			TargetValue: 1,
+			Period:      models.GoalPeriodWeek,
+		})
+		assert.ErrorContains(t, err, "parent goal must be a top-level goal")
+
+		_, err = goalService.UpdateGoal(ctx, userID, objective.ID.String(), &models.GoalRequest{
+			Title:       objective.Title,
+			Metric:      models.GoalMetricMinutes,
+			TargetValue: 60,
+			Period:      models.GoalPeriodWeek,
+		})
+		assert.ErrorContains(t, err, "metric must stay key_results")
+
+		progress, err := goalService.GetGoalProgress(ctx, userID, objective.ID.String(), "2025-05-12", nil)
+		require.NoError(t, err)
+		require.Len(t, progress.KeyResults, 1)
+		assert.Equal(t, float64(100), progress.Percent)
+		assert.Equal(t, models.ProgressAchieved, progress.State)
+
+		active, err := goalService.GetActiveGoalsProgress(ctx, userID, "2025-05-12", nil)
+		require.NoError(t, err)
+		for _, goalProgress := range active {
+			assert.NotEqual(t, learning.ID, goalProgress.GoalID, "key results are nested under their objective")
+		}
+
+		require.NoError(t, goalService.DeleteGoal(ctx, userID, objective.ID.String()))
+		_, err = goalService.GetGoal(ctx, userID, learning.ID.String())
+		assert.ErrorContains(t, err, "not found", "deleting an objective deletes its key results")
+	})
+
+	t.Run("ListAndOwnership", func(t *testing.T) {
+		goals, err := goalService.GetGoals(ctx, userID, models.GoalActive)
+		require.NoError(t, err)
+		assert.Len(t, goals, 2)
+
+		other, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     "goals-other@example.com",
+			Password:  "password123",
+			FirstName: "Other",
+			LastName:  "User",
+		})
+		require.NoError(t, err)
+
+		_, err = goalService.GetGoal(ctx, other.ID.String(), goals[0].ID.String())
+		assert.ErrorContains(t, err, "not found")
+		assert.ErrorContains(t, goalService.DeleteGoal(ctx, other.ID.String(), goals[0].ID.String()), "not found")
+	})
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validGoalRequest() *models.GoalRequest {
+	return &models.GoalRequest{
+		Title:         "8h/week of learning",
+		Metric:        models.GoalMetricMinutes,
+		TargetValue:   480,
+		Period:        models.GoalPeriodWeek,
+		ActivityTypes: []models.ActivityType{models.ActivityLearning},
+	}
+}
+
+func TestValidateGoalRequest(t *testing.T) {
+	date := func(s string) *string { return &s }
+	parentID := uuid.New()
+
+	tests := []struct {
+		name    string
+		modify  func(req *models.GoalRequest)
+		wantErr string
+	}{
+		{"valid minutes goal", func(req *models.GoalRequest) {}, ""},
+		{"missing title", func(req *models.GoalRequest) { req.Title = "  " }, "title is required"},
+		{"invalid metric", func(req *models.GoalRequest) { req.Metric = "hours" }, "invalid metric"},
+		{"invalid comparison", func(req *models.GoalRequest) { req.Comparison = "exactly" }, "invalid comparison"},
+		{"invalid period", func(req *models.GoalRequest) { req.Period = "sprint" }, "invalid period"},
+		{"invalid status", func(req *models.GoalRequest) { req.Status = "paused" }, "invalid goal status"},
+		{"zero target", func(req *models.GoalRequest) { req.TargetValue = 0 }, "target_value must be greater than 0"},
+		{"zero ceiling", func(req *models.GoalRequest) {
+			req.Comparison = models.GoalAtMost
+			req.TargetValue = 0
+		}, ""},
+		{"share above 100", func(req *models.GoalRequest) {
+			req.Metric = models.GoalMetricShare
+			req.TargetValue = 120
+		}, "at most 100"},
+		{"invalid activity type", func(req *models.GoalRequest) {
+			req.ActivityTypes = []models.ActivityType{"napping"}
+		}, "invalid activity type"},
+		{"invalid value rating", func(req *models.GoalRequest) {
+			req.ValueRatings = []models.ValueRating{"epic"}
+		}, "invalid value rating"},
+		{"custom period", func(req *models.GoalRequest) {
+			req.Period = models.GoalPeriodCustom
+			req.StartDate = date("2025-01-01")
+			req.EndDate = date("2025-03-31")
+		}, ""},
+		{"custom period without dates", func(req *models.GoalRequest) {
+			req.Period = models.GoalPeriodCustom
+		}, "start_date and end_date are required"},
+		{"custom period reversed", func(req *models.GoalRequest) {
+			req.Period = models.GoalPeriodCustom
+			req.StartDate = date("2025-03-31")
+			req.EndDate = date("2025-01-01")
+		}, "end_date must be on or after start_date"},
+		{"dates on recurring period", func(req *models.GoalRequest) {
+			req.StartDate = date("2025-01-01")
+		}, "only allowed for custom periods"},
+		{"objective", func(req *models.GoalRequest) {
+			req.Metric = models.GoalMetricKeyResults
+			req.ActivityTypes = nil
+		}, ""},
+		{"objective with filters", func(req *models.GoalRequest) {
+			req.Metric = models.GoalMetricKeyResults
+		}, "cannot have entry filters"},
+		{"nested objective", func(req *models.GoalRequest) {
+			req.Metric = models.GoalMetricKeyResults
+			req.ActivityTypes = nil
+			req.ParentID = &parentID
+		}, "cannot have a parent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validGoalRequest()
+			tt.modify(req)
+
+			err := validateGoalRequest(req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		req := validGoalRequest()
+		require.NoError(t, validateGoalRequest(req))
+		assert.Equal(t, models.GoalAtLeast, req.Comparison)
+		assert.Equal(t, models.GoalActive, req.Status)
+	})
+
+	t.Run("objective target", func(t *testing.T) {
+		req := &models.GoalRequest{Title: "Ship the platform", Metric: models.GoalMetricKeyResults, Period: models.GoalPeriodQuarter}
+		require.NoError(t, validateGoalRequest(req))
+		assert.Equal(t, float64(100), req.TargetValue)
+	})
+}
+
+func TestGoalWindow(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	// Thursday 2025-05-15 in São Paulo
+	at := time.Date(2025, 5, 15, 22, 30, 0, 0, saoPaulo)
+	date := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		goal      *models.Goal
+		weekStart time.Weekday
+		wantStart string
+		wantEnd   string
+	}{
+		{"week from monday", &models.Goal{Period: models.GoalPeriodWeek}, time.Monday, "2025-05-12", "2025-05-19"},
+		{"week from sunday", &models.Goal{Period: models.GoalPeriodWeek}, time.Sunday, "2025-05-11", "2025-05-18"},
+		{"month", &models.Goal{Period: models.GoalPeriodMonth}, time.Monday, "2025-05-01", "2025-06-01"},
+		{"quarter", &models.Goal{Period: models.GoalPeriodQuarter}, time.Monday, "2025-04-01", "2025-07-01"},
+		{"year", &models.Goal{Period: models.GoalPeriodYear}, time.Monday, "2025-01-01", "2026-01-01"},
+		{"custom", &models.Goal{Period: models.GoalPeriodCustom, StartDate: date("2025-05-01"), EndDate: date("2025-05-20")}, time.Monday, "2025-05-01", "2025-05-21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := goalWindow(tt.goal, at, tt.weekStart)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start.Format(time.DateOnly))
+			assert.Equal(t, tt.wantEnd, end.Format(time.DateOnly))
+			assert.Equal(t, saoPaulo, start.Location(), "windows start at local midnight")
+			assert.Zero(t, start.Hour())
+		})
+	}
+
+	t.Run("custom without dates", func(t *testing.T) {
+		_, _, err := goalWindow(&models.Goal{Period: models.GoalPeriodCustom}, at, time.Monday)
+		assert.Error(t, err)
+	})
+}
+
+func TestElapsedFraction(t *testing.T) {
+	start := time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 4)
+
+	assert.Equal(t, float64(0), elapsedFraction(start, end, start.Add(-time.Hour)))
+	assert.Equal(t, 0.25, elapsedFraction(start, end, start.AddDate(0, 0, 1)))
+	assert.Equal(t, float64(1), elapsedFraction(start, end, end))
+}
+
+func TestGoalMetricValue(t *testing.T) {
+	row := store.GetGoalMetricRow{MatchedMinutes: 90, MatchedEntries: 3, TotalMinutes: 600}
+
+	assert.Equal(t, float64(90), goalMetricValue(models.GoalMetricMinutes, row))
+	assert.Equal(t, float64(3), goalMetricValue(models.GoalMetricEntries, row))
+	assert.Equal(t, float64(15), goalMetricValue(models.GoalMetricShare, row))
+	assert.Equal(t, float64(0), goalMetricValue(models.GoalMetricShare, store.GetGoalMetricRow{}), "no logged time is a zero share")
+}
+
+func TestMeasureProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		comparison  models.GoalComparison
+		current     float64
+		target      float64
+		elapsed     float64
+		wantPercent float64
+		wantState   models.ProgressState
+	}{
+		{"at least reached", models.GoalAtLeast, 500, 480, 0.5, 104.17, models.ProgressAchieved},
+		{"at least keeping pace", models.GoalAtLeast, 240, 480, 0.5, 50, models.ProgressOnTrack},
+		{"at least behind pace", models.GoalAtLeast, 120, 480, 0.5, 25, models.ProgressBehind},
+		{"at least period over", models.GoalAtLeast, 2, 3, 1, 66.67, models.ProgressMissed},
+		{"at most within limit", models.GoalAtMost, 15, 20, 0.5, 100, models.ProgressOnTrack},
+		{"at most over limit", models.GoalAtMost, 25, 20, 0.5, 80, models.ProgressBehind},
+		{"at most period over", models.GoalAtMost, 15, 20, 1, 100, models.ProgressAchieved},
+		{"at most missed", models.GoalAtMost, 40, 20, 1, 50, models.ProgressMissed},
+		{"at most zero limit", models.GoalAtMost, 0, 0, 0.5, 100, models.ProgressOnTrack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, state := measureProgress(tt.comparison, tt.current, tt.target, tt.elapsed)
+			assert.Equal(t, tt.wantPercent, percent)
+			assert.Equal(t, tt.wantState, state)
+		})
+	}
+}
+
+func TestCombineKeyResults(t *testing.T) {
+	kr := func(percent float64, state models.ProgressState) *models.GoalProgress {
+		return &models.GoalProgress{Percent: percent, State: state}
+	}
+
+	percent, state := combineKeyResults(nil)
+	assert.Equal(t, float64(0), percent)
+	assert.Equal(t, models.ProgressOnTrack, state)
+
+	percent, state = combineKeyResults([]*models.GoalProgress{kr(150, models.ProgressAchieved), kr(100, models.ProgressAchieved)})
+	assert.Equal(t, float64(100), percent, "overachieving key results are capped")
+	assert.Equal(t, models.ProgressAchieved, state)
+
+	percent, state = combineKeyResults([]*models.GoalProgress{kr(100, models.ProgressAchieved), kr(50, models.ProgressOnTrack), kr(20, models.ProgressBehind)})
+	assert.Equal(t, 56.67, percent)
+	assert.Equal(t, models.ProgressBehind, state)
+
+	_, state = combineKeyResults([]*models.GoalProgress{kr(20, models.ProgressBehind), kr(10, models.ProgressMissed)})
+	assert.Equal(t, models.ProgressMissed, state)
+}
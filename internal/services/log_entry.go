@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,20 +16,46 @@ import (
 
 // LogEntryService handles all business logic for activity log entries
 type LogEntryService struct {
-	db     *database.DB
-	logger *logging.Logger
+	db             *database.DB
+	logger         *logging.Logger
+	trashRetention time.Duration
+	notifyWrite    func()
+	webhooks       *WebhookService
 }
 
 // NewLogEntryService creates a new LogEntryService instance
 func NewLogEntryService(db *database.DB, logger *logging.Logger) *LogEntryService {
 	return &LogEntryService{
-		db:     db,
-		logger: logger.WithComponent("log_entry_service"),
+		db:             db,
+		logger:         logger.WithComponent("log_entry_service"),
+		trashRetention: defaultTrashRetentionDays * 24 * time.Hour,
 	}
 }
 
+// WithWriteNotifier registers a callback run whenever a log entry is created, changed,
+// deleted or restored, such as AnalyticsService.NotifyLogEntryWrite. It must not block.
+func (s *LogEntryService) WithWriteNotifier(notify func()) *LogEntryService {
+	s.notifyWrite = notify
+	return s
+}
+
+// WithWebhooks publishes log.created, log.updated and log.deleted events to the
+// user's webhooks with every write
+func (s *LogEntryService) WithWebhooks(webhooks *WebhookService) *LogEntryService {
+	s.webhooks = webhooks
+	return s
+}
+
 // CreateLogEntry creates a new log entry with associated tags
 func (s *LogEntryService) CreateLogEntry(ctx context.Context, userID string, req *models.LogEntryRequest) (*models.LogEntry, error) {
+	// Fields omitted by the request fall back to the project's defaults
+	if req.ProjectID != nil {
+		if err := s.applyProjectDefaults(ctx, userID, req); err != nil {
+			s.logger.LogError(ctx, err, "Failed to apply project defaults", "user_id", userID, "project_id", req.ProjectID)
+			return nil, err
+		}
+	}
+
 	// Validate request
 	if err := s.validateLogEntryRequest(req); err != nil {
 		s.logger.LogError(ctx, err, "Log entry validation failed", "user_id", userID, "type", req.Type)
@@ -54,49 +81,9 @@ func (s *LogEntryService) CreateLogEntry(ctx context.Context, userID string, req
 
 	// Start write transaction to add log entry and tags
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
-		// Create log entry
-		sqlcEntry, err := qtx.CreateLogEntry(ctx, store.CreateLogEntryParams{
-			UserID:      userUUID,
-			ProjectID:   uuidToPgUUID(req.ProjectID),
-			Title:       req.Title,
-			Description: stringToPgText(req.Description),
-			Type:        string(req.Type),
-			StartTime:   timeToPgTimestamptz(req.StartTime),
-			EndTime:     timeToPgTimestamptz(req.EndTime),
-			ValueRating: string(req.ValueRating),
-			ImpactLevel: string(req.ImpactLevel),
-		})
-		if err != nil {
-			s.logger.LogError(ctx, err, "Database error creating log entry", "user_id", userID, "title", req.Title)
-			return fmt.Errorf("failed to create log entry: %w", err)
-		}
-
-		// Handle tags if provided
-		if len(req.Tags) > 0 {
-			s.logger.Info("Adding tags to log entry", "log_entry_id", sqlcEntry.ID, "tags_count", len(req.Tags), "tags", req.Tags)
-			for _, tagName := range req.Tags {
-				tagID, err := s.ensureTagExists(ctx, qtx, tagName)
-				if err != nil {
-					s.logger.LogError(ctx, err, "Failed to handle tag", "tag_name", tagName, "log_entry_id", sqlcEntry.ID)
-					return fmt.Errorf("failed to handle tag %s: %w", tagName, err)
-				}
-
-				err = qtx.AddTagToLogEntry(ctx, store.AddTagToLogEntryParams{
-					LogEntryID: sqlcEntry.ID,
-					TagID:      tagID,
-				})
-				if err != nil {
-					s.logger.LogError(ctx, err, "Failed to associate tag", "tag_name", tagName, "log_entry_id", sqlcEntry.ID, "tag_id", tagID)
-					return fmt.Errorf("failed to associate tag: %w", err)
-				}
-			}
-		}
-
-		// Convert to model and return
-		logEntry = s.sqlcToModel(sqlcEntry)
-		logEntry.Tags = req.Tags
-
-		return nil
+		var err error
+		logEntry, err = s.createLogEntryTx(ctx, qtx, userUUID, req)
+		return err
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Transaction failed for log entry creation", "user_id", userID, "title", req.Title)
 		return nil, fmt.Errorf("failed to create log entry: %w", err)
@@ -112,6 +99,97 @@ func (s *LogEntryService) CreateLogEntry(ctx context.Context, userID string, req
 	return logEntry, nil
 }
 
+// applyProjectDefaults fills the omitted fields of req from the defaults of its project.
+// Entries can only be logged on active projects the user owns or may log time on
+// through a team.
+func (s *LogEntryService) applyProjectDefaults(ctx context.Context, userID string, req *models.LogEntryRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return s.db.Read(ctx, func(qtx *store.Queries) error {
+		project, err := authorizeProject(ctx, qtx, *req.ProjectID, userUUID, models.TeamPermissionLogTime)
+		if err != nil {
+			return err
+		}
+		if project.ArchivedAt.Valid {
+			return fmt.Errorf("project is archived")
+		}
+
+		projectDefaultsToModel(project).Apply(req)
+		return nil
+	})
+}
+
+// checkLogEntryProject checks that the user may file a log entry under projectID
+// when that changes the entry's project: like new entries, it must be an active
+// project they own or may log time on through a team
+func checkLogEntryProject(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID, projectID *uuid.UUID) error {
+	if projectID == nil {
+		return nil
+	}
+
+	existing, err := qtx.GetLogEntryByID(ctx, entryUUID)
+	if err == nil && existing.ProjectID.Valid && existing.ProjectID.Bytes == *projectID {
+		return nil
+	}
+
+	project, err := authorizeProject(ctx, qtx, *projectID, userUUID, models.TeamPermissionLogTime)
+	if err != nil {
+		return err
+	}
+	if project.ArchivedAt.Valid {
+		return fmt.Errorf("project is archived")
+	}
+	return nil
+}
+
+// createLogEntryTx inserts a validated log entry and its tags inside an existing transaction
+func (s *LogEntryService) createLogEntryTx(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, req *models.LogEntryRequest) (*models.LogEntry, error) {
+	// Create log entry
+	sqlcEntry, err := qtx.CreateLogEntry(ctx, store.CreateLogEntryParams{
+		UserID:      userUUID,
+		ProjectID:   uuidToPgUUID(req.ProjectID),
+		Title:       req.Title,
+		Description: stringToPgText(req.Description),
+		Type:        string(req.Type),
+		StartTime:   timeToPgTimestamptz(req.StartTime),
+		EndTime:     timeToPgTimestamptz(req.EndTime),
+		ValueRating: string(req.ValueRating),
+		ImpactLevel: string(req.ImpactLevel),
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Database error creating log entry", "user_id", userUUID, "title", req.Title)
+		return nil, fmt.Errorf("failed to create log entry: %w", err)
+	}
+
+	// Handle tags if provided
+	if len(req.Tags) > 0 {
+		s.logger.Info("Adding tags to log entry", "log_entry_id", sqlcEntry.ID, "tags_count", len(req.Tags), "tags", req.Tags)
+	}
+	tags, err := s.addLogEntryTags(ctx, qtx, userUUID, sqlcEntry.ID, req.Tags)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to add tags to log entry", "log_entry_id", sqlcEntry.ID)
+		return nil, err
+	}
+
+	if err := s.replaceLogEntryLinks(ctx, qtx, sqlcEntry.ID, req.Links); err != nil {
+		return nil, err
+	}
+
+	// Convert to model and return
+	logEntry := s.sqlcToModel(sqlcEntry)
+	logEntry.Tags = tags
+	logEntry.Links = req.Links
+
+	if err := s.recordRevisionTx(ctx, qtx, logEntry, models.RevisionCreated); err != nil {
+		return nil, err
+	}
+
+	return logEntry, nil
+}
+
 // GetLogEntry retrieves a single log entry by ID
 func (s *LogEntryService) GetLogEntry(ctx context.Context, userID, logEntryID string) (*models.LogEntry, error) {
 	userUUID, err := uuid.Parse(userID)
@@ -156,6 +234,14 @@ func (s *LogEntryService) GetLogEntry(ctx context.Context, userID, logEntryID st
 			logEntry.Tags[i] = tag.Name
 		}
 
+		// Get links and attachments
+		if logEntry.Links, err = s.getLogEntryLinks(ctx, qtx, entryUUID); err != nil {
+			return err
+		}
+		if logEntry.Attachments, err = s.getLogEntryAttachments(ctx, qtx, userUUID, entryUUID); err != nil {
+			return err
+		}
+
 		return nil
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Failed to get log entry", "user_id", userID, "log_entry_id", logEntryID)
@@ -182,6 +268,9 @@ type LogEntryFilters struct {
 	Tags        []string
 	Limit       int32 // for pagination
 	Offset      int32 // for pagination
+
+	// IncludeTagDescendants also matches entries tagged with child tags of Tags
+	IncludeTagDescendants bool
 }
 
 // GetLogEntries retrieves log entries with optional filtering
@@ -199,8 +288,15 @@ func (s *LogEntryService) GetLogEntries(ctx context.Context, userID string, filt
 		entries     []*models.LogEntry
 	)
 
+	effectiveFilters := filters
+
 	// Start read transaction to get log entries
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		effectiveFilters, err = s.expandTagFilters(ctx, qtx, userUUID, filters)
+		if err != nil {
+			return err
+		}
+
 		if filters != nil && (!filters.StartDate.IsZero() || !filters.EndDate.IsZero()) {
 			// Use date range query
 			startDate := filters.StartDate
@@ -263,8 +359,8 @@ func (s *LogEntryService) GetLogEntries(ctx context.Context, userID string, filt
 	}
 
 	// Apply additional filters
-	if filters != nil {
-		entries = s.applyFilters(entries, filters)
+	if effectiveFilters != nil {
+		entries = s.applyFilters(entries, effectiveFilters)
 	}
 
 	s.logger.Info("Log entries retrieved successfully",
@@ -306,6 +402,11 @@ func (s *LogEntryService) UpdateLogEntry(ctx context.Context, userID, logEntryID
 
 	// Start write transaction to add log entry and tags
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		// Moving an entry to a project needs access to it; entries may stay where they are
+		if err := checkLogEntryProject(ctx, qtx, userUUID, entryUUID, req.ProjectID); err != nil {
+			return err
+		}
+
 		// Update log entry
 		sqlcEntry, err := qtx.UpdateLogEntry(ctx, store.UpdateLogEntryParams{
 			ID:          entryUUID,
@@ -324,45 +425,21 @@ func (s *LogEntryService) UpdateLogEntry(ctx context.Context, userID, logEntryID
 		}
 
 		// Update tags - remove old associations and create new ones
-		// First, get existing tags to remove them
-		existingTags, err := qtx.GetTagsForLogEntry(ctx, entryUUID)
+		tags, err := s.replaceLogEntryTags(ctx, qtx, userUUID, entryUUID, req.Tags)
 		if err != nil {
-			return fmt.Errorf("failed to get existing tags: %w", err)
-		}
-
-		// Remove existing tag associations
-		for _, tag := range existingTags {
-			err = qtx.RemoveTagFromLogEntry(ctx, store.RemoveTagFromLogEntryParams{
-				LogEntryID: entryUUID,
-				TagID:      tag.ID,
-			})
-			if err != nil {
-				return fmt.Errorf("failed to remove existing tag: %w", err)
-			}
+			return err
 		}
 
-		if len(req.Tags) > 0 {
-			for _, tagName := range req.Tags {
-				tagID, err := s.ensureTagExists(ctx, qtx, tagName)
-				if err != nil {
-					return fmt.Errorf("failed to handle tag %s: %w", tagName, err)
-				}
-
-				err = qtx.AddTagToLogEntry(ctx, store.AddTagToLogEntryParams{
-					LogEntryID: entryUUID,
-					TagID:      tagID,
-				})
-				if err != nil {
-					return fmt.Errorf("failed to associate tag: %w", err)
-				}
-			}
+		if err := s.replaceLogEntryLinks(ctx, qtx, entryUUID, req.Links); err != nil {
+			return err
 		}
 
 		// Convert to model
 		logEntry = s.sqlcToModel(sqlcEntry)
-		logEntry.Tags = req.Tags
+		logEntry.Tags = tags
+		logEntry.Links = req.Links
 
-		return nil
+		return s.recordRevisionTx(ctx, qtx, logEntry, models.RevisionUpdated)
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Transaction failed for log entry update", "user_id", userID, "log_entry_id", logEntryID)
 		return nil, fmt.Errorf("failed to update log entry: %w", err)
@@ -377,7 +454,57 @@ func (s *LogEntryService) UpdateLogEntry(ctx context.Context, userID, logEntryID
 	return logEntry, nil
 }
 
-// DeleteLogEntry deletes a log entry
+// replaceLogEntryTags swaps the tag set of a log entry inside an existing
+// transaction and returns the names of the tags now linked to it
+func (s *LogEntryService) replaceLogEntryTags(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID, tags []string) ([]string, error) {
+	// First, get existing tags to remove them
+	existingTags, err := qtx.GetTagsForLogEntry(ctx, entryUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing tags: %w", err)
+	}
+
+	// Remove existing tag associations
+	for _, tag := range existingTags {
+		err = qtx.RemoveTagFromLogEntry(ctx, store.RemoveTagFromLogEntryParams{
+			LogEntryID: entryUUID,
+			TagID:      tag.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove existing tag: %w", err)
+		}
+	}
+
+	return s.addLogEntryTags(ctx, qtx, userUUID, entryUUID, tags)
+}
+
+// addLogEntryTags links the named tags to a log entry, creating missing ones.
+// It returns the names of the linked tags in request order: aliases resolve to
+// their tag, so two names of one tag give a single name.
+func (s *LogEntryService) addLogEntryTags(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID, tags []string) ([]string, error) {
+	names := make([]string, 0, len(tags))
+	for _, tagName := range tags {
+		tag, err := s.ensureTagExists(ctx, qtx, userUUID, tagName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to handle tag %s: %w", tagName, err)
+		}
+
+		err = qtx.AddTagToLogEntry(ctx, store.AddTagToLogEntryParams{
+			LogEntryID: entryUUID,
+			TagID:      tag.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to associate tag: %w", err)
+		}
+
+		if !slices.Contains(names, tag.Name) {
+			names = append(names, tag.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// DeleteLogEntry moves a log entry to the trash
 func (s *LogEntryService) DeleteLogEntry(ctx context.Context, userID, logEntryID string) error {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
@@ -393,21 +520,9 @@ func (s *LogEntryService) DeleteLogEntry(ctx context.Context, userID, logEntryID
 
 	s.logger.Info("Deleting log entry", "user_id", userID, "log_entry_id", logEntryID)
 
-	// Start write transaction to delete log entry
+	// Start write transaction to move the log entry to the trash
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
-		rowsAffected, err := qtx.DeleteLogEntry(ctx, store.DeleteLogEntryParams{
-			ID:     entryUUID,
-			UserID: userUUID,
-		})
-		if err != nil {
-			return err
-		}
-
-		if rowsAffected == 0 {
-			return fmt.Errorf("log entry not found")
-		}
-
-		return nil
+		return s.trashLogEntryTx(ctx, qtx, userUUID, entryUUID)
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Failed to delete log entry", "user_id", userID, "log_entry_id", logEntryID)
 		return fmt.Errorf("failed to delete log entry: %w", err)
@@ -471,16 +586,24 @@ func (s *LogEntryService) validateLogEntryRequest(req *models.LogEntryRequest) e
 		return fmt.Errorf("invalid impact level: %s", req.ImpactLevel)
 	}
 
+	// Links validation
+	if err := validateLogEntryLinks(req.Links); err != nil {
+		s.logger.Warn("Validation failed: invalid links", "error", err)
+		return err
+	}
+
 	return nil
 }
 
 // sqlcToModel converts SQLC LogEntry to models.LogEntry
 func (s *LogEntryService) sqlcToModel(sqlcEntry store.LogEntry) *models.LogEntry {
+	description := pgTextToString(sqlcEntry.Description)
 	return &models.LogEntry{
 		ID:              sqlcEntry.ID,
 		UserID:          sqlcEntry.UserID,
 		Title:           sqlcEntry.Title,
-		Description:     pgTextToString(sqlcEntry.Description),
+		Description:     description,
+		DescriptionHTML: renderDescription(description),
 		Type:            models.ActivityType(sqlcEntry.Type),
 		ProjectID:       pgUUIDToUUID(sqlcEntry.ProjectID),
 		StartTime:       pgTimestamptzToTime(sqlcEntry.StartTime),
@@ -493,28 +616,31 @@ func (s *LogEntryService) sqlcToModel(sqlcEntry store.LogEntry) *models.LogEntry
 	}
 }
 
-// ensureTagExists gets or creates a tag by name
-func (s *LogEntryService) ensureTagExists(ctx context.Context, qtx *store.Queries, tagName string) (uuid.UUID, error) {
-	// Try to get existing tag
-	tag, err := qtx.GetTagByName(ctx, tagName)
-	if err == nil {
-		s.logger.Info("Using existing tag", "tag_name", tagName, "tag_id", tag.ID)
-		return tag.ID, nil
+// ensureTagExists gets or creates a tag by name in the user's namespace
+func (s *LogEntryService) ensureTagExists(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, tagName string) (store.Tag, error) {
+	tag, err := ensureUserTag(ctx, qtx, userUUID, tagName)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to ensure tag exists", "tag_name", tagName)
+		return store.Tag{}, err
+	}
+	return tag, nil
+}
+
+// expandTagFilters returns filters whose tag list includes descendant tags when requested.
+// The caller's filters are never modified.
+func (s *LogEntryService) expandTagFilters(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, filters *LogEntryFilters) (*LogEntryFilters, error) {
+	if filters == nil || !filters.IncludeTagDescendants || len(filters.Tags) == 0 {
+		return filters, nil
 	}
 
-	// Create new tag if it doesn't exist
-	s.logger.Info("Creating new tag", "tag_name", tagName)
-	newTag, err := qtx.CreateTag(ctx, store.CreateTagParams{
-		Name:  tagName,
-		Color: stringToPgText(&[]string{"#3B82F6"}[0]), // Default blue color
-	})
+	tags, err := expandTagNames(ctx, qtx, userUUID, filters.Tags)
 	if err != nil {
-		s.logger.LogError(ctx, err, "Failed to create new tag", "tag_name", tagName)
-		return uuid.Nil, err
+		return nil, err
 	}
 
-	s.logger.Info("New tag created successfully", "tag_name", tagName, "tag_id", newTag.ID)
-	return newTag.ID, nil
+	expanded := *filters
+	expanded.Tags = tags
+	return &expanded, nil
 }
 
 // applyFilters applies additional filters to log entries
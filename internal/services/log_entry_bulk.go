@@ -0,0 +1,491 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// maxBulkItems bounds the number of log entries a single bulk operation can touch
+const maxBulkItems = 500
+
+// bulkOperation applies one bulk item inside a transaction
+type bulkOperation func(ctx context.Context, qtx *store.Queries, id uuid.UUID, index int) (*models.LogEntry, error)
+
+// bulkItemError marks a failure caused by a specific item, as opposed to
+// a failure of the surrounding transaction
+type bulkItemError struct {
+	index int
+	err   error
+}
+
+func (e *bulkItemError) Error() string { return e.err.Error() }
+func (e *bulkItemError) Unwrap() error { return e.err }
+
+// BulkUpdateLogEntries replaces several log entries at once
+func (s *LogEntryService) BulkUpdateLogEntries(ctx context.Context, userID string, req *models.BulkUpdateRequest) (*models.BulkResult, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in BulkUpdateLogEntries", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	mode, err := bulkMode(req.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("at least one item is required")
+	}
+	if len(req.Items) > maxBulkItems {
+		return nil, fmt.Errorf("a bulk operation can include at most %d items", maxBulkItems)
+	}
+
+	ids := make([]uuid.UUID, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.ID
+	}
+
+	s.logger.Info("Bulk updating log entries", "user_id", userID, "mode", mode, "items", len(ids))
+
+	return s.runBulk(ctx, mode, ids, func(ctx context.Context, qtx *store.Queries, id uuid.UUID, index int) (*models.LogEntry, error) {
+		entryReq := &req.Items[index].Entry
+		if err := s.validateLogEntryRequest(entryReq); err != nil {
+			return nil, err
+		}
+		if err := checkLogEntryProject(ctx, qtx, userUUID, id, entryReq.ProjectID); err != nil {
+			return nil, err
+		}
+
+		sqlcEntry, err := qtx.UpdateLogEntry(ctx, store.UpdateLogEntryParams{
+			ID:          id,
+			Title:       entryReq.Title,
+			Description: stringToPgText(entryReq.Description),
+			Type:        string(entryReq.Type),
+			ProjectID:   uuidToPgUUID(entryReq.ProjectID),
+			StartTime:   timeToPgTimestamptz(entryReq.StartTime),
+			EndTime:     timeToPgTimestamptz(entryReq.EndTime),
+			ValueRating: string(entryReq.ValueRating),
+			ImpactLevel: string(entryReq.ImpactLevel),
+			UserID:      userUUID,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return nil, fmt.Errorf("log entry not found")
+			}
+			return nil, err
+		}
+
+		tags, err := s.replaceLogEntryTags(ctx, qtx, userUUID, id, entryReq.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.replaceLogEntryLinks(ctx, qtx, id, entryReq.Links); err != nil {
+			return nil, err
+		}
+
+		entry := s.sqlcToModel(sqlcEntry)
+		entry.Tags = tags
+		entry.Links = entryReq.Links
+		if err := s.recordRevisionTx(ctx, qtx, entry, models.RevisionUpdated); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
+
+// BulkDeleteLogEntries moves the selected log entries to the trash
+func (s *LogEntryService) BulkDeleteLogEntries(ctx context.Context, userID string, req *models.BulkDeleteRequest) (*models.BulkResult, error) {
+	userUUID, mode, ids, err := s.prepareBulkSelection(ctx, userID, req.Mode, &req.BulkSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Bulk deleting log entries", "user_id", userID, "mode", mode, "items", len(ids))
+
+	return s.runBulk(ctx, mode, ids, func(ctx context.Context, qtx *store.Queries, id uuid.UUID, _ int) (*models.LogEntry, error) {
+		return nil, s.trashLogEntryTx(ctx, qtx, userUUID, id)
+	})
+}
+
+// BulkRetagLogEntries adds and removes tags on the selected log entries
+func (s *LogEntryService) BulkRetagLogEntries(ctx context.Context, userID string, req *models.BulkRetagRequest) (*models.BulkResult, error) {
+	if len(req.AddTags) == 0 && len(req.RemoveTags) == 0 {
+		return nil, fmt.Errorf("add_tags or remove_tags is required")
+	}
+
+	userUUID, mode, ids, err := s.prepareBulkSelection(ctx, userID, req.Mode, &req.BulkSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Bulk retagging log entries", "user_id", userID, "mode", mode, "items", len(ids),
+		"add_tags", req.AddTags, "remove_tags", req.RemoveTags)
+
+	return s.runBulk(ctx, mode, ids, func(ctx context.Context, qtx *store.Queries, id uuid.UUID, _ int) (*models.LogEntry, error) {
+		sqlcEntry, err := s.getOwnedLogEntry(ctx, qtx, userUUID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tagName := range req.RemoveTags {
+			tag, err := qtx.GetTagByName(ctx, store.GetTagByNameParams{
+				UserID: uuidToPgUUID(&userUUID),
+				Name:   tagName,
+			})
+			if err != nil {
+				if database.NoRows(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get tag %s: %w", tagName, err)
+			}
+
+			if err := qtx.RemoveTagFromLogEntry(ctx, store.RemoveTagFromLogEntryParams{
+				LogEntryID: id,
+				TagID:      tag.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to remove tag %s: %w", tagName, err)
+			}
+		}
+
+		for _, tagName := range req.AddTags {
+			tag, err := s.ensureTagExists(ctx, qtx, userUUID, tagName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to handle tag %s: %w", tagName, err)
+			}
+
+			if err := qtx.AddTagToLogEntry(ctx, store.AddTagToLogEntryParams{
+				LogEntryID: id,
+				TagID:      tag.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to associate tag: %w", err)
+			}
+		}
+
+		entry, err := s.withAssociations(ctx, qtx, sqlcEntry)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.recordRevisionTx(ctx, qtx, entry, models.RevisionUpdated); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
+
+// BulkMoveLogEntries reassigns the selected log entries to a project
+func (s *LogEntryService) BulkMoveLogEntries(ctx context.Context, userID string, req *models.BulkMoveRequest) (*models.BulkResult, error) {
+	userUUID, mode, ids, err := s.prepareBulkSelection(ctx, userID, req.Mode, &req.BulkSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Bulk moving log entries", "user_id", userID, "mode", mode, "items", len(ids), "project_id", req.ProjectID)
+
+	return s.runBulk(ctx, mode, ids, func(ctx context.Context, qtx *store.Queries, id uuid.UUID, _ int) (*models.LogEntry, error) {
+		if err := checkLogEntryProject(ctx, qtx, userUUID, id, req.ProjectID); err != nil {
+			return nil, err
+		}
+
+		rowsAffected, err := qtx.UpdateLogEntryProject(ctx, store.UpdateLogEntryProjectParams{
+			ID:        id,
+			UserID:    userUUID,
+			ProjectID: uuidToPgUUID(req.ProjectID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if rowsAffected == 0 {
+			return nil, fmt.Errorf("log entry not found")
+		}
+
+		sqlcEntry, err := s.getOwnedLogEntry(ctx, qtx, userUUID, id)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := s.withAssociations(ctx, qtx, sqlcEntry)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.recordRevisionTx(ctx, qtx, entry, models.RevisionUpdated); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
+
+// runBulk applies op to every ID. In atomic mode all items share one
+// transaction and the first failure rolls back everything; in best-effort
+// mode each item gets its own transaction.
+func (s *LogEntryService) runBulk(ctx context.Context, mode models.BulkMode, ids []uuid.UUID, op bulkOperation) (*models.BulkResult, error) {
+	result := &models.BulkResult{
+		Mode:    mode,
+		Total:   len(ids),
+		Results: make([]models.BulkItemResult, len(ids)),
+	}
+
+	if mode == models.BulkBestEffort {
+		for i, id := range ids {
+			var entry *models.LogEntry
+			err := s.db.Write(ctx, func(qtx *store.Queries) error {
+				var err error
+				entry, err = op(ctx, qtx, id, i)
+				return err
+			})
+			result.Results[i] = bulkItemResult(i, id, entry, err)
+		}
+		tallyBulkResult(result)
+		result.Committed = result.Succeeded > 0
+		return result, nil
+	}
+
+	entries := make([]*models.LogEntry, len(ids))
+	err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		clear(entries)
+		for i, id := range ids {
+			entry, err := op(ctx, qtx, id, i)
+			if err != nil {
+				return &bulkItemError{index: i, err: err}
+			}
+			entries[i] = entry
+		}
+		return nil
+	})
+
+	var itemErr *bulkItemError
+	if err != nil && !errors.As(err, &itemErr) {
+		s.logger.LogError(ctx, err, "Bulk transaction failed", "mode", mode, "items", len(ids))
+		return nil, fmt.Errorf("bulk operation failed: %w", err)
+	}
+
+	for i, id := range ids {
+		switch {
+		case itemErr == nil:
+			result.Results[i] = bulkItemResult(i, id, entries[i], nil)
+		case i == itemErr.index:
+			result.Results[i] = bulkItemResult(i, id, nil, itemErr.err)
+		default:
+			result.Results[i] = models.BulkItemResult{Index: i, ID: id, Status: models.BulkItemRolledBack}
+		}
+	}
+	tallyBulkResult(result)
+	result.Committed = itemErr == nil
+
+	return result, nil
+}
+
+// prepareBulkSelection parses the common inputs of selection-based bulk operations
+func (s *LogEntryService) prepareBulkSelection(ctx context.Context, userID string, requested models.BulkMode, selection *models.BulkSelection) (uuid.UUID, models.BulkMode, []uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in bulk operation", "user_id", userID)
+		return uuid.Nil, "", nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	mode, err := bulkMode(requested)
+	if err != nil {
+		return uuid.Nil, "", nil, err
+	}
+
+	ids, err := s.resolveBulkSelection(ctx, userUUID, selection)
+	if err != nil {
+		return uuid.Nil, "", nil, err
+	}
+
+	return userUUID, mode, ids, nil
+}
+
+// resolveBulkSelection turns explicit IDs or a filter into the list of log entry IDs to process
+func (s *LogEntryService) resolveBulkSelection(ctx context.Context, userUUID uuid.UUID, selection *models.BulkSelection) ([]uuid.UUID, error) {
+	if len(selection.IDs) > 0 && selection.Filter != nil {
+		return nil, fmt.Errorf("provide either ids or filter, not both")
+	}
+
+	if len(selection.IDs) > 0 {
+		ids := dedupeUUIDs(selection.IDs)
+		if len(ids) > maxBulkItems {
+			return nil, fmt.Errorf("a bulk operation can include at most %d items", maxBulkItems)
+		}
+		return ids, nil
+	}
+
+	if selection.Filter == nil {
+		return nil, fmt.Errorf("ids or filter is required")
+	}
+
+	filters, err := bulkFilterToLogEntryFilters(selection.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		filters, err := s.expandTagFilters(ctx, qtx, userUUID, filters)
+		if err != nil {
+			return err
+		}
+
+		sqlcEntries, err := qtx.GetLogEntriesByUserAndDateRange(ctx, store.GetLogEntriesByUserAndDateRangeParams{
+			UserID:    userUUID,
+			StartTime: timeToPgTimestamptz(filters.StartDate),
+			EndTime:   timeToPgTimestamptz(filters.EndDate),
+		})
+		if err != nil {
+			return err
+		}
+
+		entries := make([]*models.LogEntry, len(sqlcEntries))
+		for i, sqlcEntry := range sqlcEntries {
+			entries[i] = s.sqlcToModel(sqlcEntry)
+			if len(filters.Tags) > 0 {
+				tags, err := qtx.GetTagsForLogEntry(ctx, sqlcEntry.ID)
+				if err != nil {
+					return err
+				}
+				entries[i].Tags = make([]string, len(tags))
+				for j, tag := range tags {
+					entries[i].Tags[j] = tag.Name
+				}
+			}
+		}
+
+		for _, entry := range s.applyFilters(entries, filters) {
+			ids = append(ids, entry.ID)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to resolve bulk selection: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("filter did not match any log entries")
+	}
+	if len(ids) > maxBulkItems {
+		return nil, fmt.Errorf("filter matches %d log entries; a bulk operation can include at most %d", len(ids), maxBulkItems)
+	}
+
+	return ids, nil
+}
+
+// getOwnedLogEntry fetches a log entry and hides entries of other users
+func (s *LogEntryService) getOwnedLogEntry(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID) (store.LogEntry, error) {
+	sqlcEntry, err := qtx.GetLogEntryByID(ctx, entryUUID)
+	if err != nil || sqlcEntry.UserID != userUUID {
+		return store.LogEntry{}, fmt.Errorf("log entry not found")
+	}
+	return sqlcEntry, nil
+}
+
+// withAssociations converts a log entry to its model including the current tags and links
+func (s *LogEntryService) withAssociations(ctx context.Context, qtx *store.Queries, sqlcEntry store.LogEntry) (*models.LogEntry, error) {
+	tags, err := qtx.GetTagsForLogEntry(ctx, sqlcEntry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	links, err := s.getLogEntryLinks(ctx, qtx, sqlcEntry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := s.sqlcToModel(sqlcEntry)
+	entry.Tags = make([]string, len(tags))
+	for i, tag := range tags {
+		entry.Tags[i] = tag.Name
+	}
+	entry.Links = links
+	return entry, nil
+}
+
+// bulkFilterToLogEntryFilters converts a bulk filter into LogEntryFilters.
+// The end date is inclusive, so the range runs until the end of that day (UTC).
+func bulkFilterToLogEntryFilters(filter *models.BulkFilter) (*LogEntryFilters, error) {
+	startDate, err := time.Parse(time.DateOnly, filter.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_date format, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse(time.DateOnly, filter.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end_date format, expected YYYY-MM-DD")
+	}
+
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end_date must be on or after start_date")
+	}
+
+	if filter.Type != nil && !filter.Type.IsValid() {
+		return nil, fmt.Errorf("invalid activity type: %s", *filter.Type)
+	}
+	if filter.ValueRating != nil && !filter.ValueRating.IsValid() {
+		return nil, fmt.Errorf("invalid value rating: %s", *filter.ValueRating)
+	}
+	if filter.ImpactLevel != nil && !filter.ImpactLevel.IsValid() {
+		return nil, fmt.Errorf("invalid impact level: %s", *filter.ImpactLevel)
+	}
+
+	filters := &LogEntryFilters{
+		StartDate:   startDate,
+		EndDate:     endDate.AddDate(0, 0, 1),
+		Type:        filter.Type,
+		ValueRating: filter.ValueRating,
+		ImpactLevel: filter.ImpactLevel,
+		Tags:        filter.Tags,
+
+		IncludeTagDescendants: filter.IncludeDescendants,
+	}
+	if filter.ProjectID != nil {
+		projectID := filter.ProjectID.String()
+		filters.ProjectID = &projectID
+	}
+
+	return filters, nil
+}
+
+func bulkMode(mode models.BulkMode) (models.BulkMode, error) {
+	if mode == "" {
+		return models.BulkAtomic, nil
+	}
+	if !mode.IsValid() {
+		return "", fmt.Errorf("invalid bulk mode: %s", mode)
+	}
+	return mode, nil
+}
+
+func bulkItemResult(index int, id uuid.UUID, entry *models.LogEntry, err error) models.BulkItemResult {
+	if err != nil {
+		return models.BulkItemResult{Index: index, ID: id, Status: models.BulkItemFailed, Error: err.Error()}
+	}
+	return models.BulkItemResult{Index: index, ID: id, Status: models.BulkItemSucceeded, Data: entry}
+}
+
+func tallyBulkResult(result *models.BulkResult) {
+	result.Succeeded, result.Failed = 0, 0
+	for _, item := range result.Results {
+		if item.Status == models.BulkItemSucceeded {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+}
+
+func dedupeUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogEntryService_BulkOperations tests atomic and best-effort bulk edits
+func TestLogEntryService_BulkOperations(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "bulk@example.com",
+		Password:  "password123",
+		FirstName: "Bulk",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	baseTime := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	newRequest := func(i int) models.LogEntryRequest {
+		return models.LogEntryRequest{
+			Title:       "Bulk entry",
+			Type:        models.ActivityDevelopment,
+			StartTime:   baseTime.Add(time.Duration(i) * time.Hour),
+			EndTime:     baseTime.Add(time.Duration(i)*time.Hour + 30*time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+			Tags:        []string{"draft"},
+		}
+	}
+
+	var ids []uuid.UUID
+	for i := range 3 {
+		req := newRequest(i)
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, &req)
+		require.NoError(t, err)
+		ids = append(ids, entry.ID)
+	}
+
+	t.Run("AtomicUpdateRollsBackOnFailure", func(t *testing.T) {
+		valid := newRequest(0)
+		valid.Title = "Renamed"
+
+		result, err := logEntryService.BulkUpdateLogEntries(ctx, userID, &models.BulkUpdateRequest{
+			Items: []models.BulkUpdateItem{
+				{ID: ids[0], Entry: valid},
+				{ID: uuid.New(), Entry: newRequest(1)},
+			},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, models.BulkItemRolledBack, result.Results[0].Status)
+		assert.Equal(t, models.BulkItemFailed, result.Results[1].Status)
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, ids[0].String())
+		require.NoError(t, err)
+		assert.Equal(t, "Bulk entry", entry.Title)
+	})
+
+	t.Run("BestEffortUpdateKeepsSuccesses", func(t *testing.T) {
+		valid := newRequest(0)
+		valid.Title = "Renamed"
+
+		result, err := logEntryService.BulkUpdateLogEntries(ctx, userID, &models.BulkUpdateRequest{
+			Mode: models.BulkBestEffort,
+			Items: []models.BulkUpdateItem{
+				{ID: ids[0], Entry: valid},
+				{ID: uuid.New(), Entry: newRequest(1)},
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, ids[0].String())
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", entry.Title)
+	})
+
+	t.Run("ForeignProjectFails", func(t *testing.T) {
+		stranger, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     "stranger-bulk@example.com",
+			Password:  "password123",
+			FirstName: "Stranger",
+			LastName:  "User",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+		foreign, err := projectService.CreateProject(ctx, stranger.ID.String(), &models.ProjectRequest{
+			Name:   "Not yours",
+			Color:  "#990000",
+			Status: models.ProjectActive,
+		})
+		require.NoError(t, err)
+
+		valid := newRequest(0)
+		valid.Title = "Renamed again"
+		moved := newRequest(1)
+		moved.ProjectID = &foreign.ID
+		items := []models.BulkUpdateItem{
+			{ID: ids[0], Entry: valid},
+			{ID: ids[1], Entry: moved},
+		}
+
+		result, err := logEntryService.BulkUpdateLogEntries(ctx, userID, &models.BulkUpdateRequest{Items: items})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, models.BulkItemRolledBack, result.Results[0].Status)
+		assert.Equal(t, models.BulkItemFailed, result.Results[1].Status)
+		assert.Contains(t, result.Results[1].Error, "project not found")
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, ids[0].String())
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", entry.Title)
+
+		result, err = logEntryService.BulkUpdateLogEntries(ctx, userID, &models.BulkUpdateRequest{
+			Mode:  models.BulkBestEffort,
+			Items: items,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		assert.Equal(t, models.BulkItemSucceeded, result.Results[0].Status)
+		assert.Equal(t, models.BulkItemFailed, result.Results[1].Status)
+		assert.Contains(t, result.Results[1].Error, "project not found")
+
+		entry, err = logEntryService.GetLogEntry(ctx, userID, ids[1].String())
+		require.NoError(t, err)
+		assert.Nil(t, entry.ProjectID)
+
+		moveResult, err := logEntryService.BulkMoveLogEntries(ctx, userID, &models.BulkMoveRequest{
+			ProjectID:     &foreign.ID,
+			BulkSelection: models.BulkSelection{IDs: ids[:1]},
+		})
+		require.NoError(t, err)
+		assert.False(t, moveResult.Committed)
+		assert.Equal(t, 1, moveResult.Failed)
+	})
+
+	t.Run("RetagByFilter", func(t *testing.T) {
+		result, err := logEntryService.BulkRetagLogEntries(ctx, userID, &models.BulkRetagRequest{
+			AddTags:    []string{"reviewed"},
+			RemoveTags: []string{"draft"},
+			BulkSelection: models.BulkSelection{Filter: &models.BulkFilter{
+				StartDate: "2025-03-10",
+				EndDate:   "2025-03-10",
+				Tags:      []string{"draft"},
+			}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Succeeded)
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, ids[1].String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"reviewed"}, entry.Tags)
+	})
+
+	t.Run("MoveToProject", func(t *testing.T) {
+		project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+			Name:   "Bulk target",
+			Color:  "#3366FF",
+			Status: models.ProjectActive,
+		})
+		require.NoError(t, err)
+
+		result, err := logEntryService.BulkMoveLogEntries(ctx, userID, &models.BulkMoveRequest{
+			ProjectID:     &project.ID,
+			BulkSelection: models.BulkSelection{IDs: ids[:2]},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Succeeded)
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, ids[0].String())
+		require.NoError(t, err)
+		require.NotNil(t, entry.ProjectID)
+		assert.Equal(t, project.ID, *entry.ProjectID)
+	})
+
+	t.Run("OtherUsersEntriesFail", func(t *testing.T) {
+		other, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     "other-bulk@example.com",
+			Password:  "password123",
+			FirstName: "Other",
+			LastName:  "User",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+
+		result, err := logEntryService.BulkDeleteLogEntries(ctx, other.ID.String(), &models.BulkDeleteRequest{
+			Mode:          models.BulkBestEffort,
+			BulkSelection: models.BulkSelection{IDs: ids},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Failed)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		result, err := logEntryService.BulkDeleteLogEntries(ctx, userID, &models.BulkDeleteRequest{
+			BulkSelection: models.BulkSelection{IDs: ids},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 3, result.Succeeded)
+	})
+}
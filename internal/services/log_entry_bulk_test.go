@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkMode(t *testing.T) {
+	mode, err := bulkMode("")
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkAtomic, mode, "atomic is the default")
+
+	mode, err = bulkMode(models.BulkBestEffort)
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkBestEffort, mode)
+
+	_, err = bulkMode("sometimes")
+	assert.Error(t, err)
+}
+
+func TestBulkFilterToLogEntryFilters(t *testing.T) {
+	support := models.ActivitySupport
+	projectID := uuid.New()
+
+	filters, err := bulkFilterToLogEntryFilters(&models.BulkFilter{
+		StartDate: "2025-01-06",
+		EndDate:   "2025-01-12",
+		Type:      &support,
+		ProjectID: &projectID,
+		Tags:      []string{"oncall"},
+
+		IncludeDescendants: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, filters.IncludeTagDescendants)
+	assert.Equal(t, "2025-01-06", filters.StartDate.Format("2006-01-02"))
+	assert.Equal(t, "2025-01-13", filters.EndDate.Format("2006-01-02"), "end date is inclusive")
+	assert.Equal(t, &support, filters.Type)
+	require.NotNil(t, filters.ProjectID)
+	assert.Equal(t, projectID.String(), *filters.ProjectID)
+
+	invalid := []*models.BulkFilter{
+		{StartDate: "", EndDate: "2025-01-12"},
+		{StartDate: "2025-01-06", EndDate: "12/01/2025"},
+		{StartDate: "2025-01-12", EndDate: "2025-01-06"},
+		{StartDate: "2025-01-06", EndDate: "2025-01-12", Type: func() *models.ActivityType { v := models.ActivityType("napping"); return &v }()},
+	}
+	for _, filter := range invalid {
+		_, err := bulkFilterToLogEntryFilters(filter)
+		assert.Error(t, err)
+	}
+}
+
+func TestBulkHelpers(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	assert.Equal(t, []uuid.UUID{a, b}, dedupeUUIDs([]uuid.UUID{a, b, a, b}))
+
+	result := &models.BulkResult{Results: []models.BulkItemResult{
+		bulkItemResult(0, a, nil, nil),
+		bulkItemResult(1, b, nil, assert.AnError),
+		{Index: 2, ID: uuid.New(), Status: models.BulkItemRolledBack},
+	}}
+	tallyBulkResult(result)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, models.BulkItemFailed, result.Results[1].Status)
+	assert.Equal(t, assert.AnError.Error(), result.Results[1].Error)
+}
+
+func TestResolveBulkSelectionValidation(t *testing.T) {
+	service := NewLogEntryService(nil, logging.NewTestLogger())
+	userID := uuid.New()
+
+	_, err := service.resolveBulkSelection(t.Context(), userID, &models.BulkSelection{})
+	assert.Error(t, err)
+
+	_, err = service.resolveBulkSelection(t.Context(), userID, &models.BulkSelection{
+		IDs:    []uuid.UUID{uuid.New()},
+		Filter: &models.BulkFilter{StartDate: "2025-01-01", EndDate: "2025-01-02"},
+	})
+	assert.Error(t, err)
+
+	ids := make([]uuid.UUID, maxBulkItems+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	_, err = service.resolveBulkSelection(t.Context(), userID, &models.BulkSelection{IDs: ids})
+	assert.Error(t, err)
+
+	id := uuid.New()
+	resolved, err := service.resolveBulkSelection(t.Context(), userID, &models.BulkSelection{IDs: []uuid.UUID{id, id}})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, resolved)
+}
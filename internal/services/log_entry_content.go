@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/markdown"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxLinksPerLogEntry bounds how many structured links one log entry can carry
+	maxLinksPerLogEntry = 20
+	// maxLinkTitleLength mirrors the log_entry_links.title column size
+	maxLinkTitleLength = 200
+	// maxLinkURLLength bounds the size of a link URL
+	maxLinkURLLength = 2000
+)
+
+// validateLogEntryLinks checks structured links before they are stored
+func validateLogEntryLinks(links []models.LogEntryLink) error {
+	if len(links) > maxLinksPerLogEntry {
+		return fmt.Errorf("too many links: maximum is %d", maxLinksPerLogEntry)
+	}
+
+	for i, link := range links {
+		if !link.Kind.IsValid() {
+			return fmt.Errorf("invalid link kind at position %d: %s", i, link.Kind)
+		}
+		if link.URL == "" || len(link.URL) > maxLinkURLLength {
+			return fmt.Errorf("invalid link URL at position %d", i)
+		}
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !markdown.SafeURL(link.URL) {
+			return fmt.Errorf("invalid link URL at position %d: only http and https URLs are allowed", i)
+		}
+		if link.Title != nil && len(strings.TrimSpace(*link.Title)) > maxLinkTitleLength {
+			return fmt.Errorf("link title at position %d exceeds %d characters", i, maxLinkTitleLength)
+		}
+	}
+
+	return nil
+}
+
+// replaceLogEntryLinks swaps the links of a log entry inside an existing transaction
+func (s *LogEntryService) replaceLogEntryLinks(ctx context.Context, qtx *store.Queries, entryUUID uuid.UUID, links []models.LogEntryLink) error {
+	if err := qtx.DeleteLinksForLogEntry(ctx, entryUUID); err != nil {
+		return fmt.Errorf("failed to remove existing links: %w", err)
+	}
+
+	for i, link := range links {
+		if _, err := qtx.CreateLogEntryLink(ctx, store.CreateLogEntryLinkParams{
+			LogEntryID: entryUUID,
+			Kind:       string(link.Kind),
+			Url:        link.URL,
+			Title:      stringToPgText(link.Title),
+			Position:   int32(i),
+		}); err != nil {
+			return fmt.Errorf("failed to create link: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getLogEntryLinks returns the links of a log entry in their stored order
+func (s *LogEntryService) getLogEntryLinks(ctx context.Context, qtx *store.Queries, entryUUID uuid.UUID) ([]models.LogEntryLink, error) {
+	rows, err := qtx.GetLinksForLogEntry(ctx, entryUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	links := make([]models.LogEntryLink, len(rows))
+	for i, row := range rows {
+		links[i] = models.LogEntryLink{
+			Kind:  models.LinkKind(row.Kind),
+			URL:   row.Url,
+			Title: pgTextToString(row.Title),
+		}
+	}
+	return links, nil
+}
+
+// getLogEntryAttachments returns the attachment metadata of a log entry
+func (s *LogEntryService) getLogEntryAttachments(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := qtx.GetAttachmentsForLogEntry(ctx, store.GetAttachmentsForLogEntryParams{
+		LogEntryID: entryUUID,
+		UserID:     userUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	attachments := make([]models.Attachment, len(rows))
+	for i, row := range rows {
+		attachments[i] = *attachmentToModel(row)
+	}
+	return attachments, nil
+}
+
+// attachmentToModel converts a stored attachment to its API model
+func attachmentToModel(a store.LogEntryAttachment) *models.Attachment {
+	return &models.Attachment{
+		ID:          a.ID,
+		LogEntryID:  a.LogEntryID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		CreatedAt:   pgTimestamptzToTime(a.CreatedAt),
+	}
+}
+
+// renderDescription renders a Markdown description to safe HTML
+func renderDescription(description *string) *string {
+	if description == nil || strings.TrimSpace(*description) == "" {
+		return nil
+	}
+	rendered := markdown.Render(*description)
+	return &rendered
+}
+
+// linksToJSON encodes links for storage in a revision
+func linksToJSON(links []models.LogEntryLink) ([]byte, error) {
+	if links == nil {
+		links = []models.LogEntryLink{}
+	}
+	data, err := json.Marshal(links)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode links: %w", err)
+	}
+	return data, nil
+}
+
+// linksFromJSON decodes links stored in a revision; malformed data yields no links
+func linksFromJSON(data []byte) []models.LogEntryLink {
+	links := []models.LogEntryLink{}
+	if len(data) == 0 {
+		return links
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return []models.LogEntryLink{}
+	}
+	return links
+}
@@ -0,0 +1,268 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/blobstore"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogEntryService_LinksAndAttachments tests markdown descriptions, structured links and attachments
+func TestLogEntryService_LinksAndAttachments(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	attachmentService := services.NewAttachmentService(db, testLogger, blobs, 1024, 2048)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "content@example.com",
+		Password:  "password123",
+		FirstName: "Content",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	otherUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "content-other@example.com",
+		Password:  "password123",
+		FirstName: "Other",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+
+	prTitle := "Fix race"
+	description := "Fixed the **race** in the cache, see https://example.com/postmortem"
+	start := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	req := &models.LogEntryRequest{
+		Title:       "Cache race fix",
+		Description: &description,
+		Type:        models.ActivityDebugging,
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		ValueRating: models.ValueHigh,
+		ImpactLevel: models.ImpactTeam,
+		Links: []models.LogEntryLink{
+			{Kind: models.LinkPullRequest, URL: "https://github.com/org/repo/pull/42", Title: &prTitle},
+			{Kind: models.LinkTicket, URL: "https://jira.example.com/browse/ENG-7"},
+		},
+	}
+
+	entry, err := logEntryService.CreateLogEntry(ctx, userID, req)
+	require.NoError(t, err)
+	entryID := entry.ID.String()
+
+	t.Run("DescriptionIsRendered", func(t *testing.T) {
+		require.NotNil(t, entry.DescriptionHTML)
+		assert.Contains(t, *entry.DescriptionHTML, "<strong>race</strong>")
+		assert.Contains(t, *entry.DescriptionHTML, `<a href="https://example.com/postmortem"`)
+	})
+
+	t.Run("LinksAreStoredInOrder", func(t *testing.T) {
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, entryID)
+		require.NoError(t, err)
+		require.Len(t, fetched.Links, 2)
+		assert.Equal(t, models.LinkPullRequest, fetched.Links[0].Kind)
+		require.NotNil(t, fetched.Links[0].Title)
+		assert.Equal(t, prTitle, *fetched.Links[0].Title)
+		assert.Equal(t, models.LinkTicket, fetched.Links[1].Kind)
+		assert.Empty(t, fetched.Attachments)
+	})
+
+	t.Run("UnsafeLinksAreRejected", func(t *testing.T) {
+		bad := *req
+		bad.Links = []models.LogEntryLink{{Kind: models.LinkOther, URL: "javascript:alert(1)"}}
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &bad)
+		require.Error(t, err)
+	})
+
+	t.Run("HistoryKeepsLinks", func(t *testing.T) {
+		edited := *req
+		edited.Links = nil
+		_, err := logEntryService.UpdateLogEntry(ctx, userID, entryID, &edited)
+		require.NoError(t, err)
+
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Empty(t, fetched.Links)
+
+		restored, err := logEntryService.RestoreLogEntryRevision(ctx, userID, entryID, 1)
+		require.NoError(t, err)
+		assert.Len(t, restored.Links, 2)
+
+		fetched, err = logEntryService.GetLogEntry(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Len(t, fetched.Links, 2)
+	})
+
+	var attachmentID string
+
+	t.Run("UploadAndDownload", func(t *testing.T) {
+		attachment, err := attachmentService.Upload(ctx, userID, entryID, "notes.txt", "text/plain; charset=utf-8", strings.NewReader("meeting notes"))
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", attachment.FileName)
+		assert.Equal(t, "text/plain", attachment.ContentType)
+		assert.Equal(t, int64(len("meeting notes")), attachment.SizeBytes)
+		attachmentID = attachment.ID.String()
+
+		meta, content, err := attachmentService.Download(ctx, userID, entryID, attachmentID)
+		require.NoError(t, err)
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, "meeting notes", string(data))
+		assert.Equal(t, attachment.ID, meta.ID)
+
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, entryID)
+		require.NoError(t, err)
+		require.Len(t, fetched.Attachments, 1)
+		assert.Equal(t, attachment.ID, fetched.Attachments[0].ID)
+	})
+
+	t.Run("UploadLimits", func(t *testing.T) {
+		_, err := attachmentService.Upload(ctx, userID, entryID, "big.txt", "text/plain", bytes.NewReader(make([]byte, 1025)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum size")
+
+		_, err = attachmentService.Upload(ctx, userID, entryID, "page.html", "text/html", strings.NewReader("<html></html>"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported attachment content type")
+
+		_, err = attachmentService.Upload(ctx, userID, entryID, "a.txt", "text/plain", bytes.NewReader(bytes.Repeat([]byte("a"), 1000)))
+		require.NoError(t, err)
+		_, err = attachmentService.Upload(ctx, userID, entryID, "b.txt", "text/plain", bytes.NewReader(bytes.Repeat([]byte("b"), 1000)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "quota exceeded")
+
+		usage, err := attachmentService.GetUsage(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000+len("meeting notes")), usage.UsedBytes)
+		assert.Equal(t, int64(2048), usage.QuotaBytes)
+	})
+
+	t.Run("OtherUsersCannotAccess", func(t *testing.T) {
+		_, _, err := attachmentService.Download(ctx, otherUser.ID.String(), entryID, attachmentID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+
+		_, err = attachmentService.Upload(ctx, otherUser.ID.String(), entryID, "x.txt", "text/plain", strings.NewReader("x"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("DeleteAttachment", func(t *testing.T) {
+		require.NoError(t, attachmentService.DeleteAttachment(ctx, userID, entryID, attachmentID))
+
+		_, _, err := attachmentService.Download(ctx, userID, entryID, attachmentID)
+		require.Error(t, err)
+	})
+
+	t.Run("PurgedEntriesLoseAttachments", func(t *testing.T) {
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, entryID))
+
+		// Attachments survive while the entry is in the trash
+		removed, err := attachmentService.SweepOrphans(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+
+		restored, err := logEntryService.RestoreFromTrash(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Len(t, restored.Links, 2)
+
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, entryID))
+		require.NoError(t, logEntryService.PurgeFromTrash(ctx, userID, entryID))
+
+		removed, err = attachmentService.SweepOrphans(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		usage, err := attachmentService.GetUsage(ctx, userID)
+		require.NoError(t, err)
+		assert.Zero(t, usage.UsedBytes)
+	})
+}
+
+// TestAttachmentService_ConcurrentUploads tests that concurrent uploads cannot exceed the quota together
+func TestAttachmentService_ConcurrentUploads(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	attachmentService := services.NewAttachmentService(db, testLogger, blobs, 1024, 2048)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "content-concurrent@example.com",
+		Password:  "password123",
+		FirstName: "Concurrent",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	start := time.Date(2025, 4, 2, 9, 0, 0, 0, time.UTC)
+	entry, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+		Title:       "Incident review",
+		Type:        models.ActivityMeeting,
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		ValueRating: models.ValueHigh,
+		ImpactLevel: models.ImpactTeam,
+	})
+	require.NoError(t, err)
+
+	const uploads = 8
+	var wg sync.WaitGroup
+	errs := make([]error, uploads)
+	for i := range uploads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = attachmentService.Upload(ctx, userID, entry.ID.String(), "part.txt", "text/plain", bytes.NewReader(bytes.Repeat([]byte("p"), 1000)))
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Contains(t, err.Error(), "quota exceeded")
+	}
+	assert.Equal(t, 2, succeeded, "only as many uploads as fit in the quota succeed")
+
+	usage, err := attachmentService.GetUsage(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), usage.UsedBytes)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLogEntryLinks(t *testing.T) {
+	title := "Review"
+	longTitle := strings.Repeat("t", maxLinkTitleLength+1)
+
+	tests := []struct {
+		name    string
+		links   []models.LogEntryLink
+		wantErr string
+	}{
+		{name: "no links", links: nil},
+		{name: "valid links", links: []models.LogEntryLink{
+			{Kind: models.LinkPullRequest, URL: "https://github.com/org/repo/pull/1", Title: &title},
+			{Kind: models.LinkTicket, URL: "http://jira.example.com/browse/ENG-1"},
+		}},
+		{name: "unknown kind", links: []models.LogEntryLink{{Kind: "video", URL: "https://example.com"}}, wantErr: "invalid link kind"},
+		{name: "javascript URL", links: []models.LogEntryLink{{Kind: models.LinkOther, URL: "javascript:alert(1)"}}, wantErr: "only http and https"},
+		{name: "mailto URL", links: []models.LogEntryLink{{Kind: models.LinkOther, URL: "mailto:team@example.com"}}, wantErr: "only http and https"},
+		{name: "missing host", links: []models.LogEntryLink{{Kind: models.LinkOther, URL: "https://"}}, wantErr: "only http and https"},
+		{name: "empty URL", links: []models.LogEntryLink{{Kind: models.LinkOther}}, wantErr: "invalid link URL"},
+		{name: "long title", links: []models.LogEntryLink{{Kind: models.LinkDocument, URL: "https://example.com", Title: &longTitle}}, wantErr: "exceeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLogEntryLinks(tt.links)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("too many links", func(t *testing.T) {
+		links := make([]models.LogEntryLink, maxLinksPerLogEntry+1)
+		for i := range links {
+			links[i] = models.LogEntryLink{Kind: models.LinkOther, URL: "https://example.com"}
+		}
+		err := validateLogEntryLinks(links)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too many links")
+	})
+}
+
+func TestLinksJSONRoundTrip(t *testing.T) {
+	title := "Design"
+	links := []models.LogEntryLink{
+		{Kind: models.LinkDesignDoc, URL: "https://docs.example.com/d/1", Title: &title},
+		{Kind: models.LinkIssue, URL: "https://github.com/org/repo/issues/2"},
+	}
+
+	data, err := linksToJSON(links)
+	require.NoError(t, err)
+	assert.Equal(t, links, linksFromJSON(data))
+
+	empty, err := linksToJSON(nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(empty))
+
+	assert.Equal(t, []models.LogEntryLink{}, linksFromJSON(nil))
+	assert.Equal(t, []models.LogEntryLink{}, linksFromJSON([]byte("not json")))
+}
+
+func TestRenderDescription(t *testing.T) {
+	assert.Nil(t, renderDescription(nil))
+
+	blank := "   "
+	assert.Nil(t, renderDescription(&blank))
+
+	description := "Fixed **login** <script>"
+	rendered := renderDescription(&description)
+	require.NotNil(t, rendered)
+	assert.Contains(t, *rendered, "<strong>login</strong>")
+	assert.Contains(t, *rendered, "&lt;script&gt;")
+}
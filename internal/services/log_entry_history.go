@@ -0,0 +1,474 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultTrashRetentionDays is how long soft-deleted log entries are kept before purging
+	defaultTrashRetentionDays = 30
+	// trashPurgeInterval is how often the background purger runs
+	trashPurgeInterval = 24 * time.Hour
+)
+
+// WithTrashRetention overrides how many days soft-deleted log entries stay in the trash
+func (s *LogEntryService) WithTrashRetention(days int) *LogEntryService {
+	if days > 0 {
+		s.trashRetention = time.Duration(days) * 24 * time.Hour
+	}
+	return s
+}
+
+// GetLogEntryHistory returns every revision of a log entry, newest first.
+// History stays available while the entry is in the trash.
+func (s *LogEntryService) GetLogEntryHistory(ctx context.Context, userID, logEntryID string) ([]*models.LogEntryRevision, error) {
+	userUUID, entryUUID, err := s.parseEntryIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*models.LogEntryRevision
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		rows, err := qtx.GetLogEntryRevisions(ctx, store.GetLogEntryRevisionsParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return fmt.Errorf("log entry not found")
+		}
+
+		revisions = make([]*models.LogEntryRevision, len(rows))
+		for i, row := range rows {
+			revisions[i] = revisionToModel(row)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get log entry history", "user_id", userID, "log_entry_id", logEntryID)
+		return nil, fmt.Errorf("failed to get log entry history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RestoreLogEntryRevision overwrites a log entry with the content of an earlier revision.
+// The restore itself is appended to the history, so it can be undone as well.
+func (s *LogEntryService) RestoreLogEntryRevision(ctx context.Context, userID, logEntryID string, revision int) (*models.LogEntry, error) {
+	userUUID, entryUUID, err := s.parseEntryIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision < 1 {
+		return nil, fmt.Errorf("invalid revision: %d", revision)
+	}
+
+	s.logger.Info("Restoring log entry revision", "user_id", userID, "log_entry_id", logEntryID, "revision", revision)
+
+	var logEntry *models.LogEntry
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := qtx.GetLogEntryTrash(ctx, store.GetLogEntryTrashParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		}); err == nil {
+			return fmt.Errorf("log entry is in the trash; restore it from the trash first")
+		} else if !database.NoRows(err) {
+			return err
+		}
+
+		rev, err := qtx.GetLogEntryRevision(ctx, store.GetLogEntryRevisionParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+			Revision:   int32(revision),
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("revision not found")
+			}
+			return err
+		}
+
+		sqlcEntry, err := qtx.UpdateLogEntry(ctx, store.UpdateLogEntryParams{
+			ID:          entryUUID,
+			Title:       rev.Title,
+			Description: rev.Description,
+			Type:        rev.Type,
+			ProjectID:   rev.ProjectID,
+			StartTime:   rev.StartTime,
+			EndTime:     rev.EndTime,
+			ValueRating: rev.ValueRating,
+			ImpactLevel: rev.ImpactLevel,
+			UserID:      userUUID,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("log entry not found")
+			}
+			return err
+		}
+
+		snapshot := revisionSnapshot(rev)
+		tags, err := s.replaceLogEntryTags(ctx, qtx, userUUID, entryUUID, snapshot.Tags)
+		if err != nil {
+			return err
+		}
+		if err := s.replaceLogEntryLinks(ctx, qtx, entryUUID, snapshot.Links); err != nil {
+			return err
+		}
+
+		logEntry = s.sqlcToModel(sqlcEntry)
+		logEntry.Tags = tags
+		logEntry.Links = snapshot.Links
+
+		return s.recordRevisionTx(ctx, qtx, logEntry, models.RevisionRestored)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to restore log entry revision", "user_id", userID, "log_entry_id", logEntryID, "revision", revision)
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+
+	s.logger.Info("Log entry revision restored", "user_id", userID, "log_entry_id", logEntryID, "revision", revision)
+	return logEntry, nil
+}
+
+// GetTrash returns the user's soft-deleted log entries, most recently deleted first
+func (s *LogEntryService) GetTrash(ctx context.Context, userID string) ([]*models.TrashedLogEntry, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTrash", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var trashed []*models.TrashedLogEntry
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		rows, err := qtx.GetTrashedLogEntries(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		trashed = make([]*models.TrashedLogEntry, len(rows))
+		for i, row := range rows {
+			deletedAt := pgTimestamptzToTime(row.DeletedAt)
+			trashed[i] = &models.TrashedLogEntry{
+				ID: row.LogEntryID,
+				LogEntrySnapshot: revisionSnapshot(store.LogEntryRevision{
+					Title:       row.Title,
+					Description: row.Description,
+					Type:        row.Type,
+					ProjectID:   row.ProjectID,
+					StartTime:   row.StartTime,
+					EndTime:     row.EndTime,
+					ValueRating: row.ValueRating,
+					ImpactLevel: row.ImpactLevel,
+					Tags:        row.Tags,
+					Links:       row.Links,
+				}),
+				DeletedAt: deletedAt,
+				PurgeAt:   deletedAt.Add(s.trashRetention),
+			}
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get trash", "user_id", userID)
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	return trashed, nil
+}
+
+// RestoreFromTrash brings a soft-deleted log entry back with its original ID and tags
+func (s *LogEntryService) RestoreFromTrash(ctx context.Context, userID, logEntryID string) (*models.LogEntry, error) {
+	userUUID, entryUUID, err := s.parseEntryIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Restoring log entry from trash", "user_id", userID, "log_entry_id", logEntryID)
+
+	var logEntry *models.LogEntry
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteLogEntryTrash(ctx, store.DeleteLogEntryTrashParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("log entry not found in trash")
+		}
+
+		latest, err := qtx.GetLatestLogEntryRevision(ctx, store.GetLatestLogEntryRevisionParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get deleted revision: %w", err)
+		}
+
+		// The original creation time is when the first revision was recorded
+		first, err := qtx.GetLogEntryRevision(ctx, store.GetLogEntryRevisionParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+			Revision:   1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get first revision: %w", err)
+		}
+
+		sqlcEntry, err := qtx.RestoreLogEntry(ctx, store.RestoreLogEntryParams{
+			ID:          entryUUID,
+			UserID:      userUUID,
+			ProjectID:   latest.ProjectID,
+			Title:       latest.Title,
+			Description: latest.Description,
+			Type:        latest.Type,
+			StartTime:   latest.StartTime,
+			EndTime:     latest.EndTime,
+			ValueRating: latest.ValueRating,
+			ImpactLevel: latest.ImpactLevel,
+			CreatedAt:   first.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore log entry: %w", err)
+		}
+
+		snapshot := revisionSnapshot(latest)
+		tags, err := s.replaceLogEntryTags(ctx, qtx, userUUID, entryUUID, snapshot.Tags)
+		if err != nil {
+			return err
+		}
+		if err := s.replaceLogEntryLinks(ctx, qtx, entryUUID, snapshot.Links); err != nil {
+			return err
+		}
+
+		logEntry = s.sqlcToModel(sqlcEntry)
+		logEntry.Tags = tags
+		logEntry.Links = snapshot.Links
+
+		return s.recordRevisionTx(ctx, qtx, logEntry, models.RevisionRestored)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to restore log entry from trash", "user_id", userID, "log_entry_id", logEntryID)
+		return nil, fmt.Errorf("failed to restore log entry: %w", err)
+	}
+
+	s.logger.Info("Log entry restored from trash", "user_id", userID, "log_entry_id", logEntryID)
+	return logEntry, nil
+}
+
+// PurgeFromTrash permanently deletes a soft-deleted log entry and its history
+func (s *LogEntryService) PurgeFromTrash(ctx context.Context, userID, logEntryID string) error {
+	userUUID, entryUUID, err := s.parseEntryIDs(ctx, userID, logEntryID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteLogEntryTrash(ctx, store.DeleteLogEntryTrashParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("log entry not found in trash")
+		}
+
+		return qtx.DeleteLogEntryRevisions(ctx, store.DeleteLogEntryRevisionsParams{
+			LogEntryID: entryUUID,
+			UserID:     userUUID,
+		})
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to purge log entry", "user_id", userID, "log_entry_id", logEntryID)
+		return fmt.Errorf("failed to purge log entry: %w", err)
+	}
+
+	s.logger.Info("Log entry purged from trash", "user_id", userID, "log_entry_id", logEntryID)
+	return nil
+}
+
+// PurgeExpiredTrash permanently deletes trashed log entries older than the retention period
+func (s *LogEntryService) PurgeExpiredTrash(ctx context.Context) (int64, error) {
+	cutoff := timeToPgTimestamptz(time.Now().Add(-s.trashRetention))
+
+	var purged int64
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := qtx.DeleteExpiredLogEntryRevisions(ctx, cutoff); err != nil {
+			return err
+		}
+
+		var err error
+		purged, err = qtx.PurgeExpiredLogEntryTrash(ctx, cutoff)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return purged, nil
+}
+
+// StartTrashPurger periodically purges expired trash until ctx is cancelled
+func (s *LogEntryService) StartTrashPurger(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Log entry trash purger started", "interval", trashPurgeInterval.String(), "retention", s.trashRetention.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Log entry trash purger stopped")
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeExpiredTrash(ctx)
+			if err != nil {
+				s.logger.LogError(ctx, err, "Failed to purge log entry trash")
+			} else if purged > 0 {
+				s.logger.Info("Expired log entries purged", "entries_purged", purged)
+			}
+		}
+	}
+}
+
+// trashLogEntryTx soft-deletes a log entry inside an existing transaction.
+// The entry row is removed and its last state is kept as a 'deleted' revision.
+func (s *LogEntryService) trashLogEntryTx(ctx context.Context, qtx *store.Queries, userUUID, entryUUID uuid.UUID) error {
+	sqlcEntry, err := s.getOwnedLogEntry(ctx, qtx, userUUID, entryUUID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := s.withAssociations(ctx, qtx, sqlcEntry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.DeleteLogEntry(ctx, store.DeleteLogEntryParams{
+		ID:     entryUUID,
+		UserID: userUUID,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.recordRevisionTx(ctx, qtx, entry, models.RevisionDeleted); err != nil {
+		return err
+	}
+
+	if _, err := qtx.CreateLogEntryTrash(ctx, store.CreateLogEntryTrashParams{
+		LogEntryID: entryUUID,
+		UserID:     userUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to move log entry to trash: %w", err)
+	}
+
+	return nil
+}
+
+// recordRevisionTx appends the current state of a log entry to its history and signals the write,
+// publishing it to the user's webhooks
+func (s *LogEntryService) recordRevisionTx(ctx context.Context, qtx *store.Queries, entry *models.LogEntry, action models.RevisionAction) error {
+	tags := entry.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	links, err := linksToJSON(entry.Links)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.CreateLogEntryRevision(ctx, store.CreateLogEntryRevisionParams{
+		LogEntryID:  entry.ID,
+		UserID:      entry.UserID,
+		Action:      string(action),
+		ProjectID:   uuidToPgUUID(entry.ProjectID),
+		Title:       entry.Title,
+		Description: stringToPgText(entry.Description),
+		Type:        string(entry.Type),
+		StartTime:   timeToPgTimestamptz(entry.StartTime),
+		EndTime:     timeToPgTimestamptz(entry.EndTime),
+		ValueRating: string(entry.ValueRating),
+		ImpactLevel: string(entry.ImpactLevel),
+		Tags:        tags,
+		Links:       links,
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to record log entry revision", "log_entry_id", entry.ID, "action", action)
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+
+	// Every write records a revision, which makes this the one place to signal writes
+	if err := s.webhooks.publishTx(ctx, qtx, entry.UserID, logEntryWebhookEvent(action), entry); err != nil {
+		return err
+	}
+	if s.notifyWrite != nil {
+		s.notifyWrite()
+	}
+
+	return nil
+}
+
+// parseEntryIDs parses the user and log entry IDs of a request
+func (s *LogEntryService) parseEntryIDs(ctx context.Context, userID, logEntryID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	entryUUID, err := uuid.Parse(logEntryID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid log entry ID format", "user_id", userID, "log_entry_id", logEntryID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid log entry ID: %w", err)
+	}
+
+	return userUUID, entryUUID, nil
+}
+
+// revisionToModel converts a stored revision to its API model
+func revisionToModel(rev store.LogEntryRevision) *models.LogEntryRevision {
+	return &models.LogEntryRevision{
+		LogEntryID:       rev.LogEntryID,
+		Revision:         int(rev.Revision),
+		Action:           models.RevisionAction(rev.Action),
+		LogEntrySnapshot: revisionSnapshot(rev),
+		CreatedAt:        pgTimestamptzToTime(rev.CreatedAt),
+	}
+}
+
+// revisionSnapshot extracts the log entry content stored in a revision
+func revisionSnapshot(rev store.LogEntryRevision) models.LogEntrySnapshot {
+	startTime := pgTimestamptzToTime(rev.StartTime)
+	endTime := pgTimestamptzToTime(rev.EndTime)
+
+	tags := rev.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return models.LogEntrySnapshot{
+		Title:           rev.Title,
+		Description:     pgTextToString(rev.Description),
+		Type:            models.ActivityType(rev.Type),
+		ProjectID:       pgUUIDToUUID(rev.ProjectID),
+		StartTime:       startTime,
+		EndTime:         endTime,
+		DurationMinutes: int(endTime.Sub(startTime).Minutes()),
+		ValueRating:     models.ValueRating(rev.ValueRating),
+		ImpactLevel:     models.ImpactLevel(rev.ImpactLevel),
+		Tags:            tags,
+		Links:           linksFromJSON(rev.Links),
+	}
+}
@@ -0,0 +1,161 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogEntryService_HistoryAndTrash tests revisions, restoring revisions and the trash bin
+func TestLogEntryService_HistoryAndTrash(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "history@example.com",
+		Password:  "password123",
+		FirstName: "History",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	original := &models.LogEntryRequest{
+		Title:       "Fix flaky test",
+		Type:        models.ActivityDebugging,
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		ValueRating: models.ValueMedium,
+		ImpactLevel: models.ImpactTeam,
+		Tags:        []string{"ci"},
+	}
+
+	entry, err := logEntryService.CreateLogEntry(ctx, userID, original)
+	require.NoError(t, err)
+	entryID := entry.ID.String()
+
+	t.Run("UpdatesAppendRevisions", func(t *testing.T) {
+		edited := *original
+		edited.Title = "Fix flaky integration test"
+		edited.Tags = []string{"ci", "testing"}
+		_, err := logEntryService.UpdateLogEntry(ctx, userID, entryID, &edited)
+		require.NoError(t, err)
+
+		history, err := logEntryService.GetLogEntryHistory(ctx, userID, entryID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, 2, history[0].Revision)
+		assert.Equal(t, models.RevisionUpdated, history[0].Action)
+		assert.Equal(t, []string{"ci", "testing"}, history[0].Tags)
+		assert.Equal(t, models.RevisionCreated, history[1].Action)
+		assert.Equal(t, "Fix flaky test", history[1].Title)
+	})
+
+	t.Run("RestoreRevision", func(t *testing.T) {
+		restored, err := logEntryService.RestoreLogEntryRevision(ctx, userID, entryID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Fix flaky test", restored.Title)
+
+		current, err := logEntryService.GetLogEntry(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ci"}, current.Tags)
+
+		history, err := logEntryService.GetLogEntryHistory(ctx, userID, entryID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, models.RevisionRestored, history[0].Action)
+
+		_, err = logEntryService.RestoreLogEntryRevision(ctx, userID, entryID, 42)
+		assert.Error(t, err)
+	})
+
+	t.Run("DeleteMovesToTrash", func(t *testing.T) {
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, entryID))
+
+		_, err := logEntryService.GetLogEntry(ctx, userID, entryID)
+		assert.Error(t, err)
+
+		trash, err := logEntryService.GetTrash(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, entry.ID, trash[0].ID)
+		assert.Equal(t, "Fix flaky test", trash[0].Title)
+		assert.WithinDuration(t, trash[0].DeletedAt.Add(30*24*time.Hour), trash[0].PurgeAt, time.Second)
+
+		_, err = logEntryService.RestoreLogEntryRevision(ctx, userID, entryID, 1)
+		assert.Error(t, err, "trashed entries must be restored from the trash first")
+	})
+
+	t.Run("RestoreFromTrash", func(t *testing.T) {
+		restored, err := logEntryService.RestoreFromTrash(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Equal(t, entry.ID, restored.ID)
+		assert.Equal(t, []string{"ci"}, restored.Tags)
+
+		trash, err := logEntryService.GetTrash(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+
+		history, err := logEntryService.GetLogEntryHistory(ctx, userID, entryID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RevisionRestored, history[0].Action)
+		assert.Equal(t, models.RevisionDeleted, history[1].Action)
+	})
+
+	t.Run("OtherUsersCannotSeeHistory", func(t *testing.T) {
+		other, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     "other-history@example.com",
+			Password:  "password123",
+			FirstName: "Other",
+			LastName:  "User",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+
+		_, err = logEntryService.GetLogEntryHistory(ctx, other.ID.String(), entryID)
+		assert.Error(t, err)
+		_, err = logEntryService.RestoreFromTrash(ctx, other.ID.String(), entryID)
+		assert.Error(t, err)
+	})
+
+	t.Run("PurgeFromTrash", func(t *testing.T) {
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, entryID))
+		require.NoError(t, logEntryService.PurgeFromTrash(ctx, userID, entryID))
+
+		_, err := logEntryService.GetLogEntryHistory(ctx, userID, entryID)
+		assert.Error(t, err)
+		_, err = logEntryService.RestoreFromTrash(ctx, userID, entryID)
+		assert.Error(t, err)
+	})
+
+	t.Run("PurgeExpiredTrash", func(t *testing.T) {
+		other, err := logEntryService.CreateLogEntry(ctx, userID, original)
+		require.NoError(t, err)
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, other.ID.String()))
+
+		purged, err := logEntryService.PurgeExpiredTrash(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, purged, "entries inside the retention window are kept")
+
+		trash, err := logEntryService.GetTrash(ctx, userID)
+		require.NoError(t, err)
+		assert.Len(t, trash, 1)
+	})
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTrashRetention(t *testing.T) {
+	service := NewLogEntryService(nil, logging.NewTestLogger())
+	assert.Equal(t, defaultTrashRetentionDays*24*time.Hour, service.trashRetention)
+
+	service.WithTrashRetention(7)
+	assert.Equal(t, 7*24*time.Hour, service.trashRetention)
+
+	service.WithTrashRetention(0)
+	assert.Equal(t, 7*24*time.Hour, service.trashRetention, "non-positive values keep the current retention")
+}
+
+func TestRevisionToModel(t *testing.T) {
+	entryID := uuid.New()
+	projectID := uuid.New()
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	recorded := time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC)
+
+	revision := revisionToModel(store.LogEntryRevision{
+		LogEntryID:  entryID,
+		Revision:    3,
+		Action:      string(models.RevisionUpdated),
+		ProjectID:   pgtype.UUID{Bytes: projectID, Valid: true},
+		Title:       "Incident review",
+		Description: pgtype.Text{String: "Root cause analysis", Valid: true},
+		Type:        string(models.ActivityMeeting),
+		StartTime:   pgtype.Timestamptz{Time: start, Valid: true},
+		EndTime:     pgtype.Timestamptz{Time: start.Add(90 * time.Minute), Valid: true},
+		ValueRating: string(models.ValueHigh),
+		ImpactLevel: string(models.ImpactTeam),
+		CreatedAt:   pgtype.Timestamptz{Time: recorded, Valid: true},
+	})
+
+	assert.Equal(t, entryID, revision.LogEntryID)
+	assert.Equal(t, 3, revision.Revision)
+	assert.Equal(t, models.RevisionUpdated, revision.Action)
+	assert.Equal(t, "Incident review", revision.Title)
+	require.NotNil(t, revision.Description)
+	assert.Equal(t, "Root cause analysis", *revision.Description)
+	require.NotNil(t, revision.ProjectID)
+	assert.Equal(t, projectID, *revision.ProjectID)
+	assert.Equal(t, 90, revision.DurationMinutes)
+	assert.Equal(t, []string{}, revision.Tags, "a missing tag set is reported as empty")
+	assert.Equal(t, []models.LogEntryLink{}, revision.Links, "missing links are reported as empty")
+	assert.Equal(t, recorded, revision.CreatedAt)
+}
@@ -0,0 +1,1178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/recurrence"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// templateMaterializeInterval is how often the scheduler looks for due occurrences
+	templateMaterializeInterval = 15 * time.Minute
+	// templateMaxBackfillDays bounds how far back the scheduler, and instantiating
+	// every due occurrence, materializes
+	templateMaxBackfillDays = 31
+	// templateMinStartDate is the earliest start_date a template may have
+	templateMinStartDate = "2000-01-01"
+	// templateMaxPreviewDays bounds the occurrence preview window
+	templateMaxPreviewDays = 366
+)
+
+// LogTemplateService handles recurring log entry templates and their occurrences
+type LogTemplateService struct {
+	db         *database.DB
+	logger     *logging.Logger
+	logEntries *LogEntryService
+}
+
+// NewLogTemplateService creates a new LogTemplateService instance
+func NewLogTemplateService(db *database.DB, logger *logging.Logger, logEntryService *LogEntryService) *LogTemplateService {
+	return &LogTemplateService{
+		db:         db,
+		logger:     logger.WithComponent("log_template_service"),
+		logEntries: logEntryService,
+	}
+}
+
+// templateSchedule is the expanded form of a template's recurrence settings
+type templateSchedule struct {
+	rule     *recurrence.Rule
+	location *time.Location
+	dtstart  time.Time
+	until    *time.Time // exclusive upper bound derived from end_date
+	duration time.Duration
+}
+
+// CreateTemplate creates a new recurring log template
+func (s *LogTemplateService) CreateTemplate(ctx context.Context, userID string, req *models.LogTemplateRequest) (*models.LogTemplate, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateTemplate", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Creating log template", "user_id", userID, "title", req.Title, "recurrence_rule", req.RecurrenceRule)
+
+	var template *models.LogTemplate
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if req.Timezone == "" {
+			req.Timezone = s.userTimezone(ctx, qtx, userUUID)
+		}
+
+		params, err := s.templateParams(req)
+		if err != nil {
+			return err
+		}
+
+		sqlcTemplate, err := qtx.CreateLogTemplate(ctx, store.CreateLogTemplateParams{
+			UserID:          userUUID,
+			ProjectID:       params.ProjectID,
+			Title:           params.Title,
+			Description:     params.Description,
+			Type:            params.Type,
+			ValueRating:     params.ValueRating,
+			ImpactLevel:     params.ImpactLevel,
+			Tags:            params.Tags,
+			RecurrenceRule:  params.RecurrenceRule,
+			StartDate:       params.StartDate,
+			EndDate:         params.EndDate,
+			StartTime:       params.StartTime,
+			DurationMinutes: params.DurationMinutes,
+			Timezone:        params.Timezone,
+			IsActive:        params.IsActive,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create log template: %w", err)
+		}
+
+		template = s.sqlcToModel(sqlcTemplate)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create log template", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Log template created successfully", "user_id", userID, "template_id", template.ID)
+	return template, nil
+}
+
+// GetTemplate retrieves a single log template owned by the user
+func (s *LogTemplateService) GetTemplate(ctx context.Context, userID, templateID string) (*models.LogTemplate, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var template *models.LogTemplate
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcTemplate, err := s.getOwnedTemplate(ctx, qtx, userUUID, templateUUID)
+		if err != nil {
+			return err
+		}
+		template = s.sqlcToModel(sqlcTemplate)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get log template", "user_id", userID, "template_id", templateID)
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetTemplates retrieves all log templates for a user
+func (s *LogTemplateService) GetTemplates(ctx context.Context, userID string) ([]*models.LogTemplate, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTemplates", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var templates []*models.LogTemplate
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcTemplates, err := qtx.GetLogTemplatesByUser(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		templates = make([]*models.LogTemplate, len(sqlcTemplates))
+		for i, sqlcTemplate := range sqlcTemplates {
+			templates[i] = s.sqlcToModel(sqlcTemplate)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get log templates", "user_id", userID)
+		return nil, fmt.Errorf("failed to get log templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// UpdateTemplate replaces the settings of an existing log template.
+// Occurrences that were already materialized are left untouched.
+func (s *LogTemplateService) UpdateTemplate(ctx context.Context, userID, templateID string, req *models.LogTemplateRequest) (*models.LogTemplate, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Updating log template", "user_id", userID, "template_id", templateID)
+
+	var template *models.LogTemplate
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if req.Timezone == "" {
+			req.Timezone = s.userTimezone(ctx, qtx, userUUID)
+		}
+
+		params, err := s.templateParams(req)
+		if err != nil {
+			return err
+		}
+
+		sqlcTemplate, err := qtx.UpdateLogTemplate(ctx, store.UpdateLogTemplateParams{
+			ID:              templateUUID,
+			UserID:          userUUID,
+			ProjectID:       params.ProjectID,
+			Title:           params.Title,
+			Description:     params.Description,
+			Type:            params.Type,
+			ValueRating:     params.ValueRating,
+			ImpactLevel:     params.ImpactLevel,
+			Tags:            params.Tags,
+			RecurrenceRule:  params.RecurrenceRule,
+			StartDate:       params.StartDate,
+			EndDate:         params.EndDate,
+			StartTime:       params.StartTime,
+			DurationMinutes: params.DurationMinutes,
+			Timezone:        params.Timezone,
+			IsActive:        params.IsActive,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("log template not found")
+			}
+			return fmt.Errorf("failed to update log template: %w", err)
+		}
+
+		template = s.sqlcToModel(sqlcTemplate)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update log template", "user_id", userID, "template_id", templateID)
+		return nil, err
+	}
+
+	s.logger.Info("Log template updated successfully", "user_id", userID, "template_id", templateID)
+	return template, nil
+}
+
+// DeleteTemplate deletes a log template. Log entries it already produced are kept.
+func (s *LogTemplateService) DeleteTemplate(ctx context.Context, userID, templateID string) error {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteLogTemplate(ctx, store.DeleteLogTemplateParams{
+			ID:     templateUUID,
+			UserID: userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("log template not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete log template", "user_id", userID, "template_id", templateID)
+		return err
+	}
+
+	s.logger.Info("Log template deleted successfully", "user_id", userID, "template_id", templateID)
+	return nil
+}
+
+// GetOccurrences expands a template between two dates (YYYY-MM-DD, inclusive)
+// and merges in the stored per-occurrence state
+func (s *LogTemplateService) GetOccurrences(ctx context.Context, userID, templateID, startDate, endDate string) ([]*models.LogTemplateOccurrence, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if end.Sub(start) > templateMaxPreviewDays*24*time.Hour {
+		return nil, fmt.Errorf("date range cannot exceed %d days", templateMaxPreviewDays)
+	}
+
+	var occurrences []*models.LogTemplateOccurrence
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcTemplate, err := s.getOwnedTemplate(ctx, qtx, userUUID, templateUUID)
+		if err != nil {
+			return err
+		}
+
+		template := s.sqlcToModel(sqlcTemplate)
+		schedule, err := newTemplateSchedule(template)
+		if err != nil {
+			return err
+		}
+
+		stored, err := qtx.GetLogTemplateOccurrencesInRange(ctx, store.GetLogTemplateOccurrencesInRangeParams{
+			TemplateID:       templateUUID,
+			OccurrenceDate:   dateToPgDate(start),
+			OccurrenceDate_2: dateToPgDate(end),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get occurrences: %w", err)
+		}
+
+		byDate := make(map[string]store.LogTemplateOccurrence, len(stored))
+		for _, occ := range stored {
+			byDate[occ.OccurrenceDate.Time.Format(time.DateOnly)] = occ
+		}
+
+		from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, schedule.location)
+		to := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, schedule.location)
+
+		occurrences = []*models.LogTemplateOccurrence{}
+		for _, occStart := range schedule.between(from, to) {
+			date := occStart.Format(time.DateOnly)
+			occurrence := &models.LogTemplateOccurrence{
+				TemplateID: templateUUID,
+				Date:       date,
+				Status:     models.OccurrencePending,
+			}
+
+			var overrides *models.OccurrenceOverrides
+			if row, ok := byDate[date]; ok {
+				occurrence.Status = models.OccurrenceStatus(row.Status)
+				occurrence.LogEntryID = pgUUIDToUUID(row.LogEntryID)
+				overrides, err = decodeOverrides(row.Overrides)
+				if err != nil {
+					return err
+				}
+				occurrence.Overrides = overrides
+			}
+
+			occurrence.StartTime, occurrence.EndTime, err = schedule.window(occStart, overrides)
+			if err != nil {
+				return err
+			}
+			occurrences = append(occurrences, occurrence)
+		}
+
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get template occurrences", "user_id", userID, "template_id", templateID)
+		return nil, err
+	}
+
+	return occurrences, nil
+}
+
+// Instantiate materializes template occurrences into log entries.
+// With an empty date every due occurrence (ended before now) of the last
+// templateMaxBackfillDays days is created; otherwise only the occurrence on
+// that date.
+func (s *LogTemplateService) Instantiate(ctx context.Context, userID, templateID, date string) ([]*models.LogEntry, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sqlcTemplate store.LogTemplate
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcTemplate, err = s.getOwnedTemplate(ctx, qtx, userUUID, templateUUID)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to load log template for instantiation", "user_id", userID, "template_id", templateID)
+		return nil, err
+	}
+
+	template := s.sqlcToModel(sqlcTemplate)
+	schedule, err := newTemplateSchedule(template)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Instantiating log template", "user_id", userID, "template_id", templateID, "date", date)
+
+	if date == "" {
+		now := time.Now()
+		from := laterOf(schedule.dtstart, now.AddDate(0, 0, -templateMaxBackfillDays).In(schedule.location))
+		entries, _, err := s.materializeDue(ctx, template, schedule, from, now)
+		return entries, err
+	}
+
+	occStart, err := schedule.occurrenceOn(date)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.materializeOccurrence(ctx, template, schedule, occStart, true)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to instantiate occurrence", "user_id", userID, "template_id", templateID, "date", date)
+		return nil, err
+	}
+
+	return []*models.LogEntry{entry}, nil
+}
+
+// SkipOccurrence marks a single occurrence as skipped. If it was already
+// materialized, the generated log entry is deleted.
+func (s *LogTemplateService) SkipOccurrence(ctx context.Context, userID, templateID, date string) (*models.LogTemplateOccurrence, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrence *models.LogTemplateOccurrence
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		template, schedule, occStart, err := s.loadOccurrence(ctx, qtx, userUUID, templateUUID, date)
+		if err != nil {
+			return err
+		}
+
+		existing, err := s.getStoredOccurrence(ctx, qtx, template.ID, occStart)
+		if err != nil {
+			return err
+		}
+
+		overrides := []byte("{}")
+		if existing != nil {
+			overrides = existing.Overrides
+			if existing.LogEntryID.Valid {
+				if err := s.logEntries.trashLogEntryTx(ctx, qtx, userUUID, uuid.UUID(existing.LogEntryID.Bytes)); err != nil {
+					return fmt.Errorf("failed to delete materialized log entry: %w", err)
+				}
+			}
+		}
+
+		row, err := qtx.UpsertLogTemplateOccurrence(ctx, store.UpsertLogTemplateOccurrenceParams{
+			TemplateID:     template.ID,
+			OccurrenceDate: occurrenceDate(occStart),
+			Status:         string(models.OccurrenceSkipped),
+			Overrides:      overrides,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to skip occurrence: %w", err)
+		}
+
+		occurrence, err = s.occurrenceToModel(schedule, occStart, row)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to skip occurrence", "user_id", userID, "template_id", templateID, "date", date)
+		return nil, err
+	}
+
+	s.logger.Info("Template occurrence skipped", "user_id", userID, "template_id", templateID, "date", date)
+	return occurrence, nil
+}
+
+// EditOccurrence stores overrides for a single occurrence. If the occurrence
+// was already materialized, its log entry is updated in place.
+func (s *LogTemplateService) EditOccurrence(ctx context.Context, userID, templateID, date string, overrides *models.OccurrenceOverrides) (*models.LogTemplateOccurrence, error) {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOverrides(overrides); err != nil {
+		return nil, err
+	}
+
+	overridesBytes, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode overrides: %w", err)
+	}
+
+	var occurrence *models.LogTemplateOccurrence
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		template, schedule, occStart, err := s.loadOccurrence(ctx, qtx, userUUID, templateUUID, date)
+		if err != nil {
+			return err
+		}
+
+		existing, err := s.getStoredOccurrence(ctx, qtx, template.ID, occStart)
+		if err != nil {
+			return err
+		}
+
+		status := models.OccurrencePending
+		var logEntryID pgtype.UUID
+		if existing != nil {
+			status = models.OccurrenceStatus(existing.Status)
+			logEntryID = existing.LogEntryID
+		}
+
+		if logEntryID.Valid {
+			req, err := schedule.entryRequest(template, occStart, overrides)
+			if err != nil {
+				return err
+			}
+			if err := s.logEntries.validateLogEntryRequest(req); err != nil {
+				return err
+			}
+
+			sqlcEntry, err := qtx.UpdateLogEntry(ctx, store.UpdateLogEntryParams{
+				ID:          uuid.UUID(logEntryID.Bytes),
+				Title:       req.Title,
+				Description: stringToPgText(req.Description),
+				Type:        string(req.Type),
+				ProjectID:   uuidToPgUUID(req.ProjectID),
+				StartTime:   timeToPgTimestamptz(req.StartTime),
+				EndTime:     timeToPgTimestamptz(req.EndTime),
+				ValueRating: string(req.ValueRating),
+				ImpactLevel: string(req.ImpactLevel),
+				UserID:      userUUID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update materialized log entry: %w", err)
+			}
+
+			if _, err := s.logEntries.replaceLogEntryTags(ctx, qtx, userUUID, uuid.UUID(logEntryID.Bytes), req.Tags); err != nil {
+				return err
+			}
+
+			entry, err := s.logEntries.withAssociations(ctx, qtx, sqlcEntry)
+			if err != nil {
+				return err
+			}
+			if err := s.logEntries.recordRevisionTx(ctx, qtx, entry, models.RevisionUpdated); err != nil {
+				return err
+			}
+		}
+
+		row, err := qtx.UpsertLogTemplateOccurrence(ctx, store.UpsertLogTemplateOccurrenceParams{
+			TemplateID:     template.ID,
+			OccurrenceDate: occurrenceDate(occStart),
+			Status:         string(status),
+			LogEntryID:     logEntryID,
+			Overrides:      overridesBytes,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save occurrence overrides: %w", err)
+		}
+
+		occurrence, err = s.occurrenceToModel(schedule, occStart, row)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to edit occurrence", "user_id", userID, "template_id", templateID, "date", date)
+		return nil, err
+	}
+
+	s.logger.Info("Template occurrence edited", "user_id", userID, "template_id", templateID, "date", date)
+	return occurrence, nil
+}
+
+// ResetOccurrence clears a skip or pending overrides for a single occurrence.
+// Materialized occurrences cannot be reset; edit or delete the log entry instead.
+func (s *LogTemplateService) ResetOccurrence(ctx context.Context, userID, templateID, date string) error {
+	userUUID, templateUUID, err := s.parseIDs(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		template, _, occStart, err := s.loadOccurrence(ctx, qtx, userUUID, templateUUID, date)
+		if err != nil {
+			return err
+		}
+
+		existing, err := s.getStoredOccurrence(ctx, qtx, template.ID, occStart)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return nil
+		}
+		if existing.Status == string(models.OccurrenceMaterialized) {
+			return fmt.Errorf("occurrence already materialized")
+		}
+
+		return qtx.DeleteLogTemplateOccurrence(ctx, store.DeleteLogTemplateOccurrenceParams{
+			TemplateID:     template.ID,
+			OccurrenceDate: occurrenceDate(occStart),
+		})
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to reset occurrence", "user_id", userID, "template_id", templateID, "date", date)
+		return err
+	}
+
+	return nil
+}
+
+// MaterializeDue creates log entries for every active template occurrence that
+// has finished since the template was last processed. It returns the number of
+// log entries created.
+func (s *LogTemplateService) MaterializeDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	var sqlcTemplates []store.LogTemplate
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		sqlcTemplates, err = qtx.GetActiveLogTemplates(ctx, dateToPgDate(now.AddDate(0, 0, -templateMaxBackfillDays)))
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to get active log templates: %w", err)
+	}
+
+	created := 0
+	for _, sqlcTemplate := range sqlcTemplates {
+		template := s.sqlcToModel(sqlcTemplate)
+		schedule, err := newTemplateSchedule(template)
+		if err != nil {
+			s.logger.LogError(ctx, err, "Skipping log template with invalid schedule", "template_id", template.ID)
+			continue
+		}
+
+		from := schedule.dtstart
+		if template.MaterializedUntil != nil {
+			if until, err := time.ParseInLocation(time.DateOnly, *template.MaterializedUntil, schedule.location); err == nil {
+				from = laterOf(from, until.AddDate(0, 0, 1))
+			}
+		}
+		from = laterOf(from, now.AddDate(0, 0, -templateMaxBackfillDays).In(schedule.location))
+
+		entries, until, err := s.materializeDue(ctx, template, schedule, from, now)
+		created += len(entries)
+		if err != nil {
+			s.logger.LogError(ctx, err, "Failed to materialize log template", "template_id", template.ID)
+			continue
+		}
+
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			return qtx.SetLogTemplateMaterializedUntil(ctx, store.SetLogTemplateMaterializedUntilParams{
+				ID:                template.ID,
+				MaterializedUntil: dateToPgDate(until),
+			})
+		}); err != nil {
+			s.logger.LogError(ctx, err, "Failed to record template progress", "template_id", template.ID)
+		}
+	}
+
+	return created, nil
+}
+
+// StartMaterializer periodically materializes due template occurrences until ctx is cancelled
+func (s *LogTemplateService) StartMaterializer(ctx context.Context) {
+	ticker := time.NewTicker(templateMaterializeInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Log template materializer started", "interval", templateMaterializeInterval.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Log template materializer stopped")
+			return
+		case <-ticker.C:
+			created, err := s.MaterializeDue(ctx)
+			if err != nil {
+				s.logger.LogError(ctx, err, "Failed to materialize log templates")
+			} else if created > 0 {
+				s.logger.Info("Log template occurrences materialized", "entries_created", created)
+			}
+		}
+	}
+}
+
+// materializeDue creates entries for occurrences in [from, now) that have
+// already ended. It returns the created entries and the last local date whose
+// occurrences are all settled.
+func (s *LogTemplateService) materializeDue(ctx context.Context, template *models.LogTemplate, schedule *templateSchedule, from, now time.Time) ([]*models.LogEntry, time.Time, error) {
+	cutoff := now.Add(-schedule.duration).In(schedule.location)
+	settled := time.Date(cutoff.Year(), cutoff.Month(), cutoff.Day()-1, 0, 0, 0, 0, time.UTC)
+
+	entries := []*models.LogEntry{}
+	for _, occStart := range schedule.between(from, cutoff.Add(time.Nanosecond)) {
+		entry, err := s.materializeOccurrence(ctx, template, schedule, occStart, false)
+		if err != nil {
+			return entries, settled, err
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, settled, nil
+}
+
+// materializeOccurrence turns one occurrence into a log entry. Skipped or
+// already materialized occurrences return nil, or an error when explicit is set.
+func (s *LogTemplateService) materializeOccurrence(ctx context.Context, template *models.LogTemplate, schedule *templateSchedule, occStart time.Time, explicit bool) (*models.LogEntry, error) {
+	var entry *models.LogEntry
+
+	err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		entry = nil
+
+		existing, err := s.getStoredOccurrence(ctx, qtx, template.ID, occStart)
+		if err != nil {
+			return err
+		}
+
+		var overrides *models.OccurrenceOverrides
+		overridesBytes := []byte("{}")
+		if existing != nil {
+			switch models.OccurrenceStatus(existing.Status) {
+			case models.OccurrenceSkipped:
+				if explicit {
+					return fmt.Errorf("occurrence was skipped")
+				}
+				return nil
+			case models.OccurrenceMaterialized:
+				if explicit {
+					return fmt.Errorf("occurrence already materialized")
+				}
+				return nil
+			}
+
+			overridesBytes = existing.Overrides
+			overrides, err = decodeOverrides(existing.Overrides)
+			if err != nil {
+				return err
+			}
+		}
+
+		req, err := schedule.entryRequest(template, occStart, overrides)
+		if err != nil {
+			return err
+		}
+		if err := s.logEntries.validateLogEntryRequest(req); err != nil {
+			return err
+		}
+
+		entry, err = s.logEntries.createLogEntryTx(ctx, qtx, template.UserID, req)
+		if err != nil {
+			return err
+		}
+
+		_, err = qtx.UpsertLogTemplateOccurrence(ctx, store.UpsertLogTemplateOccurrenceParams{
+			TemplateID:     template.ID,
+			OccurrenceDate: occurrenceDate(occStart),
+			Status:         string(models.OccurrenceMaterialized),
+			LogEntryID:     uuidToPgUUID(&entry.ID),
+			Overrides:      overridesBytes,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// loadOccurrence loads an owned template and resolves the occurrence on date
+func (s *LogTemplateService) loadOccurrence(ctx context.Context, qtx *store.Queries, userUUID, templateUUID uuid.UUID, date string) (*models.LogTemplate, *templateSchedule, time.Time, error) {
+	sqlcTemplate, err := s.getOwnedTemplate(ctx, qtx, userUUID, templateUUID)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	template := s.sqlcToModel(sqlcTemplate)
+	schedule, err := newTemplateSchedule(template)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	occStart, err := schedule.occurrenceOn(date)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	return template, schedule, occStart, nil
+}
+
+// getOwnedTemplate fetches a template and hides templates of other users
+func (s *LogTemplateService) getOwnedTemplate(ctx context.Context, qtx *store.Queries, userUUID, templateUUID uuid.UUID) (store.LogTemplate, error) {
+	sqlcTemplate, err := qtx.GetLogTemplateByID(ctx, store.GetLogTemplateByIDParams{
+		ID:     templateUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return store.LogTemplate{}, fmt.Errorf("log template not found")
+		}
+		return store.LogTemplate{}, fmt.Errorf("failed to get log template: %w", err)
+	}
+	return sqlcTemplate, nil
+}
+
+// getStoredOccurrence returns the stored occurrence state, or nil if there is none
+func (s *LogTemplateService) getStoredOccurrence(ctx context.Context, qtx *store.Queries, templateUUID uuid.UUID, occStart time.Time) (*store.LogTemplateOccurrence, error) {
+	row, err := qtx.GetLogTemplateOccurrence(ctx, store.GetLogTemplateOccurrenceParams{
+		TemplateID:     templateUUID,
+		OccurrenceDate: occurrenceDate(occStart),
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get occurrence: %w", err)
+	}
+	return &row, nil
+}
+
+// userTimezone returns the user's configured timezone, falling back to UTC
+func (s *LogTemplateService) userTimezone(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID) string {
+	user, err := qtx.GetUserByID(ctx, userUUID)
+	if err != nil || !user.Timezone.Valid || user.Timezone.String == "" {
+		return "UTC"
+	}
+	return user.Timezone.String
+}
+
+func (s *LogTemplateService) parseIDs(ctx context.Context, userID, templateID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	templateUUID, err := uuid.Parse(templateID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid log template ID format", "user_id", userID, "template_id", templateID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid log template ID: %w", err)
+	}
+
+	return userUUID, templateUUID, nil
+}
+
+// templateParams validates a request and converts it to column values
+func (s *LogTemplateService) templateParams(req *models.LogTemplateRequest) (store.UpdateLogTemplateParams, error) {
+	if err := s.validateTemplateRequest(req); err != nil {
+		return store.UpdateLogTemplateParams{}, err
+	}
+
+	rule, _ := recurrence.Parse(req.RecurrenceRule)
+	startDate, _ := time.Parse(time.DateOnly, req.StartDate)
+	hour, minute, _ := parseTimeOfDay(req.StartTime)
+
+	var endDate pgtype.Date
+	if req.EndDate != nil && *req.EndDate != "" {
+		end, _ := time.Parse(time.DateOnly, *req.EndDate)
+		endDate = dateToPgDate(end)
+	}
+
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	return store.UpdateLogTemplateParams{
+		ProjectID:       uuidToPgUUID(req.ProjectID),
+		Title:           strings.TrimSpace(req.Title),
+		Description:     stringToPgText(req.Description),
+		Type:            string(req.Type),
+		ValueRating:     string(req.ValueRating),
+		ImpactLevel:     string(req.ImpactLevel),
+		Tags:            tags,
+		RecurrenceRule:  rule.String(),
+		StartDate:       dateToPgDate(startDate),
+		EndDate:         endDate,
+		StartTime:       pgtype.Time{Microseconds: int64(hour*60+minute) * int64(time.Minute/time.Microsecond), Valid: true},
+		DurationMinutes: int32(req.DurationMinutes),
+		Timezone:        req.Timezone,
+		IsActive:        isActive,
+	}, nil
+}
+
+// validateTemplateRequest validates a log template request. The entry a
+// template produces is checked with the same rules as a hand-written entry.
+func (s *LogTemplateService) validateTemplateRequest(req *models.LogTemplateRequest) error {
+	if _, err := recurrence.Parse(req.RecurrenceRule); err != nil {
+		return fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	startDate, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date format, expected YYYY-MM-DD")
+	}
+	if req.StartDate < templateMinStartDate {
+		return fmt.Errorf("start_date must be on or after %s", templateMinStartDate)
+	}
+
+	if req.EndDate != nil && *req.EndDate != "" {
+		endDate, err := time.Parse(time.DateOnly, *req.EndDate)
+		if err != nil {
+			return fmt.Errorf("invalid end_date format, expected YYYY-MM-DD")
+		}
+		if endDate.Before(startDate) {
+			return fmt.Errorf("end_date must be on or after start_date")
+		}
+	}
+
+	if _, _, err := parseTimeOfDay(req.StartTime); err != nil {
+		return err
+	}
+
+	if req.DurationMinutes <= 0 || req.DurationMinutes > 1440 {
+		return fmt.Errorf("duration_minutes must be between 1 and 1440")
+	}
+
+	if req.Timezone != "" {
+		if err := models.ValidateTimezone(req.Timezone); err != nil {
+			return err
+		}
+	}
+
+	sample := &models.LogEntryRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Type:        req.Type,
+		ProjectID:   req.ProjectID,
+		StartTime:   startDate,
+		EndTime:     startDate.Add(time.Duration(req.DurationMinutes) * time.Minute),
+		ValueRating: req.ValueRating,
+		ImpactLevel: req.ImpactLevel,
+		Tags:        req.Tags,
+	}
+	return s.logEntries.validateLogEntryRequest(sample)
+}
+
+// sqlcToModel converts SQLC LogTemplate to models.LogTemplate
+func (s *LogTemplateService) sqlcToModel(sqlcTemplate store.LogTemplate) *models.LogTemplate {
+	template := &models.LogTemplate{
+		ID:              sqlcTemplate.ID,
+		UserID:          sqlcTemplate.UserID,
+		ProjectID:       pgUUIDToUUID(sqlcTemplate.ProjectID),
+		Title:           sqlcTemplate.Title,
+		Description:     pgTextToString(sqlcTemplate.Description),
+		Type:            models.ActivityType(sqlcTemplate.Type),
+		ValueRating:     models.ValueRating(sqlcTemplate.ValueRating),
+		ImpactLevel:     models.ImpactLevel(sqlcTemplate.ImpactLevel),
+		Tags:            sqlcTemplate.Tags,
+		RecurrenceRule:  sqlcTemplate.RecurrenceRule,
+		StartDate:       sqlcTemplate.StartDate.Time.Format(time.DateOnly),
+		DurationMinutes: int(sqlcTemplate.DurationMinutes),
+		Timezone:        sqlcTemplate.Timezone,
+		IsActive:        sqlcTemplate.IsActive,
+		CreatedAt:       pgTimestamptzToTime(sqlcTemplate.CreatedAt),
+		UpdatedAt:       pgTimestamptzToTime(sqlcTemplate.UpdatedAt),
+	}
+
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+
+	minutes := sqlcTemplate.StartTime.Microseconds / int64(time.Minute/time.Microsecond)
+	template.StartTime = fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+
+	if sqlcTemplate.EndDate.Valid {
+		endDate := sqlcTemplate.EndDate.Time.Format(time.DateOnly)
+		template.EndDate = &endDate
+	}
+	if sqlcTemplate.MaterializedUntil.Valid {
+		until := sqlcTemplate.MaterializedUntil.Time.Format(time.DateOnly)
+		template.MaterializedUntil = &until
+	}
+
+	return template
+}
+
+func (s *LogTemplateService) occurrenceToModel(schedule *templateSchedule, occStart time.Time, row store.LogTemplateOccurrence) (*models.LogTemplateOccurrence, error) {
+	overrides, err := decodeOverrides(row.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime, endTime, err := schedule.window(occStart, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LogTemplateOccurrence{
+		TemplateID: row.TemplateID,
+		Date:       occStart.Format(time.DateOnly),
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Status:     models.OccurrenceStatus(row.Status),
+		LogEntryID: pgUUIDToUUID(row.LogEntryID),
+		Overrides:  overrides,
+	}, nil
+}
+
+// newTemplateSchedule builds the recurrence schedule of a template
+func newTemplateSchedule(template *models.LogTemplate) (*templateSchedule, error) {
+	rule, err := recurrence.Parse(template.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	location, err := time.LoadLocation(template.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template timezone: %w", err)
+	}
+
+	startDate, err := time.Parse(time.DateOnly, template.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template start date: %w", err)
+	}
+
+	hour, minute, err := parseTimeOfDay(template.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &templateSchedule{
+		rule:     rule,
+		location: location,
+		dtstart:  time.Date(startDate.Year(), startDate.Month(), startDate.Day(), hour, minute, 0, 0, location),
+		duration: time.Duration(template.DurationMinutes) * time.Minute,
+	}
+
+	if template.EndDate != nil {
+		endDate, err := time.Parse(time.DateOnly, *template.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template end date: %w", err)
+		}
+		until := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, 0, location)
+		schedule.until = &until
+	}
+
+	return schedule, nil
+}
+
+// between returns occurrence start times in [from, to), honoring the template end date
+func (sch *templateSchedule) between(from, to time.Time) []time.Time {
+	if sch.until != nil && sch.until.Before(to) {
+		to = *sch.until
+	}
+	if !from.Before(to) {
+		return nil
+	}
+	return sch.rule.Between(sch.dtstart, from, to)
+}
+
+// occurrenceOn returns the start of the occurrence on a local date (YYYY-MM-DD)
+func (sch *templateSchedule) occurrenceOn(date string) (time.Time, error) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+	}
+
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, sch.location)
+	occurrences := sch.between(from, from.AddDate(0, 0, 1))
+	if len(occurrences) == 0 {
+		return time.Time{}, fmt.Errorf("template has no occurrence on %s", date)
+	}
+	return occurrences[0], nil
+}
+
+// window returns the start and end time of an occurrence after overrides
+func (sch *templateSchedule) window(occStart time.Time, overrides *models.OccurrenceOverrides) (time.Time, time.Time, error) {
+	start := occStart
+	duration := sch.duration
+
+	if overrides != nil {
+		if overrides.StartTime != nil {
+			hour, minute, err := parseTimeOfDay(*overrides.StartTime)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			start = time.Date(occStart.Year(), occStart.Month(), occStart.Day(), hour, minute, 0, 0, sch.location)
+		}
+		if overrides.DurationMinutes != nil {
+			duration = time.Duration(*overrides.DurationMinutes) * time.Minute
+		}
+	}
+
+	return start, start.Add(duration), nil
+}
+
+// entryRequest builds the log entry for an occurrence from the template and its overrides
+func (sch *templateSchedule) entryRequest(template *models.LogTemplate, occStart time.Time, overrides *models.OccurrenceOverrides) (*models.LogEntryRequest, error) {
+	start, end, err := sch.window(occStart, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &models.LogEntryRequest{
+		Title:       template.Title,
+		Description: template.Description,
+		Type:        template.Type,
+		ProjectID:   template.ProjectID,
+		StartTime:   start.UTC(),
+		EndTime:     end.UTC(),
+		ValueRating: template.ValueRating,
+		ImpactLevel: template.ImpactLevel,
+		Tags:        template.Tags,
+	}
+
+	if overrides != nil {
+		if overrides.Title != nil {
+			req.Title = *overrides.Title
+		}
+		if overrides.Description != nil {
+			req.Description = overrides.Description
+		}
+		if overrides.Type != nil {
+			req.Type = *overrides.Type
+		}
+		if overrides.ProjectID != nil {
+			req.ProjectID = overrides.ProjectID
+		}
+		if overrides.ValueRating != nil {
+			req.ValueRating = *overrides.ValueRating
+		}
+		if overrides.ImpactLevel != nil {
+			req.ImpactLevel = *overrides.ImpactLevel
+		}
+		if overrides.Tags != nil {
+			req.Tags = overrides.Tags
+		}
+	}
+
+	return req, nil
+}
+
+// validateOverrides checks the enum and time fields of occurrence overrides
+func validateOverrides(overrides *models.OccurrenceOverrides) error {
+	if overrides == nil {
+		return fmt.Errorf("overrides are required")
+	}
+	if overrides.Type != nil && !overrides.Type.IsValid() {
+		return fmt.Errorf("invalid activity type: %s", *overrides.Type)
+	}
+	if overrides.ValueRating != nil && !overrides.ValueRating.IsValid() {
+		return fmt.Errorf("invalid value rating: %s", *overrides.ValueRating)
+	}
+	if overrides.ImpactLevel != nil && !overrides.ImpactLevel.IsValid() {
+		return fmt.Errorf("invalid impact level: %s", *overrides.ImpactLevel)
+	}
+	if overrides.StartTime != nil {
+		if _, _, err := parseTimeOfDay(*overrides.StartTime); err != nil {
+			return err
+		}
+	}
+	if overrides.DurationMinutes != nil && (*overrides.DurationMinutes <= 0 || *overrides.DurationMinutes > 1440) {
+		return fmt.Errorf("duration_minutes must be between 1 and 1440")
+	}
+	return nil
+}
+
+func decodeOverrides(data []byte) (*models.OccurrenceOverrides, error) {
+	if len(data) == 0 || string(data) == "{}" {
+		return nil, nil
+	}
+
+	var overrides models.OccurrenceOverrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode occurrence overrides: %w", err)
+	}
+	return &overrides, nil
+}
+
+// parseTimeOfDay parses a local wall clock time in HH:MM format
+func parseTimeOfDay(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start_time format, expected HH:MM")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD date range
+func parseDateRange(startDate, endDate string) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date format, expected YYYY-MM-DD")
+	}
+
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date format, expected YYYY-MM-DD")
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date must be on or after start_date")
+	}
+
+	return start, end, nil
+}
+
+// occurrenceDate converts an occurrence start to its local calendar date
+func occurrenceDate(occStart time.Time) pgtype.Date {
+	return dateToPgDate(occStart)
+}
+
+// dateToPgDate converts the calendar date of t (in its own location) to pgtype.Date
+func dateToPgDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
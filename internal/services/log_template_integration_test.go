@@ -0,0 +1,191 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogTemplateService_Lifecycle tests template CRUD, instantiation and per-occurrence edits
+func TestLogTemplateService_Lifecycle(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	logTemplateService := services.NewLogTemplateService(db, testLogger, logEntryService)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "templates@example.com",
+		Password:  "password123",
+		FirstName: "Template",
+		LastName:  "User",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	startDate := time.Now().AddDate(0, 0, -6).Format(time.DateOnly)
+	template, err := logTemplateService.CreateTemplate(ctx, userID, &models.LogTemplateRequest{
+		Title:           "Daily standup",
+		Type:            models.ActivityMeeting,
+		ValueRating:     models.ValueMedium,
+		ImpactLevel:     models.ImpactTeam,
+		Tags:            []string{"standup"},
+		RecurrenceRule:  "FREQ=DAILY",
+		StartDate:       startDate,
+		StartTime:       "09:00",
+		DurationMinutes: 15,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", template.Timezone, "timezone defaults to the user's timezone")
+
+	templateID := template.ID.String()
+	firstDay := startDate
+	secondDay := time.Now().AddDate(0, 0, -5).Format(time.DateOnly)
+	thirdDay := time.Now().AddDate(0, 0, -4).Format(time.DateOnly)
+
+	t.Run("ListAndGet", func(t *testing.T) {
+		templates, err := logTemplateService.GetTemplates(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+
+		fetched, err := logTemplateService.GetTemplate(ctx, userID, templateID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=DAILY", fetched.RecurrenceRule)
+		assert.Equal(t, "09:00", fetched.StartTime)
+	})
+
+	t.Run("InstantiateSingleDate", func(t *testing.T) {
+		entries, err := logTemplateService.Instantiate(ctx, userID, templateID, firstDay)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Daily standup", entries[0].Title)
+		assert.Equal(t, 15, entries[0].DurationMinutes)
+
+		_, err = logTemplateService.Instantiate(ctx, userID, templateID, firstDay)
+		assert.Error(t, err, "an occurrence is only materialized once")
+	})
+
+	t.Run("SkipAndEditBeforeMaterializing", func(t *testing.T) {
+		occurrence, err := logTemplateService.SkipOccurrence(ctx, userID, templateID, secondDay)
+		require.NoError(t, err)
+		assert.Equal(t, models.OccurrenceSkipped, occurrence.Status)
+
+		duration := 45
+		occurrence, err = logTemplateService.EditOccurrence(ctx, userID, templateID, thirdDay, &models.OccurrenceOverrides{
+			Title:           stringPtr("Sprint planning instead"),
+			DurationMinutes: &duration,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.OccurrencePending, occurrence.Status)
+		assert.Equal(t, 45*time.Minute, occurrence.EndTime.Sub(occurrence.StartTime))
+	})
+
+	t.Run("InstantiateAllDue", func(t *testing.T) {
+		entries, err := logTemplateService.Instantiate(ctx, userID, templateID, "")
+		require.NoError(t, err)
+		// Six days back plus possibly today; day one already exists and day two is skipped
+		assert.GreaterOrEqual(t, len(entries), 4)
+
+		var edited *models.LogEntry
+		for _, entry := range entries {
+			if entry.Title == "Sprint planning instead" {
+				edited = entry
+			}
+		}
+		require.NotNil(t, edited)
+		assert.Equal(t, 45, edited.DurationMinutes)
+
+		occurrences, err := logTemplateService.GetOccurrences(ctx, userID, templateID, firstDay, thirdDay)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 3)
+		assert.Equal(t, models.OccurrenceMaterialized, occurrences[0].Status)
+		assert.Equal(t, models.OccurrenceSkipped, occurrences[1].Status)
+		assert.Equal(t, models.OccurrenceMaterialized, occurrences[2].Status)
+	})
+
+	t.Run("EditMaterializedOccurrenceUpdatesEntry", func(t *testing.T) {
+		occurrence, err := logTemplateService.EditOccurrence(ctx, userID, templateID, firstDay, &models.OccurrenceOverrides{
+			Title: stringPtr("Standup (ran long)"),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, occurrence.LogEntryID)
+
+		entry, err := logEntryService.GetLogEntry(ctx, userID, occurrence.LogEntryID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "Standup (ran long)", entry.Title)
+	})
+
+	t.Run("SkipMaterializedOccurrenceDeletesEntry", func(t *testing.T) {
+		occurrences, err := logTemplateService.GetOccurrences(ctx, userID, templateID, thirdDay, thirdDay)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 1)
+		require.NotNil(t, occurrences[0].LogEntryID)
+		entryID := occurrences[0].LogEntryID.String()
+
+		_, err = logTemplateService.SkipOccurrence(ctx, userID, templateID, thirdDay)
+		require.NoError(t, err)
+
+		_, err = logEntryService.GetLogEntry(ctx, userID, entryID)
+		assert.Error(t, err)
+	})
+
+	t.Run("OtherUsersCannotAccess", func(t *testing.T) {
+		other, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     "other-templates@example.com",
+			Password:  "password123",
+			FirstName: "Other",
+			LastName:  "User",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+
+		_, err = logTemplateService.GetTemplate(ctx, other.ID.String(), templateID)
+		assert.Error(t, err)
+		_, err = logTemplateService.Instantiate(ctx, other.ID.String(), templateID, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("InstantiateAllDueBoundsBackfill", func(t *testing.T) {
+		old, err := logTemplateService.CreateTemplate(ctx, userID, &models.LogTemplateRequest{
+			Title:           "Daily check-in",
+			Type:            models.ActivityMeeting,
+			ValueRating:     models.ValueLow,
+			ImpactLevel:     models.ImpactPersonal,
+			RecurrenceRule:  "FREQ=DAILY",
+			StartDate:       time.Now().AddDate(-3, 0, 0).Format(time.DateOnly),
+			StartTime:       "08:00",
+			DurationMinutes: 10,
+		})
+		require.NoError(t, err)
+
+		entries, err := logTemplateService.Instantiate(ctx, userID, old.ID.String(), "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, entries)
+		assert.LessOrEqual(t, len(entries), 32, "only the last 31 days are backfilled")
+		for _, entry := range entries {
+			assert.True(t, entry.StartTime.After(time.Now().AddDate(0, 0, -32)))
+		}
+
+		require.NoError(t, logTemplateService.DeleteTemplate(ctx, userID, old.ID.String()))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, logTemplateService.DeleteTemplate(ctx, userID, templateID))
+		_, err := logTemplateService.GetTemplate(ctx, userID, templateID)
+		assert.Error(t, err)
+	})
+}
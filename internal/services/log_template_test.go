@@ -0,0 +1,208 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogTemplateService() *LogTemplateService {
+	logger := logging.NewTestLogger()
+	return NewLogTemplateService(nil, logger, NewLogEntryService(nil, logger))
+}
+
+func validTemplateRequest() *models.LogTemplateRequest {
+	return &models.LogTemplateRequest{
+		Title:           "Daily standup",
+		Type:            models.ActivityMeeting,
+		ValueRating:     models.ValueMedium,
+		ImpactLevel:     models.ImpactTeam,
+		Tags:            []string{"standup"},
+		RecurrenceRule:  "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		StartDate:       "2025-01-06",
+		StartTime:       "09:30",
+		DurationMinutes: 15,
+		Timezone:        "America/Sao_Paulo",
+	}
+}
+
+func TestLogTemplateService_ValidateTemplateRequest(t *testing.T) {
+	service := newTestLogTemplateService()
+
+	tests := []struct {
+		name    string
+		modify  func(req *models.LogTemplateRequest)
+		wantErr string
+	}{
+		{name: "valid request", modify: func(req *models.LogTemplateRequest) {}},
+		{name: "invalid rule", modify: func(req *models.LogTemplateRequest) { req.RecurrenceRule = "FREQ=SOMETIMES" }, wantErr: "invalid recurrence rule"},
+		{name: "invalid start date", modify: func(req *models.LogTemplateRequest) { req.StartDate = "06/01/2025" }, wantErr: "invalid start_date"},
+		{name: "start date too early", modify: func(req *models.LogTemplateRequest) { req.StartDate = "1970-01-01" }, wantErr: "start_date must be on or after 2000-01-01"},
+		{name: "end before start", modify: func(req *models.LogTemplateRequest) { req.EndDate = stringPtr("2025-01-01") }, wantErr: "end_date must be on or after start_date"},
+		{name: "invalid start time", modify: func(req *models.LogTemplateRequest) { req.StartTime = "9.30" }, wantErr: "invalid start_time"},
+		{name: "zero duration", modify: func(req *models.LogTemplateRequest) { req.DurationMinutes = 0 }, wantErr: "duration_minutes"},
+		{name: "too long duration", modify: func(req *models.LogTemplateRequest) { req.DurationMinutes = 1441 }, wantErr: "duration_minutes"},
+		{name: "invalid timezone", modify: func(req *models.LogTemplateRequest) { req.Timezone = "Mars/Olympus" }, wantErr: "invalid timezone"},
+		{name: "invalid activity type", modify: func(req *models.LogTemplateRequest) { req.Type = "napping" }, wantErr: "invalid activity type"},
+		{name: "missing title", modify: func(req *models.LogTemplateRequest) { req.Title = "" }, wantErr: "title is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validTemplateRequest()
+			tt.modify(req)
+
+			err := service.validateTemplateRequest(req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLogTemplateService_ParamsRoundTrip(t *testing.T) {
+	service := newTestLogTemplateService()
+	req := validTemplateRequest()
+	req.EndDate = stringPtr("2025-03-31")
+
+	params, err := service.templateParams(req)
+	require.NoError(t, err)
+	assert.True(t, params.IsActive)
+
+	template := service.sqlcToModel(store.LogTemplate{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		Title:           params.Title,
+		Type:            params.Type,
+		ValueRating:     params.ValueRating,
+		ImpactLevel:     params.ImpactLevel,
+		Tags:            params.Tags,
+		RecurrenceRule:  params.RecurrenceRule,
+		StartDate:       params.StartDate,
+		EndDate:         params.EndDate,
+		StartTime:       params.StartTime,
+		DurationMinutes: params.DurationMinutes,
+		Timezone:        params.Timezone,
+		IsActive:        params.IsActive,
+	})
+
+	assert.Equal(t, "2025-01-06", template.StartDate)
+	require.NotNil(t, template.EndDate)
+	assert.Equal(t, "2025-03-31", *template.EndDate)
+	assert.Equal(t, "09:30", template.StartTime)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", template.RecurrenceRule)
+	assert.Nil(t, template.MaterializedUntil)
+}
+
+func TestTemplateSchedule(t *testing.T) {
+	template := &models.LogTemplate{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		Title:           "1:1 with manager",
+		Type:            models.ActivityMeeting,
+		ValueRating:     models.ValueHigh,
+		ImpactLevel:     models.ImpactPersonal,
+		Tags:            []string{"1on1"},
+		RecurrenceRule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+		StartDate:       "2025-01-06",
+		EndDate:         stringPtr("2025-02-03"),
+		StartTime:       "14:00",
+		DurationMinutes: 30,
+		Timezone:        "America/Sao_Paulo",
+	}
+
+	schedule, err := newTemplateSchedule(template)
+	require.NoError(t, err)
+
+	t.Run("expands in the template timezone and honors end date", func(t *testing.T) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, schedule.location)
+		to := time.Date(2025, 12, 31, 0, 0, 0, 0, schedule.location)
+		occurrences := schedule.between(from, to)
+
+		require.Len(t, occurrences, 3)
+		assert.Equal(t, "2025-01-06", occurrences[0].Format(time.DateOnly))
+		assert.Equal(t, "2025-01-20", occurrences[1].Format(time.DateOnly))
+		assert.Equal(t, "2025-02-03", occurrences[2].Format(time.DateOnly))
+		assert.Equal(t, 17, occurrences[0].UTC().Hour()) // 14:00 at UTC-3
+	})
+
+	t.Run("occurrenceOn", func(t *testing.T) {
+		occStart, err := schedule.occurrenceOn("2025-01-20")
+		require.NoError(t, err)
+		assert.Equal(t, 14, occStart.Hour())
+
+		_, err = schedule.occurrenceOn("2025-01-13")
+		assert.Error(t, err)
+
+		_, err = schedule.occurrenceOn("20/01/2025")
+		assert.Error(t, err)
+	})
+
+	t.Run("entry request without overrides", func(t *testing.T) {
+		occStart, _ := schedule.occurrenceOn("2025-01-06")
+		req, err := schedule.entryRequest(template, occStart, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, template.Title, req.Title)
+		assert.Equal(t, 30*time.Minute, req.EndTime.Sub(req.StartTime))
+		assert.Equal(t, []string{"1on1"}, req.Tags)
+	})
+
+	t.Run("entry request with overrides", func(t *testing.T) {
+		occStart, _ := schedule.occurrenceOn("2025-01-06")
+		impact := models.ImpactTeam
+		overrides := &models.OccurrenceOverrides{
+			Title:           stringPtr("1:1 moved to afternoon"),
+			StartTime:       stringPtr("16:15"),
+			DurationMinutes: func() *int { d := 45; return &d }(),
+			ImpactLevel:     &impact,
+			Tags:            []string{"1on1", "career"},
+		}
+
+		req, err := schedule.entryRequest(template, occStart, overrides)
+		require.NoError(t, err)
+
+		assert.Equal(t, "1:1 moved to afternoon", req.Title)
+		assert.Equal(t, 19, req.StartTime.Hour()) // 16:15 at UTC-3
+		assert.Equal(t, 15, req.StartTime.Minute())
+		assert.Equal(t, 45*time.Minute, req.EndTime.Sub(req.StartTime))
+		assert.Equal(t, models.ImpactTeam, req.ImpactLevel)
+		assert.Equal(t, models.ValueHigh, req.ValueRating)
+		assert.Equal(t, []string{"1on1", "career"}, req.Tags)
+	})
+}
+
+func TestValidateOverrides(t *testing.T) {
+	badType := models.ActivityType("napping")
+	badDuration := 0
+
+	assert.Error(t, validateOverrides(nil))
+	assert.NoError(t, validateOverrides(&models.OccurrenceOverrides{Title: stringPtr("Renamed")}))
+	assert.Error(t, validateOverrides(&models.OccurrenceOverrides{Type: &badType}))
+	assert.Error(t, validateOverrides(&models.OccurrenceOverrides{StartTime: stringPtr("25:00")}))
+	assert.Error(t, validateOverrides(&models.OccurrenceOverrides{DurationMinutes: &badDuration}))
+}
+
+func TestDecodeOverrides(t *testing.T) {
+	overrides, err := decodeOverrides([]byte("{}"))
+	require.NoError(t, err)
+	assert.Nil(t, overrides)
+
+	overrides, err = decodeOverrides([]byte(`{"title":"Renamed","duration_minutes":20}`))
+	require.NoError(t, err)
+	require.NotNil(t, overrides)
+	assert.Equal(t, "Renamed", *overrides.Title)
+	assert.Equal(t, 20, *overrides.DurationMinutes)
+
+	_, err = decodeOverrides([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,725 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/mailer"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/garnizeh/englog/internal/worker"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// defaultNotificationPollInterval is how often notifications are scheduled and sent
+	defaultNotificationPollInterval = time.Minute
+	// defaultDigestReportWait is how long a weekly digest waits for its AI report
+	defaultDigestReportWait = 30 * time.Minute
+	// defaultNotificationRetention is how long sent and failed notifications are kept
+	defaultNotificationRetention = 90 * 24 * time.Hour
+	// notificationCleanupInterval is how often notifications past retention are removed
+	notificationCleanupInterval = time.Hour
+	// notificationBatch bounds how many notifications one poll sends or prepares
+	notificationBatch = 50
+	// notificationListLimit bounds how many notifications are listed
+	notificationListLimit = 100
+	// notificationSendLease is how long a notification being sent is out of reach of other processors
+	notificationSendLease = 2 * time.Minute
+	// notificationErrorBytes bounds how much of an SMTP error is kept
+	notificationErrorBytes = 1024
+	// digestHour is the local hour on the first day of the week when the digest of
+	// the previous week is prepared
+	digestHour = 8
+	// digestWindow is how long after the start of the week a missed digest is still
+	// prepared, such as after downtime
+	digestWindow = 48 * time.Hour
+)
+
+// notificationRetry spaces the attempts of an email: 1m, 4m, 16m and 1h apart,
+// giving up after five attempts, about an hour and a half after the first
+var notificationRetry = worker.RetryConfig{
+	MaxAttempts:   5,
+	InitialDelay:  time.Minute,
+	MaxDelay:      time.Hour,
+	BackoffFactor: 4,
+	JitterEnabled: true,
+}
+
+// ErrNotificationsDisabled is returned when no SMTP server is configured
+var ErrNotificationsDisabled = errors.New("email notifications are not configured")
+
+// WeeklyReportQueuer queues the AI weekly report of a user on a worker and returns the task ID
+type WeeklyReportQueuer interface {
+	QueueWeeklyReportTask(ctx context.Context, userID string, weekStart, weekEnd time.Time) (string, error)
+}
+
+// NotificationService sends the email notifications users opted in to in their
+// preferences: a weekly digest with the AI report of the past week, a reminder at
+// the local end of a day nothing was logged on, and notices of failed insights.
+//
+// Every notification is recorded before it is sent, once per user, kind and period,
+// and sent in the background with retries, so restarts and several API instances
+// neither lose nor repeat emails.
+type NotificationService struct {
+	db           *database.DB
+	logger       *logging.Logger
+	sender       mailer.Sender
+	reports      WeeklyReportQueuer
+	pollInterval time.Duration
+	reportWait   time.Duration
+	retention    time.Duration
+	lastCleanup  time.Time
+}
+
+// NewNotificationService creates a new NotificationService instance. Without a
+// sender nothing is sent; without a report queuer digests only have the totals.
+func NewNotificationService(db *database.DB, logger *logging.Logger, sender mailer.Sender, reports WeeklyReportQueuer) *NotificationService {
+	return &NotificationService{
+		db:           db,
+		logger:       logger.WithComponent("notification_service"),
+		sender:       sender,
+		reports:      reports,
+		pollInterval: defaultNotificationPollInterval,
+		reportWait:   defaultDigestReportWait,
+		retention:    defaultNotificationRetention,
+	}
+}
+
+// WithNotificationSettings sets how often notifications are scheduled and sent, how
+// long a digest waits for its AI report and how long sent notifications are kept;
+// non-positive values keep the defaults
+func (s *NotificationService) WithNotificationSettings(pollInterval, reportWait, retention time.Duration) *NotificationService {
+	if pollInterval > 0 {
+		s.pollInterval = pollInterval
+	}
+	if reportWait > 0 {
+		s.reportWait = reportWait
+	}
+	if retention > 0 {
+		s.retention = retention
+	}
+	return s
+}
+
+// GetNotifications returns the latest notifications of a user, newest first
+func (s *NotificationService) GetNotifications(ctx context.Context, userID string) ([]*models.EmailNotification, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetNotifications", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var sqlcNotifications []store.EmailNotification
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcNotifications, err = qtx.GetEmailNotificationsByUser(ctx, store.GetEmailNotificationsByUserParams{
+			UserID: userUUID,
+			Limit:  notificationListLimit,
+		})
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get notifications", "user_id", userID)
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	notifications := make([]*models.EmailNotification, len(sqlcNotifications))
+	for i, sqlcNotification := range sqlcNotifications {
+		notifications[i] = emailNotificationToModel(sqlcNotification)
+	}
+	return notifications, nil
+}
+
+// SendTestEmail sends a test email to the user right away, whatever their
+// preferences, and returns it with the outcome of the attempt
+func (s *NotificationService) SendTestEmail(ctx context.Context, userID string) (*models.EmailNotification, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in SendTestEmail", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if s.sender == nil {
+		return nil, ErrNotificationsDisabled
+	}
+
+	var created store.EmailNotification
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		recipient := newNotificationRecipient(user.ID, user.Email, user.FirstName, user.Timezone, user.Preferences)
+
+		msg, err := mailer.Render(string(models.NotificationTest), models.TestEmail{
+			FirstName:   recipient.firstName,
+			Preferences: recipient.preferences,
+		})
+		if err != nil {
+			return err
+		}
+		created, err = qtx.CreateEmailNotification(ctx, notificationParams(recipient, models.NotificationTest, uuid.NewString(), msg, models.NotificationPending))
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create test email", "user_id", userID)
+		return nil, err
+	}
+
+	claimed, ok := s.claimNotification(ctx, created)
+	if !ok {
+		return nil, fmt.Errorf("test email was already sent")
+	}
+	return emailNotificationToModel(s.attemptSend(ctx, claimed)), nil
+}
+
+// PublishTaskOutcome is told about every worker task that completed or failed. The
+// AI report of a weekly digest completes the digest; failed insights are notified to
+// users who opted in. It does nothing on a nil service or without a sender.
+func (s *NotificationService) PublishTaskOutcome(ctx context.Context, outcome models.WorkerTaskOutcome) {
+	if s == nil || s.sender == nil {
+		return
+	}
+
+	switch {
+	case outcome.TaskType == "weekly_report":
+		s.completeDigest(ctx, outcome)
+	case outcome.TaskType == "insight_generation" && outcome.Failed:
+		s.noticeTaskFailure(ctx, outcome)
+	}
+}
+
+// StartNotificationProcessor schedules and sends notifications until ctx is done
+func (s *NotificationService) StartNotificationProcessor(ctx context.Context) {
+	if s.sender == nil {
+		s.logger.Info("Email notifications disabled, no SMTP server configured")
+		return
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Notification processor started", "interval", s.pollInterval.String(), "report_wait", s.reportWait.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Notification processor stopped")
+			return
+		case <-ticker.C:
+			s.ProcessNotifications(ctx, time.Now())
+		}
+	}
+}
+
+// ProcessNotifications runs one round of the notification processor: it records
+// the reminders and digests that became due by now, prepares digests whose AI
+// report did not come in time and sends every due notification
+func (s *NotificationService) ProcessNotifications(ctx context.Context, now time.Time) {
+	if s.sender == nil {
+		return
+	}
+
+	s.scheduleReminders(ctx, now)
+	s.scheduleDigests(ctx, now)
+	s.prepareStaleDigests(ctx)
+	s.sendDue(ctx)
+
+	if time.Since(s.lastCleanup) >= notificationCleanupInterval {
+		s.lastCleanup = time.Now()
+		s.removeOldNotifications(ctx)
+	}
+}
+
+// scheduleReminders records a reminder for every opted-in user whose reminder time
+// passed today without a log entry; days with entries are recorded as skipped
+func (s *NotificationService) scheduleReminders(ctx context.Context, now time.Time) {
+	recipients, err := s.recipients(ctx, models.NotificationDailyReminder)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get daily reminder recipients")
+		return
+	}
+
+	for _, recipient := range recipients {
+		day, ok := reminderDue(now, recipient)
+		if !ok {
+			continue
+		}
+		periodKey := day.Format(time.DateOnly)
+		if s.notificationRecorded(ctx, recipient, models.NotificationDailyReminder, periodKey) {
+			continue
+		}
+
+		msg, err := mailer.Render(string(models.NotificationDailyReminder), models.DailyReminder{
+			FirstName: recipient.firstName,
+			Date:      day,
+			Time:      recipient.preferences.ReminderTime,
+		})
+		if err != nil {
+			s.logger.LogError(ctx, err, "Failed to render daily reminder", "user_id", recipient.userID)
+			continue
+		}
+
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			totals, err := qtx.GetLogEntryTotals(ctx, store.GetLogEntryTotalsParams{
+				UserID:    recipient.userID,
+				StartTime: timeToPgTimestamptz(day),
+				EndTime:   timeToPgTimestamptz(day.AddDate(0, 0, 1)),
+			})
+			if err != nil {
+				return err
+			}
+
+			status := models.NotificationPending
+			if totals.TotalEntries > 0 {
+				status, msg = models.NotificationSkipped, mailer.Message{}
+			}
+			return s.recordNotificationTx(ctx, qtx, recipient, models.NotificationDailyReminder, periodKey, msg, status)
+		}); err != nil {
+			s.logger.LogError(ctx, err, "Failed to schedule daily reminder", "user_id", recipient.userID, "day", periodKey)
+		}
+	}
+}
+
+// scheduleDigests records the digest of the previous week for every opted-in user
+// whose week started, and queues the AI report it waits for
+func (s *NotificationService) scheduleDigests(ctx context.Context, now time.Time) {
+	recipients, err := s.recipients(ctx, models.NotificationWeeklyDigest)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get weekly digest recipients")
+		return
+	}
+
+	for _, recipient := range recipients {
+		periodStart, ok := digestDue(now, recipient)
+		if !ok {
+			continue
+		}
+		periodKey := periodStart.Format(time.DateOnly)
+
+		if s.notificationRecorded(ctx, recipient, models.NotificationWeeklyDigest, periodKey) {
+			continue
+		}
+
+		var created store.EmailNotification
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			var err error
+			created, err = qtx.CreateEmailNotification(ctx, notificationParams(recipient, models.NotificationWeeklyDigest, periodKey, mailer.Message{}, models.NotificationWaiting))
+			return err
+		}); err != nil {
+			// No row means the digest of this week was recorded before
+			if !database.NoRows(err) {
+				s.logger.LogError(ctx, err, "Failed to schedule weekly digest", "user_id", recipient.userID, "week", periodKey)
+			}
+			continue
+		}
+
+		taskID, err := s.queueDigestReport(ctx, recipient, periodStart)
+		if err != nil {
+			s.logger.Warn("Weekly digest sent without AI report", "user_id", recipient.userID, "week", periodKey, "error", err)
+			s.prepareDigest(ctx, created, nil)
+			continue
+		}
+
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			return qtx.SetEmailNotificationTask(ctx, store.SetEmailNotificationTaskParams{
+				ID:     created.ID,
+				TaskID: stringToPgTextRequired(taskID),
+			})
+		}); err != nil {
+			s.logger.LogError(ctx, err, "Failed to record weekly digest report task", "notification_id", created.ID, "task_id", taskID)
+		}
+	}
+}
+
+// queueDigestReport queues the AI report of the week starting at periodStart
+func (s *NotificationService) queueDigestReport(ctx context.Context, recipient notificationRecipient, periodStart time.Time) (string, error) {
+	if s.reports == nil {
+		return "", errors.New("no AI workers configured")
+	}
+	return s.reports.QueueWeeklyReportTask(ctx, recipient.userID.String(), periodStart, periodStart.AddDate(0, 0, 7).Add(-time.Nanosecond))
+}
+
+// completeDigest prepares the digest waiting for the weekly report of a task outcome
+func (s *NotificationService) completeDigest(ctx context.Context, outcome models.WorkerTaskOutcome) {
+	var waiting store.EmailNotification
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		waiting, err = qtx.GetWaitingEmailNotificationByTask(ctx, stringToPgTextRequired(outcome.TaskID))
+		return err
+	}); err != nil {
+		// Reports requested through the API have no digest waiting for them
+		if !database.NoRows(err) {
+			s.logger.LogError(ctx, err, "Failed to get weekly digest of report", "task_id", outcome.TaskID)
+		}
+		return
+	}
+
+	var report *models.WeeklyDigestReport
+	if !outcome.Failed {
+		report = &models.WeeklyDigestReport{}
+		if err := json.Unmarshal([]byte(outcome.Result), report); err != nil {
+			s.logger.Warn("Invalid weekly report result", "task_id", outcome.TaskID, "error", err)
+			report = nil
+		}
+	}
+	s.prepareDigest(ctx, waiting, report)
+}
+
+// prepareStaleDigests prepares the digests whose AI report did not come in time
+// without it
+func (s *NotificationService) prepareStaleDigests(ctx context.Context) {
+	var stale []store.EmailNotification
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		stale, err = qtx.GetStaleWaitingEmailNotifications(ctx, store.GetStaleWaitingEmailNotificationsParams{
+			CreatedAt: timeToPgTimestamptz(time.Now().Add(-s.reportWait)),
+			Limit:     notificationBatch,
+		})
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get stale weekly digests")
+		return
+	}
+
+	for _, waiting := range stale {
+		s.logger.Warn("Weekly digest sent without AI report, report did not come in time",
+			"notification_id", waiting.ID, "task_id", waiting.TaskID.String)
+		s.prepareDigest(ctx, waiting, nil)
+	}
+}
+
+// prepareDigest renders a waiting digest with the totals of its week and the AI
+// report, when there is one, and makes it due
+func (s *NotificationService) prepareDigest(ctx context.Context, waiting store.EmailNotification, report *models.WeeklyDigestReport) {
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, waiting.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		recipient := newNotificationRecipient(user.ID, user.Email, user.FirstName, user.Timezone, user.Preferences)
+
+		periodStart, err := time.ParseInLocation(time.DateOnly, waiting.PeriodKey, recipient.location)
+		if err != nil {
+			return fmt.Errorf("invalid digest week %q: %w", waiting.PeriodKey, err)
+		}
+		periodEnd := periodStart.AddDate(0, 0, 7)
+
+		totals, err := qtx.GetLogEntryTotals(ctx, store.GetLogEntryTotalsParams{
+			UserID:    waiting.UserID,
+			StartTime: timeToPgTimestamptz(periodStart),
+			EndTime:   timeToPgTimestamptz(periodEnd),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get week totals: %w", err)
+		}
+
+		msg, err := mailer.Render(string(models.NotificationWeeklyDigest), models.WeeklyDigest{
+			FirstName:   recipient.firstName,
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd.AddDate(0, 0, -1),
+			Entries:     totals.TotalEntries,
+			Minutes:     totals.TotalMinutes,
+			Report:      report,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = qtx.PrepareEmailNotification(ctx, store.PrepareEmailNotificationParams{
+			ID:       waiting.ID,
+			Subject:  msg.Subject,
+			BodyText: msg.Text,
+			BodyHtml: msg.HTML,
+		})
+		return err
+	}); err != nil {
+		// No row means another processor prepared the digest first
+		if !database.NoRows(err) {
+			s.logger.LogError(ctx, err, "Failed to prepare weekly digest", "notification_id", waiting.ID)
+		}
+	}
+}
+
+// noticeTaskFailure records a failure notice of an insight task when its user
+// opted in to them
+func (s *NotificationService) noticeTaskFailure(ctx context.Context, outcome models.WorkerTaskOutcome) {
+	userUUID, err := uuid.Parse(outcome.UserID)
+	if err != nil {
+		s.logger.Warn("Task outcome without a valid user", "task_id", outcome.TaskID, "user_id", outcome.UserID)
+		return
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		recipient := newNotificationRecipient(user.ID, user.Email, user.FirstName, user.Timezone, user.Preferences)
+		if !recipient.preferences.TaskFailures {
+			return nil
+		}
+
+		msg, err := mailer.Render(string(models.NotificationTaskFailure), models.TaskFailureNotice{
+			FirstName: recipient.firstName,
+			TaskID:    outcome.TaskID,
+			Error:     shortenText(outcome.Error, notificationErrorBytes),
+			FailedAt:  time.Now().In(recipient.location),
+		})
+		if err != nil {
+			return err
+		}
+		return s.recordNotificationTx(ctx, qtx, recipient, models.NotificationTaskFailure, outcome.TaskID, msg, models.NotificationPending)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to record task failure notice", "task_id", outcome.TaskID, "user_id", outcome.UserID)
+	}
+}
+
+// notificationRecorded reports whether a notification was recorded for a period
+// already; on errors it reports true, so the period is tried again next round
+func (s *NotificationService) notificationRecorded(ctx context.Context, recipient notificationRecipient, kind models.NotificationKind, periodKey string) bool {
+	var exists bool
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		exists, err = qtx.EmailNotificationExists(ctx, store.EmailNotificationExistsParams{
+			UserID:    recipient.userID,
+			Kind:      string(kind),
+			PeriodKey: periodKey,
+		})
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to check notification", "user_id", recipient.userID, "kind", kind, "period", periodKey)
+		return true
+	}
+	return exists
+}
+
+// recordNotificationTx records a notification inside the caller's transaction; a
+// notification recorded before for the same period is left as it is
+func (s *NotificationService) recordNotificationTx(ctx context.Context, qtx *store.Queries, recipient notificationRecipient, kind models.NotificationKind, periodKey string, msg mailer.Message, status models.NotificationStatus) error {
+	_, err := qtx.CreateEmailNotification(ctx, notificationParams(recipient, kind, periodKey, msg, status))
+	if database.NoRows(err) {
+		return nil
+	}
+	return err
+}
+
+// sendDue sends the due notifications. Every notification is claimed for the
+// length of an attempt first, so several API instances can share the work.
+func (s *NotificationService) sendDue(ctx context.Context) {
+	var due []store.EmailNotification
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		due, err = qtx.GetDueEmailNotifications(ctx, notificationBatch)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get due notifications")
+		return
+	}
+
+	for _, notification := range due {
+		if claimed, ok := s.claimNotification(ctx, notification); ok {
+			s.attemptSend(ctx, claimed)
+		}
+	}
+}
+
+// claimNotification leases a due notification to this processor; it reports false
+// when the notification was claimed elsewhere
+func (s *NotificationService) claimNotification(ctx context.Context, notification store.EmailNotification) (store.EmailNotification, bool) {
+	var claimed store.EmailNotification
+	err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		var err error
+		claimed, err = qtx.ClaimEmailNotification(ctx, store.ClaimEmailNotificationParams{
+			LeaseUntil: timeToPgTimestamptz(time.Now().Add(notificationSendLease)),
+			ID:         notification.ID,
+		})
+		return err
+	})
+	if err != nil {
+		if !database.NoRows(err) {
+			s.logger.LogError(ctx, err, "Failed to claim notification", "notification_id", notification.ID)
+		}
+		return store.EmailNotification{}, false
+	}
+	return claimed, true
+}
+
+// attemptSend sends a claimed notification and records the outcome: sent when the
+// SMTP server accepted it, pending again after a temporary failure while attempts
+// remain, and failed otherwise
+func (s *NotificationService) attemptSend(ctx context.Context, notification store.EmailNotification) store.EmailNotification {
+	sendErr := s.sender.Send(ctx, mailer.Message{
+		To:      notification.Recipient,
+		Subject: notification.Subject,
+		Text:    notification.BodyText,
+		HTML:    notification.BodyHtml,
+	})
+
+	params := store.RecordEmailNotificationAttemptParams{
+		Status: string(models.NotificationSent),
+		ID:     notification.ID,
+	}
+	attempts := int(notification.Attempts) + 1
+	if sendErr != nil {
+		params.Status = string(models.NotificationFailed)
+		params.ErrorMessage = stringToPgTextRequired(shortenText(sendErr.Error(), notificationErrorBytes))
+		if !mailer.Permanent(sendErr) && attempts < notificationRetry.MaxAttempts {
+			params.Status = string(models.NotificationPending)
+			params.NextAttemptAt = timeToPgTimestamptz(time.Now().Add(notificationRetry.CalculateDelay(attempts)))
+		}
+	}
+
+	var recorded store.EmailNotification
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		var err error
+		recorded, err = qtx.RecordEmailNotificationAttempt(ctx, params)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to record notification attempt", "notification_id", notification.ID)
+		return notification
+	}
+
+	if sendErr != nil {
+		s.logger.Warn("Email notification not sent", "notification_id", notification.ID, "kind", notification.Kind,
+			"attempts", attempts, "status", params.Status, "error", sendErr)
+	}
+	return recorded
+}
+
+// removeOldNotifications deletes finished notifications past the retention period
+func (s *NotificationService) removeOldNotifications(ctx context.Context) {
+	var removed int64
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		var err error
+		removed, err = qtx.DeleteOldEmailNotifications(ctx, timeToPgTimestamptz(time.Now().Add(-s.retention)))
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove old notifications")
+		return
+	}
+
+	if removed > 0 {
+		s.logger.Info("Old notifications removed", "notifications_removed", removed)
+	}
+}
+
+// recipients returns the users who turned a notification on
+func (s *NotificationService) recipients(ctx context.Context, kind models.NotificationKind) ([]notificationRecipient, error) {
+	var rows []store.GetNotificationRecipientsRow
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		rows, err = qtx.GetNotificationRecipients(ctx, string(kind))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	recipients := make([]notificationRecipient, len(rows))
+	for i, row := range rows {
+		recipients[i] = newNotificationRecipient(row.ID, row.Email, row.FirstName, row.Timezone, row.Preferences)
+	}
+	return recipients, nil
+}
+
+// notificationRecipient is a user as far as notifications are concerned
+type notificationRecipient struct {
+	userID      uuid.UUID
+	email       string
+	firstName   string
+	location    *time.Location
+	weekStart   time.Weekday
+	preferences models.NotificationPreferences
+}
+
+// newNotificationRecipient decodes the timezone and preferences of a user; an
+// invalid timezone falls back to UTC
+func newNotificationRecipient(userID uuid.UUID, email, firstName string, timezone pgtype.Text, rawPreferences []byte) notificationRecipient {
+	location, err := time.LoadLocation(pgTextToStringRequired(timezone))
+	if err != nil {
+		location = time.UTC
+	}
+
+	var preferences map[string]any
+	if len(rawPreferences) > 0 {
+		_ = json.Unmarshal(rawPreferences, &preferences)
+	}
+
+	return notificationRecipient{
+		userID:      userID,
+		email:       email,
+		firstName:   firstName,
+		location:    location,
+		weekStart:   models.WeekStartFromPreferences(preferences).Weekday(),
+		preferences: models.NotificationPreferencesFrom(preferences),
+	}
+}
+
+// reminderDue returns the start of the recipient's local day when their reminder
+// time passed on it
+func reminderDue(now time.Time, recipient notificationRecipient) (time.Time, bool) {
+	local := now.In(recipient.location)
+	hour, minute := recipient.preferences.ReminderClock()
+	if local.Before(time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, recipient.location)) {
+		return time.Time{}, false
+	}
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, recipient.location), true
+}
+
+// digestDue returns the start of the recipient's previous week once the digest of
+// that week is due: from digestHour on the first day of the current week until the
+// digest window closes
+func digestDue(now time.Time, recipient notificationRecipient) (time.Time, bool) {
+	local := now.In(recipient.location)
+	offset := (int(local.Weekday()) - int(recipient.weekStart) + 7) % 7
+	weekStart := time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, recipient.location)
+
+	due := weekStart.Add(digestHour * time.Hour)
+	if local.Before(due) || !local.Before(weekStart.Add(digestWindow)) {
+		return time.Time{}, false
+	}
+	return weekStart.AddDate(0, 0, -7), true
+}
+
+// notificationParams returns the parameters recording a notification to a recipient
+func notificationParams(recipient notificationRecipient, kind models.NotificationKind, periodKey string, msg mailer.Message, status models.NotificationStatus) store.CreateEmailNotificationParams {
+	return store.CreateEmailNotificationParams{
+		UserID:    recipient.userID,
+		Kind:      string(kind),
+		PeriodKey: periodKey,
+		Recipient: recipient.email,
+		Subject:   msg.Subject,
+		BodyText:  msg.Text,
+		BodyHtml:  msg.HTML,
+		Status:    string(status),
+	}
+}
+
+// emailNotificationToModel converts a store email notification to the API model
+func emailNotificationToModel(n store.EmailNotification) *models.EmailNotification {
+	notification := &models.EmailNotification{
+		ID:           n.ID,
+		Kind:         models.NotificationKind(n.Kind),
+		PeriodKey:    n.PeriodKey,
+		Recipient:    n.Recipient,
+		Subject:      n.Subject,
+		Status:       models.NotificationStatus(n.Status),
+		Attempts:     int(n.Attempts),
+		SentAt:       pgTimestamptzToTimePtr(n.SentAt),
+		ErrorMessage: pgTextToString(n.ErrorMessage),
+		CreatedAt:    pgTimestamptzToTime(n.CreatedAt),
+	}
+	if notification.Status == models.NotificationPending {
+		notification.NextAttemptAt = pgTimestamptzToTimePtr(n.NextAttemptAt)
+	}
+	return notification
+}
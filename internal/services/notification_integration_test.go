@@ -0,0 +1,205 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/mailer"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportQueuer records the weekly reports queued for digests
+type reportQueuer struct {
+	mu    sync.Mutex
+	weeks []time.Time
+}
+
+func (q *reportQueuer) QueueWeeklyReportTask(_ context.Context, _ string, weekStart, _ time.Time) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.weeks = append(q.weeks, weekStart)
+	return "weekly_report_" + strconv.Itoa(len(q.weeks)), nil
+}
+
+// TestNotificationService tests reminders, digests, failure notices and test
+// emails against a local SMTP sink
+func TestNotificationService(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	sink, err := mailer.NewSink("127.0.0.1:0", nil)
+	require.NoError(t, err)
+	defer sink.Close()
+	host, port, err := net.SplitHostPort(sink.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	sender, err := mailer.NewSMTP(mailer.Config{Host: host, Port: portNumber, FromName: "EngLog", FromEmail: "noreply@englog.dev"})
+	require.NoError(t, err)
+
+	queuer := &reportQueuer{}
+	notificationService := services.NewNotificationService(db, testLogger, sender, queuer)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	createUser := func(email string, notifications map[string]any) string {
+		user, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     email,
+			Password:  "password123",
+			FirstName: "Note",
+			LastName:  "Taker",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+		_, err = userService.UpdateUserProfile(ctx, user.ID.String(), &models.UserProfileRequest{
+			FirstName:   "Note",
+			LastName:    "Taker",
+			Timezone:    "UTC",
+			Preferences: map[string]any{models.PreferenceNotifications: notifications},
+		})
+		require.NoError(t, err)
+		return user.ID.String()
+	}
+	logAt := func(userID string, start time.Time) {
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       "Reviewed pull requests",
+			Type:        models.ActivityCodeReview,
+			StartTime:   start,
+			EndTime:     start.Add(90 * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+		})
+		require.NoError(t, err)
+	}
+	sentTo := func(email string) []mailer.SinkMessage {
+		var messages []mailer.SinkMessage
+		for _, msg := range sink.Messages() {
+			if len(msg.To) == 1 && msg.To[0] == email {
+				messages = append(messages, msg)
+			}
+		}
+		return messages
+	}
+
+	idle := createUser("idle@example.com", map[string]any{"daily_reminder": true, "reminder_time": "18:00"})
+	busy := createUser("busy@example.com", map[string]any{"daily_reminder": true})
+	digest := createUser("digest@example.com", map[string]any{"weekly_digest": true, "task_failures": true})
+	quiet := createUser("quiet@example.com", map[string]any{})
+
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	logAt(busy, monday.Add(10*time.Hour))
+	logAt(digest, monday.AddDate(0, 0, -5).Add(9*time.Hour))
+	logAt(digest, monday.AddDate(0, 0, -4).Add(9*time.Hour))
+
+	t.Run("DailyReminder", func(t *testing.T) {
+		notificationService.ProcessNotifications(ctx, monday.Add(17*time.Hour))
+		assert.Empty(t, sentTo("idle@example.com"), "not before the reminder time")
+
+		notificationService.ProcessNotifications(ctx, monday.Add(18*time.Hour+time.Minute))
+		messages := sentTo("idle@example.com")
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Nothing logged for Mon, Mar 10 yet", messages[0].Subject())
+		assert.Empty(t, sentTo("busy@example.com"), "days with entries are skipped")
+
+		// Later rounds the same day send nothing more
+		notificationService.ProcessNotifications(ctx, monday.Add(20*time.Hour))
+		assert.Len(t, sentTo("idle@example.com"), 1)
+
+		notifications, err := notificationService.GetNotifications(ctx, busy)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationSkipped, notifications[0].Status)
+
+		notifications, err = notificationService.GetNotifications(ctx, idle)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationSent, notifications[0].Status)
+		assert.NotNil(t, notifications[0].SentAt)
+	})
+
+	t.Run("WeeklyDigest", func(t *testing.T) {
+		notificationService.ProcessNotifications(ctx, monday.Add(9*time.Hour))
+		require.Len(t, queuer.weeks, 1)
+		assert.True(t, queuer.weeks[0].Equal(monday.AddDate(0, 0, -7)))
+		assert.Empty(t, sentTo("digest@example.com"), "the digest waits for its report")
+
+		notifications, err := notificationService.GetNotifications(ctx, digest)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationWaiting, notifications[0].Status)
+
+		// Reports of other tasks do not complete the digest
+		notificationService.PublishTaskOutcome(ctx, models.WorkerTaskOutcome{TaskID: "weekly_report_other", UserID: digest, TaskType: "weekly_report", Result: `{}`})
+		notificationService.PublishTaskOutcome(ctx, models.WorkerTaskOutcome{
+			TaskID:   "weekly_report_1",
+			UserID:   digest,
+			TaskType: "weekly_report",
+			Result:   `{"summary":"A week of reviews.","key_insights":["Reviews took most of the week"],"recommendations":["Pair on large changes"]}`,
+		})
+		notificationService.ProcessNotifications(ctx, monday.Add(9*time.Hour+time.Minute))
+
+		messages := sentTo("digest@example.com")
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Your week in EngLog: Mon, Mar 3 – Sun, Mar 9", messages[0].Subject())
+		text, err := messages[0].Text()
+		require.NoError(t, err)
+		assert.Contains(t, text, "**2 entries** logged over **3h**")
+		assert.Contains(t, text, "A week of reviews.")
+		assert.Contains(t, text, "- Pair on large changes")
+
+		// The digest of a week is only prepared once
+		notificationService.ProcessNotifications(ctx, monday.AddDate(0, 0, 1))
+		assert.Len(t, queuer.weeks, 1)
+	})
+
+	t.Run("TaskFailure", func(t *testing.T) {
+		for _, userID := range []string{digest, quiet} {
+			notificationService.PublishTaskOutcome(ctx, models.WorkerTaskOutcome{
+				TaskID:   "insight_" + userID,
+				UserID:   userID,
+				TaskType: "insight_generation",
+				Failed:   true,
+				Error:    "model unavailable",
+			})
+		}
+		// Completed insights are not notified
+		notificationService.PublishTaskOutcome(ctx, models.WorkerTaskOutcome{TaskID: "insight_ok", UserID: digest, TaskType: "insight_generation"})
+		notificationService.ProcessNotifications(ctx, monday.AddDate(0, 0, 1))
+
+		messages := sentTo("digest@example.com")
+		require.Len(t, messages, 2)
+		assert.Equal(t, "An insight could not be generated", messages[1].Subject())
+		text, err := messages[1].Text()
+		require.NoError(t, err)
+		assert.Contains(t, text, "model unavailable")
+		assert.Empty(t, sentTo("quiet@example.com"), "failure notices are opt-in")
+	})
+
+	t.Run("SendTestEmail", func(t *testing.T) {
+		notification, err := notificationService.SendTestEmail(ctx, quiet)
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationTest, notification.Kind)
+		assert.Equal(t, models.NotificationSent, notification.Status)
+
+		messages := sentTo("quiet@example.com")
+		require.Len(t, messages, 1)
+		assert.Equal(t, "EngLog test email", messages[0].Subject())
+
+		_, err = notificationService.SendTestEmail(ctx, "not-a-uuid")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderDue(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	recipient := notificationRecipient{
+		location:    newYork,
+		preferences: models.NotificationPreferences{DailyReminder: true, ReminderTime: "18:00"},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		day  string
+		due  bool
+	}{
+		{"before the reminder time", time.Date(2025, 3, 10, 21, 59, 0, 0, time.UTC), "", false},
+		{"at the reminder time", time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC), "2025-03-10", true},
+		{"later that evening, already the next day in UTC", time.Date(2025, 3, 11, 3, 30, 0, 0, time.UTC), "2025-03-10", true},
+		{"after local midnight", time.Date(2025, 3, 11, 4, 0, 0, 0, time.UTC), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, due := reminderDue(tt.now, recipient)
+			assert.Equal(t, tt.due, due)
+			if tt.due {
+				assert.Equal(t, tt.day, day.Format(time.DateOnly))
+				assert.Equal(t, newYork, day.Location())
+				assert.Zero(t, day.Hour())
+			}
+		})
+	}
+
+	// Without a chosen time the reminder goes out at DefaultReminderTime
+	recipient.preferences.ReminderTime = ""
+	_, due := reminderDue(time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC), recipient)
+	assert.True(t, due)
+}
+
+func TestDigestDue(t *testing.T) {
+	monday := notificationRecipient{location: time.UTC, weekStart: time.Monday}
+	sunday := notificationRecipient{location: time.UTC, weekStart: time.Sunday}
+
+	tests := []struct {
+		name      string
+		recipient notificationRecipient
+		now       time.Time
+		week      string
+		due       bool
+	}{
+		{"monday before the digest hour", monday, time.Date(2025, 3, 10, 7, 59, 0, 0, time.UTC), "", false},
+		{"monday at the digest hour", monday, time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC), "2025-03-03", true},
+		{"tuesday evening, catching up", monday, time.Date(2025, 3, 11, 23, 0, 0, 0, time.UTC), "2025-03-03", true},
+		{"wednesday, window closed", monday, time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC), "", false},
+		{"sunday for weeks starting on sunday", sunday, time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC), "2025-03-02", true},
+		{"sunday for weeks starting on monday", monday, time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			week, due := digestDue(tt.now, tt.recipient)
+			assert.Equal(t, tt.due, due)
+			if tt.due {
+				assert.Equal(t, tt.week, week.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestNewNotificationRecipient(t *testing.T) {
+	userID := uuid.New()
+	recipient := newNotificationRecipient(userID, "ada@example.com", "Ada", pgtype.Text{String: "Not/AZone", Valid: true},
+		[]byte(`{"week_start":"sunday","notifications":{"daily_reminder":true,"reminder_time":"17:30"}}`))
+
+	assert.Equal(t, userID, recipient.userID)
+	assert.Equal(t, time.UTC, recipient.location, "invalid timezones fall back to UTC")
+	assert.Equal(t, time.Sunday, recipient.weekStart)
+	assert.True(t, recipient.preferences.DailyReminder)
+	assert.False(t, recipient.preferences.WeeklyDigest)
+	assert.Equal(t, "17:30", recipient.preferences.ReminderTime)
+
+	recipient = newNotificationRecipient(userID, "ada@example.com", "Ada", pgtype.Text{}, nil)
+	assert.Equal(t, time.Monday, recipient.weekStart)
+	assert.Equal(t, models.NotificationPreferences{ReminderTime: models.DefaultReminderTime}, recipient.preferences)
+}
+
+func TestEmailNotificationToModel(t *testing.T) {
+	next := time.Now().Add(time.Minute)
+	n := store.EmailNotification{
+		ID:            uuid.New(),
+		Kind:          string(models.NotificationDailyReminder),
+		Status:        string(models.NotificationPending),
+		Attempts:      1,
+		NextAttemptAt: timeToPgTimestamptz(next),
+		ErrorMessage:  stringToPgTextRequired("451 try again later"),
+	}
+
+	notification := emailNotificationToModel(n)
+	assert.Equal(t, models.NotificationDailyReminder, notification.Kind)
+	require.NotNil(t, notification.NextAttemptAt)
+	assert.WithinDuration(t, next, *notification.NextAttemptAt, time.Millisecond)
+	require.NotNil(t, notification.ErrorMessage)
+
+	// Only pending notifications have a next attempt
+	n.Status = string(models.NotificationSent)
+	assert.Nil(t, emailNotificationToModel(n).NextAttemptAt)
+}
+
+func TestNotificationService_WithoutSender(t *testing.T) {
+	// Notifications are configured off when there is no SMTP server
+	s := NewNotificationService(nil, logging.NewTestLogger(), nil, nil)
+	s.ProcessNotifications(context.Background(), time.Now())
+	s.PublishTaskOutcome(context.Background(), models.WorkerTaskOutcome{TaskType: "insight_generation", Failed: true})
+
+	_, err := s.SendTestEmail(context.Background(), uuid.NewString())
+	assert.ErrorIs(t, err, ErrNotificationsDisabled)
+
+	var nilService *NotificationService
+	nilService.PublishTaskOutcome(context.Background(), models.WorkerTaskOutcome{TaskType: "weekly_report"})
+}
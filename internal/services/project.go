@@ -3,19 +3,23 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/garnizeh/englog/internal/database"
 	"github.com/garnizeh/englog/internal/logging"
 	"github.com/garnizeh/englog/internal/models"
 	"github.com/garnizeh/englog/internal/store"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // ProjectService handles all business logic for projects
 type ProjectService struct {
-	db     *database.DB
-	logger *logging.Logger
+	db       *database.DB
+	logger   *logging.Logger
+	webhooks *WebhookService
 }
 
 // NewProjectService creates a new ProjectService instance
@@ -26,6 +30,13 @@ func NewProjectService(db *database.DB, logger *logging.Logger) *ProjectService
 	}
 }
 
+// WithWebhooks publishes project.created, project.updated and project.deleted events
+// to the user's webhooks; archiving and unarchiving count as updates
+func (s *ProjectService) WithWebhooks(webhooks *WebhookService) *ProjectService {
+	s.webhooks = webhooks
+	return s
+}
+
 // CreateProject creates a new project
 func (s *ProjectService) CreateProject(ctx context.Context, userID string, req *models.ProjectRequest) (*models.Project, error) {
 	// Validate request
@@ -46,23 +57,39 @@ func (s *ProjectService) CreateProject(ctx context.Context, userID string, req *
 
 	// Start write transaction to create project
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := validateProjectParent(ctx, qtx, userUUID, nil, req.ParentID); err != nil {
+			return err
+		}
+
+		defaults := projectDefaultsParams(req.Defaults)
 		sqlcProject, err := qtx.CreateProject(ctx, store.CreateProjectParams{
-			Name:        req.Name,
-			Description: stringToPgText(req.Description),
-			Color:       stringToPgText(&req.Color),
-			Status:      stringToPgText((*string)(&req.Status)),
-			StartDate:   timeToPgDate(req.StartDate),
-			EndDate:     timeToPgDate(req.EndDate),
-			CreatedBy:   userUUID,
-			IsDefault:   boolToPgBool(req.IsDefault),
+			Name:               req.Name,
+			Description:        stringToPgText(req.Description),
+			Color:              stringToPgText(&req.Color),
+			Status:             stringToPgText((*string)(&req.Status)),
+			StartDate:          timeToPgDate(req.StartDate),
+			EndDate:            timeToPgDate(req.EndDate),
+			CreatedBy:          userUUID,
+			IsDefault:          boolToPgBool(req.IsDefault),
+			EstimatedHours:     float64ToPgFloat8(req.EstimatedHours),
+			ParentID:           uuidToPgUUID(req.ParentID),
+			DefaultType:        defaults.DefaultType,
+			DefaultTags:        defaults.DefaultTags,
+			DefaultValueRating: defaults.DefaultValueRating,
+			DefaultImpactLevel: defaults.DefaultImpactLevel,
 		})
 		if err != nil {
 			s.logger.LogError(ctx, err, "Database error creating project", "user_id", userID, "project_name", req.Name)
 			return fmt.Errorf("failed to create project: %w", err)
 		}
 
+		if err := s.saveActivityBudgets(ctx, qtx, sqlcProject.ID, req.ActivityBudgets); err != nil {
+			return err
+		}
+
 		project = s.sqlcToModel(sqlcProject)
-		return nil
+		project.ActivityBudgets = req.ActivityBudgets
+		return s.webhooks.publishTx(ctx, qtx, userUUID, models.WebhookProjectCreated, project)
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Transaction failed for project creation", "user_id", userID, "project_name", req.Name)
 		return nil, fmt.Errorf("failed to create project: %w", err)
@@ -92,18 +119,20 @@ func (s *ProjectService) GetProject(ctx context.Context, userID, projectID strin
 
 	// Read operation to get project
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcProject, err := qtx.GetProjectByID(ctx, projectUUID)
+		// Owners and members of the teams the project is shared with may read it
+		sqlcProject, err := authorizeProject(ctx, qtx, projectUUID, userUUID, models.TeamPermissionView)
 		if err != nil {
-			return fmt.Errorf("failed to get project: %w", err)
+			s.logger.Warn("Unauthorized access attempt to project", "user_id", userID, "project_id", projectID, "error", err.Error())
+			return err
 		}
 
-		// Verify ownership
-		if sqlcProject.CreatedBy != userUUID {
-			s.logger.Warn("Unauthorized access attempt to project", "user_id", userID, "project_id", projectID, "owner_id", sqlcProject.CreatedBy)
-			return fmt.Errorf("project not found")
+		budgets, err := qtx.GetProjectActivityBudgets(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project activity budgets: %w", err)
 		}
 
 		project = s.sqlcToModel(sqlcProject)
+		project.ActivityBudgets = activityBudgetsToModel(budgets)
 		return nil
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Failed to get project", "user_id", userID, "project_id", projectID)
@@ -114,28 +143,42 @@ func (s *ProjectService) GetProject(ctx context.Context, userID, projectID strin
 	return project, nil
 }
 
-// GetUserProjects retrieves all projects for a user
-func (s *ProjectService) GetUserProjects(ctx context.Context, userID string) ([]*models.Project, error) {
+// GetUserProjects retrieves the projects of a user; archived projects are only
+// included when includeArchived is set
+func (s *ProjectService) GetUserProjects(ctx context.Context, userID string, includeArchived bool) ([]*models.Project, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format in GetUserProjects", "user_id", userID)
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	s.logger.Info("Getting user projects", "user_id", userID)
+	s.logger.Info("Getting user projects", "user_id", userID, "include_archived", includeArchived)
 
 	var projects []*models.Project
 
 	// Read operation to get projects
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcProjects, err := qtx.GetProjectsByUser(ctx, userUUID)
+		sqlcProjects, err := qtx.GetProjectsByUser(ctx, store.GetProjectsByUserParams{
+			CreatedBy:       userUUID,
+			IncludeArchived: includeArchived,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to get projects: %w", err)
 		}
 
+		budgets, err := qtx.GetProjectActivityBudgetsByUser(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project activity budgets: %w", err)
+		}
+		budgetsByProject := make(map[uuid.UUID][]store.ProjectActivityBudget)
+		for _, budget := range budgets {
+			budgetsByProject[budget.ProjectID] = append(budgetsByProject[budget.ProjectID], budget)
+		}
+
 		projects = make([]*models.Project, len(sqlcProjects))
 		for i, sqlcProject := range sqlcProjects {
 			projects[i] = s.sqlcToModel(sqlcProject)
+			projects[i].ActivityBudgets = activityBudgetsToModel(budgetsByProject[sqlcProject.ID])
 		}
 
 		return nil
@@ -167,9 +210,19 @@ func (s *ProjectService) GetActiveProjects(ctx context.Context, userID string) (
 			return fmt.Errorf("failed to get active projects: %w", err)
 		}
 
+		budgets, err := qtx.GetProjectActivityBudgetsByUser(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project activity budgets: %w", err)
+		}
+		budgetsByProject := make(map[uuid.UUID][]store.ProjectActivityBudget)
+		for _, budget := range budgets {
+			budgetsByProject[budget.ProjectID] = append(budgetsByProject[budget.ProjectID], budget)
+		}
+
 		projects = make([]*models.Project, len(sqlcProjects))
 		for i, sqlcProject := range sqlcProjects {
 			projects[i] = s.sqlcToModel(sqlcProject)
+			projects[i].ActivityBudgets = activityBudgetsToModel(budgetsByProject[sqlcProject.ID])
 		}
 
 		return nil
@@ -242,23 +295,50 @@ func (s *ProjectService) UpdateProject(ctx context.Context, userID, projectID st
 
 	// Start write transaction to update project
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := validateProjectParent(ctx, qtx, userUUID, &projectUUID, req.ParentID); err != nil {
+			return err
+		}
+
+		if req.IsDefault {
+			current, err := qtx.GetProjectByID(ctx, projectUUID)
+			if err == nil && current.ArchivedAt.Valid {
+				return fmt.Errorf("an archived project cannot be the default project")
+			}
+		}
+
+		defaults := projectDefaultsParams(req.Defaults)
 		sqlcProject, err := qtx.UpdateProject(ctx, store.UpdateProjectParams{
-			ID:          projectUUID,
-			Name:        req.Name,
-			Description: stringToPgText(req.Description),
-			Color:       stringToPgText(&req.Color),
-			Status:      stringToPgText((*string)(&req.Status)),
-			StartDate:   timeToPgDate(req.StartDate),
-			EndDate:     timeToPgDate(req.EndDate),
-			IsDefault:   boolToPgBool(req.IsDefault),
-			CreatedBy:   userUUID,
+			ID:                 projectUUID,
+			Name:               req.Name,
+			Description:        stringToPgText(req.Description),
+			Color:              stringToPgText(&req.Color),
+			Status:             stringToPgText((*string)(&req.Status)),
+			StartDate:          timeToPgDate(req.StartDate),
+			EndDate:            timeToPgDate(req.EndDate),
+			IsDefault:          boolToPgBool(req.IsDefault),
+			CreatedBy:          userUUID,
+			EstimatedHours:     float64ToPgFloat8(req.EstimatedHours),
+			ParentID:           uuidToPgUUID(req.ParentID),
+			DefaultType:        defaults.DefaultType,
+			DefaultTags:        defaults.DefaultTags,
+			DefaultValueRating: defaults.DefaultValueRating,
+			DefaultImpactLevel: defaults.DefaultImpactLevel,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update project: %w", err)
 		}
 
+		// The request replaces the whole budget split
+		if err := qtx.DeleteProjectActivityBudgets(ctx, projectUUID); err != nil {
+			return fmt.Errorf("failed to clear project activity budgets: %w", err)
+		}
+		if err := s.saveActivityBudgets(ctx, qtx, projectUUID, req.ActivityBudgets); err != nil {
+			return err
+		}
+
 		project = s.sqlcToModel(sqlcProject)
-		return nil
+		project.ActivityBudgets = req.ActivityBudgets
+		return s.webhooks.publishTx(ctx, qtx, userUUID, models.WebhookProjectUpdated, project)
 	}); err != nil {
 		if strings.Contains(err.Error(), "no rows") {
 			s.logger.Warn("Unauthorized project update attempt", "user_id", userID, "project_id", projectID)
@@ -289,6 +369,7 @@ func (s *ProjectService) DeleteProject(ctx context.Context, userID, projectID st
 	s.logger.Info("Deleting project", "user_id", userID, "project_id", projectID)
 
 	// First verify the project exists and user has ownership
+	var deleted *models.Project
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
 		sqlcProject, err := qtx.GetProjectByID(ctx, projectUUID)
 		if err != nil {
@@ -300,6 +381,7 @@ func (s *ProjectService) DeleteProject(ctx context.Context, userID, projectID st
 			return fmt.Errorf("project not found")
 		}
 
+		deleted = s.sqlcToModel(sqlcProject)
 		return nil
 	}); err != nil {
 		if strings.Contains(err.Error(), "no rows") {
@@ -314,10 +396,13 @@ func (s *ProjectService) DeleteProject(ctx context.Context, userID, projectID st
 
 	// Now delete the project
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
-		return qtx.DeleteProject(ctx, store.DeleteProjectParams{
+		if err := qtx.DeleteProject(ctx, store.DeleteProjectParams{
 			ID:        projectUUID,
 			CreatedBy: userUUID,
-		})
+		}); err != nil {
+			return err
+		}
+		return s.webhooks.publishTx(ctx, qtx, userUUID, models.WebhookProjectDeleted, deleted)
 	}); err != nil {
 		s.logger.LogError(ctx, err, "Failed to delete project", "user_id", userID, "project_id", projectID)
 		return fmt.Errorf("failed to delete project: %w", err)
@@ -327,6 +412,98 @@ func (s *ProjectService) DeleteProject(ctx context.Context, userID, projectID st
 	return nil
 }
 
+// ArchiveProject archives a project and its sub-projects. Archived projects are
+// hidden from project lists and pickers but keep their log entries.
+func (s *ProjectService) ArchiveProject(ctx context.Context, userID, projectID string) (*models.Project, error) {
+	return s.setProjectArchived(ctx, userID, projectID, true)
+}
+
+// UnarchiveProject restores an archived project and its sub-projects
+func (s *ProjectService) UnarchiveProject(ctx context.Context, userID, projectID string) (*models.Project, error) {
+	return s.setProjectArchived(ctx, userID, projectID, false)
+}
+
+// setProjectArchived archives or restores a whole subtree, so a hierarchy is never half archived
+func (s *ProjectService) setProjectArchived(ctx context.Context, userID, projectID string, archived bool) (*models.Project, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	projectUUID, err := uuid.Parse(projectID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid project ID format", "project_id", projectID)
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	s.logger.Info("Setting project archive state", "user_id", userID, "project_id", projectID, "archived", archived)
+
+	var project *models.Project
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcProject, err := qtx.GetProjectByID(ctx, projectUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("project not found")
+			}
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		if sqlcProject.CreatedBy != userUUID {
+			return fmt.Errorf("project not found")
+		}
+
+		archivedAt := pgtype.Timestamptz{}
+		if archived {
+			subtree, err := qtx.GetProjectSubtree(ctx, projectUUID)
+			if err != nil {
+				return fmt.Errorf("failed to get sub-projects: %w", err)
+			}
+			for _, node := range subtree {
+				if node.IsDefault.Valid && node.IsDefault.Bool {
+					return fmt.Errorf("the default project cannot be archived, choose another default project first")
+				}
+			}
+			archivedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		} else if sqlcProject.ParentID.Valid {
+			parent, err := qtx.GetProjectByID(ctx, sqlcProject.ParentID.Bytes)
+			if err != nil {
+				return fmt.Errorf("failed to get parent project: %w", err)
+			}
+			if parent.ArchivedAt.Valid {
+				return fmt.Errorf("parent project is archived, unarchive the parent project first")
+			}
+		}
+
+		if _, err := qtx.SetProjectSubtreeArchived(ctx, store.SetProjectSubtreeArchivedParams{
+			ID:         projectUUID,
+			CreatedBy:  userUUID,
+			ArchivedAt: archivedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to update archive state: %w", err)
+		}
+
+		sqlcProject, err = qtx.GetProjectByID(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		budgets, err := qtx.GetProjectActivityBudgets(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get project activity budgets: %w", err)
+		}
+
+		project = s.sqlcToModel(sqlcProject)
+		project.ActivityBudgets = activityBudgetsToModel(budgets)
+		return s.webhooks.publishTx(ctx, qtx, userUUID, models.WebhookProjectUpdated, project)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to set project archive state", "user_id", userID, "project_id", projectID, "archived", archived)
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	s.logger.Info("Project archive state updated successfully", "user_id", userID, "project_id", projectID, "archived", archived)
+	return project, nil
+}
+
 // validateProjectRequest validates the project request
 func (s *ProjectService) validateProjectRequest(req *models.ProjectRequest) error {
 	if req.Name == "" {
@@ -362,22 +539,193 @@ func (s *ProjectService) validateProjectRequest(req *models.ProjectRequest) erro
 		}
 	}
 
+	if err := req.Defaults.Validate(); err != nil {
+		return err
+	}
+
+	return validateProjectBudget(req.EstimatedHours, req.ActivityBudgets)
+}
+
+// validateProjectBudget checks the estimate and its split by activity type. The split
+// may leave part of the estimate unassigned but cannot add up to more than it.
+func validateProjectBudget(estimatedHours *float64, budgets []models.ProjectActivityBudget) error {
+	if estimatedHours != nil && *estimatedHours <= 0 {
+		return fmt.Errorf("estimated hours must be greater than 0")
+	}
+
+	seen := make(map[models.ActivityType]bool, len(budgets))
+	var total float64
+	for _, budget := range budgets {
+		if !budget.ActivityType.IsValid() {
+			return fmt.Errorf("invalid activity type in budget: %s", budget.ActivityType)
+		}
+		if seen[budget.ActivityType] {
+			return fmt.Errorf("duplicate budget for activity type: %s", budget.ActivityType)
+		}
+		if budget.EstimatedHours <= 0 {
+			return fmt.Errorf("estimated hours for %s must be greater than 0", budget.ActivityType)
+		}
+		seen[budget.ActivityType] = true
+		total += budget.EstimatedHours
+	}
+
+	if estimatedHours != nil && total > *estimatedHours {
+		return fmt.Errorf("activity budgets add up to %gh, more than the %gh estimate", total, *estimatedHours)
+	}
+
+	return nil
+}
+
+// validateProjectParent checks that parentID is an active project of the user and that
+// moving projectID (nil for a new project) below it keeps the hierarchy acyclic and at
+// most models.MaxProjectDepth levels deep
+func validateProjectParent(ctx context.Context, qtx *store.Queries, userID uuid.UUID, projectID, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+	if projectID != nil && *parentID == *projectID {
+		return fmt.Errorf("a project cannot be its own parent")
+	}
+
+	parent, err := qtx.GetProjectByID(ctx, *parentID)
+	if err != nil {
+		if database.NoRows(err) {
+			return fmt.Errorf("parent project not found")
+		}
+		return fmt.Errorf("failed to get parent project: %w", err)
+	}
+	if parent.CreatedBy != userID {
+		return fmt.Errorf("parent project not found")
+	}
+
+	// Only an archived project may be moved below an archived parent
+	if parent.ArchivedAt.Valid {
+		archived := false
+		if projectID != nil {
+			current, err := qtx.GetProjectByID(ctx, *projectID)
+			if err != nil && !database.NoRows(err) {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			archived = err == nil && current.ArchivedAt.Valid
+		}
+		if !archived {
+			return fmt.Errorf("parent project is archived")
+		}
+	}
+
+	// Walk up from the parent: the project must not be one of its ancestors
+	levels := 1
+	for ancestor := parent; ancestor.ParentID.Valid; levels++ {
+		if projectID != nil && ancestor.ParentID.Bytes == *projectID {
+			return fmt.Errorf("a project cannot be moved below one of its sub-projects")
+		}
+		if levels >= models.MaxProjectDepth {
+			return fmt.Errorf("projects can be nested at most %d levels deep", models.MaxProjectDepth)
+		}
+		if ancestor, err = qtx.GetProjectByID(ctx, ancestor.ParentID.Bytes); err != nil {
+			return fmt.Errorf("failed to get parent project: %w", err)
+		}
+	}
+
+	// The project brings its own sub-projects along
+	height := 1
+	if projectID != nil {
+		subtree, err := qtx.GetProjectSubtree(ctx, *projectID)
+		if err != nil {
+			return fmt.Errorf("failed to get sub-projects: %w", err)
+		}
+		for _, node := range subtree {
+			height = max(height, int(node.Depth)+1)
+		}
+	}
+	if levels+height > models.MaxProjectDepth {
+		return fmt.Errorf("projects can be nested at most %d levels deep", models.MaxProjectDepth)
+	}
+
 	return nil
 }
 
+// saveActivityBudgets stores a project's budget split by activity type
+func (s *ProjectService) saveActivityBudgets(ctx context.Context, qtx *store.Queries, projectID uuid.UUID, budgets []models.ProjectActivityBudget) error {
+	for _, budget := range budgets {
+		if err := qtx.CreateProjectActivityBudget(ctx, store.CreateProjectActivityBudgetParams{
+			ProjectID:      projectID,
+			ActivityType:   string(budget.ActivityType),
+			EstimatedHours: budget.EstimatedHours,
+		}); err != nil {
+			return fmt.Errorf("failed to save %s budget: %w", budget.ActivityType, err)
+		}
+	}
+	return nil
+}
+
+// activityBudgetsToModel converts stored activity budgets, returning nil when there are none
+func activityBudgetsToModel(budgets []store.ProjectActivityBudget) []models.ProjectActivityBudget {
+	if len(budgets) == 0 {
+		return nil
+	}
+	result := make([]models.ProjectActivityBudget, len(budgets))
+	for i, budget := range budgets {
+		result[i] = models.ProjectActivityBudget{
+			ActivityType:   models.ActivityType(budget.ActivityType),
+			EstimatedHours: budget.EstimatedHours,
+		}
+	}
+	return result
+}
+
 // sqlcToModel converts SQLC Project to models.Project
 func (s *ProjectService) sqlcToModel(sqlcProject store.Project) *models.Project {
 	return &models.Project{
-		ID:          sqlcProject.ID,
-		Name:        sqlcProject.Name,
-		Description: pgTextToString(sqlcProject.Description),
-		Color:       pgTextToStringRequired(sqlcProject.Color),
-		Status:      models.ProjectStatus(pgTextToStringRequired(sqlcProject.Status)),
-		StartDate:   pgDateToTime(sqlcProject.StartDate),
-		EndDate:     pgDateToTime(sqlcProject.EndDate),
-		CreatedBy:   sqlcProject.CreatedBy,
-		IsDefault:   pgBoolToBool(sqlcProject.IsDefault),
-		CreatedAt:   pgTimestamptzToTime(sqlcProject.CreatedAt),
-		UpdatedAt:   pgTimestamptzToTime(sqlcProject.UpdatedAt),
+		ID:             sqlcProject.ID,
+		Name:           sqlcProject.Name,
+		Description:    pgTextToString(sqlcProject.Description),
+		Color:          pgTextToStringRequired(sqlcProject.Color),
+		Status:         models.ProjectStatus(pgTextToStringRequired(sqlcProject.Status)),
+		StartDate:      pgDateToTime(sqlcProject.StartDate),
+		EndDate:        pgDateToTime(sqlcProject.EndDate),
+		CreatedBy:      sqlcProject.CreatedBy,
+		IsDefault:      pgBoolToBool(sqlcProject.IsDefault),
+		CreatedAt:      pgTimestamptzToTime(sqlcProject.CreatedAt),
+		UpdatedAt:      pgTimestamptzToTime(sqlcProject.UpdatedAt),
+		EstimatedHours: pgFloat8ToFloat64(sqlcProject.EstimatedHours),
+		ParentID:       pgUUIDToUUID(sqlcProject.ParentID),
+		ArchivedAt:     pgTimestamptzToTimePtr(sqlcProject.ArchivedAt),
+		Defaults:       projectDefaultsToModel(sqlcProject),
+	}
+}
+
+// projectDefaultsToModel returns the project's log entry defaults, or nil when none is set
+func projectDefaultsToModel(sqlcProject store.Project) *models.ProjectDefaults {
+	defaults := &models.ProjectDefaults{
+		Type:        models.ActivityType(pgTextToStringRequired(sqlcProject.DefaultType)),
+		Tags:        sqlcProject.DefaultTags,
+		ValueRating: models.ValueRating(pgTextToStringRequired(sqlcProject.DefaultValueRating)),
+		ImpactLevel: models.ImpactLevel(pgTextToStringRequired(sqlcProject.DefaultImpactLevel)),
+	}
+	if defaults.IsEmpty() {
+		return nil
+	}
+	return defaults
+}
+
+// projectDefaultsParams converts log entry defaults to their columns; unset defaults are NULL
+func projectDefaultsParams(defaults *models.ProjectDefaults) store.CreateProjectParams {
+	params := store.CreateProjectParams{DefaultTags: []string{}}
+	if defaults == nil {
+		return params
+	}
+
+	optional := func(value string) pgtype.Text {
+		return pgtype.Text{String: value, Valid: value != ""}
+	}
+	params.DefaultType = optional(string(defaults.Type))
+	params.DefaultValueRating = optional(string(defaults.ValueRating))
+	params.DefaultImpactLevel = optional(string(defaults.ImpactLevel))
+	for _, tag := range defaults.Tags {
+		if tag = strings.TrimSpace(tag); !slices.Contains(params.DefaultTags, tag) {
+			params.DefaultTags = append(params.DefaultTags, tag)
+		}
 	}
+	return params
 }
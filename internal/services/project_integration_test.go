@@ -139,7 +139,7 @@ func TestProjectServiceIntegration(t *testing.T) {
 		}()
 
 		// Test GetUserProjects
-		allProjects, err := projectService.GetUserProjects(ctx, testUser.ID.String())
+		allProjects, err := projectService.GetUserProjects(ctx, testUser.ID.String(), false)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(allProjects), 3)
 
@@ -345,7 +345,7 @@ func TestProjectServiceIntegration(t *testing.T) {
 			_ = userService.DeleteUser(ctx, emptyUser.ID.String())
 		}()
 
-		emptyProjects, err := projectService.GetUserProjects(ctx, emptyUser.ID.String())
+		emptyProjects, err := projectService.GetUserProjects(ctx, emptyUser.ID.String(), false)
 		require.NoError(t, err)
 		assert.Empty(t, emptyProjects)
 
@@ -356,6 +356,253 @@ func TestProjectServiceIntegration(t *testing.T) {
 		_, err = projectService.GetDefaultProject(ctx, emptyUser.ID.String())
 		assert.Error(t, err) // Should error when no default project exists
 	})
+
+	t.Run("BudgetsAndStats", func(t *testing.T) {
+		analyticsService := services.NewAnalyticsService(db, testLogger)
+		logEntryService := services.NewLogEntryService(db, testLogger)
+
+		estimate := 4.0
+		startDate := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+		project, err := projectService.CreateProject(ctx, testUser.ID.String(), &models.ProjectRequest{
+			Name:           fmt.Sprintf("budget-%d", time.Now().UnixNano()),
+			Color:          "#3B82F6",
+			Status:         models.ProjectActive,
+			StartDate:      &startDate,
+			EstimatedHours: &estimate,
+			ActivityBudgets: []models.ProjectActivityBudget{
+				{ActivityType: models.ActivityMeeting, EstimatedHours: 1},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, estimate, *project.EstimatedHours)
+
+		defer func() {
+			_ = projectService.DeleteProject(ctx, testUser.ID.String(), project.ID.String())
+		}()
+
+		fetched, err := projectService.GetProject(ctx, testUser.ID.String(), project.ID.String())
+		require.NoError(t, err)
+		require.Len(t, fetched.ActivityBudgets, 1)
+		assert.Equal(t, models.ActivityMeeting, fetched.ActivityBudgets[0].ActivityType)
+
+		for i, kind := range []models.ActivityType{models.ActivityDevelopment, models.ActivityMeeting, models.ActivityDevelopment} {
+			start := startDate.Add(time.Duration(i)*24*time.Hour + 9*time.Hour)
+			_, err := logEntryService.CreateLogEntry(ctx, testUser.ID.String(), &models.LogEntryRequest{
+				Title:       fmt.Sprintf("Budget entry %d", i),
+				Type:        kind,
+				ProjectID:   &project.ID,
+				StartTime:   start,
+				EndTime:     start.Add(90 * time.Minute),
+				ValueRating: models.ValueMedium,
+				ImpactLevel: models.ImpactTeam,
+			})
+			require.NoError(t, err)
+		}
+
+		stats, err := analyticsService.GetProjectStats(ctx, testUser.ID.String(), project.ID.String(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 270, stats.ActualMinutes)
+		assert.True(t, stats.OverBudget)
+		assert.Equal(t, -0.5, *stats.RemainingHours)
+		assert.Equal(t, "2025-05-01", stats.Burn[0].Date)
+		assert.Equal(t, 4.5, stats.Burn[len(stats.Burn)-1].CumulativeHours)
+		require.NotNil(t, stats.Lifetime)
+		assert.Equal(t, int64(3), stats.Lifetime.TotalEntries)
+
+		for _, activity := range stats.Activities {
+			if activity.ActivityType == models.ActivityMeeting {
+				assert.True(t, activity.OverBudget)
+			}
+		}
+
+		// Updating without a split clears it
+		_, err = projectService.UpdateProject(ctx, testUser.ID.String(), project.ID.String(), &models.ProjectRequest{
+			Name:           project.Name,
+			Color:          project.Color,
+			Status:         project.Status,
+			StartDate:      &startDate,
+			EstimatedHours: &estimate,
+		})
+		require.NoError(t, err)
+		fetched, err = projectService.GetProject(ctx, testUser.ID.String(), project.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, fetched.ActivityBudgets)
+
+		otherUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     fmt.Sprintf("budget-other-%d@example.com", time.Now().UnixNano()),
+			Password:  "password123",
+			FirstName: "Other",
+			LastName:  "User",
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = userService.DeleteUser(ctx, otherUser.ID.String())
+		}()
+
+		_, err = analyticsService.GetProjectStats(ctx, otherUser.ID.String(), project.ID.String(), nil)
+		assert.ErrorContains(t, err, "project not found")
+	})
+
+	t.Run("HierarchyArchiveAndDefaults", func(t *testing.T) {
+		analyticsService := services.NewAnalyticsService(db, testLogger)
+		logEntryService := services.NewLogEntryService(db, testLogger)
+
+		create := func(name string, parentID *uuid.UUID, defaults *models.ProjectDefaults) (*models.Project, error) {
+			return projectService.CreateProject(ctx, testUser.ID.String(), &models.ProjectRequest{
+				Name:     fmt.Sprintf("%s-%d", name, time.Now().UnixNano()),
+				Color:    "#10B981",
+				Status:   models.ProjectActive,
+				ParentID: parentID,
+				Defaults: defaults,
+			})
+		}
+
+		epic, err := create("epic", nil, nil)
+		require.NoError(t, err)
+		defer func() {
+			_ = projectService.DeleteProject(ctx, testUser.ID.String(), epic.ID.String())
+		}()
+
+		project, err := create("project", &epic.ID, &models.ProjectDefaults{
+			Type:        models.ActivityMeeting,
+			Tags:        []string{"billing"},
+			ValueRating: models.ValueHigh,
+			ImpactLevel: models.ImpactTeam,
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = projectService.DeleteProject(ctx, testUser.ID.String(), project.ID.String())
+		}()
+		assert.Equal(t, epic.ID, *project.ParentID)
+		require.NotNil(t, project.Defaults)
+
+		workstream, err := create("workstream", &project.ID, nil)
+		require.NoError(t, err)
+		defer func() {
+			_ = projectService.DeleteProject(ctx, testUser.ID.String(), workstream.ID.String())
+		}()
+
+		_, err = create("too-deep", &workstream.ID, nil)
+		assert.ErrorContains(t, err, "at most 3 levels")
+
+		// Moving the epic below its own workstream would create a cycle
+		_, err = projectService.UpdateProject(ctx, testUser.ID.String(), epic.ID.String(), &models.ProjectRequest{
+			Name:     epic.Name,
+			Color:    epic.Color,
+			Status:   epic.Status,
+			ParentID: &workstream.ID,
+		})
+		assert.Error(t, err)
+
+		// Omitted fields come from the project's defaults
+		start := time.Now().Add(-2 * time.Hour).Truncate(time.Minute)
+		entry, err := logEntryService.CreateLogEntry(ctx, testUser.ID.String(), &models.LogEntryRequest{
+			Title:     "Sprint planning",
+			ProjectID: &project.ID,
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.ActivityMeeting, entry.Type)
+		assert.Equal(t, models.ValueHigh, entry.ValueRating)
+		assert.Equal(t, models.ImpactTeam, entry.ImpactLevel)
+		assert.Equal(t, []string{"billing"}, entry.Tags)
+
+		_, err = logEntryService.CreateLogEntry(ctx, testUser.ID.String(), &models.LogEntryRequest{
+			Title:       "Implementation",
+			Type:        models.ActivityDevelopment,
+			ProjectID:   &workstream.ID,
+			StartTime:   start.Add(time.Hour),
+			EndTime:     start.Add(2 * time.Hour),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+		})
+		require.NoError(t, err)
+
+		// The epic's stats roll up its sub-projects
+		stats, err := analyticsService.GetProjectStats(ctx, testUser.ID.String(), epic.ID.String(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 120, stats.ActualMinutes)
+		require.Len(t, stats.Subprojects, 2)
+		assert.Equal(t, project.ID, stats.Subprojects[0].ProjectID)
+		assert.Equal(t, 60, stats.Subprojects[0].ActualMinutes)
+		assert.Equal(t, 2, stats.Subprojects[1].Depth)
+
+		// Archiving hides the whole subtree but keeps the entries
+		archived, err := projectService.ArchiveProject(ctx, testUser.ID.String(), project.ID.String())
+		require.NoError(t, err)
+		assert.NotNil(t, archived.ArchivedAt)
+
+		visible, err := projectService.GetUserProjects(ctx, testUser.ID.String(), false)
+		require.NoError(t, err)
+		for _, p := range visible {
+			assert.NotEqual(t, project.ID, p.ID)
+			assert.NotEqual(t, workstream.ID, p.ID)
+		}
+		all, err := projectService.GetUserProjects(ctx, testUser.ID.String(), true)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(all), len(visible)+2)
+
+		stats, err = analyticsService.GetProjectStats(ctx, testUser.ID.String(), epic.ID.String(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 120, stats.ActualMinutes)
+
+		_, err = logEntryService.CreateLogEntry(ctx, testUser.ID.String(), &models.LogEntryRequest{
+			Title:     "Late entry",
+			ProjectID: &workstream.ID,
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+		})
+		assert.ErrorContains(t, err, "project is archived")
+
+		// Entries can stay on an archived project but not be moved to one
+		update := &models.LogEntryRequest{
+			Title:       "Sprint planning, updated",
+			Type:        entry.Type,
+			ProjectID:   &project.ID,
+			StartTime:   entry.StartTime,
+			EndTime:     entry.EndTime,
+			ValueRating: entry.ValueRating,
+			ImpactLevel: entry.ImpactLevel,
+		}
+		_, err = logEntryService.UpdateLogEntry(ctx, testUser.ID.String(), entry.ID.String(), update)
+		require.NoError(t, err)
+
+		update.ProjectID = &workstream.ID
+		_, err = logEntryService.UpdateLogEntry(ctx, testUser.ID.String(), entry.ID.String(), update)
+		assert.ErrorContains(t, err, "project is archived")
+
+		moveResult, err := logEntryService.BulkMoveLogEntries(ctx, testUser.ID.String(), &models.BulkMoveRequest{
+			ProjectID:     &workstream.ID,
+			BulkSelection: models.BulkSelection{IDs: []uuid.UUID{entry.ID}},
+		})
+		require.NoError(t, err)
+		assert.False(t, moveResult.Committed)
+		assert.Contains(t, moveResult.Results[0].Error, "project is archived")
+
+		_, err = projectService.UnarchiveProject(ctx, testUser.ID.String(), workstream.ID.String())
+		assert.ErrorContains(t, err, "unarchive the parent project first")
+
+		restored, err := projectService.UnarchiveProject(ctx, testUser.ID.String(), project.ID.String())
+		require.NoError(t, err)
+		assert.Nil(t, restored.ArchivedAt)
+
+		// The default project cannot be archived
+		_, err = projectService.UpdateProject(ctx, testUser.ID.String(), workstream.ID.String(), &models.ProjectRequest{
+			Name:      workstream.Name,
+			Color:     workstream.Color,
+			Status:    workstream.Status,
+			ParentID:  &project.ID,
+			IsDefault: true,
+		})
+		require.NoError(t, err)
+		_, err = projectService.ArchiveProject(ctx, testUser.ID.String(), epic.ID.String())
+		assert.ErrorContains(t, err, "default project cannot be archived")
+
+		defaultProject, err := projectService.GetDefaultProject(ctx, testUser.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, workstream.ID, defaultProject.ID)
+	})
 }
 
 // BenchmarkProjectService benchmarks project service operations
@@ -449,7 +696,7 @@ func BenchmarkProjectService(b *testing.B) {
 
 		b.ResetTimer()
 		for range b.N {
-			_, err := projectService.GetUserProjects(ctx, testUser.ID.String())
+			_, err := projectService.GetUserProjects(ctx, testUser.ID.String(), false)
 			if err != nil {
 				b.Fatalf("Failed to get user projects: %v", err)
 			}
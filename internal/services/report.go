@@ -0,0 +1,761 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/garnizeh/englog/internal/blobstore"
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// defaultReportPollInterval is how often report tasks are dispatched and collected
+	defaultReportPollInterval = 5 * time.Second
+	// defaultReportDownloadTTL is how long a generated report can be downloaded
+	defaultReportDownloadTTL = 72 * time.Hour
+	// reportRenderTimeout is how long a worker may take before the attempt is failed and retried
+	reportRenderTimeout = 10 * time.Minute
+	// reportTaskPriority is the priority of report tasks, 1 being the highest
+	reportTaskPriority = 5
+	// reportTaskMaxRetries bounds how often a failed report is attempted again
+	reportTaskMaxRetries = 2
+	// reportDispatchBatch bounds how many reports one poll hands to workers
+	reportDispatchBatch = 10
+	// reportExpiryBatch bounds how many expired report files one poll removes
+	reportExpiryBatch = 50
+	// reportListLimit bounds how many reports are listed
+	reportListLimit = 50
+	// maxReportPeriodDays bounds the period a report may cover
+	maxReportPeriodDays = 366
+	// reportTopProjects is how many projects a performance review shows
+	reportTopProjects = 5
+	// reportHighlights is how many highlights a performance review shows
+	reportHighlights = 15
+)
+
+// ReportRenderer runs report rendering tasks on workers
+type ReportRenderer interface {
+	// CanRenderReports reports whether a connected worker can render reports
+	CanRenderReports(ctx context.Context) bool
+	// QueueReportRender queues a rendering task on a worker under workerTaskID
+	QueueReportRender(ctx context.Context, workerTaskID, userID string, task *models.ReportRenderTask) error
+	// TakeReportResult returns the outcome of a finished rendering task and forgets it;
+	// done is false while the task is still running
+	TakeReportResult(ctx context.Context, workerTaskID string) (result *models.ReportRenderResult, done bool, err error)
+	// DiscardReportResult drops the result of a rendering task nobody waits for anymore
+	DiscardReportResult(workerTaskID string)
+}
+
+// reportTaskPayload is the payload stored on a report task
+type reportTaskPayload struct {
+	ReportType models.ReportType `json:"report_type"`
+	StartDate  string            `json:"start_date"`
+	EndDate    string            `json:"end_date"`
+}
+
+// reportTaskResult is the result stored on a completed report task
+type reportTaskResult struct {
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"storage_key,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Expired     bool      `json:"expired,omitempty"`
+}
+
+// ReportService generates report files, such as the performance review PDF, as
+// export_data tasks. Requests are recorded in the tasks table; a processor hands
+// them to workers, stores the rendered files in blob storage and removes them
+// once their download period is over.
+type ReportService struct {
+	db           *database.DB
+	logger       *logging.Logger
+	analytics    *AnalyticsService
+	blobs        blobstore.Store
+	renderer     ReportRenderer
+	pollInterval time.Duration
+	downloadTTL  time.Duration
+	webhooks     *WebhookService
+}
+
+// NewReportService creates a new ReportService instance.
+// renderer may be nil, in which case reports cannot be requested.
+func NewReportService(db *database.DB, logger *logging.Logger, analytics *AnalyticsService, blobs blobstore.Store, renderer ReportRenderer) *ReportService {
+	return &ReportService{
+		db:           db,
+		logger:       logger.WithComponent("report_service"),
+		analytics:    analytics,
+		blobs:        blobs,
+		renderer:     renderer,
+		pollInterval: defaultReportPollInterval,
+		downloadTTL:  defaultReportDownloadTTL,
+	}
+}
+
+// WithReportSettings sets how often report tasks are processed and how long generated
+// reports can be downloaded; non-positive values keep the defaults
+func (s *ReportService) WithReportSettings(pollInterval, downloadTTL time.Duration) *ReportService {
+	if pollInterval > 0 {
+		s.pollInterval = pollInterval
+	}
+	if downloadTTL > 0 {
+		s.downloadTTL = downloadTTL
+	}
+	return s
+}
+
+// WithWebhooks publishes report.completed events to the user's webhooks, and
+// task.failed once a report runs out of retries
+func (s *ReportService) WithWebhooks(webhooks *WebhookService) *ReportService {
+	s.webhooks = webhooks
+	return s
+}
+
+// RequestPerformanceReview queues a performance review PDF for the given days in the
+// user's timezone. The report is generated asynchronously; poll it with GetReport.
+func (s *ReportService) RequestPerformanceReview(ctx context.Context, userID string, req *models.PerformanceReviewRequest) (*models.ReportExport, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in RequestPerformanceReview", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := validateReportPeriod(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	if s.renderer == nil {
+		return nil, fmt.Errorf("report generation is not available")
+	}
+
+	payload, err := json.Marshal(reportTaskPayload{
+		ReportType: models.ReportPerformanceReview,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report request: %w", err)
+	}
+
+	var task store.Task
+	err = s.db.Write(ctx, func(qtx *store.Queries) error {
+		task, err = qtx.CreateTask(ctx, store.CreateTaskParams{
+			TaskType:    string(models.TaskExportData),
+			UserID:      uuidToPgUUID(&userUUID),
+			Payload:     payload,
+			Priority:    pgtype.Int4{Int32: reportTaskPriority, Valid: true},
+			MaxRetries:  pgtype.Int4{Int32: reportTaskMaxRetries, Valid: true},
+			ScheduledAt: timeToPgTimestamptz(time.Now()),
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to create report task", "user_id", userID)
+		return nil, fmt.Errorf("failed to request report: %w", err)
+	}
+
+	s.logger.Info("Performance review requested", "user_id", userID, "report_id", task.ID,
+		"start_date", req.StartDate, "end_date", req.EndDate)
+
+	return reportTaskToModel(task, time.Now()), nil
+}
+
+// ListReports returns the user's most recent reports, newest first
+func (s *ReportService) ListReports(ctx context.Context, userID string) ([]models.ReportExport, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in ListReports", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var tasks []store.Task
+	err = s.db.Read(ctx, func(qtx *store.Queries) error {
+		tasks, err = qtx.GetUserTasksByType(ctx, store.GetUserTasksByTypeParams{
+			UserID:   uuidToPgUUID(&userUUID),
+			TaskType: string(models.TaskExportData),
+			Limit:    reportListLimit,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	now := time.Now()
+	reports := make([]models.ReportExport, len(tasks))
+	for i, task := range tasks {
+		reports[i] = *reportTaskToModel(task, now)
+	}
+	return reports, nil
+}
+
+// GetReport returns one of the user's reports
+func (s *ReportService) GetReport(ctx context.Context, userID, reportID string) (*models.ReportExport, error) {
+	task, err := s.getReportTask(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	return reportTaskToModel(task, time.Now()), nil
+}
+
+// OpenReport opens the file of a completed report that has not expired.
+// The caller must close the reader.
+func (s *ReportService) OpenReport(ctx context.Context, userID, reportID string) (*models.ReportExport, io.ReadCloser, error) {
+	task, err := s.getReportTask(ctx, userID, reportID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := reportTaskToModel(task, time.Now())
+	switch {
+	case report.Expired:
+		return nil, nil, fmt.Errorf("report download has expired")
+	case report.Status != models.TaskCompleted:
+		return nil, nil, fmt.Errorf("report is not ready yet")
+	}
+
+	result, err := parseReportTaskResult(task.Result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, result.StorageKey)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to read report file", "report_id", reportID, "storage_key", result.StorageKey)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, nil, fmt.Errorf("report file not found")
+		}
+		return nil, nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	return report, content, nil
+}
+
+// StartReportProcessor hands report tasks to workers, collects the rendered files and
+// removes expired ones until ctx is cancelled
+func (s *ReportService) StartReportProcessor(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Report processor started", "interval", s.pollInterval.String(), "download_ttl", s.downloadTTL.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Report processor stopped")
+			return
+		case <-ticker.C:
+			s.ProcessReports(ctx)
+		}
+	}
+}
+
+// ProcessReports runs one round of the report processor. Failures are logged and
+// retried on the next round.
+func (s *ReportService) ProcessReports(ctx context.Context) {
+	if s.renderer != nil {
+		if err := s.collectReports(ctx); err != nil {
+			s.logger.LogError(ctx, err, "Failed to collect rendered reports")
+		}
+		if err := s.dispatchReports(ctx); err != nil {
+			s.logger.LogError(ctx, err, "Failed to dispatch report tasks")
+		}
+	}
+
+	removed, err := s.expireReports(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove expired reports")
+	} else if removed > 0 {
+		s.logger.Info("Expired reports removed", "reports_removed", removed)
+	}
+}
+
+// dispatchReports gathers the data of due report tasks and queues them on workers.
+// Tasks stay pending while no worker can render reports.
+func (s *ReportService) dispatchReports(ctx context.Context) error {
+	if !s.renderer.CanRenderReports(ctx) {
+		return nil
+	}
+
+	var due []store.Task
+	err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		due, err = qtx.GetDueTasksByType(ctx, store.GetDueTasksByTypeParams{
+			TaskType: string(models.TaskExportData),
+			Limit:    reportDispatchBatch,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get due report tasks: %w", err)
+	}
+
+	for _, task := range due {
+		// Claiming the task keeps other API instances from dispatching it too
+		var claimed store.Task
+		err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			var err error
+			claimed, err = qtx.StartTaskProcessing(ctx, task.ID)
+			return err
+		})
+		if database.NoRows(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to claim report task: %w", err)
+		}
+
+		if err := s.dispatchReport(ctx, claimed); err != nil {
+			s.failReport(ctx, claimed, err)
+		}
+	}
+	return nil
+}
+
+// dispatchReport builds the render task of a claimed report task and queues it on a worker
+func (s *ReportService) dispatchReport(ctx context.Context, task store.Task) error {
+	var payload reportTaskPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid report task payload: %w", err)
+	}
+
+	userUUID := pgUUIDToUUID(task.UserID)
+	if userUUID == nil {
+		return fmt.Errorf("report task has no user")
+	}
+
+	var render *models.ReportRenderTask
+	switch payload.ReportType {
+	case models.ReportPerformanceReview:
+		review, err := s.buildPerformanceReview(ctx, *userUUID, payload.StartDate, payload.EndDate)
+		if err != nil {
+			return err
+		}
+		render = &models.ReportRenderTask{
+			ReportType:        payload.ReportType,
+			FileName:          reportFileName(payload),
+			PerformanceReview: review,
+		}
+	default:
+		return fmt.Errorf("unsupported report type: %s", payload.ReportType)
+	}
+
+	return s.renderer.QueueReportRender(ctx, reportWorkerTaskID(task), userUUID.String(), render)
+}
+
+// collectReports stores the files of rendered reports and fails attempts that took too long
+func (s *ReportService) collectReports(ctx context.Context) error {
+	var processing []store.Task
+	err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		processing, err = qtx.GetProcessingTasksByType(ctx, string(models.TaskExportData))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get processing report tasks: %w", err)
+	}
+
+	for _, task := range processing {
+		workerTaskID := reportWorkerTaskID(task)
+		result, done, err := s.renderer.TakeReportResult(ctx, workerTaskID)
+		switch {
+		case !done:
+			// Results live in the API's memory, so a restart or a lost worker also ends here
+			if time.Since(pgTimestamptzToTime(task.StartedAt)) > reportRenderTimeout {
+				s.renderer.DiscardReportResult(workerTaskID)
+				s.failReport(ctx, task, fmt.Errorf("report rendering timed out"))
+			}
+		case err != nil:
+			s.failReport(ctx, task, err)
+		default:
+			if err := s.completeReport(ctx, task, result); err != nil {
+				s.failReport(ctx, task, err)
+			}
+		}
+	}
+	return nil
+}
+
+// completeReport stores a rendered report file and marks its task completed
+func (s *ReportService) completeReport(ctx context.Context, task store.Task, rendered *models.ReportRenderResult) error {
+	if len(rendered.Data) == 0 {
+		return fmt.Errorf("worker returned an empty report")
+	}
+
+	userUUID := pgUUIDToUUID(task.UserID)
+	if userUUID == nil {
+		return fmt.Errorf("report task has no user")
+	}
+
+	storageKey := "reports/" + userUUID.String() + "/" + task.ID.String()
+	size := int64(len(rendered.Data))
+	if err := s.blobs.Put(ctx, storageKey, bytes.NewReader(rendered.Data), size, rendered.ContentType); err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+
+	result, err := json.Marshal(reportTaskResult{
+		FileName:    rendered.FileName,
+		ContentType: rendered.ContentType,
+		SizeBytes:   size,
+		StorageKey:  storageKey,
+		ExpiresAt:   time.Now().Add(s.downloadTTL).UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode report result: %w", err)
+	}
+
+	err = s.db.Write(ctx, func(qtx *store.Queries) error {
+		completed, err := qtx.CompleteTask(ctx, store.CompleteTaskParams{ID: task.ID, Result: result})
+		if err != nil {
+			return err
+		}
+		return s.webhooks.publishTx(ctx, qtx, *userUUID, models.WebhookReportCompleted, reportTaskToModel(completed, time.Now()))
+	})
+	if err != nil {
+		if delErr := s.blobs.Delete(context.WithoutCancel(ctx), storageKey); delErr != nil {
+			s.logger.LogError(ctx, delErr, "Failed to remove file of uncompleted report", "storage_key", storageKey)
+		}
+		return fmt.Errorf("failed to complete report task: %w", err)
+	}
+
+	s.logger.Info("Report generated", "report_id", task.ID, "user_id", userUUID, "size_bytes", size)
+	return nil
+}
+
+// failReport records a failed attempt; the task is retried later until it runs out of retries
+func (s *ReportService) failReport(ctx context.Context, task store.Task, cause error) {
+	s.logger.LogError(ctx, cause, "Report generation failed", "report_id", task.ID)
+
+	err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		failed, err := qtx.FailTask(ctx, store.FailTaskParams{
+			ID:           task.ID,
+			ErrorMessage: stringToPgTextRequired(cause.Error()),
+		})
+		if err != nil {
+			return err
+		}
+
+		userUUID := pgUUIDToUUID(failed.UserID)
+		if failed.Status.String != string(models.TaskFailed) || userUUID == nil {
+			return nil
+		}
+		return s.webhooks.publishTx(ctx, qtx, *userUUID, models.WebhookTaskFailed, webhookTaskData{
+			TaskID:   failed.ID.String(),
+			TaskType: failed.TaskType,
+			Error:    cause.Error(),
+		})
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to record report failure", "report_id", task.ID)
+	}
+}
+
+// expireReports removes the files of reports past their download period
+func (s *ReportService) expireReports(ctx context.Context) (int, error) {
+	var expired []store.Task
+	err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		expired, err = qtx.GetExpiredTaskFiles(ctx, store.GetExpiredTaskFilesParams{
+			TaskType: string(models.TaskExportData),
+			Limit:    reportExpiryBatch,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired reports: %w", err)
+	}
+
+	removed := 0
+	for _, task := range expired {
+		result, err := parseReportTaskResult(task.Result)
+		if err != nil {
+			s.logger.LogError(ctx, err, "Skipping expired report with an invalid result", "report_id", task.ID)
+			continue
+		}
+		if err := s.blobs.Delete(ctx, result.StorageKey); err != nil {
+			s.logger.LogError(ctx, err, "Failed to delete expired report file", "report_id", task.ID)
+			continue
+		}
+		if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+			return qtx.ExpireTaskFile(ctx, task.ID)
+		}); err != nil {
+			return removed, fmt.Errorf("failed to expire report: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// buildPerformanceReview gathers the data of a performance review for whole days in the
+// user's timezone
+func (s *ReportService) buildPerformanceReview(ctx context.Context, userUUID uuid.UUID, startDate, endDate string) (*models.PerformanceReview, error) {
+	first, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+	}
+	last, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+	}
+
+	review := &models.PerformanceReview{
+		PeriodStart:  startDate,
+		PeriodEnd:    endDate,
+		GeneratedAt:  time.Now(),
+		Activities:   []models.ReportBreakdown{},
+		ValueRatings: []models.ReportBreakdown{},
+		ImpactLevels: []models.ReportBreakdown{},
+		TopProjects:  []models.ReportProject{},
+		Highlights:   []models.ReportHighlight{},
+	}
+
+	err = s.db.Read(ctx, func(qtx *store.Queries) error {
+		user, err := qtx.GetUserByID(ctx, userUUID)
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		review.UserName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+
+		loc, err := time.LoadLocation(pgTextToStringRequired(user.Timezone))
+		if err != nil {
+			s.logger.Warn("Stored timezone is invalid, reporting in UTC", "user_id", userUUID, "timezone", user.Timezone.String)
+			loc = time.UTC
+		}
+		review.Timezone = loc.String()
+		review.GeneratedAt = review.GeneratedAt.In(loc)
+		start := inLocation(first, loc)
+		end := inLocation(last.AddDate(0, 0, 1), loc).Add(-time.Nanosecond)
+
+		activities, err := s.analytics.activityTypeDistribution(ctx, qtx, userUUID, start, end)
+		if err != nil {
+			return err
+		}
+		for _, activity := range activities {
+			review.Activities = append(review.Activities, models.ReportBreakdown{
+				Label:   activity.Type,
+				Entries: int(activity.EntryCount),
+				Minutes: int(activity.TotalMinutes),
+			})
+			review.TotalEntries += int(activity.EntryCount)
+			review.TotalMinutes += int(activity.TotalMinutes)
+		}
+
+		valueRatings, impactLevels, err := s.analytics.ratingDistributions(ctx, qtx, userUUID, start, end)
+		if err != nil {
+			return err
+		}
+		for _, rating := range []models.ValueRating{models.ValueCritical, models.ValueHigh, models.ValueMedium, models.ValueLow} {
+			if count := valueRatings[rating]; count > 0 {
+				review.ValueRatings = append(review.ValueRatings, models.ReportBreakdown{Label: string(rating), Entries: count})
+			}
+		}
+		for _, level := range []models.ImpactLevel{models.ImpactCompany, models.ImpactDepartment, models.ImpactTeam, models.ImpactPersonal} {
+			if count := impactLevels[level]; count > 0 {
+				review.ImpactLevels = append(review.ImpactLevels, models.ReportBreakdown{Label: string(level), Entries: count})
+			}
+		}
+
+		projects, err := qtx.GetTopProjectsByTime(ctx, store.GetTopProjectsByTimeParams{
+			UserID:      userUUID,
+			StartTime:   timeToPgTimestamptz(start),
+			StartTime_2: timeToPgTimestamptz(end),
+			Limit:       reportTopProjects,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get top projects: %w", err)
+		}
+		for _, project := range projects {
+			percentage, _ := project.Percentage.Float64Value()
+			review.TopProjects = append(review.TopProjects, models.ReportProject{
+				Name:       project.Name,
+				Color:      pgTextToStringRequired(project.Color),
+				Entries:    int(project.EntryCount),
+				Minutes:    int(project.TotalMinutes),
+				Percentage: percentage.Float64,
+			})
+		}
+
+		highlights, err := qtx.GetReportHighlights(ctx, store.GetReportHighlightsParams{
+			UserID:     userUUID,
+			StartTime:  timeToPgTimestamptz(start),
+			EndTime:    timeToPgTimestamptz(end),
+			MaxEntries: reportHighlights,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get report highlights: %w", err)
+		}
+		for _, row := range highlights {
+			review.Highlights = append(review.Highlights, reportHighlightToModel(row, loc))
+		}
+
+		narrative, err := qtx.GetReportNarrative(ctx, store.GetReportNarrativeParams{
+			UserID:      userUUID,
+			ReportType:  string(models.ReportPerformanceReview),
+			PeriodEnd:   timeToPgDate(&last),
+			PeriodStart: timeToPgDate(&first),
+		})
+		switch {
+		case database.NoRows(err):
+		case err != nil:
+			return fmt.Errorf("failed to get report narrative: %w", err)
+		default:
+			review.Narrative = reportNarrativeToModel(narrative)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// getReportTask loads a report task owned by the user
+func (s *ReportService) getReportTask(ctx context.Context, userID, reportID string) (store.Task, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetReport", "user_id", userID)
+		return store.Task{}, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	reportUUID, err := uuid.Parse(reportID)
+	if err != nil {
+		return store.Task{}, fmt.Errorf("invalid report ID: %w", err)
+	}
+
+	var task store.Task
+	err = s.db.Read(ctx, func(qtx *store.Queries) error {
+		task, err = qtx.GetTaskByID(ctx, reportUUID)
+		return err
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return store.Task{}, fmt.Errorf("report not found")
+		}
+		return store.Task{}, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	if owner := pgUUIDToUUID(task.UserID); owner == nil || *owner != userUUID || task.TaskType != string(models.TaskExportData) {
+		return store.Task{}, fmt.Errorf("report not found")
+	}
+	return task, nil
+}
+
+// validateReportPeriod checks the first and last day (YYYY-MM-DD) of a report
+func validateReportPeriod(startDate, endDate string) error {
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+	}
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return fmt.Errorf("end date must be on or after start date")
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxReportPeriodDays {
+		return fmt.Errorf("report period cannot exceed %d days", maxReportPeriodDays)
+	}
+	return nil
+}
+
+// reportWorkerTaskID names one rendering attempt of a report task, so a late result of
+// an abandoned attempt is never taken for the result of a retry
+func reportWorkerTaskID(task store.Task) string {
+	return fmt.Sprintf("export_%s_%d", task.ID, pgInt4ToInt(task.RetryCount))
+}
+
+// reportFileName names the downloaded file of a report
+func reportFileName(payload reportTaskPayload) string {
+	return fmt.Sprintf("englog-%s-%s-to-%s.pdf",
+		strings.ReplaceAll(string(payload.ReportType), "_", "-"), payload.StartDate, payload.EndDate)
+}
+
+// parseReportTaskResult decodes the result stored on a completed report task
+func parseReportTaskResult(raw []byte) (*reportTaskResult, error) {
+	var result reportTaskResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid report result: %w", err)
+	}
+	return &result, nil
+}
+
+// reportTaskToModel converts a report task to a ReportExport; a report past its
+// expiry counts as expired even before its file is removed
+func reportTaskToModel(task store.Task, now time.Time) *models.ReportExport {
+	report := &models.ReportExport{
+		ID:          task.ID,
+		Status:      models.TaskStatus(pgTextToStringRequired(task.Status)),
+		Error:       pgTextToString(task.ErrorMessage),
+		CreatedAt:   pgTimestamptzToTime(task.CreatedAt),
+		CompletedAt: pgTimestamptzToTimePtr(task.CompletedAt),
+	}
+
+	var payload reportTaskPayload
+	if err := json.Unmarshal(task.Payload, &payload); err == nil {
+		report.ReportType = payload.ReportType
+		report.PeriodStart = payload.StartDate
+		report.PeriodEnd = payload.EndDate
+	}
+
+	if report.Status != models.TaskCompleted {
+		return report
+	}
+	// A completed report keeps no error of earlier attempts
+	report.Error = nil
+
+	if result, err := parseReportTaskResult(task.Result); err == nil {
+		report.FileName = result.FileName
+		report.SizeBytes = result.SizeBytes
+		expiresAt := result.ExpiresAt
+		report.ExpiresAt = &expiresAt
+		report.Expired = result.Expired || result.StorageKey == "" || !now.Before(expiresAt)
+	}
+	return report
+}
+
+// reportHighlightToModel converts a highlight row, dating it in loc
+func reportHighlightToModel(row store.GetReportHighlightsRow, loc *time.Location) models.ReportHighlight {
+	return models.ReportHighlight{
+		Date:        pgTimestamptzToTime(row.StartTime).In(loc).Format(time.DateOnly),
+		Title:       row.Title,
+		Description: pgTextToStringRequired(row.Description),
+		Type:        models.ActivityType(row.Type),
+		ProjectName: pgTextToStringRequired(row.ProjectName),
+		ValueRating: models.ValueRating(row.ValueRating),
+		ImpactLevel: models.ImpactLevel(row.ImpactLevel),
+		Minutes:     pgInt4ToInt(row.DurationMinutes),
+	}
+}
+
+// reportNarrativeToModel converts a generated insight to a report narrative
+func reportNarrativeToModel(insight store.GeneratedInsight) *models.ReportNarrative {
+	narrative := &models.ReportNarrative{
+		Title:       insight.Title,
+		Summary:     pgTextToStringRequired(insight.Summary),
+		Content:     insight.Content,
+		GeneratedAt: pgTimestamptzToTime(insight.CreatedAt),
+	}
+	if start := pgDateToString(insight.PeriodStart); start != nil {
+		narrative.PeriodStart = *start
+	}
+	if end := pgDateToString(insight.PeriodEnd); end != nil {
+		narrative.PeriodEnd = *end
+	}
+	return narrative
+}
@@ -0,0 +1,190 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/blobstore"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/reports"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReportRenderer renders reports in-process the way a worker would
+type fakeReportRenderer struct {
+	mu      sync.Mutex
+	queued  map[string]*models.ReportRenderTask
+	results map[string]*models.ReportRenderResult
+}
+
+func newFakeReportRenderer() *fakeReportRenderer {
+	return &fakeReportRenderer{
+		queued:  make(map[string]*models.ReportRenderTask),
+		results: make(map[string]*models.ReportRenderResult),
+	}
+}
+
+func (r *fakeReportRenderer) CanRenderReports(ctx context.Context) bool { return true }
+
+func (r *fakeReportRenderer) QueueReportRender(ctx context.Context, workerTaskID, userID string, task *models.ReportRenderTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queued[workerTaskID] = task
+
+	var buf bytes.Buffer
+	if err := reports.RenderPerformanceReview(&buf, task.PerformanceReview); err != nil {
+		return err
+	}
+	r.results[workerTaskID] = &models.ReportRenderResult{FileName: task.FileName, ContentType: reports.ContentType, Data: buf.Bytes()}
+	return nil
+}
+
+func (r *fakeReportRenderer) TakeReportResult(ctx context.Context, workerTaskID string) (*models.ReportRenderResult, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result, ok := r.results[workerTaskID]
+	delete(r.results, workerTaskID)
+	return result, ok, nil
+}
+
+func (r *fakeReportRenderer) DiscardReportResult(workerTaskID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.results, workerTaskID)
+}
+
+// TestReportService_PerformanceReview tests requesting, rendering and downloading a performance review
+func TestReportService_PerformanceReview(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	renderer := newFakeReportRenderer()
+
+	analyticsService := services.NewAnalyticsService(db, testLogger)
+	reportService := services.NewReportService(db, testLogger, analyticsService, blobs, renderer).
+		WithReportSettings(time.Second, time.Hour)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	testUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "reports@example.com",
+		Password:  "password123",
+		FirstName: "Report",
+		LastName:  "User",
+		Timezone:  "America/Sao_Paulo",
+	})
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	otherUser, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "reports-other@example.com",
+		Password:  "password123",
+		FirstName: "Other",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Platform",
+		Color:  "#3498db",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	entries := []struct {
+		title  string
+		start  time.Time
+		value  models.ValueRating
+		impact models.ImpactLevel
+	}{
+		{"Billing migration", time.Date(2025, 3, 10, 9, 0, 0, 0, saoPaulo), models.ValueCritical, models.ImpactCompany},
+		{"Routine fixes", time.Date(2025, 3, 11, 9, 0, 0, 0, saoPaulo), models.ValueMedium, models.ImpactTeam},
+		{"Outside the period", time.Date(2025, 4, 1, 9, 0, 0, 0, saoPaulo), models.ValueHigh, models.ImpactTeam},
+	}
+	for _, e := range entries {
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       e.title,
+			Type:        models.ActivityDevelopment,
+			ProjectID:   &project.ID,
+			StartTime:   e.start,
+			EndTime:     e.start.Add(time.Hour),
+			ValueRating: e.value,
+			ImpactLevel: e.impact,
+		})
+		require.NoError(t, err)
+	}
+
+	report, err := reportService.RequestPerformanceReview(ctx, userID, &models.PerformanceReviewRequest{
+		StartDate: "2025-03-01",
+		EndDate:   "2025-03-31",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskPending, report.Status)
+
+	_, _, err = reportService.OpenReport(ctx, userID, report.ID.String())
+	assert.ErrorContains(t, err, "not ready")
+
+	// The first round dispatches the task, the second stores the rendered file
+	reportService.ProcessReports(ctx)
+	require.Len(t, renderer.queued, 1)
+	for _, task := range renderer.queued {
+		review := task.PerformanceReview
+		require.NotNil(t, review)
+		assert.Equal(t, "Report User", review.UserName)
+		assert.Equal(t, 2, review.TotalEntries, "entries outside the period are left out")
+		assert.Equal(t, 120, review.TotalMinutes)
+		require.Len(t, review.Highlights, 1)
+		assert.Equal(t, "Billing migration", review.Highlights[0].Title)
+		assert.Equal(t, "2025-03-10", review.Highlights[0].Date)
+		require.Len(t, review.TopProjects, 1)
+		assert.Equal(t, "Platform", review.TopProjects[0].Name)
+		assert.Nil(t, review.Narrative)
+	}
+	reportService.ProcessReports(ctx)
+
+	report, err = reportService.GetReport(ctx, userID, report.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskCompleted, report.Status)
+	assert.Equal(t, "englog-performance-review-2025-03-01-to-2025-03-31.pdf", report.FileName)
+	assert.True(t, report.Downloadable())
+
+	opened, content, err := reportService.OpenReport(ctx, userID, report.ID.String())
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.Equal(t, opened.SizeBytes, int64(len(data)))
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+
+	listed, err := reportService.ListReports(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, report.ID, listed[0].ID)
+
+	_, err = reportService.GetReport(ctx, otherUser.ID.String(), report.ID.String())
+	assert.ErrorContains(t, err, "report not found", "reports are private")
+
+	otherReports, err := reportService.ListReports(ctx, otherUser.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, otherReports)
+}
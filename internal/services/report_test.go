@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReportPeriod(t *testing.T) {
+	assert.NoError(t, validateReportPeriod("2025-03-01", "2025-03-01"), "a single day is a valid period")
+	assert.NoError(t, validateReportPeriod("2024-01-01", "2024-12-31"), "a leap year fits")
+
+	assert.ErrorContains(t, validateReportPeriod("03/01/2025", "2025-03-31"), "invalid start date")
+	assert.ErrorContains(t, validateReportPeriod("2025-03-01", "2025-02-30"), "invalid end date")
+	assert.ErrorContains(t, validateReportPeriod("2025-03-02", "2025-03-01"), "on or after start date")
+	assert.ErrorContains(t, validateReportPeriod("2024-01-01", "2025-01-01"), "cannot exceed 366 days")
+}
+
+func TestReportNaming(t *testing.T) {
+	task := store.Task{ID: uuid.MustParse("3f1c9a52-8d0e-4b7a-9c61-2e5d4f8a7b10"), RetryCount: pgtype.Int4{Int32: 1, Valid: true}}
+	assert.Equal(t, "export_3f1c9a52-8d0e-4b7a-9c61-2e5d4f8a7b10_1", reportWorkerTaskID(task), "each attempt gets its own worker task")
+
+	assert.Equal(t, "englog-performance-review-2025-01-01-to-2025-06-30.pdf", reportFileName(reportTaskPayload{
+		ReportType: models.ReportPerformanceReview,
+		StartDate:  "2025-01-01",
+		EndDate:    "2025-06-30",
+	}))
+}
+
+func TestReportTaskToModel(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	payload, err := json.Marshal(reportTaskPayload{ReportType: models.ReportPerformanceReview, StartDate: "2025-01-01", EndDate: "2025-06-30"})
+	require.NoError(t, err)
+
+	task := func(status string, result reportTaskResult) store.Task {
+		raw, err := json.Marshal(result)
+		require.NoError(t, err)
+		return store.Task{
+			ID:           uuid.New(),
+			Status:       pgtype.Text{String: status, Valid: true},
+			Payload:      payload,
+			Result:       raw,
+			ErrorMessage: pgtype.Text{String: "worker went away", Valid: true},
+			CreatedAt:    pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+		}
+	}
+
+	t.Run("Pending", func(t *testing.T) {
+		report := reportTaskToModel(task(string(models.TaskPending), reportTaskResult{}), now)
+		assert.Equal(t, models.ReportPerformanceReview, report.ReportType)
+		assert.Equal(t, "2025-01-01", report.PeriodStart)
+		assert.Equal(t, "2025-06-30", report.PeriodEnd)
+		assert.Nil(t, report.ExpiresAt)
+		require.NotNil(t, report.Error, "the error of a failed attempt is shown while retrying")
+		assert.False(t, report.Downloadable())
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		report := reportTaskToModel(task(string(models.TaskCompleted), reportTaskResult{
+			FileName:   "review.pdf",
+			SizeBytes:  2048,
+			StorageKey: "reports/u/t",
+			ExpiresAt:  now.Add(time.Hour),
+		}), now)
+		assert.Equal(t, "review.pdf", report.FileName)
+		assert.Equal(t, int64(2048), report.SizeBytes)
+		assert.Nil(t, report.Error)
+		assert.False(t, report.Expired)
+		assert.True(t, report.Downloadable())
+	})
+
+	t.Run("PastExpiry", func(t *testing.T) {
+		report := reportTaskToModel(task(string(models.TaskCompleted), reportTaskResult{
+			StorageKey: "reports/u/t",
+			ExpiresAt:  now.Add(-time.Minute),
+		}), now)
+		assert.True(t, report.Expired, "expired before the file is removed")
+		assert.False(t, report.Downloadable())
+	})
+
+	t.Run("FileRemoved", func(t *testing.T) {
+		report := reportTaskToModel(task(string(models.TaskCompleted), reportTaskResult{
+			ExpiresAt: now.Add(-time.Hour),
+			Expired:   true,
+		}), now)
+		assert.True(t, report.Expired)
+	})
+}
+
+func TestReportService_RejectsInvalidInput(t *testing.T) {
+	service := NewReportService(nil, logging.NewTestLogger(), nil, nil, nil)
+	ctx := context.Background()
+	req := &models.PerformanceReviewRequest{StartDate: "2025-01-01", EndDate: "2025-01-31"}
+
+	_, err := service.RequestPerformanceReview(ctx, "not-a-uuid", req)
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	_, err = service.RequestPerformanceReview(ctx, uuid.NewString(), &models.PerformanceReviewRequest{StartDate: "2025-02-01", EndDate: "2025-01-01"})
+	assert.ErrorContains(t, err, "on or after start date")
+
+	_, err = service.RequestPerformanceReview(ctx, uuid.NewString(), req)
+	assert.ErrorContains(t, err, "not available", "reports need a renderer")
+
+	_, err = service.GetReport(ctx, uuid.NewString(), "not-a-uuid")
+	assert.ErrorContains(t, err, "invalid report ID")
+}
@@ -69,6 +69,14 @@ func pgTimestamptzToTime(pgTime pgtype.Timestamptz) time.Time {
 	return pgTime.Time
 }
 
+// pgTimestamptzToTimePtr converts pgtype.Timestamptz to *time.Time
+func pgTimestamptzToTimePtr(pgTime pgtype.Timestamptz) *time.Time {
+	if !pgTime.Valid {
+		return nil
+	}
+	return &pgTime.Time
+}
+
 // pgInt4ToInt converts pgtype.Int4 to int
 func pgInt4ToInt(pgInt pgtype.Int4) int {
 	if !pgInt.Valid {
@@ -106,6 +114,22 @@ func pgDateToTime(pgDate pgtype.Date) *time.Time {
 	return &pgDate.Time
 }
 
+// float64ToPgFloat8 converts *float64 to pgtype.Float8
+func float64ToPgFloat8(f *float64) pgtype.Float8 {
+	if f == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *f, Valid: true}
+}
+
+// pgFloat8ToFloat64 converts pgtype.Float8 to *float64
+func pgFloat8ToFloat64(pgFloat pgtype.Float8) *float64 {
+	if !pgFloat.Valid {
+		return nil
+	}
+	return &pgFloat.Float64
+}
+
 // boolToPgBool converts bool to pgtype.Bool
 func boolToPgBool(b bool) pgtype.Bool {
 	return pgtype.Bool{Bool: b, Valid: true}
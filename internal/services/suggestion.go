@@ -0,0 +1,467 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// entrySuggestionTimeout bounds how long a request waits for a worker before falling back
+	entrySuggestionTimeout = 5 * time.Second
+	// suggestionCacheTTL is how long a worker suggestion is reused for the same draft
+	suggestionCacheTTL = 10 * time.Minute
+	// suggestionCacheSize bounds the number of cached worker suggestions
+	suggestionCacheSize = 1000
+	// suggestionVocabularySize bounds how many of the user's most used tags are sent to the worker
+	suggestionVocabularySize = 100
+	// maxSuggestedTags bounds the number of tags in one suggestion
+	maxSuggestedTags = 5
+)
+
+// EntrySuggester runs an entry suggestion task on a worker and returns its JSON result
+type EntrySuggester interface {
+	SuggestEntry(ctx context.Context, userID string, payload any) (string, error)
+}
+
+// entrySuggestionTask is the worker payload of an entry suggestion
+type entrySuggestionTask struct {
+	UserID       string                  `json:"user_id"`
+	Title        string                  `json:"title"`
+	Description  string                  `json:"description,omitempty"`
+	Tags         []string                `json:"tags"`
+	Projects     []entrySuggestionOption `json:"projects"`
+	Types        []models.ActivityType   `json:"types"`
+	ValueRatings []models.ValueRating    `json:"value_ratings"`
+	ImpactLevels []models.ImpactLevel    `json:"impact_levels"`
+}
+
+// entrySuggestionOption is a project the worker may pick
+type entrySuggestionOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// entrySuggestionResult is the worker result of an entry suggestion
+type entrySuggestionResult struct {
+	Tags        []string `json:"tags"`
+	Type        string   `json:"type"`
+	ProjectID   string   `json:"project_id"`
+	ValueRating string   `json:"value_rating"`
+	ImpactLevel string   `json:"impact_level"`
+}
+
+// activityKeywords maps words of a draft to the activity they hint at; earlier rows win ties
+var activityKeywords = []struct {
+	activity models.ActivityType
+	keywords []string
+}{
+	{models.ActivityCodeReview, []string{"review", "reviews", "reviewed", "reviewing", "pr", "prs", "pull request", "code review"}},
+	{models.ActivityDebugging, []string{"debug", "debugging", "bug", "bugs", "fix", "fixed", "fixing", "crash", "regression", "flaky", "investigate", "investigating"}},
+	{models.ActivityMeeting, []string{"meeting", "meetings", "sync", "standup", "stand-up", "1:1", "1on1", "one-on-one", "retro", "retrospective", "call", "interview", "demo"}},
+	{models.ActivityTesting, []string{"test", "tests", "testing", "qa", "e2e", "coverage"}},
+	{models.ActivityDeployment, []string{"deploy", "deployed", "deploying", "deployment", "release", "released", "rollout", "rollback", "ship", "shipped"}},
+	{models.ActivityDocumentation, []string{"doc", "docs", "documentation", "document", "readme", "wiki", "adr", "runbook", "writeup", "write-up"}},
+	{models.ActivityResearch, []string{"research", "spike", "poc", "prototype", "evaluate", "evaluating", "explore", "exploring"}},
+	{models.ActivityPlanning, []string{"plan", "planning", "roadmap", "estimate", "estimation", "grooming", "refinement", "backlog", "okr", "okrs"}},
+	{models.ActivityLearning, []string{"learn", "learning", "course", "tutorial", "training", "workshop", "book", "reading", "conference"}},
+	{models.ActivityMaintenance, []string{"upgrade", "upgraded", "bump", "refactor", "refactoring", "cleanup", "clean up", "migrate", "migration", "dependency", "dependencies", "tech debt"}},
+	{models.ActivitySupport, []string{"support", "oncall", "on-call", "incident", "outage", "ticket", "customer", "escalation", "pager"}},
+	{models.ActivityDevelopment, []string{"implement", "implemented", "implementing", "feature", "build", "building", "add", "added", "endpoint", "api", "develop", "developing"}},
+}
+
+// activityRatings holds the value and impact usually logged for each activity
+var activityRatings = map[models.ActivityType]struct {
+	value  models.ValueRating
+	impact models.ImpactLevel
+}{
+	models.ActivityDevelopment:   {models.ValueHigh, models.ImpactTeam},
+	models.ActivityMeeting:       {models.ValueMedium, models.ImpactTeam},
+	models.ActivityCodeReview:    {models.ValueMedium, models.ImpactTeam},
+	models.ActivityDebugging:     {models.ValueHigh, models.ImpactTeam},
+	models.ActivityDocumentation: {models.ValueMedium, models.ImpactTeam},
+	models.ActivityTesting:       {models.ValueMedium, models.ImpactTeam},
+	models.ActivityDeployment:    {models.ValueHigh, models.ImpactDepartment},
+	models.ActivityResearch:      {models.ValueMedium, models.ImpactPersonal},
+	models.ActivityPlanning:      {models.ValueMedium, models.ImpactTeam},
+	models.ActivityLearning:      {models.ValueMedium, models.ImpactPersonal},
+	models.ActivityMaintenance:   {models.ValueLow, models.ImpactTeam},
+	models.ActivitySupport:       {models.ValueMedium, models.ImpactTeam},
+	models.ActivityOther:         {models.ValueMedium, models.ImpactPersonal},
+}
+
+var (
+	// criticalKeywords raise the suggested value rating to critical
+	criticalKeywords = []string{"critical", "outage", "incident", "security", "urgent", "sev1", "sev-1", "p0"}
+	// companyKeywords raise the suggested impact level to company
+	companyKeywords = []string{"company", "company-wide", "all-hands", "all hands", "customers", "org-wide"}
+)
+
+// SuggestionService suggests tags, type, project and ratings for new log entries
+type SuggestionService struct {
+	logger         *logging.Logger
+	tagService     *TagService
+	projectService *ProjectService
+	worker         EntrySuggester
+	cache          *suggestionCache
+}
+
+// NewSuggestionService creates a new SuggestionService instance.
+// worker may be nil, in which case only the heuristic suggestions are used.
+func NewSuggestionService(logger *logging.Logger, tagService *TagService, projectService *ProjectService, worker EntrySuggester) *SuggestionService {
+	return &SuggestionService{
+		logger:         logger.WithComponent("suggestion_service"),
+		tagService:     tagService,
+		projectService: projectService,
+		worker:         worker,
+		cache:          newSuggestionCache(suggestionCacheTTL, suggestionCacheSize),
+	}
+}
+
+// SuggestLogEntry suggests metadata for a draft log entry.
+// A connected worker is asked first; its answers are cached and checked against the
+// user's vocabulary. Without a worker, or when it fails or is slow, keyword heuristics are used.
+func (s *SuggestionService) SuggestLogEntry(ctx context.Context, userID string, req *models.LogEntrySuggestionRequest) (*models.LogEntrySuggestion, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if len(title) > 500 {
+		return nil, fmt.Errorf("title must be at most 500 characters")
+	}
+	var description string
+	if req.Description != nil {
+		description = strings.TrimSpace(*req.Description)
+	}
+	if len(description) > 5000 {
+		return nil, fmt.Errorf("description must be at most 5000 characters")
+	}
+
+	key := suggestionCacheKey(userID, title, description)
+	if cached, ok := s.cache.get(key); ok {
+		cached.Cached = true
+		return cached, nil
+	}
+
+	usage, err := s.tagService.GetUserTagUsage(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectService.GetActiveProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabulary := make([]string, len(usage))
+	for i, u := range usage {
+		vocabulary[i] = u.Tag.Name
+	}
+
+	heuristic := suggestHeuristically(title, description, vocabulary, projects)
+	if s.worker == nil {
+		return heuristic, nil
+	}
+
+	task := newEntrySuggestionTask(userID, title, description, vocabulary, projects)
+
+	workerCtx, cancel := context.WithTimeout(ctx, entrySuggestionTimeout)
+	defer cancel()
+
+	raw, err := s.worker.SuggestEntry(workerCtx, userID, task)
+	if err != nil {
+		s.logger.LogDebug(ctx, "Worker suggestion unavailable, using heuristics",
+			logging.OperationField, "suggest_log_entry",
+			"user_id", userID,
+			logging.ErrorField, err)
+		return heuristic, nil
+	}
+
+	var result entrySuggestionResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		s.logger.LogWarn(ctx, "Invalid worker suggestion, using heuristics",
+			logging.OperationField, "suggest_log_entry",
+			"user_id", userID,
+			logging.ErrorField, err)
+		return heuristic, nil
+	}
+
+	suggestion := mergeWorkerSuggestion(&result, heuristic, vocabulary, projects)
+	s.cache.put(key, suggestion)
+
+	return suggestion, nil
+}
+
+// newEntrySuggestionTask builds the worker payload, offering only the most used tags
+func newEntrySuggestionTask(userID, title, description string, vocabulary []string, projects []*models.Project) *entrySuggestionTask {
+	if len(vocabulary) > suggestionVocabularySize {
+		vocabulary = vocabulary[:suggestionVocabularySize]
+	}
+
+	options := make([]entrySuggestionOption, len(projects))
+	for i, p := range projects {
+		options[i] = entrySuggestionOption{ID: p.ID.String(), Name: p.Name}
+	}
+
+	task := &entrySuggestionTask{
+		UserID:       userID,
+		Title:        title,
+		Description:  description,
+		Tags:         vocabulary,
+		Projects:     options,
+		ValueRatings: []models.ValueRating{models.ValueLow, models.ValueMedium, models.ValueHigh, models.ValueCritical},
+		ImpactLevels: []models.ImpactLevel{models.ImpactPersonal, models.ImpactTeam, models.ImpactDepartment, models.ImpactCompany},
+	}
+	for _, row := range activityKeywords {
+		task.Types = append(task.Types, row.activity)
+	}
+	task.Types = append(task.Types, models.ActivityOther)
+
+	return task
+}
+
+// suggestHeuristically derives a suggestion from keywords in the title and description
+func suggestHeuristically(title, description string, vocabulary []string, projects []*models.Project) *models.LogEntrySuggestion {
+	titleText := strings.ToLower(title)
+	text := titleText + "\n" + strings.ToLower(description)
+
+	suggestion := &models.LogEntrySuggestion{
+		Tags:   []string{},
+		Type:   models.ActivityDevelopment,
+		Source: models.SuggestionSourceHeuristic,
+	}
+
+	for _, name := range vocabulary {
+		if len(suggestion.Tags) == maxSuggestedTags {
+			break
+		}
+		if containsTerm(text, strings.ToLower(name)) {
+			suggestion.Tags = append(suggestion.Tags, name)
+		}
+	}
+
+	// Title words count double, the description often mentions side activities
+	bestScore := 0
+	for _, row := range activityKeywords {
+		score := 0
+		for _, keyword := range row.keywords {
+			if containsTerm(titleText, keyword) {
+				score += 2
+			} else if containsTerm(text, keyword) {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestScore = score
+			suggestion.Type = row.activity
+		}
+	}
+
+	ratings := activityRatings[suggestion.Type]
+	suggestion.ValueRating = ratings.value
+	suggestion.ImpactLevel = ratings.impact
+	if containsAnyTerm(text, criticalKeywords) {
+		suggestion.ValueRating = models.ValueCritical
+	}
+	if containsAnyTerm(text, companyKeywords) {
+		suggestion.ImpactLevel = models.ImpactCompany
+	}
+
+	// The longest project name mentioned wins, otherwise the default project
+	var matched *models.Project
+	for _, p := range projects {
+		if containsTerm(text, strings.ToLower(p.Name)) && (matched == nil || len(p.Name) > len(matched.Name)) {
+			matched = p
+		}
+	}
+	if matched == nil {
+		for _, p := range projects {
+			if p.IsDefault {
+				matched = p
+				break
+			}
+		}
+	}
+	if matched != nil {
+		id := matched.ID
+		suggestion.ProjectID = &id
+	}
+
+	return suggestion
+}
+
+// mergeWorkerSuggestion keeps the worker's answers that fit the user's data and
+// fills the rest from the heuristic suggestion
+func mergeWorkerSuggestion(result *entrySuggestionResult, heuristic *models.LogEntrySuggestion, vocabulary []string, projects []*models.Project) *models.LogEntrySuggestion {
+	suggestion := &models.LogEntrySuggestion{
+		Tags:        []string{},
+		Type:        models.ActivityType(result.Type),
+		ValueRating: models.ValueRating(result.ValueRating),
+		ImpactLevel: models.ImpactLevel(result.ImpactLevel),
+		Source:      models.SuggestionSourceWorker,
+	}
+
+	canonical := make(map[string]string, len(vocabulary))
+	for _, name := range vocabulary {
+		canonical[strings.ToLower(name)] = name
+	}
+	seen := make(map[string]bool)
+	for _, tag := range result.Tags {
+		name, ok := canonical[strings.ToLower(strings.TrimSpace(tag))]
+		if !ok || seen[name] || len(suggestion.Tags) == maxSuggestedTags {
+			continue
+		}
+		seen[name] = true
+		suggestion.Tags = append(suggestion.Tags, name)
+	}
+	if len(suggestion.Tags) == 0 {
+		suggestion.Tags = heuristic.Tags
+	}
+
+	if !suggestion.Type.IsValid() {
+		suggestion.Type = heuristic.Type
+	}
+	if !suggestion.ValueRating.IsValid() {
+		suggestion.ValueRating = heuristic.ValueRating
+	}
+	if !suggestion.ImpactLevel.IsValid() {
+		suggestion.ImpactLevel = heuristic.ImpactLevel
+	}
+
+	suggestion.ProjectID = heuristic.ProjectID
+	if projectID, err := uuid.Parse(result.ProjectID); err == nil {
+		for _, p := range projects {
+			if p.ID == projectID {
+				suggestion.ProjectID = &projectID
+				break
+			}
+		}
+	}
+
+	return suggestion
+}
+
+// containsTerm reports whether term occurs in text as whole words
+func containsTerm(text, term string) bool {
+	if term == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(term)
+		if !isWordByteAt(text, start-1) && !isWordByteAt(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// containsAnyTerm reports whether any of the terms occurs in text as whole words
+func containsAnyTerm(text string, terms []string) bool {
+	for _, term := range terms {
+		if containsTerm(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordByteAt reports whether the byte at i is part of a word; out of range is not
+func isWordByteAt(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	c := rune(text[i])
+	return c >= 0x80 || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// suggestionCacheKey identifies a draft of one user regardless of case and surrounding space
+func suggestionCacheKey(userID, title, description string) string {
+	sum := sha256.Sum256([]byte(userID + "\x00" + strings.ToLower(title) + "\x00" + strings.ToLower(description)))
+	return hex.EncodeToString(sum[:])
+}
+
+// suggestionCache is a small in-memory TTL cache of worker suggestions
+type suggestionCache struct {
+	mu      sync.Mutex
+	entries map[string]suggestionCacheEntry
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+}
+
+type suggestionCacheEntry struct {
+	suggestion models.LogEntrySuggestion
+	expiresAt  time.Time
+}
+
+func newSuggestionCache(ttl time.Duration, size int) *suggestionCache {
+	return &suggestionCache{
+		entries: make(map[string]suggestionCacheEntry),
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+	}
+}
+
+// get returns a copy of a cached suggestion that has not expired
+func (c *suggestionCache) get(key string) (*models.LogEntrySuggestion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	suggestion := entry.suggestion
+	suggestion.Tags = append([]string{}, entry.suggestion.Tags...)
+	return &suggestion, true
+}
+
+// put stores a suggestion, dropping expired entries and then arbitrary ones when full
+func (c *suggestionCache) put(key string, suggestion *models.LogEntrySuggestion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= c.size {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries) < c.size {
+			break
+		}
+		delete(c.entries, k)
+	}
+
+	stored := *suggestion
+	stored.Tags = append([]string{}, suggestion.Tags...)
+	c.entries[key] = suggestionCacheEntry{suggestion: stored, expiresAt: now.Add(c.ttl)}
+}
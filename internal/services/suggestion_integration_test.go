@@ -0,0 +1,119 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSuggester answers entry suggestion tasks like a worker would
+type fakeSuggester struct {
+	result string
+	err    error
+	calls  int
+}
+
+func (f *fakeSuggester) SuggestEntry(ctx context.Context, userID string, payload any) (string, error) {
+	f.calls++
+	return f.result, f.err
+}
+
+// TestSuggestionServiceIntegration tests worker and heuristic suggestions against a user's vocabulary
+func TestSuggestionServiceIntegration(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	tagService := services.NewTagService(db, testLogger)
+	projectService := services.NewProjectService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "suggestions@example.com",
+		Password:  "password123",
+		FirstName: "Sugg",
+		LastName:  "Ester",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	project, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+		Name:   "Billing",
+		Color:  "#FF5733",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	_, err = logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+		Title:       "Invoice export",
+		Type:        models.ActivityDevelopment,
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		ValueRating: models.ValueHigh,
+		ImpactLevel: models.ImpactTeam,
+		Tags:        []string{"payments", "go"},
+	})
+	require.NoError(t, err)
+
+	draft := &models.LogEntrySuggestionRequest{Title: "Debug payments retry in Billing"}
+
+	t.Run("HeuristicsWithoutWorker", func(t *testing.T) {
+		service := services.NewSuggestionService(testLogger, tagService, projectService, nil)
+
+		s, err := service.SuggestLogEntry(ctx, userID, draft)
+		require.NoError(t, err)
+		assert.Equal(t, models.SuggestionSourceHeuristic, s.Source)
+		assert.Equal(t, []string{"payments"}, s.Tags)
+		assert.Equal(t, models.ActivityDebugging, s.Type)
+		require.NotNil(t, s.ProjectID)
+		assert.Equal(t, project.ID, *s.ProjectID)
+	})
+
+	t.Run("WorkerResultsAreCheckedAndCached", func(t *testing.T) {
+		worker := &fakeSuggester{result: `{"tags": ["GO", "kubernetes"], "type": "maintenance", "project_id": "` +
+			project.ID.String() + `", "value_rating": "low", "impact_level": "personal"}`}
+		service := services.NewSuggestionService(testLogger, tagService, projectService, worker)
+
+		s, err := service.SuggestLogEntry(ctx, userID, draft)
+		require.NoError(t, err)
+		assert.Equal(t, models.SuggestionSourceWorker, s.Source)
+		assert.False(t, s.Cached)
+		assert.Equal(t, []string{"go"}, s.Tags, "tags outside the vocabulary are dropped")
+		assert.Equal(t, models.ActivityMaintenance, s.Type)
+		assert.Equal(t, models.ValueLow, s.ValueRating)
+
+		s, err = service.SuggestLogEntry(ctx, userID, draft)
+		require.NoError(t, err)
+		assert.True(t, s.Cached)
+		assert.Equal(t, 1, worker.calls)
+	})
+
+	t.Run("WorkerFailureFallsBack", func(t *testing.T) {
+		worker := &fakeSuggester{err: errors.New("no active worker with the required capability")}
+		service := services.NewSuggestionService(testLogger, tagService, projectService, worker)
+
+		s, err := service.SuggestLogEntry(ctx, userID, draft)
+		require.NoError(t, err)
+		assert.Equal(t, models.SuggestionSourceHeuristic, s.Source)
+
+		// Heuristic answers are not cached so a worker can answer next time
+		_, err = service.SuggestLogEntry(ctx, userID, draft)
+		require.NoError(t, err)
+		assert.Equal(t, 2, worker.calls)
+	})
+}
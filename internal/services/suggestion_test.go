@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainsTerm(t *testing.T) {
+	assert.True(t, containsTerm("fix the go build", "go"))
+	assert.True(t, containsTerm("k8s/networking tweaks", "k8s/networking"))
+	assert.True(t, containsTerm("k8s/networking tweaks", "k8s"))
+	assert.True(t, containsTerm("weekly 1:1 with lead", "1:1"))
+	assert.False(t, containsTerm("good progress", "go"), "partial words do not match")
+	assert.False(t, containsTerm("mongo upgrade", "go"))
+	assert.True(t, containsTerm("mongo and go", "go"), "later whole-word occurrences are found")
+	assert.False(t, containsTerm("anything", ""))
+}
+
+func TestSuggestHeuristically(t *testing.T) {
+	platform := &models.Project{ID: uuid.New(), Name: "Platform"}
+	platformAPI := &models.Project{ID: uuid.New(), Name: "Platform API"}
+	inbox := &models.Project{ID: uuid.New(), Name: "Inbox", IsDefault: true}
+	projects := []*models.Project{platform, platformAPI, inbox}
+	vocabulary := []string{"go", "auth", "ci", "k8s"}
+
+	t.Run("debugging with tags and project", func(t *testing.T) {
+		s := suggestHeuristically("Fix flaky auth test", "Only fails on CI for the Platform API", vocabulary, projects)
+		assert.Equal(t, models.SuggestionSourceHeuristic, s.Source)
+		assert.Equal(t, models.ActivityDebugging, s.Type, "title keywords outweigh the description")
+		assert.Equal(t, []string{"auth", "ci"}, s.Tags)
+		require.NotNil(t, s.ProjectID)
+		assert.Equal(t, platformAPI.ID, *s.ProjectID, "the longest project name wins")
+		assert.Equal(t, models.ValueHigh, s.ValueRating)
+		assert.Equal(t, models.ImpactTeam, s.ImpactLevel)
+	})
+
+	t.Run("meeting falls back to the default project", func(t *testing.T) {
+		s := suggestHeuristically("Weekly 1:1 with manager", "", vocabulary, projects)
+		assert.Equal(t, models.ActivityMeeting, s.Type)
+		assert.Empty(t, s.Tags)
+		require.NotNil(t, s.ProjectID)
+		assert.Equal(t, inbox.ID, *s.ProjectID)
+	})
+
+	t.Run("critical and company keywords raise ratings", func(t *testing.T) {
+		s := suggestHeuristically("Outage postmortem", "Customers were affected company-wide", vocabulary, nil)
+		assert.Equal(t, models.ValueCritical, s.ValueRating)
+		assert.Equal(t, models.ImpactCompany, s.ImpactLevel)
+		assert.Nil(t, s.ProjectID)
+	})
+
+	t.Run("unknown text defaults to development", func(t *testing.T) {
+		s := suggestHeuristically("Misc", "", nil, nil)
+		assert.Equal(t, models.ActivityDevelopment, s.Type)
+		assert.NotNil(t, s.Tags)
+	})
+
+	t.Run("tags are capped", func(t *testing.T) {
+		words := []string{"a1", "a2", "a3", "a4", "a5", "a6"}
+		s := suggestHeuristically("a1 a2 a3 a4 a5 a6", "", words, nil)
+		assert.Len(t, s.Tags, maxSuggestedTags)
+	})
+}
+
+func TestMergeWorkerSuggestion(t *testing.T) {
+	project := &models.Project{ID: uuid.New(), Name: "Platform"}
+	fallbackProject := uuid.New()
+	heuristic := &models.LogEntrySuggestion{
+		Tags:        []string{"ci"},
+		Type:        models.ActivityTesting,
+		ProjectID:   &fallbackProject,
+		ValueRating: models.ValueMedium,
+		ImpactLevel: models.ImpactTeam,
+	}
+	vocabulary := []string{"Go", "ci", "auth"}
+
+	t.Run("valid answers are kept", func(t *testing.T) {
+		s := mergeWorkerSuggestion(&entrySuggestionResult{
+			Tags:        []string{"go", "auth", "GO", "unknown"},
+			Type:        "debugging",
+			ProjectID:   project.ID.String(),
+			ValueRating: "high",
+			ImpactLevel: "company",
+		}, heuristic, vocabulary, []*models.Project{project})
+
+		assert.Equal(t, models.SuggestionSourceWorker, s.Source)
+		assert.Equal(t, []string{"Go", "auth"}, s.Tags, "tags are mapped onto the vocabulary and deduplicated")
+		assert.Equal(t, models.ActivityDebugging, s.Type)
+		require.NotNil(t, s.ProjectID)
+		assert.Equal(t, project.ID, *s.ProjectID)
+		assert.Equal(t, models.ValueHigh, s.ValueRating)
+		assert.Equal(t, models.ImpactCompany, s.ImpactLevel)
+	})
+
+	t.Run("invalid answers fall back to heuristics", func(t *testing.T) {
+		s := mergeWorkerSuggestion(&entrySuggestionResult{
+			Tags:        []string{"made-up"},
+			Type:        "napping",
+			ProjectID:   uuid.New().String(),
+			ValueRating: "enormous",
+			ImpactLevel: "",
+		}, heuristic, vocabulary, []*models.Project{project})
+
+		assert.Equal(t, models.SuggestionSourceWorker, s.Source)
+		assert.Equal(t, []string{"ci"}, s.Tags)
+		assert.Equal(t, models.ActivityTesting, s.Type)
+		assert.Equal(t, &fallbackProject, s.ProjectID, "projects outside the user's list are ignored")
+		assert.Equal(t, models.ValueMedium, s.ValueRating)
+		assert.Equal(t, models.ImpactTeam, s.ImpactLevel)
+	})
+}
+
+func TestSuggestionCache(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	cache := newSuggestionCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", &models.LogEntrySuggestion{Tags: []string{"go"}, Type: models.ActivityDevelopment})
+
+	got, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, models.ActivityDevelopment, got.Type)
+	got.Tags[0] = "changed"
+	again, _ := cache.get("a")
+	assert.Equal(t, []string{"go"}, again.Tags, "callers get copies")
+
+	cache.put("b", &models.LogEntrySuggestion{})
+	cache.put("c", &models.LogEntrySuggestion{})
+	assert.Len(t, cache.entries, 2, "the cache is bounded")
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.get("c")
+	assert.False(t, ok, "entries expire")
+}
+
+func TestSuggestionCacheKey(t *testing.T) {
+	assert.Equal(t, suggestionCacheKey("u", "Fix Login", ""), suggestionCacheKey("u", "fix login", ""))
+	assert.NotEqual(t, suggestionCacheKey("u", "fix login", ""), suggestionCacheKey("other", "fix login", ""))
+	assert.NotEqual(t, suggestionCacheKey("u", "fix", "login"), suggestionCacheKey("u", "fix login", ""))
+}
+
+func TestSuggestionService_SuggestLogEntryValidation(t *testing.T) {
+	service := NewSuggestionService(logging.NewTestLogger(), nil, nil, nil)
+	ctx := context.Background()
+	userID := uuid.New().String()
+
+	_, err := service.SuggestLogEntry(ctx, "not-a-uuid", &models.LogEntrySuggestionRequest{Title: "Fix login"})
+	assert.ErrorContains(t, err, "invalid user ID")
+
+	_, err = service.SuggestLogEntry(ctx, userID, &models.LogEntrySuggestionRequest{Title: "   "})
+	assert.ErrorContains(t, err, "title is required")
+
+	t.Run("cached suggestions skip the lookups", func(t *testing.T) {
+		service.cache.put(suggestionCacheKey(userID, "Fix login", ""), &models.LogEntrySuggestion{
+			Type:   models.ActivityDebugging,
+			Source: models.SuggestionSourceWorker,
+		})
+
+		s, err := service.SuggestLogEntry(ctx, userID, &models.LogEntrySuggestionRequest{Title: "  Fix login "})
+		require.NoError(t, err)
+		assert.True(t, s.Cached)
+		assert.Equal(t, models.ActivityDebugging, s.Type)
+	})
+}
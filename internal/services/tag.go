@@ -14,6 +14,9 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// defaultTagColor is used for tags created implicitly from log entries
+const defaultTagColor = "#3B82F6"
+
 // TagService handles all business logic for tags
 type TagService struct {
 	db     *database.DB
@@ -28,24 +31,39 @@ func NewTagService(db *database.DB, logger *logging.Logger) *TagService {
 	}
 }
 
-// CreateTag creates a new tag
-func (s *TagService) CreateTag(ctx context.Context, req *models.TagRequest) (*models.Tag, error) {
+// CreateTag creates a new tag owned by the user
+func (s *TagService) CreateTag(ctx context.Context, userID string, req *models.TagRequest) (*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateTag", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	// Validate request
 	if err := s.validateTagRequest(req); err != nil {
 		s.logger.LogError(ctx, err, "Tag validation failed", "tag_name", req.Name)
 		return nil, err
 	}
 
-	s.logger.Info("Creating new tag", "tag_name", req.Name, "color", req.Color)
+	s.logger.Info("Creating new tag", "user_id", userID, "tag_name", req.Name, "color", req.Color)
 
 	var tag *models.Tag
 
 	// Start write transaction to create tag
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := checkTagNameAvailable(ctx, qtx, userUUID, uuid.Nil, req.Name); err != nil {
+			return err
+		}
+		if err := validateTagParent(ctx, qtx, &userUUID, nil, req.ParentID); err != nil {
+			return err
+		}
+
 		sqlcTag, err := qtx.CreateTag(ctx, store.CreateTagParams{
 			Name:        req.Name,
 			Color:       stringToPgText(&req.Color),
 			Description: stringToPgText(req.Description),
+			UserID:      uuidToPgUUID(&userUUID),
+			ParentID:    uuidToPgUUID(req.ParentID),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create tag: %w", err)
@@ -62,8 +80,14 @@ func (s *TagService) CreateTag(ctx context.Context, req *models.TagRequest) (*mo
 	return tag, nil
 }
 
-// GetTag retrieves a single tag by ID
-func (s *TagService) GetTag(ctx context.Context, tagID string) (*models.Tag, error) {
+// GetTag retrieves a single tag by ID. Users can see their own and shared tags.
+func (s *TagService) GetTag(ctx context.Context, userID, tagID string) (*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTag", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	tagUUID, err := uuid.Parse(tagID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
@@ -76,7 +100,10 @@ func (s *TagService) GetTag(ctx context.Context, tagID string) (*models.Tag, err
 
 	// Read operation to get tag
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcTag, err := qtx.GetTagByID(ctx, tagUUID)
+		sqlcTag, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+			ID:     tagUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
 		if err != nil {
 			return fmt.Errorf("failed to get tag: %w", err)
 		}
@@ -96,15 +123,30 @@ func (s *TagService) GetTag(ctx context.Context, tagID string) (*models.Tag, err
 	return tag, nil
 }
 
-// GetTagByName retrieves a single tag by name
-func (s *TagService) GetTagByName(ctx context.Context, name string) (*models.Tag, error) {
-	s.logger.Info("Getting tag by name", "tag_name", name)
+// GetTagByName retrieves a single tag by name, preferring the user's own tag over a shared one
+func (s *TagService) GetTagByName(ctx context.Context, userID, name string) (*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTagByName", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Getting tag by name", "user_id", userID, "tag_name", name)
 
 	var tag *models.Tag
 
 	// Read operation to get tag by name
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcTag, err := qtx.GetTagByName(ctx, name)
+		sqlcTag, err := qtx.GetTagByName(ctx, store.GetTagByNameParams{
+			UserID: uuidToPgUUID(&userUUID),
+			Name:   name,
+		})
+		if database.NoRows(err) {
+			sqlcTag, err = qtx.GetSharedTagByName(ctx, store.GetSharedTagByNameParams{
+				Name:   name,
+				UserID: userUUID,
+			})
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get tag by name: %w", err)
 		}
@@ -124,15 +166,21 @@ func (s *TagService) GetTagByName(ctx context.Context, name string) (*models.Tag
 	return tag, nil
 }
 
-// GetAllTags retrieves all tags
-func (s *TagService) GetAllTags(ctx context.Context) ([]*models.Tag, error) {
-	s.logger.Info("Getting all tags")
+// GetAllTags retrieves the user's tags together with the shared tags
+func (s *TagService) GetAllTags(ctx context.Context, userID string) ([]*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetAllTags", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Getting all tags", "user_id", userID)
 
 	var tags []*models.Tag
 
 	// Read operation to get all tags
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcTags, err := qtx.GetAllTags(ctx)
+		sqlcTags, err := qtx.GetAllTags(ctx, uuidToPgUUID(&userUUID))
 		if err != nil {
 			return fmt.Errorf("failed to get all tags: %w", err)
 		}
@@ -152,8 +200,14 @@ func (s *TagService) GetAllTags(ctx context.Context) ([]*models.Tag, error) {
 	return tags, nil
 }
 
-// GetPopularTags retrieves the most popular tags by usage count
-func (s *TagService) GetPopularTags(ctx context.Context, limit int32) ([]*models.Tag, error) {
+// GetPopularTags retrieves the user's most used tags
+func (s *TagService) GetPopularTags(ctx context.Context, userID string, limit int32) ([]*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetPopularTags", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
@@ -164,7 +218,10 @@ func (s *TagService) GetPopularTags(ctx context.Context, limit int32) ([]*models
 
 	// Read operation to get popular tags
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcTags, err := qtx.GetPopularTags(ctx, limit)
+		sqlcTags, err := qtx.GetPopularTags(ctx, store.GetPopularTagsParams{
+			UserID: uuidToPgUUID(&userUUID),
+			Limit:  limit,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to get popular tags: %w", err)
 		}
@@ -232,8 +289,14 @@ func (s *TagService) GetRecentlyUsedTags(ctx context.Context, userID string, lim
 	return tags, nil
 }
 
-// SearchTags searches for tags by name
-func (s *TagService) SearchTags(ctx context.Context, query string, limit int32) ([]*models.Tag, error) {
+// SearchTags searches the user's and shared tags by name
+func (s *TagService) SearchTags(ctx context.Context, userID, query string, limit int32) ([]*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in SearchTags", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	if limit <= 0 {
 		limit = 20 // Default limit
 	}
@@ -247,6 +310,7 @@ func (s *TagService) SearchTags(ctx context.Context, query string, limit int32)
 		sqlcTags, err := qtx.SearchTags(ctx, store.SearchTagsParams{
 			Column1: pgtype.Text{String: query, Valid: true},
 			Limit:   limit,
+			UserID:  uuidToPgUUID(&userUUID),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to search tags: %w", err)
@@ -267,8 +331,15 @@ func (s *TagService) SearchTags(ctx context.Context, query string, limit int32)
 	return tags, nil
 }
 
-// UpdateTag updates an existing tag
-func (s *TagService) UpdateTag(ctx context.Context, tagID string, req *models.TagRequest) (*models.Tag, error) {
+// UpdateTag updates a tag owned by the user. Shared tags are read-only.
+// Renaming a tag rewrites recurring templates and keeps the old name as an alias.
+func (s *TagService) UpdateTag(ctx context.Context, userID, tagID string, req *models.TagRequest) (*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in UpdateTag", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	// Validate request
 	if err := s.validateTagRequest(req); err != nil {
 		s.logger.Warn("Invalid tag update request", "tag_id", tagID, "tag_name", req.Name, "error", err.Error())
@@ -287,16 +358,39 @@ func (s *TagService) UpdateTag(ctx context.Context, tagID string, req *models.Ta
 
 	// Start write transaction to update tag
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		current, err := getOwnedTag(ctx, qtx, userUUID, tagUUID)
+		if err != nil {
+			return err
+		}
+
+		renamed := current.Name != req.Name
+		if renamed {
+			if err := checkTagNameAvailable(ctx, qtx, userUUID, tagUUID, req.Name); err != nil {
+				return err
+			}
+		}
+		if err := validateTagParent(ctx, qtx, &userUUID, &tagUUID, req.ParentID); err != nil {
+			return err
+		}
+
 		sqlcTag, err := qtx.UpdateTag(ctx, store.UpdateTagParams{
 			ID:          tagUUID,
 			Name:        req.Name,
 			Color:       stringToPgText(&req.Color),
 			Description: stringToPgText(req.Description),
+			UserID:      uuidToPgUUID(&userUUID),
+			ParentID:    uuidToPgUUID(req.ParentID),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update tag: %w", err)
 		}
 
+		if renamed {
+			if err := propagateTagRename(ctx, qtx, userUUID, tagUUID, current.Name, req.Name); err != nil {
+				return err
+			}
+		}
+
 		tag = s.sqlcToModel(sqlcTag)
 		return nil
 	}); err != nil {
@@ -312,59 +406,88 @@ func (s *TagService) UpdateTag(ctx context.Context, tagID string, req *models.Ta
 	return tag, nil
 }
 
-// DeleteTag deletes a tag
-func (s *TagService) DeleteTag(ctx context.Context, tagID string) error {
+// DeleteTag deletes a tag owned by the user. Shared tags are read-only.
+func (s *TagService) DeleteTag(ctx context.Context, userID, tagID string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in DeleteTag", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	tagUUID, err := uuid.Parse(tagID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
 		return fmt.Errorf("invalid tag ID: %w", err)
 	}
 
-	s.logger.Info("Deleting tag", "tag_id", tagID)
+	s.logger.Info("Deleting tag", "user_id", userID, "tag_id", tagID)
 
-	// First verify the tag exists
-	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		_, err := qtx.GetTagByID(ctx, tagUUID)
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteTag(ctx, store.DeleteTagParams{
+			ID:     tagUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
 		if err != nil {
-			return fmt.Errorf("tag not found: %w", err)
+			return err
+		}
+		if rowsAffected == 0 {
+			return s.notOwnedError(ctx, qtx, userUUID, tagUUID)
 		}
 		return nil
 	}); err != nil {
-		if strings.Contains(err.Error(), "no rows") {
-			s.logger.Warn("Tag not found for deletion", "tag_id", tagID)
+		if strings.Contains(err.Error(), "not found") {
+			s.logger.Warn("Tag not found for deletion", "user_id", userID, "tag_id", tagID)
 		} else {
-			s.logger.LogError(ctx, err, "Failed to verify tag for deletion", "tag_id", tagID)
+			s.logger.LogError(ctx, err, "Failed to delete tag", "user_id", userID, "tag_id", tagID)
 		}
-		return fmt.Errorf("failed to verify tag: %w", err)
-	}
-
-	// Now delete the tag
-	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
-		return qtx.DeleteTag(ctx, tagUUID)
-	}); err != nil {
-		s.logger.LogError(ctx, err, "Failed to delete tag", "tag_id", tagID)
 		return fmt.Errorf("failed to delete tag: %w", err)
 	}
 
-	s.logger.Info("Tag deleted successfully", "tag_id", tagID)
+	s.logger.Info("Tag deleted successfully", "user_id", userID, "tag_id", tagID)
 	return nil
 }
 
-// GetUserTagUsage retrieves tag usage statistics for a specific user
-func (s *TagService) GetUserTagUsage(ctx context.Context, userID string) ([]*models.TagUsage, error) {
+// GetUserTagUsage retrieves tag usage statistics for a specific user.
+// With includeDescendants, each tag also counts entries tagged with its descendants.
+func (s *TagService) GetUserTagUsage(ctx context.Context, userID string, includeDescendants bool) ([]*models.TagUsage, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	s.logger.Info("Getting user tag usage", "user_id", userID)
+	s.logger.Info("Getting user tag usage", "user_id", userID, "include_descendants", includeDescendants)
 
 	var tagUsages []*models.TagUsage
 
 	// Read operation to get user tag usage
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		usage, err := qtx.GetUserTagUsage(ctx, userUUID)
+		if includeDescendants {
+			usage, err := qtx.GetUserTagUsageWithDescendants(ctx, uuidToPgUUID(&userUUID))
+			if err != nil {
+				return fmt.Errorf("failed to get user tag usage: %w", err)
+			}
+
+			tagUsages = make([]*models.TagUsage, len(usage))
+			for i, u := range usage {
+				tagUsages[i] = &models.TagUsage{
+					Tag: &models.Tag{
+						ID:          u.ID,
+						UserID:      &userUUID,
+						ParentID:    pgUUIDToUUIDPtr(u.ParentID),
+						Name:        u.Name,
+						Color:       pgTextToStringRequired(u.Color),
+						Description: pgTextToString(u.Description),
+						UsageCount:  int(u.UserUsageCount),
+						CreatedAt:   pgTimestamptzToTime(u.CreatedAt),
+					},
+					UsageCount: int(u.UserUsageCount),
+				}
+			}
+			return nil
+		}
+
+		usage, err := qtx.GetUserTagUsage(ctx, uuidToPgUUID(&userUUID))
 		if err != nil {
 			return fmt.Errorf("failed to get user tag usage: %w", err)
 		}
@@ -374,13 +497,15 @@ func (s *TagService) GetUserTagUsage(ctx context.Context, userID string) ([]*mod
 			tagUsages[i] = &models.TagUsage{
 				Tag: &models.Tag{
 					ID:          u.ID,
+					UserID:      &userUUID,
+					ParentID:    pgUUIDToUUIDPtr(u.ParentID),
 					Name:        u.Name,
 					Color:       pgTextToStringRequired(u.Color),
 					Description: pgTextToString(u.Description),
-					UsageCount:  0,           // This is global usage count, not user-specific
-					CreatedAt:   time.Time{}, // Not available in this query
+					UsageCount:  pgInt4ToInt(u.UserUsageCount),
+					CreatedAt:   pgTimestamptzToTime(u.CreatedAt),
 				},
-				UsageCount: int(u.UserUsageCount),
+				UsageCount: pgInt4ToInt(u.UserUsageCount),
 			}
 		}
 
@@ -394,8 +519,14 @@ func (s *TagService) GetUserTagUsage(ctx context.Context, userID string) ([]*mod
 	return tagUsages, nil
 }
 
-// GetLogEntriesForTag retrieves all log entries that have a specific tag
-func (s *TagService) GetLogEntriesForTag(ctx context.Context, tagID string) ([]*models.LogEntry, error) {
+// GetLogEntriesForTag retrieves the user's log entries that have a specific tag
+func (s *TagService) GetLogEntriesForTag(ctx context.Context, userID, tagID string) ([]*models.LogEntry, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetLogEntriesForTag", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	tagUUID, err := uuid.Parse(tagID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
@@ -408,7 +539,10 @@ func (s *TagService) GetLogEntriesForTag(ctx context.Context, tagID string) ([]*
 
 	// Read operation to get log entries for tag
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
-		sqlcEntries, err := qtx.GetLogEntriesForTag(ctx, tagUUID)
+		sqlcEntries, err := qtx.GetLogEntriesForTag(ctx, store.GetLogEntriesForTagParams{
+			TagID:  tagUUID,
+			UserID: userUUID,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to get log entries for tag: %w", err)
 		}
@@ -428,27 +562,41 @@ func (s *TagService) GetLogEntriesForTag(ctx context.Context, tagID string) ([]*
 	return logEntries, nil
 }
 
-// AddTagToLogEntry associates a tag with a log entry
-func (s *TagService) AddTagToLogEntry(ctx context.Context, logEntryID, tagID string) error {
-	logEntryUUID, err := uuid.Parse(logEntryID)
-	if err != nil {
-		s.logger.LogError(ctx, err, "Invalid log entry ID format", "log_entry_id", logEntryID)
-		return fmt.Errorf("invalid log entry ID: %w", err)
-	}
-
-	tagUUID, err := uuid.Parse(tagID)
+// AddTagToLogEntry associates a tag with one of the user's log entries.
+// A shared tag is attached through the user's personal copy of it.
+func (s *TagService) AddTagToLogEntry(ctx context.Context, userID, logEntryID, tagID string) error {
+	userUUID, logEntryUUID, tagUUID, err := s.parseAssociationIDs(ctx, userID, logEntryID, tagID)
 	if err != nil {
-		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
-		return fmt.Errorf("invalid tag ID: %w", err)
+		return err
 	}
 
-	s.logger.Info("Adding tag to log entry", "log_entry_id", logEntryID, "tag_id", tagID)
+	s.logger.Info("Adding tag to log entry", "user_id", userID, "log_entry_id", logEntryID, "tag_id", tagID)
 
 	// Start write transaction to add tag to log entry
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := ensureLogEntryOwned(ctx, qtx, userUUID, logEntryUUID); err != nil {
+			return err
+		}
+
+		tag, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+			ID:     tagUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
+		if database.NoRows(err) {
+			return fmt.Errorf("tag not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get tag: %w", err)
+		}
+		if !tag.UserID.Valid {
+			if tag, err = ensureUserTag(ctx, qtx, userUUID, tag.Name); err != nil {
+				return err
+			}
+		}
+
 		return qtx.AddTagToLogEntry(ctx, store.AddTagToLogEntryParams{
 			LogEntryID: logEntryUUID,
-			TagID:      tagUUID,
+			TagID:      tag.ID,
 		})
 	}); err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -463,30 +611,49 @@ func (s *TagService) AddTagToLogEntry(ctx context.Context, logEntryID, tagID str
 	return nil
 }
 
-// RemoveTagFromLogEntry removes a tag association from a log entry
-func (s *TagService) RemoveTagFromLogEntry(ctx context.Context, logEntryID, tagID string) error {
-	logEntryUUID, err := uuid.Parse(logEntryID)
-	if err != nil {
-		s.logger.LogError(ctx, err, "Invalid log entry ID format", "log_entry_id", logEntryID)
-		return fmt.Errorf("invalid log entry ID: %w", err)
-	}
-
-	tagUUID, err := uuid.Parse(tagID)
+// RemoveTagFromLogEntry removes a tag association from one of the user's log entries
+func (s *TagService) RemoveTagFromLogEntry(ctx context.Context, userID, logEntryID, tagID string) error {
+	userUUID, logEntryUUID, tagUUID, err := s.parseAssociationIDs(ctx, userID, logEntryID, tagID)
 	if err != nil {
-		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
-		return fmt.Errorf("invalid tag ID: %w", err)
+		return err
 	}
 
-	s.logger.Info("Removing tag from log entry", "log_entry_id", logEntryID, "tag_id", tagID)
+	s.logger.Info("Removing tag from log entry", "user_id", userID, "log_entry_id", logEntryID, "tag_id", tagID)
 
 	// Start write transaction to remove tag from log entry
 	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if err := ensureLogEntryOwned(ctx, qtx, userUUID, logEntryUUID); err != nil {
+			return err
+		}
+
+		tag, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+			ID:     tagUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
+		if database.NoRows(err) {
+			return fmt.Errorf("tag not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get tag: %w", err)
+		}
+		if !tag.UserID.Valid {
+			// Entries only ever carry the personal copy of a shared tag
+			if tag, err = qtx.GetTagByName(ctx, store.GetTagByNameParams{
+				UserID: uuidToPgUUID(&userUUID),
+				Name:   tag.Name,
+			}); database.NoRows(err) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to get tag: %w", err)
+			}
+		}
+
 		return qtx.RemoveTagFromLogEntry(ctx, store.RemoveTagFromLogEntryParams{
 			LogEntryID: logEntryUUID,
-			TagID:      tagUUID,
+			TagID:      tag.ID,
 		})
 	}); err != nil {
-		if strings.Contains(err.Error(), "no rows") {
+		if strings.Contains(err.Error(), "not found") {
 			s.logger.Warn("Tag association not found for removal", "log_entry_id", logEntryID, "tag_id", tagID)
 		} else {
 			s.logger.LogError(ctx, err, "Failed to remove tag from log entry", "log_entry_id", logEntryID, "tag_id", tagID)
@@ -498,20 +665,30 @@ func (s *TagService) RemoveTagFromLogEntry(ctx context.Context, logEntryID, tagI
 	return nil
 }
 
-// GetTagsForLogEntry retrieves all tags associated with a log entry
-func (s *TagService) GetTagsForLogEntry(ctx context.Context, logEntryID string) ([]*models.Tag, error) {
+// GetTagsForLogEntry retrieves all tags associated with one of the user's log entries
+func (s *TagService) GetTagsForLogEntry(ctx context.Context, userID, logEntryID string) ([]*models.Tag, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTagsForLogEntry", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	logEntryUUID, err := uuid.Parse(logEntryID)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Invalid log entry ID format", "log_entry_id", logEntryID)
 		return nil, fmt.Errorf("invalid log entry ID: %w", err)
 	}
 
-	s.logger.Info("Getting tags for log entry", "log_entry_id", logEntryID)
+	s.logger.Info("Getting tags for log entry", "user_id", userID, "log_entry_id", logEntryID)
 
 	var tags []*models.Tag
 
 	// Read operation to get tags for log entry
 	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if err := ensureLogEntryOwned(ctx, qtx, userUUID, logEntryUUID); err != nil {
+			return err
+		}
+
 		sqlcTags, err := qtx.GetTagsForLogEntry(ctx, logEntryUUID)
 		if err != nil {
 			return fmt.Errorf("failed to get tags for log entry: %w", err)
@@ -548,13 +725,13 @@ func (s *TagService) CleanupUnusedTags(ctx context.Context) error {
 	return nil
 }
 
-// EnsureTagExists creates a tag if it doesn't exist, or returns the existing one
-func (s *TagService) EnsureTagExists(ctx context.Context, name, color string) (*models.Tag, error) {
-	s.logger.Info("Ensuring tag exists", "tag_name", name, "color", color)
+// EnsureTagExists creates a tag in the user's namespace if it doesn't exist, or returns the existing one
+func (s *TagService) EnsureTagExists(ctx context.Context, userID, name, color string) (*models.Tag, error) {
+	s.logger.Info("Ensuring tag exists", "user_id", userID, "tag_name", name, "color", color)
 
-	// First try to get existing tag
-	tag, err := s.GetTagByName(ctx, name)
-	if err == nil {
+	// First try to get the user's own tag; shared tags are not reused directly
+	tag, err := s.GetTagByName(ctx, userID, name)
+	if err == nil && tag.UserID != nil {
 		s.logger.Info("Tag already exists", "tag_name", name, "tag_id", tag.ID)
 		return tag, nil
 	}
@@ -567,8 +744,13 @@ func (s *TagService) EnsureTagExists(ctx context.Context, name, color string) (*
 		Color:       color,
 		Description: nil,
 	}
+	if tag != nil {
+		// Inherit the shared tag's presentation
+		req.Color = tag.Color
+		req.Description = tag.Description
+	}
 
-	createdTag, err := s.CreateTag(ctx, req)
+	createdTag, err := s.CreateTag(ctx, userID, req)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Failed to create tag in EnsureTagExists", "tag_name", name, "color", color)
 		return nil, err
@@ -603,10 +785,91 @@ func (s *TagService) validateTagRequest(req *models.TagRequest) error {
 	return nil
 }
 
+// parseAssociationIDs parses the IDs used by the tag association methods
+func (s *TagService) parseAssociationIDs(ctx context.Context, userID, logEntryID, tagID string) (uuid.UUID, uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	logEntryUUID, err := uuid.Parse(logEntryID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid log entry ID format", "log_entry_id", logEntryID)
+		return uuid.Nil, uuid.Nil, uuid.Nil, fmt.Errorf("invalid log entry ID: %w", err)
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return uuid.Nil, uuid.Nil, uuid.Nil, fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	return userUUID, logEntryUUID, tagUUID, nil
+}
+
+// notOwnedError explains why a tag could not be modified by the user
+func (s *TagService) notOwnedError(ctx context.Context, qtx *store.Queries, userUUID, tagUUID uuid.UUID) error {
+	tag, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+		ID:     tagUUID,
+		UserID: uuidToPgUUID(&userUUID),
+	})
+	if err == nil && !tag.UserID.Valid {
+		return fmt.Errorf("shared tags are read-only")
+	}
+	return fmt.Errorf("tag not found")
+}
+
+// ensureUserTag gets or creates a tag by name in the user's namespace.
+// Aliases resolve to the tag they belong to. Using a shared or team tag name creates a personal copy that inherits its color
+// and description, so usage counts stay per user.
+func ensureUserTag(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, name string) (store.Tag, error) {
+	owner := uuidToPgUUID(&userUUID)
+
+	tag, err := qtx.GetTagByName(ctx, store.GetTagByNameParams{
+		UserID: owner,
+		Name:   name,
+	})
+	if err == nil || !database.NoRows(err) {
+		return tag, err
+	}
+
+	tag, err = qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{
+		UserID: userUUID,
+		Lower:  name,
+	})
+	if err == nil || !database.NoRows(err) {
+		return tag, err
+	}
+
+	color := stringToPgTextRequired(defaultTagColor)
+	var description pgtype.Text
+	shared, err := qtx.GetSharedTagByName(ctx, store.GetSharedTagByNameParams{
+		Name:   name,
+		UserID: userUUID,
+	})
+	if err == nil {
+		color, description = shared.Color, shared.Description
+	} else if !database.NoRows(err) {
+		return store.Tag{}, err
+	}
+
+	return qtx.CreateTag(ctx, store.CreateTagParams{
+		Name:        name,
+		Color:       color,
+		Description: description,
+		UserID:      owner,
+	})
+}
+
 // sqlcToModel converts SQLC Tag to models.Tag
 func (s *TagService) sqlcToModel(sqlcTag store.Tag) *models.Tag {
 	return &models.Tag{
 		ID:          sqlcTag.ID,
+		UserID:      pgUUIDToUUIDPtr(sqlcTag.UserID),
+		TeamID:      pgUUIDToUUIDPtr(sqlcTag.TeamID),
+		ParentID:    pgUUIDToUUIDPtr(sqlcTag.ParentID),
+		Shared:      !sqlcTag.UserID.Valid,
 		Name:        sqlcTag.Name,
 		Color:       pgTextToStringRequired(sqlcTag.Color),
 		Description: pgTextToString(sqlcTag.Description),
@@ -619,6 +882,10 @@ func (s *TagService) sqlcToModel(sqlcTag store.Tag) *models.Tag {
 func (s *TagService) sqlcRecentlyUsedToModel(sqlcRow store.GetRecentlyUsedTagsRow) *models.Tag {
 	return &models.Tag{
 		ID:          sqlcRow.ID,
+		UserID:      pgUUIDToUUIDPtr(sqlcRow.UserID),
+		TeamID:      pgUUIDToUUIDPtr(sqlcRow.TeamID),
+		ParentID:    pgUUIDToUUIDPtr(sqlcRow.ParentID),
+		Shared:      !sqlcRow.UserID.Valid,
 		Name:        sqlcRow.Name,
 		Color:       pgTextToStringRequired(sqlcRow.Color),
 		Description: pgTextToString(sqlcRow.Description),
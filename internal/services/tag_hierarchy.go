@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// maxTagAliasLength mirrors the tag_aliases.alias column size
+const maxTagAliasLength = 100
+
+// AddTagAlias adds an alternative name to a tag owned by the user
+func (s *TagService) AddTagAlias(ctx context.Context, userID, tagID string, req *models.TagAliasRequest) (*models.TagAlias, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in AddTagAlias", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return nil, fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	alias := strings.TrimSpace(req.Alias)
+	if alias == "" {
+		return nil, fmt.Errorf("alias is required")
+	}
+	if len(alias) > maxTagAliasLength {
+		return nil, fmt.Errorf("alias must be at most %d characters", maxTagAliasLength)
+	}
+
+	s.logger.Info("Adding tag alias", "user_id", userID, "tag_id", tagID, "alias", alias)
+
+	var tagAlias *models.TagAlias
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := getOwnedTag(ctx, qtx, userUUID, tagUUID); err != nil {
+			return err
+		}
+
+		conflicts, err := qtx.GetTagsByNameInsensitive(ctx, store.GetTagsByNameInsensitiveParams{
+			UserID: uuidToPgUUID(&userUUID),
+			Lower:  alias,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check tag names: %w", err)
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("alias %q conflicts with tag %q; merge the tags instead", alias, conflicts[0].Name)
+		}
+
+		if existing, err := qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{
+			UserID: userUUID,
+			Lower:  alias,
+		}); err == nil {
+			return fmt.Errorf("alias %q is already used by tag %q", alias, existing.Name)
+		} else if !database.NoRows(err) {
+			return fmt.Errorf("failed to check aliases: %w", err)
+		}
+
+		sqlcAlias, err := qtx.CreateTagAlias(ctx, store.CreateTagAliasParams{
+			TagID:  tagUUID,
+			UserID: userUUID,
+			Alias:  alias,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create alias: %w", err)
+		}
+
+		tagAlias = tagAliasToModel(sqlcAlias)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to add tag alias", "user_id", userID, "tag_id", tagID, "alias", alias)
+		return nil, fmt.Errorf("failed to add tag alias: %w", err)
+	}
+
+	s.logger.Info("Tag alias added successfully", "user_id", userID, "tag_id", tagID, "alias", alias)
+	return tagAlias, nil
+}
+
+// GetTagAliases lists the aliases of a tag visible to the user
+func (s *TagService) GetTagAliases(ctx context.Context, userID, tagID string) ([]*models.TagAlias, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetTagAliases", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return nil, fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	var aliases []*models.TagAlias
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+			ID:     tagUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		}); database.NoRows(err) {
+			return fmt.Errorf("tag not found")
+		} else if err != nil {
+			return fmt.Errorf("failed to get tag: %w", err)
+		}
+
+		rows, err := qtx.GetTagAliases(ctx, tagUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get aliases: %w", err)
+		}
+
+		aliases = make([]*models.TagAlias, len(rows))
+		for i, row := range rows {
+			aliases[i] = tagAliasToModel(row)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get tag aliases", "user_id", userID, "tag_id", tagID)
+		return nil, fmt.Errorf("failed to get tag aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// RemoveTagAlias removes an alias from a tag owned by the user
+func (s *TagService) RemoveTagAlias(ctx context.Context, userID, tagID, alias string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in RemoveTagAlias", "user_id", userID)
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := getOwnedTag(ctx, qtx, userUUID, tagUUID); err != nil {
+			return err
+		}
+
+		rowsAffected, err := qtx.DeleteTagAlias(ctx, store.DeleteTagAliasParams{
+			TagID:  tagUUID,
+			UserID: userUUID,
+			Lower:  alias,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete alias: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("alias not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove tag alias", "user_id", userID, "tag_id", tagID, "alias", alias)
+		return fmt.Errorf("failed to remove tag alias: %w", err)
+	}
+
+	s.logger.Info("Tag alias removed successfully", "user_id", userID, "tag_id", tagID, "alias", alias)
+	return nil
+}
+
+// MergeTags merges the source tag into the target tag. Log entries, child tags
+// and aliases of the source move to the target, template tag lists are
+// rewritten, the source is deleted and its name becomes an alias of the target.
+func (s *TagService) MergeTags(ctx context.Context, userID, sourceID string, req *models.TagMergeRequest) (*models.TagMergeResult, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in MergeTags", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	sourceUUID, err := uuid.Parse(sourceID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", sourceID)
+		return nil, fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	targetUUID := req.TargetID
+	if targetUUID == uuid.Nil {
+		return nil, fmt.Errorf("target_id is required")
+	}
+	if targetUUID == sourceUUID {
+		return nil, fmt.Errorf("cannot merge a tag into itself")
+	}
+
+	s.logger.Info("Merging tags", "user_id", userID, "source_tag_id", sourceID, "target_tag_id", targetUUID)
+
+	var result *models.TagMergeResult
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		source, err := getOwnedTag(ctx, qtx, userUUID, sourceUUID)
+		if err != nil {
+			return err
+		}
+		target, err := getOwnedTag(ctx, qtx, userUUID, targetUUID)
+		if err != nil {
+			return fmt.Errorf("target %w", err)
+		}
+
+		// A target below the source moves up so re-parenting cannot form a cycle
+		descendants, err := qtx.GetTagDescendantIDs(ctx, store.GetTagDescendantIDsParams{
+			ID:     sourceUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get descendant tags: %w", err)
+		}
+		if slices.Contains(descendants, targetUUID) {
+			if err := qtx.SetTagParent(ctx, store.SetTagParentParams{
+				ID:       targetUUID,
+				ParentID: source.ParentID,
+			}); err != nil {
+				return fmt.Errorf("failed to move target tag: %w", err)
+			}
+		}
+
+		moved, err := qtx.MoveLogEntryTags(ctx, store.MoveLogEntryTagsParams{
+			TagID:   sourceUUID,
+			Column2: targetUUID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move log entries: %w", err)
+		}
+
+		if err := qtx.ReparentTagChildren(ctx, store.ReparentTagChildrenParams{
+			ParentID:   uuidToPgUUID(&sourceUUID),
+			ParentID_2: uuidToPgUUID(&targetUUID),
+		}); err != nil {
+			return fmt.Errorf("failed to move child tags: %w", err)
+		}
+
+		if err := qtx.MoveTagAliases(ctx, store.MoveTagAliasesParams{
+			TagID:   sourceUUID,
+			TagID_2: targetUUID,
+		}); err != nil {
+			return fmt.Errorf("failed to move aliases: %w", err)
+		}
+
+		if _, err := qtx.DeleteTag(ctx, store.DeleteTagParams{
+			ID:     sourceUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		}); err != nil {
+			return fmt.Errorf("failed to delete merged tag: %w", err)
+		}
+
+		if err := propagateTagRename(ctx, qtx, userUUID, targetUUID, source.Name, target.Name); err != nil {
+			return err
+		}
+
+		merged, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+			ID:     targetUUID,
+			UserID: uuidToPgUUID(&userUUID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get merged tag: %w", err)
+		}
+
+		result = &models.TagMergeResult{
+			Tag:          s.sqlcToModel(merged),
+			MovedEntries: moved,
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to merge tags", "user_id", userID, "source_tag_id", sourceID, "target_tag_id", targetUUID)
+		return nil, fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	s.logger.Info("Tags merged successfully",
+		"user_id", userID,
+		"source_tag_id", sourceID,
+		"target_tag_id", targetUUID,
+		"moved_entries", result.MovedEntries)
+	return result, nil
+}
+
+// getOwnedTag fetches a tag the user may modify
+func getOwnedTag(ctx context.Context, qtx *store.Queries, userUUID, tagUUID uuid.UUID) (store.Tag, error) {
+	tag, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+		ID:     tagUUID,
+		UserID: uuidToPgUUID(&userUUID),
+	})
+	if database.NoRows(err) {
+		return store.Tag{}, fmt.Errorf("tag not found")
+	}
+	if err != nil {
+		return store.Tag{}, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if !tag.UserID.Valid {
+		return store.Tag{}, fmt.Errorf("shared tags are read-only")
+	}
+	return tag, nil
+}
+
+// validateTagParent checks that parentUUID can become the parent of a tag in
+// the owner's namespace. tagUUID is nil for new tags.
+func validateTagParent(ctx context.Context, qtx *store.Queries, owner, tagUUID, parentUUID *uuid.UUID) error {
+	if parentUUID == nil {
+		return nil
+	}
+	if tagUUID != nil && *tagUUID == *parentUUID {
+		return fmt.Errorf("a tag cannot be its own parent")
+	}
+
+	parent, err := qtx.GetTagByID(ctx, store.GetTagByIDParams{
+		ID:     *parentUUID,
+		UserID: uuidToPgUUID(owner),
+	})
+	if database.NoRows(err) {
+		return fmt.Errorf("parent tag not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get parent tag: %w", err)
+	}
+	if parent.UserID != uuidToPgUUID(owner) {
+		return fmt.Errorf("parent tag must belong to the same namespace")
+	}
+
+	if tagUUID == nil {
+		return nil
+	}
+
+	descendants, err := qtx.GetTagDescendantIDs(ctx, store.GetTagDescendantIDsParams{
+		ID:     *tagUUID,
+		UserID: uuidToPgUUID(owner),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get descendant tags: %w", err)
+	}
+	if slices.Contains(descendants, *parentUUID) {
+		return fmt.Errorf("parent tag cannot be a descendant of the tag")
+	}
+
+	return nil
+}
+
+// checkTagNameAvailable rejects names that are aliases of another tag of the user
+func checkTagNameAvailable(ctx context.Context, qtx *store.Queries, userUUID, tagUUID uuid.UUID, name string) error {
+	aliased, err := qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{
+		UserID: userUUID,
+		Lower:  name,
+	})
+	if database.NoRows(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check aliases: %w", err)
+	}
+	if aliased.ID != tagUUID {
+		return fmt.Errorf("tag name %q is an alias of tag %q", name, aliased.Name)
+	}
+	return nil
+}
+
+// propagateTagRename rewrites recurring templates from oldName to newName and
+// keeps oldName as an alias, so restored revisions and imports still resolve
+func propagateTagRename(ctx context.Context, qtx *store.Queries, userUUID, tagUUID uuid.UUID, oldName, newName string) error {
+	if err := qtx.ReplaceLogTemplateTag(ctx, store.ReplaceLogTemplateTagParams{
+		UserID:  userUUID,
+		Column2: oldName,
+		Column3: newName,
+	}); err != nil {
+		return fmt.Errorf("failed to update templates: %w", err)
+	}
+
+	// The new name no longer needs to be an alias of the tag
+	if _, err := qtx.DeleteTagAlias(ctx, store.DeleteTagAliasParams{
+		TagID:  tagUUID,
+		UserID: userUUID,
+		Lower:  newName,
+	}); err != nil {
+		return fmt.Errorf("failed to update aliases: %w", err)
+	}
+
+	if _, err := qtx.GetTagByAlias(ctx, store.GetTagByAliasParams{
+		UserID: userUUID,
+		Lower:  oldName,
+	}); err == nil {
+		return nil
+	} else if !database.NoRows(err) {
+		return fmt.Errorf("failed to check aliases: %w", err)
+	}
+
+	if _, err := qtx.CreateTagAlias(ctx, store.CreateTagAliasParams{
+		TagID:  tagUUID,
+		UserID: userUUID,
+		Alias:  oldName,
+	}); err != nil {
+		return fmt.Errorf("failed to keep previous name as alias: %w", err)
+	}
+	return nil
+}
+
+// expandTagNames adds the names of all descendants of the given tags.
+// Aliases are resolved to their tags; the requested names are kept as is.
+func expandTagNames(ctx context.Context, qtx *store.Queries, userUUID uuid.UUID, names []string) ([]string, error) {
+	descendants, err := qtx.GetTagNamesWithDescendants(ctx, store.GetTagNamesWithDescendantsParams{
+		UserID:  uuidToPgUUID(&userUUID),
+		Column2: names,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand tag filter: %w", err)
+	}
+
+	expanded := slices.Clone(names)
+	for _, name := range descendants {
+		if !slices.Contains(expanded, name) {
+			expanded = append(expanded, name)
+		}
+	}
+	return expanded, nil
+}
+
+// tagAliasToModel converts a stored alias to its API model
+func tagAliasToModel(a store.TagAlias) *models.TagAlias {
+	return &models.TagAlias{
+		ID:        a.ID,
+		TagID:     a.TagID,
+		Alias:     a.Alias,
+		CreatedAt: pgTimestamptzToTime(a.CreatedAt),
+	}
+}
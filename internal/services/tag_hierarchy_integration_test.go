@@ -0,0 +1,214 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTagHierarchyIntegration tests nested tags, aliases, merges and renames
+func TestTagHierarchyIntegration(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	tagService := services.NewTagService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	templateService := services.NewLogTemplateService(db, testLogger, logEntryService)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "tag-hierarchy@example.com",
+		Password:  "password123",
+		FirstName: "Tree",
+		LastName:  "User",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	newEntry := func(tags ...string) *models.LogEntry {
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       "Tagged work",
+			Type:        models.ActivityDevelopment,
+			StartTime:   start,
+			EndTime:     start.Add(time.Hour),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+			Tags:        tags,
+		})
+		require.NoError(t, err)
+		return entry
+	}
+
+	k8s, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "k8s", Color: "#326CE5"})
+	require.NoError(t, err)
+	networking, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "k8s/networking", Color: "#326CE5", ParentID: &k8s.ID})
+	require.NoError(t, err)
+	require.NotNil(t, networking.ParentID)
+	assert.Equal(t, k8s.ID, *networking.ParentID)
+
+	t.Run("ParentCycleIsRejected", func(t *testing.T) {
+		_, err := tagService.UpdateTag(ctx, userID, k8s.ID.String(), &models.TagRequest{Name: "k8s", Color: "#326CE5", ParentID: &networking.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "descendant")
+
+		_, err = tagService.UpdateTag(ctx, userID, k8s.ID.String(), &models.TagRequest{Name: "k8s", Color: "#326CE5", ParentID: &k8s.ID})
+		require.Error(t, err)
+
+		missing := uuid.New()
+		_, err = tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "orphan", Color: "#326CE5", ParentID: &missing})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parent tag not found")
+	})
+
+	t.Run("FiltersAndUsageIncludeDescendants", func(t *testing.T) {
+		newEntry("k8s")
+		newEntry("k8s/networking")
+
+		entries, err := logEntryService.GetLogEntries(ctx, userID, &services.LogEntryFilters{Tags: []string{"k8s"}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		filters := &services.LogEntryFilters{Tags: []string{"k8s"}, IncludeTagDescendants: true}
+		entries, err = logEntryService.GetLogEntries(ctx, userID, filters)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, []string{"k8s"}, filters.Tags, "caller filters are not modified")
+
+		usage, err := tagService.GetUserTagUsage(ctx, userID, true)
+		require.NoError(t, err)
+		counts := map[string]int{}
+		for _, u := range usage {
+			counts[u.Tag.Name] = u.UsageCount
+		}
+		assert.Equal(t, 2, counts["k8s"])
+		assert.Equal(t, 1, counts["k8s/networking"])
+
+		usage, err = tagService.GetUserTagUsage(ctx, userID, false)
+		require.NoError(t, err)
+		for _, u := range usage {
+			if u.Tag.Name == "k8s" {
+				assert.Equal(t, 1, u.UsageCount)
+			}
+		}
+	})
+
+	goTag, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "go", Color: "#00ADD8"})
+	require.NoError(t, err)
+
+	t.Run("AliasesResolveOnWrite", func(t *testing.T) {
+		_, err := tagService.AddTagAlias(ctx, userID, goTag.ID.String(), &models.TagAliasRequest{Alias: "Golang"})
+		require.NoError(t, err)
+
+		entry := newEntry("golang")
+		assert.Equal(t, []string{"go"}, entry.Tags)
+
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, entry.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, fetched.Tags)
+
+		// The response and the revision carry the tag stored, once
+		entry = newEntry("golang", "go")
+		assert.Equal(t, []string{"go"}, entry.Tags)
+
+		history, err := logEntryService.GetLogEntryHistory(ctx, userID, entry.ID.String())
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, []string{"go"}, history[0].Tags)
+
+		_, err = tagService.AddTagAlias(ctx, userID, goTag.ID.String(), &models.TagAliasRequest{Alias: "K8S"})
+		require.Error(t, err, "an alias cannot shadow an existing tag")
+
+		_, err = tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "golang", Color: "#00ADD8"})
+		require.Error(t, err, "a tag cannot reuse an alias")
+
+		aliases, err := tagService.GetTagAliases(ctx, userID, goTag.ID.String())
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+		assert.Equal(t, "Golang", aliases[0].Alias)
+	})
+
+	t.Run("MergeMovesEntriesChildrenAndAliases", func(t *testing.T) {
+		golang, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "Go", Color: "#00ADD8"})
+		require.NoError(t, err)
+		generics, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "Go/generics", Color: "#00ADD8", ParentID: &golang.ID})
+		require.NoError(t, err)
+
+		both := newEntry("go", "Go")
+		newEntry("Go")
+
+		result, err := tagService.MergeTags(ctx, userID, golang.ID.String(), &models.TagMergeRequest{TargetID: goTag.ID})
+		require.NoError(t, err)
+		assert.Equal(t, goTag.ID, result.Tag.ID)
+		assert.Equal(t, int64(1), result.MovedEntries, "entries already tagged with the target are not duplicated")
+
+		_, err = tagService.GetTag(ctx, userID, golang.ID.String())
+		require.Error(t, err)
+
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, both.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, fetched.Tags)
+
+		child, err := tagService.GetTag(ctx, userID, generics.ID.String())
+		require.NoError(t, err)
+		require.NotNil(t, child.ParentID)
+		assert.Equal(t, goTag.ID, *child.ParentID)
+
+		// The merged name now resolves to the target
+		entry := newEntry("Go")
+		fetched, err = logEntryService.GetLogEntry(ctx, userID, entry.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, fetched.Tags)
+	})
+
+	t.Run("MergeIntoDescendant", func(t *testing.T) {
+		result, err := tagService.MergeTags(ctx, userID, k8s.ID.String(), &models.TagMergeRequest{TargetID: networking.ID})
+		require.NoError(t, err)
+		assert.Nil(t, result.Tag.ParentID)
+	})
+
+	t.Run("RenamePropagatesToTemplates", func(t *testing.T) {
+		ci, err := tagService.CreateTag(ctx, userID, &models.TagRequest{Name: "ci", Color: "#FF9900"})
+		require.NoError(t, err)
+
+		template, err := templateService.CreateTemplate(ctx, userID, &models.LogTemplateRequest{
+			Title:           "Pipeline babysitting",
+			Type:            models.ActivityMaintenance,
+			ValueRating:     models.ValueLow,
+			ImpactLevel:     models.ImpactTeam,
+			Tags:            []string{"ci", "oncall"},
+			RecurrenceRule:  "FREQ=WEEKLY;BYDAY=MO",
+			StartDate:       "2025-06-02",
+			StartTime:       "09:00",
+			DurationMinutes: 30,
+		})
+		require.NoError(t, err)
+
+		_, err = tagService.UpdateTag(ctx, userID, ci.ID.String(), &models.TagRequest{Name: "ci-cd", Color: "#FF9900"})
+		require.NoError(t, err)
+
+		updated, err := templateService.GetTemplate(ctx, userID, template.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ci-cd", "oncall"}, updated.Tags)
+
+		// Old names written later still land on the renamed tag
+		entry := newEntry("ci")
+		fetched, err := logEntryService.GetLogEntry(ctx, userID, entry.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ci-cd"}, fetched.Tags)
+	})
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagAliasToModel(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	row := store.TagAlias{
+		ID:        uuid.New(),
+		TagID:     uuid.New(),
+		UserID:    uuid.New(),
+		Alias:     "golang",
+		CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+
+	alias := tagAliasToModel(row)
+	assert.Equal(t, row.ID, alias.ID)
+	assert.Equal(t, row.TagID, alias.TagID)
+	assert.Equal(t, "golang", alias.Alias)
+	assert.Equal(t, createdAt, alias.CreatedAt)
+}
+
+func TestTagService_SqlcToModelParent(t *testing.T) {
+	tagService := &TagService{}
+	parentID := uuid.New()
+
+	child := tagService.sqlcToModel(store.Tag{
+		ID:       uuid.New(),
+		Name:     "k8s/networking",
+		ParentID: pgtype.UUID{Bytes: parentID, Valid: true},
+	})
+	require.NotNil(t, child.ParentID)
+	assert.Equal(t, parentID, *child.ParentID)
+
+	root := tagService.sqlcToModel(store.Tag{ID: uuid.New(), Name: "k8s"})
+	assert.Nil(t, root.ParentID)
+}
+
+func TestLogEntryService_ExpandTagFiltersWithoutDescendants(t *testing.T) {
+	service := NewLogEntryService(nil, logging.NewTestLogger())
+	ctx := context.Background()
+
+	// No query runs unless descendants were requested for a non-empty tag list
+	filters, err := service.expandTagFilters(ctx, nil, uuid.New(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, filters)
+
+	plain := &LogEntryFilters{Tags: []string{"k8s"}}
+	filters, err = service.expandTagFilters(ctx, nil, uuid.New(), plain)
+	require.NoError(t, err)
+	assert.Same(t, plain, filters)
+
+	noTags := &LogEntryFilters{IncludeTagDescendants: true}
+	filters, err = service.expandTagFilters(ctx, nil, uuid.New(), noTags)
+	require.NoError(t, err)
+	assert.Same(t, noTags, filters)
+}
+
+func TestTagService_MergeTagsValidation(t *testing.T) {
+	tagService := NewTagService(nil, logging.NewTestLogger())
+	ctx := context.Background()
+	userID := uuid.New().String()
+	tagID := uuid.New()
+
+	_, err := tagService.MergeTags(ctx, userID, tagID.String(), &models.TagMergeRequest{TargetID: tagID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "into itself")
+
+	_, err = tagService.MergeTags(ctx, userID, tagID.String(), &models.TagMergeRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target_id is required")
+
+	_, err = tagService.MergeTags(ctx, "not-a-uuid", tagID.String(), &models.TagMergeRequest{TargetID: uuid.New()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid user ID")
+}
+
+func TestTagService_AddTagAliasValidation(t *testing.T) {
+	tagService := NewTagService(nil, logging.NewTestLogger())
+	ctx := context.Background()
+	userID := uuid.New().String()
+	tagID := uuid.New().String()
+
+	_, err := tagService.AddTagAlias(ctx, userID, tagID, &models.TagAliasRequest{Alias: "   "})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alias is required")
+
+	long := make([]byte, maxTagAliasLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	_, err = tagService.AddTagAlias(ctx, userID, tagID, &models.TagAliasRequest{Alias: string(long)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most")
+}
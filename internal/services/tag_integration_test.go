@@ -10,12 +10,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/garnizeh/englog/internal/logging"
 	"github.com/garnizeh/englog/internal/models"
 	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/store"
 	"github.com/garnizeh/englog/internal/testutils"
 )
 
@@ -28,6 +30,16 @@ func TestTagServiceIntegration(t *testing.T) {
 	testLogger := logging.NewTestLogger()
 
 	tagService := services.NewTagService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+	user, err := userService.CreateUser(context.Background(), &models.UserRegistration{
+		Email:     "tag-owner@example.com",
+		Password:  "password123",
+		FirstName: "Tag",
+		LastName:  "Owner",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
 
 	t.Run("FullTagLifecycle", func(t *testing.T) {
 		ctx := context.Background()
@@ -40,7 +52,7 @@ func TestTagServiceIntegration(t *testing.T) {
 		}
 
 		// Test tag creation
-		createdTag, err := tagService.CreateTag(ctx, createReq)
+		createdTag, err := tagService.CreateTag(ctx, userID, createReq)
 		require.NoError(t, err)
 		require.NotNil(t, createdTag)
 		assert.Equal(t, createReq.Name, createdTag.Name)
@@ -49,14 +61,14 @@ func TestTagServiceIntegration(t *testing.T) {
 		assert.NotEqual(t, uuid.Nil, createdTag.ID)
 
 		// Test tag retrieval by ID
-		retrievedTag, err := tagService.GetTag(ctx, createdTag.ID.String())
+		retrievedTag, err := tagService.GetTag(ctx, userID, createdTag.ID.String())
 		require.NoError(t, err)
 		assert.Equal(t, createdTag.ID, retrievedTag.ID)
 		assert.Equal(t, createdTag.Name, retrievedTag.Name)
 		assert.Equal(t, createdTag.Color, retrievedTag.Color)
 
 		// Test tag retrieval by name
-		tagByName, err := tagService.GetTagByName(ctx, createdTag.Name)
+		tagByName, err := tagService.GetTagByName(ctx, userID, createdTag.Name)
 		require.NoError(t, err)
 		assert.Equal(t, createdTag.ID, tagByName.ID)
 
@@ -67,18 +79,18 @@ func TestTagServiceIntegration(t *testing.T) {
 			Description: stringPtr("Updated description"),
 		}
 
-		updatedTag, err := tagService.UpdateTag(ctx, createdTag.ID.String(), updateReq)
+		updatedTag, err := tagService.UpdateTag(ctx, userID, createdTag.ID.String(), updateReq)
 		require.NoError(t, err)
 		assert.Equal(t, updateReq.Name, updatedTag.Name)
 		assert.Equal(t, updateReq.Color, updatedTag.Color)
 		assert.Equal(t, *updateReq.Description, *updatedTag.Description)
 
 		// Test tag deletion
-		err = tagService.DeleteTag(ctx, createdTag.ID.String())
+		err = tagService.DeleteTag(ctx, userID, createdTag.ID.String())
 		require.NoError(t, err)
 
 		// Verify tag is deleted
-		_, err = tagService.GetTag(ctx, createdTag.ID.String())
+		_, err = tagService.GetTag(ctx, userID, createdTag.ID.String())
 		assert.Error(t, err)
 	})
 
@@ -106,7 +118,7 @@ func TestTagServiceIntegration(t *testing.T) {
 
 		var createdTagIDs []string
 		for _, tagReq := range testTags {
-			tag, err := tagService.CreateTag(ctx, tagReq)
+			tag, err := tagService.CreateTag(ctx, userID, tagReq)
 			require.NoError(t, err)
 			createdTagIDs = append(createdTagIDs, tag.ID.String())
 		}
@@ -114,17 +126,17 @@ func TestTagServiceIntegration(t *testing.T) {
 		// Clean up created tags
 		defer func() {
 			for _, tagID := range createdTagIDs {
-				_ = tagService.DeleteTag(ctx, tagID)
+				_ = tagService.DeleteTag(ctx, userID, tagID)
 			}
 		}()
 
 		// Test GetAllTags
-		allTags, err := tagService.GetAllTags(ctx)
+		allTags, err := tagService.GetAllTags(ctx, userID)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(allTags), 3)
 
 		// Test SearchTags
-		searchResults, err := tagService.SearchTags(ctx, "search-", 10)
+		searchResults, err := tagService.SearchTags(ctx, userID, "search-", 10)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(searchResults), 3)
 
@@ -155,7 +167,7 @@ func TestTagServiceIntegration(t *testing.T) {
 					Description: stringPtr(fmt.Sprintf("Concurrent tag %d", index)),
 				}
 
-				tag, err := tagService.CreateTag(ctx, tagReq)
+				tag, err := tagService.CreateTag(ctx, userID, tagReq)
 				if err != nil {
 					errors <- err
 					return
@@ -180,7 +192,7 @@ func TestTagServiceIntegration(t *testing.T) {
 		// Clean up created tags
 		defer func() {
 			for _, tag := range createdTags {
-				_ = tagService.DeleteTag(ctx, tag.ID.String())
+				_ = tagService.DeleteTag(ctx, userID, tag.ID.String())
 			}
 		}()
 
@@ -204,19 +216,19 @@ func TestTagServiceIntegration(t *testing.T) {
 			Description: stringPtr("Original tag"),
 		}
 
-		tag1, err := tagService.CreateTag(ctx, tagReq)
+		tag1, err := tagService.CreateTag(ctx, userID, tagReq)
 		require.NoError(t, err)
 
 		defer func() {
-			_ = tagService.DeleteTag(ctx, tag1.ID.String())
+			_ = tagService.DeleteTag(ctx, userID, tag1.ID.String())
 		}()
 
 		// Try to create duplicate
-		_, err = tagService.CreateTag(ctx, tagReq)
+		_, err = tagService.CreateTag(ctx, userID, tagReq)
 		assert.Error(t, err)
 
 		// Test getting non-existent tag
-		_, err = tagService.GetTag(ctx, uuid.New().String())
+		_, err = tagService.GetTag(ctx, userID, uuid.New().String())
 		assert.Error(t, err)
 
 		// Test updating non-existent tag
@@ -225,17 +237,17 @@ func TestTagServiceIntegration(t *testing.T) {
 			Color:       "#00FF00",
 			Description: stringPtr("Updated"),
 		}
-		_, err = tagService.UpdateTag(ctx, uuid.New().String(), updateReq)
+		_, err = tagService.UpdateTag(ctx, userID, uuid.New().String(), updateReq)
 		assert.Error(t, err)
 
 		// Test deleting non-existent tag
-		err = tagService.DeleteTag(ctx, uuid.New().String())
+		err = tagService.DeleteTag(ctx, userID, uuid.New().String())
 		assert.Error(t, err)
 
 		// Test invalid UUID formats
 		invalidUUIDs := []string{"", "invalid", "not-a-uuid", "12345"}
 		for _, invalidUUID := range invalidUUIDs {
-			_, err = tagService.GetTag(ctx, invalidUUID)
+			_, err = tagService.GetTag(ctx, userID, invalidUUID)
 			assert.Error(t, err)
 		}
 	})
@@ -250,12 +262,12 @@ func TestTagServiceIntegration(t *testing.T) {
 			Description: nil,
 		}
 
-		tag, err := tagService.CreateTag(ctx, minimalTag)
+		tag, err := tagService.CreateTag(ctx, userID, minimalTag)
 		require.NoError(t, err)
 		assert.Nil(t, tag.Description)
 
 		defer func() {
-			_ = tagService.DeleteTag(ctx, tag.ID.String())
+			_ = tagService.DeleteTag(ctx, userID, tag.ID.String())
 		}()
 
 		// Test with maximum length fields
@@ -265,25 +277,195 @@ func TestTagServiceIntegration(t *testing.T) {
 			Description: stringPtr("This is a very long description that tests the maximum length handling for tag descriptions in the database and service layer"),
 		}
 
-		tag2, err := tagService.CreateTag(ctx, maxTag)
+		tag2, err := tagService.CreateTag(ctx, userID, maxTag)
 		require.NoError(t, err)
 
 		defer func() {
-			_ = tagService.DeleteTag(ctx, tag2.ID.String())
+			_ = tagService.DeleteTag(ctx, userID, tag2.ID.String())
 		}()
 
 		// Test search with empty query
-		emptyResults, err := tagService.SearchTags(ctx, "", 10)
+		emptyResults, err := tagService.SearchTags(ctx, userID, "", 10)
 		require.NoError(t, err)
 		assert.NotNil(t, emptyResults)
 
 		// Test search with very large limit
-		largeResults, err := tagService.SearchTags(ctx, "tag", 1000)
+		largeResults, err := tagService.SearchTags(ctx, userID, "tag", 1000)
 		require.NoError(t, err)
 		assert.NotNil(t, largeResults)
 	})
 }
 
+// TestTagServiceIntegration_UserScoping tests per-user tag namespaces and shared tags
+func TestTagServiceIntegration_UserScoping(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	tagService := services.NewTagService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	newUser := func(email string) string {
+		user, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     email,
+			Password:  "password123",
+			FirstName: "Scoped",
+			LastName:  "User",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+		return user.ID.String()
+	}
+	alice := newUser("tags-alice@example.com")
+	bob := newUser("tags-bob@example.com")
+
+	newEntry := func(userID string, tags ...string) *models.LogEntry {
+		start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       "Scoped entry",
+			Type:        models.ActivityDevelopment,
+			StartTime:   start,
+			EndTime:     start.Add(time.Hour),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactPersonal,
+			Tags:        tags,
+		})
+		require.NoError(t, err)
+		return entry
+	}
+
+	t.Run("SameNameInDifferentNamespaces", func(t *testing.T) {
+		aliceTag, err := tagService.CreateTag(ctx, alice, &models.TagRequest{Name: "backend", Color: "#111111"})
+		require.NoError(t, err)
+		bobTag, err := tagService.CreateTag(ctx, bob, &models.TagRequest{Name: "backend", Color: "#222222"})
+		require.NoError(t, err)
+		assert.NotEqual(t, aliceTag.ID, bobTag.ID)
+
+		_, err = tagService.CreateTag(ctx, alice, &models.TagRequest{Name: "backend", Color: "#333333"})
+		require.Error(t, err)
+	})
+
+	t.Run("OtherUsersCannotModifyTags", func(t *testing.T) {
+		aliceTag, err := tagService.GetTagByName(ctx, alice, "backend")
+		require.NoError(t, err)
+
+		_, err = tagService.GetTag(ctx, bob, aliceTag.ID.String())
+		require.Error(t, err)
+
+		_, err = tagService.UpdateTag(ctx, bob, aliceTag.ID.String(), &models.TagRequest{Name: "hijacked", Color: "#000000"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tag not found")
+
+		err = tagService.DeleteTag(ctx, bob, aliceTag.ID.String())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tag not found")
+
+		stillThere, err := tagService.GetTag(ctx, alice, aliceTag.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "backend", stillThere.Name)
+	})
+
+	t.Run("UsageCountsArePerUser", func(t *testing.T) {
+		newEntry(alice, "backend")
+		newEntry(alice, "backend")
+		newEntry(bob, "backend")
+
+		alicePopular, err := tagService.GetPopularTags(ctx, alice, 10)
+		require.NoError(t, err)
+		require.Len(t, alicePopular, 1)
+		assert.Equal(t, 2, alicePopular[0].UsageCount)
+
+		bobPopular, err := tagService.GetPopularTags(ctx, bob, 10)
+		require.NoError(t, err)
+		require.Len(t, bobPopular, 1)
+		assert.Equal(t, 1, bobPopular[0].UsageCount)
+
+		usage, err := tagService.GetUserTagUsage(ctx, alice, false)
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		assert.Equal(t, 2, usage[0].UsageCount)
+	})
+
+	t.Run("SharedTagsAreReadOnlyAndCopiedOnUse", func(t *testing.T) {
+		// Shared tags cannot be created through the API; they are the unused tags
+		// the per-user migration left without an owner
+		description := "Company-wide on-call work"
+		var sharedTag store.Tag
+		require.NoError(t, db.Write(ctx, func(qtx *store.Queries) error {
+			var err error
+			sharedTag, err = qtx.CreateTag(ctx, store.CreateTagParams{
+				Name:        "on-call",
+				Color:       pgtype.Text{String: "#FF0000", Valid: true},
+				Description: pgtype.Text{String: description, Valid: true},
+			})
+			return err
+		}))
+
+		shared, err := tagService.GetTag(ctx, bob, sharedTag.ID.String())
+		require.NoError(t, err)
+		assert.True(t, shared.Shared)
+		assert.Nil(t, shared.UserID)
+
+		_, err = tagService.UpdateTag(ctx, bob, shared.ID.String(), &models.TagRequest{Name: "on-call", Color: "#000000"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+
+		err = tagService.DeleteTag(ctx, bob, shared.ID.String())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+
+		entry := newEntry(bob, "on-call")
+		tags, err := tagService.GetTagsForLogEntry(ctx, bob, entry.ID.String())
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+		assert.NotEqual(t, shared.ID, tags[0].ID)
+		assert.False(t, tags[0].Shared)
+		assert.Equal(t, "#FF0000", tags[0].Color)
+		require.NotNil(t, tags[0].Description)
+		assert.Equal(t, description, *tags[0].Description)
+
+		// The personal copy replaces the shared tag in the user's list
+		all, err := tagService.GetAllTags(ctx, bob)
+		require.NoError(t, err)
+		count := 0
+		for _, tag := range all {
+			if tag.Name == "on-call" {
+				count++
+				assert.False(t, tag.Shared)
+			}
+		}
+		assert.Equal(t, 1, count)
+
+		// Alice has not used it yet, so she still sees the shared tag
+		byName, err := tagService.GetTagByName(ctx, alice, "on-call")
+		require.NoError(t, err)
+		assert.True(t, byName.Shared)
+	})
+
+	t.Run("AssociationsRequireOwnership", func(t *testing.T) {
+		aliceEntry := newEntry(alice)
+		bobTag, err := tagService.GetTagByName(ctx, bob, "backend")
+		require.NoError(t, err)
+
+		err = tagService.AddTagToLogEntry(ctx, bob, aliceEntry.ID.String(), bobTag.ID.String())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+
+		err = tagService.AddTagToLogEntry(ctx, alice, aliceEntry.ID.String(), bobTag.ID.String())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tag not found")
+
+		_, err = tagService.GetTagsForLogEntry(ctx, bob, aliceEntry.ID.String())
+		require.Error(t, err)
+
+		entries, err := tagService.GetLogEntriesForTag(ctx, alice, bobTag.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
 // BenchmarkTagService benchmarks tag service operations
 func BenchmarkTagService(b *testing.B) {
 	db := testutils.DB(b)
@@ -292,6 +474,18 @@ func BenchmarkTagService(b *testing.B) {
 	testLogger := logging.NewTestLogger()
 
 	tagService := services.NewTagService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+	user, err := userService.CreateUser(context.Background(), &models.UserRegistration{
+		Email:     "tag-bench@example.com",
+		Password:  "password123",
+		FirstName: "Tag",
+		LastName:  "Owner",
+		Timezone:  "UTC",
+	})
+	if err != nil {
+		b.Fatalf("Failed to create user: %v", err)
+	}
+	userID := user.ID.String()
 	ctx := context.Background()
 
 	b.Run("CreateTag", func(b *testing.B) {
@@ -305,7 +499,7 @@ func BenchmarkTagService(b *testing.B) {
 				Description: stringPtr("Benchmark tag"),
 			}
 
-			tag, err := tagService.CreateTag(ctx, req)
+			tag, err := tagService.CreateTag(ctx, userID, req)
 			if err != nil {
 				b.Fatalf("Failed to create tag: %v", err)
 			}
@@ -315,7 +509,7 @@ func BenchmarkTagService(b *testing.B) {
 
 		// Cleanup
 		for _, id := range createdIDs {
-			_ = tagService.DeleteTag(ctx, id)
+			_ = tagService.DeleteTag(ctx, userID, id)
 		}
 	})
 
@@ -327,18 +521,18 @@ func BenchmarkTagService(b *testing.B) {
 			Description: stringPtr("Benchmark get tag"),
 		}
 
-		tag, err := tagService.CreateTag(ctx, req)
+		tag, err := tagService.CreateTag(ctx, userID, req)
 		if err != nil {
 			b.Fatalf("Failed to create test tag: %v", err)
 		}
 
 		defer func() {
-			_ = tagService.DeleteTag(ctx, tag.ID.String())
+			_ = tagService.DeleteTag(ctx, userID, tag.ID.String())
 		}()
 
 		b.ResetTimer()
 		for range b.N {
-			_, err := tagService.GetTag(ctx, tag.ID.String())
+			_, err := tagService.GetTag(ctx, userID, tag.ID.String())
 			if err != nil {
 				b.Fatalf("Failed to get tag: %v", err)
 			}
@@ -353,12 +547,12 @@ func BenchmarkTagService(b *testing.B) {
 				Color:       "#FF0000",
 				Description: stringPtr("Search benchmark tag"),
 			}
-			_, _ = tagService.CreateTag(ctx, req)
+			_, _ = tagService.CreateTag(ctx, userID, req)
 		}
 
 		b.ResetTimer()
 		for range b.N {
-			_, err := tagService.SearchTags(ctx, "search-bench", 10)
+			_, err := tagService.SearchTags(ctx, userID, "search-bench", 10)
 			if err != nil {
 				b.Fatalf("Failed to search tags: %v", err)
 			}
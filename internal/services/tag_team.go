@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// Team tags are the vocabulary a team shares. Members see them next to their own
+// tags and using one on a log entry creates a personal copy, as with shared tags;
+// only owners and managers may create, edit or delete them.
+
+// GetTeamTags lists the tags of a team the user belongs to
+func (s *TagService) GetTeamTags(ctx context.Context, userID, teamID string) ([]*models.Tag, error) {
+	userUUID, teamUUID, err := s.parseTeamIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Getting team tags", "user_id", userID, "team_id", teamID)
+
+	var tags []*models.Tag
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionView); err != nil {
+			return err
+		}
+
+		sqlcTags, err := qtx.GetTeamTags(ctx, uuidToPgUUID(&teamUUID))
+		if err != nil {
+			return fmt.Errorf("failed to get team tags: %w", err)
+		}
+
+		tags = make([]*models.Tag, len(sqlcTags))
+		for i, sqlcTag := range sqlcTags {
+			tags[i] = s.sqlcToModel(sqlcTag)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get team tags", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	s.logger.Info("Team tags retrieved successfully", "team_id", teamID, "count", len(tags))
+	return tags, nil
+}
+
+// CreateTeamTag adds a tag to the team's vocabulary. Requires an owner or manager.
+func (s *TagService) CreateTeamTag(ctx context.Context, userID, teamID string, req *models.TagRequest) (*models.Tag, error) {
+	userUUID, teamUUID, err := s.parseTeamIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateTeamTagRequest(req); err != nil {
+		s.logger.Warn("Invalid team tag request", "team_id", teamID, "tag_name", req.Name, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info("Creating team tag", "user_id", userID, "team_id", teamID, "tag_name", req.Name, "color", req.Color)
+
+	var tag *models.Tag
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageTags); err != nil {
+			return err
+		}
+		if err := checkTeamTagNameAvailable(ctx, qtx, teamUUID, uuid.Nil, req.Name); err != nil {
+			return err
+		}
+
+		sqlcTag, err := qtx.CreateTeamTag(ctx, store.CreateTeamTagParams{
+			Name:        req.Name,
+			Color:       stringToPgText(&req.Color),
+			Description: stringToPgText(req.Description),
+			TeamID:      uuidToPgUUID(&teamUUID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create team tag: %w", err)
+		}
+
+		tag = s.sqlcToModel(sqlcTag)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create team tag", "user_id", userID, "team_id", teamID, "tag_name", req.Name)
+		return nil, err
+	}
+
+	s.logger.Info("Team tag created successfully", "team_id", teamID, "tag_id", tag.ID, "tag_name", tag.Name)
+	return tag, nil
+}
+
+// UpdateTeamTag edits a team tag. Personal copies made from it keep their own
+// name, color and description. Requires an owner or manager.
+func (s *TagService) UpdateTeamTag(ctx context.Context, userID, teamID, tagID string, req *models.TagRequest) (*models.Tag, error) {
+	userUUID, teamUUID, err := s.parseTeamIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return nil, fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	if err := s.validateTeamTagRequest(req); err != nil {
+		s.logger.Warn("Invalid team tag request", "team_id", teamID, "tag_id", tagID, "tag_name", req.Name, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info("Updating team tag", "user_id", userID, "team_id", teamID, "tag_id", tagID, "tag_name", req.Name)
+
+	var tag *models.Tag
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageTags); err != nil {
+			return err
+		}
+		if err := checkTeamTagNameAvailable(ctx, qtx, teamUUID, tagUUID, req.Name); err != nil {
+			return err
+		}
+
+		sqlcTag, err := qtx.UpdateTeamTag(ctx, store.UpdateTeamTagParams{
+			ID:          tagUUID,
+			TeamID:      uuidToPgUUID(&teamUUID),
+			Name:        req.Name,
+			Color:       stringToPgText(&req.Color),
+			Description: stringToPgText(req.Description),
+		})
+		if database.NoRows(err) {
+			return fmt.Errorf("tag not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update team tag: %w", err)
+		}
+
+		tag = s.sqlcToModel(sqlcTag)
+		return nil
+	}); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.logger.Warn("Team tag not found for update", "team_id", teamID, "tag_id", tagID)
+		} else {
+			s.logger.LogError(ctx, err, "Failed to update team tag", "user_id", userID, "team_id", teamID, "tag_id", tagID)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Team tag updated successfully", "team_id", teamID, "tag_id", tagID, "tag_name", tag.Name)
+	return tag, nil
+}
+
+// DeleteTeamTag removes a tag from the team's vocabulary. Personal copies made
+// from it are kept. Requires an owner or manager.
+func (s *TagService) DeleteTeamTag(ctx context.Context, userID, teamID, tagID string) error {
+	userUUID, teamUUID, err := s.parseTeamIDs(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+
+	tagUUID, err := uuid.Parse(tagID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid tag ID format", "tag_id", tagID)
+		return fmt.Errorf("invalid tag ID: %w", err)
+	}
+
+	s.logger.Info("Deleting team tag", "user_id", userID, "team_id", teamID, "tag_id", tagID)
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageTags); err != nil {
+			return err
+		}
+
+		rowsAffected, err := qtx.DeleteTeamTag(ctx, store.DeleteTeamTagParams{
+			ID:     tagUUID,
+			TeamID: uuidToPgUUID(&teamUUID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete team tag: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("tag not found")
+		}
+		return nil
+	}); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.logger.Warn("Team tag not found for deletion", "team_id", teamID, "tag_id", tagID)
+		} else {
+			s.logger.LogError(ctx, err, "Failed to delete team tag", "user_id", userID, "team_id", teamID, "tag_id", tagID)
+		}
+		return err
+	}
+
+	s.logger.Info("Team tag deleted successfully", "team_id", teamID, "tag_id", tagID)
+	return nil
+}
+
+// validateTeamTagRequest validates a team tag. Team tags are flat: members
+// nest their personal copies as they like.
+func (s *TagService) validateTeamTagRequest(req *models.TagRequest) error {
+	if err := s.validateTagRequest(req); err != nil {
+		return err
+	}
+	if req.ParentID != nil {
+		return fmt.Errorf("team tags cannot have a parent")
+	}
+	return nil
+}
+
+// parseTeamIDs parses the IDs used by the team tag methods
+func (s *TagService) parseTeamIDs(ctx context.Context, userID, teamID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	teamUUID, err := uuid.Parse(teamID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid team ID format", "user_id", userID, "team_id", teamID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid team ID: %w", err)
+	}
+
+	return userUUID, teamUUID, nil
+}
+
+// checkTeamTagNameAvailable rejects a name already used by another tag of the team
+func checkTeamTagNameAvailable(ctx context.Context, qtx *store.Queries, teamUUID, tagUUID uuid.UUID, name string) error {
+	tags, err := qtx.GetTeamTags(ctx, uuidToPgUUID(&teamUUID))
+	if err != nil {
+		return fmt.Errorf("failed to get team tags: %w", err)
+	}
+	for _, tag := range tags {
+		if tag.Name == name && tag.ID != tagUUID {
+			return fmt.Errorf("a team tag named %q already exists", name)
+		}
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -305,3 +307,25 @@ func TestTagService_EnsureTagExistsLogic(t *testing.T) {
 	assert.Equal(t, testColor, req.Color)
 	assert.Nil(t, req.Description)
 }
+
+func TestTagService_SqlcToModelOwnership(t *testing.T) {
+	tagService := &TagService{}
+	owner := uuid.New()
+
+	owned := tagService.sqlcToModel(store.Tag{
+		ID:     uuid.New(),
+		Name:   "owned",
+		UserID: pgtype.UUID{Bytes: owner, Valid: true},
+	})
+	if assert.NotNil(t, owned.UserID) {
+		assert.Equal(t, owner, *owned.UserID)
+	}
+	assert.False(t, owned.Shared)
+
+	shared := tagService.sqlcToModel(store.Tag{
+		ID:   uuid.New(),
+		Name: "shared",
+	})
+	assert.Nil(t, shared.UserID)
+	assert.True(t, shared.Shared)
+}
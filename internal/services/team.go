@@ -0,0 +1,617 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// TeamService handles shared team workspaces, their members and shared projects
+type TeamService struct {
+	db       *database.DB
+	logger   *logging.Logger
+	projects *ProjectService
+}
+
+// NewTeamService creates a new TeamService instance
+func NewTeamService(db *database.DB, logger *logging.Logger, projectService *ProjectService) *TeamService {
+	return &TeamService{
+		db:       db,
+		logger:   logger.WithComponent("team_service"),
+		projects: projectService,
+	}
+}
+
+// CreateTeam creates a team owned by the user
+func (s *TeamService) CreateTeam(ctx context.Context, userID string, req *models.TeamRequest) (*models.Team, error) {
+	if err := validateTeamRequest(req); err != nil {
+		return nil, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateTeam", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	s.logger.Info("Creating team", "user_id", userID, "team_name", req.Name)
+
+	var team *models.Team
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcTeam, err := qtx.CreateTeam(ctx, store.CreateTeamParams{
+			Name:        strings.TrimSpace(req.Name),
+			Description: stringToPgText(req.Description),
+			CreatedBy:   uuidToPgUUID(&userUUID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create team: %w", err)
+		}
+
+		if _, err := qtx.AddTeamMember(ctx, store.AddTeamMemberParams{
+			TeamID: sqlcTeam.ID,
+			UserID: userUUID,
+			Role:   string(models.TeamRoleOwner),
+		}); err != nil {
+			return fmt.Errorf("failed to add team owner: %w", err)
+		}
+
+		team = teamToModel(sqlcTeam, models.TeamRoleOwner, 1)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create team", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Team created successfully", "user_id", userID, "team_id", team.ID)
+	return team, nil
+}
+
+// GetUserTeams lists the teams the user belongs to, with the user's role in each
+func (s *TeamService) GetUserTeams(ctx context.Context, userID string) ([]*models.Team, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetUserTeams", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var teams []*models.Team
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		rows, err := qtx.GetTeamsByUser(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get teams: %w", err)
+		}
+
+		teams = make([]*models.Team, len(rows))
+		for i, row := range rows {
+			teams[i] = teamToModel(store.Team{
+				ID:          row.ID,
+				Name:        row.Name,
+				Description: row.Description,
+				CreatedBy:   row.CreatedBy,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+			}, models.TeamRole(row.Role), int(row.MemberCount))
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get user teams", "user_id", userID)
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetTeam returns a team the user belongs to
+func (s *TeamService) GetTeam(ctx context.Context, userID, teamID string) (*models.Team, error) {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var team *models.Team
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionView)
+		if err != nil {
+			return err
+		}
+
+		sqlcTeam, err := qtx.GetTeamByID(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team: %w", err)
+		}
+		members, err := qtx.GetTeamMembers(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team members: %w", err)
+		}
+
+		team = teamToModel(sqlcTeam, role, len(members))
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get team", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// UpdateTeam renames a team or changes its description; owners only
+func (s *TeamService) UpdateTeam(ctx context.Context, userID, teamID string, req *models.TeamRequest) (*models.Team, error) {
+	if err := validateTeamRequest(req); err != nil {
+		return nil, err
+	}
+
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var team *models.Team
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageTeam)
+		if err != nil {
+			return err
+		}
+
+		sqlcTeam, err := qtx.UpdateTeam(ctx, store.UpdateTeamParams{
+			ID:          teamUUID,
+			Name:        strings.TrimSpace(req.Name),
+			Description: stringToPgText(req.Description),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+		members, err := qtx.GetTeamMembers(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team members: %w", err)
+		}
+
+		team = teamToModel(sqlcTeam, role, len(members))
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update team", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	s.logger.Info("Team updated successfully", "user_id", userID, "team_id", teamID)
+	return team, nil
+}
+
+// DeleteTeam deletes a team and its memberships; shared projects and all log entries are kept
+func (s *TeamService) DeleteTeam(ctx context.Context, userID, teamID string) error {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageTeam); err != nil {
+			return err
+		}
+		return qtx.DeleteTeam(ctx, teamUUID)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete team", "user_id", userID, "team_id", teamID)
+		return err
+	}
+
+	s.logger.Info("Team deleted successfully", "user_id", userID, "team_id", teamID)
+	return nil
+}
+
+// GetTeamMembers lists the members of a team the user belongs to
+func (s *TeamService) GetTeamMembers(ctx context.Context, userID, teamID string) ([]*models.TeamMember, error) {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*models.TeamMember
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionView); err != nil {
+			return err
+		}
+
+		rows, err := qtx.GetTeamMembers(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team members: %w", err)
+		}
+
+		members = make([]*models.TeamMember, len(rows))
+		for i, row := range rows {
+			members[i] = &models.TeamMember{
+				UserID:    row.UserID,
+				Email:     row.Email,
+				FirstName: row.FirstName,
+				LastName:  row.LastName,
+				Role:      models.TeamRole(row.Role),
+				JoinedAt:  row.JoinedAt.Time,
+			}
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get team members", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddTeamMember adds an existing user to the team. Managers may add members and
+// viewers; only owners may add managers and owners.
+func (s *TeamService) AddTeamMember(ctx context.Context, userID, teamID string, req *models.TeamMemberRequest) (*models.TeamMember, error) {
+	if !req.Role.IsValid() {
+		return nil, fmt.Errorf("invalid team role: %s", req.Role)
+	}
+	if strings.TrimSpace(req.Email) == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Adding team member", "user_id", userID, "team_id", teamID, "role", req.Role)
+
+	var member *models.TeamMember
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageMembers)
+		if err != nil {
+			return err
+		}
+		if !role.CanAssign(req.Role) {
+			return fmt.Errorf("permission denied: the %s role cannot add a %s", role, req.Role)
+		}
+
+		user, err := qtx.GetUserByEmail(ctx, strings.TrimSpace(req.Email))
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if _, err := qtx.GetTeamMember(ctx, store.GetTeamMemberParams{TeamID: teamUUID, UserID: user.ID}); err == nil {
+			return fmt.Errorf("user is already a member of this team")
+		} else if !database.NoRows(err) {
+			return fmt.Errorf("failed to get team membership: %w", err)
+		}
+
+		sqlcMember, err := qtx.AddTeamMember(ctx, store.AddTeamMemberParams{
+			TeamID: teamUUID,
+			UserID: user.ID,
+			Role:   string(req.Role),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add team member: %w", err)
+		}
+
+		member = &models.TeamMember{
+			UserID:    user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Role:      models.TeamRole(sqlcMember.Role),
+			JoinedAt:  sqlcMember.JoinedAt.Time,
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to add team member", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	s.logger.Info("Team member added successfully", "user_id", userID, "team_id", teamID, "member_id", member.UserID)
+	return member, nil
+}
+
+// UpdateTeamMemberRole changes the role of a member. The caller must be allowed to
+// assign both the current and the new role, and a team always keeps an owner.
+func (s *TeamService) UpdateTeamMemberRole(ctx context.Context, userID, teamID, memberID string, req *models.TeamRoleRequest) (*models.TeamMember, error) {
+	if !req.Role.IsValid() {
+		return nil, fmt.Errorf("invalid team role: %s", req.Role)
+	}
+
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	memberUUID, err := uuid.Parse(memberID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid member ID: %w", err)
+	}
+
+	var member *models.TeamMember
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionManageMembers)
+		if err != nil {
+			return err
+		}
+
+		current, err := s.checkMemberChange(ctx, qtx, teamUUID, memberUUID, role, req.Role)
+		if err != nil {
+			return err
+		}
+		if !role.CanAssign(current) {
+			return fmt.Errorf("permission denied: the %s role cannot change a %s", role, current)
+		}
+
+		if _, err := qtx.UpdateTeamMemberRole(ctx, store.UpdateTeamMemberRoleParams{
+			TeamID: teamUUID,
+			UserID: memberUUID,
+			Role:   string(req.Role),
+		}); err != nil {
+			return fmt.Errorf("failed to update team role: %w", err)
+		}
+
+		rows, err := qtx.GetTeamMembers(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team members: %w", err)
+		}
+		for _, row := range rows {
+			if row.UserID == memberUUID {
+				member = &models.TeamMember{
+					UserID:    row.UserID,
+					Email:     row.Email,
+					FirstName: row.FirstName,
+					LastName:  row.LastName,
+					Role:      models.TeamRole(row.Role),
+					JoinedAt:  row.JoinedAt.Time,
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update team role", "user_id", userID, "team_id", teamID, "member_id", memberID)
+		return nil, err
+	}
+
+	s.logger.Info("Team role updated successfully", "user_id", userID, "team_id", teamID, "member_id", memberID, "role", req.Role)
+	return member, nil
+}
+
+// RemoveTeamMember removes a member from the team. Members may always leave;
+// removing someone else follows the same rules as changing their role.
+func (s *TeamService) RemoveTeamMember(ctx context.Context, userID, teamID, memberID string) error {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	memberUUID, err := uuid.Parse(memberID)
+	if err != nil {
+		return fmt.Errorf("invalid member ID: %w", err)
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		permission := models.TeamPermissionManageMembers
+		if memberUUID == userUUID {
+			permission = models.TeamPermissionView
+		}
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, permission)
+		if err != nil {
+			return err
+		}
+
+		current, err := s.checkMemberChange(ctx, qtx, teamUUID, memberUUID, role, "")
+		if err != nil {
+			return err
+		}
+		if memberUUID != userUUID && !role.CanAssign(current) {
+			return fmt.Errorf("permission denied: the %s role cannot remove a %s", role, current)
+		}
+
+		if _, err := qtx.RemoveTeamMember(ctx, store.RemoveTeamMemberParams{
+			TeamID: teamUUID,
+			UserID: memberUUID,
+		}); err != nil {
+			return fmt.Errorf("failed to remove team member: %w", err)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove team member", "user_id", userID, "team_id", teamID, "member_id", memberID)
+		return err
+	}
+
+	s.logger.Info("Team member removed successfully", "user_id", userID, "team_id", teamID, "member_id", memberID)
+	return nil
+}
+
+// checkMemberChange returns the current role of a member about to get newRole, or
+// to be removed when newRole is empty, refusing changes that leave the team without an owner
+func (s *TeamService) checkMemberChange(ctx context.Context, qtx *store.Queries, teamUUID, memberUUID uuid.UUID, callerRole, newRole models.TeamRole) (models.TeamRole, error) {
+	target, err := qtx.GetTeamMember(ctx, store.GetTeamMemberParams{TeamID: teamUUID, UserID: memberUUID})
+	if err != nil {
+		if database.NoRows(err) {
+			return "", fmt.Errorf("team member not found")
+		}
+		return "", fmt.Errorf("failed to get team membership: %w", err)
+	}
+	current := models.TeamRole(target.Role)
+
+	if newRole != "" && !callerRole.CanAssign(newRole) {
+		return current, fmt.Errorf("permission denied: the %s role cannot assign the %s role", callerRole, newRole)
+	}
+
+	if current == models.TeamRoleOwner && newRole != models.TeamRoleOwner {
+		owners, err := qtx.CountTeamOwners(ctx, teamUUID)
+		if err != nil {
+			return current, fmt.Errorf("failed to count team owners: %w", err)
+		}
+		if owners <= 1 {
+			return current, fmt.Errorf("a team needs at least one owner, promote another member first")
+		}
+	}
+
+	return current, nil
+}
+
+// GetTeamProjects lists the projects shared with a team the user belongs to
+func (s *TeamService) GetTeamProjects(ctx context.Context, userID, teamID string) ([]*models.Project, error) {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*models.Project
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionView); err != nil {
+			return err
+		}
+
+		sqlcProjects, err := qtx.GetTeamProjects(ctx, teamUUID)
+		if err != nil {
+			return fmt.Errorf("failed to get team projects: %w", err)
+		}
+
+		projects = make([]*models.Project, len(sqlcProjects))
+		for i, sqlcProject := range sqlcProjects {
+			projects[i] = s.projects.sqlcToModel(sqlcProject)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get team projects", "user_id", userID, "team_id", teamID)
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// ShareProject shares one of the user's own projects with a team. Members of the
+// team can then see the project and, unless they are viewers, log time on it.
+func (s *TeamService) ShareProject(ctx context.Context, userID, teamID string, req *models.TeamProjectRequest) error {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		if _, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionShareProjects); err != nil {
+			return err
+		}
+
+		project, err := qtx.GetProjectByID(ctx, req.ProjectID)
+		if err != nil || project.CreatedBy != userUUID {
+			return fmt.Errorf("project not found")
+		}
+		if project.ArchivedAt.Valid {
+			return fmt.Errorf("archived projects cannot be shared")
+		}
+
+		if _, err := qtx.ShareProjectWithTeam(ctx, store.ShareProjectWithTeamParams{
+			TeamID:    teamUUID,
+			ProjectID: req.ProjectID,
+		}); err != nil {
+			return fmt.Errorf("failed to share project: %w", err)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to share project", "user_id", userID, "team_id", teamID, "project_id", req.ProjectID)
+		return err
+	}
+
+	s.logger.Info("Project shared successfully", "user_id", userID, "team_id", teamID, "project_id", req.ProjectID)
+	return nil
+}
+
+// UnshareProject stops sharing a project with a team. The project owner and team
+// managers may unshare; entries already logged on the project are kept.
+func (s *TeamService) UnshareProject(ctx context.Context, userID, teamID, projectID string) error {
+	userUUID, teamUUID, err := s.parseIDs(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	projectUUID, err := uuid.Parse(projectID)
+	if err != nil {
+		return fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		role, err := authorizeTeam(ctx, qtx, teamUUID, userUUID, models.TeamPermissionView)
+		if err != nil {
+			return err
+		}
+
+		project, err := qtx.GetProjectByID(ctx, projectUUID)
+		if err != nil {
+			return fmt.Errorf("project not found")
+		}
+		if project.CreatedBy != userUUID && !role.Can(models.TeamPermissionManageMembers) {
+			return fmt.Errorf("permission denied: only the project owner or a team manager can unshare a project")
+		}
+
+		rowsAffected, err := qtx.UnshareProjectFromTeam(ctx, store.UnshareProjectFromTeamParams{
+			TeamID:    teamUUID,
+			ProjectID: projectUUID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to unshare project: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("project not found in this team")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to unshare project", "user_id", userID, "team_id", teamID, "project_id", projectID)
+		return err
+	}
+
+	s.logger.Info("Project unshared successfully", "user_id", userID, "team_id", teamID, "project_id", projectID)
+	return nil
+}
+
+// parseIDs parses the user and team IDs of a request
+func (s *TeamService) parseIDs(ctx context.Context, userID, teamID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	teamUUID, err := uuid.Parse(teamID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid team ID format", "user_id", userID, "team_id", teamID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid team ID: %w", err)
+	}
+
+	return userUUID, teamUUID, nil
+}
+
+// validateTeamRequest validates the team request
+func validateTeamRequest(req *models.TeamRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("team name is required")
+	}
+	if len(req.Name) > 200 {
+		return fmt.Errorf("team name must be at most 200 characters")
+	}
+	if req.Description != nil && len(*req.Description) > 1000 {
+		return fmt.Errorf("team description must be at most 1000 characters")
+	}
+	return nil
+}
+
+// teamToModel converts a team row, with the role of the user it was loaded for
+func teamToModel(sqlcTeam store.Team, role models.TeamRole, memberCount int) *models.Team {
+	return &models.Team{
+		ID:          sqlcTeam.ID,
+		Name:        sqlcTeam.Name,
+		Description: pgTextToString(sqlcTeam.Description),
+		CreatedBy:   pgUUIDToUUID(sqlcTeam.CreatedBy),
+		Role:        role,
+		MemberCount: memberCount,
+		CreatedAt:   sqlcTeam.CreatedAt.Time,
+		UpdatedAt:   sqlcTeam.UpdatedAt.Time,
+	}
+}
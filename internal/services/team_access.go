@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/google/uuid"
+)
+
+// Authorization for shared workspaces. Everything a user creates stays scoped by
+// user_id/created_by; teams only widen access to the projects shared with them.
+// Callers that are not members get "not found" so team and project IDs do not
+// leak; members without the required role get "permission denied".
+
+// authorizeTeam returns the caller's role in the team when it grants permission
+func authorizeTeam(ctx context.Context, qtx *store.Queries, teamID, userID uuid.UUID, permission models.TeamPermission) (models.TeamRole, error) {
+	member, err := qtx.GetTeamMember(ctx, store.GetTeamMemberParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return "", fmt.Errorf("team not found")
+		}
+		return "", fmt.Errorf("failed to get team membership: %w", err)
+	}
+
+	role := models.TeamRole(member.Role)
+	if !role.Can(permission) {
+		return role, fmt.Errorf("permission denied: the %s role cannot %s", role, permissionAction(permission))
+	}
+
+	return role, nil
+}
+
+// authorizeProject returns a project the user owns, or one shared with a team
+// where the user's role grants permission
+func authorizeProject(ctx context.Context, qtx *store.Queries, projectID, userID uuid.UUID, permission models.TeamPermission) (store.Project, error) {
+	project, err := qtx.GetProjectByID(ctx, projectID)
+	if err != nil {
+		if database.NoRows(err) {
+			return store.Project{}, fmt.Errorf("project not found")
+		}
+		return store.Project{}, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project.CreatedBy == userID {
+		return project, nil
+	}
+
+	roles, err := qtx.GetProjectTeamRoles(ctx, store.GetProjectTeamRolesParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return store.Project{}, fmt.Errorf("failed to get project teams: %w", err)
+	}
+	if len(roles) == 0 {
+		return store.Project{}, fmt.Errorf("project not found")
+	}
+
+	for _, row := range roles {
+		if models.TeamRole(row.Role).Can(permission) {
+			return project, nil
+		}
+	}
+
+	return store.Project{}, fmt.Errorf("permission denied: your team role cannot %s", permissionAction(permission))
+}
+
+// permissionAction describes a permission for error messages
+func permissionAction(permission models.TeamPermission) string {
+	switch permission {
+	case models.TeamPermissionView:
+		return "view this team"
+	case models.TeamPermissionLogTime:
+		return "log time on shared projects"
+	case models.TeamPermissionShareProjects:
+		return "share projects"
+	case models.TeamPermissionViewAnalytics:
+		return "view team analytics"
+	case models.TeamPermissionManageMembers:
+		return "manage members"
+	case models.TeamPermissionManageTags:
+		return "manage team tags"
+	case models.TeamPermissionManageTeam:
+		return "manage the team"
+	}
+	return string(permission)
+}
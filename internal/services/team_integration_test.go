@@ -0,0 +1,279 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTeamService_Workspace tests membership, roles, shared projects and team analytics
+func TestTeamService_Workspace(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	projectService := services.NewProjectService(db, testLogger)
+	teamService := services.NewTeamService(db, testLogger, projectService)
+	analyticsService := services.NewAnalyticsService(db, testLogger)
+	logEntryService := services.NewLogEntryService(db, testLogger)
+	tagService := services.NewTagService(db, testLogger)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	newUser := func(email, first string) string {
+		user, err := userService.CreateUser(ctx, &models.UserRegistration{
+			Email:     email,
+			Password:  "password123",
+			FirstName: first,
+			LastName:  "Team",
+			Timezone:  "UTC",
+		})
+		require.NoError(t, err)
+		return user.ID.String()
+	}
+	ownerID := newUser("owner@example.com", "Olivia")
+	managerID := newUser("manager@example.com", "Manuel")
+	memberID := newUser("member@example.com", "Mei")
+	viewerID := newUser("viewer@example.com", "Victor")
+	outsiderID := newUser("outsider@example.com", "Oscar")
+
+	team, err := teamService.CreateTeam(ctx, ownerID, &models.TeamRequest{Name: "Platform"})
+	require.NoError(t, err)
+	assert.Equal(t, models.TeamRoleOwner, team.Role)
+	teamID := team.ID.String()
+
+	t.Run("Members", func(t *testing.T) {
+		_, err := teamService.AddTeamMember(ctx, ownerID, teamID, &models.TeamMemberRequest{Email: "manager@example.com", Role: models.TeamRoleManager})
+		require.NoError(t, err)
+
+		// Managers add members and viewers, but not other managers
+		_, err = teamService.AddTeamMember(ctx, managerID, teamID, &models.TeamMemberRequest{Email: "member@example.com", Role: models.TeamRoleMember})
+		require.NoError(t, err)
+		_, err = teamService.AddTeamMember(ctx, managerID, teamID, &models.TeamMemberRequest{Email: "viewer@example.com", Role: models.TeamRoleManager})
+		assert.ErrorContains(t, err, "permission denied")
+		_, err = teamService.AddTeamMember(ctx, managerID, teamID, &models.TeamMemberRequest{Email: "viewer@example.com", Role: models.TeamRoleViewer})
+		require.NoError(t, err)
+
+		_, err = teamService.AddTeamMember(ctx, ownerID, teamID, &models.TeamMemberRequest{Email: "member@example.com", Role: models.TeamRoleMember})
+		assert.Error(t, err, "already a member")
+		_, err = teamService.AddTeamMember(ctx, ownerID, teamID, &models.TeamMemberRequest{Email: "nobody@example.com", Role: models.TeamRoleMember})
+		assert.Error(t, err)
+
+		_, err = teamService.AddTeamMember(ctx, memberID, teamID, &models.TeamMemberRequest{Email: "outsider@example.com", Role: models.TeamRoleViewer})
+		assert.ErrorContains(t, err, "permission denied")
+
+		members, err := teamService.GetTeamMembers(ctx, viewerID, teamID)
+		require.NoError(t, err)
+		require.Len(t, members, 4)
+		assert.Equal(t, models.TeamRoleOwner, members[0].Role)
+
+		teams, err := teamService.GetUserTeams(ctx, memberID)
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, models.TeamRoleMember, teams[0].Role)
+		assert.Equal(t, 4, teams[0].MemberCount)
+	})
+
+	t.Run("OutsidersSeeNothing", func(t *testing.T) {
+		_, err := teamService.GetTeam(ctx, outsiderID, teamID)
+		assert.ErrorContains(t, err, "not found")
+		_, err = teamService.GetTeamMembers(ctx, outsiderID, teamID)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("LastOwner", func(t *testing.T) {
+		err := teamService.RemoveTeamMember(ctx, ownerID, teamID, ownerID)
+		assert.Error(t, err)
+		_, err = teamService.UpdateTeamMemberRole(ctx, ownerID, teamID, ownerID, &models.TeamRoleRequest{Role: models.TeamRoleMember})
+		assert.Error(t, err)
+		assert.ErrorContains(t, teamService.DeleteTeam(ctx, managerID, teamID), "permission denied")
+	})
+
+	project, err := projectService.CreateProject(ctx, ownerID, &models.ProjectRequest{
+		Name:   "Billing",
+		Color:  "#336699",
+		Status: models.ProjectActive,
+	})
+	require.NoError(t, err)
+	projectID := project.ID
+
+	t.Run("SharedProjects", func(t *testing.T) {
+		// Not shared yet: other users cannot see or log time on it
+		_, err := projectService.GetProject(ctx, memberID, projectID.String())
+		assert.ErrorContains(t, err, "not found")
+
+		// Only the project owner can share it
+		err = teamService.ShareProject(ctx, managerID, teamID, &models.TeamProjectRequest{ProjectID: projectID})
+		assert.Error(t, err)
+		require.NoError(t, teamService.ShareProject(ctx, ownerID, teamID, &models.TeamProjectRequest{ProjectID: projectID}))
+
+		projects, err := teamService.GetTeamProjects(ctx, viewerID, teamID)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		assert.Equal(t, projectID, projects[0].ID)
+
+		_, err = projectService.GetProject(ctx, viewerID, projectID.String())
+		assert.NoError(t, err)
+		_, err = projectService.GetProject(ctx, outsiderID, projectID.String())
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	monday := time.Date(2025, 5, 12, 9, 0, 0, 0, time.UTC)
+	logTime := func(userID string, minutes int) error {
+		_, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       "Invoices",
+			Type:        models.ActivityDevelopment,
+			ProjectID:   &projectID,
+			StartTime:   monday,
+			EndTime:     monday.Add(time.Duration(minutes) * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+		})
+		return err
+	}
+
+	t.Run("LogTimeOnSharedProject", func(t *testing.T) {
+		assert.NoError(t, logTime(ownerID, 60))
+		assert.NoError(t, logTime(memberID, 120))
+		assert.ErrorContains(t, logTime(viewerID, 30), "permission denied")
+		assert.ErrorContains(t, logTime(outsiderID, 30), "not found")
+	})
+
+	t.Run("TeamAnalytics", func(t *testing.T) {
+		start, end := monday.AddDate(0, 0, -1), monday.AddDate(0, 0, 1)
+
+		_, err := analyticsService.GetTeamAnalytics(ctx, memberID, teamID, start, end)
+		assert.ErrorContains(t, err, "permission denied")
+
+		// Members are anonymous until they opt in to being listed
+		analytics, err := analyticsService.GetTeamAnalytics(ctx, managerID, teamID, start, end)
+		require.NoError(t, err)
+		assert.Equal(t, 180, analytics.TotalMinutes)
+		assert.Equal(t, 2, analytics.Contributors)
+		assert.Empty(t, analytics.Members)
+
+		_, err = userService.UpdateUserProfile(ctx, memberID, &models.UserProfileRequest{
+			FirstName:   "Mei",
+			LastName:    "Team",
+			Timezone:    "UTC",
+			Preferences: map[string]any{"team_sharing": "named"},
+		})
+		require.NoError(t, err)
+
+		// The owner is now the only anonymous member with activity, so theirs is left out
+		analytics, err = analyticsService.GetTeamAnalytics(ctx, managerID, teamID, start, end)
+		require.NoError(t, err)
+		assert.Equal(t, 120, analytics.TotalMinutes)
+		assert.Equal(t, 1, analytics.Contributors)
+		require.Len(t, analytics.Members, 1)
+		assert.Equal(t, "Mei Team", analytics.Members[0].Name)
+
+		// A member who keeps activity private drops out of team totals
+		_, err = userService.UpdateUserProfile(ctx, memberID, &models.UserProfileRequest{
+			FirstName:   "Mei",
+			LastName:    "Team",
+			Timezone:    "UTC",
+			Preferences: map[string]any{"team_sharing": "private"},
+		})
+		require.NoError(t, err)
+
+		analytics, err = analyticsService.GetTeamAnalytics(ctx, managerID, teamID, start, end)
+		require.NoError(t, err)
+		assert.Zero(t, analytics.TotalMinutes)
+		assert.Zero(t, analytics.Contributors)
+		assert.Equal(t, 4, analytics.HiddenMembers)
+		assert.Empty(t, analytics.Members)
+	})
+
+	t.Run("TeamTags", func(t *testing.T) {
+		req := &models.TagRequest{Name: "release", Color: "#AA3355"}
+
+		// Owners and managers curate the team's tags; members use them
+		_, err := tagService.CreateTeamTag(ctx, memberID, teamID, req)
+		assert.ErrorContains(t, err, "permission denied")
+		_, err = tagService.CreateTeamTag(ctx, outsiderID, teamID, req)
+		assert.ErrorContains(t, err, "not found")
+
+		tag, err := tagService.CreateTeamTag(ctx, managerID, teamID, req)
+		require.NoError(t, err)
+		require.NotNil(t, tag.TeamID)
+		assert.Equal(t, team.ID, *tag.TeamID)
+		assert.Nil(t, tag.UserID)
+		_, err = tagService.CreateTeamTag(ctx, ownerID, teamID, req)
+		assert.ErrorContains(t, err, "already exists")
+
+		teamTags, err := tagService.GetTeamTags(ctx, viewerID, teamID)
+		require.NoError(t, err)
+		require.Len(t, teamTags, 1)
+		_, err = tagService.GetTeamTags(ctx, outsiderID, teamID)
+		assert.ErrorContains(t, err, "not found")
+
+		_, err = tagService.GetTag(ctx, memberID, tag.ID.String())
+		assert.NoError(t, err)
+		_, err = tagService.GetTag(ctx, outsiderID, tag.ID.String())
+		assert.Error(t, err)
+		_, err = tagService.UpdateTag(ctx, memberID, tag.ID.String(), &models.TagRequest{Name: "mine", Color: "#000000"})
+		assert.ErrorContains(t, err, "read-only")
+
+		// Using a team tag gives the member a personal copy with the team's color
+		entry, err := logEntryService.CreateLogEntry(ctx, memberID, &models.LogEntryRequest{
+			Title:       "Release notes",
+			Type:        models.ActivityDocumentation,
+			StartTime:   monday,
+			EndTime:     monday.Add(30 * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+			Tags:        []string{"release"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"release"}, entry.Tags)
+
+		copied, err := tagService.GetTagByName(ctx, memberID, "release")
+		require.NoError(t, err)
+		assert.NotEqual(t, tag.ID, copied.ID)
+		assert.Nil(t, copied.TeamID)
+		assert.Equal(t, "#AA3355", copied.Color)
+
+		outsiderTags, err := tagService.GetAllTags(ctx, outsiderID)
+		require.NoError(t, err)
+		for _, outsiderTag := range outsiderTags {
+			assert.NotEqual(t, tag.ID, outsiderTag.ID)
+		}
+
+		updated, err := tagService.UpdateTeamTag(ctx, ownerID, teamID, tag.ID.String(), &models.TagRequest{Name: "releases", Color: "#112233"})
+		require.NoError(t, err)
+		assert.Equal(t, "releases", updated.Name)
+		_, err = tagService.UpdateTeamTag(ctx, memberID, teamID, tag.ID.String(), req)
+		assert.ErrorContains(t, err, "permission denied")
+
+		assert.ErrorContains(t, tagService.DeleteTeamTag(ctx, memberID, teamID, tag.ID.String()), "permission denied")
+		require.NoError(t, tagService.DeleteTeamTag(ctx, managerID, teamID, tag.ID.String()))
+		assert.ErrorContains(t, tagService.DeleteTeamTag(ctx, managerID, teamID, tag.ID.String()), "not found")
+
+		copied, err = tagService.GetTagByName(ctx, memberID, "release")
+		require.NoError(t, err, "personal copies outlive the team tag")
+		assert.Equal(t, "#AA3355", copied.Color)
+	})
+
+	t.Run("UnshareAndLeave", func(t *testing.T) {
+		require.NoError(t, teamService.UnshareProject(ctx, managerID, teamID, projectID.String()))
+		_, err := projectService.GetProject(ctx, memberID, projectID.String())
+		assert.ErrorContains(t, err, "not found")
+
+		require.NoError(t, teamService.RemoveTeamMember(ctx, viewerID, teamID, viewerID))
+		_, err = teamService.GetTeam(ctx, viewerID, teamID)
+		assert.ErrorContains(t, err, "not found")
+
+		require.NoError(t, teamService.DeleteTeam(ctx, ownerID, teamID))
+	})
+}
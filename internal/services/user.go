@@ -421,6 +421,10 @@ func (s *UserService) validateProfileRequest(req *models.UserProfileRequest) err
 		return fmt.Errorf("invalid timezone: %w", err)
 	}
 
+	if err := models.ValidatePreferences(req.Preferences); err != nil {
+		return fmt.Errorf("invalid preferences: %w", err)
+	}
+
 	return nil
 }
 
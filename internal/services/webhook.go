@@ -0,0 +1,1073 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garnizeh/englog/internal/database"
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/store"
+	"github.com/garnizeh/englog/internal/webhook"
+	"github.com/garnizeh/englog/internal/worker"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// maxWebhooks is the number of webhooks a user may have at once
+	maxWebhooks = 20
+	// webhookSecretBytes is the number of random bytes of a signing secret
+	webhookSecretBytes = 32
+	// webhookSecretPrefix marks signing secrets so they are recognized when leaked
+	webhookSecretPrefix = "whsec_"
+	// defaultWebhookPollInterval is how often due deliveries are sent
+	defaultWebhookPollInterval = 5 * time.Second
+	// defaultWebhookTimeout is how long a receiver may take to answer
+	defaultWebhookTimeout = 10 * time.Second
+	// defaultWebhookDeliveryRetention is how long finished deliveries are kept
+	defaultWebhookDeliveryRetention = 30 * 24 * time.Hour
+	// webhookCleanupInterval is how often finished deliveries past retention are removed
+	webhookCleanupInterval = time.Hour
+	// webhookDeliveryBatch bounds how many deliveries one poll sends
+	webhookDeliveryBatch = 50
+	// webhookDeliveryConcurrency bounds how many deliveries are sent at once
+	webhookDeliveryConcurrency = 4
+	// webhookDeliveryListLimit bounds how many deliveries are listed
+	webhookDeliveryListLimit = 100
+	// webhookErrorBytes bounds how much of the error of a failed attempt is kept
+	webhookErrorBytes = 1024
+	// webhookDrainBytes bounds how much of a receiver's answer is read, and discarded,
+	// so the connection can be reused
+	webhookDrainBytes = 4096
+	// webhookUserAgent identifies deliveries to receivers
+	webhookUserAgent = "EngLog-Webhooks/1.0"
+)
+
+// webhookRetry spaces the attempts of a delivery: 30s, 1.5m, 4.5m, ... up to six hours
+// apart, giving up after eight attempts, about nine hours after the event
+var webhookRetry = worker.RetryConfig{
+	MaxAttempts:   8,
+	InitialDelay:  30 * time.Second,
+	MaxDelay:      6 * time.Hour,
+	BackoffFactor: 3,
+	JitterEnabled: true,
+}
+
+// WebhookService manages the users' outbound webhooks, records an HMAC-signed
+// delivery for every event they subscribed to and sends the deliveries in the
+// background, retrying failed ones with exponential backoff.
+//
+// Events are published by other services inside their own write transaction, so a
+// delivery exists exactly when the change it reports was committed.
+type WebhookService struct {
+	db           *database.DB
+	logger       *logging.Logger
+	client       *http.Client
+	pollInterval time.Duration
+	retention    time.Duration
+	lastCleanup  time.Time
+
+	// allowPrivateNetworks lets webhooks point at loopback and private addresses,
+	// for local development only
+	allowPrivateNetworks bool
+}
+
+// NewWebhookService creates a new WebhookService instance. Webhooks may only
+// point at public addresses, which is checked when they are saved and again on
+// every connection, so a host cannot be re-pointed at an internal service later.
+func NewWebhookService(db *database.DB, logger *logging.Logger) *WebhookService {
+	s := &WebhookService{
+		db:           db,
+		logger:       logger.WithComponent("webhook_service"),
+		pollInterval: defaultWebhookPollInterval,
+		retention:    defaultWebhookDeliveryRetention,
+	}
+
+	dialer := &net.Dialer{
+		Timeout: defaultWebhookTimeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			if s.allowPrivateNetworks {
+				return nil
+			}
+			return webhook.DialControl(network, address, conn)
+		},
+	}
+	s.client = &http.Client{
+		Timeout: defaultWebhookTimeout,
+		Transport: &http.Transport{
+			// No proxy: it would be dialed instead of the receiver and escape the check
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		// A redirect is answered like any other status; following it could
+		// send signed events to a host the user never registered
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// WithWebhookSettings sets how often due deliveries are sent, how long receivers may
+// take to answer and how long finished deliveries are kept; non-positive values keep
+// the defaults
+func (s *WebhookService) WithWebhookSettings(pollInterval, timeout, retention time.Duration) *WebhookService {
+	if pollInterval > 0 {
+		s.pollInterval = pollInterval
+	}
+	if timeout > 0 {
+		s.client.Timeout = timeout
+	}
+	if retention > 0 {
+		s.retention = retention
+	}
+	return s
+}
+
+// WithPrivateNetworks lets webhooks point at loopback and private addresses, such
+// as a receiver started with englog webhook-listen. Never enable it where users
+// are not trusted: it exposes the services of the internal network to them.
+func (s *WebhookService) WithPrivateNetworks(allow bool) *WebhookService {
+	s.allowPrivateNetworks = allow
+	return s
+}
+
+// CreateWebhook registers a webhook with a new signing secret, which is returned this once
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID string, req *models.WebhookRequest) (*models.WebhookWithSecret, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in CreateWebhook", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	name, events, err := validateWebhookRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDestination(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to generate webhook secret", "user_id", userID)
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	s.logger.Info("Creating webhook", "user_id", userID, "name", name, "events", events)
+
+	var created *models.WebhookWithSecret
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		count, err := qtx.CountWebhooks(ctx, userUUID)
+		if err != nil {
+			return fmt.Errorf("failed to count webhooks: %w", err)
+		}
+		if count >= maxWebhooks {
+			return fmt.Errorf("at most %d webhooks are allowed; delete one first", maxWebhooks)
+		}
+
+		sqlcWebhook, err := qtx.CreateWebhook(ctx, store.CreateWebhookParams{
+			UserID: userUUID,
+			Name:   name,
+			Url:    strings.TrimSpace(req.URL),
+			Secret: secret,
+			Events: events,
+			Active: req.Active == nil || *req.Active,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook: %w", err)
+		}
+
+		created = &models.WebhookWithSecret{
+			Webhook: *webhookToModel(sqlcWebhook),
+			Secret:  secret,
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create webhook", "user_id", userID)
+		return nil, err
+	}
+
+	s.logger.Info("Webhook created successfully", "user_id", userID, "webhook_id", created.ID)
+	return created, nil
+}
+
+// GetWebhooks retrieves the webhooks of a user, oldest first, without their secrets
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format in GetWebhooks", "user_id", userID)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var webhooks []*models.Webhook
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcWebhooks, err := qtx.GetWebhooksByUser(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		webhooks = make([]*models.Webhook, len(sqlcWebhooks))
+		for i, sqlcWebhook := range sqlcWebhooks {
+			webhooks[i] = webhookToModel(sqlcWebhook)
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get webhooks", "user_id", userID)
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook retrieves one of the user's webhooks without its secret
+func (s *WebhookService) GetWebhook(ctx context.Context, userID, webhookID string) (*models.Webhook, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Webhook
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcWebhook, err := getOwnedWebhook(ctx, qtx, userUUID, webhookUUID)
+		if err != nil {
+			return err
+		}
+		result = webhookToModel(sqlcWebhook)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateWebhook replaces the name, URL, events and state of a webhook. Pending
+// deliveries of a webhook that gets disabled fail on their next attempt.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID, webhookID string, req *models.WebhookRequest) (*models.Webhook, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, events, err := validateWebhookRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDestination(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	var updated *models.Webhook
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcWebhook, err := qtx.UpdateWebhook(ctx, store.UpdateWebhookParams{
+			ID:     webhookUUID,
+			UserID: userUUID,
+			Name:   name,
+			Url:    strings.TrimSpace(req.URL),
+			Events: events,
+			Active: req.Active == nil || *req.Active,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("webhook not found")
+			}
+			return fmt.Errorf("failed to update webhook: %w", err)
+		}
+		updated = webhookToModel(sqlcWebhook)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update webhook", "user_id", userID, "webhook_id", webhookID)
+		return nil, err
+	}
+
+	s.logger.Info("Webhook updated successfully", "user_id", userID, "webhook_id", webhookID)
+	return updated, nil
+}
+
+// DeleteWebhook deletes a webhook together with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		rowsAffected, err := qtx.DeleteWebhook(ctx, store.DeleteWebhookParams{
+			ID:     webhookUUID,
+			UserID: userUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("webhook not found")
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete webhook", "user_id", userID, "webhook_id", webhookID)
+		return err
+	}
+
+	s.logger.Info("Webhook deleted successfully", "user_id", userID, "webhook_id", webhookID)
+	return nil
+}
+
+// RotateSecret replaces the signing secret of a webhook and returns the new one this
+// once. Deliveries attempted from now on, including retries, use the new secret.
+func (s *WebhookService) RotateSecret(ctx context.Context, userID, webhookID string) (*models.WebhookWithSecret, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to generate webhook secret", "user_id", userID)
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	var rotated *models.WebhookWithSecret
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcWebhook, err := qtx.UpdateWebhookSecret(ctx, store.UpdateWebhookSecretParams{
+			ID:     webhookUUID,
+			UserID: userUUID,
+			Secret: secret,
+		})
+		if err != nil {
+			if database.NoRows(err) {
+				return fmt.Errorf("webhook not found")
+			}
+			return fmt.Errorf("failed to rotate webhook secret: %w", err)
+		}
+
+		rotated = &models.WebhookWithSecret{
+			Webhook: *webhookToModel(sqlcWebhook),
+			Secret:  secret,
+		}
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to rotate webhook secret", "user_id", userID, "webhook_id", webhookID)
+		return nil, err
+	}
+
+	s.logger.Info("Webhook secret rotated successfully", "user_id", userID, "webhook_id", webhookID)
+	return rotated, nil
+}
+
+// PingWebhook queues a ping event for a webhook to test its receiver, whatever
+// events it subscribed to. The delivery is sent on the next poll.
+func (s *WebhookService) PingWebhook(ctx context.Context, userID, webhookID string) (*models.WebhookDelivery, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery *models.WebhookDelivery
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcWebhook, err := getOwnedWebhook(ctx, qtx, userUUID, webhookUUID)
+		if err != nil {
+			return err
+		}
+		if !sqlcWebhook.Active {
+			return fmt.Errorf("webhook is disabled")
+		}
+
+		payload, err := newWebhookPayload(models.WebhookPing, map[string]any{
+			"webhook_id": sqlcWebhook.ID,
+			"name":       sqlcWebhook.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		sqlcDelivery, err := qtx.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
+			WebhookID: sqlcWebhook.ID,
+			UserID:    userUUID,
+			EventID:   payload.eventID,
+			EventType: string(models.WebhookPing),
+			Payload:   payload.body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+
+		delivery = webhookDeliveryToModel(sqlcDelivery, false)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to ping webhook", "user_id", userID, "webhook_id", webhookID)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetDeliveries lists the latest deliveries of a webhook, newest first, optionally
+// only those with the given status
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID, webhookID string, status models.WebhookDeliveryStatus) ([]*models.WebhookDelivery, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusFilter := pgtype.Text{}
+	if status != "" {
+		if !status.IsValid() {
+			return nil, fmt.Errorf("invalid delivery status: %s", status)
+		}
+		statusFilter = stringToPgTextRequired(string(status))
+	}
+
+	var deliveries []*models.WebhookDelivery
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		if _, err := getOwnedWebhook(ctx, qtx, userUUID, webhookUUID); err != nil {
+			return err
+		}
+
+		sqlcDeliveries, err := qtx.GetWebhookDeliveries(ctx, store.GetWebhookDeliveriesParams{
+			WebhookID:     webhookUUID,
+			UserID:        userUUID,
+			Status:        statusFilter,
+			MaxDeliveries: webhookDeliveryListLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get webhook deliveries: %w", err)
+		}
+
+		deliveries = make([]*models.WebhookDelivery, len(sqlcDeliveries))
+		for i, sqlcDelivery := range sqlcDeliveries {
+			deliveries[i] = webhookDeliveryToModel(sqlcDelivery, false)
+		}
+		return nil
+	}); err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			s.logger.LogError(ctx, err, "Failed to get webhook deliveries", "user_id", userID, "webhook_id", webhookID)
+		}
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDelivery retrieves one delivery of a webhook including the payload it sent
+func (s *WebhookService) GetDelivery(ctx context.Context, userID, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryUUID, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery ID: %w", err)
+	}
+
+	var delivery *models.WebhookDelivery
+
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		sqlcDelivery, err := getWebhookDelivery(ctx, qtx, userUUID, webhookUUID, deliveryUUID)
+		if err != nil {
+			return err
+		}
+		delivery = webhookDeliveryToModel(sqlcDelivery, true)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ReplayDelivery sends the payload of a finished delivery again as a new delivery.
+// The replay keeps the event ID, so receivers can tell it is the same event.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, userID, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	userUUID, webhookUUID, err := s.parseWebhookIDs(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryUUID, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery ID: %w", err)
+	}
+
+	var replay *models.WebhookDelivery
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		sqlcWebhook, err := getOwnedWebhook(ctx, qtx, userUUID, webhookUUID)
+		if err != nil {
+			return err
+		}
+		if !sqlcWebhook.Active {
+			return fmt.Errorf("webhook is disabled")
+		}
+
+		original, err := getWebhookDelivery(ctx, qtx, userUUID, webhookUUID, deliveryUUID)
+		if err != nil {
+			return err
+		}
+		if original.Status == string(models.WebhookDeliveryPending) {
+			return fmt.Errorf("delivery is still pending")
+		}
+
+		sqlcDelivery, err := qtx.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
+			WebhookID: original.WebhookID,
+			UserID:    userUUID,
+			EventID:   original.EventID,
+			EventType: original.EventType,
+			Payload:   original.Payload,
+			ReplayOf:  uuidToPgUUID(&original.ID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+
+		replay = webhookDeliveryToModel(sqlcDelivery, false)
+		return nil
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to replay webhook delivery", "user_id", userID, "webhook_id", webhookID, "delivery_id", deliveryID)
+		return nil, err
+	}
+
+	s.logger.Info("Webhook delivery replayed", "user_id", userID, "webhook_id", webhookID, "delivery_id", deliveryID, "replay_id", replay.ID)
+	return replay, nil
+}
+
+// PublishTaskOutcome records the events of a task a worker finished: generated
+// insights, completed weekly reports and failures of either. Other tasks are
+// either answered synchronously or, like report exports, publish their own events.
+func (s *WebhookService) PublishTaskOutcome(ctx context.Context, outcome models.WorkerTaskOutcome) {
+	if s == nil {
+		return
+	}
+
+	event, ok := webhookEventForTask(outcome)
+	if !ok {
+		return
+	}
+
+	userUUID, err := uuid.Parse(outcome.UserID)
+	if err != nil {
+		s.logger.Warn("Task outcome without a valid user", "task_id", outcome.TaskID, "user_id", outcome.UserID)
+		return
+	}
+
+	data := webhookTaskData{
+		TaskID:   outcome.TaskID,
+		TaskType: outcome.TaskType,
+		Error:    outcome.Error,
+	}
+	if !outcome.Failed && json.Valid([]byte(outcome.Result)) {
+		data.Result = json.RawMessage(outcome.Result)
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		return s.publishTx(ctx, qtx, userUUID, event, data)
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to publish task outcome", "task_id", outcome.TaskID, "event", event)
+	}
+}
+
+// publishTx records a delivery of an event for every active webhook of the user
+// subscribed to it, inside the caller's transaction. It does nothing on a nil service,
+// so services publish events without checking whether webhooks are configured.
+func (s *WebhookService) publishTx(ctx context.Context, qtx *store.Queries, userID uuid.UUID, event models.WebhookEvent, data any) error {
+	if s == nil {
+		return nil
+	}
+
+	webhooks, err := qtx.GetWebhooksForEvent(ctx, store.GetWebhooksForEventParams{
+		UserID:    userID,
+		EventType: string(event),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks for event: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := newWebhookPayload(event, data)
+	if err != nil {
+		return err
+	}
+
+	for _, sqlcWebhook := range webhooks {
+		if _, err := qtx.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
+			WebhookID: sqlcWebhook.ID,
+			UserID:    userID,
+			EventID:   payload.eventID,
+			EventType: string(event),
+			Payload:   payload.body,
+		}); err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartDeliveryProcessor sends due deliveries and removes old ones until ctx is cancelled
+func (s *WebhookService) StartDeliveryProcessor(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Webhook delivery processor started", "interval", s.pollInterval.String(), "timeout", s.client.Timeout.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Webhook delivery processor stopped")
+			return
+		case <-ticker.C:
+			s.ProcessDeliveries(ctx)
+		}
+	}
+}
+
+// ProcessDeliveries runs one round of the delivery processor. Every due delivery is
+// claimed for the length of an attempt first, so several API instances can share
+// the work; a delivery whose attempt never finishes becomes due again afterwards.
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) {
+	var due []store.WebhookDelivery
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		due, err = qtx.GetDueWebhookDeliveries(ctx, webhookDeliveryBatch)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to get due webhook deliveries")
+		return
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, webhookDeliveryConcurrency)
+	for _, delivery := range due {
+		claimed, ok := s.claimDelivery(ctx, delivery)
+		if !ok {
+			continue
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.attemptDelivery(ctx, claimed)
+		}()
+	}
+	wg.Wait()
+
+	if time.Since(s.lastCleanup) >= webhookCleanupInterval {
+		s.lastCleanup = time.Now()
+		s.removeOldDeliveries(ctx)
+	}
+}
+
+// claimDelivery leases a due delivery to this processor; it reports false when the
+// delivery was claimed elsewhere or is gone
+func (s *WebhookService) claimDelivery(ctx context.Context, delivery store.WebhookDelivery) (store.WebhookDelivery, bool) {
+	var claimed store.WebhookDelivery
+	err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		var err error
+		claimed, err = qtx.ClaimWebhookDelivery(ctx, store.ClaimWebhookDeliveryParams{
+			LeaseUntil: timeToPgTimestamptz(time.Now().Add(s.client.Timeout + time.Minute)),
+			ID:         delivery.ID,
+		})
+		return err
+	})
+	if err != nil {
+		if !database.NoRows(err) {
+			s.logger.LogError(ctx, err, "Failed to claim webhook delivery", "delivery_id", delivery.ID)
+		}
+		return store.WebhookDelivery{}, false
+	}
+	return claimed, true
+}
+
+// attemptDelivery sends a claimed delivery and records the outcome: succeeded on a
+// 2xx answer, pending again after a retriable failure while attempts remain, and
+// failed otherwise
+func (s *WebhookService) attemptDelivery(ctx context.Context, delivery store.WebhookDelivery) {
+	var sqlcWebhook store.Webhook
+	if err := s.db.Read(ctx, func(qtx *store.Queries) error {
+		var err error
+		sqlcWebhook, err = qtx.GetWebhookByID(ctx, store.GetWebhookByIDParams{
+			ID:     delivery.WebhookID,
+			UserID: delivery.UserID,
+		})
+		return err
+	}); err != nil {
+		// A deleted webhook takes its deliveries with it
+		if !database.NoRows(err) {
+			s.logger.LogError(ctx, err, "Failed to get webhook of delivery", "delivery_id", delivery.ID)
+		}
+		return
+	}
+
+	var attempt webhookAttempt
+	if sqlcWebhook.Active {
+		attempt = s.send(ctx, sqlcWebhook, delivery)
+	} else {
+		attempt.err = fmt.Errorf("webhook is disabled")
+	}
+
+	params := store.RecordWebhookDeliveryAttemptParams{
+		Status:     string(models.WebhookDeliveryFailed),
+		DurationMs: pgtype.Int4{Int32: int32(attempt.duration.Milliseconds()), Valid: attempt.duration > 0},
+		ID:         delivery.ID,
+	}
+	if attempt.status != 0 {
+		params.ResponseStatus = pgtype.Int4{Int32: int32(attempt.status), Valid: true}
+	}
+	if attempt.err != nil {
+		params.ErrorMessage = stringToPgTextRequired(shortenText(attempt.err.Error(), webhookErrorBytes))
+	}
+
+	attempts := int(delivery.Attempts) + 1
+	switch {
+	case attempt.succeeded():
+		params.Status = string(models.WebhookDeliverySucceeded)
+	case sqlcWebhook.Active && attempt.retriable() && attempts < webhookRetry.MaxAttempts:
+		params.Status = string(models.WebhookDeliveryPending)
+		params.NextAttemptAt = timeToPgTimestamptz(time.Now().Add(webhookRetry.CalculateDelay(attempts)))
+	}
+
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		_, err := qtx.RecordWebhookDeliveryAttempt(ctx, params)
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to record webhook delivery attempt", "delivery_id", delivery.ID)
+		return
+	}
+
+	if params.Status == string(models.WebhookDeliveryFailed) {
+		s.logger.Warn("Webhook delivery failed", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID,
+			"attempts", attempts, "response_status", attempt.status, "error", params.ErrorMessage.String)
+	}
+}
+
+// send posts the payload of a delivery to its webhook, signed with the current secret
+func (s *WebhookService) send(ctx context.Context, sqlcWebhook store.Webhook, delivery store.WebhookDelivery) webhookAttempt {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sqlcWebhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return webhookAttempt{err: fmt.Errorf("invalid webhook request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, delivery.ID.String())
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(sqlcWebhook.Secret, time.Now(), delivery.Payload))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return webhookAttempt{err: err, duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+
+	// Only the status of the answer is kept, so a webhook cannot be used to read
+	// the responses of another service
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookDrainBytes))
+	attempt := webhookAttempt{
+		status:   resp.StatusCode,
+		duration: time.Since(start),
+	}
+	if !attempt.succeeded() {
+		attempt.err = fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return attempt
+}
+
+// removeOldDeliveries deletes finished deliveries past the retention period
+func (s *WebhookService) removeOldDeliveries(ctx context.Context) {
+	var removed int64
+	if err := s.db.Write(ctx, func(qtx *store.Queries) error {
+		var err error
+		removed, err = qtx.DeleteOldWebhookDeliveries(ctx, timeToPgTimestamptz(time.Now().Add(-s.retention)))
+		return err
+	}); err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove old webhook deliveries")
+		return
+	}
+
+	if removed > 0 {
+		s.logger.Info("Old webhook deliveries removed", "deliveries_removed", removed)
+	}
+}
+
+// parseWebhookIDs parses the user and webhook IDs of a request
+func (s *WebhookService) parseWebhookIDs(ctx context.Context, userID, webhookID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid user ID format", "user_id", userID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	webhookUUID, err := uuid.Parse(webhookID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Invalid webhook ID format", "user_id", userID, "webhook_id", webhookID)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid webhook ID: %w", err)
+	}
+
+	return userUUID, webhookUUID, nil
+}
+
+// webhookAttempt is the outcome of one attempt to send a delivery
+type webhookAttempt struct {
+	status   int // HTTP status of the answer; 0 when there was none
+	err      error
+	duration time.Duration
+}
+
+// succeeded reports whether the receiver accepted the delivery
+func (a webhookAttempt) succeeded() bool {
+	return a.status >= 200 && a.status < 300
+}
+
+// retriable reports whether the delivery may succeed when attempted again: after
+// network errors, timeouts, rate limiting and server errors
+func (a webhookAttempt) retriable() bool {
+	switch {
+	case a.status == 0:
+		return true
+	case a.status == http.StatusRequestTimeout, a.status == http.StatusTooManyRequests:
+		return true
+	default:
+		return a.status >= 500
+	}
+}
+
+// webhookTaskData is the data of the events of worker tasks
+type webhookTaskData struct {
+	TaskID   string          `json:"task_id"`
+	TaskType string          `json:"task_type"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// encodedWebhookPayload is the body of a delivery and the ID of its event
+type encodedWebhookPayload struct {
+	eventID uuid.UUID
+	body    []byte
+}
+
+// newWebhookPayload encodes a new event
+func newWebhookPayload(event models.WebhookEvent, data any) (encodedWebhookPayload, error) {
+	payload := models.WebhookPayload{
+		ID:        uuid.New(),
+		Type:      event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return encodedWebhookPayload{}, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	return encodedWebhookPayload{eventID: payload.ID, body: body}, nil
+}
+
+// webhookEventForTask maps the outcome of a worker task to the event it publishes
+func webhookEventForTask(outcome models.WorkerTaskOutcome) (models.WebhookEvent, bool) {
+	switch outcome.TaskType {
+	case "insight_generation":
+		if outcome.Failed {
+			return models.WebhookTaskFailed, true
+		}
+		return models.WebhookInsightGenerated, true
+	case "weekly_report":
+		if outcome.Failed {
+			return models.WebhookTaskFailed, true
+		}
+		return models.WebhookReportCompleted, true
+	}
+	return "", false
+}
+
+// logEntryWebhookEvent maps the action of a log entry revision to the event it
+// publishes; restoring an entry, from its history or the trash, updates it
+func logEntryWebhookEvent(action models.RevisionAction) models.WebhookEvent {
+	switch action {
+	case models.RevisionCreated:
+		return models.WebhookLogCreated
+	case models.RevisionDeleted:
+		return models.WebhookLogDeleted
+	default:
+		return models.WebhookLogUpdated
+	}
+}
+
+// validateWebhookRequest checks a webhook request and returns its trimmed name and
+// its events without duplicates
+func validateWebhookRequest(req *models.WebhookRequest) (string, []string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", nil, fmt.Errorf("name must be at most 100 characters")
+	}
+
+	u, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", nil, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return "", nil, fmt.Errorf("url must not contain credentials")
+	}
+
+	if len(req.Events) == 0 {
+		return "", nil, fmt.Errorf("at least one event is required")
+	}
+	events := make([]string, 0, len(req.Events))
+	seen := make(map[models.WebhookEvent]bool)
+	for _, event := range req.Events {
+		if !event.IsValid() {
+			return "", nil, fmt.Errorf("invalid webhook event: %s", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, string(event))
+		}
+	}
+
+	return name, events, nil
+}
+
+// checkDestination refuses webhook URLs whose host resolves to a loopback,
+// private, link-local or otherwise internal address, unless private networks are
+// allowed
+func (s *WebhookService) checkDestination(ctx context.Context, rawURL string) error {
+	if s.allowPrivateNetworks {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if err := webhook.CheckHost(ctx, net.DefaultResolver, u.Hostname()); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenDestination) {
+			return err
+		}
+		return fmt.Errorf("url host could not be resolved: %s", u.Hostname())
+	}
+	return nil
+}
+
+// getOwnedWebhook loads a webhook of the user
+func getOwnedWebhook(ctx context.Context, qtx *store.Queries, userUUID, webhookUUID uuid.UUID) (store.Webhook, error) {
+	sqlcWebhook, err := qtx.GetWebhookByID(ctx, store.GetWebhookByIDParams{
+		ID:     webhookUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return store.Webhook{}, fmt.Errorf("webhook not found")
+		}
+		return store.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return sqlcWebhook, nil
+}
+
+// getWebhookDelivery loads a delivery of one of the user's webhooks
+func getWebhookDelivery(ctx context.Context, qtx *store.Queries, userUUID, webhookUUID, deliveryUUID uuid.UUID) (store.WebhookDelivery, error) {
+	sqlcDelivery, err := qtx.GetWebhookDeliveryByID(ctx, store.GetWebhookDeliveryByIDParams{
+		ID:     deliveryUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		if database.NoRows(err) {
+			return store.WebhookDelivery{}, fmt.Errorf("webhook delivery not found")
+		}
+		return store.WebhookDelivery{}, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if sqlcDelivery.WebhookID != webhookUUID {
+		return store.WebhookDelivery{}, fmt.Errorf("webhook delivery not found")
+	}
+	return sqlcDelivery, nil
+}
+
+// generateWebhookSecret returns a random signing secret
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(bytes), nil
+}
+
+// webhookToModel converts a stored webhook to the API model
+func webhookToModel(sqlcWebhook store.Webhook) *models.Webhook {
+	events := make([]models.WebhookEvent, len(sqlcWebhook.Events))
+	for i, event := range sqlcWebhook.Events {
+		events[i] = models.WebhookEvent(event)
+	}
+	return &models.Webhook{
+		ID:        sqlcWebhook.ID,
+		Name:      sqlcWebhook.Name,
+		URL:       sqlcWebhook.Url,
+		Events:    events,
+		Active:    sqlcWebhook.Active,
+		CreatedAt: pgTimestamptzToTime(sqlcWebhook.CreatedAt),
+		UpdatedAt: pgTimestamptzToTime(sqlcWebhook.UpdatedAt),
+	}
+}
+
+// webhookDeliveryToModel converts a stored delivery to the API model, with its
+// payload when asked for
+func webhookDeliveryToModel(sqlcDelivery store.WebhookDelivery, withPayload bool) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		ID:            sqlcDelivery.ID,
+		WebhookID:     sqlcDelivery.WebhookID,
+		EventID:       sqlcDelivery.EventID,
+		EventType:     models.WebhookEvent(sqlcDelivery.EventType),
+		Status:        models.WebhookDeliveryStatus(sqlcDelivery.Status),
+		Attempts:      int(sqlcDelivery.Attempts),
+		LastAttemptAt: pgTimestamptzToTimePtr(sqlcDelivery.LastAttemptAt),
+		Error:         pgTextToString(sqlcDelivery.ErrorMessage),
+		ReplayOf:      pgUUIDToUUID(sqlcDelivery.ReplayOf),
+		CreatedAt:     pgTimestamptzToTime(sqlcDelivery.CreatedAt),
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		delivery.NextAttemptAt = pgTimestamptzToTimePtr(sqlcDelivery.NextAttemptAt)
+	}
+	if sqlcDelivery.ResponseStatus.Valid {
+		status := int(sqlcDelivery.ResponseStatus.Int32)
+		delivery.ResponseStatus = &status
+	}
+	if sqlcDelivery.DurationMs.Valid {
+		duration := int(sqlcDelivery.DurationMs.Int32)
+		delivery.DurationMs = &duration
+	}
+	if withPayload {
+		delivery.Payload = json.RawMessage(sqlcDelivery.Payload)
+	}
+	return delivery
+}
@@ -0,0 +1,236 @@
+//go:build integration
+// +build integration
+
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/services"
+	"github.com/garnizeh/englog/internal/testutils"
+	"github.com/garnizeh/englog/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records the verified deliveries posted to it
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	payloads []models.WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := webhook.Verify(r.secret, req.Header.Get(webhook.SignatureHeader), body, 0, time.Now()); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var payload models.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err == nil {
+		r.payloads = append(r.payloads, payload)
+	}
+	w.WriteHeader(r.status)
+}
+
+func (r *webhookReceiver) received() []models.WebhookPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.WebhookPayload(nil), r.payloads...)
+}
+
+// TestWebhookService tests event publishing, signed deliveries, retries and replays
+func TestWebhookService(t *testing.T) {
+	db := testutils.DB(t)
+	testLogger := logging.NewTestLogger()
+
+	// The receiver runs on loopback
+	webhookService := services.NewWebhookService(db, testLogger).WithPrivateNetworks(true)
+	logEntryService := services.NewLogEntryService(db, testLogger).WithWebhooks(webhookService)
+	projectService := services.NewProjectService(db, testLogger).WithWebhooks(webhookService)
+	userService := services.NewUserService(db, testLogger)
+
+	ctx := context.Background()
+
+	user, err := userService.CreateUser(ctx, &models.UserRegistration{
+		Email:     "webhooks@example.com",
+		Password:  "password123",
+		FirstName: "Web",
+		LastName:  "Hooks",
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	t.Run("InternalDestinationsRefused", func(t *testing.T) {
+		strict := services.NewWebhookService(db, testLogger)
+		for _, target := range []string{server.URL, "http://169.254.169.254/latest/meta-data/", "http://10.0.0.5:8080/hook", "http://localhost:6379/"} {
+			_, err := strict.CreateWebhook(ctx, userID, &models.WebhookRequest{
+				Name:   "Internal",
+				URL:    target,
+				Events: []models.WebhookEvent{models.WebhookLogCreated},
+			})
+			assert.ErrorIs(t, err, webhook.ErrForbiddenDestination, target)
+		}
+
+		webhooks, err := strict.GetWebhooks(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, webhooks)
+	})
+
+	created, err := webhookService.CreateWebhook(ctx, userID, &models.WebhookRequest{
+		Name:   "Warehouse sync",
+		URL:    server.URL,
+		Events: []models.WebhookEvent{models.WebhookLogCreated, models.WebhookLogDeleted, models.WebhookProjectCreated},
+	})
+	require.NoError(t, err)
+	assert.True(t, created.Active)
+	assert.Contains(t, created.Secret, "whsec_")
+	receiver.secret = created.Secret
+	webhookID := created.ID.String()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	newEntry := func(title string) *models.LogEntry {
+		entry, err := logEntryService.CreateLogEntry(ctx, userID, &models.LogEntryRequest{
+			Title:       title,
+			Type:        models.ActivityDevelopment,
+			StartTime:   start,
+			EndTime:     start.Add(30 * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+		})
+		require.NoError(t, err)
+		return entry
+	}
+
+	t.Run("Deliver", func(t *testing.T) {
+		entry := newEntry("Sync me")
+		_, err := projectService.CreateProject(ctx, userID, &models.ProjectRequest{
+			Name:   "Warehouse",
+			Color:  "#00ff00",
+			Status: models.ProjectActive,
+		})
+		require.NoError(t, err)
+		// Not subscribed
+		_, err = logEntryService.UpdateLogEntry(ctx, userID, entry.ID.String(), &models.LogEntryRequest{
+			Title:       "Sync me later",
+			Type:        models.ActivityDevelopment,
+			StartTime:   start,
+			EndTime:     start.Add(30 * time.Minute),
+			ValueRating: models.ValueMedium,
+			ImpactLevel: models.ImpactTeam,
+		})
+		require.NoError(t, err)
+
+		webhookService.ProcessDeliveries(ctx)
+
+		payloads := receiver.received()
+		require.Len(t, payloads, 2)
+		types := []models.WebhookEvent{payloads[0].Type, payloads[1].Type}
+		assert.ElementsMatch(t, []models.WebhookEvent{models.WebhookLogCreated, models.WebhookProjectCreated}, types)
+
+		deliveries, err := webhookService.GetDeliveries(ctx, userID, webhookID, models.WebhookDeliverySucceeded)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		require.NotNil(t, deliveries[0].ResponseStatus)
+		assert.Equal(t, http.StatusOK, *deliveries[0].ResponseStatus)
+	})
+
+	t.Run("RetryAndReplay", func(t *testing.T) {
+		receiver.mu.Lock()
+		receiver.status = http.StatusServiceUnavailable
+		receiver.mu.Unlock()
+
+		entry := newEntry("Flaky")
+		require.NoError(t, logEntryService.DeleteLogEntry(ctx, userID, entry.ID.String()))
+		webhookService.ProcessDeliveries(ctx)
+
+		pending, err := webhookService.GetDeliveries(ctx, userID, webhookID, models.WebhookDeliveryPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 2, "failed attempts are retried later")
+		assert.Equal(t, 1, pending[0].Attempts)
+		require.NotNil(t, pending[0].NextAttemptAt)
+		assert.True(t, pending[0].NextAttemptAt.After(time.Now()))
+
+		_, err = webhookService.ReplayDelivery(ctx, userID, webhookID, pending[0].ID.String())
+		assert.ErrorContains(t, err, "still pending")
+
+		// A delivery that succeeded can be sent again under the same event ID
+		succeeded, err := webhookService.GetDeliveries(ctx, userID, webhookID, models.WebhookDeliverySucceeded)
+		require.NoError(t, err)
+		require.NotEmpty(t, succeeded)
+		original, err := webhookService.GetDelivery(ctx, userID, webhookID, succeeded[0].ID.String())
+		require.NoError(t, err)
+		assert.NotEmpty(t, original.Payload)
+
+		receiver.mu.Lock()
+		receiver.status = http.StatusOK
+		receiver.mu.Unlock()
+
+		replay, err := webhookService.ReplayDelivery(ctx, userID, webhookID, original.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, original.EventID, replay.EventID)
+		require.NotNil(t, replay.ReplayOf)
+		assert.Equal(t, original.ID, *replay.ReplayOf)
+
+		before := len(receiver.received())
+		webhookService.ProcessDeliveries(ctx)
+		payloads := receiver.received()
+		require.Len(t, payloads, before+1, "only the replay is due")
+		assert.Equal(t, original.EventID, payloads[len(payloads)-1].ID)
+	})
+
+	t.Run("DisabledAndPing", func(t *testing.T) {
+		active := false
+		_, err := webhookService.UpdateWebhook(ctx, userID, webhookID, &models.WebhookRequest{
+			Name:   "Warehouse sync",
+			URL:    server.URL,
+			Events: []models.WebhookEvent{models.WebhookLogCreated},
+			Active: &active,
+		})
+		require.NoError(t, err)
+
+		before := len(receiver.received())
+		newEntry("Not sent")
+		webhookService.ProcessDeliveries(ctx)
+		assert.Len(t, receiver.received(), before, "disabled webhooks get no events")
+
+		_, err = webhookService.PingWebhook(ctx, userID, webhookID)
+		assert.ErrorContains(t, err, "disabled")
+	})
+
+	t.Run("RotateSecret", func(t *testing.T) {
+		rotated, err := webhookService.RotateSecret(ctx, userID, webhookID)
+		require.NoError(t, err)
+		assert.NotEqual(t, created.Secret, rotated.Secret)
+
+		webhooks, err := webhookService.GetWebhooks(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, webhookService.DeleteWebhook(ctx, userID, webhookID))
+		_, err := webhookService.GetDeliveries(ctx, userID, webhookID, "")
+		assert.ErrorContains(t, err, "not found")
+		assert.ErrorContains(t, webhookService.DeleteWebhook(ctx, userID, webhookID), "not found")
+	})
+}
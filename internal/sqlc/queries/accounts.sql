@@ -0,0 +1,50 @@
+-- EngLog Account Archive Queries
+-- Full account exports and the lookups that keep account imports from duplicating data
+
+-- name: ExportAccountLogEntries :many
+SELECT
+    le.id, le.project_id, le.title, le.description, le.type,
+    le.start_time, le.end_time, le.value_rating, le.impact_level,
+    ARRAY(
+        SELECT t.name FROM log_entry_tags let
+        JOIN tags t ON t.id = let.tag_id
+        WHERE let.log_entry_id = le.id
+        ORDER BY t.name
+    )::text[] AS tags,
+    COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('kind', l.kind, 'url', l.url, 'title', l.title) ORDER BY l.position, l.created_at)
+        FROM log_entry_links l
+        WHERE l.log_entry_id = le.id
+    ), '[]')::jsonb AS links,
+    le.created_at, le.updated_at
+FROM log_entries le
+WHERE le.user_id = $1
+ORDER BY le.start_time, le.id;
+
+-- name: GetAccountInsights :many
+SELECT * FROM generated_insights
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: GetAccountTagAliases :many
+SELECT * FROM tag_aliases
+WHERE user_id = $1
+ORDER BY tag_id, alias;
+
+-- name: GetAccountTags :many
+-- The user's own tags plus the shared tags on their log entries
+SELECT * FROM tags t
+WHERE t.user_id = $1
+   OR (t.user_id IS NULL AND EXISTS (
+        SELECT 1 FROM log_entry_tags let
+        JOIN log_entries le ON le.id = let.log_entry_id
+        WHERE let.tag_id = t.id AND le.user_id = $1
+   ))
+ORDER BY t.name, t.id;
+
+-- name: LogEntryExists :one
+-- Whether the user already logged an entry with this title and time span
+SELECT EXISTS(
+    SELECT 1 FROM log_entries
+    WHERE user_id = $1 AND title = $2 AND start_time = $3 AND end_time = $4
+);
@@ -6,22 +6,65 @@ SELECT * FROM user_activity_summary
 WHERE user_id = $1;
 
 -- name: RefreshUserActivitySummary :exec
-REFRESH MATERIALIZED VIEW user_activity_summary;
+-- CONCURRENTLY keeps the view readable during the refresh; it relies on idx_user_activity_summary_user
+REFRESH MATERIALIZED VIEW CONCURRENTLY user_activity_summary;
 
 -- name: GetDailyActivityPattern :many
-SELECT * FROM daily_activity_patterns
-WHERE user_id = $1
-  AND activity_date >= $2
-  AND activity_date <= $3
+-- The daily_activity_patterns view buckets in server time, so log entries are aggregated directly
+SELECT
+    (start_time AT TIME ZONE sqlc.arg(tz)::text)::date AS activity_date,
+    EXTRACT(DOW FROM start_time AT TIME ZONE sqlc.arg(tz)::text)::int AS day_of_week,
+    EXTRACT(HOUR FROM start_time AT TIME ZONE sqlc.arg(tz)::text)::int AS hour_of_day,
+    COUNT(*) AS entry_count,
+    SUM(duration_minutes) AS total_minutes
+FROM log_entries
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY activity_date, day_of_week, hour_of_day
 ORDER BY activity_date DESC, hour_of_day ASC;
 
 -- name: GetProjectPerformanceMetrics :many
-SELECT * FROM project_performance_metrics
+SELECT
+    project_id,
+    project_name,
+    total_entries,
+    COALESCE(total_minutes, 0)::bigint AS total_minutes,
+    contributors_count,
+    active_days,
+    first_activity::timestamptz AS first_activity,
+    last_activity::timestamptz AS last_activity,
+    recent_entries_30d
+FROM project_performance_metrics
 WHERE project_owner = $1
 ORDER BY total_minutes DESC;
 
 -- name: GetUserProductivityTrend :many
-SELECT * FROM get_user_productivity_trend($1, $2);
+-- Same scores as get_user_productivity_trend, grouped by day in the given timezone
+SELECT
+    (start_time AT TIME ZONE sqlc.arg(tz)::text)::date AS activity_date,
+    SUM(duration_minutes)::int AS total_minutes,
+    COUNT(*)::int AS entry_count,
+    ROUND(AVG(CASE
+        WHEN value_rating = 'critical' THEN 4
+        WHEN value_rating = 'high' THEN 3
+        WHEN value_rating = 'medium' THEN 2
+        WHEN value_rating = 'low' THEN 1
+        ELSE 0
+    END), 2)::float8 AS avg_value_score,
+    ROUND((SUM(duration_minutes) * AVG(CASE
+        WHEN value_rating = 'critical' THEN 4
+        WHEN value_rating = 'high' THEN 3
+        WHEN value_rating = 'medium' THEN 2
+        WHEN value_rating = 'low' THEN 1
+        ELSE 0
+    END)) / 100.0, 2)::float8 AS productivity_score
+FROM log_entries
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY activity_date
+ORDER BY activity_date ASC;
 
 -- name: GetActivityTypeDistribution :many
 SELECT
@@ -83,32 +126,33 @@ END DESC;
 
 -- name: GetWeeklyActivitySummary :many
 SELECT
-    DATE_TRUNC('week', start_time) as week_start,
+    (DATE_TRUNC('week', (start_time AT TIME ZONE sqlc.arg(tz)::text) + make_interval(days => sqlc.arg(week_offset)::int))
+        - make_interval(days => sqlc.arg(week_offset)::int))::date as week_start,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration,
     COUNT(DISTINCT project_id) as projects_count,
-    COUNT(DISTINCT DATE(start_time)) as active_days
+    COUNT(DISTINCT (start_time AT TIME ZONE sqlc.arg(tz)::text)::date) as active_days
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY DATE_TRUNC('week', start_time)
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY week_start
 ORDER BY week_start DESC;
 
 -- name: GetMonthlyActivitySummary :many
 SELECT
-    DATE_TRUNC('month', start_time) as month_start,
+    DATE_TRUNC('month', start_time AT TIME ZONE sqlc.arg(tz)::text)::date as month_start,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration,
     COUNT(DISTINCT project_id) as projects_count,
-    COUNT(DISTINCT DATE(start_time)) as active_days
+    COUNT(DISTINCT (start_time AT TIME ZONE sqlc.arg(tz)::text)::date) as active_days
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY DATE_TRUNC('month', start_time)
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY month_start
 ORDER BY month_start DESC;
 
 -- name: GetTopProjectsByTime :many
@@ -128,39 +172,87 @@ LIMIT $4;
 
 -- name: GetProductivityByDayOfWeek :many
 SELECT
-    EXTRACT(DOW FROM start_time) as day_of_week,
-    TO_CHAR(start_time, 'Day') as day_name,
+    EXTRACT(DOW FROM start_time AT TIME ZONE sqlc.arg(tz)::text)::int as day_of_week,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY EXTRACT(DOW FROM start_time), TO_CHAR(start_time, 'Day')
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY day_of_week
 ORDER BY day_of_week;
 
 -- name: GetProductivityByHour :many
 SELECT
-    EXTRACT(HOUR FROM start_time) as hour_of_day,
+    EXTRACT(HOUR FROM start_time AT TIME ZONE sqlc.arg(tz)::text)::int as hour_of_day,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY EXTRACT(HOUR FROM start_time)
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+GROUP BY hour_of_day
 ORDER BY hour_of_day;
 
 -- name: GetComparisonStats :one
 SELECT
     -- Current period
     COUNT(CASE WHEN start_time >= $2 AND start_time <= $3 THEN 1 END) as current_entries,
-    SUM(CASE WHEN start_time >= $2 AND start_time <= $3 THEN duration_minutes ELSE 0 END) as current_minutes,
+    COALESCE(SUM(CASE WHEN start_time >= $2 AND start_time <= $3 THEN duration_minutes ELSE 0 END), 0)::bigint as current_minutes,
 
     -- Previous period
     COUNT(CASE WHEN start_time >= $4 AND start_time <= $5 THEN 1 END) as previous_entries,
-    SUM(CASE WHEN start_time >= $4 AND start_time <= $5 THEN duration_minutes ELSE 0 END) as previous_minutes
+    COALESCE(SUM(CASE WHEN start_time >= $4 AND start_time <= $5 THEN duration_minutes ELSE 0 END), 0)::bigint as previous_minutes
 FROM log_entries
 WHERE user_id = $1;
+
+-- name: GetFocusEntries :many
+-- Entries in start order; focus metrics are derived from the switches and gaps between them
+SELECT start_time, end_time, type, project_id
+FROM log_entries
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time <= sqlc.arg(end_time)
+ORDER BY start_time ASC, end_time ASC;
+
+-- name: GetProjectDailyMinutes :many
+-- Minutes logged on a set of projects (a project and its sub-projects) per day
+-- and activity type, for budget burn-down
+SELECT
+    (start_time AT TIME ZONE sqlc.arg(tz)::text)::date AS activity_date,
+    type,
+    SUM(duration_minutes)::int AS total_minutes
+FROM log_entries
+WHERE project_id = ANY(sqlc.arg(project_ids)::uuid[])
+GROUP BY 1, 2
+ORDER BY 1, 2;
+
+-- name: GetProjectTotals :many
+-- All-time entries and minutes per project, for sub-project rollups
+SELECT
+    project_id,
+    COUNT(*)::int AS entry_count,
+    SUM(duration_minutes)::int AS total_minutes
+FROM log_entries
+WHERE project_id = ANY(sqlc.arg(project_ids)::uuid[])
+GROUP BY project_id;
+
+-- name: GetTeamActivity :many
+-- Entries and minutes per member, project and activity type on the projects shared
+-- with a team. Only current members count; raw entries never leave this query.
+SELECT
+    le.user_id,
+    le.project_id,
+    le.type,
+    COUNT(*)::int AS entry_count,
+    SUM(le.duration_minutes)::int AS total_minutes
+FROM log_entries le
+JOIN team_projects tp ON tp.project_id = le.project_id
+JOIN team_members tm ON tm.team_id = tp.team_id AND tm.user_id = le.user_id
+WHERE tp.team_id = sqlc.arg(team_id)
+    AND le.start_time >= sqlc.arg(start_time)
+    AND le.start_time <= sqlc.arg(end_time)
+GROUP BY le.user_id, le.project_id, le.type
+ORDER BY le.user_id, le.project_id, le.type;
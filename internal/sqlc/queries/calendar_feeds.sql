@@ -0,0 +1,45 @@
+-- EngLog Calendar Feed Queries
+-- Secret feed tokens and the log entries served as iCalendar events
+
+-- name: CreateCalendarFeedToken :one
+INSERT INTO calendar_feed_tokens (user_id, name, token_hash)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: CountCalendarFeedTokens :one
+SELECT COUNT(*) FROM calendar_feed_tokens
+WHERE user_id = $1;
+
+-- name: GetCalendarFeedTokensByUser :many
+SELECT * FROM calendar_feed_tokens
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: GetCalendarFeedTokenByHash :one
+SELECT * FROM calendar_feed_tokens
+WHERE token_hash = $1;
+
+-- name: TouchCalendarFeedToken :exec
+UPDATE calendar_feed_tokens
+SET last_used_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteCalendarFeedToken :execrows
+DELETE FROM calendar_feed_tokens
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetCalendarFeedEntries :many
+-- Entries of a window with their project, newest first, optionally limited to projects and types
+SELECT
+    le.id, le.title, le.description, le.type, le.start_time, le.end_time,
+    le.value_rating, le.impact_level, le.updated_at, le.project_id,
+    p.name AS project_name, p.color AS project_color
+FROM log_entries le
+LEFT JOIN projects p ON p.id = le.project_id
+WHERE le.user_id = sqlc.arg(user_id)
+  AND le.start_time >= sqlc.arg(start_time)
+  AND le.start_time < sqlc.arg(end_time)
+  AND (cardinality(sqlc.arg(project_ids)::uuid[]) = 0 OR le.project_id = ANY(sqlc.arg(project_ids)::uuid[]))
+  AND (cardinality(sqlc.arg(types)::text[]) = 0 OR le.type = ANY(sqlc.arg(types)::text[]))
+ORDER BY le.start_time DESC, le.id
+LIMIT sqlc.arg(max_entries);
@@ -0,0 +1,26 @@
+-- EngLog CSV Import Profile Queries
+-- Column mappings users saved for importing the CSV exports of other time trackers
+
+-- name: CreateCsvImportProfile :one
+INSERT INTO csv_import_profiles (user_id, name, mapping)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCsvImportProfileByID :one
+SELECT * FROM csv_import_profiles
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetCsvImportProfilesByUser :many
+SELECT * FROM csv_import_profiles
+WHERE user_id = $1
+ORDER BY name ASC;
+
+-- name: UpdateCsvImportProfile :one
+UPDATE csv_import_profiles
+SET name = $3, mapping = $4, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCsvImportProfile :execrows
+DELETE FROM csv_import_profiles
+WHERE id = $1 AND user_id = $2;
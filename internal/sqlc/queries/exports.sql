@@ -0,0 +1,56 @@
+-- EngLog Export Queries
+-- Data exports of log entries, projects and tag usage, read through server-side cursors
+
+-- name: ExportLogEntries :many
+SELECT
+    le.id, le.title, le.description, le.type, le.project_id,
+    p.name AS project_name,
+    le.start_time, le.end_time, le.duration_minutes, le.value_rating, le.impact_level,
+    COALESCE(ARRAY_AGG(t.name ORDER BY t.name) FILTER (WHERE t.id IS NOT NULL), '{}')::text[] AS tags,
+    le.created_at, le.updated_at
+FROM log_entries le
+LEFT JOIN projects p ON p.id = le.project_id
+LEFT JOIN log_entry_tags let ON let.log_entry_id = le.id
+LEFT JOIN tags t ON t.id = let.tag_id
+WHERE le.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(start_time)::timestamptz IS NULL OR le.start_time >= sqlc.narg(start_time)::timestamptz)
+  AND (sqlc.narg(end_time)::timestamptz IS NULL OR le.start_time <= sqlc.narg(end_time)::timestamptz)
+  AND (sqlc.narg(project_id)::uuid IS NULL OR le.project_id = sqlc.narg(project_id)::uuid)
+GROUP BY le.id, p.name
+ORDER BY le.start_time, le.id;
+
+-- name: ExportProjects :many
+-- Projects the user owns with the time they logged on them
+SELECT
+    p.id, p.name, p.description, p.status, p.color, p.parent_id,
+    parent.name AS parent_name,
+    p.start_date, p.end_date, p.estimated_hours, p.is_default, p.archived_at,
+    COUNT(le.id) AS entry_count,
+    COALESCE(SUM(le.duration_minutes), 0)::bigint AS total_minutes,
+    MAX(le.start_time)::timestamptz AS last_activity_at,
+    p.created_at
+FROM projects p
+LEFT JOIN projects parent ON parent.id = p.parent_id
+LEFT JOIN log_entries le ON le.project_id = p.id AND le.user_id = p.created_by
+WHERE p.created_by = $1
+GROUP BY p.id, parent.name
+ORDER BY p.name, p.id;
+
+-- name: ExportTagUsage :many
+-- Every tag of the user with how often and how long it was used
+SELECT
+    t.id, t.name, t.parent_id,
+    parent.name AS parent_name,
+    t.color, t.description,
+    COUNT(le.id) AS entry_count,
+    COALESCE(SUM(le.duration_minutes), 0)::bigint AS total_minutes,
+    MIN(le.start_time)::timestamptz AS first_used_at,
+    MAX(le.start_time)::timestamptz AS last_used_at,
+    t.created_at
+FROM tags t
+LEFT JOIN tags parent ON parent.id = t.parent_id
+LEFT JOIN log_entry_tags let ON let.tag_id = t.id
+LEFT JOIN log_entries le ON le.id = let.log_entry_id AND le.user_id = t.user_id
+WHERE t.user_id = $1
+GROUP BY t.id, parent.name
+ORDER BY entry_count DESC, t.name, t.id;
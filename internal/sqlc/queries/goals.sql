@@ -0,0 +1,60 @@
+-- EngLog Goal Queries
+-- Personal goals, OKR key results and the log entry metrics they are measured with
+
+-- name: CreateGoal :one
+INSERT INTO goals (
+    user_id, parent_id, title, description, metric, comparison, target_value,
+    period, start_date, end_date, activity_types, tags, project_id,
+    value_ratings, status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING *;
+
+-- name: GetGoalByID :one
+SELECT * FROM goals
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetGoalsByUser :many
+SELECT * FROM goals
+WHERE user_id = $1
+ORDER BY parent_id NULLS FIRST, created_at ASC;
+
+-- name: GetGoalChildren :many
+SELECT * FROM goals
+WHERE parent_id = $1 AND user_id = $2
+ORDER BY created_at ASC;
+
+-- name: UpdateGoal :one
+UPDATE goals
+SET parent_id = $3, title = $4, description = $5, metric = $6,
+    comparison = $7, target_value = $8, period = $9, start_date = $10,
+    end_date = $11, activity_types = $12, tags = $13, project_id = $14,
+    value_ratings = $15, status = $16, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteGoal :execrows
+DELETE FROM goals
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetGoalMetric :one
+-- Minutes and entries of the log entries matching a goal's filters, plus all
+-- minutes logged in the window so shares can be computed
+SELECT
+    COALESCE(SUM(le.duration_minutes) FILTER (WHERE m.matched), 0)::bigint AS matched_minutes,
+    COUNT(*) FILTER (WHERE m.matched) AS matched_entries,
+    COALESCE(SUM(le.duration_minutes), 0)::bigint AS total_minutes
+FROM log_entries le
+CROSS JOIN LATERAL (
+    SELECT (cardinality(sqlc.arg(activity_types)::text[]) = 0 OR le.type = ANY(sqlc.arg(activity_types)::text[]))
+       AND (sqlc.narg(project_id)::uuid IS NULL OR le.project_id = sqlc.narg(project_id)::uuid)
+       AND (cardinality(sqlc.arg(value_ratings)::text[]) = 0 OR le.value_rating = ANY(sqlc.arg(value_ratings)::text[]))
+       AND (cardinality(sqlc.arg(tags)::text[]) = 0 OR EXISTS (
+            SELECT 1 FROM log_entry_tags let
+            JOIN tags t ON t.id = let.tag_id
+            WHERE let.log_entry_id = le.id AND t.name = ANY(sqlc.arg(tags)::text[])
+       )) AS matched
+) m
+WHERE le.user_id = sqlc.arg(user_id)
+  AND le.start_time >= sqlc.arg(start_time)
+  AND le.start_time < sqlc.arg(end_time);
@@ -44,6 +44,11 @@ SET title = $2, description = $3, type = $4, project_id = $5,
 WHERE id = $1 AND user_id = $10
 RETURNING *;
 
+-- name: UpdateLogEntryProject :execrows
+UPDATE log_entries
+SET project_id = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
+
 -- name: DeleteLogEntry :execrows
 DELETE FROM log_entries
 WHERE id = $1 AND user_id = $2;
@@ -60,6 +65,16 @@ WHERE user_id = $1
   AND start_time >= $2
   AND start_time <= $3;
 
+-- name: GetLogEntryTotals :one
+-- Entries and minutes a user logged with a start time in [start_time, end_time)
+SELECT
+    COUNT(*) as total_entries,
+    COALESCE(SUM(duration_minutes), 0)::bigint as total_minutes
+FROM log_entries
+WHERE user_id = sqlc.arg(user_id)
+  AND start_time >= sqlc.arg(start_time)
+  AND start_time < sqlc.arg(end_time);
+
 -- name: GetRecentLogEntries :many
 SELECT le.*, p.name as project_name, p.color as project_color
 FROM log_entries le
@@ -0,0 +1,52 @@
+-- EngLog Log Entry Content Queries
+-- Structured links and file attachments of log entries
+
+-- name: CreateLogEntryLink :one
+INSERT INTO log_entry_links (log_entry_id, kind, url, title, position)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetLinksForLogEntry :many
+SELECT * FROM log_entry_links
+WHERE log_entry_id = $1
+ORDER BY position, created_at;
+
+-- name: DeleteLinksForLogEntry :exec
+DELETE FROM log_entry_links
+WHERE log_entry_id = $1;
+
+-- name: CreateAttachment :one
+INSERT INTO log_entry_attachments (
+    id, log_entry_id, user_id, file_name, content_type, size_bytes, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetAttachmentByID :one
+SELECT * FROM log_entry_attachments
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetAttachmentsForLogEntry :many
+SELECT * FROM log_entry_attachments
+WHERE log_entry_id = $1 AND user_id = $2
+ORDER BY created_at;
+
+-- name: DeleteAttachment :execrows
+DELETE FROM log_entry_attachments
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetUserAttachmentUsage :one
+SELECT COALESCE(SUM(size_bytes), 0)::BIGINT AS used_bytes
+FROM log_entry_attachments
+WHERE user_id = $1;
+
+-- name: LockUserAttachmentQuota :exec
+-- Serializes the uploads of a user until the transaction ends, so concurrent uploads cannot all fit in the same remaining quota
+SELECT pg_advisory_xact_lock(hashtext('log_entry_attachments'), hashtext(sqlc.arg(user_id)::uuid::text));
+
+-- name: GetOrphanedAttachments :many
+SELECT a.* FROM log_entry_attachments a
+WHERE NOT EXISTS (SELECT 1 FROM log_entries le WHERE le.id = a.log_entry_id)
+  AND NOT EXISTS (SELECT 1 FROM log_entry_trash t WHERE t.log_entry_id = a.log_entry_id)
+ORDER BY a.created_at
+LIMIT $1;
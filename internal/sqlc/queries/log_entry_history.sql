@@ -0,0 +1,74 @@
+-- EngLog Log Entry History Queries
+-- Append-only revisions and the soft-delete trash bin
+
+-- name: CreateLogEntryRevision :one
+INSERT INTO log_entry_revisions (
+    log_entry_id, user_id, revision, action, project_id, title, description,
+    type, start_time, end_time, value_rating, impact_level, tags, links
+) VALUES (
+    $1, $2,
+    (SELECT COALESCE(MAX(r.revision), 0) + 1 FROM log_entry_revisions r WHERE r.log_entry_id = $1),
+    $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetLogEntryRevisions :many
+SELECT * FROM log_entry_revisions
+WHERE log_entry_id = $1 AND user_id = $2
+ORDER BY revision DESC;
+
+-- name: GetLogEntryRevision :one
+SELECT * FROM log_entry_revisions
+WHERE log_entry_id = $1 AND user_id = $2 AND revision = $3;
+
+-- name: GetLatestLogEntryRevision :one
+SELECT * FROM log_entry_revisions
+WHERE log_entry_id = $1 AND user_id = $2
+ORDER BY revision DESC
+LIMIT 1;
+
+-- name: DeleteLogEntryRevisions :exec
+DELETE FROM log_entry_revisions
+WHERE log_entry_id = $1 AND user_id = $2;
+
+-- name: RestoreLogEntry :one
+INSERT INTO log_entries (
+    id, user_id, project_id, title, description, type,
+    start_time, end_time, value_rating, impact_level, created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: CreateLogEntryTrash :one
+INSERT INTO log_entry_trash (log_entry_id, user_id)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetLogEntryTrash :one
+SELECT * FROM log_entry_trash
+WHERE log_entry_id = $1 AND user_id = $2;
+
+-- name: GetTrashedLogEntries :many
+SELECT t.deleted_at, r.*
+FROM log_entry_trash t
+JOIN log_entry_revisions r ON r.log_entry_id = t.log_entry_id
+WHERE t.user_id = $1
+  AND r.revision = (
+      SELECT MAX(r2.revision) FROM log_entry_revisions r2
+      WHERE r2.log_entry_id = t.log_entry_id
+  )
+ORDER BY t.deleted_at DESC;
+
+-- name: DeleteLogEntryTrash :execrows
+DELETE FROM log_entry_trash
+WHERE log_entry_id = $1 AND user_id = $2;
+
+-- name: DeleteExpiredLogEntryRevisions :exec
+DELETE FROM log_entry_revisions
+WHERE log_entry_id IN (
+    SELECT log_entry_id FROM log_entry_trash
+    WHERE deleted_at < $1
+);
+
+-- name: PurgeExpiredLogEntryTrash :execrows
+DELETE FROM log_entry_trash
+WHERE deleted_at < $1;
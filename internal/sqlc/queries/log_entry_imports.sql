@@ -0,0 +1,12 @@
+-- EngLog Log Entry Import Queries
+-- Records of imported external items that keep repeated imports from duplicating entries
+
+-- name: CreateLogEntryImport :exec
+INSERT INTO log_entry_imports (user_id, source, external_id, log_entry_id)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetImportedExternalIDs :many
+-- External IDs of a source that the user already imported, out of the given ones
+SELECT external_id FROM log_entry_imports
+WHERE user_id = $1 AND source = $2 AND external_id = ANY(sqlc.arg(external_ids)::text[])
+ORDER BY external_id;
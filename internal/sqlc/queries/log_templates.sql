@@ -0,0 +1,77 @@
+-- EngLog Log Template Queries
+-- Recurring log entry templates and their per-occurrence state
+
+-- name: CreateLogTemplate :one
+INSERT INTO log_templates (
+    user_id, project_id, title, description, type, value_rating, impact_level,
+    tags, recurrence_rule, start_date, end_date, start_time, duration_minutes,
+    timezone, is_active
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING *;
+
+-- name: GetLogTemplateByID :one
+SELECT * FROM log_templates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetLogTemplatesByUser :many
+SELECT * FROM log_templates
+WHERE user_id = $1
+ORDER BY is_active DESC, title ASC;
+
+-- name: GetActiveLogTemplates :many
+SELECT * FROM log_templates
+WHERE is_active = TRUE
+  AND (end_date IS NULL OR end_date >= $1)
+ORDER BY user_id, id;
+
+-- name: UpdateLogTemplate :one
+UPDATE log_templates
+SET project_id = $3, title = $4, description = $5, type = $6,
+    value_rating = $7, impact_level = $8, tags = $9, recurrence_rule = $10,
+    start_date = $11, end_date = $12, start_time = $13, duration_minutes = $14,
+    timezone = $15, is_active = $16, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: SetLogTemplateMaterializedUntil :exec
+UPDATE log_templates
+SET materialized_until = $2
+WHERE id = $1;
+
+-- name: DeleteLogTemplate :execrows
+DELETE FROM log_templates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetLogTemplateOccurrence :one
+SELECT * FROM log_template_occurrences
+WHERE template_id = $1 AND occurrence_date = $2;
+
+-- name: GetLogTemplateOccurrencesInRange :many
+SELECT * FROM log_template_occurrences
+WHERE template_id = $1
+  AND occurrence_date >= $2
+  AND occurrence_date <= $3
+ORDER BY occurrence_date ASC;
+
+-- name: UpsertLogTemplateOccurrence :one
+INSERT INTO log_template_occurrences (
+    template_id, occurrence_date, status, log_entry_id, overrides
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (template_id, occurrence_date) DO UPDATE
+SET status = EXCLUDED.status,
+    log_entry_id = EXCLUDED.log_entry_id,
+    overrides = EXCLUDED.overrides,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteLogTemplateOccurrence :exec
+DELETE FROM log_template_occurrences
+WHERE template_id = $1 AND occurrence_date = $2;
+
+-- name: ReplaceLogTemplateTag :exec
+UPDATE log_templates
+SET tags = array_replace(tags, $2::text, $3::text), updated_at = NOW()
+WHERE user_id = $1 AND $2::text = ANY(tags);
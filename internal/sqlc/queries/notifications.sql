@@ -0,0 +1,79 @@
+-- EngLog Email Notification Queries
+-- Users who opted in to notifications and the emails sent to them
+
+-- name: CreateEmailNotification :one
+INSERT INTO email_notifications (
+    user_id, kind, period_key, recipient, subject, body_text, body_html, task_id, status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (user_id, kind, period_key) DO NOTHING
+RETURNING *;
+
+-- name: GetNotificationRecipients :many
+-- Users who turned a notification on in their preferences
+SELECT id, email, first_name, timezone, preferences FROM users
+WHERE preferences->'notifications'->>sqlc.arg(preference)::text = 'true'
+ORDER BY id;
+
+-- name: EmailNotificationExists :one
+SELECT EXISTS (
+    SELECT 1 FROM email_notifications
+    WHERE user_id = $1 AND kind = $2 AND period_key = $3
+);
+
+-- name: GetEmailNotificationsByUser :many
+SELECT * FROM email_notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id
+LIMIT $2;
+
+-- name: SetEmailNotificationTask :exec
+UPDATE email_notifications
+SET task_id = $2
+WHERE id = $1 AND status = 'waiting';
+
+-- name: GetWaitingEmailNotificationByTask :one
+SELECT * FROM email_notifications
+WHERE task_id = $1 AND status = 'waiting';
+
+-- name: GetStaleWaitingEmailNotifications :many
+-- Digests whose AI report did not come in time
+SELECT * FROM email_notifications
+WHERE status = 'waiting' AND created_at < $1
+ORDER BY created_at ASC
+LIMIT $2;
+
+-- name: PrepareEmailNotification :one
+-- Fills in the content of a waiting notification and makes it due
+UPDATE email_notifications
+SET subject = $2, body_text = $3, body_html = $4, status = 'pending', next_attempt_at = NOW()
+WHERE id = $1 AND status = 'waiting'
+RETURNING *;
+
+-- name: GetDueEmailNotifications :many
+SELECT * FROM email_notifications
+WHERE status = 'pending' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at ASC
+LIMIT $1;
+
+-- name: ClaimEmailNotification :one
+-- Moves a due notification out of reach of other processors until the lease ends
+UPDATE email_notifications
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id = sqlc.arg(id) AND status = 'pending' AND next_attempt_at <= NOW()
+RETURNING *;
+
+-- name: RecordEmailNotificationAttempt :one
+UPDATE email_notifications
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    sent_at = CASE WHEN sqlc.arg(status) = 'sent' THEN NOW() ELSE sent_at END,
+    error_message = sqlc.arg(error_message)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteOldEmailNotifications :execrows
+DELETE FROM email_notifications
+WHERE status NOT IN ('waiting', 'pending') AND created_at < $1;
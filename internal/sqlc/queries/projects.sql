@@ -3,9 +3,10 @@
 
 -- name: CreateProject :one
 INSERT INTO projects (
-    name, description, color, status, start_date, end_date, created_by, is_default
+    name, description, color, status, start_date, end_date, created_by, is_default, estimated_hours,
+    parent_id, default_type, default_tags, default_value_rating, default_impact_level
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 ) RETURNING *;
 
 -- name: GetProjectByID :one
@@ -14,12 +15,12 @@ WHERE id = $1;
 
 -- name: GetProjectsByUser :many
 SELECT * FROM projects
-WHERE created_by = $1
+WHERE created_by = $1 AND (sqlc.arg(include_archived)::boolean OR archived_at IS NULL)
 ORDER BY is_default DESC, name ASC;
 
 -- name: GetActiveProjectsByUser :many
 SELECT * FROM projects
-WHERE created_by = $1 AND status = 'active'
+WHERE created_by = $1 AND status = 'active' AND archived_at IS NULL
 ORDER BY is_default DESC, name ASC;
 
 -- name: GetUserDefaultProject :one
@@ -30,7 +31,9 @@ LIMIT 1;
 -- name: UpdateProject :one
 UPDATE projects
 SET name = $2, description = $3, color = $4, status = $5,
-    start_date = $6, end_date = $7, is_default = $8, updated_at = NOW()
+    start_date = $6, end_date = $7, is_default = $8, estimated_hours = $10,
+    parent_id = $11, default_type = $12, default_tags = $13,
+    default_value_rating = $14, default_impact_level = $15, updated_at = NOW()
 WHERE id = $1 AND created_by = $9
 RETURNING *;
 
@@ -71,3 +74,51 @@ SELECT
     MAX(le.start_time) as last_activity
 FROM log_entries le
 WHERE le.project_id = $1;
+
+-- name: CreateProjectActivityBudget :exec
+INSERT INTO project_activity_budgets (project_id, activity_type, estimated_hours)
+VALUES ($1, $2, $3);
+
+-- name: DeleteProjectActivityBudgets :exec
+DELETE FROM project_activity_budgets
+WHERE project_id = $1;
+
+-- name: GetProjectActivityBudgets :many
+SELECT * FROM project_activity_budgets
+WHERE project_id = $1
+ORDER BY activity_type;
+
+-- name: GetProjectActivityBudgetsByUser :many
+SELECT pab.* FROM project_activity_budgets pab
+JOIN projects p ON p.id = pab.project_id
+WHERE p.created_by = $1
+ORDER BY pab.project_id, pab.activity_type;
+
+-- name: GetProjectSubtree :many
+-- The project followed by every sub-project below it, with the depth below the project
+WITH RECURSIVE subtree AS (
+    SELECT p.id, p.parent_id, p.name, p.is_default, p.archived_at, 0 AS depth
+    FROM projects p
+    WHERE p.id = $1
+    UNION ALL
+    SELECT child.id, child.parent_id, child.name, child.is_default, child.archived_at, s.depth + 1
+    FROM projects child
+    JOIN subtree s ON child.parent_id = s.id
+    WHERE s.depth < 10
+)
+SELECT id, parent_id, name, is_default, archived_at, depth::int AS depth
+FROM subtree
+ORDER BY depth, name;
+
+-- name: SetProjectSubtreeArchived :execrows
+-- Archives, or restores with a NULL archived_at, a project and all its sub-projects
+WITH RECURSIVE subtree AS (
+    SELECT p.id FROM projects p
+    WHERE p.id = sqlc.arg(id) AND p.created_by = sqlc.arg(created_by)
+    UNION
+    SELECT child.id FROM projects child
+    JOIN subtree s ON child.parent_id = s.id
+)
+UPDATE projects
+SET archived_at = sqlc.narg(archived_at), updated_at = NOW()
+WHERE id IN (SELECT id FROM subtree);
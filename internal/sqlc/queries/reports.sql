@@ -0,0 +1,48 @@
+-- EngLog Report Queries
+-- Data gathered for generated reports such as the performance review
+
+-- name: GetBragDocEntries :many
+-- Entries of a period with their project, in the order they were logged
+SELECT
+    le.id, le.title, le.description, le.type, le.project_id,
+    p.name AS project_name,
+    le.start_time, le.duration_minutes, le.value_rating, le.impact_level
+FROM log_entries le
+LEFT JOIN projects p ON p.id = le.project_id
+WHERE le.user_id = sqlc.arg(user_id)
+  AND le.start_time >= sqlc.arg(start_time)
+  AND le.start_time <= sqlc.arg(end_time)
+ORDER BY le.start_time, le.id
+LIMIT sqlc.arg(max_entries);
+
+-- name: GetReportHighlights :many
+-- High-value or wide-impact entries of a period, most significant first
+SELECT
+    le.id, le.title, le.description, le.type,
+    p.name AS project_name,
+    le.start_time, le.duration_minutes, le.value_rating, le.impact_level
+FROM log_entries le
+LEFT JOIN projects p ON p.id = le.project_id
+WHERE le.user_id = sqlc.arg(user_id)
+  AND le.start_time >= sqlc.arg(start_time)
+  AND le.start_time <= sqlc.arg(end_time)
+  AND (le.value_rating IN ('high', 'critical') OR le.impact_level IN ('department', 'company'))
+ORDER BY
+    CASE le.impact_level WHEN 'company' THEN 4 WHEN 'department' THEN 3 WHEN 'team' THEN 2 ELSE 1 END DESC,
+    CASE le.value_rating WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END DESC,
+    le.duration_minutes DESC NULLS LAST,
+    le.start_time
+LIMIT sqlc.arg(max_entries);
+
+-- name: GetReportNarrative :one
+-- The active insight of a type that covers most of a period, newest first on ties
+SELECT * FROM generated_insights
+WHERE user_id = sqlc.arg(user_id)
+  AND report_type = sqlc.arg(report_type)
+  AND status = 'active'
+  AND period_start <= sqlc.arg(period_end)
+  AND period_end >= sqlc.arg(period_start)
+ORDER BY
+    LEAST(period_end, sqlc.arg(period_end)::date) - GREATEST(period_start, sqlc.arg(period_start)::date) DESC,
+    created_at DESC
+LIMIT 1;
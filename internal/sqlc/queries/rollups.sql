@@ -0,0 +1,95 @@
+-- EngLog Rollup Queries
+-- Analytics read from the per-user daily rollups; days are in the user's profile timezone
+
+-- name: GetRollupActivityTypeDistribution :many
+SELECT
+    type,
+    SUM(entry_count)::bigint AS entry_count,
+    SUM(total_minutes)::bigint AS total_minutes,
+    (SUM(total_minutes)::float8 / SUM(entry_count))::float8 AS avg_duration,
+    ROUND((SUM(critical_entries) * 4 + SUM(high_entries) * 3 + SUM(medium_entries) * 2 + SUM(low_entries))::numeric
+        / SUM(entry_count), 2) AS avg_value_score
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date
+GROUP BY type
+ORDER BY total_minutes DESC;
+
+-- name: GetRollupMonthlySummary :many
+SELECT
+    DATE_TRUNC('month', activity_date)::date AS month_start,
+    SUM(entry_count)::bigint AS entry_count,
+    SUM(total_minutes)::bigint AS total_minutes,
+    (SUM(total_minutes)::float8 / SUM(entry_count))::float8 AS avg_duration,
+    COUNT(DISTINCT project_id) AS projects_count,
+    COUNT(DISTINCT activity_date) AS active_days
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date
+GROUP BY month_start
+ORDER BY month_start DESC;
+
+-- name: GetRollupProductivityByDayOfWeek :many
+SELECT
+    EXTRACT(DOW FROM activity_date)::int AS day_of_week,
+    SUM(entry_count)::bigint AS entry_count,
+    SUM(total_minutes)::bigint AS total_minutes,
+    (SUM(total_minutes)::float8 / SUM(entry_count))::float8 AS avg_duration
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date
+GROUP BY day_of_week
+ORDER BY day_of_week;
+
+-- name: GetRollupProductivityTrend :many
+-- Same scores as GetUserProductivityTrend
+SELECT
+    activity_date,
+    SUM(total_minutes)::int AS total_minutes,
+    SUM(entry_count)::int AS entry_count,
+    ROUND((SUM(critical_entries) * 4 + SUM(high_entries) * 3 + SUM(medium_entries) * 2 + SUM(low_entries))::numeric
+        / SUM(entry_count), 2)::float8 AS avg_value_score,
+    ROUND(SUM(total_minutes) * ((SUM(critical_entries) * 4 + SUM(high_entries) * 3 + SUM(medium_entries) * 2 + SUM(low_entries))::numeric
+        / SUM(entry_count)) / 100.0, 2)::float8 AS productivity_score
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date
+GROUP BY activity_date
+ORDER BY activity_date ASC;
+
+-- name: GetRollupRatingTotals :one
+-- Entries per value rating and impact level
+SELECT
+    COALESCE(SUM(critical_entries), 0)::bigint AS critical_entries,
+    COALESCE(SUM(high_entries), 0)::bigint AS high_entries,
+    COALESCE(SUM(medium_entries), 0)::bigint AS medium_entries,
+    COALESCE(SUM(low_entries), 0)::bigint AS low_entries,
+    COALESCE(SUM(company_impact_entries), 0)::bigint AS company_impact_entries,
+    COALESCE(SUM(department_impact_entries), 0)::bigint AS department_impact_entries,
+    COALESCE(SUM(team_impact_entries), 0)::bigint AS team_impact_entries,
+    COALESCE(SUM(personal_impact_entries), 0)::bigint AS personal_impact_entries
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date;
+
+-- name: GetRollupWeeklySummary :many
+SELECT
+    (DATE_TRUNC('week', activity_date + make_interval(days => sqlc.arg(week_offset)::int))
+        - make_interval(days => sqlc.arg(week_offset)::int))::date AS week_start,
+    SUM(entry_count)::bigint AS entry_count,
+    SUM(total_minutes)::bigint AS total_minutes,
+    (SUM(total_minutes)::float8 / SUM(entry_count))::float8 AS avg_duration,
+    COUNT(DISTINCT project_id) AS projects_count,
+    COUNT(DISTINCT activity_date) AS active_days
+FROM user_daily_rollups
+WHERE user_id = sqlc.arg(user_id)
+  AND activity_date >= sqlc.arg(start_date)::date
+  AND activity_date <= sqlc.arg(end_date)::date
+GROUP BY week_start
+ORDER BY week_start DESC;
+
@@ -0,0 +1,95 @@
+-- EngLog Tag Hierarchy Queries
+-- Tag aliases, parent/child relationships and merges
+
+-- name: CreateTagAlias :one
+INSERT INTO tag_aliases (tag_id, user_id, alias)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTagAliases :many
+SELECT * FROM tag_aliases
+WHERE tag_id = $1
+ORDER BY alias;
+
+-- name: DeleteTagAlias :execrows
+DELETE FROM tag_aliases
+WHERE tag_id = $1 AND user_id = $2 AND LOWER(alias) = LOWER($3);
+
+-- name: GetTagByAlias :one
+SELECT t.* FROM tags t
+JOIN tag_aliases a ON a.tag_id = t.id
+WHERE a.user_id = $1 AND LOWER(a.alias) = LOWER($2);
+
+-- name: GetTagsByNameInsensitive :many
+SELECT * FROM tags
+WHERE user_id = $1 AND LOWER(name) = LOWER($2);
+
+-- name: MoveTagAliases :exec
+UPDATE tag_aliases
+SET tag_id = $2
+WHERE tag_id = $1;
+
+-- name: GetTagDescendantIDs :many
+-- The tag itself followed by every tag below it
+WITH RECURSIVE descendants AS (
+    SELECT t.id FROM tags t WHERE t.id = $1 AND t.user_id = $2
+    UNION
+    SELECT child.id FROM tags child
+    JOIN descendants d ON child.parent_id = d.id
+    WHERE child.user_id = $2
+)
+SELECT id FROM descendants;
+
+-- name: GetTagNamesWithDescendants :many
+-- Expands tag names or aliases into the names of those tags and all their descendants
+WITH RECURSIVE roots AS (
+    SELECT t.id FROM tags t
+    WHERE t.user_id = $1 AND t.name = ANY($2::text[])
+    UNION
+    SELECT a.tag_id FROM tag_aliases a
+    WHERE a.user_id = $1 AND LOWER(a.alias) IN (SELECT LOWER(n) FROM unnest($2::text[]) n)
+), tree AS (
+    SELECT id FROM roots
+    UNION
+    SELECT child.id FROM tags child
+    JOIN tree ON child.parent_id = tree.id
+    WHERE child.user_id = $1
+)
+SELECT t.name FROM tags t
+JOIN tree ON tree.id = t.id
+ORDER BY t.name;
+
+-- name: SetTagParent :exec
+UPDATE tags
+SET parent_id = $2
+WHERE id = $1;
+
+-- name: ReparentTagChildren :exec
+UPDATE tags
+SET parent_id = $2
+WHERE parent_id = $1 AND id <> $2;
+
+-- name: MoveLogEntryTags :execrows
+-- Copies the source tag's entry associations onto the target; deleting the source removes the rest
+INSERT INTO log_entry_tags (log_entry_id, tag_id)
+SELECT let.log_entry_id, $2::uuid FROM log_entry_tags let
+WHERE let.tag_id = $1
+ON CONFLICT (log_entry_id, tag_id) DO NOTHING;
+
+-- name: GetUserTagUsageWithDescendants :many
+-- Usage per tag counting each entry tagged with the tag or any of its descendants once
+WITH RECURSIVE tree AS (
+    SELECT t.id AS root_id, t.id AS tag_id FROM tags t WHERE t.user_id = $1
+    UNION
+    SELECT tree.root_id, child.id FROM tags child
+    JOIN tree ON child.parent_id = tree.tag_id
+    WHERE child.user_id = $1
+)
+SELECT
+    r.id, r.name, r.color, r.description, r.created_at, r.parent_id,
+    COUNT(DISTINCT let.log_entry_id)::int AS user_usage_count
+FROM tags r
+JOIN tree ON tree.root_id = r.id
+JOIN log_entry_tags let ON let.tag_id = tree.tag_id
+GROUP BY r.id, r.name, r.color, r.description, r.created_at, r.parent_id
+ORDER BY user_usage_count DESC, r.name ASC;
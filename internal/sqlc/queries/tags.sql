@@ -2,41 +2,69 @@
 -- Tag CRUD operations and usage statistics
 
 -- name: CreateTag :one
-INSERT INTO tags (name, color, description)
-VALUES ($1, $2, $3)
+INSERT INTO tags (name, color, description, user_id, parent_id)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetTagByID :one
+-- A tag of the user, a shared tag or a tag of one of the user's teams
 SELECT * FROM tags
-WHERE id = $1;
+WHERE id = $1
+  AND (user_id = $2
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2
+   ))));
 
 -- name: GetTagByName :one
 SELECT * FROM tags
-WHERE name = $1;
+WHERE user_id = $1 AND name = $2;
+
+-- name: GetSharedTagByName :one
+-- A shared tag or a tag of one of the user's teams, preferring team tags
+SELECT * FROM tags
+WHERE user_id IS NULL AND name = $1
+  AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2
+  ))
+ORDER BY team_id IS NULL, created_at
+LIMIT 1;
 
 -- name: GetAllTags :many
+-- The user's own tags plus shared and team tags they have not copied yet
 SELECT * FROM tags
+WHERE user_id = $1
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1
+   )) AND NOT EXISTS (
+        SELECT 1 FROM tags own WHERE own.user_id = $1 AND own.name = tags.name
+   ))
 ORDER BY usage_count DESC, name ASC;
 
 -- name: GetPopularTags :many
 SELECT * FROM tags
-WHERE usage_count > 0
+WHERE user_id = $1 AND usage_count > 0
 ORDER BY usage_count DESC, name ASC
-LIMIT $1;
+LIMIT $2;
 
 -- name: UpdateTag :one
 UPDATE tags
-SET name = $2, color = $3, description = $4
-WHERE id = $1
+SET name = $2, color = $3, description = $4, parent_id = $6
+WHERE id = $1 AND user_id = $5
 RETURNING *;
 
--- name: DeleteTag :exec
+-- name: DeleteTag :execrows
 DELETE FROM tags
-WHERE id = $1;
+WHERE id = $1 AND user_id = $2;
 
 -- name: SearchTags :many
 SELECT * FROM tags
 WHERE name ILIKE '%' || $1 || '%'
+  AND (user_id = $3
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $3
+   )) AND NOT EXISTS (
+        SELECT 1 FROM tags own WHERE own.user_id = $3 AND own.name = tags.name
+   )))
 ORDER BY usage_count DESC, name ASC
 LIMIT $2;
 
@@ -58,26 +86,16 @@ ORDER BY t.name;
 -- name: GetLogEntriesForTag :many
 SELECT le.* FROM log_entries le
 JOIN log_entry_tags let ON le.id = let.log_entry_id
-WHERE let.tag_id = $1
+WHERE let.tag_id = $1 AND le.user_id = $2
 ORDER BY le.start_time DESC;
 
--- name: GetTagUsageStats :one
-SELECT
-    COUNT(*) as total_tags,
-    COUNT(CASE WHEN usage_count > 0 THEN 1 END) as used_tags,
-    AVG(usage_count) as avg_usage,
-    MAX(usage_count) as max_usage
-FROM tags;
-
 -- name: GetUserTagUsage :many
 SELECT
-    t.id, t.name, t.color, t.description,
-    COUNT(let.log_entry_id) as user_usage_count
+    t.id, t.name, t.color, t.description, t.created_at, t.parent_id,
+    t.usage_count as user_usage_count
 FROM tags t
-LEFT JOIN log_entry_tags let ON t.id = let.tag_id
-LEFT JOIN log_entries le ON let.log_entry_id = le.id AND le.user_id = $1
-GROUP BY t.id, t.name, t.color, t.description
-HAVING COUNT(let.log_entry_id) > 0
+WHERE t.user_id = $1
+  AND t.usage_count > 0
 ORDER BY user_usage_count DESC, t.name ASC;
 
 -- name: GetRecentlyUsedTags :many
@@ -87,10 +105,32 @@ JOIN log_entry_tags let ON t.id = let.tag_id
 JOIN log_entries le ON let.log_entry_id = le.id
 WHERE le.user_id = $1
   AND le.created_at >= $2
-GROUP BY t.id, t.name, t.color, t.description, t.usage_count, t.created_at
+GROUP BY t.id, t.name, t.color, t.description, t.usage_count, t.created_at, t.user_id, t.parent_id, t.team_id
 ORDER BY last_used DESC;
 
 -- name: CleanupUnusedTags :exec
 DELETE FROM tags
-WHERE usage_count = 0
-  AND created_at < NOW() - INTERVAL '30 days';
+WHERE user_id IS NOT NULL
+  AND usage_count = 0
+  AND created_at < NOW() - INTERVAL '30 days'
+  AND NOT EXISTS (SELECT 1 FROM tags child WHERE child.parent_id = tags.id);
+
+-- name: CreateTeamTag :one
+INSERT INTO tags (name, color, description, team_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetTeamTags :many
+SELECT * FROM tags
+WHERE team_id = $1
+ORDER BY name ASC;
+
+-- name: UpdateTeamTag :one
+UPDATE tags
+SET name = $3, color = $4, description = $5
+WHERE id = $1 AND team_id = $2
+RETURNING *;
+
+-- name: DeleteTeamTag :execrows
+DELETE FROM tags
+WHERE id = $1 AND team_id = $2;
@@ -128,3 +128,39 @@ SELECT * FROM tasks
 WHERE user_id = $1
   AND created_at >= $2
 ORDER BY created_at DESC;
+
+-- name: GetUserTasksByType :many
+SELECT * FROM tasks
+WHERE user_id = $1 AND task_type = $2
+ORDER BY created_at DESC
+LIMIT $3;
+
+-- name: GetDueTasksByType :many
+-- Pending or retrying tasks of one type that are ready to run
+SELECT * FROM tasks
+WHERE task_type = $1
+  AND status IN ('pending', 'retrying')
+  AND scheduled_at <= NOW()
+ORDER BY priority ASC, scheduled_at ASC
+LIMIT $2;
+
+-- name: GetProcessingTasksByType :many
+SELECT * FROM tasks
+WHERE task_type = $1 AND status = 'processing'
+ORDER BY started_at ASC;
+
+-- name: GetExpiredTaskFiles :many
+-- Completed tasks whose result file passed its expires_at and was not removed yet
+SELECT * FROM tasks
+WHERE task_type = $1
+  AND status = 'completed'
+  AND result->>'storage_key' IS NOT NULL
+  AND (result->>'expires_at')::timestamptz < NOW()
+ORDER BY completed_at ASC
+LIMIT $2;
+
+-- name: ExpireTaskFile :exec
+UPDATE tasks
+SET result = (result - 'storage_key') || '{"expired": true}'::jsonb,
+    updated_at = NOW()
+WHERE id = $1;
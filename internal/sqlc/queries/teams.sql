@@ -0,0 +1,90 @@
+-- EngLog Team Queries
+-- Shared team workspaces, their members and the projects shared with them
+
+-- name: CreateTeam :one
+INSERT INTO teams (name, description, created_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTeamByID :one
+SELECT * FROM teams
+WHERE id = $1;
+
+-- name: GetTeamsByUser :many
+-- The teams a user belongs to, with the user's role and the team size
+SELECT
+    t.id, t.name, t.description, t.created_by, t.created_at, t.updated_at,
+    tm.role,
+    (SELECT COUNT(*) FROM team_members m WHERE m.team_id = t.id)::int AS member_count
+FROM teams t
+JOIN team_members tm ON tm.team_id = t.id
+WHERE tm.user_id = $1
+ORDER BY t.name ASC;
+
+-- name: UpdateTeam :one
+UPDATE teams
+SET name = $2, description = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTeam :exec
+DELETE FROM teams
+WHERE id = $1;
+
+-- name: AddTeamMember :one
+INSERT INTO team_members (team_id, user_id, role)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTeamMember :one
+SELECT * FROM team_members
+WHERE team_id = $1 AND user_id = $2;
+
+-- name: GetTeamMembers :many
+-- Members with the profile fields teams may see; preferences hold the member's team sharing setting
+SELECT
+    tm.user_id, tm.role, tm.joined_at,
+    u.email, u.first_name, u.last_name, u.preferences
+FROM team_members tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+ORDER BY
+    CASE tm.role WHEN 'owner' THEN 0 WHEN 'manager' THEN 1 WHEN 'member' THEN 2 ELSE 3 END,
+    u.first_name ASC, u.last_name ASC;
+
+-- name: UpdateTeamMemberRole :one
+UPDATE team_members
+SET role = $3
+WHERE team_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: RemoveTeamMember :execrows
+DELETE FROM team_members
+WHERE team_id = $1 AND user_id = $2;
+
+-- name: CountTeamOwners :one
+SELECT COUNT(*)::int AS owners
+FROM team_members
+WHERE team_id = $1 AND role = 'owner';
+
+-- name: ShareProjectWithTeam :execrows
+INSERT INTO team_projects (team_id, project_id)
+VALUES ($1, $2)
+ON CONFLICT (team_id, project_id) DO NOTHING;
+
+-- name: UnshareProjectFromTeam :execrows
+DELETE FROM team_projects
+WHERE team_id = $1 AND project_id = $2;
+
+-- name: GetTeamProjects :many
+SELECT p.* FROM projects p
+JOIN team_projects tp ON tp.project_id = p.id
+WHERE tp.team_id = $1
+ORDER BY p.name ASC;
+
+-- name: GetProjectTeamRoles :many
+-- The roles a user holds in the teams a project is shared with
+SELECT tm.team_id, tm.role
+FROM team_projects tp
+JOIN team_members tm ON tm.team_id = tp.team_id
+WHERE tp.project_id = $1 AND tm.user_id = $2;
@@ -0,0 +1,89 @@
+-- EngLog Webhook Queries
+-- Endpoints users registered for events and the log of deliveries made to them
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, name, url, secret, events, active)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: CountWebhooks :one
+SELECT COUNT(*) FROM webhooks
+WHERE user_id = $1;
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetWebhooksByUser :many
+SELECT * FROM webhooks
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: GetWebhooksForEvent :many
+-- Active webhooks of a user subscribed to an event type
+SELECT * FROM webhooks
+WHERE user_id = sqlc.arg(user_id) AND active AND sqlc.arg(event_type)::text = ANY(events)
+ORDER BY created_at ASC;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET name = $3, url = $4, events = $5, active = $6, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: UpdateWebhookSecret :one
+UPDATE webhooks
+SET secret = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, user_id, event_id, event_type, payload, replay_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetWebhookDeliveries :many
+-- Deliveries of a webhook, newest first, optionally only those with a status
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id)
+  AND user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg(max_deliveries);
+
+-- name: GetDueWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at ASC
+LIMIT $1;
+
+-- name: ClaimWebhookDelivery :one
+-- Moves a due delivery out of reach of other processors until the lease ends
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id = sqlc.arg(id) AND status = 'pending' AND next_attempt_at <= NOW()
+RETURNING *;
+
+-- name: RecordWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    last_attempt_at = NOW(),
+    response_status = sqlc.arg(response_status),
+    error_message = sqlc.arg(error_message),
+    duration_ms = sqlc.arg(duration_ms)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteOldWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < $1;
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Recurring log entry templates (e.g. "daily standup", "1:1 every other Monday")
+CREATE TABLE IF NOT EXISTS log_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    type VARCHAR(50) NOT NULL CHECK (type IN (
+        'development', 'meeting', 'code_review', 'debugging', 'documentation',
+        'testing', 'deployment', 'research', 'planning', 'learning',
+        'maintenance', 'support', 'other'
+    )),
+    value_rating VARCHAR(20) NOT NULL CHECK (value_rating IN ('low', 'medium', 'high', 'critical')),
+    impact_level VARCHAR(20) NOT NULL CHECK (impact_level IN ('personal', 'team', 'department', 'company')),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    recurrence_rule TEXT NOT NULL, -- RFC 5545 RRULE, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=MO
+    start_date DATE NOT NULL, -- First day the rule applies, in the template timezone
+    end_date DATE, -- Optional last day (inclusive)
+    start_time TIME NOT NULL, -- Local wall clock time of each occurrence
+    duration_minutes INTEGER NOT NULL,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'UTC',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    materialized_until DATE, -- Last local day processed by the scheduler
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Constraints
+    CONSTRAINT log_templates_duration_check CHECK (duration_minutes > 0 AND duration_minutes <= 1440),
+    CONSTRAINT log_templates_dates_check CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- Per-occurrence state: materialized entries, skipped dates and single-occurrence edits
+CREATE TABLE IF NOT EXISTS log_template_occurrences (
+    template_id UUID NOT NULL REFERENCES log_templates(id) ON DELETE CASCADE,
+    occurrence_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'materialized', 'skipped')),
+    log_entry_id UUID REFERENCES log_entries(id) ON DELETE SET NULL,
+    overrides JSONB NOT NULL DEFAULT '{}', -- Field overrides for this occurrence only
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (template_id, occurrence_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_log_templates_user ON log_templates(user_id);
+CREATE INDEX IF NOT EXISTS idx_log_templates_active ON log_templates(is_active) WHERE is_active = TRUE;
+CREATE INDEX IF NOT EXISTS idx_log_template_occurrences_entry ON log_template_occurrences(log_entry_id) WHERE log_entry_id IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_log_template_occurrences_entry;
+DROP INDEX IF EXISTS idx_log_templates_active;
+DROP INDEX IF EXISTS idx_log_templates_user;
+DROP TABLE IF EXISTS log_template_occurrences CASCADE;
+DROP TABLE IF EXISTS log_templates CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Append-only revisions of log entries, including their tag set.
+-- Rows are never updated; they outlive the entry while it sits in the trash.
+CREATE TABLE IF NOT EXISTS log_entry_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    log_entry_id UUID NOT NULL, -- No FK: revisions are kept while the entry is in the trash
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    type VARCHAR(50) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    value_rating VARCHAR(20) NOT NULL,
+    impact_level VARCHAR(20) NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT log_entry_revisions_unique UNIQUE (log_entry_id, revision)
+);
+
+-- Soft-deleted log entries. The entry row is removed from log_entries so that
+-- every existing query and analytics view ignores it; its content lives on
+-- in the latest ('deleted') revision until it is restored or purged.
+CREATE TABLE IF NOT EXISTS log_entry_trash (
+    log_entry_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_log_entry_revisions_user ON log_entry_revisions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_log_entry_trash_user ON log_entry_trash(user_id, deleted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_log_entry_trash_deleted_at ON log_entry_trash(deleted_at);
+
+-- Baseline revision for entries created before history was tracked
+INSERT INTO log_entry_revisions (
+    log_entry_id, user_id, revision, action, project_id, title, description,
+    type, start_time, end_time, value_rating, impact_level, tags, created_at
+)
+SELECT
+    le.id, le.user_id, 1, 'created', le.project_id, le.title, le.description,
+    le.type, le.start_time, le.end_time, le.value_rating, le.impact_level,
+    ARRAY(
+        SELECT t.name FROM log_entry_tags let
+        JOIN tags t ON t.id = let.tag_id
+        WHERE let.log_entry_id = le.id
+        ORDER BY t.name
+    ),
+    COALESCE(le.updated_at, NOW())
+FROM log_entries le
+ON CONFLICT (log_entry_id, revision) DO NOTHING;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_log_entry_trash_deleted_at;
+DROP INDEX IF EXISTS idx_log_entry_trash_user;
+DROP INDEX IF EXISTS idx_log_entry_revisions_user;
+DROP TABLE IF EXISTS log_entry_trash CASCADE;
+DROP TABLE IF EXISTS log_entry_revisions CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Structured links (pull requests, tickets, design docs...) attached to log entries
+CREATE TABLE IF NOT EXISTS log_entry_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    log_entry_id UUID NOT NULL REFERENCES log_entries(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN (
+        'pull_request', 'issue', 'ticket', 'design_doc', 'document', 'dashboard', 'other'
+    )),
+    url TEXT NOT NULL,
+    title VARCHAR(200),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Files attached to log entries. The content lives on the blob storage backend
+-- under storage_key. Like revisions, rows have no FK to log_entries so they
+-- survive while the entry is in the trash; orphans are swept after a purge.
+CREATE TABLE IF NOT EXISTS log_entry_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    log_entry_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Links are part of the entry content, so revisions keep them too
+ALTER TABLE log_entry_revisions ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_log_entry_links_entry ON log_entry_links(log_entry_id, position);
+CREATE INDEX IF NOT EXISTS idx_log_entry_attachments_entry ON log_entry_attachments(log_entry_id);
+CREATE INDEX IF NOT EXISTS idx_log_entry_attachments_user ON log_entry_attachments(user_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_log_entry_attachments_user;
+DROP INDEX IF EXISTS idx_log_entry_attachments_entry;
+DROP INDEX IF EXISTS idx_log_entry_links_entry;
+ALTER TABLE log_entry_revisions DROP COLUMN IF EXISTS links;
+DROP TABLE IF EXISTS log_entry_attachments CASCADE;
+DROP TABLE IF EXISTS log_entry_links CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,111 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags become owned by a user. Shared tags (user_id IS NULL) are a read-only
+-- vocabulary: using one on a log entry creates a personal copy, so every
+-- log_entry_tags row points at a tag owned by the entry's author and the
+-- usage_count maintained by the trigger is a per-user count.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+DROP INDEX IF EXISTS idx_tags_name;
+
+-- Split every tag in use into one personal copy per user that used it
+CREATE TEMP TABLE tag_split ON COMMIT DROP AS
+SELECT pairs.old_tag_id, pairs.user_id, uuid_generate_v4() AS new_tag_id
+FROM (
+    SELECT DISTINCT let.tag_id AS old_tag_id, le.user_id
+    FROM log_entry_tags let
+    JOIN log_entries le ON le.id = let.log_entry_id
+) pairs;
+
+INSERT INTO tags (id, name, color, description, usage_count, created_at, user_id)
+SELECT s.new_tag_id, t.name, t.color, t.description, 0, t.created_at, s.user_id
+FROM tag_split s
+JOIN tags t ON t.id = s.old_tag_id;
+
+UPDATE log_entry_tags let
+SET tag_id = s.new_tag_id
+FROM tag_split s, log_entries le
+WHERE le.id = let.log_entry_id
+  AND s.old_tag_id = let.tag_id
+  AND s.user_id = le.user_id;
+
+-- Tags that were split are replaced by their copies; unused tags stay shared
+DELETE FROM tags WHERE id IN (SELECT old_tag_id FROM tag_split);
+
+UPDATE tags t
+SET usage_count = (SELECT COUNT(*) FROM log_entry_tags let WHERE let.tag_id = t.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, name) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_shared_name ON tags(name) WHERE user_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tags_user_popular ON tags(user_id, usage_count DESC, name);
+
+-- Only tags owned by the entry's author may be attached to it
+CREATE OR REPLACE FUNCTION update_tag_usage_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NOT EXISTS (
+            SELECT 1
+            FROM tags t
+            JOIN log_entries le ON le.id = NEW.log_entry_id
+            WHERE t.id = NEW.tag_id AND t.user_id = le.user_id
+        ) THEN
+            RAISE EXCEPTION 'tag % is not owned by the author of log entry %', NEW.tag_id, NEW.log_entry_id
+                USING ERRCODE = 'foreign_key_violation';
+        END IF;
+        UPDATE tags SET usage_count = usage_count + 1 WHERE id = NEW.tag_id;
+        RETURN NEW;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE tags SET usage_count = GREATEST(usage_count - 1, 0) WHERE id = OLD.tag_id;
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_tag_usage_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE tags SET usage_count = usage_count + 1 WHERE id = NEW.tag_id;
+        RETURN NEW;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE tags SET usage_count = GREATEST(usage_count - 1, 0) WHERE id = OLD.tag_id;
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_tags_user_popular;
+DROP INDEX IF EXISTS idx_tags_shared_name;
+DROP INDEX IF EXISTS idx_tags_user_name;
+
+-- Merge tags with the same name back into a single global tag
+CREATE TEMP TABLE tag_merge ON COMMIT DROP AS
+SELECT t.id AS old_tag_id, keep.id AS keep_tag_id
+FROM tags t
+JOIN (
+    SELECT DISTINCT ON (name) id, name
+    FROM tags
+    ORDER BY name, user_id NULLS FIRST, created_at
+) keep ON keep.name = t.name
+WHERE t.id <> keep.id;
+
+UPDATE log_entry_tags let
+SET tag_id = m.keep_tag_id
+FROM tag_merge m
+WHERE let.tag_id = m.old_tag_id;
+
+DELETE FROM tags WHERE id IN (SELECT old_tag_id FROM tag_merge);
+
+UPDATE tags t
+SET usage_count = (SELECT COUNT(*) FROM log_entry_tags let WHERE let.tag_id = t.id);
+
+ALTER TABLE tags DROP COLUMN IF EXISTS user_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+-- +goose StatementEnd
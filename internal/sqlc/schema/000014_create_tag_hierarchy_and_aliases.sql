@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags can be nested (k8s/networking under k8s). Deleting a parent leaves its
+-- children at the top level; the service re-parents them on merge.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tags(id) ON DELETE SET NULL;
+ALTER TABLE tags ADD CONSTRAINT tags_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+
+-- Alternative spellings of a user's tag (golang, Go -> go). Aliases are matched
+-- case-insensitively when log entries are written.
+CREATE TABLE IF NOT EXISTS tag_aliases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alias VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tags_parent ON tags(parent_id) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_aliases_user_alias ON tag_aliases(user_id, LOWER(alias));
+CREATE INDEX IF NOT EXISTS idx_tag_aliases_tag ON tag_aliases(tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tag_aliases_tag;
+DROP INDEX IF EXISTS idx_tag_aliases_user_alias;
+DROP INDEX IF EXISTS idx_tags_parent;
+DROP TABLE IF EXISTS tag_aliases;
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_parent_not_self;
+ALTER TABLE tags DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
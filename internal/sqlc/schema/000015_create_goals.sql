@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Personal goals measured against log entries ("8h/week of learning",
+-- "3 design docs this quarter", "support below 20% of my time").
+-- A goal with metric 'key_results' is an objective whose progress is the
+-- average of its child goals (OKR style).
+CREATE TABLE IF NOT EXISTS goals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES goals(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    metric VARCHAR(20) NOT NULL CHECK (metric IN ('minutes', 'entries', 'share', 'key_results')),
+    comparison VARCHAR(10) NOT NULL DEFAULT 'at_least' CHECK (comparison IN ('at_least', 'at_most')),
+    target_value DOUBLE PRECISION NOT NULL DEFAULT 0, -- Minutes, entry count or percentage of logged time
+    period VARCHAR(20) NOT NULL CHECK (period IN ('week', 'month', 'quarter', 'year', 'custom')),
+    start_date DATE, -- Only for custom periods, in the user's timezone
+    end_date DATE, -- Only for custom periods (inclusive)
+
+    -- Entry filters; an empty filter matches every entry
+    activity_types TEXT[] NOT NULL DEFAULT '{}',
+    tags TEXT[] NOT NULL DEFAULT '{}', -- Also matches descendant tags
+    project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    value_ratings TEXT[] NOT NULL DEFAULT '{}',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'archived')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Constraints
+    CONSTRAINT goals_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id),
+    CONSTRAINT goals_target_check CHECK (target_value >= 0),
+    CONSTRAINT goals_share_check CHECK (metric <> 'share' OR target_value <= 100),
+    CONSTRAINT goals_custom_period_check CHECK (
+        (period = 'custom' AND start_date IS NOT NULL AND end_date IS NOT NULL AND end_date >= start_date)
+        OR (period <> 'custom' AND start_date IS NULL AND end_date IS NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_user_status ON goals(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_goals_parent ON goals(parent_id) WHERE parent_id IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_goals_parent;
+DROP INDEX IF EXISTS idx_goals_user_status;
+DROP TABLE IF EXISTS goals CASCADE;
+
+-- +goose StatementEnd
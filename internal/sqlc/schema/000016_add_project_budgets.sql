@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Effort budgets: an estimate in hours for the whole project, optionally split
+-- by activity type ("40h total, of which 8h of meetings"). Actual effort is
+-- always derived from log_entries.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS estimated_hours DOUBLE PRECISION;
+ALTER TABLE projects ADD CONSTRAINT projects_estimated_hours_check CHECK (estimated_hours IS NULL OR estimated_hours > 0);
+
+CREATE TABLE IF NOT EXISTS project_activity_budgets (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    activity_type VARCHAR(50) NOT NULL CHECK (
+        activity_type IN (
+            'development', 'meeting', 'code_review', 'debugging',
+            'documentation', 'testing', 'deployment', 'research',
+            'planning', 'learning', 'maintenance', 'support', 'other'
+        )
+    ),
+    estimated_hours DOUBLE PRECISION NOT NULL CHECK (estimated_hours > 0),
+    PRIMARY KEY (project_id, activity_type)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS project_activity_budgets;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_estimated_hours_check;
+ALTER TABLE projects DROP COLUMN IF EXISTS estimated_hours;
+
+-- +goose StatementEnd
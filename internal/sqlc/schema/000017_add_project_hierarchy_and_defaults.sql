@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sub-projects (epic -> project -> workstream). Deleting a project turns its
+-- sub-projects into top-level projects; depth and cycles are checked by the service.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES projects(id) ON DELETE SET NULL;
+ALTER TABLE projects ADD CONSTRAINT projects_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+
+-- Archived projects are hidden from pickers but keep their log entries
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Defaults for log entries created on the project without these fields
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS default_type VARCHAR(50) CHECK (
+    default_type IN (
+        'development', 'meeting', 'code_review', 'debugging',
+        'documentation', 'testing', 'deployment', 'research',
+        'planning', 'learning', 'maintenance', 'support', 'other'
+    )
+);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS default_tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS default_value_rating VARCHAR(20) CHECK (
+    default_value_rating IN ('low', 'medium', 'high', 'critical')
+);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS default_impact_level VARCHAR(20) CHECK (
+    default_impact_level IN ('personal', 'team', 'department', 'company')
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_parent ON projects(parent_id) WHERE parent_id IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_projects_parent;
+ALTER TABLE projects DROP COLUMN IF EXISTS default_impact_level;
+ALTER TABLE projects DROP COLUMN IF EXISTS default_value_rating;
+ALTER TABLE projects DROP COLUMN IF EXISTS default_tags;
+ALTER TABLE projects DROP COLUMN IF EXISTS default_type;
+ALTER TABLE projects DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_parent_not_self;
+ALTER TABLE projects DROP COLUMN IF EXISTS parent_id;
+
+-- +goose StatementEnd
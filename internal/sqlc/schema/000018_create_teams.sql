@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Shared team workspaces. Log entries stay personal: teams see the projects
+-- shared with them and aggregate activity on those projects, never raw entries.
+CREATE TABLE IF NOT EXISTS teams (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Every team keeps at least one owner; the service refuses to remove or demote the last one
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'manager', 'member', 'viewer')),
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+-- Projects shared with a team; the project stays owned by its creator
+CREATE TABLE IF NOT EXISTS team_projects (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    shared_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (team_id, project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_team_projects_project ON team_projects(project_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_team_projects_project;
+DROP INDEX IF EXISTS idx_team_members_user;
+DROP TABLE IF EXISTS team_projects;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams CASCADE;
+
+-- +goose StatementEnd
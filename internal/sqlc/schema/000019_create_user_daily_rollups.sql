@@ -0,0 +1,182 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-user daily aggregates of log entries, maintained by triggers on every write so heavy
+-- analytics read precomputed rows instead of scanning log entries. Days are calendar days
+-- in the user's profile timezone; changing the timezone rebuilds the user's rollups.
+CREATE TABLE IF NOT EXISTS user_daily_rollups (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_date DATE NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    project_id UUID, -- No foreign key: deleting a project moves its entries to no project through the entry trigger
+    entry_count INTEGER NOT NULL DEFAULT 0,
+    total_minutes INTEGER NOT NULL DEFAULT 0,
+    critical_entries INTEGER NOT NULL DEFAULT 0,
+    high_entries INTEGER NOT NULL DEFAULT 0,
+    medium_entries INTEGER NOT NULL DEFAULT 0,
+    low_entries INTEGER NOT NULL DEFAULT 0,
+    company_impact_entries INTEGER NOT NULL DEFAULT 0,
+    department_impact_entries INTEGER NOT NULL DEFAULT 0,
+    team_impact_entries INTEGER NOT NULL DEFAULT 0,
+    personal_impact_entries INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT user_daily_rollups_key UNIQUE NULLS NOT DISTINCT (user_id, activity_date, type, project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_daily_rollups_user_date ON user_daily_rollups(user_id, activity_date);
+
+-- The calendar day of ts in tz, falling back to UTC for timezones PostgreSQL does not know
+CREATE OR REPLACE FUNCTION rollup_local_date(ts TIMESTAMPTZ, tz TEXT)
+RETURNS DATE AS $$
+BEGIN
+    RETURN (ts AT TIME ZONE tz)::date;
+EXCEPTION WHEN invalid_parameter_value THEN
+    RETURN (ts AT TIME ZONE 'UTC')::date;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Adds (sign = 1) or removes (sign = -1) one log entry from its owner's rollups
+CREATE OR REPLACE FUNCTION apply_user_daily_rollup(entry log_entries, sign INTEGER)
+RETURNS VOID AS $$
+DECLARE
+    day DATE;
+BEGIN
+    SELECT rollup_local_date(entry.start_time, COALESCE(u.timezone, 'UTC')) INTO day
+    FROM users u
+    WHERE u.id = entry.user_id;
+
+    -- The owner is being deleted: its rollups go with it
+    IF day IS NULL THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO user_daily_rollups AS r (
+        user_id, activity_date, type, project_id, entry_count, total_minutes,
+        critical_entries, high_entries, medium_entries, low_entries,
+        company_impact_entries, department_impact_entries, team_impact_entries, personal_impact_entries
+    ) VALUES (
+        entry.user_id, day, entry.type, entry.project_id, sign, sign * entry.duration_minutes,
+        CASE WHEN entry.value_rating = 'critical' THEN sign ELSE 0 END,
+        CASE WHEN entry.value_rating = 'high' THEN sign ELSE 0 END,
+        CASE WHEN entry.value_rating = 'medium' THEN sign ELSE 0 END,
+        CASE WHEN entry.value_rating = 'low' THEN sign ELSE 0 END,
+        CASE WHEN entry.impact_level = 'company' THEN sign ELSE 0 END,
+        CASE WHEN entry.impact_level = 'department' THEN sign ELSE 0 END,
+        CASE WHEN entry.impact_level = 'team' THEN sign ELSE 0 END,
+        CASE WHEN entry.impact_level = 'personal' THEN sign ELSE 0 END
+    )
+    ON CONFLICT ON CONSTRAINT user_daily_rollups_key DO UPDATE SET
+        entry_count = r.entry_count + EXCLUDED.entry_count,
+        total_minutes = r.total_minutes + EXCLUDED.total_minutes,
+        critical_entries = r.critical_entries + EXCLUDED.critical_entries,
+        high_entries = r.high_entries + EXCLUDED.high_entries,
+        medium_entries = r.medium_entries + EXCLUDED.medium_entries,
+        low_entries = r.low_entries + EXCLUDED.low_entries,
+        company_impact_entries = r.company_impact_entries + EXCLUDED.company_impact_entries,
+        department_impact_entries = r.department_impact_entries + EXCLUDED.department_impact_entries,
+        team_impact_entries = r.team_impact_entries + EXCLUDED.team_impact_entries,
+        personal_impact_entries = r.personal_impact_entries + EXCLUDED.personal_impact_entries,
+        updated_at = NOW();
+
+    IF sign < 0 THEN
+        DELETE FROM user_daily_rollups
+        WHERE user_id = entry.user_id
+          AND activity_date = day
+          AND type = entry.type
+          AND project_id IS NOT DISTINCT FROM entry.project_id
+          AND entry_count <= 0;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_user_daily_rollups()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM apply_user_daily_rollup(OLD, -1);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM apply_user_daily_rollup(NEW, 1);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Recomputes every rollup of a user from their log entries
+CREATE OR REPLACE FUNCTION rebuild_user_daily_rollups(user_uuid UUID)
+RETURNS VOID AS $$
+BEGIN
+    DELETE FROM user_daily_rollups WHERE user_id = user_uuid;
+
+    INSERT INTO user_daily_rollups (
+        user_id, activity_date, type, project_id, entry_count, total_minutes,
+        critical_entries, high_entries, medium_entries, low_entries,
+        company_impact_entries, department_impact_entries, team_impact_entries, personal_impact_entries
+    )
+    SELECT
+        le.user_id,
+        rollup_local_date(le.start_time, COALESCE(u.timezone, 'UTC')) AS activity_date,
+        le.type,
+        le.project_id,
+        COUNT(*),
+        SUM(le.duration_minutes),
+        COUNT(*) FILTER (WHERE le.value_rating = 'critical'),
+        COUNT(*) FILTER (WHERE le.value_rating = 'high'),
+        COUNT(*) FILTER (WHERE le.value_rating = 'medium'),
+        COUNT(*) FILTER (WHERE le.value_rating = 'low'),
+        COUNT(*) FILTER (WHERE le.impact_level = 'company'),
+        COUNT(*) FILTER (WHERE le.impact_level = 'department'),
+        COUNT(*) FILTER (WHERE le.impact_level = 'team'),
+        COUNT(*) FILTER (WHERE le.impact_level = 'personal')
+    FROM log_entries le
+    JOIN users u ON u.id = le.user_id
+    WHERE le.user_id = user_uuid
+    GROUP BY le.user_id, activity_date, le.type, le.project_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION rebuild_user_daily_rollups_on_timezone()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM rebuild_user_daily_rollups(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger WHERE tgname = 'trigger_update_user_daily_rollups'
+    ) THEN
+        CREATE TRIGGER trigger_update_user_daily_rollups
+            AFTER INSERT OR DELETE OR UPDATE OF user_id, project_id, type, start_time, end_time, value_rating, impact_level
+            ON log_entries
+            FOR EACH ROW
+            EXECUTE FUNCTION update_user_daily_rollups();
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger WHERE tgname = 'trigger_rebuild_user_daily_rollups'
+    ) THEN
+        CREATE TRIGGER trigger_rebuild_user_daily_rollups
+            AFTER UPDATE OF timezone ON users
+            FOR EACH ROW
+            WHEN (OLD.timezone IS DISTINCT FROM NEW.timezone)
+            EXECUTE FUNCTION rebuild_user_daily_rollups_on_timezone();
+    END IF;
+END $$;
+
+-- Backfill existing log entries
+SELECT rebuild_user_daily_rollups(id) FROM users;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trigger_rebuild_user_daily_rollups ON users;
+DROP TRIGGER IF EXISTS trigger_update_user_daily_rollups ON log_entries;
+DROP FUNCTION IF EXISTS rebuild_user_daily_rollups_on_timezone();
+DROP FUNCTION IF EXISTS rebuild_user_daily_rollups(UUID);
+DROP FUNCTION IF EXISTS update_user_daily_rollups();
+DROP FUNCTION IF EXISTS apply_user_daily_rollup(log_entries, INTEGER);
+DROP FUNCTION IF EXISTS rollup_local_date(TIMESTAMPTZ, TEXT);
+DROP TABLE IF EXISTS user_daily_rollups;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Items of external sources, such as calendar events, that were imported as log entries.
+-- Importing the same source again skips the items recorded here, including those whose
+-- entries the user deleted afterwards.
+CREATE TABLE IF NOT EXISTS log_entry_imports (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('ics')),
+    external_id TEXT NOT NULL, -- Calendar events: the UID, followed by the occurrence start for recurring events
+    log_entry_id UUID NOT NULL, -- No FK: the record is kept while the entry is in the trash and after it is purged
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, source, external_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS log_entry_imports;
+-- +goose StatementEnd
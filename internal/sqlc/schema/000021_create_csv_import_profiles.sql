@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Saved column mappings for importing CSV exports of other time trackers
+CREATE TABLE IF NOT EXISTS csv_import_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    mapping JSONB NOT NULL DEFAULT '{}', -- models.CSVColumnMapping
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_csv_import_profiles_user_name ON csv_import_profiles(user_id, LOWER(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_csv_import_profiles_user_name;
+DROP TABLE IF EXISTS csv_import_profiles;
+-- +goose StatementEnd
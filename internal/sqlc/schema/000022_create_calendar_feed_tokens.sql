@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Secret tokens of the iCalendar feeds users subscribe to; only their SHA-256 is kept
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- hex SHA-256 of the token
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feed_tokens_user ON calendar_feed_tokens(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_calendar_feed_tokens_user;
+DROP TABLE IF EXISTS calendar_feed_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails sent, or to be sent, to users. A notification is recorded once per user,
+-- kind and period (a day, a week or a task), which keeps it from being sent twice.
+-- Waiting digests wait for their AI report; pending notifications are attempted
+-- again at next_attempt_at.
+CREATE TABLE IF NOT EXISTS email_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL, -- models.NotificationKind values
+    period_key VARCHAR(255) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body_text TEXT NOT NULL DEFAULT '',
+    body_html TEXT NOT NULL DEFAULT '',
+    task_id VARCHAR(255), -- Worker task a waiting digest waits for
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('waiting', 'pending', 'sent', 'failed', 'skipped')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT email_notifications_once UNIQUE (user_id, kind, period_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_notifications_due ON email_notifications(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_email_notifications_task ON email_notifications(task_id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_email_notifications_user_created ON email_notifications(user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_email_notifications_user_created;
+DROP INDEX IF EXISTS idx_email_notifications_task;
+DROP INDEX IF EXISTS idx_email_notifications_due;
+DROP TABLE IF EXISTS email_notifications;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Answers of receivers are no longer kept: a webhook pointed at an internal
+-- service would otherwise let its owner read that service's responses. The
+-- status code and error of an attempt are enough to debug a receiver.
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS response_body;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS response_body TEXT;
+
+-- +goose StatementEnd
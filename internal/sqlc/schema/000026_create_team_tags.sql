@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Team tags are a vocabulary that team owners and managers curate for their
+-- team. Like shared tags they have no user_id and are read-only to members:
+-- using one on a log entry creates a personal copy that inherits its color and
+-- description. Shared tags are the ones with neither a user nor a team.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS team_id UUID REFERENCES teams(id) ON DELETE CASCADE;
+ALTER TABLE tags ADD CONSTRAINT tags_single_owner CHECK (user_id IS NULL OR team_id IS NULL);
+
+DROP INDEX IF EXISTS idx_tags_shared_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_shared_name ON tags(name) WHERE user_id IS NULL AND team_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_team_name ON tags(team_id, name) WHERE team_id IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM tags WHERE team_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_tags_team_name;
+DROP INDEX IF EXISTS idx_tags_shared_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_shared_name ON tags(name) WHERE user_id IS NULL;
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_single_owner;
+ALTER TABLE tags DROP COLUMN IF EXISTS team_id;
+
+-- +goose StatementEnd
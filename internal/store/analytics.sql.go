@@ -77,11 +77,11 @@ const getComparisonStats = `-- name: GetComparisonStats :one
 SELECT
     -- Current period
     COUNT(CASE WHEN start_time >= $2 AND start_time <= $3 THEN 1 END) as current_entries,
-    SUM(CASE WHEN start_time >= $2 AND start_time <= $3 THEN duration_minutes ELSE 0 END) as current_minutes,
+    COALESCE(SUM(CASE WHEN start_time >= $2 AND start_time <= $3 THEN duration_minutes ELSE 0 END), 0)::bigint as current_minutes,
 
     -- Previous period
     COUNT(CASE WHEN start_time >= $4 AND start_time <= $5 THEN 1 END) as previous_entries,
-    SUM(CASE WHEN start_time >= $4 AND start_time <= $5 THEN duration_minutes ELSE 0 END) as previous_minutes
+    COALESCE(SUM(CASE WHEN start_time >= $4 AND start_time <= $5 THEN duration_minutes ELSE 0 END), 0)::bigint as previous_minutes
 FROM log_entries
 WHERE user_id = $1
 `
@@ -120,38 +120,104 @@ func (q *Queries) GetComparisonStats(ctx context.Context, arg GetComparisonStats
 }
 
 const getDailyActivityPattern = `-- name: GetDailyActivityPattern :many
-SELECT user_id, activity_date, day_of_week, hour_of_day, entry_count, total_minutes, avg_duration, activity_types, avg_value_score FROM daily_activity_patterns
-WHERE user_id = $1
-  AND activity_date >= $2
-  AND activity_date <= $3
+SELECT
+    (start_time AT TIME ZONE $1::text)::date AS activity_date,
+    EXTRACT(DOW FROM start_time AT TIME ZONE $1::text)::int AS day_of_week,
+    EXTRACT(HOUR FROM start_time AT TIME ZONE $1::text)::int AS hour_of_day,
+    COUNT(*) AS entry_count,
+    SUM(duration_minutes) AS total_minutes
+FROM log_entries
+WHERE user_id = $2
+  AND start_time >= $3
+  AND start_time <= $4
+GROUP BY activity_date, day_of_week, hour_of_day
 ORDER BY activity_date DESC, hour_of_day ASC
 `
 
 type GetDailyActivityPatternParams struct {
-	UserID         uuid.UUID   `db:"user_id" json:"user_id"`
-	ActivityDate   pgtype.Date `db:"activity_date" json:"activity_date"`
-	ActivityDate_2 pgtype.Date `db:"activity_date_2" json:"activity_date_2"`
+	Tz        string             `db:"tz" json:"tz"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
-func (q *Queries) GetDailyActivityPattern(ctx context.Context, arg GetDailyActivityPatternParams) ([]DailyActivityPattern, error) {
-	rows, err := q.db.Query(ctx, getDailyActivityPattern, arg.UserID, arg.ActivityDate, arg.ActivityDate_2)
+type GetDailyActivityPatternRow struct {
+	ActivityDate pgtype.Date `db:"activity_date" json:"activity_date"`
+	DayOfWeek    int32       `db:"day_of_week" json:"day_of_week"`
+	HourOfDay    int32       `db:"hour_of_day" json:"hour_of_day"`
+	EntryCount   int64       `db:"entry_count" json:"entry_count"`
+	TotalMinutes int64       `db:"total_minutes" json:"total_minutes"`
+}
+
+// The daily_activity_patterns view buckets in server time, so log entries are aggregated directly
+func (q *Queries) GetDailyActivityPattern(ctx context.Context, arg GetDailyActivityPatternParams) ([]GetDailyActivityPatternRow, error) {
+	rows, err := q.db.Query(ctx, getDailyActivityPattern,
+		arg.Tz,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []DailyActivityPattern{}
+	items := []GetDailyActivityPatternRow{}
 	for rows.Next() {
-		var i DailyActivityPattern
+		var i GetDailyActivityPatternRow
 		if err := rows.Scan(
-			&i.UserID,
 			&i.ActivityDate,
 			&i.DayOfWeek,
 			&i.HourOfDay,
 			&i.EntryCount,
 			&i.TotalMinutes,
-			&i.AvgDuration,
-			&i.ActivityTypes,
-			&i.AvgValueScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFocusEntries = `-- name: GetFocusEntries :many
+SELECT start_time, end_time, type, project_id
+FROM log_entries
+WHERE user_id = $1
+  AND start_time >= $2
+  AND start_time <= $3
+ORDER BY start_time ASC, end_time ASC
+`
+
+type GetFocusEntriesParams struct {
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
+}
+
+type GetFocusEntriesRow struct {
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
+	Type      string             `db:"type" json:"type"`
+	ProjectID pgtype.UUID        `db:"project_id" json:"project_id"`
+}
+
+// Entries in start order; focus metrics are derived from the switches and gaps between them
+func (q *Queries) GetFocusEntries(ctx context.Context, arg GetFocusEntriesParams) ([]GetFocusEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getFocusEntries, arg.UserID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFocusEntriesRow{}
+	for rows.Next() {
+		var i GetFocusEntriesRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.Type,
+			&i.ProjectID,
 		); err != nil {
 			return nil, err
 		}
@@ -223,37 +289,43 @@ func (q *Queries) GetImpactLevelDistribution(ctx context.Context, arg GetImpactL
 
 const getMonthlyActivitySummary = `-- name: GetMonthlyActivitySummary :many
 SELECT
-    DATE_TRUNC('month', start_time) as month_start,
+    DATE_TRUNC('month', start_time AT TIME ZONE $1::text)::date as month_start,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration,
     COUNT(DISTINCT project_id) as projects_count,
-    COUNT(DISTINCT DATE(start_time)) as active_days
+    COUNT(DISTINCT (start_time AT TIME ZONE $1::text)::date) as active_days
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY DATE_TRUNC('month', start_time)
+WHERE user_id = $2
+  AND start_time >= $3
+  AND start_time <= $4
+GROUP BY month_start
 ORDER BY month_start DESC
 `
 
 type GetMonthlyActivitySummaryParams struct {
-	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
-	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
-	StartTime_2 pgtype.Timestamptz `db:"start_time_2" json:"start_time_2"`
+	Tz        string             `db:"tz" json:"tz"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
 type GetMonthlyActivitySummaryRow struct {
-	MonthStart    pgtype.Interval `db:"month_start" json:"month_start"`
-	EntryCount    int64           `db:"entry_count" json:"entry_count"`
-	TotalMinutes  int64           `db:"total_minutes" json:"total_minutes"`
-	AvgDuration   float64         `db:"avg_duration" json:"avg_duration"`
-	ProjectsCount int64           `db:"projects_count" json:"projects_count"`
-	ActiveDays    int64           `db:"active_days" json:"active_days"`
+	MonthStart    pgtype.Date `db:"month_start" json:"month_start"`
+	EntryCount    int64       `db:"entry_count" json:"entry_count"`
+	TotalMinutes  int64       `db:"total_minutes" json:"total_minutes"`
+	AvgDuration   float64     `db:"avg_duration" json:"avg_duration"`
+	ProjectsCount int64       `db:"projects_count" json:"projects_count"`
+	ActiveDays    int64       `db:"active_days" json:"active_days"`
 }
 
 func (q *Queries) GetMonthlyActivitySummary(ctx context.Context, arg GetMonthlyActivitySummaryParams) ([]GetMonthlyActivitySummaryRow, error) {
-	rows, err := q.db.Query(ctx, getMonthlyActivitySummary, arg.UserID, arg.StartTime, arg.StartTime_2)
+	rows, err := q.db.Query(ctx, getMonthlyActivitySummary,
+		arg.Tz,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -281,35 +353,39 @@ func (q *Queries) GetMonthlyActivitySummary(ctx context.Context, arg GetMonthlyA
 
 const getProductivityByDayOfWeek = `-- name: GetProductivityByDayOfWeek :many
 SELECT
-    EXTRACT(DOW FROM start_time) as day_of_week,
-    TO_CHAR(start_time, 'Day') as day_name,
+    EXTRACT(DOW FROM start_time AT TIME ZONE $1::text)::int as day_of_week,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY EXTRACT(DOW FROM start_time), TO_CHAR(start_time, 'Day')
+WHERE user_id = $2
+  AND start_time >= $3
+  AND start_time <= $4
+GROUP BY day_of_week
 ORDER BY day_of_week
 `
 
 type GetProductivityByDayOfWeekParams struct {
-	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
-	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
-	StartTime_2 pgtype.Timestamptz `db:"start_time_2" json:"start_time_2"`
+	Tz        string             `db:"tz" json:"tz"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
 type GetProductivityByDayOfWeekRow struct {
-	DayOfWeek    pgtype.Numeric `db:"day_of_week" json:"day_of_week"`
-	DayName      string         `db:"day_name" json:"day_name"`
-	EntryCount   int64          `db:"entry_count" json:"entry_count"`
-	TotalMinutes int64          `db:"total_minutes" json:"total_minutes"`
-	AvgDuration  float64        `db:"avg_duration" json:"avg_duration"`
+	DayOfWeek    int32   `db:"day_of_week" json:"day_of_week"`
+	EntryCount   int64   `db:"entry_count" json:"entry_count"`
+	TotalMinutes int64   `db:"total_minutes" json:"total_minutes"`
+	AvgDuration  float64 `db:"avg_duration" json:"avg_duration"`
 }
 
 func (q *Queries) GetProductivityByDayOfWeek(ctx context.Context, arg GetProductivityByDayOfWeekParams) ([]GetProductivityByDayOfWeekRow, error) {
-	rows, err := q.db.Query(ctx, getProductivityByDayOfWeek, arg.UserID, arg.StartTime, arg.StartTime_2)
+	rows, err := q.db.Query(ctx, getProductivityByDayOfWeek,
+		arg.Tz,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -319,7 +395,6 @@ func (q *Queries) GetProductivityByDayOfWeek(ctx context.Context, arg GetProduct
 		var i GetProductivityByDayOfWeekRow
 		if err := rows.Scan(
 			&i.DayOfWeek,
-			&i.DayName,
 			&i.EntryCount,
 			&i.TotalMinutes,
 			&i.AvgDuration,
@@ -336,33 +411,39 @@ func (q *Queries) GetProductivityByDayOfWeek(ctx context.Context, arg GetProduct
 
 const getProductivityByHour = `-- name: GetProductivityByHour :many
 SELECT
-    EXTRACT(HOUR FROM start_time) as hour_of_day,
+    EXTRACT(HOUR FROM start_time AT TIME ZONE $1::text)::int as hour_of_day,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY EXTRACT(HOUR FROM start_time)
+WHERE user_id = $2
+  AND start_time >= $3
+  AND start_time <= $4
+GROUP BY hour_of_day
 ORDER BY hour_of_day
 `
 
 type GetProductivityByHourParams struct {
-	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
-	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
-	StartTime_2 pgtype.Timestamptz `db:"start_time_2" json:"start_time_2"`
+	Tz        string             `db:"tz" json:"tz"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
 type GetProductivityByHourRow struct {
-	HourOfDay    pgtype.Numeric `db:"hour_of_day" json:"hour_of_day"`
-	EntryCount   int64          `db:"entry_count" json:"entry_count"`
-	TotalMinutes int64          `db:"total_minutes" json:"total_minutes"`
-	AvgDuration  float64        `db:"avg_duration" json:"avg_duration"`
+	HourOfDay    int32   `db:"hour_of_day" json:"hour_of_day"`
+	EntryCount   int64   `db:"entry_count" json:"entry_count"`
+	TotalMinutes int64   `db:"total_minutes" json:"total_minutes"`
+	AvgDuration  float64 `db:"avg_duration" json:"avg_duration"`
 }
 
 func (q *Queries) GetProductivityByHour(ctx context.Context, arg GetProductivityByHourParams) ([]GetProductivityByHourRow, error) {
-	rows, err := q.db.Query(ctx, getProductivityByHour, arg.UserID, arg.StartTime, arg.StartTime_2)
+	rows, err := q.db.Query(ctx, getProductivityByHour,
+		arg.Tz,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -386,37 +467,201 @@ func (q *Queries) GetProductivityByHour(ctx context.Context, arg GetProductivity
 	return items, nil
 }
 
+const getProjectDailyMinutes = `-- name: GetProjectDailyMinutes :many
+SELECT
+    (start_time AT TIME ZONE $1::text)::date AS activity_date,
+    type,
+    SUM(duration_minutes)::int AS total_minutes
+FROM log_entries
+WHERE project_id = ANY($2::uuid[])
+GROUP BY 1, 2
+ORDER BY 1, 2
+`
+
+type GetProjectDailyMinutesParams struct {
+	Tz         string      `db:"tz" json:"tz"`
+	ProjectIds []uuid.UUID `db:"project_ids" json:"project_ids"`
+}
+
+type GetProjectDailyMinutesRow struct {
+	ActivityDate pgtype.Date `db:"activity_date" json:"activity_date"`
+	Type         string      `db:"type" json:"type"`
+	TotalMinutes int32       `db:"total_minutes" json:"total_minutes"`
+}
+
+// Minutes logged on a set of projects (a project and its sub-projects) per day
+// and activity type, for budget burn-down
+func (q *Queries) GetProjectDailyMinutes(ctx context.Context, arg GetProjectDailyMinutesParams) ([]GetProjectDailyMinutesRow, error) {
+	rows, err := q.db.Query(ctx, getProjectDailyMinutes, arg.Tz, arg.ProjectIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetProjectDailyMinutesRow{}
+	for rows.Next() {
+		var i GetProjectDailyMinutesRow
+		if err := rows.Scan(
+			&i.ActivityDate,
+			&i.Type,
+			&i.TotalMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProjectPerformanceMetrics = `-- name: GetProjectPerformanceMetrics :many
-SELECT project_id, project_name, project_status, project_owner, total_entries, total_minutes, avg_entry_duration, contributors_count, active_days, first_activity, last_activity, avg_value_score, most_common_activity, recent_entries_30d, project_created_at FROM project_performance_metrics
+SELECT
+    project_id,
+    project_name,
+    total_entries,
+    COALESCE(total_minutes, 0)::bigint AS total_minutes,
+    contributors_count,
+    active_days,
+    first_activity::timestamptz AS first_activity,
+    last_activity::timestamptz AS last_activity,
+    recent_entries_30d
+FROM project_performance_metrics
 WHERE project_owner = $1
 ORDER BY total_minutes DESC
 `
 
-func (q *Queries) GetProjectPerformanceMetrics(ctx context.Context, projectOwner uuid.UUID) ([]ProjectPerformanceMetric, error) {
+type GetProjectPerformanceMetricsRow struct {
+	ProjectID         uuid.UUID          `db:"project_id" json:"project_id"`
+	ProjectName       string             `db:"project_name" json:"project_name"`
+	TotalEntries      int64              `db:"total_entries" json:"total_entries"`
+	TotalMinutes      int64              `db:"total_minutes" json:"total_minutes"`
+	ContributorsCount int64              `db:"contributors_count" json:"contributors_count"`
+	ActiveDays        int64              `db:"active_days" json:"active_days"`
+	FirstActivity     pgtype.Timestamptz `db:"first_activity" json:"first_activity"`
+	LastActivity      pgtype.Timestamptz `db:"last_activity" json:"last_activity"`
+	RecentEntries30d  int64              `db:"recent_entries_30d" json:"recent_entries_30d"`
+}
+
+func (q *Queries) GetProjectPerformanceMetrics(ctx context.Context, projectOwner uuid.UUID) ([]GetProjectPerformanceMetricsRow, error) {
 	rows, err := q.db.Query(ctx, getProjectPerformanceMetrics, projectOwner)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ProjectPerformanceMetric{}
+	items := []GetProjectPerformanceMetricsRow{}
 	for rows.Next() {
-		var i ProjectPerformanceMetric
+		var i GetProjectPerformanceMetricsRow
 		if err := rows.Scan(
 			&i.ProjectID,
 			&i.ProjectName,
-			&i.ProjectStatus,
-			&i.ProjectOwner,
 			&i.TotalEntries,
 			&i.TotalMinutes,
-			&i.AvgEntryDuration,
 			&i.ContributorsCount,
 			&i.ActiveDays,
 			&i.FirstActivity,
 			&i.LastActivity,
-			&i.AvgValueScore,
-			&i.MostCommonActivity,
 			&i.RecentEntries30d,
-			&i.ProjectCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectTotals = `-- name: GetProjectTotals :many
+SELECT
+    project_id,
+    COUNT(*)::int AS entry_count,
+    SUM(duration_minutes)::int AS total_minutes
+FROM log_entries
+WHERE project_id = ANY($1::uuid[])
+GROUP BY project_id
+`
+
+type GetProjectTotalsRow struct {
+	ProjectID    pgtype.UUID `db:"project_id" json:"project_id"`
+	EntryCount   int32       `db:"entry_count" json:"entry_count"`
+	TotalMinutes int32       `db:"total_minutes" json:"total_minutes"`
+}
+
+// All-time entries and minutes per project, for sub-project rollups
+func (q *Queries) GetProjectTotals(ctx context.Context, projectIds []uuid.UUID) ([]GetProjectTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getProjectTotals, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetProjectTotalsRow{}
+	for rows.Next() {
+		var i GetProjectTotalsRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.EntryCount,
+			&i.TotalMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamActivity = `-- name: GetTeamActivity :many
+SELECT
+    le.user_id,
+    le.project_id,
+    le.type,
+    COUNT(*)::int AS entry_count,
+    SUM(le.duration_minutes)::int AS total_minutes
+FROM log_entries le
+JOIN team_projects tp ON tp.project_id = le.project_id
+JOIN team_members tm ON tm.team_id = tp.team_id AND tm.user_id = le.user_id
+WHERE tp.team_id = $1
+    AND le.start_time >= $2
+    AND le.start_time <= $3
+GROUP BY le.user_id, le.project_id, le.type
+ORDER BY le.user_id, le.project_id, le.type
+`
+
+type GetTeamActivityParams struct {
+	TeamID    uuid.UUID          `db:"team_id" json:"team_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
+}
+
+type GetTeamActivityRow struct {
+	UserID       uuid.UUID   `db:"user_id" json:"user_id"`
+	ProjectID    pgtype.UUID `db:"project_id" json:"project_id"`
+	Type         string      `db:"type" json:"type"`
+	EntryCount   int32       `db:"entry_count" json:"entry_count"`
+	TotalMinutes int32       `db:"total_minutes" json:"total_minutes"`
+}
+
+// Entries and minutes per member, project and activity type on the projects shared
+// with a team. Only current members count; raw entries never leave this query.
+func (q *Queries) GetTeamActivity(ctx context.Context, arg GetTeamActivityParams) ([]GetTeamActivityRow, error) {
+	rows, err := q.db.Query(ctx, getTeamActivity, arg.TeamID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTeamActivityRow{}
+	for rows.Next() {
+		var i GetTeamActivityRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ProjectID,
+			&i.Type,
+			&i.EntryCount,
+			&i.TotalMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -535,27 +780,72 @@ func (q *Queries) GetUserActivitySummaryView(ctx context.Context, userID uuid.UU
 }
 
 const getUserProductivityTrend = `-- name: GetUserProductivityTrend :many
-SELECT get_user_productivity_trend FROM get_user_productivity_trend($1, $2)
+SELECT
+    (start_time AT TIME ZONE $1::text)::date AS activity_date,
+    SUM(duration_minutes)::int AS total_minutes,
+    COUNT(*)::int AS entry_count,
+    ROUND(AVG(CASE
+        WHEN value_rating = 'critical' THEN 4
+        WHEN value_rating = 'high' THEN 3
+        WHEN value_rating = 'medium' THEN 2
+        WHEN value_rating = 'low' THEN 1
+        ELSE 0
+    END), 2)::float8 AS avg_value_score,
+    ROUND((SUM(duration_minutes) * AVG(CASE
+        WHEN value_rating = 'critical' THEN 4
+        WHEN value_rating = 'high' THEN 3
+        WHEN value_rating = 'medium' THEN 2
+        WHEN value_rating = 'low' THEN 1
+        ELSE 0
+    END)) / 100.0, 2)::float8 AS productivity_score
+FROM log_entries
+WHERE user_id = $2
+  AND start_time >= $3
+  AND start_time <= $4
+GROUP BY activity_date
+ORDER BY activity_date ASC
 `
 
 type GetUserProductivityTrendParams struct {
-	UserUuid uuid.UUID `db:"user_uuid" json:"user_uuid"`
-	DaysBack int32     `db:"days_back" json:"days_back"`
+	Tz        string             `db:"tz" json:"tz"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
-func (q *Queries) GetUserProductivityTrend(ctx context.Context, arg GetUserProductivityTrendParams) ([]interface{}, error) {
-	rows, err := q.db.Query(ctx, getUserProductivityTrend, arg.UserUuid, arg.DaysBack)
+type GetUserProductivityTrendRow struct {
+	ActivityDate      pgtype.Date `db:"activity_date" json:"activity_date"`
+	TotalMinutes      int32       `db:"total_minutes" json:"total_minutes"`
+	EntryCount        int32       `db:"entry_count" json:"entry_count"`
+	AvgValueScore     float64     `db:"avg_value_score" json:"avg_value_score"`
+	ProductivityScore float64     `db:"productivity_score" json:"productivity_score"`
+}
+
+// Same scores as get_user_productivity_trend, grouped by day in the given timezone
+func (q *Queries) GetUserProductivityTrend(ctx context.Context, arg GetUserProductivityTrendParams) ([]GetUserProductivityTrendRow, error) {
+	rows, err := q.db.Query(ctx, getUserProductivityTrend,
+		arg.Tz,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []interface{}{}
+	items := []GetUserProductivityTrendRow{}
 	for rows.Next() {
-		var get_user_productivity_trend interface{}
-		if err := rows.Scan(&get_user_productivity_trend); err != nil {
+		var i GetUserProductivityTrendRow
+		if err := rows.Scan(
+			&i.ActivityDate,
+			&i.TotalMinutes,
+			&i.EntryCount,
+			&i.AvgValueScore,
+			&i.ProductivityScore,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, get_user_productivity_trend)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -623,37 +913,46 @@ func (q *Queries) GetValueRatingDistribution(ctx context.Context, arg GetValueRa
 
 const getWeeklyActivitySummary = `-- name: GetWeeklyActivitySummary :many
 SELECT
-    DATE_TRUNC('week', start_time) as week_start,
+    (DATE_TRUNC('week', (start_time AT TIME ZONE $1::text) + make_interval(days => $2::int))
+        - make_interval(days => $2::int))::date as week_start,
     COUNT(*) as entry_count,
     SUM(duration_minutes) as total_minutes,
     AVG(duration_minutes) as avg_duration,
     COUNT(DISTINCT project_id) as projects_count,
-    COUNT(DISTINCT DATE(start_time)) as active_days
+    COUNT(DISTINCT (start_time AT TIME ZONE $1::text)::date) as active_days
 FROM log_entries
-WHERE user_id = $1
-  AND start_time >= $2
-  AND start_time <= $3
-GROUP BY DATE_TRUNC('week', start_time)
+WHERE user_id = $3
+  AND start_time >= $4
+  AND start_time <= $5
+GROUP BY week_start
 ORDER BY week_start DESC
 `
 
 type GetWeeklyActivitySummaryParams struct {
-	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
-	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
-	StartTime_2 pgtype.Timestamptz `db:"start_time_2" json:"start_time_2"`
+	Tz         string             `db:"tz" json:"tz"`
+	WeekOffset int32              `db:"week_offset" json:"week_offset"`
+	UserID     uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime  pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime    pgtype.Timestamptz `db:"end_time" json:"end_time"`
 }
 
 type GetWeeklyActivitySummaryRow struct {
-	WeekStart     pgtype.Interval `db:"week_start" json:"week_start"`
-	EntryCount    int64           `db:"entry_count" json:"entry_count"`
-	TotalMinutes  int64           `db:"total_minutes" json:"total_minutes"`
-	AvgDuration   float64         `db:"avg_duration" json:"avg_duration"`
-	ProjectsCount int64           `db:"projects_count" json:"projects_count"`
-	ActiveDays    int64           `db:"active_days" json:"active_days"`
+	WeekStart     pgtype.Date `db:"week_start" json:"week_start"`
+	EntryCount    int64       `db:"entry_count" json:"entry_count"`
+	TotalMinutes  int64       `db:"total_minutes" json:"total_minutes"`
+	AvgDuration   float64     `db:"avg_duration" json:"avg_duration"`
+	ProjectsCount int64       `db:"projects_count" json:"projects_count"`
+	ActiveDays    int64       `db:"active_days" json:"active_days"`
 }
 
 func (q *Queries) GetWeeklyActivitySummary(ctx context.Context, arg GetWeeklyActivitySummaryParams) ([]GetWeeklyActivitySummaryRow, error) {
-	rows, err := q.db.Query(ctx, getWeeklyActivitySummary, arg.UserID, arg.StartTime, arg.StartTime_2)
+	rows, err := q.db.Query(ctx, getWeeklyActivitySummary,
+		arg.Tz,
+		arg.WeekOffset,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -680,9 +979,10 @@ func (q *Queries) GetWeeklyActivitySummary(ctx context.Context, arg GetWeeklyAct
 }
 
 const refreshUserActivitySummary = `-- name: RefreshUserActivitySummary :exec
-REFRESH MATERIALIZED VIEW user_activity_summary
+REFRESH MATERIALIZED VIEW CONCURRENTLY user_activity_summary
 `
 
+// CONCURRENTLY keeps the view readable during the refresh; it relies on idx_user_activity_summary_user
 func (q *Queries) RefreshUserActivitySummary(ctx context.Context) error {
 	_, err := q.db.Exec(ctx, refreshUserActivitySummary)
 	return err
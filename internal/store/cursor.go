@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Server-side cursors for results too large to collect in memory. sqlc only
+// generates queries that return every row at once, so these helpers reuse the
+// generated SQL and row types and hand the rows over in batches instead. They
+// must run inside a transaction, which owns the cursor.
+
+// cursorBatchSize is the number of rows fetched from a cursor per round trip
+const cursorBatchSize = 500
+
+// StreamExportAccountLogEntries calls f for every row of ExportAccountLogEntries
+func (q *Queries) StreamExportAccountLogEntries(ctx context.Context, userID uuid.UUID, f func(ExportAccountLogEntriesRow) error) error {
+	return streamCursor(ctx, q.db, "export_account_log_entries", exportAccountLogEntries, f, userID)
+}
+
+// StreamExportLogEntries calls f for every row of ExportLogEntries
+func (q *Queries) StreamExportLogEntries(ctx context.Context, arg ExportLogEntriesParams, f func(ExportLogEntriesRow) error) error {
+	return streamCursor(ctx, q.db, "export_log_entries", exportLogEntries, f,
+		arg.UserID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.ProjectID,
+	)
+}
+
+// StreamExportProjects calls f for every row of ExportProjects
+func (q *Queries) StreamExportProjects(ctx context.Context, createdBy uuid.UUID, f func(ExportProjectsRow) error) error {
+	return streamCursor(ctx, q.db, "export_projects", exportProjects, f, createdBy)
+}
+
+// StreamExportTagUsage calls f for every row of ExportTagUsage
+func (q *Queries) StreamExportTagUsage(ctx context.Context, userID uuid.UUID, f func(ExportTagUsageRow) error) error {
+	return streamCursor(ctx, q.db, "export_tag_usage", exportTagUsage, f, userID)
+}
+
+// streamCursor declares a cursor for query and passes its rows to f, one batch at a time
+func streamCursor[T any](ctx context.Context, db DBTX, name, query string, f func(T) error, args ...any) error {
+	if _, err := db.Exec(ctx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("declare cursor %s: %w", name, err)
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM %s", cursorBatchSize, name)
+	for {
+		rows, err := db.Query(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("fetch from cursor %s: %w", name, err)
+		}
+		items, err := pgx.CollectRows(rows, pgx.RowToStructByPos[T])
+		if err != nil {
+			return fmt.Errorf("fetch from cursor %s: %w", name, err)
+		}
+
+		for _, item := range items {
+			if err := f(item); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < cursorBatchSize {
+			break
+		}
+	}
+
+	if _, err := db.Exec(ctx, "CLOSE "+name); err != nil {
+		return fmt.Errorf("close cursor %s: %w", name, err)
+	}
+	return nil
+}
@@ -512,6 +512,35 @@ func (q *Queries) GetLogEntryByID(ctx context.Context, id uuid.UUID) (LogEntry,
 	return i, err
 }
 
+const getLogEntryTotals = `-- name: GetLogEntryTotals :one
+SELECT
+    COUNT(*) as total_entries,
+    COALESCE(SUM(duration_minutes), 0)::bigint as total_minutes
+FROM log_entries
+WHERE user_id = $1
+  AND start_time >= $2
+  AND start_time < $3
+`
+
+type GetLogEntryTotalsParams struct {
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	StartTime pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime   pgtype.Timestamptz `db:"end_time" json:"end_time"`
+}
+
+type GetLogEntryTotalsRow struct {
+	TotalEntries int64 `db:"total_entries" json:"total_entries"`
+	TotalMinutes int64 `db:"total_minutes" json:"total_minutes"`
+}
+
+// Entries and minutes a user logged with a start time in [start_time, end_time)
+func (q *Queries) GetLogEntryTotals(ctx context.Context, arg GetLogEntryTotalsParams) (GetLogEntryTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getLogEntryTotals, arg.UserID, arg.StartTime, arg.EndTime)
+	var i GetLogEntryTotalsRow
+	err := row.Scan(&i.TotalEntries, &i.TotalMinutes)
+	return i, err
+}
+
 const getRecentLogEntries = `-- name: GetRecentLogEntries :many
 SELECT le.id, le.user_id, le.project_id, le.title, le.description, le.type, le.start_time, le.end_time, le.duration_minutes, le.value_rating, le.impact_level, le.created_at, le.updated_at, p.name as project_name, p.color as project_color
 FROM log_entries le
@@ -724,3 +753,23 @@ func (q *Queries) UpdateLogEntry(ctx context.Context, arg UpdateLogEntryParams)
 	)
 	return i, err
 }
+
+const updateLogEntryProject = `-- name: UpdateLogEntryProject :execrows
+UPDATE log_entries
+SET project_id = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+`
+
+type UpdateLogEntryProjectParams struct {
+	ID        uuid.UUID   `db:"id" json:"id"`
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+	ProjectID pgtype.UUID `db:"project_id" json:"project_id"`
+}
+
+func (q *Queries) UpdateLogEntryProject(ctx context.Context, arg UpdateLogEntryProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateLogEntryProject, arg.ID, arg.UserID, arg.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,6 +11,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CalendarFeedToken struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	UserID     uuid.UUID          `db:"user_id" json:"user_id"`
+	Name       string             `db:"name" json:"name"`
+	TokenHash  string             `db:"token_hash" json:"token_hash"`
+	LastUsedAt pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type CsvImportProfile struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	Name      string             `db:"name" json:"name"`
+	Mapping   []byte             `db:"mapping" json:"mapping"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type DailyActivityPattern struct {
 	UserID        uuid.UUID      `db:"user_id" json:"user_id"`
 	ActivityDate  pgtype.Date    `db:"activity_date" json:"activity_date"`
@@ -23,6 +41,24 @@ type DailyActivityPattern struct {
 	AvgValueScore float64        `db:"avg_value_score" json:"avg_value_score"`
 }
 
+type EmailNotification struct {
+	ID            uuid.UUID          `db:"id" json:"id"`
+	UserID        uuid.UUID          `db:"user_id" json:"user_id"`
+	Kind          string             `db:"kind" json:"kind"`
+	PeriodKey     string             `db:"period_key" json:"period_key"`
+	Recipient     string             `db:"recipient" json:"recipient"`
+	Subject       string             `db:"subject" json:"subject"`
+	BodyText      string             `db:"body_text" json:"body_text"`
+	BodyHtml      string             `db:"body_html" json:"body_html"`
+	TaskID        pgtype.Text        `db:"task_id" json:"task_id"`
+	Status        string             `db:"status" json:"status"`
+	Attempts      int32              `db:"attempts" json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `db:"next_attempt_at" json:"next_attempt_at"`
+	SentAt        pgtype.Timestamptz `db:"sent_at" json:"sent_at"`
+	ErrorMessage  pgtype.Text        `db:"error_message" json:"error_message"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
 type GeneratedInsight struct {
 	ID                   uuid.UUID          `db:"id" json:"id"`
 	UserID               uuid.UUID          `db:"user_id" json:"user_id"`
@@ -41,6 +77,27 @@ type GeneratedInsight struct {
 	UpdatedAt            pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type Goal struct {
+	ID            uuid.UUID          `db:"id" json:"id"`
+	UserID        uuid.UUID          `db:"user_id" json:"user_id"`
+	ParentID      pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	Title         string             `db:"title" json:"title"`
+	Description   pgtype.Text        `db:"description" json:"description"`
+	Metric        string             `db:"metric" json:"metric"`
+	Comparison    string             `db:"comparison" json:"comparison"`
+	TargetValue   float64            `db:"target_value" json:"target_value"`
+	Period        string             `db:"period" json:"period"`
+	StartDate     pgtype.Date        `db:"start_date" json:"start_date"`
+	EndDate       pgtype.Date        `db:"end_date" json:"end_date"`
+	ActivityTypes []string           `db:"activity_types" json:"activity_types"`
+	Tags          []string           `db:"tags" json:"tags"`
+	ProjectID     pgtype.UUID        `db:"project_id" json:"project_id"`
+	ValueRatings  []string           `db:"value_ratings" json:"value_ratings"`
+	Status        string             `db:"status" json:"status"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type LogEntry struct {
 	ID              uuid.UUID          `db:"id" json:"id"`
 	UserID          uuid.UUID          `db:"user_id" json:"user_id"`
@@ -57,24 +114,123 @@ type LogEntry struct {
 	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
-type LogEntryTag struct {
+type LogEntryAttachment struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	LogEntryID  uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
+	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
+	FileName    string             `db:"file_name" json:"file_name"`
+	ContentType string             `db:"content_type" json:"content_type"`
+	SizeBytes   int64              `db:"size_bytes" json:"size_bytes"`
+	StorageKey  string             `db:"storage_key" json:"storage_key"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type LogEntryImport struct {
+	UserID     uuid.UUID          `db:"user_id" json:"user_id"`
+	Source     string             `db:"source" json:"source"`
+	ExternalID string             `db:"external_id" json:"external_id"`
 	LogEntryID uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
-	TagID      uuid.UUID          `db:"tag_id" json:"tag_id"`
+	ImportedAt pgtype.Timestamptz `db:"imported_at" json:"imported_at"`
+}
+
+type LogEntryLink struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	LogEntryID uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
+	Kind       string             `db:"kind" json:"kind"`
+	Url        string             `db:"url" json:"url"`
+	Title      pgtype.Text        `db:"title" json:"title"`
+	Position   int32              `db:"position" json:"position"`
 	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
 
-type Project struct {
+type LogEntryRevision struct {
 	ID          uuid.UUID          `db:"id" json:"id"`
-	Name        string             `db:"name" json:"name"`
+	LogEntryID  uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
+	UserID      uuid.UUID          `db:"user_id" json:"user_id"`
+	Revision    int32              `db:"revision" json:"revision"`
+	Action      string             `db:"action" json:"action"`
+	ProjectID   pgtype.UUID        `db:"project_id" json:"project_id"`
+	Title       string             `db:"title" json:"title"`
 	Description pgtype.Text        `db:"description" json:"description"`
-	Color       pgtype.Text        `db:"color" json:"color"`
-	Status      pgtype.Text        `db:"status" json:"status"`
-	StartDate   pgtype.Date        `db:"start_date" json:"start_date"`
-	EndDate     pgtype.Date        `db:"end_date" json:"end_date"`
-	CreatedBy   uuid.UUID          `db:"created_by" json:"created_by"`
-	IsDefault   pgtype.Bool        `db:"is_default" json:"is_default"`
+	Type        string             `db:"type" json:"type"`
+	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime     pgtype.Timestamptz `db:"end_time" json:"end_time"`
+	ValueRating string             `db:"value_rating" json:"value_rating"`
+	ImpactLevel string             `db:"impact_level" json:"impact_level"`
+	Tags        []string           `db:"tags" json:"tags"`
 	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	Links       []byte             `db:"links" json:"links"`
+}
+
+type LogEntryTag struct {
+	LogEntryID uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
+	TagID      uuid.UUID          `db:"tag_id" json:"tag_id"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type LogEntryTrash struct {
+	LogEntryID uuid.UUID          `db:"log_entry_id" json:"log_entry_id"`
+	UserID     uuid.UUID          `db:"user_id" json:"user_id"`
+	DeletedAt  pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+}
+
+type LogTemplate struct {
+	ID                uuid.UUID          `db:"id" json:"id"`
+	UserID            uuid.UUID          `db:"user_id" json:"user_id"`
+	ProjectID         pgtype.UUID        `db:"project_id" json:"project_id"`
+	Title             string             `db:"title" json:"title"`
+	Description       pgtype.Text        `db:"description" json:"description"`
+	Type              string             `db:"type" json:"type"`
+	ValueRating       string             `db:"value_rating" json:"value_rating"`
+	ImpactLevel       string             `db:"impact_level" json:"impact_level"`
+	Tags              []string           `db:"tags" json:"tags"`
+	RecurrenceRule    string             `db:"recurrence_rule" json:"recurrence_rule"`
+	StartDate         pgtype.Date        `db:"start_date" json:"start_date"`
+	EndDate           pgtype.Date        `db:"end_date" json:"end_date"`
+	StartTime         pgtype.Time        `db:"start_time" json:"start_time"`
+	DurationMinutes   int32              `db:"duration_minutes" json:"duration_minutes"`
+	Timezone          string             `db:"timezone" json:"timezone"`
+	IsActive          bool               `db:"is_active" json:"is_active"`
+	MaterializedUntil pgtype.Date        `db:"materialized_until" json:"materialized_until"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type LogTemplateOccurrence struct {
+	TemplateID     uuid.UUID          `db:"template_id" json:"template_id"`
+	OccurrenceDate pgtype.Date        `db:"occurrence_date" json:"occurrence_date"`
+	Status         string             `db:"status" json:"status"`
+	LogEntryID     pgtype.UUID        `db:"log_entry_id" json:"log_entry_id"`
+	Overrides      []byte             `db:"overrides" json:"overrides"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type Project struct {
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	Name               string             `db:"name" json:"name"`
+	Description        pgtype.Text        `db:"description" json:"description"`
+	Color              pgtype.Text        `db:"color" json:"color"`
+	Status             pgtype.Text        `db:"status" json:"status"`
+	StartDate          pgtype.Date        `db:"start_date" json:"start_date"`
+	EndDate            pgtype.Date        `db:"end_date" json:"end_date"`
+	CreatedBy          uuid.UUID          `db:"created_by" json:"created_by"`
+	IsDefault          pgtype.Bool        `db:"is_default" json:"is_default"`
+	CreatedAt          pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	EstimatedHours     pgtype.Float8      `db:"estimated_hours" json:"estimated_hours"`
+	ParentID           pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	ArchivedAt         pgtype.Timestamptz `db:"archived_at" json:"archived_at"`
+	DefaultType        pgtype.Text        `db:"default_type" json:"default_type"`
+	DefaultTags        []string           `db:"default_tags" json:"default_tags"`
+	DefaultValueRating pgtype.Text        `db:"default_value_rating" json:"default_value_rating"`
+	DefaultImpactLevel pgtype.Text        `db:"default_impact_level" json:"default_impact_level"`
+}
+
+type ProjectActivityBudget struct {
+	ProjectID      uuid.UUID `db:"project_id" json:"project_id"`
+	ActivityType   string    `db:"activity_type" json:"activity_type"`
+	EstimatedHours float64   `db:"estimated_hours" json:"estimated_hours"`
 }
 
 type ProjectPerformanceMetric struct {
@@ -121,6 +277,17 @@ type Tag struct {
 	Description pgtype.Text        `db:"description" json:"description"`
 	UsageCount  pgtype.Int4        `db:"usage_count" json:"usage_count"`
 	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UserID      pgtype.UUID        `db:"user_id" json:"user_id"`
+	ParentID    pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	TeamID      pgtype.UUID        `db:"team_id" json:"team_id"`
+}
+
+type TagAlias struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	TagID     uuid.UUID          `db:"tag_id" json:"tag_id"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	Alias     string             `db:"alias" json:"alias"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
 
 type Task struct {
@@ -142,6 +309,28 @@ type Task struct {
 	UpdatedAt            pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type Team struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	Name        string             `db:"name" json:"name"`
+	Description pgtype.Text        `db:"description" json:"description"`
+	CreatedBy   pgtype.UUID        `db:"created_by" json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type TeamMember struct {
+	TeamID   uuid.UUID          `db:"team_id" json:"team_id"`
+	UserID   uuid.UUID          `db:"user_id" json:"user_id"`
+	Role     string             `db:"role" json:"role"`
+	JoinedAt pgtype.Timestamptz `db:"joined_at" json:"joined_at"`
+}
+
+type TeamProject struct {
+	TeamID    uuid.UUID          `db:"team_id" json:"team_id"`
+	ProjectID uuid.UUID          `db:"project_id" json:"project_id"`
+	SharedAt  pgtype.Timestamptz `db:"shared_at" json:"shared_at"`
+}
+
 type User struct {
 	ID           uuid.UUID          `db:"id" json:"id"`
 	Email        string             `db:"email" json:"email"`
@@ -184,6 +373,24 @@ type UserActivitySummary struct {
 	RefreshedAt             interface{} `db:"refreshed_at" json:"refreshed_at"`
 }
 
+type UserDailyRollup struct {
+	UserID                  uuid.UUID          `db:"user_id" json:"user_id"`
+	ActivityDate            pgtype.Date        `db:"activity_date" json:"activity_date"`
+	Type                    string             `db:"type" json:"type"`
+	ProjectID               pgtype.UUID        `db:"project_id" json:"project_id"`
+	EntryCount              int32              `db:"entry_count" json:"entry_count"`
+	TotalMinutes            int32              `db:"total_minutes" json:"total_minutes"`
+	CriticalEntries         int32              `db:"critical_entries" json:"critical_entries"`
+	HighEntries             int32              `db:"high_entries" json:"high_entries"`
+	MediumEntries           int32              `db:"medium_entries" json:"medium_entries"`
+	LowEntries              int32              `db:"low_entries" json:"low_entries"`
+	CompanyImpactEntries    int32              `db:"company_impact_entries" json:"company_impact_entries"`
+	DepartmentImpactEntries int32              `db:"department_impact_entries" json:"department_impact_entries"`
+	TeamImpactEntries       int32              `db:"team_impact_entries" json:"team_impact_entries"`
+	PersonalImpactEntries   int32              `db:"personal_impact_entries" json:"personal_impact_entries"`
+	UpdatedAt               pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type UserSession struct {
 	ID               uuid.UUID          `db:"id" json:"id"`
 	UserID           uuid.UUID          `db:"user_id" json:"user_id"`
@@ -196,3 +403,33 @@ type UserSession struct {
 	IsActive         pgtype.Bool        `db:"is_active" json:"is_active"`
 	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
+
+type Webhook struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	Name      string             `db:"name" json:"name"`
+	Url       string             `db:"url" json:"url"`
+	Secret    string             `db:"secret" json:"secret"`
+	Events    []string           `db:"events" json:"events"`
+	Active    bool               `db:"active" json:"active"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID          `db:"id" json:"id"`
+	WebhookID      uuid.UUID          `db:"webhook_id" json:"webhook_id"`
+	UserID         uuid.UUID          `db:"user_id" json:"user_id"`
+	EventID        uuid.UUID          `db:"event_id" json:"event_id"`
+	EventType      string             `db:"event_type" json:"event_type"`
+	Payload        []byte             `db:"payload" json:"payload"`
+	Status         string             `db:"status" json:"status"`
+	Attempts       int32              `db:"attempts" json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `db:"last_attempt_at" json:"last_attempt_at"`
+	ResponseStatus pgtype.Int4        `db:"response_status" json:"response_status"`
+	ErrorMessage   pgtype.Text        `db:"error_message" json:"error_message"`
+	DurationMs     pgtype.Int4        `db:"duration_ms" json:"duration_ms"`
+	ReplayOf       pgtype.UUID        `db:"replay_of" json:"replay_of"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
@@ -15,21 +15,28 @@ import (
 const createProject = `-- name: CreateProject :one
 
 INSERT INTO projects (
-    name, description, color, status, start_date, end_date, created_by, is_default
+    name, description, color, status, start_date, end_date, created_by, is_default, estimated_hours,
+    parent_id, default_type, default_tags, default_value_rating, default_impact_level
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
-) RETURNING id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) RETURNING id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level
 `
 
 type CreateProjectParams struct {
-	Name        string      `db:"name" json:"name"`
-	Description pgtype.Text `db:"description" json:"description"`
-	Color       pgtype.Text `db:"color" json:"color"`
-	Status      pgtype.Text `db:"status" json:"status"`
-	StartDate   pgtype.Date `db:"start_date" json:"start_date"`
-	EndDate     pgtype.Date `db:"end_date" json:"end_date"`
-	CreatedBy   uuid.UUID   `db:"created_by" json:"created_by"`
-	IsDefault   pgtype.Bool `db:"is_default" json:"is_default"`
+	Name               string        `db:"name" json:"name"`
+	Description        pgtype.Text   `db:"description" json:"description"`
+	Color              pgtype.Text   `db:"color" json:"color"`
+	Status             pgtype.Text   `db:"status" json:"status"`
+	StartDate          pgtype.Date   `db:"start_date" json:"start_date"`
+	EndDate            pgtype.Date   `db:"end_date" json:"end_date"`
+	CreatedBy          uuid.UUID     `db:"created_by" json:"created_by"`
+	IsDefault          pgtype.Bool   `db:"is_default" json:"is_default"`
+	EstimatedHours     pgtype.Float8 `db:"estimated_hours" json:"estimated_hours"`
+	ParentID           pgtype.UUID   `db:"parent_id" json:"parent_id"`
+	DefaultType        pgtype.Text   `db:"default_type" json:"default_type"`
+	DefaultTags        []string      `db:"default_tags" json:"default_tags"`
+	DefaultValueRating pgtype.Text   `db:"default_value_rating" json:"default_value_rating"`
+	DefaultImpactLevel pgtype.Text   `db:"default_impact_level" json:"default_impact_level"`
 }
 
 // EngLog Project Management Queries
@@ -44,6 +51,12 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		arg.EndDate,
 		arg.CreatedBy,
 		arg.IsDefault,
+		arg.EstimatedHours,
+		arg.ParentID,
+		arg.DefaultType,
+		arg.DefaultTags,
+		arg.DefaultValueRating,
+		arg.DefaultImpactLevel,
 	)
 	var i Project
 	err := row.Scan(
@@ -58,10 +71,33 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.IsDefault,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EstimatedHours,
+		&i.ParentID,
+		&i.ArchivedAt,
+		&i.DefaultType,
+		&i.DefaultTags,
+		&i.DefaultValueRating,
+		&i.DefaultImpactLevel,
 	)
 	return i, err
 }
 
+const createProjectActivityBudget = `-- name: CreateProjectActivityBudget :exec
+INSERT INTO project_activity_budgets (project_id, activity_type, estimated_hours)
+VALUES ($1, $2, $3)
+`
+
+type CreateProjectActivityBudgetParams struct {
+	ProjectID      uuid.UUID `db:"project_id" json:"project_id"`
+	ActivityType   string    `db:"activity_type" json:"activity_type"`
+	EstimatedHours float64   `db:"estimated_hours" json:"estimated_hours"`
+}
+
+func (q *Queries) CreateProjectActivityBudget(ctx context.Context, arg CreateProjectActivityBudgetParams) error {
+	_, err := q.db.Exec(ctx, createProjectActivityBudget, arg.ProjectID, arg.ActivityType, arg.EstimatedHours)
+	return err
+}
+
 const deleteProject = `-- name: DeleteProject :exec
 DELETE FROM projects
 WHERE id = $1 AND created_by = $2
@@ -77,9 +113,19 @@ func (q *Queries) DeleteProject(ctx context.Context, arg DeleteProjectParams) er
 	return err
 }
 
+const deleteProjectActivityBudgets = `-- name: DeleteProjectActivityBudgets :exec
+DELETE FROM project_activity_budgets
+WHERE project_id = $1
+`
+
+func (q *Queries) DeleteProjectActivityBudgets(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectActivityBudgets, projectID)
+	return err
+}
+
 const getActiveProjectsByUser = `-- name: GetActiveProjectsByUser :many
-SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at FROM projects
-WHERE created_by = $1 AND status = 'active'
+SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level FROM projects
+WHERE created_by = $1 AND status = 'active' AND archived_at IS NULL
 ORDER BY is_default DESC, name ASC
 `
 
@@ -104,6 +150,74 @@ func (q *Queries) GetActiveProjectsByUser(ctx context.Context, createdBy uuid.UU
 			&i.IsDefault,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EstimatedHours,
+			&i.ParentID,
+			&i.ArchivedAt,
+			&i.DefaultType,
+			&i.DefaultTags,
+			&i.DefaultValueRating,
+			&i.DefaultImpactLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectActivityBudgets = `-- name: GetProjectActivityBudgets :many
+SELECT project_id, activity_type, estimated_hours FROM project_activity_budgets
+WHERE project_id = $1
+ORDER BY activity_type
+`
+
+func (q *Queries) GetProjectActivityBudgets(ctx context.Context, projectID uuid.UUID) ([]ProjectActivityBudget, error) {
+	rows, err := q.db.Query(ctx, getProjectActivityBudgets, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProjectActivityBudget{}
+	for rows.Next() {
+		var i ProjectActivityBudget
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.ActivityType,
+			&i.EstimatedHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectActivityBudgetsByUser = `-- name: GetProjectActivityBudgetsByUser :many
+SELECT pab.project_id, pab.activity_type, pab.estimated_hours FROM project_activity_budgets pab
+JOIN projects p ON p.id = pab.project_id
+WHERE p.created_by = $1
+ORDER BY pab.project_id, pab.activity_type
+`
+
+func (q *Queries) GetProjectActivityBudgetsByUser(ctx context.Context, createdBy uuid.UUID) ([]ProjectActivityBudget, error) {
+	rows, err := q.db.Query(ctx, getProjectActivityBudgetsByUser, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProjectActivityBudget{}
+	for rows.Next() {
+		var i ProjectActivityBudget
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.ActivityType,
+			&i.EstimatedHours,
 		); err != nil {
 			return nil, err
 		}
@@ -116,7 +230,7 @@ func (q *Queries) GetActiveProjectsByUser(ctx context.Context, createdBy uuid.UU
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
-SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at FROM projects
+SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level FROM projects
 WHERE id = $1
 `
 
@@ -135,6 +249,13 @@ func (q *Queries) GetProjectByID(ctx context.Context, id uuid.UUID) (Project, er
 		&i.IsDefault,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EstimatedHours,
+		&i.ParentID,
+		&i.ArchivedAt,
+		&i.DefaultType,
+		&i.DefaultTags,
+		&i.DefaultValueRating,
+		&i.DefaultImpactLevel,
 	)
 	return i, err
 }
@@ -174,14 +295,72 @@ func (q *Queries) GetProjectStats(ctx context.Context, projectID pgtype.UUID) (G
 	return i, err
 }
 
+const getProjectSubtree = `-- name: GetProjectSubtree :many
+WITH RECURSIVE subtree AS (
+    SELECT p.id, p.parent_id, p.name, p.is_default, p.archived_at, 0 AS depth
+    FROM projects p
+    WHERE p.id = $1
+    UNION ALL
+    SELECT child.id, child.parent_id, child.name, child.is_default, child.archived_at, s.depth + 1
+    FROM projects child
+    JOIN subtree s ON child.parent_id = s.id
+    WHERE s.depth < 10
+)
+SELECT id, parent_id, name, is_default, archived_at, depth::int AS depth
+FROM subtree
+ORDER BY depth, name
+`
+
+type GetProjectSubtreeRow struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	ParentID   pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	Name       string             `db:"name" json:"name"`
+	IsDefault  pgtype.Bool        `db:"is_default" json:"is_default"`
+	ArchivedAt pgtype.Timestamptz `db:"archived_at" json:"archived_at"`
+	Depth      int32              `db:"depth" json:"depth"`
+}
+
+// The project followed by every sub-project below it, with the depth below the project
+func (q *Queries) GetProjectSubtree(ctx context.Context, id uuid.UUID) ([]GetProjectSubtreeRow, error) {
+	rows, err := q.db.Query(ctx, getProjectSubtree, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetProjectSubtreeRow{}
+	for rows.Next() {
+		var i GetProjectSubtreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Name,
+			&i.IsDefault,
+			&i.ArchivedAt,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProjectsByUser = `-- name: GetProjectsByUser :many
-SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at FROM projects
-WHERE created_by = $1
+SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level FROM projects
+WHERE created_by = $1 AND ($2::boolean OR archived_at IS NULL)
 ORDER BY is_default DESC, name ASC
 `
 
-func (q *Queries) GetProjectsByUser(ctx context.Context, createdBy uuid.UUID) ([]Project, error) {
-	rows, err := q.db.Query(ctx, getProjectsByUser, createdBy)
+type GetProjectsByUserParams struct {
+	CreatedBy       uuid.UUID `db:"created_by" json:"created_by"`
+	IncludeArchived bool      `db:"include_archived" json:"include_archived"`
+}
+
+func (q *Queries) GetProjectsByUser(ctx context.Context, arg GetProjectsByUserParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, getProjectsByUser, arg.CreatedBy, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -201,6 +380,13 @@ func (q *Queries) GetProjectsByUser(ctx context.Context, createdBy uuid.UUID) ([
 			&i.IsDefault,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EstimatedHours,
+			&i.ParentID,
+			&i.ArchivedAt,
+			&i.DefaultType,
+			&i.DefaultTags,
+			&i.DefaultValueRating,
+			&i.DefaultImpactLevel,
 		); err != nil {
 			return nil, err
 		}
@@ -214,7 +400,7 @@ func (q *Queries) GetProjectsByUser(ctx context.Context, createdBy uuid.UUID) ([
 
 const getProjectsWithActivity = `-- name: GetProjectsWithActivity :many
 SELECT
-    p.id, p.name, p.description, p.color, p.status, p.start_date, p.end_date, p.created_by, p.is_default, p.created_at, p.updated_at,
+    p.id, p.name, p.description, p.color, p.status, p.start_date, p.end_date, p.created_by, p.is_default, p.created_at, p.updated_at, p.estimated_hours, p.parent_id, p.archived_at, p.default_type, p.default_tags, p.default_value_rating, p.default_impact_level,
     COUNT(le.id) as entry_count,
     SUM(le.duration_minutes) as total_minutes
 FROM projects p
@@ -225,19 +411,26 @@ ORDER BY p.is_default DESC, entry_count DESC
 `
 
 type GetProjectsWithActivityRow struct {
-	ID           uuid.UUID          `db:"id" json:"id"`
-	Name         string             `db:"name" json:"name"`
-	Description  pgtype.Text        `db:"description" json:"description"`
-	Color        pgtype.Text        `db:"color" json:"color"`
-	Status       pgtype.Text        `db:"status" json:"status"`
-	StartDate    pgtype.Date        `db:"start_date" json:"start_date"`
-	EndDate      pgtype.Date        `db:"end_date" json:"end_date"`
-	CreatedBy    uuid.UUID          `db:"created_by" json:"created_by"`
-	IsDefault    pgtype.Bool        `db:"is_default" json:"is_default"`
-	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
-	EntryCount   int64              `db:"entry_count" json:"entry_count"`
-	TotalMinutes int64              `db:"total_minutes" json:"total_minutes"`
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	Name               string             `db:"name" json:"name"`
+	Description        pgtype.Text        `db:"description" json:"description"`
+	Color              pgtype.Text        `db:"color" json:"color"`
+	Status             pgtype.Text        `db:"status" json:"status"`
+	StartDate          pgtype.Date        `db:"start_date" json:"start_date"`
+	EndDate            pgtype.Date        `db:"end_date" json:"end_date"`
+	CreatedBy          uuid.UUID          `db:"created_by" json:"created_by"`
+	IsDefault          pgtype.Bool        `db:"is_default" json:"is_default"`
+	CreatedAt          pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	EstimatedHours     pgtype.Float8      `db:"estimated_hours" json:"estimated_hours"`
+	ParentID           pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	ArchivedAt         pgtype.Timestamptz `db:"archived_at" json:"archived_at"`
+	DefaultType        pgtype.Text        `db:"default_type" json:"default_type"`
+	DefaultTags        []string           `db:"default_tags" json:"default_tags"`
+	DefaultValueRating pgtype.Text        `db:"default_value_rating" json:"default_value_rating"`
+	DefaultImpactLevel pgtype.Text        `db:"default_impact_level" json:"default_impact_level"`
+	EntryCount         int64              `db:"entry_count" json:"entry_count"`
+	TotalMinutes       int64              `db:"total_minutes" json:"total_minutes"`
 }
 
 func (q *Queries) GetProjectsWithActivity(ctx context.Context, createdBy uuid.UUID) ([]GetProjectsWithActivityRow, error) {
@@ -261,6 +454,13 @@ func (q *Queries) GetProjectsWithActivity(ctx context.Context, createdBy uuid.UU
 			&i.IsDefault,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EstimatedHours,
+			&i.ParentID,
+			&i.ArchivedAt,
+			&i.DefaultType,
+			&i.DefaultTags,
+			&i.DefaultValueRating,
+			&i.DefaultImpactLevel,
 			&i.EntryCount,
 			&i.TotalMinutes,
 		); err != nil {
@@ -275,7 +475,7 @@ func (q *Queries) GetProjectsWithActivity(ctx context.Context, createdBy uuid.UU
 }
 
 const getUserDefaultProject = `-- name: GetUserDefaultProject :one
-SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at FROM projects
+SELECT id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level FROM projects
 WHERE created_by = $1 AND is_default = true
 LIMIT 1
 `
@@ -295,6 +495,13 @@ func (q *Queries) GetUserDefaultProject(ctx context.Context, createdBy uuid.UUID
 		&i.IsDefault,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EstimatedHours,
+		&i.ParentID,
+		&i.ArchivedAt,
+		&i.DefaultType,
+		&i.DefaultTags,
+		&i.DefaultValueRating,
+		&i.DefaultImpactLevel,
 	)
 	return i, err
 }
@@ -308,24 +515,60 @@ func (q *Queries) SetProjectAsDefault(ctx context.Context) error {
 	return err
 }
 
+const setProjectSubtreeArchived = `-- name: SetProjectSubtreeArchived :execrows
+WITH RECURSIVE subtree AS (
+    SELECT p.id FROM projects p
+    WHERE p.id = $1 AND p.created_by = $2
+    UNION
+    SELECT child.id FROM projects child
+    JOIN subtree s ON child.parent_id = s.id
+)
+UPDATE projects
+SET archived_at = $3, updated_at = NOW()
+WHERE id IN (SELECT id FROM subtree)
+`
+
+type SetProjectSubtreeArchivedParams struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	CreatedBy  uuid.UUID          `db:"created_by" json:"created_by"`
+	ArchivedAt pgtype.Timestamptz `db:"archived_at" json:"archived_at"`
+}
+
+// Archives, or restores with a NULL archived_at, a project and all its sub-projects
+func (q *Queries) SetProjectSubtreeArchived(ctx context.Context, arg SetProjectSubtreeArchivedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProjectSubtreeArchived, arg.ID, arg.CreatedBy, arg.ArchivedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects
 SET name = $2, description = $3, color = $4, status = $5,
-    start_date = $6, end_date = $7, is_default = $8, updated_at = NOW()
+    start_date = $6, end_date = $7, is_default = $8, estimated_hours = $10,
+    parent_id = $11, default_type = $12, default_tags = $13,
+    default_value_rating = $14, default_impact_level = $15, updated_at = NOW()
 WHERE id = $1 AND created_by = $9
-RETURNING id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at
+RETURNING id, name, description, color, status, start_date, end_date, created_by, is_default, created_at, updated_at, estimated_hours, parent_id, archived_at, default_type, default_tags, default_value_rating, default_impact_level
 `
 
 type UpdateProjectParams struct {
-	ID          uuid.UUID   `db:"id" json:"id"`
-	Name        string      `db:"name" json:"name"`
-	Description pgtype.Text `db:"description" json:"description"`
-	Color       pgtype.Text `db:"color" json:"color"`
-	Status      pgtype.Text `db:"status" json:"status"`
-	StartDate   pgtype.Date `db:"start_date" json:"start_date"`
-	EndDate     pgtype.Date `db:"end_date" json:"end_date"`
-	IsDefault   pgtype.Bool `db:"is_default" json:"is_default"`
-	CreatedBy   uuid.UUID   `db:"created_by" json:"created_by"`
+	ID                 uuid.UUID     `db:"id" json:"id"`
+	Name               string        `db:"name" json:"name"`
+	Description        pgtype.Text   `db:"description" json:"description"`
+	Color              pgtype.Text   `db:"color" json:"color"`
+	Status             pgtype.Text   `db:"status" json:"status"`
+	StartDate          pgtype.Date   `db:"start_date" json:"start_date"`
+	EndDate            pgtype.Date   `db:"end_date" json:"end_date"`
+	IsDefault          pgtype.Bool   `db:"is_default" json:"is_default"`
+	CreatedBy          uuid.UUID     `db:"created_by" json:"created_by"`
+	EstimatedHours     pgtype.Float8 `db:"estimated_hours" json:"estimated_hours"`
+	ParentID           pgtype.UUID   `db:"parent_id" json:"parent_id"`
+	DefaultType        pgtype.Text   `db:"default_type" json:"default_type"`
+	DefaultTags        []string      `db:"default_tags" json:"default_tags"`
+	DefaultValueRating pgtype.Text   `db:"default_value_rating" json:"default_value_rating"`
+	DefaultImpactLevel pgtype.Text   `db:"default_impact_level" json:"default_impact_level"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
@@ -339,6 +582,12 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		arg.EndDate,
 		arg.IsDefault,
 		arg.CreatedBy,
+		arg.EstimatedHours,
+		arg.ParentID,
+		arg.DefaultType,
+		arg.DefaultTags,
+		arg.DefaultValueRating,
+		arg.DefaultImpactLevel,
 	)
 	var i Project
 	err := row.Scan(
@@ -353,6 +602,13 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.IsDefault,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EstimatedHours,
+		&i.ParentID,
+		&i.ArchivedAt,
+		&i.DefaultType,
+		&i.DefaultTags,
+		&i.DefaultValueRating,
+		&i.DefaultImpactLevel,
 	)
 	return i, err
 }
@@ -13,55 +13,165 @@ import (
 
 type Querier interface {
 	AddTagToLogEntry(ctx context.Context, arg AddTagToLogEntryParams) error
+	AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (TeamMember, error)
 	ArchiveInsight(ctx context.Context, arg ArchiveInsightParams) error
 	CancelDeletionRequest(ctx context.Context, arg CancelDeletionRequestParams) error
 	CancelTask(ctx context.Context, id uuid.UUID) error
+	// Moves a due notification out of reach of other processors until the lease ends
+	ClaimEmailNotification(ctx context.Context, arg ClaimEmailNotificationParams) (EmailNotification, error)
+	// Moves a due delivery out of reach of other processors until the lease ends
+	ClaimWebhookDelivery(ctx context.Context, arg ClaimWebhookDeliveryParams) (WebhookDelivery, error)
 	CleanupExpiredDenylistedTokens(ctx context.Context) error
 	CleanupExpiredSessions(ctx context.Context) error
 	CleanupOldInsights(ctx context.Context, createdAt pgtype.Timestamptz) error
 	CleanupOldTasks(ctx context.Context, completedAt pgtype.Timestamptz) error
 	CleanupUnusedTags(ctx context.Context) error
 	CompleteTask(ctx context.Context, arg CompleteTaskParams) (Task, error)
+	CountCalendarFeedTokens(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountTeamOwners(ctx context.Context, teamID uuid.UUID) (int32, error)
+	CountWebhooks(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (LogEntryAttachment, error)
+	// EngLog Calendar Feed Queries
+	// Secret feed tokens and the log entries served as iCalendar events
+	CreateCalendarFeedToken(ctx context.Context, arg CreateCalendarFeedTokenParams) (CalendarFeedToken, error)
+	// EngLog CSV Import Profile Queries
+	// Column mappings users saved for importing the CSV exports of other time trackers
+	CreateCsvImportProfile(ctx context.Context, arg CreateCsvImportProfileParams) (CsvImportProfile, error)
+	// EngLog Email Notification Queries
+	// Users who opted in to notifications and the emails sent to them
+	CreateEmailNotification(ctx context.Context, arg CreateEmailNotificationParams) (EmailNotification, error)
+	// EngLog Goal Queries
+	// Personal goals, OKR key results and the log entry metrics they are measured with
+	CreateGoal(ctx context.Context, arg CreateGoalParams) (Goal, error)
 	// EngLog Insights Management Queries
 	// AI-generated insights and analytics
 	CreateInsight(ctx context.Context, arg CreateInsightParams) (GeneratedInsight, error)
 	// EngLog Log Entries Queries
 	// Activity tracking and log entry management
 	CreateLogEntry(ctx context.Context, arg CreateLogEntryParams) (LogEntry, error)
+	// EngLog Log Entry Import Queries
+	// Records of imported external items that keep repeated imports from duplicating entries
+	CreateLogEntryImport(ctx context.Context, arg CreateLogEntryImportParams) error
+	// EngLog Log Entry Content Queries
+	// Structured links and file attachments of log entries
+	CreateLogEntryLink(ctx context.Context, arg CreateLogEntryLinkParams) (LogEntryLink, error)
+	// EngLog Log Entry History Queries
+	// Append-only revisions and the soft-delete trash bin
+	CreateLogEntryRevision(ctx context.Context, arg CreateLogEntryRevisionParams) (LogEntryRevision, error)
+	CreateLogEntryTrash(ctx context.Context, arg CreateLogEntryTrashParams) (LogEntryTrash, error)
+	// EngLog Log Template Queries
+	// Recurring log entry templates and their per-occurrence state
+	CreateLogTemplate(ctx context.Context, arg CreateLogTemplateParams) (LogTemplate, error)
 	// EngLog Project Management Queries
 	// Project CRUD operations and statistics
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectActivityBudget(ctx context.Context, arg CreateProjectActivityBudgetParams) error
 	// EngLog Authentication Queries
 	// JWT token management and session handling
 	CreateRefreshTokenDenylist(ctx context.Context, arg CreateRefreshTokenDenylistParams) error
 	// EngLog Tags Management Queries
 	// Tag CRUD operations and usage statistics
 	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
+	// EngLog Tag Hierarchy Queries
+	// Tag aliases, parent/child relationships and merges
+	CreateTagAlias(ctx context.Context, arg CreateTagAliasParams) (TagAlias, error)
 	// EngLog Task Management Queries
 	// Background task processing and job queue
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	// EngLog Team Queries
+	// Shared team workspaces, their members and the projects shared with them
+	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
+	CreateTeamTag(ctx context.Context, arg CreateTeamTagParams) (Tag, error)
 	// EngLog User Management Queries
 	// User authentication and profile management
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserSession(ctx context.Context, arg CreateUserSessionParams) (UserSession, error)
+	// EngLog Webhook Queries
+	// Endpoints users registered for events and the log of deliveries made to them
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
 	DeactivateSession(ctx context.Context, id uuid.UUID) error
 	DeactivateUserSessions(ctx context.Context, userID uuid.UUID) error
+	DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) (int64, error)
+	DeleteCalendarFeedToken(ctx context.Context, arg DeleteCalendarFeedTokenParams) (int64, error)
+	DeleteCsvImportProfile(ctx context.Context, arg DeleteCsvImportProfileParams) (int64, error)
+	DeleteExpiredLogEntryRevisions(ctx context.Context, deletedAt pgtype.Timestamptz) error
+	DeleteGoal(ctx context.Context, arg DeleteGoalParams) (int64, error)
 	DeleteInsight(ctx context.Context, arg DeleteInsightParams) error
+	DeleteLinksForLogEntry(ctx context.Context, logEntryID uuid.UUID) error
 	DeleteLogEntry(ctx context.Context, arg DeleteLogEntryParams) (int64, error)
+	DeleteLogEntryRevisions(ctx context.Context, arg DeleteLogEntryRevisionsParams) error
+	DeleteLogEntryTrash(ctx context.Context, arg DeleteLogEntryTrashParams) (int64, error)
+	DeleteLogTemplate(ctx context.Context, arg DeleteLogTemplateParams) (int64, error)
+	DeleteLogTemplateOccurrence(ctx context.Context, arg DeleteLogTemplateOccurrenceParams) error
+	DeleteOldEmailNotifications(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error)
+	DeleteOldWebhookDeliveries(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error)
 	DeleteProject(ctx context.Context, arg DeleteProjectParams) error
-	DeleteTag(ctx context.Context, id uuid.UUID) error
+	DeleteProjectActivityBudgets(ctx context.Context, projectID uuid.UUID) error
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
+	DeleteTagAlias(ctx context.Context, arg DeleteTagAliasParams) (int64, error)
+	DeleteTeam(ctx context.Context, id uuid.UUID) error
+	DeleteTeamTag(ctx context.Context, arg DeleteTeamTagParams) (int64, error)
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	EmailNotificationExists(ctx context.Context, arg EmailNotificationExistsParams) (bool, error)
+	ExpireTaskFile(ctx context.Context, id uuid.UUID) error
+	// EngLog Account Archive Queries
+	// Full account exports and the lookups that keep account imports from duplicating data
+	ExportAccountLogEntries(ctx context.Context, userID uuid.UUID) ([]ExportAccountLogEntriesRow, error)
+	// EngLog Export Queries
+	// Data exports of log entries, projects and tag usage, read through server-side cursors
+	ExportLogEntries(ctx context.Context, arg ExportLogEntriesParams) ([]ExportLogEntriesRow, error)
+	// Projects the user owns with the time they logged on them
+	ExportProjects(ctx context.Context, createdBy uuid.UUID) ([]ExportProjectsRow, error)
+	// Every tag of the user with how often and how long it was used
+	ExportTagUsage(ctx context.Context, userID uuid.UUID) ([]ExportTagUsageRow, error)
 	FailTask(ctx context.Context, arg FailTaskParams) (Task, error)
+	GetAccountInsights(ctx context.Context, userID uuid.UUID) ([]GeneratedInsight, error)
+	GetAccountTagAliases(ctx context.Context, userID uuid.UUID) ([]TagAlias, error)
+	// The user's own tags plus the shared tags on their log entries
+	GetAccountTags(ctx context.Context, userID pgtype.UUID) ([]Tag, error)
+	GetActiveLogTemplates(ctx context.Context, endDate pgtype.Date) ([]LogTemplate, error)
 	GetActiveProjectsByUser(ctx context.Context, createdBy uuid.UUID) ([]Project, error)
 	GetActiveSessionsByUser(ctx context.Context, userID uuid.UUID) ([]UserSession, error)
 	GetActivityTypeDistribution(ctx context.Context, arg GetActivityTypeDistributionParams) ([]GetActivityTypeDistributionRow, error)
-	GetAllTags(ctx context.Context) ([]Tag, error)
+	// The user's own tags plus shared and team tags they have not copied yet
+	GetAllTags(ctx context.Context, userID pgtype.UUID) ([]Tag, error)
+	GetAttachmentByID(ctx context.Context, arg GetAttachmentByIDParams) (LogEntryAttachment, error)
+	GetAttachmentsForLogEntry(ctx context.Context, arg GetAttachmentsForLogEntryParams) ([]LogEntryAttachment, error)
+	// EngLog Report Queries
+	// Data gathered for generated reports such as the performance review
+	// Entries of a period with their project, in the order they were logged
+	GetBragDocEntries(ctx context.Context, arg GetBragDocEntriesParams) ([]GetBragDocEntriesRow, error)
+	// Entries of a window with their project, newest first, optionally limited to projects and types
+	GetCalendarFeedEntries(ctx context.Context, arg GetCalendarFeedEntriesParams) ([]GetCalendarFeedEntriesRow, error)
+	GetCalendarFeedTokenByHash(ctx context.Context, tokenHash string) (CalendarFeedToken, error)
+	GetCalendarFeedTokensByUser(ctx context.Context, userID uuid.UUID) ([]CalendarFeedToken, error)
 	GetComparisonStats(ctx context.Context, arg GetComparisonStatsParams) (GetComparisonStatsRow, error)
-	GetDailyActivityPattern(ctx context.Context, arg GetDailyActivityPatternParams) ([]DailyActivityPattern, error)
+	GetCsvImportProfileByID(ctx context.Context, arg GetCsvImportProfileByIDParams) (CsvImportProfile, error)
+	GetCsvImportProfilesByUser(ctx context.Context, userID uuid.UUID) ([]CsvImportProfile, error)
+	GetDailyActivityPattern(ctx context.Context, arg GetDailyActivityPatternParams) ([]GetDailyActivityPatternRow, error)
 	GetDailyProductivityStats(ctx context.Context, arg GetDailyProductivityStatsParams) ([]GetDailyProductivityStatsRow, error)
 	GetDenylistedTokensByUser(ctx context.Context, userID uuid.UUID) ([]RefreshTokenDenylist, error)
+	GetDueEmailNotifications(ctx context.Context, limit int32) ([]EmailNotification, error)
+	// Pending or retrying tasks of one type that are ready to run
+	GetDueTasksByType(ctx context.Context, arg GetDueTasksByTypeParams) ([]Task, error)
+	GetDueWebhookDeliveries(ctx context.Context, limit int32) ([]WebhookDelivery, error)
+	GetEmailNotificationsByUser(ctx context.Context, arg GetEmailNotificationsByUserParams) ([]EmailNotification, error)
+	// Completed tasks whose result file passed its expires_at and was not removed yet
+	GetExpiredTaskFiles(ctx context.Context, arg GetExpiredTaskFilesParams) ([]Task, error)
+	// Entries in start order; focus metrics are derived from the switches and gaps between them
+	GetFocusEntries(ctx context.Context, arg GetFocusEntriesParams) ([]GetFocusEntriesRow, error)
+	GetGoalByID(ctx context.Context, arg GetGoalByIDParams) (Goal, error)
+	GetGoalChildren(ctx context.Context, arg GetGoalChildrenParams) ([]Goal, error)
+	// Minutes and entries of the log entries matching a goal's filters, plus all
+	// minutes logged in the window so shares can be computed
+	GetGoalMetric(ctx context.Context, arg GetGoalMetricParams) (GetGoalMetricRow, error)
+	GetGoalsByUser(ctx context.Context, userID uuid.UUID) ([]Goal, error)
 	GetHighValueEntries(ctx context.Context, arg GetHighValueEntriesParams) ([]LogEntry, error)
 	GetImpactLevelDistribution(ctx context.Context, arg GetImpactLevelDistributionParams) ([]GetImpactLevelDistributionRow, error)
+	// External IDs of a source that the user already imported, out of the given ones
+	GetImportedExternalIDs(ctx context.Context, arg GetImportedExternalIDsParams) ([]string, error)
 	GetInsightByID(ctx context.Context, id uuid.UUID) (GeneratedInsight, error)
 	GetInsightGenerationMetrics(ctx context.Context, arg GetInsightGenerationMetricsParams) ([]GetInsightGenerationMetricsRow, error)
 	GetInsightStats(ctx context.Context, userID uuid.UUID) (GetInsightStatsRow, error)
@@ -70,36 +180,87 @@ type Querier interface {
 	GetInsightsByUser(ctx context.Context, arg GetInsightsByUserParams) ([]GeneratedInsight, error)
 	GetInsightsByUserAndType(ctx context.Context, arg GetInsightsByUserAndTypeParams) ([]GeneratedInsight, error)
 	GetLatestInsightByType(ctx context.Context, arg GetLatestInsightByTypeParams) (GeneratedInsight, error)
+	GetLatestLogEntryRevision(ctx context.Context, arg GetLatestLogEntryRevisionParams) (LogEntryRevision, error)
+	GetLinksForLogEntry(ctx context.Context, logEntryID uuid.UUID) ([]LogEntryLink, error)
 	GetLogEntriesByProject(ctx context.Context, projectID pgtype.UUID) ([]LogEntry, error)
 	GetLogEntriesByType(ctx context.Context, arg GetLogEntriesByTypeParams) ([]LogEntry, error)
 	GetLogEntriesByUser(ctx context.Context, arg GetLogEntriesByUserParams) ([]LogEntry, error)
 	GetLogEntriesByUserAndDateRange(ctx context.Context, arg GetLogEntriesByUserAndDateRangeParams) ([]LogEntry, error)
 	GetLogEntriesByUserAndProject(ctx context.Context, arg GetLogEntriesByUserAndProjectParams) ([]LogEntry, error)
-	GetLogEntriesForTag(ctx context.Context, tagID uuid.UUID) ([]LogEntry, error)
+	GetLogEntriesForTag(ctx context.Context, arg GetLogEntriesForTagParams) ([]LogEntry, error)
 	GetLogEntriesWithTags(ctx context.Context, arg GetLogEntriesWithTagsParams) ([]GetLogEntriesWithTagsRow, error)
 	GetLogEntryByID(ctx context.Context, id uuid.UUID) (LogEntry, error)
+	GetLogEntryRevision(ctx context.Context, arg GetLogEntryRevisionParams) (LogEntryRevision, error)
+	GetLogEntryRevisions(ctx context.Context, arg GetLogEntryRevisionsParams) ([]LogEntryRevision, error)
+	// Entries and minutes a user logged with a start time in [start_time, end_time)
+	GetLogEntryTotals(ctx context.Context, arg GetLogEntryTotalsParams) (GetLogEntryTotalsRow, error)
+	GetLogEntryTrash(ctx context.Context, arg GetLogEntryTrashParams) (LogEntryTrash, error)
+	GetLogTemplateByID(ctx context.Context, arg GetLogTemplateByIDParams) (LogTemplate, error)
+	GetLogTemplateOccurrence(ctx context.Context, arg GetLogTemplateOccurrenceParams) (LogTemplateOccurrence, error)
+	GetLogTemplateOccurrencesInRange(ctx context.Context, arg GetLogTemplateOccurrencesInRangeParams) ([]LogTemplateOccurrence, error)
+	GetLogTemplatesByUser(ctx context.Context, userID uuid.UUID) ([]LogTemplate, error)
 	GetMonthlyActivitySummary(ctx context.Context, arg GetMonthlyActivitySummaryParams) ([]GetMonthlyActivitySummaryRow, error)
+	// Users who turned a notification on in their preferences
+	GetNotificationRecipients(ctx context.Context, preference string) ([]GetNotificationRecipientsRow, error)
+	GetOrphanedAttachments(ctx context.Context, limit int32) ([]LogEntryAttachment, error)
 	GetPendingTasks(ctx context.Context, limit int32) ([]Task, error)
-	GetPopularTags(ctx context.Context, limit int32) ([]Tag, error)
+	GetPopularTags(ctx context.Context, arg GetPopularTagsParams) ([]Tag, error)
+	GetProcessingTasksByType(ctx context.Context, taskType string) ([]Task, error)
 	GetProductivityByDayOfWeek(ctx context.Context, arg GetProductivityByDayOfWeekParams) ([]GetProductivityByDayOfWeekRow, error)
 	GetProductivityByHour(ctx context.Context, arg GetProductivityByHourParams) ([]GetProductivityByHourRow, error)
+	GetProjectActivityBudgets(ctx context.Context, projectID uuid.UUID) ([]ProjectActivityBudget, error)
+	GetProjectActivityBudgetsByUser(ctx context.Context, createdBy uuid.UUID) ([]ProjectActivityBudget, error)
 	GetProjectByID(ctx context.Context, id uuid.UUID) (Project, error)
-	GetProjectPerformanceMetrics(ctx context.Context, projectOwner uuid.UUID) ([]ProjectPerformanceMetric, error)
+	// Minutes logged on a set of projects (a project and its sub-projects) per day
+	// and activity type, for budget burn-down
+	GetProjectDailyMinutes(ctx context.Context, arg GetProjectDailyMinutesParams) ([]GetProjectDailyMinutesRow, error)
+	GetProjectPerformanceMetrics(ctx context.Context, projectOwner uuid.UUID) ([]GetProjectPerformanceMetricsRow, error)
 	GetProjectStats(ctx context.Context, projectID pgtype.UUID) (GetProjectStatsRow, error)
-	GetProjectsByUser(ctx context.Context, createdBy uuid.UUID) ([]Project, error)
+	// The project followed by every sub-project below it, with the depth below the project
+	GetProjectSubtree(ctx context.Context, id uuid.UUID) ([]GetProjectSubtreeRow, error)
+	// The roles a user holds in the teams a project is shared with
+	GetProjectTeamRoles(ctx context.Context, arg GetProjectTeamRolesParams) ([]GetProjectTeamRolesRow, error)
+	// All-time entries and minutes per project, for sub-project rollups
+	GetProjectTotals(ctx context.Context, projectIds []uuid.UUID) ([]GetProjectTotalsRow, error)
+	GetProjectsByUser(ctx context.Context, arg GetProjectsByUserParams) ([]Project, error)
 	GetProjectsWithActivity(ctx context.Context, createdBy uuid.UUID) ([]GetProjectsWithActivityRow, error)
 	GetRecentLogEntries(ctx context.Context, arg GetRecentLogEntriesParams) ([]GetRecentLogEntriesRow, error)
 	GetRecentUsers(ctx context.Context, limit int32) ([]GetRecentUsersRow, error)
 	GetRecentlyUsedTags(ctx context.Context, arg GetRecentlyUsedTagsParams) ([]GetRecentlyUsedTagsRow, error)
+	// High-value or wide-impact entries of a period, most significant first
+	GetReportHighlights(ctx context.Context, arg GetReportHighlightsParams) ([]GetReportHighlightsRow, error)
+	// The active insight of a type that covers most of a period, newest first on ties
+	GetReportNarrative(ctx context.Context, arg GetReportNarrativeParams) (GeneratedInsight, error)
+	// EngLog Rollup Queries
+	// Analytics read from the per-user daily rollups; days are in the user's profile timezone
+	GetRollupActivityTypeDistribution(ctx context.Context, arg GetRollupActivityTypeDistributionParams) ([]GetRollupActivityTypeDistributionRow, error)
+	GetRollupMonthlySummary(ctx context.Context, arg GetRollupMonthlySummaryParams) ([]GetRollupMonthlySummaryRow, error)
+	GetRollupProductivityByDayOfWeek(ctx context.Context, arg GetRollupProductivityByDayOfWeekParams) ([]GetRollupProductivityByDayOfWeekRow, error)
+	// Same scores as GetUserProductivityTrend
+	GetRollupProductivityTrend(ctx context.Context, arg GetRollupProductivityTrendParams) ([]GetRollupProductivityTrendRow, error)
+	// Entries per value rating and impact level
+	GetRollupRatingTotals(ctx context.Context, arg GetRollupRatingTotalsParams) (GetRollupRatingTotalsRow, error)
+	GetRollupWeeklySummary(ctx context.Context, arg GetRollupWeeklySummaryParams) ([]GetRollupWeeklySummaryRow, error)
 	GetScheduledDeletions(ctx context.Context) ([]ScheduledDeletion, error)
 	GetSessionCount(ctx context.Context) (int64, error)
+	// A shared tag or a tag of one of the user's teams, preferring team tags
+	GetSharedTagByName(ctx context.Context, arg GetSharedTagByNameParams) (Tag, error)
+	// Digests whose AI report did not come in time
+	GetStaleWaitingEmailNotifications(ctx context.Context, arg GetStaleWaitingEmailNotificationsParams) ([]EmailNotification, error)
 	GetStuckTasks(ctx context.Context) ([]Task, error)
 	// EngLog Database Health Queries
 	// This file contains queries for health checking and system status
 	GetSystemHealth(ctx context.Context) (GetSystemHealthRow, error)
-	GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error)
-	GetTagByName(ctx context.Context, name string) (Tag, error)
-	GetTagUsageStats(ctx context.Context) (GetTagUsageStatsRow, error)
+	GetTagAliases(ctx context.Context, tagID uuid.UUID) ([]TagAlias, error)
+	GetTagByAlias(ctx context.Context, arg GetTagByAliasParams) (Tag, error)
+	// A tag of the user, a shared tag or a tag of one of the user's teams
+	GetTagByID(ctx context.Context, arg GetTagByIDParams) (Tag, error)
+	GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error)
+	// The tag itself followed by every tag below it
+	GetTagDescendantIDs(ctx context.Context, arg GetTagDescendantIDsParams) ([]uuid.UUID, error)
+	// Expands tag names or aliases into the names of those tags and all their descendants
+	GetTagNamesWithDescendants(ctx context.Context, arg GetTagNamesWithDescendantsParams) ([]string, error)
+	GetTagsByNameInsensitive(ctx context.Context, arg GetTagsByNameInsensitiveParams) ([]Tag, error)
 	GetTagsForLogEntry(ctx context.Context, logEntryID uuid.UUID) ([]Tag, error)
 	GetTaskByID(ctx context.Context, id uuid.UUID) (Task, error)
 	GetTaskPerformanceMetrics(ctx context.Context, completedAt pgtype.Timestamptz) ([]GetTaskPerformanceMetricsRow, error)
@@ -107,43 +268,102 @@ type Querier interface {
 	GetTaskStats(ctx context.Context, createdAt pgtype.Timestamptz) (GetTaskStatsRow, error)
 	GetTasksByType(ctx context.Context, taskType string) ([]Task, error)
 	GetTasksByUser(ctx context.Context, arg GetTasksByUserParams) ([]Task, error)
+	// Entries and minutes per member, project and activity type on the projects shared
+	// with a team. Only current members count; raw entries never leave this query.
+	GetTeamActivity(ctx context.Context, arg GetTeamActivityParams) ([]GetTeamActivityRow, error)
+	GetTeamByID(ctx context.Context, id uuid.UUID) (Team, error)
+	GetTeamMember(ctx context.Context, arg GetTeamMemberParams) (TeamMember, error)
+	// Members with the profile fields teams may see; preferences hold the member's team sharing setting
+	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]GetTeamMembersRow, error)
+	GetTeamProjects(ctx context.Context, teamID uuid.UUID) ([]Project, error)
+	GetTeamTags(ctx context.Context, teamID pgtype.UUID) ([]Tag, error)
+	// The teams a user belongs to, with the user's role and the team size
+	GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]GetTeamsByUserRow, error)
 	GetTopProjectsByTime(ctx context.Context, arg GetTopProjectsByTimeParams) ([]GetTopProjectsByTimeRow, error)
+	GetTrashedLogEntries(ctx context.Context, userID uuid.UUID) ([]GetTrashedLogEntriesRow, error)
 	GetUserActivitySummary(ctx context.Context, arg GetUserActivitySummaryParams) (GetUserActivitySummaryRow, error)
 	// EngLog Analytics Queries
 	// Advanced analytics and reporting
 	GetUserActivitySummaryView(ctx context.Context, userID uuid.UUID) (UserActivitySummary, error)
+	GetUserAttachmentUsage(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserCount(ctx context.Context) (int64, error)
 	GetUserDefaultProject(ctx context.Context, createdBy uuid.UUID) (Project, error)
 	GetUserDeletionRequests(ctx context.Context, userID uuid.UUID) ([]ScheduledDeletion, error)
-	GetUserProductivityTrend(ctx context.Context, arg GetUserProductivityTrendParams) ([]interface{}, error)
+	GetUserProductivityTrend(ctx context.Context, arg GetUserProductivityTrendParams) ([]GetUserProductivityTrendRow, error)
 	GetUserSession(ctx context.Context, id uuid.UUID) (UserSession, error)
 	GetUserSessionByToken(ctx context.Context, sessionTokenHash string) (UserSession, error)
-	GetUserTagUsage(ctx context.Context, userID uuid.UUID) ([]GetUserTagUsageRow, error)
+	GetUserTagUsage(ctx context.Context, userID pgtype.UUID) ([]GetUserTagUsageRow, error)
+	// Usage per tag counting each entry tagged with the tag or any of its descendants once
+	GetUserTagUsageWithDescendants(ctx context.Context, userID pgtype.UUID) ([]GetUserTagUsageWithDescendantsRow, error)
 	GetUserTaskHistory(ctx context.Context, arg GetUserTaskHistoryParams) ([]Task, error)
+	GetUserTasksByType(ctx context.Context, arg GetUserTasksByTypeParams) ([]Task, error)
 	GetValueRatingDistribution(ctx context.Context, arg GetValueRatingDistributionParams) ([]GetValueRatingDistributionRow, error)
+	GetWaitingEmailNotificationByTask(ctx context.Context, taskID pgtype.Text) (EmailNotification, error)
+	GetWebhookByID(ctx context.Context, arg GetWebhookByIDParams) (Webhook, error)
+	// Deliveries of a webhook, newest first, optionally only those with a status
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, arg GetWebhookDeliveryByIDParams) (WebhookDelivery, error)
+	GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]Webhook, error)
+	// Active webhooks of a user subscribed to an event type
+	GetWebhooksForEvent(ctx context.Context, arg GetWebhooksForEventParams) ([]Webhook, error)
 	GetWeeklyActivitySummary(ctx context.Context, arg GetWeeklyActivitySummaryParams) ([]GetWeeklyActivitySummaryRow, error)
 	IsRefreshTokenDenylisted(ctx context.Context, jti string) (bool, error)
+	// Serializes the uploads of a user until the transaction ends, so concurrent uploads cannot all fit in the same remaining quota
+	LockUserAttachmentQuota(ctx context.Context, userID uuid.UUID) error
+	// Whether the user already logged an entry with this title and time span
+	LogEntryExists(ctx context.Context, arg LogEntryExistsParams) (bool, error)
+	// Copies the source tag's entry associations onto the target; deleting the source removes the rest
+	MoveLogEntryTags(ctx context.Context, arg MoveLogEntryTagsParams) (int64, error)
+	MoveTagAliases(ctx context.Context, arg MoveTagAliasesParams) error
+	// Fills in the content of a waiting notification and makes it due
+	PrepareEmailNotification(ctx context.Context, arg PrepareEmailNotificationParams) (EmailNotification, error)
+	PurgeExpiredLogEntryTrash(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	RecordEmailNotificationAttempt(ctx context.Context, arg RecordEmailNotificationAttemptParams) (EmailNotification, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	// CONCURRENTLY keeps the view readable during the refresh; it relies on idx_user_activity_summary_user
 	RefreshUserActivitySummary(ctx context.Context) error
 	RemoveTagFromLogEntry(ctx context.Context, arg RemoveTagFromLogEntryParams) error
+	RemoveTeamMember(ctx context.Context, arg RemoveTeamMemberParams) (int64, error)
+	ReparentTagChildren(ctx context.Context, arg ReparentTagChildrenParams) error
+	ReplaceLogTemplateTag(ctx context.Context, arg ReplaceLogTemplateTagParams) error
 	ResetStuckTasks(ctx context.Context) error
+	RestoreLogEntry(ctx context.Context, arg RestoreLogEntryParams) (LogEntry, error)
 	ScheduleUserDeletion(ctx context.Context, arg ScheduleUserDeletionParams) (ScheduledDeletion, error)
 	SearchLogEntries(ctx context.Context, arg SearchLogEntriesParams) ([]LogEntry, error)
 	SearchTags(ctx context.Context, arg SearchTagsParams) ([]Tag, error)
+	SetEmailNotificationTask(ctx context.Context, arg SetEmailNotificationTaskParams) error
+	SetLogTemplateMaterializedUntil(ctx context.Context, arg SetLogTemplateMaterializedUntilParams) error
 	SetProjectAsDefault(ctx context.Context) error
+	// Archives, or restores with a NULL archived_at, a project and all its sub-projects
+	SetProjectSubtreeArchived(ctx context.Context, arg SetProjectSubtreeArchivedParams) (int64, error)
+	SetTagParent(ctx context.Context, arg SetTagParentParams) error
+	ShareProjectWithTeam(ctx context.Context, arg ShareProjectWithTeamParams) (int64, error)
 	StartTaskProcessing(ctx context.Context, id uuid.UUID) (Task, error)
 	SupersedeOldInsights(ctx context.Context, arg SupersedeOldInsightsParams) error
+	TouchCalendarFeedToken(ctx context.Context, id uuid.UUID) error
+	UnshareProjectFromTeam(ctx context.Context, arg UnshareProjectFromTeamParams) (int64, error)
+	UpdateCsvImportProfile(ctx context.Context, arg UpdateCsvImportProfileParams) (CsvImportProfile, error)
 	UpdateDeletionStatus(ctx context.Context, arg UpdateDeletionStatusParams) error
+	UpdateGoal(ctx context.Context, arg UpdateGoalParams) (Goal, error)
 	UpdateInsight(ctx context.Context, arg UpdateInsightParams) (GeneratedInsight, error)
 	UpdateLogEntry(ctx context.Context, arg UpdateLogEntryParams) (LogEntry, error)
+	UpdateLogEntryProject(ctx context.Context, arg UpdateLogEntryProjectParams) (int64, error)
+	UpdateLogTemplate(ctx context.Context, arg UpdateLogTemplateParams) (LogTemplate, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
 	UpdateSessionActivity(ctx context.Context, id uuid.UUID) error
 	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) (Task, error)
+	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
+	UpdateTeamMemberRole(ctx context.Context, arg UpdateTeamMemberRoleParams) (TeamMember, error)
+	UpdateTeamTag(ctx context.Context, arg UpdateTeamTagParams) (Tag, error)
 	UpdateUserLastLogin(ctx context.Context, id uuid.UUID) error
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpdateWebhookSecret(ctx context.Context, arg UpdateWebhookSecretParams) (Webhook, error)
+	UpsertLogTemplateOccurrence(ctx context.Context, arg UpsertLogTemplateOccurrenceParams) (LogTemplateOccurrence, error)
 }
 
 var _ Querier = (*Queries)(nil)
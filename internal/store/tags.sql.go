@@ -30,8 +30,10 @@ func (q *Queries) AddTagToLogEntry(ctx context.Context, arg AddTagToLogEntryPara
 
 const cleanupUnusedTags = `-- name: CleanupUnusedTags :exec
 DELETE FROM tags
-WHERE usage_count = 0
+WHERE user_id IS NOT NULL
+  AND usage_count = 0
   AND created_at < NOW() - INTERVAL '30 days'
+  AND NOT EXISTS (SELECT 1 FROM tags child WHERE child.parent_id = tags.id)
 `
 
 func (q *Queries) CleanupUnusedTags(ctx context.Context) error {
@@ -41,21 +43,64 @@ func (q *Queries) CleanupUnusedTags(ctx context.Context) error {
 
 const createTag = `-- name: CreateTag :one
 
-INSERT INTO tags (name, color, description)
-VALUES ($1, $2, $3)
-RETURNING id, name, color, description, usage_count, created_at
+INSERT INTO tags (name, color, description, user_id, parent_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, color, description, usage_count, created_at, user_id, parent_id, team_id
 `
 
 type CreateTagParams struct {
 	Name        string      `db:"name" json:"name"`
 	Color       pgtype.Text `db:"color" json:"color"`
 	Description pgtype.Text `db:"description" json:"description"`
+	UserID      pgtype.UUID `db:"user_id" json:"user_id"`
+	ParentID    pgtype.UUID `db:"parent_id" json:"parent_id"`
 }
 
 // EngLog Tags Management Queries
 // Tag CRUD operations and usage statistics
 func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
-	row := q.db.QueryRow(ctx, createTag, arg.Name, arg.Color, arg.Description)
+	row := q.db.QueryRow(ctx, createTag,
+		arg.Name,
+		arg.Color,
+		arg.Description,
+		arg.UserID,
+		arg.ParentID,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Color,
+		&i.Description,
+		&i.UsageCount,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
+	)
+	return i, err
+}
+
+const createTeamTag = `-- name: CreateTeamTag :one
+INSERT INTO tags (name, color, description, team_id)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, color, description, usage_count, created_at, user_id, parent_id, team_id
+`
+
+type CreateTeamTagParams struct {
+	Name        string      `db:"name" json:"name"`
+	Color       pgtype.Text `db:"color" json:"color"`
+	Description pgtype.Text `db:"description" json:"description"`
+	TeamID      pgtype.UUID `db:"team_id" json:"team_id"`
+}
+
+func (q *Queries) CreateTeamTag(ctx context.Context, arg CreateTeamTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, createTeamTag,
+		arg.Name,
+		arg.Color,
+		arg.Description,
+		arg.TeamID,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
@@ -64,27 +109,63 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 		&i.Description,
 		&i.UsageCount,
 		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
 	)
 	return i, err
 }
 
-const deleteTag = `-- name: DeleteTag :exec
+const deleteTag = `-- name: DeleteTag :execrows
 DELETE FROM tags
-WHERE id = $1
+WHERE id = $1 AND user_id = $2
 `
 
-func (q *Queries) DeleteTag(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteTag, id)
-	return err
+type DeleteTagParams struct {
+	ID     uuid.UUID   `db:"id" json:"id"`
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTag, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTeamTag = `-- name: DeleteTeamTag :execrows
+DELETE FROM tags
+WHERE id = $1 AND team_id = $2
+`
+
+type DeleteTeamTagParams struct {
+	ID     uuid.UUID   `db:"id" json:"id"`
+	TeamID pgtype.UUID `db:"team_id" json:"team_id"`
+}
+
+func (q *Queries) DeleteTeamTag(ctx context.Context, arg DeleteTeamTagParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTeamTag, arg.ID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAllTags = `-- name: GetAllTags :many
-SELECT id, name, color, description, usage_count, created_at FROM tags
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE user_id = $1
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1
+   )) AND NOT EXISTS (
+        SELECT 1 FROM tags own WHERE own.user_id = $1 AND own.name = tags.name
+   ))
 ORDER BY usage_count DESC, name ASC
 `
 
-func (q *Queries) GetAllTags(ctx context.Context) ([]Tag, error) {
-	rows, err := q.db.Query(ctx, getAllTags)
+// The user's own tags plus shared and team tags they have not copied yet
+func (q *Queries) GetAllTags(ctx context.Context, userID pgtype.UUID) ([]Tag, error) {
+	rows, err := q.db.Query(ctx, getAllTags, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +180,9 @@ func (q *Queries) GetAllTags(ctx context.Context) ([]Tag, error) {
 			&i.Description,
 			&i.UsageCount,
 			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -113,12 +197,17 @@ func (q *Queries) GetAllTags(ctx context.Context) ([]Tag, error) {
 const getLogEntriesForTag = `-- name: GetLogEntriesForTag :many
 SELECT le.id, le.user_id, le.project_id, le.title, le.description, le.type, le.start_time, le.end_time, le.duration_minutes, le.value_rating, le.impact_level, le.created_at, le.updated_at FROM log_entries le
 JOIN log_entry_tags let ON le.id = let.log_entry_id
-WHERE let.tag_id = $1
+WHERE let.tag_id = $1 AND le.user_id = $2
 ORDER BY le.start_time DESC
 `
 
-func (q *Queries) GetLogEntriesForTag(ctx context.Context, tagID uuid.UUID) ([]LogEntry, error) {
-	rows, err := q.db.Query(ctx, getLogEntriesForTag, tagID)
+type GetLogEntriesForTagParams struct {
+	TagID  uuid.UUID `db:"tag_id" json:"tag_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) GetLogEntriesForTag(ctx context.Context, arg GetLogEntriesForTagParams) ([]LogEntry, error) {
+	rows, err := q.db.Query(ctx, getLogEntriesForTag, arg.TagID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -152,14 +241,19 @@ func (q *Queries) GetLogEntriesForTag(ctx context.Context, tagID uuid.UUID) ([]L
 }
 
 const getPopularTags = `-- name: GetPopularTags :many
-SELECT id, name, color, description, usage_count, created_at FROM tags
-WHERE usage_count > 0
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE user_id = $1 AND usage_count > 0
 ORDER BY usage_count DESC, name ASC
-LIMIT $1
+LIMIT $2
 `
 
-func (q *Queries) GetPopularTags(ctx context.Context, limit int32) ([]Tag, error) {
-	rows, err := q.db.Query(ctx, getPopularTags, limit)
+type GetPopularTagsParams struct {
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+	Limit  int32       `db:"limit" json:"limit"`
+}
+
+func (q *Queries) GetPopularTags(ctx context.Context, arg GetPopularTagsParams) ([]Tag, error) {
+	rows, err := q.db.Query(ctx, getPopularTags, arg.UserID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -174,6 +268,9 @@ func (q *Queries) GetPopularTags(ctx context.Context, limit int32) ([]Tag, error
 			&i.Description,
 			&i.UsageCount,
 			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -186,13 +283,13 @@ func (q *Queries) GetPopularTags(ctx context.Context, limit int32) ([]Tag, error
 }
 
 const getRecentlyUsedTags = `-- name: GetRecentlyUsedTags :many
-SELECT DISTINCT t.id, t.name, t.color, t.description, t.usage_count, t.created_at, MAX(le.created_at) as last_used
+SELECT DISTINCT t.id, t.name, t.color, t.description, t.usage_count, t.created_at, t.user_id, t.parent_id, t.team_id, MAX(le.created_at) as last_used
 FROM tags t
 JOIN log_entry_tags let ON t.id = let.tag_id
 JOIN log_entries le ON let.log_entry_id = le.id
 WHERE le.user_id = $1
   AND le.created_at >= $2
-GROUP BY t.id, t.name, t.color, t.description, t.usage_count, t.created_at
+GROUP BY t.id, t.name, t.color, t.description, t.usage_count, t.created_at, t.user_id, t.parent_id, t.team_id
 ORDER BY last_used DESC
 `
 
@@ -208,6 +305,9 @@ type GetRecentlyUsedTagsRow struct {
 	Description pgtype.Text        `db:"description" json:"description"`
 	UsageCount  pgtype.Int4        `db:"usage_count" json:"usage_count"`
 	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UserID      pgtype.UUID        `db:"user_id" json:"user_id"`
+	ParentID    pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	TeamID      pgtype.UUID        `db:"team_id" json:"team_id"`
 	LastUsed    interface{}        `db:"last_used" json:"last_used"`
 }
 
@@ -227,6 +327,9 @@ func (q *Queries) GetRecentlyUsedTags(ctx context.Context, arg GetRecentlyUsedTa
 			&i.Description,
 			&i.UsageCount,
 			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
 			&i.LastUsed,
 		); err != nil {
 			return nil, err
@@ -239,13 +342,24 @@ func (q *Queries) GetRecentlyUsedTags(ctx context.Context, arg GetRecentlyUsedTa
 	return items, nil
 }
 
-const getTagByID = `-- name: GetTagByID :one
-SELECT id, name, color, description, usage_count, created_at FROM tags
-WHERE id = $1
+const getSharedTagByName = `-- name: GetSharedTagByName :one
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE user_id IS NULL AND name = $1
+  AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2
+  ))
+ORDER BY team_id IS NULL, created_at
+LIMIT 1
 `
 
-func (q *Queries) GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error) {
-	row := q.db.QueryRow(ctx, getTagByID, id)
+type GetSharedTagByNameParams struct {
+	Name   string    `db:"name" json:"name"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// A shared tag or a tag of one of the user's teams, preferring team tags
+func (q *Queries) GetSharedTagByName(ctx context.Context, arg GetSharedTagByNameParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, getSharedTagByName, arg.Name, arg.UserID)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
@@ -254,17 +368,30 @@ func (q *Queries) GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error) {
 		&i.Description,
 		&i.UsageCount,
 		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
 	)
 	return i, err
 }
 
-const getTagByName = `-- name: GetTagByName :one
-SELECT id, name, color, description, usage_count, created_at FROM tags
-WHERE name = $1
+const getTagByID = `-- name: GetTagByID :one
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE id = $1
+  AND (user_id = $2
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2
+   ))))
 `
 
-func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
-	row := q.db.QueryRow(ctx, getTagByName, name)
+type GetTagByIDParams struct {
+	ID     uuid.UUID   `db:"id" json:"id"`
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+// A tag of the user, a shared tag or a tag of one of the user's teams
+func (q *Queries) GetTagByID(ctx context.Context, arg GetTagByIDParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByID, arg.ID, arg.UserID)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
@@ -273,40 +400,42 @@ func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
 		&i.Description,
 		&i.UsageCount,
 		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
 	)
 	return i, err
 }
 
-const getTagUsageStats = `-- name: GetTagUsageStats :one
-SELECT
-    COUNT(*) as total_tags,
-    COUNT(CASE WHEN usage_count > 0 THEN 1 END) as used_tags,
-    AVG(usage_count) as avg_usage,
-    MAX(usage_count) as max_usage
-FROM tags
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE user_id = $1 AND name = $2
 `
 
-type GetTagUsageStatsRow struct {
-	TotalTags int64       `db:"total_tags" json:"total_tags"`
-	UsedTags  int64       `db:"used_tags" json:"used_tags"`
-	AvgUsage  float64     `db:"avg_usage" json:"avg_usage"`
-	MaxUsage  interface{} `db:"max_usage" json:"max_usage"`
+type GetTagByNameParams struct {
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+	Name   string      `db:"name" json:"name"`
 }
 
-func (q *Queries) GetTagUsageStats(ctx context.Context) (GetTagUsageStatsRow, error) {
-	row := q.db.QueryRow(ctx, getTagUsageStats)
-	var i GetTagUsageStatsRow
+func (q *Queries) GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByName, arg.UserID, arg.Name)
+	var i Tag
 	err := row.Scan(
-		&i.TotalTags,
-		&i.UsedTags,
-		&i.AvgUsage,
-		&i.MaxUsage,
+		&i.ID,
+		&i.Name,
+		&i.Color,
+		&i.Description,
+		&i.UsageCount,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getTagsForLogEntry = `-- name: GetTagsForLogEntry :many
-SELECT t.id, t.name, t.color, t.description, t.usage_count, t.created_at FROM tags t
+SELECT t.id, t.name, t.color, t.description, t.usage_count, t.created_at, t.user_id, t.parent_id, t.team_id FROM tags t
 JOIN log_entry_tags let ON t.id = let.tag_id
 WHERE let.log_entry_id = $1
 ORDER BY t.name
@@ -328,6 +457,45 @@ func (q *Queries) GetTagsForLogEntry(ctx context.Context, logEntryID uuid.UUID)
 			&i.Description,
 			&i.UsageCount,
 			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamTags = `-- name: GetTeamTags :many
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
+WHERE team_id = $1
+ORDER BY name ASC
+`
+
+func (q *Queries) GetTeamTags(ctx context.Context, teamID pgtype.UUID) ([]Tag, error) {
+	rows, err := q.db.Query(ctx, getTeamTags, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tag{}
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Color,
+			&i.Description,
+			&i.UsageCount,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -341,25 +509,25 @@ func (q *Queries) GetTagsForLogEntry(ctx context.Context, logEntryID uuid.UUID)
 
 const getUserTagUsage = `-- name: GetUserTagUsage :many
 SELECT
-    t.id, t.name, t.color, t.description,
-    COUNT(let.log_entry_id) as user_usage_count
+    t.id, t.name, t.color, t.description, t.created_at, t.parent_id,
+    t.usage_count as user_usage_count
 FROM tags t
-LEFT JOIN log_entry_tags let ON t.id = let.tag_id
-LEFT JOIN log_entries le ON let.log_entry_id = le.id AND le.user_id = $1
-GROUP BY t.id, t.name, t.color, t.description
-HAVING COUNT(let.log_entry_id) > 0
+WHERE t.user_id = $1
+  AND t.usage_count > 0
 ORDER BY user_usage_count DESC, t.name ASC
 `
 
 type GetUserTagUsageRow struct {
-	ID             uuid.UUID   `db:"id" json:"id"`
-	Name           string      `db:"name" json:"name"`
-	Color          pgtype.Text `db:"color" json:"color"`
-	Description    pgtype.Text `db:"description" json:"description"`
-	UserUsageCount int64       `db:"user_usage_count" json:"user_usage_count"`
+	ID             uuid.UUID          `db:"id" json:"id"`
+	Name           string             `db:"name" json:"name"`
+	Color          pgtype.Text        `db:"color" json:"color"`
+	Description    pgtype.Text        `db:"description" json:"description"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ParentID       pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	UserUsageCount pgtype.Int4        `db:"user_usage_count" json:"user_usage_count"`
 }
 
-func (q *Queries) GetUserTagUsage(ctx context.Context, userID uuid.UUID) ([]GetUserTagUsageRow, error) {
+func (q *Queries) GetUserTagUsage(ctx context.Context, userID pgtype.UUID) ([]GetUserTagUsageRow, error) {
 	rows, err := q.db.Query(ctx, getUserTagUsage, userID)
 	if err != nil {
 		return nil, err
@@ -373,6 +541,8 @@ func (q *Queries) GetUserTagUsage(ctx context.Context, userID uuid.UUID) ([]GetU
 			&i.Name,
 			&i.Color,
 			&i.Description,
+			&i.CreatedAt,
+			&i.ParentID,
 			&i.UserUsageCount,
 		); err != nil {
 			return nil, err
@@ -401,8 +571,14 @@ func (q *Queries) RemoveTagFromLogEntry(ctx context.Context, arg RemoveTagFromLo
 }
 
 const searchTags = `-- name: SearchTags :many
-SELECT id, name, color, description, usage_count, created_at FROM tags
+SELECT id, name, color, description, usage_count, created_at, user_id, parent_id, team_id FROM tags
 WHERE name ILIKE '%' || $1 || '%'
+  AND (user_id = $3
+   OR (user_id IS NULL AND (team_id IS NULL OR team_id IN (
+        SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $3
+   )) AND NOT EXISTS (
+        SELECT 1 FROM tags own WHERE own.user_id = $3 AND own.name = tags.name
+   )))
 ORDER BY usage_count DESC, name ASC
 LIMIT $2
 `
@@ -410,10 +586,11 @@ LIMIT $2
 type SearchTagsParams struct {
 	Column1 pgtype.Text `db:"column_1" json:"column_1"`
 	Limit   int32       `db:"limit" json:"limit"`
+	UserID  pgtype.UUID `db:"user_id" json:"user_id"`
 }
 
 func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]Tag, error) {
-	rows, err := q.db.Query(ctx, searchTags, arg.Column1, arg.Limit)
+	rows, err := q.db.Query(ctx, searchTags, arg.Column1, arg.Limit, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -428,6 +605,9 @@ func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]Tag,
 			&i.Description,
 			&i.UsageCount,
 			&i.CreatedAt,
+			&i.UserID,
+			&i.ParentID,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -441,9 +621,9 @@ func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]Tag,
 
 const updateTag = `-- name: UpdateTag :one
 UPDATE tags
-SET name = $2, color = $3, description = $4
-WHERE id = $1
-RETURNING id, name, color, description, usage_count, created_at
+SET name = $2, color = $3, description = $4, parent_id = $6
+WHERE id = $1 AND user_id = $5
+RETURNING id, name, color, description, usage_count, created_at, user_id, parent_id, team_id
 `
 
 type UpdateTagParams struct {
@@ -451,6 +631,8 @@ type UpdateTagParams struct {
 	Name        string      `db:"name" json:"name"`
 	Color       pgtype.Text `db:"color" json:"color"`
 	Description pgtype.Text `db:"description" json:"description"`
+	UserID      pgtype.UUID `db:"user_id" json:"user_id"`
+	ParentID    pgtype.UUID `db:"parent_id" json:"parent_id"`
 }
 
 func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
@@ -459,6 +641,46 @@ func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, erro
 		arg.Name,
 		arg.Color,
 		arg.Description,
+		arg.UserID,
+		arg.ParentID,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Color,
+		&i.Description,
+		&i.UsageCount,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
+	)
+	return i, err
+}
+
+const updateTeamTag = `-- name: UpdateTeamTag :one
+UPDATE tags
+SET name = $3, color = $4, description = $5
+WHERE id = $1 AND team_id = $2
+RETURNING id, name, color, description, usage_count, created_at, user_id, parent_id, team_id
+`
+
+type UpdateTeamTagParams struct {
+	ID          uuid.UUID   `db:"id" json:"id"`
+	TeamID      pgtype.UUID `db:"team_id" json:"team_id"`
+	Name        string      `db:"name" json:"name"`
+	Color       pgtype.Text `db:"color" json:"color"`
+	Description pgtype.Text `db:"description" json:"description"`
+}
+
+func (q *Queries) UpdateTeamTag(ctx context.Context, arg UpdateTeamTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, updateTeamTag,
+		arg.ID,
+		arg.TeamID,
+		arg.Name,
+		arg.Color,
+		arg.Description,
 	)
 	var i Tag
 	err := row.Scan(
@@ -468,6 +690,9 @@ func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, erro
 		&i.Description,
 		&i.UsageCount,
 		&i.CreatedAt,
+		&i.UserID,
+		&i.ParentID,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -123,6 +123,18 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 	return i, err
 }
 
+const expireTaskFile = `-- name: ExpireTaskFile :exec
+UPDATE tasks
+SET result = (result - 'storage_key') || '{"expired": true}'::jsonb,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) ExpireTaskFile(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, expireTaskFile, id)
+	return err
+}
+
 const failTask = `-- name: FailTask :one
 UPDATE tasks
 SET status = CASE
@@ -169,6 +181,111 @@ func (q *Queries) FailTask(ctx context.Context, arg FailTaskParams) (Task, error
 	return i, err
 }
 
+const getDueTasksByType = `-- name: GetDueTasksByType :many
+SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
+WHERE task_type = $1
+  AND status IN ('pending', 'retrying')
+  AND scheduled_at <= NOW()
+ORDER BY priority ASC, scheduled_at ASC
+LIMIT $2
+`
+
+type GetDueTasksByTypeParams struct {
+	TaskType string `db:"task_type" json:"task_type"`
+	Limit    int32  `db:"limit" json:"limit"`
+}
+
+// Pending or retrying tasks of one type that are ready to run
+func (q *Queries) GetDueTasksByType(ctx context.Context, arg GetDueTasksByTypeParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, getDueTasksByType, arg.TaskType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.UserID,
+			&i.Payload,
+			&i.Status,
+			&i.Priority,
+			&i.MaxRetries,
+			&i.RetryCount,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Result,
+			&i.ErrorMessage,
+			&i.ProcessingDurationMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredTaskFiles = `-- name: GetExpiredTaskFiles :many
+SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
+WHERE task_type = $1
+  AND status = 'completed'
+  AND result->>'storage_key' IS NOT NULL
+  AND (result->>'expires_at')::timestamptz < NOW()
+ORDER BY completed_at ASC
+LIMIT $2
+`
+
+type GetExpiredTaskFilesParams struct {
+	TaskType string `db:"task_type" json:"task_type"`
+	Limit    int32  `db:"limit" json:"limit"`
+}
+
+// Completed tasks whose result file passed its expires_at and was not removed yet
+func (q *Queries) GetExpiredTaskFiles(ctx context.Context, arg GetExpiredTaskFilesParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, getExpiredTaskFiles, arg.TaskType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.UserID,
+			&i.Payload,
+			&i.Status,
+			&i.Priority,
+			&i.MaxRetries,
+			&i.RetryCount,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Result,
+			&i.ErrorMessage,
+			&i.ProcessingDurationMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPendingTasks = `-- name: GetPendingTasks :many
 SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
 WHERE status IN ('pending', 'retrying')
@@ -214,6 +331,49 @@ func (q *Queries) GetPendingTasks(ctx context.Context, limit int32) ([]Task, err
 	return items, nil
 }
 
+const getProcessingTasksByType = `-- name: GetProcessingTasksByType :many
+SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
+WHERE task_type = $1 AND status = 'processing'
+ORDER BY started_at ASC
+`
+
+func (q *Queries) GetProcessingTasksByType(ctx context.Context, taskType string) ([]Task, error) {
+	rows, err := q.db.Query(ctx, getProcessingTasksByType, taskType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.UserID,
+			&i.Payload,
+			&i.Status,
+			&i.Priority,
+			&i.MaxRetries,
+			&i.RetryCount,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Result,
+			&i.ErrorMessage,
+			&i.ProcessingDurationMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStuckTasks = `-- name: GetStuckTasks :many
 SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
 WHERE status = 'processing'
@@ -558,6 +718,56 @@ func (q *Queries) GetUserTaskHistory(ctx context.Context, arg GetUserTaskHistory
 	return items, nil
 }
 
+const getUserTasksByType = `-- name: GetUserTasksByType :many
+SELECT id, task_type, user_id, payload, status, priority, max_retries, retry_count, scheduled_at, started_at, completed_at, result, error_message, processing_duration_ms, created_at, updated_at FROM tasks
+WHERE user_id = $1 AND task_type = $2
+ORDER BY created_at DESC
+LIMIT $3
+`
+
+type GetUserTasksByTypeParams struct {
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	TaskType string      `db:"task_type" json:"task_type"`
+	Limit    int32       `db:"limit" json:"limit"`
+}
+
+func (q *Queries) GetUserTasksByType(ctx context.Context, arg GetUserTasksByTypeParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, getUserTasksByType, arg.UserID, arg.TaskType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.UserID,
+			&i.Payload,
+			&i.Status,
+			&i.Priority,
+			&i.MaxRetries,
+			&i.RetryCount,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Result,
+			&i.ErrorMessage,
+			&i.ProcessingDurationMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const resetStuckTasks = `-- name: ResetStuckTasks :exec
 UPDATE tasks
 SET status = 'pending', started_at = NULL, updated_at = NOW()
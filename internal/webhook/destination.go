@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrForbiddenDestination is returned for webhook URLs that resolve to loopback,
+// private, link-local or otherwise internal addresses
+var ErrForbiddenDestination = errors.New("webhook URL must not point to a private or internal address")
+
+// forbiddenPrefixes are the ranges not covered by the netip.Addr predicates that
+// still reach the local network or nothing at all
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which embeds IPv4 addresses
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+}
+
+// AllowedAddr reports whether deliveries may be sent to addr: a public unicast
+// address, not a loopback, private, link-local, unspecified or multicast one
+func AllowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves the host of a webhook URL and returns ErrForbiddenDestination
+// when any of its addresses is not allowed
+func CheckHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !AllowedAddr(addr) {
+			return ErrForbiddenDestination
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("webhook host %s could not be resolved: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !AllowedAddr(addr) {
+			return ErrForbiddenDestination
+		}
+	}
+	return nil
+}
+
+// DialControl is a net.Dialer Control function that refuses connections to
+// addresses AllowedAddr rejects. It runs after name resolution, so a host that
+// resolves to another address at delivery time than when it was checked (DNS
+// rebinding) is still refused.
+func DialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %s: %w", address, err)
+	}
+	if !AllowedAddr(addrPort.Addr()) {
+		return ErrForbiddenDestination
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedAddr(t *testing.T) {
+	for addr, allowed := range map[string]bool{
+		"93.184.216.34":          true,
+		"2606:2800:220:1::1":     true,
+		"127.0.0.1":              false,
+		"::1":                    false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"fe80::1":                false,
+		"fd00::1":                false,
+		"0.0.0.0":                false,
+		"::":                     false,
+		"224.0.0.1":              false,
+		"ff02::1":                false,
+		"100.64.0.1":             false,
+		"255.255.255.255":        false,
+		"::ffff:127.0.0.1":       false,
+		"::ffff:169.254.169.254": false,
+		"64:ff9b::a9fe:a9fe":     false,
+		"::ffff:93.184.216.34":   true,
+		"2001:4860:4860::8888":   true,
+		"198.18.0.1":             false,
+		"192.0.0.8":              false,
+	} {
+		assert.Equal(t, allowed, AllowedAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, CheckHost(ctx, net.DefaultResolver, "93.184.216.34"))
+	assert.ErrorIs(t, CheckHost(ctx, net.DefaultResolver, "169.254.169.254"), ErrForbiddenDestination)
+	assert.ErrorIs(t, CheckHost(ctx, net.DefaultResolver, "::1"), ErrForbiddenDestination)
+	assert.ErrorIs(t, CheckHost(ctx, net.DefaultResolver, "localhost"), ErrForbiddenDestination)
+	assert.Error(t, CheckHost(ctx, net.DefaultResolver, "does-not-exist.invalid"))
+}
+
+func TestDialControl(t *testing.T) {
+	assert.NoError(t, DialControl("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, DialControl("tcp4", "127.0.0.1:8080", nil), ErrForbiddenDestination)
+	assert.ErrorIs(t, DialControl("tcp6", "[fe80::1%eth0]:80", nil), ErrForbiddenDestination)
+	assert.Error(t, DialControl("tcp", "not an address", nil))
+
+	// A dialer with the control function refuses internal addresses whatever name
+	// resolved to them
+	dialer := &net.Dialer{Control: DialControl}
+	_, err := dialer.DialContext(context.Background(), "tcp", "localhost:1")
+	assert.ErrorIs(t, err, ErrForbiddenDestination)
+}
@@ -0,0 +1,97 @@
+// Package webhook signs outbound webhook deliveries, verifies their signatures and
+// checks that deliveries only go to public addresses.
+//
+// A delivery carries the header
+//
+//	X-EngLog-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256>
+//
+// where the HMAC is computed with the webhook secret over "<t>.<request body>".
+// Receivers recompute it and reject signatures that differ or are too old, which
+// also keeps captured requests from being replayed later.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a webhook delivery
+const (
+	SignatureHeader = "X-EngLog-Signature"
+	EventHeader     = "X-EngLog-Event"
+	DeliveryHeader  = "X-EngLog-Delivery"
+)
+
+// DefaultTolerance is how old a signature Verify accepts by default
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when no signature of the header matches the body
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	// ErrExpiredSignature is returned when the signature timestamp is outside the tolerance
+	ErrExpiredSignature = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// Sign returns the signature header value of a body sent at the given time
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + computeMAC(secret, t, body)
+}
+
+// Verify checks a signature header against the body. Signatures more than
+// tolerance away from now are rejected; a non-positive tolerance uses
+// DefaultTolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("malformed webhook signature header")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed webhook signature timestamp: %w", err)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	expected := []byte(computeMAC(secret, timestamp, body))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// computeMAC returns the hex HMAC-SHA256 of "<timestamp>.<body>"
+func computeMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
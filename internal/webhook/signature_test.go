@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"type":"log.created"}`)
+	sentAt := time.Unix(1710000000, 0)
+
+	header := Sign(secret, sentAt, body)
+	assert.True(t, strings.HasPrefix(header, "t=1710000000,v1="))
+	assert.Len(t, strings.TrimPrefix(header, "t=1710000000,v1="), 64)
+
+	require.NoError(t, Verify(secret, header, body, 0, sentAt.Add(time.Minute)))
+
+	assert.ErrorIs(t, Verify("other", header, body, 0, sentAt), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(secret, header, []byte(`{"type":"log.deleted"}`), 0, sentAt), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(secret, header, body, 0, sentAt.Add(10*time.Minute)), ErrExpiredSignature)
+	assert.NoError(t, Verify(secret, header, body, time.Hour, sentAt.Add(10*time.Minute)))
+}
+
+func TestVerify_MultipleSignatures(t *testing.T) {
+	body := []byte("{}")
+	sentAt := time.Now()
+
+	// A header may carry several signatures; any of them matching is enough
+	old := Sign("old", sentAt, body)
+	current := Sign("current", sentAt, body)
+	header := old + "," + current[strings.Index(current, "v1="):]
+
+	assert.NoError(t, Verify("old", header, body, 0, sentAt))
+	assert.NoError(t, Verify("current", header, body, 0, sentAt))
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	for _, header := range []string{"", "v1=abc", "t=1710000000", "t=soon,v1=abc"} {
+		assert.Error(t, Verify("secret", header, []byte("{}"), 0, time.Unix(1710000000, 0)), header)
+	}
+}
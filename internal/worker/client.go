@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,8 @@ import (
 	"github.com/garnizeh/englog/internal/ai"
 	"github.com/garnizeh/englog/internal/config"
 	"github.com/garnizeh/englog/internal/logging"
+	"github.com/garnizeh/englog/internal/models"
+	"github.com/garnizeh/englog/internal/reports"
 	workerpb "github.com/garnizeh/englog/proto/worker"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -251,6 +254,8 @@ func (c *Client) doRegisterWorker(ctx context.Context) error {
 		Capabilities: []workerpb.WorkerCapability{
 			workerpb.WorkerCapability_CAPABILITY_AI_INSIGHTS,
 			workerpb.WorkerCapability_CAPABILITY_WEEKLY_REPORTS,
+			workerpb.WorkerCapability_CAPABILITY_ENTRY_SUGGESTIONS,
+			workerpb.WorkerCapability_CAPABILITY_DATA_EXPORTS,
 		},
 		Version: c.config.Worker.Version,
 		Metadata: map[string]string{
@@ -500,6 +505,8 @@ func (c *Client) doStreamTasks(ctx context.Context) error {
 		Capabilities: []workerpb.WorkerCapability{
 			workerpb.WorkerCapability_CAPABILITY_AI_INSIGHTS,
 			workerpb.WorkerCapability_CAPABILITY_WEEKLY_REPORTS,
+			workerpb.WorkerCapability_CAPABILITY_ENTRY_SUGGESTIONS,
+			workerpb.WorkerCapability_CAPABILITY_DATA_EXPORTS,
 		},
 	}
 
@@ -596,6 +603,15 @@ func (c *Client) processTask(ctx context.Context, task *workerpb.TaskRequest) er
 		result, processErr = c.processInsightTaskWithRetry(ctx, task)
 	case workerpb.TaskType_TASK_TYPE_WEEKLY_REPORT:
 		result, processErr = c.processWeeklyReportTaskWithRetry(ctx, task)
+	case workerpb.TaskType_TASK_TYPE_ENTRY_SUGGESTION:
+		// The API waits synchronously and falls back on failure, so no retries here
+		result, processErr = c.processEntrySuggestionTask(ctx, task)
+	case workerpb.TaskType_TASK_TYPE_ACCOMPLISHMENT_SUMMARY:
+		// The API waits synchronously and renders without summaries on failure, so no retries here
+		result, processErr = c.processAccomplishmentSummaryTask(ctx, task)
+	case workerpb.TaskType_TASK_TYPE_DATA_EXPORT:
+		// Rendering is deterministic; the API retries failed reports itself
+		result, processErr = c.processDataExportTask(ctx, task)
 	default:
 		processErr = fmt.Errorf("unsupported task type: %s", task.TaskType)
 	}
@@ -715,6 +731,74 @@ func (c *Client) processWeeklyReportTask(ctx context.Context, task *workerpb.Tas
 	return string(result), nil
 }
 
+func (c *Client) processEntrySuggestionTask(ctx context.Context, task *workerpb.TaskRequest) (string, error) {
+	var suggestionReq ai.EntrySuggestionRequest
+	if err := json.Unmarshal([]byte(task.Payload), &suggestionReq); err != nil {
+		return "", fmt.Errorf("failed to unmarshal entry suggestion request: %w", err)
+	}
+
+	suggestion, err := c.aiService.SuggestEntry(ctx, &suggestionReq)
+	if err != nil {
+		return "", fmt.Errorf("entry suggestion failed: %w", err)
+	}
+
+	result, err := json.Marshal(suggestion)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal suggestion result: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (c *Client) processAccomplishmentSummaryTask(ctx context.Context, task *workerpb.TaskRequest) (string, error) {
+	var summaryReq ai.AccomplishmentSummaryRequest
+	if err := json.Unmarshal([]byte(task.Payload), &summaryReq); err != nil {
+		return "", fmt.Errorf("failed to unmarshal accomplishment summary request: %w", err)
+	}
+
+	summary, err := c.aiService.SummarizeAccomplishments(ctx, &summaryReq)
+	if err != nil {
+		return "", fmt.Errorf("accomplishment summary failed: %w", err)
+	}
+
+	result, err := json.Marshal(summary)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal accomplishment summary result: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (c *Client) processDataExportTask(ctx context.Context, task *workerpb.TaskRequest) (string, error) {
+	var render models.ReportRenderTask
+	if err := json.Unmarshal([]byte(task.Payload), &render); err != nil {
+		return "", fmt.Errorf("failed to unmarshal report render task: %w", err)
+	}
+
+	c.updateTaskProgress(ctx, task.TaskId, 10, "Rendering report")
+
+	var buf bytes.Buffer
+	switch {
+	case render.ReportType == models.ReportPerformanceReview && render.PerformanceReview != nil:
+		if err := reports.RenderPerformanceReview(&buf, render.PerformanceReview); err != nil {
+			return "", fmt.Errorf("failed to render performance review: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unsupported report type: %s", render.ReportType)
+	}
+
+	result, err := json.Marshal(models.ReportRenderResult{
+		FileName:    render.FileName,
+		ContentType: reports.ContentType,
+		Data:        buf.Bytes(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report render result: %w", err)
+	}
+
+	return string(result), nil
+}
+
 func (c *Client) updateTaskProgress(ctx context.Context, taskID string, progress int32, message string) {
 	err := RetryOperation(ctx, c.logger, "update_task_progress", c.retryConfig, func() error {
 		return c.doUpdateTaskProgress(ctx, taskID, progress, message)